@@ -0,0 +1,263 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// activityDays is how far back the Activity tab's feed reaches.
+const activityDays = 7
+
+// activityEntry is one datapoint in the Activity tab's feed.
+type activityEntry struct {
+	slug string
+	dp   Datapoint
+}
+
+// activityLoadedMsg carries a fetched Activity feed, newest first.
+type activityLoadedMsg struct {
+	entries []activityEntry
+	err     error
+}
+
+// loadActivityCmd fetches the datapoints of the last activityDays days across
+// all goals, in one request.
+func loadActivityCmd(ctx context.Context, client Client, now time.Time) tea.Cmd {
+	return func() tea.Msg {
+		entries, err := fetchActivity(ctx, client, now.AddDate(0, 0, -activityDays))
+		return activityLoadedMsg{entries: entries, err: err}
+	}
+}
+
+// fetchActivity fetches the datapoints added or changed since a time across
+// the goals the global --filter keeps, as a feed.
+func fetchActivity(ctx context.Context, client Client, since time.Time) ([]activityEntry, error) {
+	goals, err := client.FetchDatapointsSince(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	return activityFeed(keepGoals(goals, goalFilter)), nil
+}
+
+// activityFeed flattens goals' datapoints into one feed, newest first. Ties
+// are broken by slug so the order is stable.
+func activityFeed(goals []Goal) []activityEntry {
+	var entries []activityEntry
+	for _, g := range goals {
+		for _, dp := range g.Datapoints {
+			entries = append(entries, activityEntry{slug: g.Slug, dp: dp})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].dp.Timestamp != entries[j].dp.Timestamp {
+			return entries[i].dp.Timestamp > entries[j].dp.Timestamp
+		}
+		return entries[i].slug < entries[j].slug
+	})
+	return entries
+}
+
+// activityModel is the Activity tab: a feed of recent datapoints across goals,
+// optionally narrowed to one goal and one day.
+type activityModel struct {
+	ctx     context.Context
+	client  Client
+	entries []activityEntry // the whole feed, newest first
+	loading bool            // a fetch is in flight
+	loaded  bool            // entries holds a fetched feed
+	err     error           // the last fetch failed
+	goal    string          // only this goal's datapoints; "" for all goals
+	day     string          // only this YYYYMMDD day's datapoints; "" for all days
+	offset  int             // first visible row of the filtered feed
+	width   int
+	height  int
+}
+
+// refresh starts a fetch of the feed, unless one is already in flight.
+func (m *activityModel) refresh() tea.Cmd {
+	if m.loading {
+		return nil
+	}
+	m.loading = true
+	return loadActivityCmd(m.ctx, m.client, time.Now())
+}
+
+// visible returns the feed after the goal and day filters.
+func (m activityModel) visible() []activityEntry {
+	var out []activityEntry
+	for _, e := range m.entries {
+		if (m.goal == "" || e.slug == m.goal) && (m.day == "" || e.dp.Daystamp == m.day) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// goalChoices returns the slugs in the feed, sorted, for the goal filter to
+// cycle through.
+func (m activityModel) goalChoices() []string {
+	var slugs []string
+	for _, e := range m.entries {
+		if !slices.Contains(slugs, e.slug) {
+			slugs = append(slugs, e.slug)
+		}
+	}
+	sort.Strings(slugs)
+	return slugs
+}
+
+// dayChoices returns the days in the feed, newest first, for the day filter
+// to cycle through.
+func (m activityModel) dayChoices() []string {
+	var days []string
+	for _, e := range m.entries {
+		if e.dp.Daystamp != "" && !slices.Contains(days, e.dp.Daystamp) {
+			days = append(days, e.dp.Daystamp)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(days)))
+	return days
+}
+
+// nextChoice returns the choice after current, going from "" (all) through
+// choices and back to "".
+func nextChoice(choices []string, current string) string {
+	i := slices.Index(choices, current)
+	if i+1 < len(choices) {
+		return choices[i+1]
+	}
+	return ""
+}
+
+// rows is how many feed rows fit under the header and above the help line.
+func (m activityModel) rows() int {
+	return max(1, m.height-3)
+}
+
+// scroll moves the view by delta rows, clamped to the filtered feed.
+func (m *activityModel) scroll(delta int) {
+	m.offset = max(0, min(m.offset+delta, len(m.visible())-m.rows()))
+}
+
+func (m activityModel) Update(msg tea.Msg) (activityModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.scroll(0)
+
+	case activityLoadedMsg:
+		m.loading = false
+		// A failed refetch keeps showing the last good feed with the error.
+		m.err = msg.err
+		if msg.err == nil {
+			m.entries = msg.entries
+			m.loaded = true
+			m.scroll(0)
+		}
+
+	case tea.MouseMsg:
+		switch msg.Button {
+		case tea.MouseButtonWheelUp:
+			m.scroll(-3)
+		case tea.MouseButtonWheelDown:
+			m.scroll(3)
+		}
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "up", "k":
+			m.scroll(-1)
+		case "down", "j":
+			m.scroll(1)
+		case "pgup":
+			m.scroll(-m.rows())
+		case "pgdown", "d":
+			m.scroll(m.rows())
+		case "home", "g":
+			m.offset = 0
+		case "end", "G":
+			m.scroll(len(m.entries))
+		case "f":
+			m.goal = nextChoice(m.goalChoices(), m.goal)
+			m.offset = 0
+		case "D":
+			m.day = nextChoice(m.dayChoices(), m.day)
+			m.offset = 0
+		case "c":
+			m.goal, m.day, m.offset = "", "", 0
+		case "r":
+			return m, m.refresh()
+		}
+	}
+	return m, nil
+}
+
+func (m activityModel) View() string {
+	goal, day := "all goals", "all days"
+	if m.goal != "" {
+		goal = m.goal
+	}
+	if m.day != "" {
+		day = datapointDate(Datapoint{Daystamp: m.day})
+	}
+	header := lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("Last %d days", activityDays)) +
+		fmt.Sprintf("  %s, %s", goal, day)
+	help := lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(
+		"Scroll: ↑ ↓ PgUp PgDn  |  Goal: f  |  Day: D  |  Clear: c  |  Refresh: r  |  Goals: Esc  |  Quit: q")
+
+	var body string
+	visible := m.visible()
+	switch {
+	case m.err != nil && !m.loaded:
+		body = UrgencyOverdue.TextStyle().Render(fmt.Sprintf("Failed to load activity: %s", redactError(m.err)))
+	case !m.loaded:
+		body = "Loading activity..."
+	case len(visible) == 0:
+		body = "No datapoints."
+	default:
+		body = m.renderRows(visible[m.offset:min(len(visible), m.offset+m.rows())])
+	}
+	if m.err != nil && m.loaded {
+		help = UrgencyOverdue.TextStyle().Render(fmt.Sprintf("Refresh failed: %s", redactError(m.err))) + "  " + help
+	}
+
+	// Pad the body so the help line stays at the bottom.
+	if pad := m.rows() - lipgloss.Height(body); pad > 0 {
+		body += strings.Repeat("\n", pad)
+	}
+	return header + "\n" + body + "\n" + help
+}
+
+// renderRows draws feed rows in aligned date / goal / value / comment columns.
+func (m activityModel) renderRows(entries []activityEntry) string {
+	dps := make([]Datapoint, len(entries))
+	slugWidth := 0
+	for i, e := range entries {
+		dps[i] = e.dp
+		slugWidth = max(slugWidth, len(e.slug))
+	}
+	dates, values, valueWidth := formatDatapointRows(dps)
+
+	lines := make([]string, len(entries))
+	for i, e := range entries {
+		line := fmt.Sprintf("%s  %-*s  %*s", dates[i], slugWidth, e.slug, valueWidth, values[i])
+		if e.dp.Comment != "" {
+			line += "  " + e.dp.Comment
+		}
+		if m.width > 0 && lipgloss.Width(line) > m.width {
+			line = truncateString(line, m.width)
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestActivityFeed(t *testing.T) {
+	entries := activityFeed([]Goal{
+		{Slug: "write", Datapoints: []Datapoint{{Timestamp: 100, Daystamp: "20261014"}, {Timestamp: 300, Daystamp: "20261015"}}},
+		{Slug: "read", Datapoints: []Datapoint{{Timestamp: 300, Daystamp: "20261015"}, {Timestamp: 200, Daystamp: "20261014"}}},
+	})
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.slug)
+	}
+	if strings.Join(got, ",") != "read,write,read,write" {
+		t.Errorf("feed order = %v, want newest first with ties by slug", got)
+	}
+
+	m := activityModel{entries: entries, loaded: true, height: 20}
+	if choices := m.goalChoices(); strings.Join(choices, ",") != "read,write" {
+		t.Errorf("goalChoices = %v", choices)
+	}
+	if choices := m.dayChoices(); strings.Join(choices, ",") != "20261015,20261014" {
+		t.Errorf("dayChoices = %v", choices)
+	}
+
+	// f and D cycle through each choice and back to all.
+	press := func(key string) {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+	}
+	press("f")
+	press("D")
+	if m.goal != "read" || m.day != "20261015" || len(m.visible()) != 1 {
+		t.Errorf("goal %q, day %q, visible %d", m.goal, m.day, len(m.visible()))
+	}
+	if view := m.View(); !strings.Contains(view, "read, 2026-10-15") {
+		t.Errorf("view lacks the filters:\n%s", view)
+	}
+	press("f")
+	press("f")
+	if m.goal != "" {
+		t.Errorf("goal filter didn't wrap to all, got %q", m.goal)
+	}
+	press("c")
+	if m.day != "" || len(m.visible()) != 4 {
+		t.Errorf("c left day %q, visible %d", m.day, len(m.visible()))
+	}
+}
+
+func TestActivityTab(t *testing.T) {
+	var since time.Time
+	client := &FakeClient{
+		FetchDatapointsSinceFunc: func(s time.Time) ([]Goal, error) {
+			since = s
+			return []Goal{{Slug: "read", Datapoints: []Datapoint{{Timestamp: 1, Daystamp: "20261015", Value: 12, Comment: "chapter 3"}}}}, nil
+		},
+	}
+	m := model{state: "app", appModel: appModel{
+		ctx:    context.Background(),
+		client: client,
+		config: &Config{Username: "u"},
+		goals:  []Goal{{Slug: "read"}},
+		width:  80,
+		height: 20,
+	}}
+
+	tm, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("3")})
+	m = mustModel(t, tm)
+	if m.appModel.tab != tabActivity || cmd == nil {
+		t.Fatalf("tab = %v, cmd = %v", m.appModel.tab, cmd)
+	}
+	if view := m.View(); !strings.Contains(view, "Loading activity...") {
+		t.Errorf("view before the feed loads:\n%s", view)
+	}
+	m = mustModel(t, mustTeaModel(m.Update(cmd())))
+	if time.Since(since) < 7*24*time.Hour-time.Minute {
+		t.Errorf("fetched since %v, want a week back", since)
+	}
+	if view := m.View(); !strings.Contains(view, "2026-10-15  read  12  chapter 3") {
+		t.Errorf("view lacks the datapoint:\n%s", view)
+	}
+
+	// Switching away and back doesn't refetch.
+	m = mustModel(t, mustTeaModel(m.Update(tea.KeyMsg{Type: tea.KeyEsc})))
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("3")}); cmd != nil {
+		t.Error("reopening the Activity tab should keep its feed")
+	}
+
+	// A failed refresh keeps the feed and shows the error.
+	client.FetchDatapointsSinceFunc = func(time.Time) ([]Goal, error) { return nil, errors.New("boom") }
+	m.appModel.tab = tabActivity
+	tm, cmd = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	m = mustModel(t, mustTeaModel(tm.Update(cmd())))
+	if view := m.View(); !strings.Contains(view, "chapter 3") || !strings.Contains(view, "Refresh failed: boom") {
+		t.Errorf("view after a failed refresh:\n%s", view)
+	}
+}
@@ -1,35 +1,83 @@
 package main
 
 import (
+	"bufio"
+	"cmp"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 )
 
-const addUsage = `Usage: buzz add [--requestid=<id>] [--daystamp=<date>] <goalslug> <value> [comment]
-       echo "<value>" | buzz add [--requestid=<id>] [--daystamp=<date>] <goalslug> [comment]
+const addUsage = `Usage: buzz add [--requestid=<id>|--no-requestid] [--date=<date>|--daystamp=<date>|--timestamp=<epoch>] [--lb|--kg] [--dry-run] <goalslug> <value> [comment]
+       echo "<value>" | buzz add [--requestid=<id>|--no-requestid] [--date=<date>] <goalslug> [comment]
+       echo "<comment>" | buzz add [flags] <goalslug> <value> --comment-stdin
+       buzz add --bulk -|--file=<path>   (see buzz add --bulk --help)
 
 Note: Flags must come BEFORE positional arguments.
       Example: buzz add --daystamp=20240115 goalslug value comment
-      The --daystamp flag accepts dates in YYYYMMDD format.`
+      The --daystamp flag accepts dates in YYYYMMDD format, --date accepts
+      YYYY-MM-DD, and --timestamp a Unix time in seconds. Like the TUI's
+      datapoint form, --date and --timestamp refuse dates more than a day
+      ahead. Only one of the three may be given.
+      Without --requestid, the request ID is derived from the date, goal, and
+      value, so re-running the same add on the same day never logs twice. Use
+      --no-requestid to deliberately log the same value again.
+      --comment-stdin reads the comment from the first line of stdin, so it
+      needs no shell quoting; it may also follow the positional arguments.
+      <value> may be a number, a time like 1:30 (converted to the goal's
+      units, so 90 for a goal in minutes), or an expression like 3*12+5. Run from a terminal without a value, buzz add
+      prompts for one.
+      --lb and --kg give the value in pounds or kilograms, converted to the
+      units of a weight (fatloser or gainer) goal.
+      --dry-run checks the datapoint and shows roughly what it would do to the
+      goal's safety buffer and value, as buzz simulate does, without adding it.`
 
 // addRequest is a fully-parsed, validated `buzz add` invocation, ready to send.
 type addRequest struct {
 	goalSlug  string
-	value     string // already converted to a decimal-hours string when a time
+	value     string // a time is converted to the goal's units (see timeValue)
 	comment   string
-	daystamp  string // YYYYMMDD, or "" to use the current timestamp
+	daystamp  string // YYYYMMDD, or "" to use the timestamp
+	timestamp string // Unix seconds from --timestamp, or "" for the current time
 	requestid string
+	// noRequestID opts this add out of the default derived request ID.
+	noRequestID bool
+	// distinctComment means the comment tells adds apart (see
+	// withDefaultRequestID): it was piped with --comment-stdin, or came from
+	// a line of a bulk add.
+	distinctComment bool
+	// gunits are the goal's units when they've been fetched (see
+	// completeAddRequest), for validating time values and the success message.
+	gunits string
+	// timeValue is the value as typed when it's a time like "1:30", which
+	// completeAddRequest converts to the goal's units. Until then value holds
+	// it in decimal hours.
+	timeValue string
+	// weightUnits is "lb" or "kg" when the value was given with --lb/--kg,
+	// to be converted to the goal's units.
+	weightUnits string
+	// promptValue means no value was given and stdin is a terminal, so the
+	// value is to be asked for (see promptAddValue).
+	promptValue bool
+	// dryRun shows the add's simulated effect instead of submitting it.
+	dryRun bool
 }
 
 // handleAddCommand adds a datapoint to a goal without opening the TUI.
 func handleAddCommand() {
+	if isBulkAdd(os.Args[2:]) {
+		handleAddBulkCommand()
+		return
+	}
 	req, code, done := parseAddArgs(os.Args[2:], readValueFromStdin, os.Stdout, os.Stderr)
 	if done {
 		os.Exit(code)
@@ -39,9 +87,30 @@ func handleAddCommand() {
 	if !ok {
 		os.Exit(1)
 	}
+	req, code, done = completeAddRequest(req, client, os.Stdin, os.Stdout, os.Stderr)
+	if done {
+		os.Exit(code)
+	}
+	useConfiguredHooks(os.Stderr)
+	req, code, done = applyAddHook(req, activeHooks, client, os.Stderr)
+	if done {
+		os.Exit(code)
+	}
+	// loadClient just loaded the config successfully.
+	if config, err := LoadConfig(); err == nil {
+		req = withDefaultRequestID(req, config, time.Now())
+	}
 
-	code = runAddCommand(req, client, os.Stdout, os.Stderr)
+	if req.dryRun {
+		os.Exit(runAddDryRun(context.Background(), req, client, time.Now(), os.Stdout, os.Stderr))
+	}
+	req, goal := fetchAddGoal(context.Background(), req, client)
+	celebration := addCelebration(goal, req, time.Now())
+	code, added := submitAdd(req, client, time.Now(), os.Stdout, os.Stderr)
 	if code == 0 {
+		if added {
+			celebrate(os.Stdout, celebration)
+		}
 		fmt.Print(getUpdateMessage())
 	}
 	os.Exit(code)
@@ -58,7 +127,14 @@ func parseAddArgs(args []string, readStdin func() (string, error), stdout, stder
 	// both --help and parse errors.
 	addFlags.SetOutput(io.Discard)
 	requestid := addFlags.String("requestid", "", "Request ID for idempotency")
+	noRequestID := addFlags.Bool("no-requestid", false, "Don't derive a request ID")
+	commentStdin := addFlags.Bool("comment-stdin", false, "Read the comment from stdin")
 	daystamp := addFlags.String("daystamp", "", "Date for the datapoint in YYYYMMDD format")
+	date := addFlags.String("date", "", "Date for the datapoint in YYYY-MM-DD format")
+	timestamp := addFlags.String("timestamp", "", "Time of the datapoint as a Unix timestamp")
+	lb := addFlags.Bool("lb", false, "The value is in pounds")
+	kg := addFlags.Bool("kg", false, "The value is in kilograms")
+	dryRun := dryRunFlag(addFlags, "Show what the add would do without adding it")
 	if err := addFlags.Parse(args); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
 			fmt.Fprintln(stdout, addUsage)
@@ -69,13 +145,49 @@ func parseAddArgs(args []string, readStdin func() (string, error), stdout, stder
 		return addRequest{}, 1, true
 	}
 
+	if *noRequestID && *requestid != "" {
+		fmt.Fprintln(stderr, "Error: --requestid and --no-requestid can't be used together")
+		return addRequest{}, 1, true
+	}
+
+	dates := 0
+	for _, f := range []string{*daystamp, *date, *timestamp} {
+		if f != "" {
+			dates++
+		}
+	}
+	if dates > 1 {
+		fmt.Fprintln(stderr, "Error: Only one of --date, --daystamp, and --timestamp can be used")
+		return addRequest{}, 1, true
+	}
+
+	if *lb && *kg {
+		fmt.Fprintln(stderr, "Error: --lb and --kg can't be used together")
+		return addRequest{}, 1, true
+	}
+	var weightUnits string
+	switch {
+	case *lb:
+		weightUnits = "lb"
+	case *kg:
+		weightUnits = "kg"
+	}
+
 	positional := addFlags.Args()
 
+	// --comment-stdin takes no value, so unlike the other flags it is also
+	// recognized after the positional args, as in
+	// `git log -1 --format=%s | buzz add commits 1 --comment-stdin`.
+	if i := slices.Index(positional, "--comment-stdin"); i >= 0 {
+		*commentStdin = true
+		positional = slices.Delete(slices.Clone(positional), i, i+1)
+	}
+
 	// Detect if known flags appear after positional arguments and warn the user.
 	if misplacedFlag := detectMisplacedFlag(positional); misplacedFlag != "" {
 		fmt.Fprintf(stderr, "Warning: Flag '%s' appears after positional arguments and will be treated as part of the comment.\n", misplacedFlag)
 		fmt.Fprintf(stderr, "Flags must come BEFORE positional arguments to be recognized.\n")
-		fmt.Fprintf(stderr, "Correct usage: buzz add [--requestid=ID] [--daystamp=DATE] goalslug value comment\n")
+		fmt.Fprintf(stderr, "Correct usage: buzz add [--requestid=ID] [--date=DATE] goalslug value comment\n")
 		fmt.Fprintln(stderr, "")
 	}
 
@@ -88,11 +200,30 @@ func parseAddArgs(args []string, readStdin func() (string, error), stdout, stder
 	goalSlug := positional[0]
 	var value string
 	var commentStartIndex int // index where the optional comment starts
+	var stdinComment string
+	var promptValue bool
 
-	// Try to read a value from stdin first (for piped input).
-	stdinValue, err := readStdin()
-	if err == nil && stdinValue != "" {
-		// Reject the ambiguous case where a value is piped AND a positional
+	if *commentStdin {
+		// stdin carries the comment, so the value must be positional.
+		switch {
+		case len(positional) < 2:
+			fmt.Fprintln(stderr, "Error: --comment-stdin needs the value as an argument")
+			fmt.Fprintln(stderr, addUsage)
+			return addRequest{}, 1, true
+		case len(positional) > 2:
+			fmt.Fprintln(stderr, "Error: Provide the comment either via --comment-stdin or as arguments, not both")
+			fmt.Fprintln(stderr, addUsage)
+			return addRequest{}, 1, true
+		}
+		comment, err := readStdin()
+		if err != nil || comment == "" {
+			fmt.Fprintln(stderr, "Error: --comment-stdin: no comment piped on stdin")
+			return addRequest{}, 1, true
+		}
+		value = positional[1]
+		stdinComment = comment
+	} else if stdinValue, stdinErr := readStdin(); stdinErr == nil && stdinValue != "" {
+		// A value piped on stdin (checked before positional args). Reject the ambiguous case where a value is piped AND a positional
 		// value is supplied — silently taking stdin could submit a different
 		// datapoint than the user intended for a write operation.
 		if len(positional) >= 2 {
@@ -105,6 +236,11 @@ func parseAddArgs(args []string, readStdin func() (string, error), stdout, stder
 	} else if len(positional) >= 2 {
 		value = positional[1]
 		commentStartIndex = 2
+	} else if errors.Is(stdinErr, errStdinNotPiped) {
+		// Run from a terminal with just a goal: the caller prompts for the
+		// value once it has a client to look up the last one.
+		promptValue = true
+		commentStartIndex = 1
 	} else {
 		fmt.Fprintln(stderr, "Error: Missing required value argument")
 		fmt.Fprintln(stderr, addUsage)
@@ -113,7 +249,9 @@ func parseAddArgs(args []string, readStdin func() (string, error), stdout, stder
 
 	// Optional comment — default when not provided.
 	comment := "Added via buzz"
-	if len(positional) >= commentStartIndex+1 {
+	if stdinComment != "" {
+		comment = stdinComment
+	} else if len(positional) >= commentStartIndex+1 {
 		comment = strings.Join(positional[commentStartIndex:], " ")
 	}
 
@@ -127,55 +265,446 @@ func parseAddArgs(args []string, readStdin func() (string, error), stdout, stder
 		daystampForAPI = *daystamp
 	}
 
-	// Convert a time-format value (e.g. "1:30:00") to decimal hours.
-	if isTimeFormat(value) {
-		decimalValue, ok := timeToDecimalHours(value)
-		if !ok {
-			fmt.Fprintf(stderr, "Error: Invalid time format: %s\n", value)
+	// --date is validated as the TUI's datapoint form validates its date,
+	// then sent as the daystamp it names.
+	if *date != "" {
+		if msg := validateDatapointDate(*date); msg != "" {
+			fmt.Fprintf(stderr, "Error: --date: %s\n", msg)
 			return addRequest{}, 1, true
 		}
-		value = fmt.Sprintf("%.6g", decimalValue)
+		d, _ := time.ParseInLocation("2006-01-02", *date, time.Local)
+		daystampForAPI = d.Format("20060102")
 	}
 
-	// Validate the value is a number.
-	if _, err := strconv.ParseFloat(value, 64); err != nil {
-		fmt.Fprintf(stderr, "Error: Value must be a valid number, got: %s\n", value)
-		return addRequest{}, 1, true
+	if *timestamp != "" {
+		secs, err := strconv.ParseInt(*timestamp, 10, 64)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: Invalid --timestamp: %s (expected a Unix time in seconds)\n", *timestamp)
+			return addRequest{}, 1, true
+		}
+		if msg := validateDatapointDate(time.Unix(secs, 0).Format("2006-01-02")); msg != "" {
+			fmt.Fprintf(stderr, "Error: --timestamp: %s\n", msg)
+			return addRequest{}, 1, true
+		}
+	}
+
+	var timeValue string
+	if !promptValue {
+		if isTimeFormat(normalizeDecimal(value)) {
+			timeValue = value
+		}
+		parsed, err := parseAddValue(value, weightUnits)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %s\n", err)
+			return addRequest{}, 1, true
+		}
+		value = parsed
 	}
 
 	return addRequest{
-		goalSlug:  goalSlug,
-		value:     value,
-		comment:   comment,
-		daystamp:  daystampForAPI,
-		requestid: *requestid,
+		goalSlug:        goalSlug,
+		value:           value,
+		comment:         comment,
+		daystamp:        daystampForAPI,
+		timestamp:       *timestamp,
+		requestid:       *requestid,
+		noRequestID:     *noRequestID,
+		distinctComment: *commentStdin,
+		timeValue:       timeValue,
+		weightUnits:     weightUnits,
+		promptValue:     promptValue,
+		dryRun:          *dryRun,
 	}, 0, false
 }
 
+// parseAddValue converts a datapoint value as typed — a number, a time like
+// "1:30:00", or an arithmetic expression like "3*12+5" — to the decimal
+// string sent to Beeminder. gunits are the goal's units, or "" when unknown:
+// a time is converted to them (see timeToUnits), and rejected when they're
+// known not to measure time.
+//
+// A decimal comma is accepted under a locale that writes one (see
+// normalizeDecimal), so "3,5" is 3.5.
+func parseAddValue(s, gunits string) (string, error) {
+	s = normalizeDecimal(s)
+	if isTimeFormat(s) {
+		if gunits != "" && !isTimeUnits(gunits) {
+			return "", fmt.Errorf("%s looks like a time, but the goal is measured in %s", s, gunits)
+		}
+		decimalValue, ok := timeToUnits(s, gunits)
+		if !ok {
+			return "", fmt.Errorf("invalid time format: %s", s)
+		}
+		return fmt.Sprintf("%.6g", decimalValue), nil
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return s, nil
+	}
+	v, err := evalValueExpr(s)
+	if err != nil {
+		return "", fmt.Errorf("value must be a valid number, time, or expression, got: %s", s)
+	}
+	return strconv.FormatFloat(v, 'g', 12, 64), nil
+}
+
+// completeAddRequest finishes a request that needs the goal itself: asking
+// for the value (promptValue), converting a time to the goal's units (so
+// "1:30" is 90 for a goal in minutes), and converting a --lb/--kg weight to
+// them. Requests needing none of these are returned unchanged without an API
+// call. It returns the completed request, a process exit code, and done=true
+// when the caller should stop.
+func completeAddRequest(req addRequest, client Client, stdin io.Reader, stdout, stderr io.Writer) (addRequest, int, bool) {
+	if !req.promptValue && req.timeValue == "" && req.weightUnits == "" {
+		return req, 0, false
+	}
+
+	// Look the goal up first so a mistyped slug fails before anything is typed.
+	goal, err := client.FetchGoal(context.Background(), req.goalSlug)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %s\n", redactError(err))
+		return addRequest{}, 1, true
+	}
+	req.gunits = goal.Gunits
+
+	if req.timeValue != "" {
+		if req.value, err = parseAddValue(req.timeValue, goal.Gunits); err != nil {
+			fmt.Fprintf(stderr, "Error: %s\n", err)
+			return addRequest{}, 1, true
+		}
+	}
+
+	goalWeightUnits := weightUnitsOf(goal.Gunits)
+	if req.weightUnits != "" {
+		if goal.GoalType != "fatloser" && goal.GoalType != "gainer" {
+			fmt.Fprintf(stderr, "Error: --%s only applies to weight goals (fatloser or gainer); %s is a %s goal\n", req.weightUnits, goal.Slug, goal.GoalType)
+			return addRequest{}, 1, true
+		}
+		if goalWeightUnits == "" {
+			fmt.Fprintf(stderr, "Error: Can't convert to %s's units %q (expected lb or kg)\n", goal.Slug, goal.Gunits)
+			return addRequest{}, 1, true
+		}
+	}
+
+	if req.promptValue {
+		var code int
+		var done bool
+		req, code, done = promptAddValue(req, *goal, client, stdin, stdout, stderr)
+		if done {
+			return addRequest{}, code, true
+		}
+	}
+
+	if req.weightUnits != "" && req.weightUnits != goalWeightUnits {
+		v, _ := strconv.ParseFloat(req.value, 64) // parseAddValue already validated it
+		converted := fmt.Sprintf("%.6g", convertWeight(v, req.weightUnits))
+		fmt.Fprintf(stdout, "%s %s = %s %s\n", req.value, req.weightUnits, converted, goalWeightUnits)
+		req.value = converted
+	}
+	return req, 0, false
+}
+
+// promptAddValue asks for the value of an add run from a terminal without
+// one, showing the goal's units and last value as a reminder. For an odometer
+// (biker) goal it asks for the reading, refuses one below the last reading
+// (that takes an odometer reset), and shows the distance since. An invalid
+// entry is explained and asked for again; an empty one cancels. It returns the
+// completed request, a process exit code, and done=true when the caller
+// should stop.
+func promptAddValue(req addRequest, goal Goal, client Client, stdin io.Reader, stdout, stderr io.Writer) (addRequest, int, bool) {
+	// A --lb/--kg value is typed in those units, not the goal's.
+	entryUnits := goal.Gunits
+	if req.weightUnits != "" {
+		entryUnits = req.weightUnits
+	}
+	odometer := goal.GoalType == "biker"
+
+	label := "Value for " + goal.Slug
+	if odometer {
+		label = "Odometer reading for " + goal.Slug
+	}
+	if entryUnits != "" {
+		label += fmt.Sprintf(" (%s)", entryUnits)
+	}
+	lastValue, err := client.GetLastDatapointValue(context.Background(), goal.Slug)
+	hasLast := err == nil && lastValue != 0
+	if hasLast {
+		label += fmt.Sprintf(" [last: %s]", strconv.FormatFloat(lastValue, 'f', -1, 64))
+	}
+
+	reader := bufio.NewReader(stdin)
+	for {
+		fmt.Fprintf(stdout, "%s: ", label)
+		line, err := reader.ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			fmt.Fprintln(stdout)
+			fmt.Fprintf(stderr, "Error: Failed to read value: %s\n", redactError(err))
+			return addRequest{}, 1, true
+		}
+		input := strings.TrimSpace(line)
+		if input == "" {
+			if err != nil {
+				fmt.Fprintln(stdout)
+			}
+			fmt.Fprintln(stdout, "Cancelled.")
+			return addRequest{}, 0, true
+		}
+		value, parseErr := parseAddValue(input, entryUnits)
+		if parseErr == nil && odometer && hasLast {
+			v, _ := strconv.ParseFloat(value, 64)
+			if v < lastValue && v != 0 {
+				parseErr = fmt.Errorf("%s is below the last reading; if the odometer was reset, run 'buzz odometer-reset %s' first", value, goal.Slug)
+			} else if v >= lastValue {
+				fmt.Fprintf(stdout, "+%s%s since the last reading\n", strconv.FormatFloat(v-lastValue, 'f', -1, 64), unitsSuffix(entryUnits))
+			}
+		}
+		if parseErr == nil {
+			req.value = value
+			req.promptValue = false
+			return req, 0, false
+		}
+		fmt.Fprintf(stderr, "Error: %s\n", parseErr)
+		if err != nil {
+			// EOF: there's no one to ask again.
+			return addRequest{}, 1, true
+		}
+	}
+}
+
+// unitsSuffix returns " <units>" for appending to a number, or "" when the
+// goal has no units.
+func unitsSuffix(gunits string) string {
+	if gunits == "" {
+		return ""
+	}
+	return " " + gunits
+}
+
+// kgPerLb is the exact international avoirdupois pound.
+const kgPerLb = 0.45359237
+
+// weightUnitsOf returns "lb" or "kg" when a goal's units name one of them, or
+// "" otherwise.
+func weightUnitsOf(gunits string) string {
+	switch strings.ToLower(strings.TrimSpace(gunits)) {
+	case "lb", "lbs", "pound", "pounds":
+		return "lb"
+	case "kg", "kgs", "kilo", "kilos", "kilogram", "kilograms":
+		return "kg"
+	}
+	return ""
+}
+
+// convertWeight converts v from the given weight units ("lb" or "kg") to the
+// other one.
+func convertWeight(v float64, from string) float64 {
+	if from == "kg" {
+		return v / kgPerLb
+	}
+	return v * kgPerLb
+}
+
+// withDefaultRequestID fills in the derived request ID (see
+// derivedRequestID) when the add has none, unless --no-requestid was given or
+// no_auto_requestid is set in config. A comment from --comment-stdin or a
+// bulk add line is part of the ID: it's how a script like `git log -1
+// --format=%s | buzz add commits 1 --comment-stdin` logs each commit, and how
+// two lines of an import differing only in their comment both get added.
+func withDefaultRequestID(req addRequest, config *Config, now time.Time) addRequest {
+	if req.requestid != "" || req.noRequestID || config.NoAutoRequestID {
+		return req
+	}
+	date := req.daystamp
+	if date == "" {
+		date = now.Format("20060102")
+		if secs, err := strconv.ParseInt(req.timestamp, 10, 64); err == nil {
+			date = time.Unix(secs, 0).Format("20060102")
+		}
+	}
+	var comment string
+	if req.distinctComment {
+		comment = req.comment
+	}
+	req.requestid = derivedRequestID(date, req.goalSlug, req.value, comment)
+	return req
+}
+
+// derivedRequestID returns a request ID determined by the datapoint's date
+// (YYYYMMDD), goal, and value, and comment unless it's "". Beeminder ignores a
+// datapoint whose request ID it has already seen for the goal, so re-running
+// an add — from shell history, or a script retrying after a timeout — can't
+// log it twice. The value is normalized first so "1" and "1.0" count as the
+// same add.
+func derivedRequestID(date, goalSlug, value, comment string) string {
+	if v, err := strconv.ParseFloat(value, 64); err == nil {
+		value = strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	key := date + "\x00" + goalSlug + "\x00" + value
+	if comment != "" {
+		key += "\x00" + comment
+	}
+	sum := sha256.Sum256([]byte(key))
+	return "buzz-" + date + "-" + hex.EncodeToString(sum[:6])
+}
+
+// alreadyLoggedSlack allows for the Date header's whole seconds when
+// comparing a datapoint's updated_at with when the add was sent.
+const alreadyLoggedSlack = 2 * time.Second
+
+// alreadyLogged reports whether dp, returned for an add sent at sentAt, is a
+// datapoint Beeminder already had rather than a new one: the one buzz last
+// added to the goal (previousID), or one last changed before the add was sent
+// by Beeminder's clock.
+func alreadyLogged(dp *Datapoint, previousID string, sentAt time.Time) bool {
+	if dp == nil || dp.ID == "" {
+		return false
+	}
+	if dp.ID == previousID {
+		return true
+	}
+	if dp.UpdatedAt == 0 {
+		return false
+	}
+	skew, _ := clockSkew() // local minus server time
+	return time.Unix(dp.UpdatedAt, 0).Before(sentAt.Add(-skew - alreadyLoggedSlack))
+}
+
+// alreadyLoggedMessage says that req's datapoint was already logged, and how
+// to log it again anyway.
+func alreadyLoggedMessage(req addRequest, now time.Time) string {
+	when := "today"
+	if day := addDay(req, now); day.Format("20060102") != now.Format("20060102") {
+		when = "on " + day.Format("2006-01-02")
+	}
+	return fmt.Sprintf("Warning: %s: %s%s already logged %s, not added again (requestid=%q).\n"+
+		"To log it again anyway, pass --no-requestid or a different --requestid, or set \"no_auto_requestid\": true in ~/.buzzrc.\n",
+		req.goalSlug, req.value, unitsSuffix(req.gunits), when, req.requestid)
+}
+
+// addDay is the day the request adds to: its daystamp, or the day of its
+// timestamp, or else today.
+func addDay(req addRequest, now time.Time) time.Time {
+	switch {
+	case req.daystamp != "":
+		day, _ := time.ParseInLocation("20060102", req.daystamp, now.Location())
+		return day
+	case req.timestamp != "":
+		secs, _ := strconv.ParseInt(req.timestamp, 10, 64)
+		return time.Unix(secs, 0).In(now.Location())
+	}
+	return now
+}
+
+// runAddDryRun fetches the goal and prints what the request would add and
+// roughly what it would do to the goal (see simulateAdd), without submitting
+// it. It returns the process exit code.
+func runAddDryRun(ctx context.Context, req addRequest, client Client, now time.Time, stdout, stderr io.Writer) int {
+	goal, err := client.FetchGoalWithDatapoints(ctx, req.goalSlug)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %s\n", redactError(err))
+		return 1
+	}
+	v, err := strconv.ParseFloat(req.value, 64)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: value must be a valid number, got: %s\n", req.value)
+		return 1
+	}
+
+	day := addDay(req, now)
+	fmt.Fprintf(stdout, "Dry run: Would add %s%s to %s on %s, comment=%q", req.value, unitsSuffix(goal.Gunits), goal.Slug, formatDate(day), req.comment)
+	if req.requestid != "" {
+		fmt.Fprintf(stdout, ", requestid=%q", req.requestid)
+	}
+	fmt.Fprintln(stdout)
+	if sim, err := simulateAdd(*goal, v, day, now); err != nil {
+		fmt.Fprintf(stdout, "Can't simulate the effect: %s\n", err)
+	} else {
+		fmt.Fprint(stdout, sim.describe(*goal, now))
+	}
+	fmt.Fprintln(stdout, "(Dry run; nothing was added.)")
+	return 0
+}
+
+// fetchAddGoal fetches the goal req adds to, for the success message (its
+// units, unless completeAddRequest already looked them up) and the celebration
+// check (its datapoints, when celebrations are on) — one request for both.
+// Both are niceties, so a failed fetch just leaves them out. The goal is
+// returned only when fetched with its datapoints.
+func fetchAddGoal(ctx context.Context, req addRequest, client Client) (addRequest, *Goal) {
+	if celebrationsOn() {
+		goal, err := client.FetchGoalWithDatapoints(ctx, req.goalSlug)
+		if err != nil {
+			return req, nil
+		}
+		req.gunits = cmp.Or(req.gunits, goal.Gunits)
+		return req, goal
+	}
+	if req.gunits == "" {
+		if goal, err := client.FetchGoal(ctx, req.goalSlug); err == nil {
+			req.gunits = goal.Gunits
+		}
+	}
+	return req, nil
+}
+
 // runAddCommand submits the datapoint for an already-validated request and
 // returns the process exit code.
 func runAddCommand(req addRequest, client Client, stdout, stderr io.Writer) int {
-	// Use the current time as timestamp (only used when daystamp is empty).
-	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	code, _ := submitAdd(req, client, time.Now(), stdout, stderr)
+	return code
+}
 
-	if _, err := client.CreateDatapointWithDaystamp(context.Background(), req.goalSlug, timestamp, req.daystamp, req.value, req.comment, req.requestid); err != nil {
+// submitAdd submits the datapoint for an already-validated request at now,
+// returning the process exit code and whether a new datapoint was added.
+// Beeminder answers a request ID it has already seen with the datapoint it
+// made then, so an add it deduplicated is reported as such rather than as a
+// success.
+func submitAdd(req addRequest, client Client, now time.Time, stdout, stderr io.Writer) (int, bool) {
+	// Use --timestamp, or else the current time (only used when daystamp is empty).
+	timestamp := req.timestamp
+	if timestamp == "" {
+		timestamp = strconv.FormatInt(now.Unix(), 10)
+	}
+	var previousID string
+	if adds, err := loadLastAdds(); err == nil {
+		previousID = adds[req.goalSlug]
+	}
+
+	dp, err := client.CreateDatapointWithDaystamp(context.Background(), req.goalSlug, timestamp, req.daystamp, req.value, req.comment, req.requestid)
+	if err != nil {
 		fmt.Fprintf(stderr, "Error: Failed to add datapoint: %s\n", redactError(err))
-		return 1
+		return 1, false
+	}
+	if req.requestid != "" && alreadyLogged(dp, previousID, now) {
+		fmt.Fprint(stderr, alreadyLoggedMessage(req, now))
+		return 0, false
 	}
 
 	successMsg := fmt.Sprintf("Successfully added datapoint to %s: value=%s, comment=\"%s\"", req.goalSlug, req.value, req.comment)
+	if req.gunits != "" {
+		successMsg = fmt.Sprintf("Successfully added %s%s to %s: comment=\"%s\"", req.value, unitsSuffix(req.gunits), req.goalSlug, req.comment)
+	}
 	if req.daystamp != "" {
 		successMsg += fmt.Sprintf(", daystamp=%s", req.daystamp)
 	}
+	if req.timestamp != "" {
+		successMsg += fmt.Sprintf(", timestamp=%s", req.timestamp)
+	}
 	if req.requestid != "" {
 		successMsg += fmt.Sprintf(", requestid=\"%s\"", req.requestid)
 	}
 	fmt.Fprintln(stdout, successMsg)
 
+	// Remember the datapoint so `buzz undo` can take it back.
+	if dp != nil && dp.ID != "" {
+		if err := setLastAdd(req.goalSlug, dp.ID); err != nil {
+			fmt.Fprintf(stderr, "Warning: Could not remember the datapoint for buzz undo: %s\n", err)
+		}
+	}
+
 	// Signal any running TUI instances to refresh so they pick up the new
 	// datapoint. Don't fail the command if flag creation fails.
 	if err := createRefreshFlag(); err != nil {
 		fmt.Fprintf(stderr, "Warning: Could not create refresh flag: %s\n", redactError(err))
 	}
-	return 0
+	return 0, true
 }
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const addBulkUsage = `Usage: buzz add --bulk -
+       buzz add --bulk --file=<path>
+
+Reads datapoints as CSV lines of slug,value,comment[,date] — from stdin with
+"-", or from a file with --file — and adds each in turn. The comment may be
+empty (it defaults to "Added via buzz") and the date is YYYY-MM-DD or
+YYYYMMDD (default: now). A time like 1:30 is converted to the goal's units.
+Quote a comment containing commas. Blank lines, lines starting with #, and a
+header row starting with "slug" are skipped. Every line is checked before
+anything is submitted; a bad line stops the import. A before_add hook (see
+"hooks" in ~/.buzzrc) runs on each line just before it's sent, and a line it
+refuses counts as failed. Each datapoint gets a request ID derived from its date, goal, value,
+and comment (unless no_auto_requestid is set), so re-running an import that
+stopped partway doesn't log anything twice.`
+
+// bulkAddRow is one line of a bulk add, with the line it came from for
+// error messages.
+type bulkAddRow struct {
+	line int
+	req  addRequest
+}
+
+// isBulkAdd reports whether `buzz add` arguments ask for a bulk add, which is
+// parsed by parseBulkAddArgs rather than parseAddArgs. Only the flags before
+// the first positional argument count, so a comment can mention --bulk.
+func isBulkAdd(args []string) bool {
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			return false
+		}
+		if arg == "--bulk" || arg == "-bulk" || strings.HasPrefix(arg, "--file") || strings.HasPrefix(arg, "-file") {
+			return true
+		}
+	}
+	return false
+}
+
+// handleAddBulkCommand runs `buzz add --bulk`.
+func handleAddBulkCommand() {
+	source, code, done := parseBulkAddArgs(os.Args[2:], os.Stdout, os.Stderr)
+	if done {
+		os.Exit(code)
+	}
+
+	var r io.Reader = os.Stdin
+	if source != "-" {
+		f, err := os.Open(source)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	config, client, ok := loadConfigAndClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+	useConfiguredHooks(os.Stderr)
+	os.Exit(runAddBulkCommand(r, config, client, time.Now(), os.Stdout, os.Stderr))
+}
+
+// parseBulkAddArgs parses `buzz add --bulk` arguments, returning where to
+// read from ("-" for stdin, else a file path), a process exit code, and
+// done=true when the caller should stop.
+func parseBulkAddArgs(args []string, stdout, stderr io.Writer) (string, int, bool) {
+	fs := flag.NewFlagSet("add", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Bool("bulk", false, "Add datapoints from CSV lines")
+	file := fs.String("file", "", "CSV file to read")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stdout, addBulkUsage)
+			return "", 0, true
+		}
+		fmt.Fprintf(stderr, "Error parsing flags: %s\n", redactError(err))
+		fmt.Fprintln(stderr, addBulkUsage)
+		return "", 1, true
+	}
+
+	rest := fs.Args()
+	switch {
+	case *file != "" && len(rest) == 0:
+		return *file, 0, false
+	case *file == "" && slices.Equal(rest, []string{"-"}):
+		return "-", 0, false
+	}
+	fmt.Fprintln(stderr, "Error: --bulk reads from stdin (-) or --file, and takes no other arguments")
+	fmt.Fprintln(stderr, addBulkUsage)
+	return "", 1, true
+}
+
+// parseBulkAddRows reads and validates the lines of a bulk add. It returns
+// every problem found rather than stopping at the first, so a file can be
+// fixed in one pass.
+func parseBulkAddRows(r io.Reader) ([]bulkAddRow, []error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+
+	var rows []bulkAddRow
+	var errs []error
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, []error{err}
+			}
+			errs = append(errs, err)
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		if len(rows) == 0 && len(errs) == 0 && strings.EqualFold(strings.TrimSpace(record[0]), "slug") {
+			continue // header row
+		}
+		req, err := parseBulkAddRecord(record)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %w", line, err))
+			continue
+		}
+		rows = append(rows, bulkAddRow{line: line, req: req})
+	}
+	return rows, errs
+}
+
+// parseBulkAddRecord turns one slug,value,comment[,date] record into a
+// request, validating it as `buzz add` validates its arguments.
+func parseBulkAddRecord(record []string) (addRequest, error) {
+	if len(record) < 2 || len(record) > 4 {
+		return addRequest{}, fmt.Errorf("expected slug,value,comment[,date], got %d fields", len(record))
+	}
+	for i := range record {
+		record[i] = strings.TrimSpace(record[i])
+	}
+	slug := record[0]
+	if slug == "" {
+		return addRequest{}, errors.New("missing goal slug")
+	}
+	value, err := parseAddValue(record[1], "")
+	if err != nil {
+		return addRequest{}, err
+	}
+	req := addRequest{goalSlug: slug, value: value, comment: "Added via buzz", distinctComment: true}
+	if isTimeFormat(normalizeDecimal(record[1])) {
+		req.timeValue = record[1]
+	}
+	if len(record) > 2 && record[2] != "" {
+		req.comment = record[2]
+	}
+	if len(record) > 3 && record[3] != "" {
+		date := record[3]
+		if t, err := time.Parse("20060102", date); err == nil {
+			date = t.Format("2006-01-02")
+		}
+		if msg := validateDatapointDate(date); msg != "" {
+			return addRequest{}, fmt.Errorf("date %s: %s", record[3], msg)
+		}
+		req.daystamp = strings.ReplaceAll(date, "-", "")
+	}
+	return req, nil
+}
+
+// convertBulkTimes converts the rows' times like "1:30" to their goals'
+// units, as completeAddRequest does for `buzz add`, so 1:30 on a goal in
+// minutes is 90. Each goal is fetched once, and only if one of its lines is
+// a time. It returns an error for each line that can't be converted.
+func convertBulkTimes(ctx context.Context, rows []bulkAddRow, client Client) []error {
+	goals := map[string]*Goal{}
+	fetchErrs := map[string]error{}
+	var errs []error
+	for i := range rows {
+		req := &rows[i].req
+		if req.timeValue == "" {
+			continue
+		}
+		goal, fetched := goals[req.goalSlug]
+		err := fetchErrs[req.goalSlug]
+		if !fetched && err == nil {
+			goal, err = client.FetchGoal(ctx, req.goalSlug)
+			goals[req.goalSlug], fetchErrs[req.goalSlug] = goal, err
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %s", rows[i].line, redactError(err)))
+			continue
+		}
+		req.gunits = goal.Gunits
+		if req.value, err = parseAddValue(req.timeValue, goal.Gunits); err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %w", rows[i].line, err))
+		}
+	}
+	return errs
+}
+
+// runAddBulkCommand is the testable core of `buzz add --bulk`: it validates
+// every line of r, then submits each datapoint, printing progress as it goes
+// and a summary at the end. A failed submission doesn't stop the rest; the
+// exit code is 1 if any failed.
+func runAddBulkCommand(r io.Reader, config *Config, client Client, now time.Time, stdout, stderr io.Writer) int {
+	rows, errs := parseBulkAddRows(r)
+	if len(errs) == 0 {
+		errs = convertBulkTimes(context.Background(), rows, client)
+	}
+	if len(errs) > 0 {
+		for _, err := range errs {
+			fmt.Fprintf(stderr, "Error: %s\n", err)
+		}
+		fmt.Fprintln(stderr, "Nothing was submitted; fix the lines above and run the import again.")
+		return 1
+	}
+	if len(rows) == 0 {
+		fmt.Fprintln(stderr, "Error: No datapoints to add")
+		return 1
+	}
+
+	timestamp := strconv.FormatInt(now.Unix(), 10) // for lines without a date
+	added, failed, logged := 0, 0, 0
+	lastIDs := map[string]string{} // the datapoint each goal's last line got
+	for i, row := range rows {
+		// The before_add hook runs on each line just before it's sent, as
+		// for `buzz add`; a line it refuses counts as failed.
+		req, _, refused := applyAddHook(row.req, activeHooks, client, stderr)
+		if refused {
+			fmt.Fprintf(stdout, "[%d/%d] %s %s: failed\n", i+1, len(rows), row.req.goalSlug, row.req.value)
+			failed++
+			continue
+		}
+		req = withDefaultRequestID(req, config, now)
+		progress := fmt.Sprintf("[%d/%d] %s %s", i+1, len(rows), req.goalSlug, req.value)
+		if req.daystamp != "" {
+			progress += " on " + isoDate(req.daystamp)
+		}
+		dp, err := client.CreateDatapointWithDaystamp(context.Background(), req.goalSlug, timestamp, req.daystamp, req.value, req.comment, req.requestid)
+		if err != nil {
+			fmt.Fprintf(stdout, "%s: failed\n", progress)
+			fmt.Fprintf(stderr, "Error: line %d: %s\n", row.line, redactError(err))
+			failed++
+			continue
+		}
+		// Identical lines, or lines already imported by an earlier run, get
+		// the same request ID, and Beeminder hands back the datapoint it has.
+		if req.requestid != "" && alreadyLogged(dp, lastIDs[req.goalSlug], now) {
+			fmt.Fprintf(stdout, "%s: already logged, not added again\n", progress)
+			logged++
+			continue
+		}
+		if dp != nil {
+			lastIDs[req.goalSlug] = dp.ID
+		}
+		fmt.Fprintf(stdout, "%s: ok\n", progress)
+		added++
+	}
+
+	fmt.Fprintf(stdout, "Added %d of %d datapoints", added, len(rows))
+	if logged > 0 {
+		fmt.Fprintf(stdout, "; %d already logged", logged)
+	}
+	if failed > 0 {
+		fmt.Fprintf(stdout, "; %d failed", failed)
+	}
+	fmt.Fprintln(stdout)
+	if logged > 0 {
+		fmt.Fprintln(stderr, "Lines with the same goal, value, date, and comment as one already logged aren't added again. To add them anyway, set \"no_auto_requestid\": true in ~/.buzzrc.")
+	}
+
+	if added > 0 {
+		if err := createRefreshFlag(); err != nil {
+			fmt.Fprintf(stderr, "Warning: Could not create refresh flag: %s\n", redactError(err))
+		}
+	}
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
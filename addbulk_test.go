@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsBulkAdd(t *testing.T) {
+	for _, tt := range []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"--bulk", "-"}, true},
+		{[]string{"--file=data.csv"}, true},
+		{[]string{"--bulk", "--file", "data.csv"}, true},
+		{[]string{"goal", "1", "--bulk"}, false},
+		{[]string{"--daystamp=20240115", "goal", "1"}, false},
+	} {
+		if got := isBulkAdd(tt.args); got != tt.want {
+			t.Errorf("isBulkAdd(%v) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestParseBulkAddArgs(t *testing.T) {
+	for _, tt := range []struct {
+		args     []string
+		want     string
+		wantCode int
+		wantDone bool
+	}{
+		{[]string{"--bulk", "-"}, "-", 0, false},
+		{[]string{"--bulk", "--file=data.csv"}, "data.csv", 0, false},
+		{[]string{"--file", "data.csv"}, "data.csv", 0, false},
+		{[]string{"--bulk"}, "", 1, true},
+		{[]string{"--bulk", "--file=data.csv", "-"}, "", 1, true},
+		{[]string{"--bulk", "--help"}, "", 0, true},
+	} {
+		got, code, done := parseBulkAddArgs(tt.args, &bytes.Buffer{}, &bytes.Buffer{})
+		if got != tt.want || code != tt.wantCode || done != tt.wantDone {
+			t.Errorf("parseBulkAddArgs(%v) = %q, %d, %v", tt.args, got, code, done)
+		}
+	}
+}
+
+func TestParseBulkAddRows(t *testing.T) {
+	input := `slug,value,comment,date
+# migrated from the old tracker
+reading,12,"chapters 3, 4",2024-01-15
+
+workout,1:30,,20240116
+water,3
+`
+	rows, errs := parseBulkAddRows(strings.NewReader(input))
+	if len(errs) != 0 {
+		t.Fatalf("errs = %v", errs)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("rows = %+v, want 3", rows)
+	}
+	if r := rows[0]; r.line != 3 || r.req.goalSlug != "reading" || r.req.value != "12" || r.req.comment != "chapters 3, 4" || r.req.daystamp != "20240115" {
+		t.Errorf("row 0 = %+v", r)
+	}
+	if r := rows[1]; r.req.value != "1.5" || r.req.comment != "Added via buzz" || r.req.daystamp != "20240116" {
+		t.Errorf("row 1 = %+v", r)
+	}
+	if r := rows[2]; r.req.daystamp != "" || r.req.comment != "Added via buzz" {
+		t.Errorf("row 2 = %+v", r)
+	}
+
+	future := time.Now().AddDate(0, 0, 3).Format("2006-01-02")
+	_, errs = parseBulkAddRows(strings.NewReader("reading\nreading,abc\n,1\nreading,1,x," + future + "\nreading,1,x,2024/01/15\n"))
+	if len(errs) != 5 {
+		t.Fatalf("errs = %v, want one per line", errs)
+	}
+	if !strings.HasPrefix(errs[1].Error(), "line 2:") {
+		t.Errorf("errs[1] = %v, want it to name line 2", errs[1])
+	}
+}
+
+func TestRunAddBulkCommand(t *testing.T) {
+	now := time.Date(2024, 1, 20, 9, 0, 0, 0, time.Local)
+
+	t.Run("submits each line and summarizes", func(t *testing.T) {
+		setHome(t, t.TempDir())
+		var gotReqIDs []string
+		client := &FakeClient{
+			CreateDatapointWithDaystampFunc: func(slug, _, _, _, _, requestid string) (*Datapoint, error) {
+				gotReqIDs = append(gotReqIDs, requestid)
+				if slug == "gone" {
+					return nil, errors.New("goal not found")
+				}
+				return &Datapoint{}, nil
+			},
+		}
+		var out, errb bytes.Buffer
+		code := runAddBulkCommand(strings.NewReader("reading,1,,2024-01-15\ngone,2\nwater,3\n"), &Config{}, client, now, &out, &errb)
+		checkResult(t, code, out.String(), errb.String(), 1, "Added 2 of 3 datapoints; 1 failed", "line 2: goal not found")
+		if !strings.Contains(out.String(), "[1/3] reading 1 on 2024-01-15: ok") || !strings.Contains(out.String(), "[2/3] gone 2: failed") {
+			t.Errorf("progress = %q", out.String())
+		}
+		if len(gotReqIDs) != 3 || !strings.HasPrefix(gotReqIDs[0], "buzz-20240115-") || !strings.HasPrefix(gotReqIDs[2], "buzz-20240120-") {
+			t.Errorf("request IDs = %v, want ones derived from each line's date", gotReqIDs)
+		}
+	})
+
+	t.Run("identical lines are added once", func(t *testing.T) {
+		setHome(t, t.TempDir())
+		ids := map[string]string{} // request ID → datapoint ID, as Beeminder keeps them
+		client := &FakeClient{
+			CreateDatapointWithDaystampFunc: func(_, _, _, _, _, requestid string) (*Datapoint, error) {
+				if ids[requestid] == "" {
+					ids[requestid] = fmt.Sprintf("dp%d", len(ids))
+				}
+				return &Datapoint{ID: ids[requestid], UpdatedAt: now.Unix()}, nil
+			},
+		}
+		var out, errb bytes.Buffer
+		code := runAddBulkCommand(strings.NewReader("reading,1\nreading,1\nreading,2\n"), &Config{}, client, now, &out, &errb)
+		checkResult(t, code, out.String(), errb.String(), 0, "Added 2 of 3 datapoints; 1 already logged", "no_auto_requestid")
+		if !strings.Contains(out.String(), "[2/3] reading 1: already logged, not added again") {
+			t.Errorf("progress = %q", out.String())
+		}
+	})
+
+	t.Run("lines differing only in comment are both added", func(t *testing.T) {
+		setHome(t, t.TempDir())
+		var gotReqIDs []string
+		client := &FakeClient{
+			CreateDatapointWithDaystampFunc: func(_, _, _, _, _, requestid string) (*Datapoint, error) {
+				gotReqIDs = append(gotReqIDs, requestid)
+				return &Datapoint{ID: requestid, UpdatedAt: now.Unix()}, nil
+			},
+		}
+		var out, errb bytes.Buffer
+		code := runAddBulkCommand(strings.NewReader("reading,1,morning\nreading,1,evening\n"), &Config{}, client, now, &out, &errb)
+		checkResult(t, code, out.String(), errb.String(), 0, "Added 2 of 2 datapoints", "")
+		if len(gotReqIDs) != 2 || gotReqIDs[0] == gotReqIDs[1] {
+			t.Errorf("request IDs = %v, want one per comment", gotReqIDs)
+		}
+	})
+
+	t.Run("times are converted to each goal's units", func(t *testing.T) {
+		setHome(t, t.TempDir())
+		fetches := map[string]int{}
+		var gotValues []string
+		client := &FakeClient{
+			FetchGoalFunc: func(slug string) (*Goal, error) {
+				fetches[slug]++
+				return &Goal{Slug: slug, Gunits: map[string]string{"piano": "minutes", "sleep": "hours"}[slug]}, nil
+			},
+			CreateDatapointWithDaystampFunc: func(_, _, _, value, _, _ string) (*Datapoint, error) {
+				gotValues = append(gotValues, value)
+				return &Datapoint{}, nil
+			},
+		}
+		var out, errb bytes.Buffer
+		code := runAddBulkCommand(strings.NewReader("piano,1:30,a\npiano,0:45,b\nsleep,7:30\nwater,3\n"), &Config{}, client, now, &out, &errb)
+		checkResult(t, code, out.String(), errb.String(), 0, "Added 4 of 4 datapoints", "")
+		if !slices.Equal(gotValues, []string{"90", "45", "7.5", "3"}) {
+			t.Errorf("values = %v, want 90, 45, 7.5, 3", gotValues)
+		}
+		if fetches["piano"] != 1 || fetches["sleep"] != 1 || fetches["water"] != 0 {
+			t.Errorf("fetches = %v, want each goal with a time fetched once", fetches)
+		}
+	})
+
+	t.Run("a time on a goal not measured in time submits nothing", func(t *testing.T) {
+		client := &FakeClient{
+			FetchGoalFunc: func(slug string) (*Goal, error) {
+				return &Goal{Slug: slug, Gunits: "pages"}, nil
+			},
+		}
+		var out, errb bytes.Buffer
+		code := runAddBulkCommand(strings.NewReader("reading,1\nreading,1:30\n"), &Config{}, client, now, &out, &errb)
+		checkResult(t, code, out.String(), errb.String(), 1, "", "line 2: 1:30 looks like a time, but the goal is measured in pages")
+	})
+
+	t.Run("the before_add hook runs on each line", func(t *testing.T) {
+		setHome(t, t.TempDir())
+		activeHooks = writeHooks(t, `
+def before_add(goal, value, comment):
+    if goal.slug == "junk":
+        fail("no junk")
+    return {"comment": comment + "!"}
+`)
+		t.Cleanup(func() { activeHooks = nil })
+		var gotComments []string
+		client := &FakeClient{
+			FetchGoalFunc: func(slug string) (*Goal, error) {
+				return &Goal{Slug: slug}, nil
+			},
+			CreateDatapointWithDaystampFunc: func(_, _, _, _, comment, _ string) (*Datapoint, error) {
+				gotComments = append(gotComments, comment)
+				return &Datapoint{}, nil
+			},
+		}
+		var out, errb bytes.Buffer
+		code := runAddBulkCommand(strings.NewReader("reading,1,a\njunk,2,b\nwater,3,c\n"), &Config{}, client, now, &out, &errb)
+		checkResult(t, code, out.String(), errb.String(), 1, "Added 2 of 3 datapoints; 1 failed", "no junk")
+		if !strings.Contains(out.String(), "[2/3] junk 2: failed") {
+			t.Errorf("progress = %q", out.String())
+		}
+		if !slices.Equal(gotComments, []string{"a!", "c!"}) {
+			t.Errorf("comments = %v, want the hook's", gotComments)
+		}
+	})
+
+	t.Run("a bad line submits nothing", func(t *testing.T) {
+		client := &FakeClient{} // any submission fails with errFakeNotConfigured
+		var out, errb bytes.Buffer
+		code := runAddBulkCommand(strings.NewReader("reading,1\nreading,lots\n"), &Config{}, client, now, &out, &errb)
+		checkResult(t, code, out.String(), errb.String(), 1, "", "Nothing was submitted")
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		var out, errb bytes.Buffer
+		code := runAddBulkCommand(strings.NewReader("slug,value\n"), &Config{}, &FakeClient{}, now, &out, &errb)
+		checkResult(t, code, out.String(), errb.String(), 1, "", "No datapoints to add")
+	})
+}
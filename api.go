@@ -53,7 +53,7 @@ func handleAPICommand() {
 		os.Exit(1)
 	}
 
-	client := NewHTTPClient(config)
+	client := journaling(NewHTTPClient(config))
 	os.Exit(runAPICommand(os.Args[2:], client, os.Stdout, os.Stderr))
 }
 
@@ -145,6 +145,9 @@ func runAPICommand(args []string, client Client, stdout, stderr io.Writer) int {
 	// the body above, so error details from the API remain visible.
 	if status < 200 || status >= 300 {
 		fmt.Fprintf(stderr, "API returned status %d\n", status)
+		if status == http.StatusUnauthorized {
+			fmt.Fprintln(stderr, "Your auth token was rejected; run 'buzz auth login' to sign in again.")
+		}
 		return 1
 	}
 
@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -10,7 +11,7 @@ import (
 )
 
 // parseAndSaveCredentials parses a JSON Beeminder credentials blob, validates
-// that the required fields are present, and persists it to the config file. It
+// that the required fields are present, and saves them to the config file. It
 // is shared by the interactive TUI auth screen and the `buzz auth login`
 // command so both accept identical input and report identical errors.
 func parseAndSaveCredentials(input string) (*Config, error) {
@@ -34,11 +35,23 @@ func parseAndSaveCredentials(input string) (*Config, error) {
 		return nil, fmt.Errorf("username and auth_token are required")
 	}
 
-	if err := SaveConfig(&config); err != nil {
+	// Logging in again keeps the rest of the config, such as saved filters.
+	// A missing or unreadable config is replaced.
+	saved := &config
+	err := updateConfig(func(existing *Config) error {
+		existing.Username, existing.AuthToken = config.Username, config.AuthToken
+		saved = existing
+		return nil
+	})
+	if err != nil && !errors.Is(err, errConfigLocked) {
+		saved = &config
+		err = SaveConfig(&config)
+	}
+	if err != nil {
 		return nil, fmt.Errorf("failed to save config: %w", err)
 	}
 
-	return &config, nil
+	return saved, nil
 }
 
 type authModel struct {
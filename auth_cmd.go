@@ -13,6 +13,8 @@ func printAuthHelp() {
 	fmt.Println("")
 	fmt.Println("USAGE:")
 	fmt.Println("  buzz auth login                   Authenticate by pasting your API credentials")
+	fmt.Println("  buzz auth encrypt                 Encrypt ~/.buzzrc with a passphrase, or change it")
+	fmt.Println("  buzz auth decrypt                 Store ~/.buzzrc unencrypted again")
 	fmt.Println("  buzz auth help                    Show this help message")
 }
 
@@ -26,6 +28,10 @@ func handleAuthCommand() {
 	switch os.Args[2] {
 	case "login":
 		handleAuthLoginCommand()
+	case "encrypt":
+		handleAuthEncryptCommand(true)
+	case "decrypt":
+		handleAuthEncryptCommand(false)
 	case "help", "-h", "--help":
 		printAuthHelp()
 	default:
@@ -81,3 +87,32 @@ func handleAuthLoginCommand() {
 	fmt.Println("")
 	fmt.Println("✓ Authentication successful! Credentials saved to ~/.buzzrc")
 }
+
+// handleAuthEncryptCommand encrypts ~/.buzzrc with a passphrase (or changes
+// it), or with encrypt false decrypts it again. See configcrypt.go.
+func handleAuthEncryptCommand(encrypt bool) {
+	if !ConfigExists() {
+		fmt.Fprintln(os.Stderr, "Error: No configuration found. Please run 'buzz auth login' to authenticate.")
+		os.Exit(1)
+	}
+	path, err := getConfigPath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+
+	if !encrypt {
+		if err := decryptConfigFile(path); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to decrypt config: %s\n", err)
+			os.Exit(1)
+		}
+		fmt.Println("✓ ~/.buzzrc is no longer encrypted")
+		return
+	}
+	if err := encryptConfigFile(path); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Failed to encrypt config: %s\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("✓ ~/.buzzrc is encrypted. buzz will ask for the passphrase when it starts,")
+	fmt.Printf("  or read it from $%s.\n", configPassphraseEnv)
+}
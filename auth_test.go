@@ -11,7 +11,7 @@ import (
 func TestParseAndSaveCredentials(t *testing.T) {
 	t.Run("valid credentials are parsed and saved", func(t *testing.T) {
 		tmpDir := t.TempDir()
-		t.Setenv("HOME", tmpDir)
+		setHome(t, tmpDir)
 
 		config, err := parseAndSaveCredentials(`{"username":"alice","auth_token":"secret"}`)
 		if err != nil {
@@ -34,8 +34,26 @@ func TestParseAndSaveCredentials(t *testing.T) {
 		}
 	})
 
+	t.Run("logging in again keeps other settings", func(t *testing.T) {
+		setHome(t, t.TempDir())
+		if err := SaveConfig(&Config{Username: "alice", AuthToken: "old", Filters: map[string]string{"urgent": "safebuf < 2"}}); err != nil {
+			t.Fatal(err)
+		}
+		config, err := parseAndSaveCredentials(`{"username":"alice","auth_token":"new"}`)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		loaded, err := LoadConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if config.AuthToken != "new" || loaded.AuthToken != "new" || loaded.Filters["urgent"] != "safebuf < 2" {
+			t.Errorf("returned %+v, saved %+v; want the new token and the saved filter", config, loaded)
+		}
+	})
+
 	t.Run("surrounding whitespace is trimmed", func(t *testing.T) {
-		t.Setenv("HOME", t.TempDir())
+		setHome(t, t.TempDir())
 
 		if _, err := parseAndSaveCredentials("  \n{\"username\":\"bob\",\"auth_token\":\"t\"}\n  "); err != nil {
 			t.Fatalf("unexpected error: %v", err)
@@ -43,7 +61,7 @@ func TestParseAndSaveCredentials(t *testing.T) {
 	})
 
 	t.Run("rejects empty input", func(t *testing.T) {
-		t.Setenv("HOME", t.TempDir())
+		setHome(t, t.TempDir())
 
 		if _, err := parseAndSaveCredentials("   \n  "); err == nil {
 			t.Error("expected error for empty input")
@@ -54,7 +72,7 @@ func TestParseAndSaveCredentials(t *testing.T) {
 	})
 
 	t.Run("rejects invalid JSON", func(t *testing.T) {
-		t.Setenv("HOME", t.TempDir())
+		setHome(t, t.TempDir())
 
 		if _, err := parseAndSaveCredentials("not json"); err == nil {
 			t.Error("expected error for invalid JSON")
@@ -65,7 +83,7 @@ func TestParseAndSaveCredentials(t *testing.T) {
 	})
 
 	t.Run("rejects missing required fields", func(t *testing.T) {
-		t.Setenv("HOME", t.TempDir())
+		setHome(t, t.TempDir())
 
 		if _, err := parseAndSaveCredentials(`{"username":"alice"}`); err == nil {
 			t.Error("expected error when auth_token is missing")
@@ -79,7 +97,7 @@ func TestParseAndSaveCredentials(t *testing.T) {
 	})
 
 	t.Run("rejects whitespace-only required fields", func(t *testing.T) {
-		t.Setenv("HOME", t.TempDir())
+		setHome(t, t.TempDir())
 
 		if _, err := parseAndSaveCredentials(`{"username":"   ","auth_token":"secret"}`); err == nil {
 			t.Error("expected error for whitespace-only username")
@@ -93,7 +111,7 @@ func TestParseAndSaveCredentials(t *testing.T) {
 	})
 
 	t.Run("trims surrounding whitespace from saved fields", func(t *testing.T) {
-		t.Setenv("HOME", t.TempDir())
+		setHome(t, t.TempDir())
 
 		config, err := parseAndSaveCredentials(`{"username":"  alice  ","auth_token":" secret "}`)
 		if err != nil {
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Autodata goals get their datapoints from an integration (IFTTT, the API, a
+// fitness tracker, …) rather than by hand. When the integration silently
+// breaks, nothing is reported and the goal derails by surprise, so buzz
+// badges autodata goals and warns once one has gone quiet for longer than
+// its integration normally would.
+
+const (
+	// autodataMarker badges autodata goals in the grid; autodataStaleMarker
+	// replaces it once the integration has gone quiet. Both are two terminal
+	// cells wide.
+	autodataMarker      = "🔌"
+	autodataStaleMarker = "❗"
+)
+
+// defaultAutodataCadence is how often an integration is expected to report
+// when autodataCadences has no entry for it.
+const defaultAutodataCadence = 24 * time.Hour
+
+// autodataCadences are the expected reporting intervals of integrations that
+// report less often than daily.
+var autodataCadences = map[string]time.Duration{
+	"withings": 7 * 24 * time.Hour, // weigh-ins are often weekly
+}
+
+// autodataNames are display names for integrations whose autodata value
+// doesn't read well as-is.
+var autodataNames = map[string]string{
+	"api":        "API",
+	"ifttt":      "IFTTT",
+	"zapier":     "Zapier",
+	"gmail":      "Gmail",
+	"github":     "GitHub",
+	"fitbit":     "Fitbit",
+	"googlefit":  "Google Fit",
+	"rescuetime": "RescueTime",
+	"todoist":    "Todoist",
+	"trello":     "Trello",
+	"duolingo":   "Duolingo",
+	"strava":     "Strava",
+	"withings":   "Withings",
+	"habitica":   "Habitica",
+	"skritter":   "Skritter",
+	"tagtime":    "TagTime",
+}
+
+// isAutodataGoal reports whether the goal's datapoints come from an
+// integration.
+func isAutodataGoal(goal Goal) bool {
+	return goal.Autodata != "" && goal.Autodata != "manual"
+}
+
+// autodataSourceName returns the display name of the goal's autodata source,
+// or the raw autodata value for integrations buzz doesn't know.
+func autodataSourceName(goal Goal) string {
+	if name, ok := autodataNames[strings.ToLower(goal.Autodata)]; ok {
+		return name
+	}
+	return goal.Autodata
+}
+
+// autodataWarning returns a warning like "IFTTT hasn't reported in 3 days"
+// when an autodata goal's last datapoint is older than its integration's
+// expected cadence plus a day of slack, or "" when there's nothing to warn
+// about (including goals with no datapoints yet).
+func autodataWarning(goal Goal, now time.Time) string {
+	if !isAutodataGoal(goal) || goal.Lastday == 0 {
+		return ""
+	}
+	cadence, ok := autodataCadences[strings.ToLower(goal.Autodata)]
+	if !ok {
+		cadence = defaultAutodataCadence
+	}
+	age := now.Sub(time.Unix(goal.Lastday, 0))
+	if age <= cadence+24*time.Hour {
+		return ""
+	}
+	days := int(age / (24 * time.Hour))
+	return fmt.Sprintf("%s hasn't reported in %d days", autodataSourceName(goal), days)
+}
+
+// goalMarkers returns the badges shown before a goal's slug in the grid:
+// autodata status, then secretMarker. Empty for a plain goal.
+func goalMarkers(goal Goal, now time.Time) string {
+	var markers string
+	if isAutodataGoal(goal) {
+		if autodataWarning(goal, now) != "" {
+			markers += autodataStaleMarker
+		} else {
+			markers += autodataMarker
+		}
+	}
+	if goal.Secret {
+		markers += secretMarker
+	}
+	return markers
+}
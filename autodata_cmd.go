@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+// `buzz autodata <source>` turns buzz into the autodata bridge for a goal: each
+// source measures something (GitHub contributions, words written, …) and submits today's
+// value. Run from cron, a source updates the day's datapoint rather than
+// piling up new ones.
+
+// printAutodataHelp prints usage for the `buzz autodata` command group.
+func printAutodataHelp(w io.Writer) {
+	fmt.Fprintln(w, "buzz autodata - Submit a goal's datapoints from another service")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "USAGE:")
+	fmt.Fprintln(w, "  buzz autodata github [--user <login>] [--dry-run] --goal <goalslug>")
+	fmt.Fprintln(w, "                                    Submit today's GitHub contribution count")
+	fmt.Fprintln(w, "  buzz autodata http --url <url> --jsonpath <path> [--header 'Name: value']... [--dry-run] --goal <goalslug>")
+	fmt.Fprintln(w, "                                    Submit a number read from a JSON URL")
+	fmt.Fprintln(w, "  buzz autodata wordcount --glob <pattern> [--mode total|delta] [--dry-run] --goal <goalslug>")
+	fmt.Fprintln(w, "                                    Submit the word count of a set of files")
+	fmt.Fprintln(w, "  buzz autodata help                Show this help message")
+}
+
+// handleAutodataCommand dispatches `buzz autodata <source>`.
+func handleAutodataCommand() {
+	if len(os.Args) < 3 {
+		printAutodataHelp(os.Stderr)
+		os.Exit(1)
+	}
+
+	switch os.Args[2] {
+	case "github":
+		handleAutodataGitHubCommand(os.Args[3:])
+	case "http":
+		handleAutodataHTTPCommand(os.Args[3:])
+	case "wordcount":
+		handleAutodataWordcountCommand(os.Args[3:])
+	case "help", "-h", "--help":
+		printAutodataHelp(os.Stdout)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown autodata source: %s\n", os.Args[2])
+		printAutodataHelp(os.Stderr)
+		os.Exit(1)
+	}
+}
+
+// autodataRequestID is the request ID for a source's datapoint on the given
+// day (YYYYMMDD, or longer for a source with several datapoints a day).
+// Beeminder updates the datapoint with a request ID it has already seen, so
+// every run on the same day replaces that day's value.
+func autodataRequestID(source, daystamp string) string {
+	return "buzz-autodata-" + source + "-" + daystamp
+}
+
+// submitAutodataValue submits value as the goal's datapoint for now's day
+// from the named source, or just prints it for a dry run. requestID is
+// usually autodataRequestID's, or "" to always add a new datapoint. It
+// returns the process exit code.
+func submitAutodataValue(ctx context.Context, client Client, goalSlug, source string, value float64, comment, requestID string, dryRun bool, now time.Time, stdout, stderr io.Writer) int {
+	formatted := strconv.FormatFloat(value, 'f', -1, 64)
+	if dryRun {
+		fmt.Fprintf(stdout, "%s: %s (dry run; nothing was submitted to %s)\n", source, formatted, goalSlug)
+		return 0
+	}
+
+	daystamp := now.Format("20060102")
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	if _, err := client.CreateDatapointWithDaystamp(ctx, goalSlug, timestamp, daystamp, formatted, comment, requestID); err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to add datapoint: %s\n", redactError(err))
+		return 1
+	}
+	fmt.Fprintf(stdout, "Submitted %s to %s for %s (\"%s\")\n", formatted, goalSlug, now.Format("2006-01-02"), comment)
+
+	// Signal any running TUI instances to refresh so they pick up the new
+	// datapoint. Don't fail the command if flag creation fails.
+	if err := createRefreshFlag(); err != nil {
+		fmt.Fprintf(stderr, "Warning: Could not create refresh flag: %s\n", redactError(err))
+	}
+	return 0
+}
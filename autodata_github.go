@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+const autodataGitHubUsage = `Usage: buzz autodata github [--user <login>] [--dry-run] --goal <goalslug>
+  --goal     Goal to submit today's contribution count to
+  --user     GitHub login to count (default: me, the token's own account)
+  --dry-run  Print the count without submitting it
+The GitHub token comes from "github_token" in ~/.buzzrc, or $GITHUB_TOKEN.`
+
+// githubGraphQLURL is GitHub's GraphQL endpoint; contribution counts aren't
+// available from the REST API. A variable so tests can point it elsewhere.
+var githubGraphQLURL = "https://api.github.com/graphql"
+
+// autodataGitHubRequest is a parsed, validated `buzz autodata github`
+// invocation.
+type autodataGitHubRequest struct {
+	goalSlug string
+	user     string // "" for the token's own account
+	dryRun   bool
+}
+
+// handleAutodataGitHubCommand submits today's GitHub contribution count.
+func handleAutodataGitHubCommand(args []string) {
+	req, code, done := parseAutodataGitHubArgs(args, os.Stdout, os.Stderr)
+	if done {
+		os.Exit(code)
+	}
+
+	client, ok := loadClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+	// loadClient just loaded the config successfully.
+	token := os.Getenv("GITHUB_TOKEN")
+	if config, err := LoadConfig(); err == nil && config.GitHubToken != "" {
+		token = config.GitHubToken
+	}
+	if token == "" {
+		fmt.Fprintln(os.Stderr, `Error: No GitHub token: set "github_token" in ~/.buzzrc or $GITHUB_TOKEN`)
+		os.Exit(1)
+	}
+
+	code = runAutodataGitHubCommand(context.Background(), req, githubGraphQLURL, token, client, time.Now(), os.Stdout, os.Stderr)
+	if code == 0 {
+		fmt.Print(getUpdateMessage())
+	}
+	os.Exit(code)
+}
+
+// parseAutodataGitHubArgs parses and validates `buzz autodata github`
+// arguments, returning the request, a process exit code, and done=true when
+// the caller should stop (help shown, or a parse/validation error).
+func parseAutodataGitHubArgs(args []string, stdout, stderr io.Writer) (autodataGitHubRequest, int, bool) {
+	githubFlags := flag.NewFlagSet("autodata github", flag.ContinueOnError)
+	// Silence the flag package's own output; we print our own usage.
+	githubFlags.SetOutput(io.Discard)
+	goal := githubFlags.String("goal", "", "Goal to submit to")
+	user := githubFlags.String("user", "me", "GitHub login to count")
+	dryRun := dryRunFlag(githubFlags, "Print the count without submitting it")
+	if err := githubFlags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stdout, autodataGitHubUsage)
+			return autodataGitHubRequest{}, 0, true
+		}
+		fmt.Fprintf(stderr, "Error parsing flags: %s\n", redactError(err))
+		fmt.Fprintln(stderr, autodataGitHubUsage)
+		return autodataGitHubRequest{}, 2, true
+	}
+	if githubFlags.NArg() > 0 {
+		fmt.Fprintf(stderr, "Error: unexpected argument %q\n", githubFlags.Arg(0))
+		fmt.Fprintln(stderr, autodataGitHubUsage)
+		return autodataGitHubRequest{}, 1, true
+	}
+	if *goal == "" {
+		fmt.Fprintln(stderr, "Error: --goal is required")
+		fmt.Fprintln(stderr, autodataGitHubUsage)
+		return autodataGitHubRequest{}, 1, true
+	}
+
+	req := autodataGitHubRequest{goalSlug: *goal, user: *user, dryRun: *dryRun}
+	if req.user == "me" {
+		req.user = ""
+	}
+	return req, 0, false
+}
+
+// runAutodataGitHubCommand counts the user's contributions so far today and
+// submits the count. It returns the process exit code.
+func runAutodataGitHubCommand(ctx context.Context, req autodataGitHubRequest, apiURL, token string, client Client, now time.Time, stdout, stderr io.Writer) int {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	count, err := fetchGitHubContributions(ctx, apiURL, token, req.user, midnight, now)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to fetch GitHub contributions: %s\n", redactError(err))
+		return 1
+	}
+	who := req.user
+	if who == "" {
+		who = "me"
+	}
+	comment := fmt.Sprintf("%d GitHub contributions by %s, via buzz", count, who)
+	requestID := autodataRequestID("github", now.Format("20060102"))
+	return submitAutodataValue(ctx, client, req.goalSlug, "github", float64(count), comment, requestID, req.dryRun, now, stdout, stderr)
+}
+
+// fetchGitHubContributions returns a GitHub user's contribution count between
+// from and to, or the token owner's when login is "".
+func fetchGitHubContributions(ctx context.Context, apiURL, token, login string, from, to time.Time) (int, error) {
+	const collection = `contributionsCollection(from: $from, to: $to) { contributionCalendar { totalContributions } }`
+	query := `query($from: DateTime!, $to: DateTime!) { viewer { ` + collection + ` } }`
+	variables := map[string]any{"from": from.Format(time.RFC3339), "to": to.Format(time.RFC3339)}
+	if login != "" {
+		query = `query($login: String!, $from: DateTime!, $to: DateTime!) { user(login: $login) { ` + collection + ` } }`
+		variables["login"] = login
+	}
+	body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "buzz-cli")
+
+	resp, err := (&http.Client{Timeout: httpClientTimeout}).Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		return 0, fmt.Errorf("GitHub rejected the token")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	}
+
+	type account struct {
+		ContributionsCollection struct {
+			ContributionCalendar struct {
+				TotalContributions int `json:"totalContributions"`
+			} `json:"contributionCalendar"`
+		} `json:"contributionsCollection"`
+	}
+	var result struct {
+		Data struct {
+			Viewer *account `json:"viewer"`
+			User   *account `json:"user"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("decoding GitHub response: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		return 0, fmt.Errorf("GitHub: %s", result.Errors[0].Message)
+	}
+	acct := result.Data.Viewer
+	if login != "" {
+		acct = result.Data.User
+	}
+	if acct == nil {
+		return 0, fmt.Errorf("GitHub user not found: %s", login)
+	}
+	return acct.ContributionsCollection.ContributionCalendar.TotalContributions, nil
+}
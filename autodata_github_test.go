@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseAutodataGitHubArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantDone bool
+		wantCode int
+		wantReq  autodataGitHubRequest
+		wantErr  string
+	}{
+		{name: "defaults to me", args: []string{"--goal", "commits"}, wantReq: autodataGitHubRequest{goalSlug: "commits"}},
+		{name: "named user, dry run", args: []string{"--user", "octocat", "--dry-run", "--goal", "commits"}, wantReq: autodataGitHubRequest{goalSlug: "commits", user: "octocat", dryRun: true}},
+		{name: "help", args: []string{"--help"}, wantDone: true},
+		{name: "bad flag", args: []string{"--nope"}, wantDone: true, wantCode: 2, wantErr: "Error parsing flags"},
+		{name: "missing goal", args: []string{"--user", "me"}, wantDone: true, wantCode: 1, wantErr: "--goal is required"},
+		{name: "stray argument", args: []string{"--goal", "commits", "extra"}, wantDone: true, wantCode: 1, wantErr: "unexpected argument"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errb bytes.Buffer
+			req, code, done := parseAutodataGitHubArgs(tt.args, &out, &errb)
+			if done != tt.wantDone || code != tt.wantCode {
+				t.Fatalf("done=%v code=%d, want done=%v code=%d (stderr=%q)", done, code, tt.wantDone, tt.wantCode, errb.String())
+			}
+			if !done && req != tt.wantReq {
+				t.Errorf("req = %+v, want %+v", req, tt.wantReq)
+			}
+			if !strings.Contains(errb.String(), tt.wantErr) {
+				t.Errorf("stderr = %q, want %q", errb.String(), tt.wantErr)
+			}
+		})
+	}
+}
+
+// githubServer serves a GraphQL contributions response, recording the last
+// request's body and Authorization header.
+func githubServer(t *testing.T, status int, response string) (*httptest.Server, *map[string]any, *string) {
+	t.Helper()
+	var gotBody map[string]any
+	var gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &gotBody, &gotAuth
+}
+
+func TestFetchGitHubContributions(t *testing.T) {
+	from := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	to := from.Add(9 * time.Hour)
+	calendar := `{"contributionsCollection":{"contributionCalendar":{"totalContributions":7}}}`
+
+	t.Run("viewer", func(t *testing.T) {
+		srv, body, auth := githubServer(t, http.StatusOK, `{"data":{"viewer":`+calendar+`}}`)
+		got, err := fetchGitHubContributions(context.Background(), srv.URL, "tok", "", from, to)
+		if err != nil || got != 7 {
+			t.Fatalf("got %d, %v; want 7", got, err)
+		}
+		if *auth != "Bearer tok" {
+			t.Errorf("Authorization = %q", *auth)
+		}
+		if q, _ := (*body)["query"].(string); !strings.Contains(q, "viewer") {
+			t.Errorf("query = %q, want a viewer query", q)
+		}
+		vars, _ := (*body)["variables"].(map[string]any)
+		if vars["from"] != "2026-03-10T00:00:00Z" || vars["to"] != "2026-03-10T09:00:00Z" {
+			t.Errorf("variables = %v", vars)
+		}
+	})
+
+	t.Run("named user", func(t *testing.T) {
+		srv, body, _ := githubServer(t, http.StatusOK, `{"data":{"user":`+calendar+`}}`)
+		got, err := fetchGitHubContributions(context.Background(), srv.URL, "tok", "octocat", from, to)
+		if err != nil || got != 7 {
+			t.Fatalf("got %d, %v; want 7", got, err)
+		}
+		if vars, _ := (*body)["variables"].(map[string]any); vars["login"] != "octocat" {
+			t.Errorf("variables = %v, want login octocat", vars)
+		}
+	})
+
+	errorCases := []struct {
+		name     string
+		status   int
+		response string
+		wantErr  string
+	}{
+		{"unknown user", http.StatusOK, `{"data":{"user":null},"errors":[{"message":"Could not resolve to a User"}]}`, "Could not resolve"},
+		{"bad token", http.StatusUnauthorized, `{}`, "rejected the token"},
+		{"server error", http.StatusBadGateway, ``, "status 502"},
+		{"no account", http.StatusOK, `{"data":{"user":null}}`, "not found"},
+	}
+	for _, tt := range errorCases {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, _, _ := githubServer(t, tt.status, tt.response)
+			_, err := fetchGitHubContributions(context.Background(), srv.URL, "tok", "nobody", from, to)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunAutodataGitHubCommand(t *testing.T) {
+	now := time.Date(2026, 3, 10, 15, 4, 0, 0, time.Local)
+	srv, _, _ := githubServer(t, http.StatusOK, `{"data":{"viewer":{"contributionsCollection":{"contributionCalendar":{"totalContributions":5}}}}}`)
+
+	t.Run("submits idempotently for today", func(t *testing.T) {
+		setHome(t, t.TempDir()) // contain createRefreshFlag's file write
+		var out, errb bytes.Buffer
+		var gotSlug, gotDaystamp, gotValue, gotReqID string
+		client := &FakeClient{
+			CreateDatapointWithDaystampFunc: func(slug, _, daystamp, value, _, requestid string) (*Datapoint, error) {
+				gotSlug, gotDaystamp, gotValue, gotReqID = slug, daystamp, value, requestid
+				return &Datapoint{}, nil
+			},
+		}
+		req := autodataGitHubRequest{goalSlug: "commits"}
+		code := runAutodataGitHubCommand(context.Background(), req, srv.URL, "tok", client, now, &out, &errb)
+		checkResult(t, code, out.String(), errb.String(), 0, "Submitted 5 to commits for 2026-03-10", "")
+		if gotSlug != "commits" || gotDaystamp != "20260310" || gotValue != "5" || gotReqID != "buzz-autodata-github-20260310" {
+			t.Errorf("slug=%q daystamp=%q value=%q requestid=%q", gotSlug, gotDaystamp, gotValue, gotReqID)
+		}
+	})
+
+	t.Run("dry run submits nothing", func(t *testing.T) {
+		var out, errb bytes.Buffer
+		req := autodataGitHubRequest{goalSlug: "commits", dryRun: true}
+		code := runAutodataGitHubCommand(context.Background(), req, srv.URL, "tok", &FakeClient{}, now, &out, &errb)
+		checkResult(t, code, out.String(), errb.String(), 0, "github: 5 (dry run", "")
+	})
+
+	t.Run("fetch error", func(t *testing.T) {
+		bad, _, _ := githubServer(t, http.StatusUnauthorized, `{}`)
+		var out, errb bytes.Buffer
+		code := runAutodataGitHubCommand(context.Background(), autodataGitHubRequest{goalSlug: "commits"}, bad.URL, "tok", &FakeClient{}, now, &out, &errb)
+		checkResult(t, code, out.String(), errb.String(), 1, "", "Failed to fetch GitHub contributions")
+	})
+}
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const autodataHTTPUsage = `Usage: buzz autodata http --url <url> --jsonpath <path> [--header 'Name: value']... [--dry-run] --goal <goalslug>
+  --url       URL to fetch; the response must be JSON
+  --jsonpath  Where the number is, e.g. $.count, $.data[0].total, or .stats.words
+  --header    Request header to send, e.g. 'Authorization: Bearer ...' (repeatable)
+  --goal      Goal to submit today's value to
+  --dry-run   Print the value without submitting it`
+
+// maxAutodataHTTPBody caps how much of a response `buzz autodata http` reads.
+const maxAutodataHTTPBody = 10 << 20
+
+// headerFlags collects repeated --header flags.
+type headerFlags []string
+
+func (h *headerFlags) String() string { return strings.Join(*h, ", ") }
+
+func (h *headerFlags) Set(s string) error {
+	name, _, ok := strings.Cut(s, ":")
+	if !ok || strings.TrimSpace(name) == "" {
+		return fmt.Errorf("header must look like 'Name: value', got %q", s)
+	}
+	*h = append(*h, s)
+	return nil
+}
+
+// autodataHTTPRequest is a parsed, validated `buzz autodata http` invocation.
+type autodataHTTPRequest struct {
+	goalSlug string
+	url      string
+	jsonPath []jsonPathStep
+	headers  []string // "Name: value"
+	dryRun   bool
+}
+
+// handleAutodataHTTPCommand fetches a number from a JSON URL and submits it.
+func handleAutodataHTTPCommand(args []string) {
+	req, code, done := parseAutodataHTTPArgs(args, os.Stdout, os.Stderr)
+	if done {
+		os.Exit(code)
+	}
+
+	client, ok := loadClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+
+	code = runAutodataHTTPCommand(context.Background(), req, client, time.Now(), os.Stdout, os.Stderr)
+	if code == 0 {
+		fmt.Print(getUpdateMessage())
+	}
+	os.Exit(code)
+}
+
+// parseAutodataHTTPArgs parses and validates `buzz autodata http` arguments,
+// returning the request, a process exit code, and done=true when the caller
+// should stop (help shown, or a parse/validation error).
+func parseAutodataHTTPArgs(args []string, stdout, stderr io.Writer) (autodataHTTPRequest, int, bool) {
+	httpFlags := flag.NewFlagSet("autodata http", flag.ContinueOnError)
+	// Silence the flag package's own output; we print our own usage.
+	httpFlags.SetOutput(io.Discard)
+	goal := httpFlags.String("goal", "", "Goal to submit to")
+	rawURL := httpFlags.String("url", "", "URL to fetch")
+	jsonPath := httpFlags.String("jsonpath", "", "Where the number is")
+	var headers headerFlags
+	httpFlags.Var(&headers, "header", "Request header to send")
+	dryRun := dryRunFlag(httpFlags, "Print the value without submitting it")
+	if err := httpFlags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stdout, autodataHTTPUsage)
+			return autodataHTTPRequest{}, 0, true
+		}
+		fmt.Fprintf(stderr, "Error parsing flags: %s\n", redactError(err))
+		fmt.Fprintln(stderr, autodataHTTPUsage)
+		return autodataHTTPRequest{}, 2, true
+	}
+	if httpFlags.NArg() > 0 {
+		fmt.Fprintf(stderr, "Error: unexpected argument %q\n", httpFlags.Arg(0))
+		fmt.Fprintln(stderr, autodataHTTPUsage)
+		return autodataHTTPRequest{}, 1, true
+	}
+	if *goal == "" || *rawURL == "" || *jsonPath == "" {
+		fmt.Fprintln(stderr, "Error: --url, --jsonpath, and --goal are required")
+		fmt.Fprintln(stderr, autodataHTTPUsage)
+		return autodataHTTPRequest{}, 1, true
+	}
+	if u, err := url.Parse(*rawURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		fmt.Fprintln(stderr, "Error: --url must be an http or https URL")
+		return autodataHTTPRequest{}, 1, true
+	}
+	steps, err := parseJSONPath(*jsonPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Invalid --jsonpath: %s\n", err)
+		return autodataHTTPRequest{}, 1, true
+	}
+
+	return autodataHTTPRequest{goalSlug: *goal, url: *rawURL, jsonPath: steps, headers: headers, dryRun: *dryRun}, 0, false
+}
+
+// runAutodataHTTPCommand fetches the URL, extracts the number, and submits it
+// as today's value. It returns the process exit code.
+func runAutodataHTTPCommand(ctx context.Context, req autodataHTTPRequest, client Client, now time.Time, stdout, stderr io.Writer) int {
+	doc, err := fetchJSON(ctx, req.url, req.headers)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to fetch %s: %s\n", urlOrigin(req.url), redactError(err))
+		return 1
+	}
+	value, err := jsonPathNumber(doc, req.jsonPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %s\n", err)
+		return 1
+	}
+
+	host := req.url
+	if u, err := url.Parse(req.url); err == nil {
+		host = u.Host
+	}
+	comment := fmt.Sprintf("%s from %s, via buzz", strconv.FormatFloat(value, 'f', -1, 64), host)
+	requestID := autodataRequestID("http", now.Format("20060102"))
+	return submitAutodataValue(ctx, client, req.goalSlug, "http", value, comment, requestID, req.dryRun, now, stdout, stderr)
+}
+
+// urlOrigin returns just the scheme and host of a URL, e.g.
+// "https://api.example.com", for messages: its path and query may hold an API
+// key.
+func urlOrigin(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "the URL"
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// withoutURL strips the request URL from a *url.Error, which repeats it in
+// full, query and all, leaving what went wrong.
+func withoutURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
+
+// fetchJSON GETs a URL with the given "Name: value" headers and decodes the
+// JSON response. Its errors never include the URL.
+func fetchJSON(ctx context.Context, rawURL string, headers []string) (any, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, withoutURL(err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "buzz-cli")
+	for _, h := range headers {
+		name, value, _ := strings.Cut(h, ":")
+		req.Header.Set(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+
+	resp, err := (&http.Client{Timeout: httpClientTimeout}).Do(req)
+	if err != nil {
+		return nil, withoutURL(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("server returned status %d", resp.StatusCode)
+	}
+
+	var doc any
+	decoder := json.NewDecoder(io.LimitReader(resp.Body, maxAutodataHTTPBody))
+	decoder.UseNumber()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("response is not JSON: %w", err)
+	}
+	return doc, nil
+}
+
+// jsonPathStep is one step of a JSONPath: an object key, or an array index
+// when key is "" (negative indexes count from the end).
+type jsonPathStep struct {
+	key   string
+	index int
+}
+
+// parseJSONPath parses the subset of JSONPath (and jq) that picks out a single
+// value: an optional leading "$", then any mix of .key, ["key"], ['key'], and
+// [index] steps. "$" or "." alone is the whole document.
+func parseJSONPath(s string) ([]jsonPathStep, error) {
+	p := strings.TrimSpace(s)
+	p = strings.TrimPrefix(p, "$")
+	if p == "." {
+		return nil, nil
+	}
+	if p != "" && p[0] != '.' && p[0] != '[' {
+		// A bare first key, as in "count" or "data[0]".
+		p = "." + p
+	}
+
+	var steps []jsonPathStep
+	for p != "" {
+		switch p[0] {
+		case '.':
+			p = p[1:]
+			if strings.HasPrefix(p, "[") {
+				// jq's .[0] and .["key"]
+				continue
+			}
+			end := strings.IndexAny(p, ".[")
+			if end < 0 {
+				end = len(p)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("empty key in %q", s)
+			}
+			steps = append(steps, jsonPathStep{key: p[:end]})
+			p = p[end:]
+		case '[':
+			end := strings.Index(p, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed [ in %q", s)
+			}
+			inner := strings.TrimSpace(p[1:end])
+			p = p[end+1:]
+			if len(inner) >= 2 && (inner[0] == '"' || inner[0] == '\'') && inner[len(inner)-1] == inner[0] {
+				steps = append(steps, jsonPathStep{key: inner[1 : len(inner)-1]})
+				continue
+			}
+			index, err := strconv.Atoi(inner)
+			if err != nil {
+				return nil, fmt.Errorf("[%s] is neither an index nor a quoted key in %q", inner, s)
+			}
+			steps = append(steps, jsonPathStep{index: index})
+		default:
+			return nil, fmt.Errorf("unexpected %q in %q", p[0], s)
+		}
+	}
+	return steps, nil
+}
+
+// jsonPathNumber follows steps into a decoded JSON document (decoded with
+// UseNumber) and returns the number there. A string holding a number counts.
+func jsonPathNumber(doc any, steps []jsonPathStep) (float64, error) {
+	at := "$"
+	v := doc
+	for _, step := range steps {
+		switch node := v.(type) {
+		case map[string]any:
+			if step.key == "" {
+				return 0, fmt.Errorf("%s is an object, not an array", at)
+			}
+			next, ok := node[step.key]
+			if !ok {
+				return 0, fmt.Errorf("%s has no key %q", at, step.key)
+			}
+			v = next
+			at += "." + step.key
+		case []any:
+			if step.key != "" {
+				return 0, fmt.Errorf("%s is an array, not an object", at)
+			}
+			i := step.index
+			if i < 0 {
+				i += len(node)
+			}
+			if i < 0 || i >= len(node) {
+				return 0, fmt.Errorf("%s has no index %d (length %d)", at, step.index, len(node))
+			}
+			v = node[i]
+			at += "[" + strconv.Itoa(step.index) + "]"
+		default:
+			return 0, fmt.Errorf("%s is %s, so it has nothing inside", at, jsonKind(v))
+		}
+	}
+
+	switch n := v.(type) {
+	case json.Number:
+		return n.Float64()
+	case string:
+		if f, err := strconv.ParseFloat(strings.TrimSpace(n), 64); err == nil {
+			return f, nil
+		}
+	}
+	return 0, fmt.Errorf("%s is %s, not a number", at, jsonKind(v))
+}
+
+// jsonKind names a decoded JSON value's type for error messages.
+func jsonKind(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "a boolean"
+	case string:
+		return fmt.Sprintf("the string %q", v)
+	case []any:
+		return "an array"
+	case map[string]any:
+		return "an object"
+	}
+	return "a number"
+}
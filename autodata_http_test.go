@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseJSONPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    []jsonPathStep
+		wantErr bool
+	}{
+		{path: "$", want: nil},
+		{path: ".", want: nil},
+		{path: "$.count", want: []jsonPathStep{{key: "count"}}},
+		{path: ".count", want: []jsonPathStep{{key: "count"}}},
+		{path: "count", want: []jsonPathStep{{key: "count"}}},
+		{path: "$.data[0].total", want: []jsonPathStep{{key: "data"}, {index: 0}, {key: "total"}}},
+		{path: ".[-1]", want: []jsonPathStep{{index: -1}}},
+		{path: `$.items[-1]["word count"]`, want: []jsonPathStep{{key: "items"}, {index: -1}, {key: "word count"}}},
+		{path: "$['a.b']", want: []jsonPathStep{{key: "a.b"}}},
+		{path: "$..count", wantErr: true},
+		{path: "$.data[0", wantErr: true},
+		{path: "$.data[*]", wantErr: true},
+		{path: "$.data[0]x", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := parseJSONPath(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseJSONPath(%q) = %+v, want %+v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONPathNumber(t *testing.T) {
+	const doc = `{"count": 12, "ratio": 0.5, "text": "42", "name": "x", "data": [{"total": 3}, {"total": 7}], "none": null}`
+	decoder := json.NewDecoder(strings.NewReader(doc))
+	decoder.UseNumber()
+	var v any
+	if err := decoder.Decode(&v); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path    string
+		want    float64
+		wantErr string
+	}{
+		{path: "$.count", want: 12},
+		{path: "$.ratio", want: 0.5},
+		{path: "$.text", want: 42},
+		{path: "$.data[1].total", want: 7},
+		{path: "$.data[-2].total", want: 3},
+		{path: "$", wantErr: "$ is an object, not a number"},
+		{path: "$.name", wantErr: `$.name is the string "x", not a number`},
+		{path: "$.none", wantErr: "$.none is null"},
+		{path: "$.missing", wantErr: `$ has no key "missing"`},
+		{path: "$.data[2]", wantErr: "$.data has no index 2 (length 2)"},
+		{path: "$.data.total", wantErr: "$.data is an array, not an object"},
+		{path: "$[0]", wantErr: "$ is an object, not an array"},
+		{path: "$.count.x", wantErr: "$.count is a number, so it has nothing inside"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			steps, err := parseJSONPath(tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := jsonPathNumber(v, steps)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("got %v, %v; want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAutodataHTTPArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantDone bool
+		wantCode int
+		wantErr  string
+	}{
+		{name: "valid", args: []string{"--url", "https://example.com/s.json", "--jsonpath", "$.count", "--header", "X-Key: k", "--goal", "x"}},
+		{name: "help", args: []string{"--help"}, wantDone: true},
+		{name: "bad header", args: []string{"--header", "nocolon"}, wantDone: true, wantCode: 2, wantErr: "Name: value"},
+		{name: "missing url", args: []string{"--jsonpath", "$.count", "--goal", "x"}, wantDone: true, wantCode: 1, wantErr: "are required"},
+		{name: "not http", args: []string{"--url", "file:///etc/passwd", "--jsonpath", "$.count", "--goal", "x"}, wantDone: true, wantCode: 1, wantErr: "http or https URL"},
+		{name: "bad path", args: []string{"--url", "https://example.com", "--jsonpath", "$.a[", "--goal", "x"}, wantDone: true, wantCode: 1, wantErr: "Invalid --jsonpath"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errb bytes.Buffer
+			req, code, done := parseAutodataHTTPArgs(tt.args, &out, &errb)
+			if done != tt.wantDone || code != tt.wantCode {
+				t.Fatalf("done=%v code=%d, want done=%v code=%d (stderr=%q)", done, code, tt.wantDone, tt.wantCode, errb.String())
+			}
+			if !strings.Contains(errb.String(), tt.wantErr) {
+				t.Errorf("stderr = %q, want %q", errb.String(), tt.wantErr)
+			}
+			if tt.name == "valid" && (req.goalSlug != "x" || len(req.jsonPath) != 1 || len(req.headers) != 1) {
+				t.Errorf("req = %+v", req)
+			}
+		})
+	}
+}
+
+func TestRunAutodataHTTPCommand(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	var gotHeader string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Get("X-Api-Key")
+		switch r.URL.Path {
+		case "/stats.json":
+			w.Write([]byte(`{"stats": {"count": 17}}`))
+		case "/html":
+			w.Write([]byte(`<html></html>`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	request := func(path, jsonPath string) autodataHTTPRequest {
+		steps, err := parseJSONPath(jsonPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return autodataHTTPRequest{goalSlug: "x", url: srv.URL + path, jsonPath: steps, headers: []string{"X-Api-Key: sekrit"}}
+	}
+
+	t.Run("submits the number", func(t *testing.T) {
+		setHome(t, t.TempDir()) // contain createRefreshFlag's file write
+		var out, errb bytes.Buffer
+		var gotValue, gotReqID string
+		client := &FakeClient{
+			CreateDatapointWithDaystampFunc: func(_, _, _, value, _, requestid string) (*Datapoint, error) {
+				gotValue, gotReqID = value, requestid
+				return &Datapoint{}, nil
+			},
+		}
+		code := runAutodataHTTPCommand(context.Background(), request("/stats.json", "$.stats.count"), client, now, &out, &errb)
+		checkResult(t, code, out.String(), errb.String(), 0, "Submitted 17 to x for 2026-03-10", "")
+		if gotValue != "17" || gotReqID != "buzz-autodata-http-20260310" {
+			t.Errorf("value=%q requestid=%q", gotValue, gotReqID)
+		}
+		if gotHeader != "sekrit" {
+			t.Errorf("X-Api-Key = %q, want the --header value", gotHeader)
+		}
+	})
+
+	errorCases := []struct {
+		name     string
+		path     string
+		jsonPath string
+		wantErr  string
+	}{
+		{"missing key", "/stats.json", "$.stats.words", `$.stats has no key "words"`},
+		{"not JSON", "/html", "$.count", "response is not JSON"},
+		{"not found", "/nope", "$.count", "status 404"},
+	}
+	for _, tt := range errorCases {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errb bytes.Buffer
+			code := runAutodataHTTPCommand(context.Background(), request(tt.path, tt.jsonPath), &FakeClient{}, now, &out, &errb)
+			checkResult(t, code, out.String(), errb.String(), 1, "", tt.wantErr)
+		})
+	}
+}
+
+func TestAutodataHTTPErrorsHideTheURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.Close() // every fetch fails to connect
+	steps, _ := parseJSONPath("$.count")
+	req := autodataHTTPRequest{goalSlug: "x", url: srv.URL + "/stats?api_key=sekrit", jsonPath: steps}
+
+	var out, errb bytes.Buffer
+	code := runAutodataHTTPCommand(context.Background(), req, &FakeClient{}, time.Now(), &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 1, "", "Failed to fetch "+srv.URL+": ")
+	if strings.Contains(errb.String(), "sekrit") || strings.Contains(errb.String(), "/stats") {
+		t.Errorf("error shows the URL's path or query: %s", errb.String())
+	}
+}
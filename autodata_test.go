@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAutodataWarning(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(d float64) int64 { return now.Add(-time.Duration(d * float64(24*time.Hour))).Unix() }
+
+	tests := []struct {
+		name string
+		goal Goal
+		want string
+	}{
+		{"manual goal", Goal{Lastday: daysAgo(10)}, ""},
+		{"no datapoints yet", Goal{Autodata: "ifttt"}, ""},
+		{"reported today", Goal{Autodata: "ifttt", Lastday: daysAgo(0.5)}, ""},
+		{"within slack", Goal{Autodata: "ifttt", Lastday: daysAgo(1.9)}, ""},
+		{"stale", Goal{Autodata: "ifttt", Lastday: daysAgo(3.2)}, "IFTTT hasn't reported in 3 days"},
+		{"unknown source keeps its name", Goal{Autodata: "beeminder_sms", Lastday: daysAgo(4)}, "beeminder_sms hasn't reported in 4 days"},
+		{"weekly source within cadence", Goal{Autodata: "withings", Lastday: daysAgo(6)}, ""},
+		{"weekly source stale", Goal{Autodata: "withings", Lastday: daysAgo(9)}, "Withings hasn't reported in 9 days"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := autodataWarning(tt.goal, now); got != tt.want {
+				t.Errorf("autodataWarning() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoalMarkers(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	stale := now.Add(-5 * 24 * time.Hour).Unix()
+
+	tests := []struct {
+		name string
+		goal Goal
+		want string
+	}{
+		{"plain", Goal{}, ""},
+		{"manual autodata value", Goal{Autodata: "manual"}, ""},
+		{"autodata", Goal{Autodata: "api", Lastday: now.Unix()}, autodataMarker},
+		{"stale autodata", Goal{Autodata: "api", Lastday: stale}, autodataStaleMarker},
+		{"secret autodata", Goal{Autodata: "api", Secret: true}, autodataMarker + secretMarker},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := goalMarkers(tt.goal, now); got != tt.want {
+				t.Errorf("goalMarkers() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
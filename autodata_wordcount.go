@@ -0,0 +1,375 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const autodataWordcountUsage = `Usage: buzz autodata wordcount --glob <pattern> [--mode total|delta] [--dry-run] --goal <goalslug>
+  --glob     Files to count, e.g. 'drafts/**/*.md' (** matches any number of directories)
+  --goal     Goal to submit the count to
+  --mode     total: submit the word count itself (default for odometer goals)
+             delta: submit the words added since the last run (default otherwise)
+  --dry-run  Print the count without submitting it`
+
+// autodataWordcountRequest is a parsed, validated `buzz autodata wordcount`
+// invocation.
+type autodataWordcountRequest struct {
+	goalSlug string
+	glob     string
+	mode     string // "total", "delta", or "" to pick from the goal type
+	dryRun   bool
+}
+
+// handleAutodataWordcountCommand counts the words in a set of files and
+// submits the count.
+func handleAutodataWordcountCommand(args []string) {
+	req, code, done := parseAutodataWordcountArgs(args, os.Stdout, os.Stderr)
+	if done {
+		os.Exit(code)
+	}
+
+	client, ok := loadClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+
+	code = runAutodataWordcountCommand(context.Background(), req, client, time.Now(), os.Stdout, os.Stderr)
+	if code == 0 {
+		fmt.Print(getUpdateMessage())
+	}
+	os.Exit(code)
+}
+
+// parseAutodataWordcountArgs parses and validates `buzz autodata wordcount`
+// arguments, returning the request, a process exit code, and done=true when
+// the caller should stop (help shown, or a parse/validation error).
+func parseAutodataWordcountArgs(args []string, stdout, stderr io.Writer) (autodataWordcountRequest, int, bool) {
+	wordcountFlags := flag.NewFlagSet("autodata wordcount", flag.ContinueOnError)
+	// Silence the flag package's own output; we print our own usage.
+	wordcountFlags.SetOutput(io.Discard)
+	goal := wordcountFlags.String("goal", "", "Goal to submit to")
+	glob := wordcountFlags.String("glob", "", "Files to count")
+	mode := wordcountFlags.String("mode", "", "total or delta")
+	dryRun := dryRunFlag(wordcountFlags, "Print the count without submitting it")
+	if err := wordcountFlags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stdout, autodataWordcountUsage)
+			return autodataWordcountRequest{}, 0, true
+		}
+		fmt.Fprintf(stderr, "Error parsing flags: %s\n", redactError(err))
+		fmt.Fprintln(stderr, autodataWordcountUsage)
+		return autodataWordcountRequest{}, 2, true
+	}
+	if wordcountFlags.NArg() > 0 {
+		fmt.Fprintf(stderr, "Error: unexpected argument %q\n", wordcountFlags.Arg(0))
+		fmt.Fprintln(stderr, autodataWordcountUsage)
+		return autodataWordcountRequest{}, 1, true
+	}
+	if *goal == "" || *glob == "" {
+		fmt.Fprintln(stderr, "Error: --goal and --glob are required")
+		fmt.Fprintln(stderr, autodataWordcountUsage)
+		return autodataWordcountRequest{}, 1, true
+	}
+	if *mode != "" && *mode != "total" && *mode != "delta" {
+		fmt.Fprintf(stderr, "Error: --mode must be total or delta, got: %s\n", *mode)
+		return autodataWordcountRequest{}, 1, true
+	}
+	if _, err := path.Match(filepath.ToSlash(*glob), ""); err != nil {
+		fmt.Fprintf(stderr, "Error: Invalid --glob pattern: %s\n", *glob)
+		return autodataWordcountRequest{}, 1, true
+	}
+
+	return autodataWordcountRequest{goalSlug: *goal, glob: *glob, mode: *mode, dryRun: *dryRun}, 0, false
+}
+
+// runAutodataWordcountCommand counts the words in the matching files and
+// submits the total, or the words added since the last run. It returns the
+// process exit code.
+func runAutodataWordcountCommand(ctx context.Context, req autodataWordcountRequest, client Client, now time.Time, stdout, stderr io.Writer) int {
+	mode := req.mode
+	if mode == "" {
+		goal, err := client.FetchGoal(ctx, req.goalSlug)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: Failed to fetch goal: %s\n", redactError(err))
+			return 1
+		}
+		mode = "delta"
+		if goal.GoalType == "biker" {
+			mode = "total"
+		}
+	}
+
+	files, err := globFiles(req.glob)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %s\n", redactError(err))
+		return 1
+	}
+	if len(files) == 0 {
+		fmt.Fprintf(stderr, "Error: No files match %s\n", req.glob)
+		return 1
+	}
+	words, err := countWords(files)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to count words: %s\n", redactError(err))
+		return 1
+	}
+
+	if mode == "total" {
+		comment := fmt.Sprintf("%d words in %d file(s), counted by buzz", words, len(files))
+		requestID := autodataRequestID("wordcount", now.Format("20060102"))
+		return submitAutodataValue(ctx, client, req.goalSlug, "wordcount", float64(words), comment, requestID, req.dryRun, now, stdout, stderr)
+	}
+
+	state, err := loadWordcountState()
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to read word count state: %s\n", redactError(err))
+		return 1
+	}
+	key, err := wordcountStateKey(req.goalSlug, req.glob)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %s\n", redactError(err))
+		return 1
+	}
+	last, seen := state[key]
+	if !seen {
+		if req.dryRun {
+			fmt.Fprintf(stdout, "wordcount: %d words, no baseline yet (dry run; nothing was recorded)\n", words)
+			return 0
+		}
+		if err := setWordcountState(key, words); err != nil {
+			fmt.Fprintf(stderr, "Error: Failed to save word count state: %s\n", redactError(err))
+			return 1
+		}
+		fmt.Fprintf(stdout, "Recorded a baseline of %d words for %s; later runs submit the words added since\n", words, req.goalSlug)
+		return 0
+	}
+
+	added := words - last
+	if added <= 0 {
+		if !req.dryRun && added < 0 {
+			// Words were cut: count from the shorter text so rewriting them
+			// counts again.
+			if err := setWordcountState(key, words); err != nil {
+				fmt.Fprintf(stderr, "Error: Failed to save word count state: %s\n", redactError(err))
+				return 1
+			}
+		}
+		fmt.Fprintf(stdout, "No new words since the last run (%d words now, %d then)\n", words, last)
+		return 0
+	}
+
+	// Each run's delta is its own datapoint, so two runs on the same day add
+	// up rather than replace each other; the counts in the request ID keep
+	// them apart, while a retry of the same delta (after the state failed to
+	// save, say) replaces it instead of adding it twice.
+	comment := fmt.Sprintf("%d new words (%d total), counted by buzz", added, words)
+	requestID := autodataRequestID("wordcount", fmt.Sprintf("%s-%d-%d", now.Format("20060102"), last, words))
+	if code := submitAutodataValue(ctx, client, req.goalSlug, "wordcount", float64(added), comment, requestID, req.dryRun, now, stdout, stderr); code != 0 || req.dryRun {
+		return code
+	}
+	if err := setWordcountState(key, words); err != nil {
+		// The datapoint is in; without the new baseline the next run would
+		// submit these words again, so say so loudly.
+		fmt.Fprintf(stderr, "Error: Failed to save word count state, so the next run will count these %d words again: %s\n", added, redactError(err))
+		return 1
+	}
+	return 0
+}
+
+// globFiles returns the regular files matching a glob pattern. Besides the
+// usual filepath.Match syntax, a "**" path segment matches any number of
+// directories, including none.
+func globFiles(pattern string) ([]string, error) {
+	if !strings.Contains(pattern, "**") {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		return regularFiles(matches), nil
+	}
+
+	segments := strings.Split(filepath.ToSlash(pattern), "/")
+	// Walk from the longest leading run of literal segments.
+	rootLen := 0
+	for rootLen < len(segments) && !strings.ContainsAny(segments[rootLen], `*?[\`) {
+		rootLen++
+	}
+	root := strings.Join(segments[:rootLen], "/")
+	if root == "" {
+		root = "."
+		if strings.HasPrefix(pattern, "/") {
+			root = "/"
+		}
+	}
+	rest := segments[rootLen:]
+
+	var matches []string
+	err := filepath.WalkDir(filepath.FromSlash(root), func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == filepath.FromSlash(root) && errors.Is(err, fs.ErrNotExist) {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(filepath.FromSlash(root), p)
+		if err != nil {
+			return err
+		}
+		if matchGlobSegments(rest, strings.Split(filepath.ToSlash(rel), "/")) {
+			matches = append(matches, p)
+		}
+		return nil
+	})
+	return matches, err
+}
+
+// matchGlobSegments reports whether a path's segments match a pattern's,
+// where a "**" pattern segment matches zero or more path segments.
+func matchGlobSegments(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if matchGlobSegments(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(name) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], name[0]); !ok {
+		return false
+	}
+	return matchGlobSegments(pattern[1:], name[1:])
+}
+
+// regularFiles filters paths down to regular files.
+func regularFiles(paths []string) []string {
+	var files []string
+	for _, p := range paths {
+		if info, err := os.Stat(p); err == nil && info.Mode().IsRegular() {
+			files = append(files, p)
+		}
+	}
+	return files
+}
+
+// countWords returns the total number of whitespace-separated words in files.
+func countWords(files []string) (int, error) {
+	total := 0
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return 0, err
+		}
+		total += len(strings.Fields(string(data)))
+	}
+	return total, nil
+}
+
+// getWordcountStatePath returns the path of the file recording each
+// delta-mode word count's last total.
+func getWordcountStatePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".buzz_wordcount_state"), nil
+}
+
+// wordcountStateKey identifies a goal and set of files in the word count
+// state. The pattern is made absolute so runs from other directories agree.
+func wordcountStateKey(goalSlug, glob string) (string, error) {
+	abs, err := filepath.Abs(glob)
+	if err != nil {
+		return "", err
+	}
+	return goalSlug + " " + filepath.ToSlash(abs), nil
+}
+
+// renameSlugInWordcountState moves the word counts recorded for oldSlug over
+// to newSlug, so the next delta-mode run on the renamed goal doesn't start
+// counting from scratch.
+func renameSlugInWordcountState(oldSlug, newSlug string) error {
+	statePath, err := getWordcountStatePath()
+	if err != nil {
+		return err
+	}
+	return updateFileAtomic(statePath, 0600, func(data []byte) ([]byte, error) {
+		state := map[string]int{}
+		if data == nil || json.Unmarshal(data, &state) != nil {
+			return nil, errUnchanged
+		}
+		changed := false
+		for key, words := range state {
+			if glob, ok := strings.CutPrefix(key, oldSlug+" "); ok {
+				delete(state, key)
+				state[newSlug+" "+glob] = words
+				changed = true
+			}
+		}
+		if !changed {
+			return nil, errUnchanged
+		}
+		return json.MarshalIndent(state, "", "  ")
+	})
+}
+
+// loadWordcountState loads the word count state from disk. A missing file is
+// not an error; it returns an empty state.
+func loadWordcountState() (map[string]int, error) {
+	statePath, err := getWordcountStatePath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(statePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]int{}, nil
+		}
+		return nil, err
+	}
+
+	state := map[string]int{}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// setWordcountState records words as the count for key, under the state
+// file's lock so runs for other goals at the same time aren't lost.
+func setWordcountState(key string, words int) error {
+	statePath, err := getWordcountStatePath()
+	if err != nil {
+		return err
+	}
+	return updateFileAtomic(statePath, 0600, func(data []byte) ([]byte, error) {
+		state := map[string]int{}
+		if data != nil {
+			if err := json.Unmarshal(data, &state); err != nil {
+				return nil, err
+			}
+		}
+		state[key] = words
+		return json.MarshalIndent(state, "", "  ")
+	})
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseAutodataWordcountArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantDone bool
+		wantCode int
+		wantReq  autodataWordcountRequest
+		wantErr  string
+	}{
+		{name: "defaults", args: []string{"--glob", "drafts/**/*.md", "--goal", "writing"}, wantReq: autodataWordcountRequest{goalSlug: "writing", glob: "drafts/**/*.md"}},
+		{name: "mode and dry run", args: []string{"--glob", "*.md", "--mode", "delta", "--dry-run", "--goal", "writing"}, wantReq: autodataWordcountRequest{goalSlug: "writing", glob: "*.md", mode: "delta", dryRun: true}},
+		{name: "help", args: []string{"--help"}, wantDone: true},
+		{name: "bad flag", args: []string{"--nope"}, wantDone: true, wantCode: 2, wantErr: "Error parsing flags"},
+		{name: "missing glob", args: []string{"--goal", "writing"}, wantDone: true, wantCode: 1, wantErr: "--goal and --glob are required"},
+		{name: "bad mode", args: []string{"--glob", "*.md", "--mode", "weekly", "--goal", "writing"}, wantDone: true, wantCode: 1, wantErr: "--mode must be total or delta"},
+		{name: "bad pattern", args: []string{"--glob", "[", "--goal", "writing"}, wantDone: true, wantCode: 1, wantErr: "Invalid --glob pattern"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errb bytes.Buffer
+			req, code, done := parseAutodataWordcountArgs(tt.args, &out, &errb)
+			if done != tt.wantDone || code != tt.wantCode {
+				t.Fatalf("done=%v code=%d, want done=%v code=%d (stderr=%q)", done, code, tt.wantDone, tt.wantCode, errb.String())
+			}
+			if !done && req != tt.wantReq {
+				t.Errorf("req = %+v, want %+v", req, tt.wantReq)
+			}
+			if !strings.Contains(errb.String(), tt.wantErr) {
+				t.Errorf("stderr = %q, want %q", errb.String(), tt.wantErr)
+			}
+		})
+	}
+}
+
+// writeFiles creates files (relative to dir) with the given contents.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestGlobFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"drafts/a.md":         "",
+		"drafts/b.txt":        "",
+		"drafts/ch1/c.md":     "",
+		"drafts/ch1/sec/d.md": "",
+		"notes/e.md":          "",
+	})
+	if err := os.Mkdir(filepath.Join(dir, "drafts", "dir.md"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{"drafts/*.md", []string{"drafts/a.md"}},
+		{"drafts/**/*.md", []string{"drafts/a.md", "drafts/ch1/c.md", "drafts/ch1/sec/d.md"}},
+		{"**/c.md", []string{"drafts/ch1/c.md"}},
+		{"drafts/**", []string{"drafts/a.md", "drafts/b.txt", "drafts/ch1/c.md", "drafts/ch1/sec/d.md"}},
+		{"missing/**/*.md", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			got, err := globFiles(filepath.Join(dir, tt.pattern))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var rel []string
+			for _, p := range got {
+				r, _ := filepath.Rel(dir, p)
+				rel = append(rel, filepath.ToSlash(r))
+			}
+			sort.Strings(rel)
+			if strings.Join(rel, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("globFiles(%q) = %v, want %v", tt.pattern, rel, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunAutodataWordcountCommand(t *testing.T) {
+	now := time.Date(2026, 3, 10, 21, 0, 0, 0, time.Local)
+
+	// run counts dir's Markdown files for the "writing" goal, returning the
+	// exit code, output, and the submitted value and request ID ("" if none).
+	run := func(t *testing.T, dir string, req autodataWordcountRequest, goalType string) (int, string, string, string, string) {
+		t.Helper()
+		var out, errb bytes.Buffer
+		var gotValue, gotReqID string
+		client := &FakeClient{
+			FetchGoalFunc: func(slug string) (*Goal, error) {
+				return &Goal{Slug: slug, GoalType: goalType}, nil
+			},
+			CreateDatapointWithDaystampFunc: func(_, _, _, value, _, requestid string) (*Datapoint, error) {
+				gotValue, gotReqID = value, requestid
+				return &Datapoint{}, nil
+			},
+		}
+		req.goalSlug = "writing"
+		req.glob = filepath.Join(dir, "**", "*.md")
+		code := runAutodataWordcountCommand(context.Background(), req, client, now, &out, &errb)
+		return code, out.String(), errb.String(), gotValue, gotReqID
+	}
+
+	t.Run("odometer goals submit the total", func(t *testing.T) {
+		setHome(t, t.TempDir())
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{"a.md": "one two three", "ch/b.md": "four\nfive"})
+		code, out, errOut, value, reqID := run(t, dir, autodataWordcountRequest{}, "biker")
+		checkResult(t, code, out, errOut, 0, "Submitted 5 to writing", "")
+		if value != "5" || reqID != "buzz-autodata-wordcount-20260310" {
+			t.Errorf("value=%q requestid=%q", value, reqID)
+		}
+	})
+
+	t.Run("other goals submit the delta", func(t *testing.T) {
+		setHome(t, t.TempDir())
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{"a.md": "one two three"})
+
+		code, out, errOut, value, _ := run(t, dir, autodataWordcountRequest{}, "hustler")
+		checkResult(t, code, out, errOut, 0, "Recorded a baseline of 3 words", "")
+		if value != "" {
+			t.Errorf("first run submitted %q, want nothing", value)
+		}
+
+		writeFiles(t, dir, map[string]string{"ch/b.md": "four five"})
+		code, out, errOut, _, _ = run(t, dir, autodataWordcountRequest{dryRun: true}, "hustler")
+		checkResult(t, code, out, errOut, 0, "wordcount: 2 (dry run", "")
+
+		code, out, errOut, value, reqID := run(t, dir, autodataWordcountRequest{}, "hustler")
+		checkResult(t, code, out, errOut, 0, "Submitted 2 to writing", "")
+		if value != "2" || reqID != "buzz-autodata-wordcount-20260310-3-5" {
+			t.Errorf("value=%q requestid=%q, want 2 with an ID from the day and counts", value, reqID)
+		}
+
+		code, out, errOut, value, _ = run(t, dir, autodataWordcountRequest{}, "hustler")
+		checkResult(t, code, out, errOut, 0, "No new words", "")
+		if value != "" {
+			t.Errorf("unchanged run submitted %q, want nothing", value)
+		}
+
+		// Cutting words lowers the baseline, so writing them again counts.
+		writeFiles(t, dir, map[string]string{"ch/b.md": ""})
+		code, out, errOut, _, _ = run(t, dir, autodataWordcountRequest{}, "hustler")
+		checkResult(t, code, out, errOut, 0, "No new words", "")
+		writeFiles(t, dir, map[string]string{"ch/b.md": "six"})
+		code, out, errOut, value, _ = run(t, dir, autodataWordcountRequest{}, "hustler")
+		checkResult(t, code, out, errOut, 0, "Submitted 1 to writing", "")
+		if value != "1" {
+			t.Errorf("value = %q, want 1", value)
+		}
+	})
+
+	t.Run("mode overrides the goal type", func(t *testing.T) {
+		setHome(t, t.TempDir())
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{"a.md": "one two"})
+		code, out, errOut, value, _ := run(t, dir, autodataWordcountRequest{mode: "total"}, "hustler")
+		checkResult(t, code, out, errOut, 0, "Submitted 2 to writing", "")
+		if value != "2" {
+			t.Errorf("value = %q, want 2", value)
+		}
+	})
+
+	t.Run("no matching files", func(t *testing.T) {
+		setHome(t, t.TempDir())
+		code, out, errOut, _, _ := run(t, t.TempDir(), autodataWordcountRequest{mode: "total"}, "biker")
+		checkResult(t, code, out, errOut, 1, "", "No files match")
+	})
+}
+
+func TestRenameSlugInWordcountState(t *testing.T) {
+	setHome(t, t.TempDir())
+	for key, words := range map[string]int{
+		"run /notes/*.md":    120,
+		"runner /notes/*.md": 40,
+		"write /book/*.md":   900,
+	} {
+		if err := setWordcountState(key, words); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := renameSlugInWordcountState("run", "jog"); err != nil {
+		t.Fatal(err)
+	}
+	state, err := loadWordcountState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int{"jog /notes/*.md": 120, "runner /notes/*.md": 40, "write /book/*.md": 900}
+	if !reflect.DeepEqual(state, want) {
+		t.Errorf("state = %v, want %v", state, want)
+	}
+}
@@ -15,6 +15,7 @@ type Goal struct {
 	Losedate    int64                 `json:"losedate"`
 	Pledge      float64               `json:"pledge"`
 	PledgeCap   *float64              `json:"pledge_cap"` // Pointer to handle null values from API
+	Contract    *Contract             `json:"contract"`   // The pledge at stake and any scheduled stepdown; null on some payloads
 	Safebuf     int                   `json:"safebuf"`
 	Limsum      string                `json:"limsum"`
 	Baremin     string                `json:"baremin"`
@@ -39,6 +40,22 @@ type Goal struct {
 	Roadall     [][]*float64          `json:"roadall"`    // Full piecewise bright line: rows of [t, v, r] with exactly one of v/r null per row (except the first row, which anchors the road start)
 	Dueby       map[string]DuebyEntry `json:"dueby"`      // Per-daystamp deltas/totals, pre-rounded to the goal's display precision. Keys are YYYYMMDD strings.
 	Datapoints  []Datapoint           `json:"datapoints,omitempty"`
+	Tags        []string              `json:"tags,omitempty"` // User-assigned goal tags
+	WeekendsOff bool                  `json:"weekends_off"`   // Whether Beeminder automatically schedules breaks on weekends
+	Secret      bool                  `json:"secret"`         // Whether the goal is hidden from everyone but its owner
+	DataPublic  bool                  `json:"datapublic"`     // Whether the goal's datapoints are publicly visible
+	Lastday     int64                 `json:"lastday"`        // Unix timestamp of the goal's most recent datapoint
+	UpdatedAt   int64                 `json:"updated_at"`     // Unix timestamp of the goal's last change, by any means
+
+	series   *chartSeries // Datapoints reduced for charting, set by prepareChart
+	warnings []string     // Fields that couldn't be read as sent, set by UnmarshalJSON
+}
+
+// Contract is a goal's `contract`: the amount at stake and, once a stepdown
+// has been requested, when the pledge drops.
+type Contract struct {
+	Amount     float64 `json:"amount"`
+	StepdownAt *int64  `json:"stepdown_at"` // Unix timestamp of the scheduled pledge decrease, or null
 }
 
 // DuebyEntry is one entry in a goal's `dueby` map, keyed by daystamp.
@@ -52,6 +69,16 @@ type DuebyEntry struct {
 	FormattedTotal string  `json:"formatted_total_for_beedroid"`
 }
 
+// User represents the parts of a Beeminder user `buzz whoami` shows
+type User struct {
+	Username     string   `json:"username"`
+	Timezone     string   `json:"timezone"`
+	UrgencyLoad  int      `json:"urgency_load"`
+	Subscription string   `json:"subscription"` // plan name, empty on the free plan
+	Goals        []string `json:"goals"`        // slugs of the active goals
+	Deadbeat     bool     `json:"deadbeat"`     // a failed payment is outstanding
+}
+
 // Datapoint represents a Beeminder datapoint
 type Datapoint struct {
 	ID        string  `json:"id"`
@@ -59,6 +86,7 @@ type Datapoint struct {
 	Daystamp  string  `json:"daystamp"`
 	Value     float64 `json:"value"`
 	Comment   string  `json:"comment"`
+	UpdatedAt int64   `json:"updated_at"` // Unix timestamp of the datapoint's creation or last change
 }
 
 // Charge represents a Beeminder charge response
@@ -276,3 +304,13 @@ func IsDoLessGoal(goal Goal) bool {
 	}
 	return false
 }
+
+// IsDoMoreGoal returns true if the goal is a do-more goal: the standard
+// "hustler" and "biker" types, or any goal with the do-more platonic
+// configuration (yaw = 1, dir = 1)
+func IsDoMoreGoal(goal Goal) bool {
+	if goal.GoalType == "hustler" || goal.GoalType == "biker" {
+		return true
+	}
+	return goal.Yaw == 1 && goal.Dir == 1
+}
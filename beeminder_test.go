@@ -1305,6 +1305,140 @@ func TestFetchArchivedGoals(t *testing.T) {
 	})
 }
 
+// TestFetchUser tests that FetchUser asks for the token's own user, whatever
+// the configured username.
+func TestFetchUser(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/users/me.json" {
+			t.Errorf("Expected path /api/v1/users/me.json, got %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("auth_token") != "testtoken" {
+			t.Errorf("Expected auth_token testtoken, got %q", r.URL.Query().Get("auth_token"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"username":"alice","timezone":"Europe/Paris","urgency_load":7,"subscription":"bee_plus","goals":["a","b","c"]}`))
+	}))
+	defer mockServer.Close()
+
+	config := &Config{Username: "testuser", AuthToken: "testtoken", BaseURL: mockServer.URL}
+	user, err := NewHTTPClient(config).FetchUser(context.Background())
+	if err != nil {
+		t.Fatalf("FetchUser failed: %v", err)
+	}
+	if user.Username != "alice" || user.Timezone != "Europe/Paris" || user.UrgencyLoad != 7 || user.Subscription != "bee_plus" || len(user.Goals) != 3 {
+		t.Errorf("FetchUser = %+v", user)
+	}
+}
+
+// TestFetchGoalSummaries tests that FetchGoalSummaries asks for the goal list
+// without roads.
+func TestFetchGoalSummaries(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/users/testuser/goals.json" {
+			t.Errorf("Expected the goals endpoint, got %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("emaciated") != "true" {
+			t.Errorf("Expected emaciated=true, got query %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]Goal{{Slug: "read", Safebuf: 3}})
+	}))
+	defer mockServer.Close()
+
+	config := &Config{Username: "testuser", AuthToken: "testtoken", BaseURL: mockServer.URL}
+
+	goals, err := NewHTTPClient(config).FetchGoalSummaries(context.Background())
+	if err != nil {
+		t.Fatalf("FetchGoalSummaries failed: %v", err)
+	}
+	if len(goals) != 1 || goals[0].Slug != "read" || goals[0].Safebuf != 3 {
+		t.Errorf("Expected the read goal, got %+v", goals)
+	}
+}
+
+// TestFetchDatapoints tests that FetchDatapoints asks the datapoints endpoint
+// for the newest count datapoints.
+func TestFetchDatapoints(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/users/testuser/goals/read/datapoints.json" {
+			t.Errorf("Expected the datapoints endpoint, got %s", r.URL.Path)
+		}
+		if q := r.URL.Query(); q.Get("count") != "5" || q.Get("sort") != "timestamp" {
+			t.Errorf("Expected count=5 and sort=timestamp, got query %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]Datapoint{{ID: "abc", Value: 2}})
+	}))
+	defer mockServer.Close()
+
+	config := &Config{Username: "testuser", AuthToken: "testtoken", BaseURL: mockServer.URL}
+
+	dps, err := NewHTTPClient(config).FetchDatapoints(context.Background(), "read", 5)
+	if err != nil {
+		t.Fatalf("FetchDatapoints failed: %v", err)
+	}
+	if len(dps) != 1 || dps[0].ID != "abc" {
+		t.Errorf("Expected the abc datapoint, got %+v", dps)
+	}
+}
+
+func TestUpdateDatapoint(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/api/v1/users/testuser/goals/read/datapoints/abc.json" {
+			t.Errorf("Expected a PUT to the datapoint, got %s %s", r.Method, r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		if r.PostForm.Get("value") != "4" || r.PostForm.Get("comment") != "" || r.PostForm.Has("timestamp") {
+			t.Errorf("Expected value=4 and an empty comment only, got %s", r.PostForm.Encode())
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Datapoint{ID: "abc", Value: 4})
+	}))
+	defer mockServer.Close()
+
+	config := &Config{Username: "testuser", AuthToken: "testtoken", BaseURL: mockServer.URL}
+
+	value, comment := "4", ""
+	dp, err := NewHTTPClient(config).UpdateDatapoint(context.Background(), "read", "abc", &value, &comment, nil)
+	if err != nil {
+		t.Fatalf("UpdateDatapoint failed: %v", err)
+	}
+	if dp.ID != "abc" || dp.Value != 4 {
+		t.Errorf("Expected the updated datapoint, got %+v", dp)
+	}
+}
+
+func TestDeleteDatapoint(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("Expected a DELETE, got %s", r.Method)
+		}
+		if r.URL.Path != "/api/v1/users/testuser/goals/read/datapoints/abc.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Datapoint{ID: "abc", Value: 2})
+	}))
+	defer mockServer.Close()
+
+	client := NewHTTPClient(&Config{Username: "testuser", AuthToken: "testtoken", BaseURL: mockServer.URL})
+
+	dp, err := client.DeleteDatapoint(context.Background(), "read", "abc")
+	if err != nil {
+		t.Fatalf("DeleteDatapoint failed: %v", err)
+	}
+	if dp.ID != "abc" {
+		t.Errorf("Expected the deleted datapoint, got %+v", dp)
+	}
+
+	if _, err := client.DeleteDatapoint(context.Background(), "read", "gone"); err == nil || !strings.Contains(err.Error(), "datapoint gone not found") {
+		t.Errorf("Expected a not-found error for a missing datapoint, got %v", err)
+	}
+}
+
 // TestRefreshGoalWithMockServer tests RefreshGoal function with a mock HTTP server
 func TestRefreshGoalWithMockServer(t *testing.T) {
 	// Test case 1: successful refresh (returns true)
@@ -2357,6 +2491,101 @@ func TestCallUncleWithMockServer(t *testing.T) {
 	})
 }
 
+// TestShortCircuitGoalWithMockServer tests ShortCircuitGoal against a mock HTTP server.
+func TestShortCircuitGoalWithMockServer(t *testing.T) {
+	t.Run("successful short-circuit", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				t.Errorf("Expected POST request, got %s", r.Method)
+			}
+			expectedPath := "/api/v1/users/testuser/goals/testgoal/shortcircuit.json"
+			if r.URL.Path != expectedPath {
+				t.Errorf("Expected path %s, got %s", expectedPath, r.URL.Path)
+			}
+			if r.URL.Query().Get("auth_token") != "testtoken" {
+				t.Errorf("Expected auth_token 'testtoken', got %s", r.URL.Query().Get("auth_token"))
+			}
+
+			goal := map[string]interface{}{
+				"slug":   "testgoal",
+				"pledge": 10,
+			}
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(goal)
+		}))
+		defer mockServer.Close()
+
+		config := &Config{
+			Username:  "testuser",
+			AuthToken: "testtoken",
+			BaseURL:   mockServer.URL,
+		}
+
+		goal, err := NewHTTPClient(config).ShortCircuitGoal(context.Background(), "testgoal")
+		if err != nil {
+			t.Fatalf("ShortCircuitGoal failed: %v", err)
+		}
+		if goal == nil || goal.Slug != "testgoal" || goal.Pledge != 10 {
+			t.Fatalf("Unexpected goal: %+v", goal)
+		}
+	})
+
+	t.Run("API error", func(t *testing.T) {
+		mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"errors": "No payment method"}`))
+		}))
+		defer mockServer.Close()
+
+		config := &Config{
+			Username:  "testuser",
+			AuthToken: "testtoken",
+			BaseURL:   mockServer.URL,
+		}
+
+		goal, err := NewHTTPClient(config).ShortCircuitGoal(context.Background(), "testgoal")
+		if err == nil {
+			t.Error("Expected error for non-200 status, got nil")
+		}
+		if goal != nil {
+			t.Errorf("Expected nil goal on error, got: %+v", goal)
+		}
+	})
+}
+
+// TestStepDownGoalWithMockServer tests StepDownGoal against a mock HTTP server.
+func TestStepDownGoalWithMockServer(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected POST request, got %s", r.Method)
+		}
+		expectedPath := "/api/v1/users/testuser/goals/testgoal/stepdown.json"
+		if r.URL.Path != expectedPath {
+			t.Errorf("Expected path %s, got %s", expectedPath, r.URL.Path)
+		}
+		if r.URL.Query().Get("auth_token") != "testtoken" {
+			t.Errorf("Expected auth_token 'testtoken', got %s", r.URL.Query().Get("auth_token"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"slug": "testgoal", "pledge": 10, "contract": {"amount": 10, "stepdown_at": 1705535940}}`))
+	}))
+	defer mockServer.Close()
+
+	config := &Config{
+		Username:  "testuser",
+		AuthToken: "testtoken",
+		BaseURL:   mockServer.URL,
+	}
+
+	goal, err := NewHTTPClient(config).StepDownGoal(context.Background(), "testgoal")
+	if err != nil {
+		t.Fatalf("StepDownGoal failed: %v", err)
+	}
+	if goal.Contract == nil || goal.Contract.StepdownAt == nil || *goal.Contract.StepdownAt != 1705535940 {
+		t.Fatalf("Unexpected contract: %+v", goal.Contract)
+	}
+}
+
 // TestRatchetGoalWithMockServer tests RatchetGoal against a mock HTTP server.
 func TestRatchetGoalWithMockServer(t *testing.T) {
 	t.Run("successful ratchet", func(t *testing.T) {
@@ -2641,3 +2870,35 @@ func TestFetchGoalsWithDatapointsManyGoals(t *testing.T) {
 		}
 	}
 }
+
+func TestUpdateGoalRoad(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("Expected PUT request, got %s", r.Method)
+		}
+		if r.URL.Path != "/api/v1/users/testuser/goals/testgoal.json" {
+			t.Errorf("Unexpected URL path: %s", r.URL.Path)
+		}
+		r.ParseForm()
+		if got, want := r.FormValue("roadall"), "[[1700000000,0,null],[1800000000,null,2]]"; got != want {
+			t.Errorf("Expected roadall %s, got %s", want, got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Goal{Slug: "testgoal"})
+	}))
+	defer mockServer.Close()
+
+	config := &Config{
+		Username:  "testuser",
+		AuthToken: "testtoken",
+		BaseURL:   mockServer.URL,
+	}
+	roadall := [][]*float64{roadallRow(1700000000, fptr(0), nil), roadallRow(1800000000, nil, fptr(2))}
+	goal, err := NewHTTPClient(config).UpdateGoalRoad(context.Background(), "testgoal", roadall)
+	if err != nil {
+		t.Fatalf("UpdateGoalRoad failed: %v", err)
+	}
+	if goal.Slug != "testgoal" {
+		t.Errorf("Expected slug 'testgoal', got %s", goal.Slug)
+	}
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// `buzz break` schedules a flat spot, e.g. for a vacation, the way
+// Beeminder's road editor does: the bright red line runs at the break's rate
+// (0 unless --rate says otherwise) between the two dates, then picks up where
+// it left off, so the rest of the road keeps its rates and runs that much
+// lower (or, for a do-less goal, higher).
+
+const breakUsage = `Usage: buzz break [--rate <rate>] [--yes|-y] <goalslug> <start> <end>
+  Flatten the goal's bright red line from <start> through <end> (YYYY-MM-DD).
+  The break must start on or after the akrasia horizon, a week from today.
+  --rate is in the goal's own rate units and defaults to 0.`
+
+// breakRequest holds the parsed `buzz break` arguments.
+type breakRequest struct {
+	goalSlug    string
+	start, end  time.Time // local midnights; end is the last day of the break
+	rate        float64
+	skipConfirm bool
+}
+
+// handleBreakCommand schedules a break on a goal.
+func handleBreakCommand() {
+	req, code, done := parseBreakArgs(os.Args[2:], os.Stdout, os.Stderr)
+	if done {
+		os.Exit(code)
+	}
+
+	client, ok := loadClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+
+	code = runBreakCommand(context.Background(), client, req, time.Now(), os.Stdin, os.Stdout, os.Stderr)
+	if code == 0 {
+		fmt.Print(getUpdateMessage())
+	}
+	os.Exit(code)
+}
+
+// parseBreakArgs parses `buzz break` arguments, returning the request, a
+// process exit code, and done=true when the caller should stop (help shown,
+// or a usage error). Flags may come before or after the goal and dates.
+func parseBreakArgs(args []string, stdout, stderr io.Writer) (breakRequest, int, bool) {
+	breakFlags := flag.NewFlagSet("break", flag.ContinueOnError)
+	// Silence the flag package's own output; we print our own usage.
+	breakFlags.SetOutput(io.Discard)
+	rate := breakFlags.String("rate", "0", "Rate during the break")
+	yes := breakFlags.Bool("yes", false, "Skip confirmation prompt")
+	yesShort := breakFlags.Bool("y", false, "Skip confirmation prompt (shorthand)")
+
+	// Re-parse after each positional, as dial does, so flags can follow them.
+	var positional []string
+	for remaining := args; ; {
+		if err := breakFlags.Parse(remaining); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				fmt.Fprintln(stdout, breakUsage)
+				return breakRequest{}, 0, true
+			}
+			fmt.Fprintf(stderr, "Error parsing flags: %s\n", err)
+			fmt.Fprintln(stderr, breakUsage)
+			return breakRequest{}, 2, true
+		}
+		if breakFlags.NArg() == 0 {
+			break
+		}
+		positional = append(positional, breakFlags.Arg(0))
+		remaining = breakFlags.Args()[1:]
+	}
+	if len(positional) != 3 {
+		fmt.Fprintln(stderr, "Error: buzz break takes a goal, a start date, and an end date")
+		fmt.Fprintln(stderr, breakUsage)
+		return breakRequest{}, 1, true
+	}
+
+	req := breakRequest{goalSlug: positional[0], skipConfirm: *yes || *yesShort}
+	var err error
+	if req.start, err = time.ParseInLocation("2006-01-02", positional[1], time.Local); err != nil {
+		fmt.Fprintf(stderr, "Error: invalid start date %q (want YYYY-MM-DD)\n", positional[1])
+		return breakRequest{}, 1, true
+	}
+	if req.end, err = time.ParseInLocation("2006-01-02", positional[2], time.Local); err != nil {
+		fmt.Fprintf(stderr, "Error: invalid end date %q (want YYYY-MM-DD)\n", positional[2])
+		return breakRequest{}, 1, true
+	}
+	if req.end.Before(req.start) {
+		fmt.Fprintln(stderr, "Error: the break must end on or after its start date")
+		return breakRequest{}, 1, true
+	}
+	if req.rate, err = strconv.ParseFloat(*rate, 64); err != nil {
+		fmt.Fprintf(stderr, "Error: invalid --rate %q (want a number)\n", *rate)
+		return breakRequest{}, 1, true
+	}
+	return req, 0, false
+}
+
+// breakRoad returns roadall with the line running at rate, in the goal's
+// runits, from start to end, and otherwise as before: rows during the break
+// are dropped, and value rows after it are moved by however much the break
+// moved the line, so the road after it keeps its shape.
+func breakRoad(roadall [][]*float64, runits string, rate float64, start, end time.Time) ([][]*float64, error) {
+	r, err := parseRoad(roadall, runits)
+	if err != nil {
+		return nil, err
+	}
+	if len(r) == 0 {
+		return nil, errors.New("the goal has no bright red line to break")
+	}
+	if !isKnownRunits(runits) {
+		return nil, fmt.Errorf("unknown rate units %q", runits)
+	}
+	s, e := float64(start.Unix()), float64(end.Unix())
+	if last := *roadall[len(roadall)-1][0]; e > last {
+		return nil, fmt.Errorf("the break runs past the goal's end (%s)", time.Unix(int64(last), 0).Format("2006-01-02"))
+	}
+
+	startV := r.valueAt(start)
+	endV := startV + ratePerDay(rate, runits)*(e-s)/86400
+	shift := r.valueAt(end) - endV
+
+	broken := [][]*float64{roadall[0]}
+	for _, row := range roadall[1:] {
+		if *row[0] < s {
+			broken = append(broken, row)
+		}
+	}
+	broken = append(broken, []*float64{&s, &startV, nil}, []*float64{&e, nil, &rate})
+	for _, row := range roadall[1:] {
+		if *row[0] <= e {
+			continue
+		}
+		if row[1] != nil {
+			v := *row[1] - shift
+			row = []*float64{row[0], &v, nil}
+		}
+		broken = append(broken, row)
+	}
+	return broken, nil
+}
+
+// describeRoad lists the bright red line's rates from now on, a line per
+// stretch at one rate, e.g. "2024-07-01 → 2024-07-15  0 pages / day".
+func describeRoad(r road, runits, gunits string, now time.Time) []string {
+	type stretch struct {
+		from, to float64
+		slope    float64
+	}
+	var stretches []stretch
+	for _, seg := range r {
+		if seg.endT <= float64(now.Unix()) || seg.endT == seg.startT {
+			continue
+		}
+		if n := len(stretches); n > 0 && math.Abs(stretches[n-1].slope-seg.slopePerDay) < 1e-9 {
+			stretches[n-1].to = seg.endT
+			continue
+		}
+		stretches = append(stretches, stretch{from: math.Max(seg.startT, float64(now.Unix())), to: seg.endT, slope: seg.slopePerDay})
+	}
+	lines := make([]string, len(stretches))
+	day := func(t float64) string { return time.Unix(int64(t), 0).Format("2006-01-02") }
+	for i, st := range stretches {
+		rate := st.slope / ratePerDay(1, runits)
+		lines[i] = fmt.Sprintf("%s → %s  %s", day(st.from), day(st.to), formatRate(rate, runits, gunits))
+	}
+	return lines
+}
+
+// runBreakCommand fetches the goal, previews its road with the break,
+// confirms on stdin unless skipConfirm is set, and updates the goal. It
+// returns the process exit code.
+func runBreakCommand(ctx context.Context, client Client, req breakRequest, now time.Time, stdin io.Reader, out, errOut io.Writer) int {
+	if horizon := akrasiaHorizon(now); req.start.Before(horizon) {
+		fmt.Fprintf(errOut, "Error: A break can't start before the akrasia horizon, %s\n", horizon.Format("2006-01-02"))
+		return 1
+	}
+	goal, err := client.FetchGoal(ctx, req.goalSlug)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: Failed to fetch goal: %s\n", redactError(err))
+		return 1
+	}
+
+	// The break covers its last day too, so it ends the midnight after.
+	roadall, err := breakRoad(goal.Roadall, goal.Runits, req.rate, req.start, req.end.AddDate(0, 0, 1))
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: Can't schedule a break on %s: %s\n", goal.Slug, err)
+		return 1
+	}
+	r, err := parseRoad(roadall, goal.Runits)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: Can't schedule a break on %s: %s\n", goal.Slug, err)
+		return 1
+	}
+	fmt.Fprintf(out, "%s's bright red line with the break:\n", goal.Slug)
+	for _, line := range describeRoad(r, goal.Runits, goal.Gunits, now) {
+		fmt.Fprintf(out, "  %s\n", line)
+	}
+
+	span := req.start.Format("Mon Jan 2") + " through " + req.end.Format("Mon Jan 2")
+	if !req.skipConfirm {
+		fmt.Fprintf(out, "Schedule a break on %s from %s? [y/N] ", goal.Slug, span)
+		// As with buzz dial, a piped "y" without a newline still confirms.
+		line, err := bufio.NewReader(stdin).ReadString('\n')
+		answer := strings.ToLower(strings.TrimSpace(line))
+		if err != nil && !errors.Is(err, io.EOF) || answer != "y" && answer != "yes" {
+			fmt.Fprintln(out, "Cancelled.")
+			return 0
+		}
+	}
+
+	if _, err := client.UpdateGoalRoad(ctx, goal.Slug, roadall); err != nil {
+		fmt.Fprintf(errOut, "Error: Failed to schedule break: %s\n", redactError(err))
+		return 1
+	}
+	fmt.Fprintf(out, "Scheduled a break on %s from %s.\n", goal.Slug, span)
+	return 0
+}
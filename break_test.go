@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseBreakArgs(t *testing.T) {
+	jul := func(d int) time.Time { return time.Date(2024, 7, d, 0, 0, 0, 0, time.Local) }
+	for _, tt := range []struct {
+		args     []string
+		want     breakRequest
+		wantCode int
+		wantDone bool
+	}{
+		{[]string{"reading", "2024-07-01", "2024-07-14"}, breakRequest{goalSlug: "reading", start: jul(1), end: jul(14)}, 0, false},
+		{[]string{"reading", "2024-07-01", "2024-07-01", "--rate", "0.5", "-y"}, breakRequest{goalSlug: "reading", start: jul(1), end: jul(1), rate: 0.5, skipConfirm: true}, 0, false},
+		{[]string{"reading", "2024-07-01"}, breakRequest{}, 1, true},
+		{[]string{"reading", "July 1", "2024-07-14"}, breakRequest{}, 1, true},
+		{[]string{"reading", "2024-07-14", "2024-07-01"}, breakRequest{}, 1, true},
+		{[]string{"reading", "2024-07-01", "2024-07-14", "--rate", "none"}, breakRequest{}, 1, true},
+		{[]string{"--nope"}, breakRequest{}, 2, true},
+		{[]string{"-h"}, breakRequest{}, 0, true},
+	} {
+		req, code, done := parseBreakArgs(tt.args, &bytes.Buffer{}, &bytes.Buffer{})
+		if !req.start.Equal(tt.want.start) || !req.end.Equal(tt.want.end) || req.goalSlug != tt.want.goalSlug ||
+			req.rate != tt.want.rate || req.skipConfirm != tt.want.skipConfirm || code != tt.wantCode || done != tt.wantDone {
+			t.Errorf("parseBreakArgs(%v) = %+v, %d, %v", tt.args, req, code, done)
+		}
+	}
+}
+
+func TestBreakRoad(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	unix := func(d int) float64 { return float64(day(d).Unix()) }
+	// 1/day from Jan 1 to Jan 20, then up to 40 by Jan 30.
+	roadall := [][]*float64{
+		roadallRow(unix(1), fptr(0), nil),
+		roadallRow(unix(20), nil, fptr(1)),
+		roadallRow(unix(30), fptr(39), nil),
+	}
+
+	got, err := breakRoad(roadall, "d", 0, day(10), day(15))
+	if err != nil {
+		t.Fatalf("breakRoad: %v", err)
+	}
+	r, err := parseRoad(got, "d")
+	if err != nil {
+		t.Fatalf("broken road doesn't parse: %v", err)
+	}
+	for _, tt := range []struct {
+		day  int
+		want float64
+	}{
+		{5, 4},   // untouched before the break
+		{10, 9},  // break starts
+		{15, 9},  // flat through the break
+		{20, 14}, // then on at 1/day
+		{30, 34}, // and the rest of the road five lower
+	} {
+		if v := r.valueAt(day(tt.day)); v != tt.want {
+			t.Errorf("valueAt(Jan %d) = %v, want %v", tt.day, v, tt.want)
+		}
+	}
+
+	if _, err := breakRoad(roadall, "d", 0, day(25), day(31)); err == nil || !strings.Contains(err.Error(), "past the goal's end") {
+		t.Errorf("breakRoad past the end: err = %v", err)
+	}
+}
+
+func TestRunBreakCommand(t *testing.T) {
+	now := time.Date(2024, 6, 20, 12, 0, 0, 0, time.Local)
+	start := float64(time.Date(2024, 6, 1, 0, 0, 0, 0, time.Local).Unix())
+	end := float64(time.Date(2024, 12, 31, 0, 0, 0, 0, time.Local).Unix())
+	var updated [][]*float64
+	client := &FakeClient{
+		FetchGoalFunc: func(slug string) (*Goal, error) {
+			return &Goal{Slug: slug, Runits: "d", Gunits: "pages",
+				Roadall: [][]*float64{roadallRow(start, fptr(0), nil), roadallRow(end, nil, fptr(1))}}, nil
+		},
+		UpdateGoalRoadFunc: func(slug string, roadall [][]*float64) (*Goal, error) {
+			updated = roadall
+			return &Goal{Slug: slug}, nil
+		},
+	}
+	run := func(req breakRequest, stdin string) (int, string, string) {
+		var out, errOut bytes.Buffer
+		code := runBreakCommand(context.Background(), client, req, now, strings.NewReader(stdin), &out, &errOut)
+		return code, out.String(), errOut.String()
+	}
+	jul := func(d int) time.Time { return time.Date(2024, 7, d, 0, 0, 0, 0, time.Local) }
+
+	code, out, errOut := run(breakRequest{goalSlug: "reading", start: jul(1), end: jul(14)}, "y\n")
+	checkResult(t, code, out, errOut, 0, "", "")
+	wantPreview := "reading's bright red line with the break:\n" +
+		"  2024-06-20 → 2024-07-01  1 pages / day\n" +
+		"  2024-07-01 → 2024-07-15  0 pages / day\n" +
+		"  2024-07-15 → 2024-12-31  1 pages / day\n"
+	if !strings.HasPrefix(out, wantPreview) || !strings.HasSuffix(out, "Scheduled a break on reading from Mon Jul 1 through Sun Jul 14.\n") {
+		t.Errorf("confirmed break: out %q", out)
+	}
+	if len(updated) != 4 {
+		t.Errorf("updated road has %d rows, want 4", len(updated))
+	}
+
+	updated = nil
+	code, out, errOut = run(breakRequest{goalSlug: "reading", start: jul(1), end: jul(14)}, "\n")
+	checkResult(t, code, out, errOut, 0, "", "")
+	if !strings.HasSuffix(out, "Cancelled.\n") || updated != nil {
+		t.Errorf("declined break: out %q, updated %v", out, updated)
+	}
+
+	code, out, errOut = run(breakRequest{goalSlug: "reading", start: jul(25).AddDate(0, -1, 0), end: jul(1), skipConfirm: true}, "")
+	checkResult(t, code, out, errOut, 1, "", "Error: A break can't start before the akrasia horizon, 2024-06-27\n")
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const bufferUsage = "Usage: buzz buffer"
+
+// bufferBarWidth is the length of the longest bar in `buzz buffer`.
+const bufferBarWidth = 40
+
+// bufferBucket is one bar of the safety-buffer histogram: the goals whose
+// safebuf falls in an urgency level's range.
+type bufferBucket struct {
+	Buffer string   `json:"buffer"` // the range in days, e.g. "0", "3-6", or "7+"
+	Goals  int      `json:"goals"`
+	Slugs  []string `json:"slugs"`
+
+	urgency Urgency
+}
+
+// bufferHistogram buckets goals by safety buffer, one bucket per urgency level
+// (0, 1, 2, 3-6, and 7+ days), most urgent first.
+func bufferHistogram(goals []Goal) []bufferBucket {
+	buckets := []bufferBucket{
+		{Buffer: "0", urgency: UrgencyOverdue},
+		{Buffer: "1", urgency: UrgencyDueToday},
+		{Buffer: "2", urgency: UrgencyDueTomorrow},
+		{Buffer: "3-6", urgency: UrgencyThisWeek},
+		{Buffer: "7+", urgency: UrgencyDistant},
+	}
+	for _, g := range goals {
+		b := &buckets[UrgencyFor(g.Safebuf)]
+		b.Goals++
+		b.Slugs = append(b.Slugs, g.Slug)
+	}
+	for i := range buckets {
+		if buckets[i].Slugs == nil {
+			buckets[i].Slugs = []string{} // marshal as [] rather than null
+		}
+	}
+	return buckets
+}
+
+// bufferLabel names a bucket's range for the histogram, e.g. "1 day".
+func (b bufferBucket) bufferLabel() string {
+	if b.Buffer == "1" {
+		return "1 day"
+	}
+	return b.Buffer + " days"
+}
+
+// renderBufferHistogram draws the histogram as coloured bars, scaled so the
+// largest bucket is bufferBarWidth long, with a count after each bar.
+func renderBufferHistogram(buckets []bufferBucket) string {
+	total, largest := 0, 0
+	for _, b := range buckets {
+		total += b.Goals
+		largest = max(largest, b.Goals)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Safety buffer across %d goals:\n", total)
+	for _, b := range buckets {
+		bar := 0
+		if largest > 0 {
+			bar = b.Goals * bufferBarWidth / largest
+		}
+		if b.Goals > 0 && bar == 0 {
+			bar = 1
+		}
+		fmt.Fprintf(&sb, "  %-8s %s %d\n", b.bufferLabel(), b.urgency.TextStyle().Render(strings.Repeat("█", bar)), b.Goals)
+	}
+	return sb.String()
+}
+
+// compactBufferHistogram is the one-line histogram for the TUI footer, e.g.
+// "Buffer 0:2 1:1 2:0 3-6:5 7+:9". With color set, each count is drawn in its
+// urgency colour.
+func compactBufferHistogram(goals []Goal, color bool) string {
+	parts := []string{"Buffer"}
+	for _, b := range bufferHistogram(goals) {
+		part := b.Buffer + ":" + strconv.Itoa(b.Goals)
+		if color {
+			part = b.urgency.TextStyle().Render(part)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, " ")
+}
+
+// handleBufferCommand prints a histogram of the goals by safety buffer.
+func handleBufferCommand() {
+	bufferFlags := flag.NewFlagSet("buffer", flag.ContinueOnError)
+	// Silence the flag package's own output; we print our own usage.
+	bufferFlags.SetOutput(io.Discard)
+	if err := bufferFlags.Parse(os.Args[2:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fmt.Println(bufferUsage)
+			return
+		}
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %s\n", err)
+		fmt.Fprintln(os.Stderr, bufferUsage)
+		os.Exit(2)
+	}
+	if bufferFlags.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Error: unexpected argument %q\n", bufferFlags.Arg(0))
+		fmt.Fprintln(os.Stderr, bufferUsage)
+		os.Exit(1)
+	}
+
+	client, ok := loadClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+
+	code := runBufferCommand(context.Background(), client, goalFilter, outputFormat, os.Stdout, os.Stderr)
+	if code == 0 && outputFormat == "table" {
+		fmt.Print(getUpdateMessage())
+	}
+	os.Exit(code)
+}
+
+// runBufferCommand fetches the goals and prints their safety-buffer histogram
+// in the given format. keep, when non-nil, is the global --filter. It returns
+// the process exit code.
+func runBufferCommand(ctx context.Context, client Client, keep func(Goal) bool, format string, out, errOut io.Writer) int {
+	goals, err := client.FetchGoals(ctx)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: Failed to fetch goals: %s\n", redactError(err))
+		return 1
+	}
+	buckets := bufferHistogram(keepGoals(goals, keep))
+
+	switch format {
+	case "jsonl":
+		err = writeJSONL(out, buckets)
+	case "json":
+		var b []byte
+		if b, err = json.MarshalIndent(buckets, "", "  "); err == nil {
+			fmt.Fprintln(out, string(b))
+		}
+	case "csv":
+		rows := make([][]string, len(buckets))
+		for i, b := range buckets {
+			rows[i] = []string{b.Buffer, strconv.Itoa(b.Goals), strings.Join(b.Slugs, " ")}
+		}
+		var rendered string
+		if rendered, err = encodeCSV([]string{"buffer", "goals", "slugs"}, rows); err == nil {
+			fmt.Fprint(out, rendered)
+		}
+	default:
+		fmt.Fprint(out, renderBufferHistogram(buckets))
+	}
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: %s\n", redactError(err))
+		return 1
+	}
+	return 0
+}
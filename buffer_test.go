@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func bufferTestGoals() []Goal {
+	return []Goal{
+		{Slug: "a", Safebuf: 0},
+		{Slug: "b", Safebuf: 0},
+		{Slug: "c", Safebuf: 1},
+		{Slug: "d", Safebuf: 4},
+		{Slug: "e", Safebuf: 6},
+		{Slug: "f", Safebuf: 30},
+	}
+}
+
+func TestBufferHistogram(t *testing.T) {
+	buckets := bufferHistogram(bufferTestGoals())
+	want := []struct {
+		buffer string
+		goals  int
+		slugs  string
+	}{{"0", 2, "a b"}, {"1", 1, "c"}, {"2", 0, ""}, {"3-6", 2, "d e"}, {"7+", 1, "f"}}
+	if len(buckets) != len(want) {
+		t.Fatalf("got %d buckets, want %d", len(buckets), len(want))
+	}
+	for i, w := range want {
+		b := buckets[i]
+		if b.Buffer != w.buffer || b.Goals != w.goals || strings.Join(b.Slugs, " ") != w.slugs {
+			t.Errorf("bucket %d = %+v, want %+v", i, b, w)
+		}
+	}
+
+	if got := compactBufferHistogram(bufferTestGoals(), false); got != "Buffer 0:2 1:1 2:0 3-6:2 7+:1" {
+		t.Errorf("compactBufferHistogram = %q", got)
+	}
+}
+
+func TestRunBufferCommand(t *testing.T) {
+	client := &FakeClient{FetchGoalsFunc: func() ([]Goal, error) { return bufferTestGoals(), nil }}
+
+	var out, errb bytes.Buffer
+	code := runBufferCommand(context.Background(), client, nil, "table", &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 0, "Safety buffer across 6 goals:", "")
+	for _, want := range []string{"0 days", "1 day ", "2 days    0", "7+ days"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output lacks %q:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	code = runBufferCommand(context.Background(), client, func(g Goal) bool { return g.Safebuf > 0 }, "csv", &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 0, "buffer,goals,slugs\n0,0,\n1,1,c\n2,0,\n3-6,2,d e\n7+,1,f\n", "")
+
+	out.Reset()
+	code = runBufferCommand(context.Background(), client, nil, "json", &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 0, `"buffer": "3-6"`, "")
+}
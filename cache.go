@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+)
+
+// cachingClient is a Client that caches goals fetched with their datapoints
+// and road (FetchGoalWithDatapoints), the heaviest payloads the TUI asks for,
+// so flipping back and forth between goals in the Goals and Review tabs
+// doesn't refetch identical data.
+//
+// An entry is dropped when buzz itself changes its goal through the client
+// (adding a datapoint, changing a setting, ...), and when a goal list fetched
+// later shows the goal has changed elsewhere: its updated_at or last
+// datapoint moved on. Every other call passes straight through.
+type cachingClient struct {
+	Client
+
+	mu      sync.Mutex
+	details map[string]*Goal // by slug
+}
+
+// newCachingClient wraps client with a goal-details cache.
+func newCachingClient(client Client) *cachingClient {
+	return &cachingClient{Client: client, details: map[string]*Goal{}}
+}
+
+// goalVersion identifies a revision of a goal in a goal list, for telling
+// whether cached details are still current.
+func goalVersion(g Goal) [2]int64 {
+	return [2]int64{g.UpdatedAt, g.Lastday}
+}
+
+// FetchGoalWithDatapoints returns the cached details for goalSlug, fetching
+// and caching them on a miss. Callers get their own deep copy of the Goal
+// (see cloneGoal), so nothing they do to it, or to its datapoints or road,
+// reaches the cache.
+func (c *cachingClient) FetchGoalWithDatapoints(ctx context.Context, goalSlug string) (*Goal, error) {
+	c.mu.Lock()
+	cached, ok := c.details[goalSlug]
+	c.mu.Unlock()
+	if ok {
+		return cloneGoal(cached), nil
+	}
+
+	goal, err := c.Client.FetchGoalWithDatapoints(ctx, goalSlug)
+	if err != nil || goal == nil {
+		return goal, err
+	}
+	c.mu.Lock()
+	c.details[goalSlug] = cloneGoal(goal)
+	c.mu.Unlock()
+	return goal, nil
+}
+
+// cloneGoal returns a copy of g that shares nothing a caller could change
+// with it: its slices, maps, and the values its pointers point at are copied
+// too.
+func cloneGoal(g *Goal) *Goal {
+	c := *g
+	c.PledgeCap = clonePointer(g.PledgeCap)
+	if g.Contract != nil {
+		contract := *g.Contract
+		contract.StepdownAt = clonePointer(g.Contract.StepdownAt)
+		c.Contract = &contract
+	}
+	c.Autoratchet = clonePointer(g.Autoratchet)
+	c.Rate = clonePointer(g.Rate)
+	c.Currate = clonePointer(g.Currate)
+	c.Rcur = clonePointer(g.Rcur)
+	c.Curval = clonePointer(g.Curval)
+	c.Goalval = clonePointer(g.Goalval)
+	c.Mathishard = clonePointers(g.Mathishard)
+	if g.Roadall != nil {
+		c.Roadall = make([][]*float64, len(g.Roadall))
+		for i, row := range g.Roadall {
+			c.Roadall[i] = clonePointers(row)
+		}
+	}
+	c.Dueby = maps.Clone(g.Dueby)
+	c.Datapoints = slices.Clone(g.Datapoints)
+	c.Tags = slices.Clone(g.Tags)
+	c.warnings = slices.Clone(g.warnings)
+	// series is shared: prepareChart replaces it rather than changing it.
+	return &c
+}
+
+// clonePointer returns a pointer to a copy of *p, or nil if p is.
+func clonePointer[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+// clonePointers returns a copy of ps pointing at copies of its values.
+func clonePointers[T any](ps []*T) []*T {
+	if ps == nil {
+		return nil
+	}
+	c := make([]*T, len(ps))
+	for i, p := range ps {
+		c[i] = clonePointer(p)
+	}
+	return c
+}
+
+// FetchGoals fetches the goal list and drops the cached details of goals that
+// have changed since, or are gone.
+func (c *cachingClient) FetchGoals(ctx context.Context) ([]Goal, error) {
+	goals, err := c.Client.FetchGoals(ctx)
+	if err != nil {
+		return goals, err
+	}
+	c.dropChanged(goals)
+	return goals, nil
+}
+
+// FetchGoalSummaries is FetchGoals for the goal list without roads.
+func (c *cachingClient) FetchGoalSummaries(ctx context.Context) ([]Goal, error) {
+	goals, err := c.Client.FetchGoalSummaries(ctx)
+	if err != nil {
+		return goals, err
+	}
+	c.dropChanged(goals)
+	return goals, nil
+}
+
+// dropChanged drops the cached details of goals that differ from their
+// revision in goals, or aren't in it.
+func (c *cachingClient) dropChanged(goals []Goal) {
+	current := make(map[string][2]int64, len(goals))
+	for _, g := range goals {
+		current[g.Slug] = goalVersion(g)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for slug, cached := range c.details {
+		if version, ok := current[slug]; !ok || version != goalVersion(*cached) {
+			delete(c.details, slug)
+		}
+	}
+}
+
+// invalidate drops the cached details for the given goals.
+func (c *cachingClient) invalidate(slugs ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, slug := range slugs {
+		delete(c.details, slug)
+	}
+}
+
+// The writes below change a goal's datapoints or road, so each drops that
+// goal's cached details, whether or not the write succeeded: a failed
+// request may still have reached Beeminder.
+
+func (c *cachingClient) CreateDatapoint(ctx context.Context, goalSlug, timestamp, value, comment, requestid string) (*Datapoint, error) {
+	defer c.invalidate(goalSlug)
+	return c.Client.CreateDatapoint(ctx, goalSlug, timestamp, value, comment, requestid)
+}
+
+func (c *cachingClient) CreateDatapointWithDaystamp(ctx context.Context, goalSlug, timestamp, daystamp, value, comment, requestid string) (*Datapoint, error) {
+	defer c.invalidate(goalSlug)
+	return c.Client.CreateDatapointWithDaystamp(ctx, goalSlug, timestamp, daystamp, value, comment, requestid)
+}
+
+func (c *cachingClient) UpdateDatapoint(ctx context.Context, goalSlug, id string, value, comment, timestamp *string) (*Datapoint, error) {
+	defer c.invalidate(goalSlug)
+	return c.Client.UpdateDatapoint(ctx, goalSlug, id, value, comment, timestamp)
+}
+
+func (c *cachingClient) DeleteDatapoint(ctx context.Context, goalSlug, id string) (*Datapoint, error) {
+	defer c.invalidate(goalSlug)
+	return c.Client.DeleteDatapoint(ctx, goalSlug, id)
+}
+
+func (c *cachingClient) CallUncle(ctx context.Context, goalSlug string) (*Goal, error) {
+	defer c.invalidate(goalSlug)
+	return c.Client.CallUncle(ctx, goalSlug)
+}
+
+func (c *cachingClient) ShortCircuitGoal(ctx context.Context, goalSlug string) (*Goal, error) {
+	defer c.invalidate(goalSlug)
+	return c.Client.ShortCircuitGoal(ctx, goalSlug)
+}
+
+func (c *cachingClient) RatchetGoal(ctx context.Context, goalSlug string, ratchet int) (*Goal, error) {
+	defer c.invalidate(goalSlug)
+	return c.Client.RatchetGoal(ctx, goalSlug, ratchet)
+}
+
+func (c *cachingClient) StepDownGoal(ctx context.Context, goalSlug string) (*Goal, error) {
+	defer c.invalidate(goalSlug)
+	return c.Client.StepDownGoal(ctx, goalSlug)
+}
+
+func (c *cachingClient) UpdateGoalDeadline(ctx context.Context, goalSlug string, deadline int) (*Goal, error) {
+	defer c.invalidate(goalSlug)
+	return c.Client.UpdateGoalDeadline(ctx, goalSlug, deadline)
+}
+
+func (c *cachingClient) UpdateGoalWeekendsOff(ctx context.Context, goalSlug string, weekendsOff bool) (*Goal, error) {
+	defer c.invalidate(goalSlug)
+	return c.Client.UpdateGoalWeekendsOff(ctx, goalSlug, weekendsOff)
+}
+
+func (c *cachingClient) UpdateGoalTags(ctx context.Context, goalSlug string, tags []string) (*Goal, error) {
+	defer c.invalidate(goalSlug)
+	return c.Client.UpdateGoalTags(ctx, goalSlug, tags)
+}
+
+func (c *cachingClient) UpdateGoalRoad(ctx context.Context, goalSlug string, roadall [][]*float64) (*Goal, error) {
+	defer c.invalidate(goalSlug)
+	return c.Client.UpdateGoalRoad(ctx, goalSlug, roadall)
+}
+
+func (c *cachingClient) RenameGoal(ctx context.Context, goalSlug, newSlug string) (*Goal, error) {
+	defer c.invalidate(goalSlug, newSlug)
+	return c.Client.RenameGoal(ctx, goalSlug, newSlug)
+}
+
+func (c *cachingClient) UpdateGoalVisibility(ctx context.Context, goalSlug string, secret, dataPublic *bool) (*Goal, error) {
+	defer c.invalidate(goalSlug)
+	return c.Client.UpdateGoalVisibility(ctx, goalSlug, secret, dataPublic)
+}
+
+func (c *cachingClient) UpdateGoalPledgeCap(ctx context.Context, goalSlug string, pledgeCap float64) (*Goal, error) {
+	defer c.invalidate(goalSlug)
+	return c.Client.UpdateGoalPledgeCap(ctx, goalSlug, pledgeCap)
+}
+
+func (c *cachingClient) RefreshGoal(ctx context.Context, goalSlug string) (bool, error) {
+	defer c.invalidate(goalSlug)
+	return c.Client.RefreshGoal(ctx, goalSlug)
+}
+
+// APIRequest can change anything, so any request but a GET empties the cache.
+func (c *cachingClient) APIRequest(ctx context.Context, method, path string, params url.Values) (int, []byte, error) {
+	if method != http.MethodGet {
+		defer func() {
+			c.mu.Lock()
+			clear(c.details)
+			c.mu.Unlock()
+		}()
+	}
+	return c.Client.APIRequest(ctx, method, path, params)
+}
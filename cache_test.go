@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestCachingClient(t *testing.T) {
+	ctx := context.Background()
+	fetches := map[string]int{}
+	goals := []Goal{{Slug: "read", UpdatedAt: 100}, {Slug: "write", UpdatedAt: 100}}
+	fake := &FakeClient{
+		FetchGoalWithDatapointsFunc: func(slug string) (*Goal, error) {
+			fetches[slug]++
+			for _, g := range goals {
+				if g.Slug == slug {
+					return &g, nil
+				}
+			}
+			return nil, errFakeNotConfigured
+		},
+		FetchGoalsFunc: func() ([]Goal, error) { return goals, nil },
+		CreateDatapointFunc: func(string, string, string, string, string) (*Datapoint, error) {
+			return &Datapoint{}, nil
+		},
+		APIRequestFunc: func(string, string, url.Values) (int, []byte, error) { return 200, nil, nil },
+	}
+	c := newCachingClient(fake)
+	fetch := func(slug string) *Goal {
+		t.Helper()
+		g, err := c.FetchGoalWithDatapoints(ctx, slug)
+		if err != nil {
+			t.Fatalf("FetchGoalWithDatapoints(%s): %v", slug, err)
+		}
+		return g
+	}
+
+	// A second fetch is served from the cache, as a copy of its own.
+	fetch("read").Title = "scribbled on"
+	if g := fetch("read"); fetches["read"] != 1 || g.Title != "" {
+		t.Fatalf("fetches = %d, title %q; want one fetch and an untouched copy", fetches["read"], g.Title)
+	}
+	if _, err := c.FetchGoalWithDatapoints(ctx, "gone"); err == nil {
+		t.Error("a failed fetch should report its error")
+	}
+
+	// Adding a datapoint drops that goal, and only that goal.
+	fetch("write")
+	if _, err := c.CreateDatapoint(ctx, "read", "1", "2", "", ""); err != nil {
+		t.Fatal(err)
+	}
+	fetch("read")
+	fetch("write")
+	if fetches["read"] != 2 || fetches["write"] != 1 {
+		t.Errorf("after an add to read, fetches = %v; want read refetched, write cached", fetches)
+	}
+
+	// A goal list showing a goal changed elsewhere drops it too.
+	goals[1].UpdatedAt = 200
+	if _, err := c.FetchGoals(ctx); err != nil {
+		t.Fatal(err)
+	}
+	fetch("read")
+	fetch("write")
+	if fetches["read"] != 2 || fetches["write"] != 2 {
+		t.Errorf("after write changed elsewhere, fetches = %v; want only write refetched", fetches)
+	}
+
+	// So does the goal list without roads, the TUI's.
+	goals[0].UpdatedAt = 300
+	if _, err := c.FetchGoalSummaries(ctx); err != nil {
+		t.Fatal(err)
+	}
+	fetch("read")
+	fetch("write")
+	if fetches["read"] != 3 || fetches["write"] != 2 {
+		t.Errorf("after read changed elsewhere, fetches = %v; want only read refetched", fetches)
+	}
+
+	// A raw GET leaves the cache alone; anything else empties it.
+	c.APIRequest(ctx, http.MethodGet, "users/me.json", nil)
+	fetch("read")
+	c.APIRequest(ctx, http.MethodPost, "users/me/goals/read/datapoints.json", nil)
+	fetch("read")
+	if fetches["read"] != 4 {
+		t.Errorf("read fetched %d times, want 4 (only after the POST)", fetches["read"])
+	}
+}
+
+func TestCachingClientCopiesDetailsDeeply(t *testing.T) {
+	ctx := context.Background()
+	c := newCachingClient(&FakeClient{
+		FetchGoalWithDatapointsFunc: func(slug string) (*Goal, error) {
+			v, rate := 10.0, 1.0
+			return &Goal{
+				Slug:       slug,
+				Rate:       &rate,
+				Roadall:    [][]*float64{{&v, nil, &rate}},
+				Dueby:      map[string]DuebyEntry{"20240115": {Delta: 1}},
+				Datapoints: []Datapoint{{ID: "dp1", Value: 1}},
+				Tags:       []string{"work"},
+			}, nil
+		},
+	})
+
+	// Scribble on both the fetched goal and a cached copy.
+	for range 2 {
+		g, err := c.FetchGoalWithDatapoints(ctx, "read")
+		if err != nil {
+			t.Fatal(err)
+		}
+		*g.Rate = 99
+		*g.Roadall[0][0] = 99
+		g.Dueby["20240115"] = DuebyEntry{Delta: 99}
+		g.Datapoints[0].Value = 99
+		g.Tags[0] = "scribbled"
+	}
+
+	g, err := c.FetchGoalWithDatapoints(ctx, "read")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *g.Rate != 1 || *g.Roadall[0][0] != 10 || g.Dueby["20240115"].Delta != 1 || g.Datapoints[0].Value != 1 || g.Tags[0] != "work" {
+		t.Errorf("cached goal = %+v (rate %v, road %v, datapoints %v); want it untouched", g, *g.Rate, *g.Roadall[0][0], g.Datapoints)
+	}
+}
+
+func TestReviewDropsStaleDetails(t *testing.T) {
+	m := initialReviewModel(nil, &Config{})
+	m.client = &FakeClient{FetchGoalWithDatapointsFunc: func(slug string) (*Goal, error) { return &Goal{Slug: slug}, nil }}
+	m.details["read"] = &Goal{Slug: "read", Lastday: 1}
+	m.details["write"] = &Goal{Slug: "write", Lastday: 1}
+
+	m.setGoals([]Goal{{Slug: "read", Lastday: 2}, {Slug: "write", Lastday: 1}})
+	if _, ok := m.details["read"]; ok {
+		t.Error("read has a newer datapoint; its details should be dropped")
+	}
+	if _, ok := m.details["write"]; !ok {
+		t.Error("write is unchanged; its details should be kept")
+	}
+}
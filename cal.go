@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const calUsage = `Usage: buzz cal [days]
+  Show a calendar of the next days (default 7; e.g. 14 or 30) with the goals
+  that derail each day.`
+
+// defaultCalDays is how many days `buzz cal` shows without an argument.
+const defaultCalDays = 7
+
+// maxCalDays caps the calendar, since a goal has one upcoming derail date and
+// the rest of the grid would be empty.
+const maxCalDays = 62
+
+// calCellWidth is the width of a day's column in the calendar grid.
+const calCellWidth = 12
+
+// calDay is one day of the calendar and the goals that derail on it.
+type calDay struct {
+	Date  string   `json:"date"` // YYYY-MM-DD
+	Slugs []string `json:"slugs"`
+
+	day   time.Time
+	goals []Goal
+}
+
+// handleCalCommand shows the upcoming derail dates as a calendar.
+func handleCalCommand() {
+	days, code, done := parseCalArgs(os.Args[2:], os.Stdout, os.Stderr)
+	if done {
+		os.Exit(code)
+	}
+
+	client, ok := loadClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+
+	code = runCalCommand(context.Background(), client, days, goalFilter, outputFormat, time.Now(), os.Stdout, os.Stderr)
+	if code == 0 && outputFormat == "table" {
+		fmt.Print(getUpdateMessage())
+	}
+	os.Exit(code)
+}
+
+// parseCalArgs parses `buzz cal` arguments, returning the number of days to
+// show, a process exit code, and done=true when the caller should stop (help
+// shown, or a usage error).
+func parseCalArgs(args []string, stdout, stderr io.Writer) (int, int, bool) {
+	calFlags := flag.NewFlagSet("cal", flag.ContinueOnError)
+	// Silence the flag package's own output; we print our own usage.
+	calFlags.SetOutput(io.Discard)
+	if err := calFlags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stdout, calUsage)
+			return 0, 0, true
+		}
+		fmt.Fprintf(stderr, "Error parsing flags: %s\n", err)
+		fmt.Fprintln(stderr, calUsage)
+		return 0, 2, true
+	}
+	if calFlags.NArg() > 1 {
+		fmt.Fprintf(stderr, "Error: unexpected argument %q\n", calFlags.Arg(1))
+		fmt.Fprintln(stderr, calUsage)
+		return 0, 1, true
+	}
+	if calFlags.NArg() == 0 {
+		return defaultCalDays, 0, false
+	}
+	days, err := strconv.Atoi(strings.TrimSuffix(calFlags.Arg(0), "d"))
+	if err != nil || days < 1 || days > maxCalDays {
+		fmt.Fprintf(stderr, "Error: invalid number of days %q (want 1 to %d)\n", calFlags.Arg(0), maxCalDays)
+		fmt.Fprintln(stderr, calUsage)
+		return 0, 1, true
+	}
+	return days, 0, false
+}
+
+// derailDay is the calendar day g derails on. A deadline after midnight
+// (a night-owl deadline, e.g. 3am) belongs to the day before, as Beeminder
+// counts it.
+func derailDay(g Goal, loc *time.Location) time.Time {
+	losedate := g.Losedate
+	if g.Deadline > 0 {
+		losedate -= int64(g.Deadline) + 1 // a second before the day ends
+	}
+	t := time.Unix(losedate, 0).In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// calendarDays returns the days from today for days days, each with the goals
+// that derail on it, soonest deadline first. Goals already past their
+// deadline are shown on today; goals at their end value are left out.
+func calendarDays(goals []Goal, days int, now time.Time) []calDay {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	cal := make([]calDay, days)
+	for i := range cal {
+		day := today.AddDate(0, 0, i)
+		cal[i] = calDay{Date: day.Format("2006-01-02"), Slugs: []string{}, day: day}
+	}
+	sorted := append([]Goal(nil), filterOutEndValueReached(goals)...)
+	SortGoals(sorted)
+	for _, g := range sorted {
+		day := derailDay(g, now.Location())
+		if day.Before(today) {
+			day = today
+		}
+		for i := range cal {
+			if cal[i].day.Equal(day) {
+				cal[i].Slugs = append(cal[i].Slugs, g.Slug)
+				cal[i].goals = append(cal[i].goals, g)
+				break
+			}
+		}
+	}
+	return cal
+}
+
+// renderCalendar draws the days as a Monday-first grid, a row per week, with
+// each goal in its urgency colour. Days outside the range are left blank.
+func renderCalendar(cal []calDay) string {
+	rule := func(left, mid, right string) string {
+		cells := make([]string, 7)
+		for i := range cells {
+			cells[i] = strings.Repeat("─", calCellWidth)
+		}
+		return left + strings.Join(cells, mid) + right + "\n"
+	}
+
+	first := weekStart(cal[0].day, 0)
+	last := cal[len(cal)-1].day
+	var sb strings.Builder
+	sb.WriteString(rule("┌", "┬", "┐"))
+	for week := first; !week.After(last); week = week.AddDate(0, 0, 7) {
+		if week != first {
+			sb.WriteString(rule("├", "┼", "┤"))
+		}
+		var days [7]*calDay
+		lines := 0
+		for i := range days {
+			offset := int(math.Round(week.AddDate(0, 0, i).Sub(cal[0].day).Hours() / 24))
+			if offset >= 0 && offset < len(cal) {
+				days[i] = &cal[offset]
+				lines = max(lines, len(days[i].goals))
+			}
+		}
+		for line := -1; line < lines; line++ {
+			sb.WriteString("│")
+			for _, d := range days {
+				cell := strings.Repeat(" ", calCellWidth)
+				switch {
+				case d == nil:
+				case line < 0:
+					cell = truncateString(" "+d.day.Format("Mon Jan 2"), calCellWidth)
+				case line < len(d.goals):
+					g := d.goals[line]
+					cell = UrgencyFor(g.Safebuf).TextStyle().Render(truncateString(" "+g.Slug, calCellWidth))
+				}
+				sb.WriteString(cell + "│")
+			}
+			sb.WriteString("\n")
+		}
+	}
+	sb.WriteString(rule("└", "┴", "┘"))
+	return sb.String()
+}
+
+// runCalCommand fetches the goals and prints the calendar of the next days.
+// It returns the process exit code.
+func runCalCommand(ctx context.Context, client Client, days int, keep func(Goal) bool, format string, now time.Time, out, errOut io.Writer) int {
+	goals, err := client.FetchGoals(ctx)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: Failed to fetch goals: %s\n", redactError(err))
+		return 1
+	}
+	cal := calendarDays(keepGoals(goals, keep), days, now)
+
+	switch format {
+	case "jsonl":
+		err = writeJSONL(out, cal)
+	case "json":
+		var b []byte
+		if b, err = json.MarshalIndent(cal, "", "  "); err == nil {
+			fmt.Fprintln(out, string(b))
+		}
+	case "csv":
+		rows := make([][]string, len(cal))
+		for i, d := range cal {
+			rows[i] = []string{d.Date, strings.Join(d.Slugs, " ")}
+		}
+		var rendered string
+		if rendered, err = encodeCSV([]string{"date", "slugs"}, rows); err == nil {
+			fmt.Fprint(out, rendered)
+		}
+	default:
+		fmt.Fprint(out, renderCalendar(cal))
+	}
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: %s\n", redactError(err))
+		return 1
+	}
+	return 0
+}
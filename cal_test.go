@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCalArgs(t *testing.T) {
+	for _, tt := range []struct {
+		args     []string
+		want     int
+		wantCode int
+		wantDone bool
+	}{
+		{nil, 7, 0, false},
+		{[]string{"14"}, 14, 0, false},
+		{[]string{"30d"}, 30, 0, false},
+		{[]string{"0"}, 0, 1, true},
+		{[]string{"365"}, 0, 1, true},
+		{[]string{"week"}, 0, 1, true},
+		{[]string{"7", "14"}, 0, 1, true},
+		{[]string{"--help"}, 0, 0, true},
+	} {
+		days, code, done := parseCalArgs(tt.args, &bytes.Buffer{}, &bytes.Buffer{})
+		if days != tt.want || code != tt.wantCode || done != tt.wantDone {
+			t.Errorf("parseCalArgs(%v) = %d, %d, %v", tt.args, days, code, done)
+		}
+	}
+}
+
+func TestCalendarDays(t *testing.T) {
+	now := time.Date(2024, 1, 17, 9, 0, 0, 0, time.Local) // a Wednesday
+	at := func(day, hour int) int64 { return time.Date(2024, 1, day, hour, 0, 0, 0, time.Local).Unix() }
+	goals := []Goal{
+		{Slug: "water", Losedate: at(17, 23)},
+		{Slug: "late", Losedate: at(16, 23)},
+		{Slug: "owl", Losedate: at(19, 3), Deadline: 3 * 3600},
+		{Slug: "far", Losedate: at(30, 23)},
+	}
+	cal := calendarDays(goals, 7, now)
+	if len(cal) != 7 || cal[0].Date != "2024-01-17" {
+		t.Fatalf("cal = %+v", cal)
+	}
+	if got := strings.Join(cal[0].Slugs, ","); got != "late,water" {
+		t.Errorf("today = %s, want the overdue goal and today's", got)
+	}
+	if got := strings.Join(cal[1].Slugs, ","); got != "owl" {
+		t.Errorf("Jan 18 = %s, want the 3am deadline on the night before", got)
+	}
+
+	grid := renderCalendar(cal)
+	lines := strings.Split(strings.TrimSuffix(grid, "\n"), "\n")
+	if !strings.HasPrefix(lines[1], "│"+strings.Repeat(" ", calCellWidth)+"│"+strings.Repeat(" ", calCellWidth)+"│ Wed Jan 17") {
+		t.Errorf("first week should start on Monday with blanks before today:\n%s", grid)
+	}
+	if !strings.Contains(grid, "├") || !strings.Contains(grid, "Mon Jan 22") || strings.Contains(grid, "Wed Jan 24") {
+		t.Errorf("grid should span exactly the 7 days:\n%s", grid)
+	}
+}
+
+func TestRunCalCommand(t *testing.T) {
+	now := time.Date(2024, 1, 17, 9, 0, 0, 0, time.Local)
+	client := &FakeClient{FetchGoalsFunc: func() ([]Goal, error) {
+		return []Goal{{Slug: "water", Losedate: now.Add(3 * time.Hour).Unix()}}, nil
+	}}
+	var out, errb bytes.Buffer
+	code := runCalCommand(context.Background(), client, 2, nil, "csv", now, &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 0, "date,slugs\n2024-01-17,water\n2024-01-18,\n", "")
+
+	client.FetchGoalsFunc = nil
+	code = runCalCommand(context.Background(), client, 2, nil, "table", now, &out, &errb)
+	checkResult(t, code, "", errb.String(), 1, "", "Failed to fetch goals")
+}
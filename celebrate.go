@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// celebrateSetting is the config's celebrate setting (see applyCelebrate).
+var celebrateSetting string
+
+// applyCelebrate sets how buzz celebrates an add that takes a goal out of the
+// red: "" or "on" with a message, "bell" with a message and the terminal
+// bell, and "off" not at all.
+func applyCelebrate(setting string) {
+	celebrateSetting = setting
+}
+
+// celebrationsOn reports whether adds are celebrated.
+func celebrationsOn() bool {
+	return celebrateSetting != "off"
+}
+
+// celebrationFor returns the message celebrating adding value to goal on day
+// when that takes the goal out of the red, as simulateAdd estimates it, and ""
+// otherwise. goal must have been fetched with its datapoints.
+func celebrationFor(goal Goal, value float64, day, now time.Time) string {
+	if !celebrationsOn() || IsEndValueReached(goal) || UrgencyFor(goal.Safebuf) != UrgencyOverdue {
+		return ""
+	}
+	sim, err := simulateAdd(goal, value, day, now)
+	if err != nil || sim.bufferAfter < 1 {
+		return ""
+	}
+	msg := fmt.Sprintf("🎉 %s is out of the red!", goal.Slug)
+	if streak := loggingStreak(goal.Datapoints, day); streak > 1 {
+		msg += fmt.Sprintf(" That's a %d-day streak.", streak)
+	}
+	return msg
+}
+
+// loggingStreak counts the days in a row, ending on day, with a datapoint,
+// counting day itself as the one being added.
+func loggingStreak(datapoints []Datapoint, day time.Time) int {
+	logged := make(map[string]bool, len(datapoints))
+	for _, dp := range datapoints {
+		logged[dp.Daystamp] = true
+	}
+	streak := 1
+	for d := day.AddDate(0, 0, -1); logged[d.Format("20060102")]; d = d.AddDate(0, 0, -1) {
+		streak++
+	}
+	return streak
+}
+
+// addCelebration is celebrationFor the `buzz add` request, given the goal
+// fetchAddGoal fetched with its datapoints. Celebrating is a nicety, so no
+// goal (a failed fetch) or an unparsable value just means no celebration.
+func addCelebration(goal *Goal, req addRequest, now time.Time) string {
+	if goal == nil || !celebrationsOn() {
+		return ""
+	}
+	value, err := strconv.ParseFloat(req.value, 64)
+	if err != nil {
+		return ""
+	}
+	return celebrationFor(*goal, value, addDay(req, now), now)
+}
+
+// celebrate prints msg, if any, with the terminal bell when it's configured.
+func celebrate(out io.Writer, msg string) {
+	if msg == "" {
+		return
+	}
+	if celebrateSetting == "bell" {
+		msg += "\a"
+	}
+	fmt.Fprintln(out, msg)
+}
+
+// ringBellCmd rings the terminal bell when it's configured. It writes to
+// stderr so as not to disturb the TUI's rendering on stdout.
+func ringBellCmd() tea.Cmd {
+	if celebrateSetting != "bell" {
+		return nil
+	}
+	return func() tea.Msg {
+		fmt.Fprint(os.Stderr, "\a")
+		return nil
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+// setCelebrate sets the celebrate setting for the test.
+func setCelebrate(t *testing.T, setting string) {
+	t.Helper()
+	prev := celebrateSetting
+	applyCelebrate(setting)
+	t.Cleanup(func() { applyCelebrate(prev) })
+}
+
+func TestCelebrationFor(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	red := simulateTestGoal()
+	red.Safebuf = 0
+
+	if got := celebrationFor(red, 3, now, now); got != "🎉 reading is out of the red!" {
+		t.Errorf("out of the red: %q", got)
+	}
+	if got := celebrationFor(red, 0, now, now); got != "" {
+		t.Errorf("still in the red: %q, want no celebration", got)
+	}
+	if got := celebrationFor(simulateTestGoal(), 3, now, now); got != "" {
+		t.Errorf("already safe: %q, want no celebration", got)
+	}
+
+	red.Datapoints = append(red.Datapoints,
+		Datapoint{Daystamp: "20260308", Value: 1},
+		Datapoint{Daystamp: "20260309", Value: 1},
+	)
+	if got := celebrationFor(red, 3, now, now); got != "🎉 reading is out of the red! That's a 4-day streak." {
+		t.Errorf("with a streak: %q", got)
+	}
+
+	setCelebrate(t, "off")
+	if got := celebrationFor(red, 3, now, now); got != "" {
+		t.Errorf("celebrate off: %q, want no celebration", got)
+	}
+}
+
+func TestAddCelebration(t *testing.T) {
+	setCelebrate(t, "bell")
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	fetched := 0
+	client := &FakeClient{FetchGoalWithDatapointsFunc: func(slug string) (*Goal, error) {
+		fetched++
+		g := simulateTestGoal()
+		g.Safebuf = 0
+		return &g, nil
+	}}
+
+	req, goal := fetchAddGoal(context.Background(), addRequest{goalSlug: "reading", value: "3"}, client)
+	msg := addCelebration(goal, req, now)
+	var out bytes.Buffer
+	celebrate(&out, msg)
+	if out.String() != "🎉 reading is out of the red!\a\n" {
+		t.Errorf("celebrate wrote %q, want the message and the bell", out.String())
+	}
+	if fetched != 1 {
+		t.Errorf("fetched the goal with its datapoints %d times, want once", fetched)
+	}
+
+	setCelebrate(t, "off")
+	req, goal = fetchAddGoal(context.Background(), addRequest{goalSlug: "reading", value: "3", gunits: "pages"}, client)
+	if msg := addCelebration(goal, req, now); msg != "" || fetched != 1 {
+		t.Errorf("celebrate off: %q after %d fetches, want no celebration and no fetch", msg, fetched)
+	}
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// githubReleaseByTagAPI is the GitHub endpoint for a single tagged release. It
+// is a var (not a const) so tests can point it at an httptest server.
+var githubReleaseByTagAPI = "https://api.github.com/repos/pinepeakdigital/buzz/releases/tags/%s"
+
+// whatsNewMaxLines caps how much of the release notes the first-launch pane
+// shows; `buzz changelog` prints them in full.
+const whatsNewMaxLines = 15
+
+// ChangelogCache records the last version the user ran and the release notes
+// last fetched for it, so the "What's new" pane is shown once per upgrade and
+// `buzz changelog` works offline after the first fetch.
+type ChangelogCache struct {
+	LastRunVersion string    `json:"last_run_version"`
+	NotesVersion   string    `json:"notes_version"`
+	Notes          string    `json:"notes"`
+	NotesURL       string    `json:"notes_url"`
+	FetchedAt      time.Time `json:"fetched_at"`
+}
+
+// getChangelogCachePath returns the path to the changelog cache file
+func getChangelogCachePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".buzz_changelog_cache"), nil
+}
+
+// loadChangelogCache loads the changelog cache from disk. A missing file is
+// not an error; it returns an empty cache.
+func loadChangelogCache() (*ChangelogCache, error) {
+	cachePath, err := getChangelogCachePath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &ChangelogCache{}, nil
+		}
+		return nil, err
+	}
+
+	var cache ChangelogCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, err
+	}
+	return &cache, nil
+}
+
+// updateChangelogCache applies update to the changelog cache on disk (empty
+// when there's none yet) under the file's lock, so a buzz recording the
+// version it runs and one caching release notes don't undo each other. An
+// update returning errUnchanged saves nothing.
+func updateChangelogCache(update func(*ChangelogCache) error) error {
+	cachePath, err := getChangelogCachePath()
+	if err != nil {
+		return err
+	}
+	return updateFileAtomic(cachePath, 0600, func(data []byte) ([]byte, error) {
+		var cache ChangelogCache
+		if data != nil {
+			if err := json.Unmarshal(data, &cache); err != nil {
+				return nil, err
+			}
+		}
+		if err := update(&cache); err != nil {
+			return nil, err
+		}
+		return json.MarshalIndent(cache, "", "  ")
+	})
+}
+
+// fetchRelease fetches a release from GitHub: the given tag, or the latest
+// release when tag is empty.
+func fetchRelease(tag string) (*Release, error) {
+	client := &http.Client{
+		Timeout: 5 * time.Second,
+	}
+
+	endpoint := githubReleasesAPI
+	if tag != "" {
+		endpoint = fmt.Sprintf(githubReleaseByTagAPI, url.PathEscape(tag))
+	}
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set User-Agent to avoid GitHub rate limiting
+	req.Header.Set("User-Agent", "buzz-cli")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("no release found for %s", tag)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var release Release
+	if err := json.Unmarshal(body, &release); err != nil {
+		return nil, err
+	}
+	return &release, nil
+}
+
+// releaseNotesFor returns the release for tag, from the cache when it already
+// holds that tag's notes, otherwise from GitHub (caching the result). An
+// empty tag always fetches the latest release.
+func releaseNotesFor(tag string) (*Release, error) {
+	cache, err := loadChangelogCache()
+	if err != nil {
+		cache = &ChangelogCache{}
+	}
+	if tag != "" && cache.NotesVersion == tag && cache.Notes != "" {
+		return &Release{TagName: cache.NotesVersion, HTMLURL: cache.NotesURL, Body: cache.Notes}, nil
+	}
+
+	release, err := fetchRelease(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = updateChangelogCache(func(c *ChangelogCache) error {
+		c.NotesVersion = release.TagName
+		c.Notes = release.Body
+		c.NotesURL = release.HTMLURL
+		c.FetchedAt = time.Now()
+		return nil
+	}) // Ignore errors when saving cache
+
+	return release, nil
+}
+
+// recordRunVersion stores current as the last-run version and reports whether
+// it differs from the previously recorded one. A fresh install (nothing
+// recorded yet) and unstamped dev builds never count as an upgrade.
+func recordRunVersion(current string) bool {
+	if current == "dev" {
+		return false
+	}
+
+	// Compare and record under the lock, so two buzz processes recording at
+	// once don't both report the upgrade.
+	var previous string
+	err := updateChangelogCache(func(c *ChangelogCache) error {
+		previous = c.LastRunVersion
+		if previous == current {
+			return errUnchanged
+		}
+		c.LastRunVersion = current
+		return nil
+	})
+	if err != nil {
+		return false
+	}
+	return previous != "" && previous != current
+}
+
+// isUpgrade reports whether current differs from the recorded last-run
+// version, recording nothing. Like recordRunVersion, a fresh install and dev
+// builds don't count.
+func isUpgrade(current string) bool {
+	if current == "dev" {
+		return false
+	}
+	cache, err := loadChangelogCache()
+	return err == nil && cache.LastRunVersion != "" && cache.LastRunVersion != current
+}
+
+// pendingWhatsNew returns a brief summary of the current version's release
+// notes if this is the first launch since an upgrade, or "" otherwise. Errors
+// are swallowed: a missing changelog must never get in the way of startup.
+// The version is only recorded once the notes are shown (see
+// recordRunVersionCmd), so notes that couldn't be fetched, or arrived while a
+// form was open, are tried again next launch.
+func pendingWhatsNew() string {
+	if !isUpgrade(version) {
+		// Record a fresh install's version, so its first upgrade is noticed.
+		recordRunVersion(version)
+		return ""
+	}
+	release, err := releaseNotesFor(version)
+	if err != nil {
+		return ""
+	}
+	if strings.TrimSpace(release.Body) == "" {
+		recordRunVersion(version) // nothing to show, now or later
+		return ""
+	}
+	return summarizeReleaseNotes(release.Body, whatsNewMaxLines)
+}
+
+// summarizeReleaseNotes trims release notes to at most maxLines lines,
+// pointing at `buzz changelog` when anything was cut.
+func summarizeReleaseNotes(notes string, maxLines int) string {
+	notes = strings.TrimSpace(strings.ReplaceAll(notes, "\r\n", "\n"))
+	lines := strings.Split(notes, "\n")
+	if len(lines) <= maxLines {
+		return notes
+	}
+	return strings.Join(lines[:maxLines], "\n") + "\n…\nRun 'buzz changelog' for the full release notes."
+}
+
+// changelogUsage documents `buzz changelog`.
+const changelogUsage = `Usage: buzz changelog [version]
+
+Show the release notes for the installed version of buzz, or for the given
+version (e.g. v0.40.0). Development builds show the latest release.`
+
+// handleChangelogCommand prints release notes for the running (or a given)
+// version of buzz.
+func handleChangelogCommand() {
+	os.Exit(runChangelogCommand(os.Args[2:], os.Stdout, os.Stderr))
+}
+
+// runChangelogCommand is the testable core of `buzz changelog`.
+func runChangelogCommand(args []string, stdout, stderr io.Writer) int {
+	if len(args) > 0 && (args[0] == "-h" || args[0] == "--help") {
+		fmt.Fprintln(stdout, changelogUsage)
+		return 0
+	}
+	if len(args) > 1 {
+		fmt.Fprintln(stderr, "Error: changelog takes at most one argument")
+		fmt.Fprintln(stderr, changelogUsage)
+		return 1
+	}
+
+	tag := version
+	if len(args) == 1 {
+		tag = args[0]
+		if !strings.HasPrefix(tag, "v") {
+			tag = "v" + tag
+		}
+	} else if tag == "dev" {
+		tag = "" // no release matches a dev build; show the latest instead
+	}
+
+	release, err := releaseNotesFor(tag)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to fetch release notes: %s\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "What's new in buzz %s\n\n", release.TagName)
+	notes := strings.TrimSpace(strings.ReplaceAll(release.Body, "\r\n", "\n"))
+	if notes == "" {
+		notes = "(no release notes)"
+	}
+	fmt.Fprintln(stdout, notes)
+	if release.HTMLURL != "" {
+		fmt.Fprintf(stdout, "\n%s\n", release.HTMLURL)
+	}
+	return 0
+}
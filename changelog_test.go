@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// stubReleaseServer serves a single tagged release and counts requests, so
+// tests can check both the output and whether the cache was used.
+func stubReleaseServer(t *testing.T, tag, body string) *int {
+	t.Helper()
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if !strings.HasSuffix(r.URL.Path, "/"+tag) {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"tag_name":%q,"html_url":"https://example.com/%s","body":%q}`, tag, tag, body)
+	}))
+	t.Cleanup(srv.Close)
+
+	orig := githubReleaseByTagAPI
+	githubReleaseByTagAPI = srv.URL + "/tags/%s"
+	t.Cleanup(func() { githubReleaseByTagAPI = orig })
+	return &hits
+}
+
+func TestRunChangelogCommand(t *testing.T) {
+	t.Run("prints notes for the given version and caches them", func(t *testing.T) {
+		setHome(t, t.TempDir())
+		hits := stubReleaseServer(t, "v1.2.0", "- Added changelog\r\n- Fixed grid")
+
+		var out, errOut bytes.Buffer
+		code := runChangelogCommand([]string{"1.2.0"}, &out, &errOut)
+		checkResult(t, code, out.String(), errOut.String(), 0, "What's new in buzz v1.2.0", "")
+		if !strings.Contains(out.String(), "- Added changelog\n- Fixed grid") {
+			t.Errorf("output missing notes:\n%s", out.String())
+		}
+
+		out.Reset()
+		if code := runChangelogCommand([]string{"v1.2.0"}, &out, &errOut); code != 0 {
+			t.Fatalf("second run code = %d, want 0", code)
+		}
+		if *hits != 1 {
+			t.Errorf("GitHub hit %d times, want 1 (second run should use the cache)", *hits)
+		}
+	})
+
+	t.Run("unknown version is an error", func(t *testing.T) {
+		setHome(t, t.TempDir())
+		stubReleaseServer(t, "v1.2.0", "notes")
+
+		var out, errOut bytes.Buffer
+		code := runChangelogCommand([]string{"v9.9.9"}, &out, &errOut)
+		checkResult(t, code, out.String(), errOut.String(), 1, "", "no release found for v9.9.9")
+	})
+
+	t.Run("too many arguments", func(t *testing.T) {
+		var out, errOut bytes.Buffer
+		code := runChangelogCommand([]string{"a", "b"}, &out, &errOut)
+		checkResult(t, code, out.String(), errOut.String(), 1, "", "at most one argument")
+	})
+}
+
+func TestRecordRunVersion(t *testing.T) {
+	setHome(t, t.TempDir())
+
+	if recordRunVersion("v1.0.0") {
+		t.Error("first recorded run should not count as an upgrade")
+	}
+	if recordRunVersion("v1.0.0") {
+		t.Error("same version should not count as an upgrade")
+	}
+	if !recordRunVersion("v1.1.0") {
+		t.Error("new version should count as an upgrade")
+	}
+	if recordRunVersion("v1.1.0") {
+		t.Error("upgrade should only be reported once")
+	}
+	if recordRunVersion("dev") {
+		t.Error("dev builds should never count as an upgrade")
+	}
+}
+
+func TestPendingWhatsNew(t *testing.T) {
+	setHome(t, t.TempDir())
+	orig := version
+	t.Cleanup(func() { version = orig })
+
+	// A fresh install records its version and shows nothing.
+	version = "v1.0.0"
+	if notes := pendingWhatsNew(); notes != "" {
+		t.Errorf("fresh install notes = %q, want none", notes)
+	}
+
+	// After an upgrade, notes that can't be fetched leave the version
+	// unrecorded, so the next launch tries again.
+	version = "v1.1.0"
+	stubReleaseServer(t, "v9.9.9", "")
+	if notes := pendingWhatsNew(); notes != "" {
+		t.Errorf("notes = %q, want none when the fetch fails", notes)
+	}
+	if !isUpgrade("v1.1.0") {
+		t.Fatal("a failed fetch shouldn't record the version")
+	}
+
+	// Fetched notes are returned, but only recorded once shown.
+	stubReleaseServer(t, "v1.1.0", "- Faster grid")
+	if notes := pendingWhatsNew(); notes != "- Faster grid" {
+		t.Errorf("notes = %q, want the release notes", notes)
+	}
+	if !isUpgrade("v1.1.0") {
+		t.Fatal("fetching the notes shouldn't record the version")
+	}
+	recordRunVersionCmd()()
+	if isUpgrade("v1.1.0") || pendingWhatsNew() != "" {
+		t.Error("notes should be shown once per upgrade")
+	}
+}
+
+func TestSummarizeReleaseNotes(t *testing.T) {
+	short := "- one\n- two"
+	if got := summarizeReleaseNotes(short, 5); got != short {
+		t.Errorf("short notes changed: %q", got)
+	}
+
+	long := "1\n2\n3\n4"
+	got := summarizeReleaseNotes(long, 2)
+	if !strings.HasPrefix(got, "1\n2\n") || strings.Contains(got, "3") {
+		t.Errorf("long notes not truncated: %q", got)
+	}
+	if !strings.Contains(got, "buzz changelog") {
+		t.Errorf("truncated notes should point at buzz changelog: %q", got)
+	}
+}
@@ -24,21 +24,22 @@ func handleChargeCommand() {
 }
 
 // runChargeCommand is the testable core of `buzz charge <amount> <note>
-// [--dryrun]`. It validates the amount and note, creates the charge, and
+// [--dry-run]`. It validates the amount and note, creates the charge, and
 // returns the process exit code.
 func runChargeCommand(args []string, client Client, stdout, stderr io.Writer) int {
 	if len(args) < 2 {
 		fmt.Fprintln(stderr, "Error: Missing required arguments")
-		fmt.Fprintln(stderr, "Usage: buzz charge <amount> <note> [--dryrun]")
+		fmt.Fprintln(stderr, "Usage: buzz charge <amount> <note> [--dry-run]")
 		return 1
 	}
 
 	amountStr := args[0]
-	// Collect note parts and allow --dryrun anywhere after amount.
+	// Collect note parts and allow --dry-run (or --dryrun) anywhere after
+	// amount.
 	dryrun := false
 	var noteParts []string
 	for _, a := range args[1:] {
-		if a == "--dryrun" {
+		if a == "--dry-run" || a == "--dryrun" {
 			dryrun = true
 			continue
 		}
@@ -47,26 +48,13 @@ func runChargeCommand(args []string, client Client, stdout, stderr io.Writer) in
 	note := strings.Join(noteParts, " ")
 	if strings.TrimSpace(note) == "" {
 		fmt.Fprintln(stderr, "Error: Note is required")
-		fmt.Fprintln(stderr, "Usage: buzz charge <amount> <note> [--dryrun]")
+		fmt.Fprintln(stderr, "Usage: buzz charge <amount> <note> [--dry-run]")
 		return 1
 	}
 
-	// Validate amount is a number.
-	amount, err := strconv.ParseFloat(amountStr, 64)
+	amount, err := parseChargeAmount(amountStr)
 	if err != nil {
-		fmt.Fprintf(stderr, "Error: Amount must be a valid number, got: %s\n", amountStr)
-		return 1
-	}
-	// ParseFloat accepts "NaN"/"+Inf"/"-Inf"; reject those explicitly before
-	// the lower-bound check (NaN comparisons are always false, so NaN would
-	// otherwise sneak past `amount < 1.00` and reach the API).
-	if math.IsNaN(amount) || math.IsInf(amount, 0) {
-		fmt.Fprintf(stderr, "Error: Amount must be a finite number, got: %s\n", amountStr)
-		return 1
-	}
-	// Validate amount is >= 1.00.
-	if amount < 1.00 {
-		fmt.Fprintf(stderr, "Error: Amount must be at least 1.00, got: %.2f\n", amount)
+		fmt.Fprintf(stderr, "Error: %s\n", err)
 		return 1
 	}
 
@@ -84,3 +72,24 @@ func runChargeCommand(args []string, client Client, stdout, stderr io.Writer) in
 	}
 	return 0
 }
+
+// parseChargeAmount parses a charge amount, which must be a finite number of
+// at least 1.00. Shared by `buzz charge` and the TUI's charge form.
+func parseChargeAmount(amountStr string) (float64, error) {
+	// Validate amount is a number.
+	amount, err := strconv.ParseFloat(amountStr, 64)
+	if err != nil {
+		return 0, fmt.Errorf("Amount must be a valid number, got: %s", amountStr)
+	}
+	// ParseFloat accepts "NaN"/"+Inf"/"-Inf"; reject those explicitly before
+	// the lower-bound check (NaN comparisons are always false, so NaN would
+	// otherwise sneak past `amount < 1.00` and reach the API).
+	if math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return 0, fmt.Errorf("Amount must be a finite number, got: %s", amountStr)
+	}
+	// Validate amount is >= 1.00.
+	if amount < 1.00 {
+		return 0, fmt.Errorf("Amount must be at least 1.00, got: %.2f", amount)
+	}
+	return amount, nil
+}
@@ -22,6 +22,56 @@ const (
 	maxChartWidth = 80
 )
 
+// chartAllDays is the chartWindow.days value that charts a goal's whole
+// history, ignoring its tmin/tmax.
+const chartAllDays = -1
+
+// chartTimeframes are the timeframes review cycles through, in order: the
+// goal's own graph window, the last 7, 30, and 90 days, and all of it.
+var chartTimeframes = []int{0, 7, 30, 90, chartAllDays}
+
+// chartWindow is what a chart shows: its timeframe and whether the Y axis is
+// zoomed to the datapoints. The zero value is the goal's own graph window,
+// unzoomed.
+type chartWindow struct {
+	days  int  // the last days days; 0 for the goal's graph window, chartAllDays for all of it
+	zoomY bool // scale the Y axis to the datapoints, letting the bright red line run off it
+}
+
+// label describes the window for the chart header, e.g. "last 7 days"; it is
+// empty for the default window.
+func (w chartWindow) label() string {
+	var parts []string
+	switch {
+	case w.days == chartAllDays:
+		parts = append(parts, "all data")
+	case w.days > 0:
+		parts = append(parts, fmt.Sprintf("last %d days", w.days))
+	}
+	if w.zoomY {
+		parts = append(parts, "zoomed to datapoints")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// timeframe resolves the window's [start, end]. Fixed-length timeframes end
+// where the goal's default window does (now, or a future-dated datapoint).
+func (w chartWindow) timeframe(goal Goal, now time.Time) (start, end time.Time) {
+	switch {
+	case w.days == chartAllDays:
+		start, end = defaultTimeframe(goal, now)
+		if first, ok := firstDatapointTime(goal); ok && first.Before(start) {
+			start = first
+		}
+		return start, end
+	case w.days > 0:
+		_, end = defaultTimeframe(goal, now)
+		return now.AddDate(0, 0, -w.days), end
+	default:
+		return chartTimeframe(goal, now)
+	}
+}
+
 // renderGoalChart renders an ASCII chart of a goal's progress: the goal's
 // datapoints (blue) against its bright red line (red), over the goal's graph
 // window — the user-set tmin/tmax axis limits where present, otherwise the
@@ -29,11 +79,30 @@ const (
 // for the exact window resolution. It returns "" when there is nothing
 // chartable (no datapoints, or none inside the window).
 func renderGoalChart(goal Goal, width int) string {
+	return renderGoalChartWindow(goal, width, chartWindow{})
+}
+
+// renderGoalChartWindow is renderGoalChart over a chosen window: another
+// timeframe, or the Y axis zoomed to the datapoints.
+func renderGoalChartWindow(goal Goal, width int, window chartWindow) string {
+	chartWidth := width - 10 // leave room for padding and axis labels
+	if chartWidth < minChartWidth {
+		chartWidth = minChartWidth
+	}
+	if chartWidth > maxChartWidth {
+		chartWidth = maxChartWidth
+	}
+	return plotGoalChart(goal, chartWidth, window)
+}
+
+// plotGoalChart is renderGoalChartWindow with the plot chartWidth columns
+// wide, however wide that is.
+func plotGoalChart(goal Goal, chartWidth int, window chartWindow) string {
 	if len(goal.Datapoints) == 0 {
 		return ""
 	}
 
-	startTime, endTime := chartTimeframe(goal, time.Now())
+	startTime, endTime := window.timeframe(goal, time.Now())
 
 	processed := processDatapoints(goal, startTime, endTime)
 	if len(processed) == 0 {
@@ -61,16 +130,11 @@ func renderGoalChart(goal Goal, width int) string {
 	// instead of overlapping as they do on Beeminder's own graph.
 	brightLine = daysnapRoad(brightLine, startTime.Location())
 
-	chartWidth := width - 10 // leave room for padding and axis labels
-	if chartWidth < minChartWidth {
-		chartWidth = minChartWidth
-	}
-	if chartWidth > maxChartWidth {
-		chartWidth = maxChartWidth
-	}
-
 	roadValues := roadValuesForTimeframe(brightLine, startTime, endTime, chartWidth)
 	datapointValues, nodeCols := datapointSeries(processed, startTime, endTime, chartWidth)
+	if window.zoomY {
+		roadValues = zoomToDatapoints(roadValues, datapointValues)
+	}
 
 	var chart strings.Builder
 	chart.WriteString("\n")
@@ -92,6 +156,9 @@ func renderGoalChart(goal Goal, width int) string {
 	chart.WriteString(chartStyle.Render(header) + "\n")
 
 	timeframeInfo := fmt.Sprintf("Timeframe: %s to %s", startTime.Format("Jan 2"), endTime.Format("Jan 2, 2006"))
+	if label := window.label(); label != "" {
+		timeframeInfo += " (" + label + ")"
+	}
 	chart.WriteString(chartStyle.Render(timeframeInfo) + "\n\n")
 
 	// Plot the road first and the datapoints second: asciigraph lets a later
@@ -201,16 +268,64 @@ func defaultTimeframe(goal Goal, now time.Time) (start, end time.Time) {
 	return start, end
 }
 
+// firstDatapointTime returns the timestamp of the goal's earliest datapoint,
+// in the local zone like lastDatapointTime. ok is false when the goal has no
+// datapoints.
+func firstDatapointTime(goal Goal) (t time.Time, ok bool) {
+	if len(goal.Datapoints) == 0 {
+		return time.Time{}, false
+	}
+	if goal.series.fits(goal, time.Local) {
+		return time.Unix(goal.series.first, 0).In(time.Local), true
+	}
+	earliest := goal.Datapoints[0].Timestamp
+	for _, dp := range goal.Datapoints[1:] {
+		if dp.Timestamp < earliest {
+			earliest = dp.Timestamp
+		}
+	}
+	return time.Unix(earliest, 0).In(time.Local), true
+}
+
+// zoomToDatapoints blanks (NaN) the road values more than a tenth of the
+// datapoints' range outside it, so asciigraph scales the Y axis to the
+// datapoints and the bright red line runs off the chart where it strays
+// (asciigraph leaves NaN cells blank).
+func zoomToDatapoints(roadValues, datapointValues []float64) []float64 {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range datapointValues {
+		lo, hi = math.Min(lo, v), math.Max(hi, v)
+	}
+	margin := (hi - lo) / 10
+	if margin == 0 {
+		margin = math.Max(1, math.Abs(hi)/10)
+	}
+
+	zoomed := make([]float64, len(roadValues))
+	for i, v := range roadValues {
+		if v < lo-margin || v > hi+margin {
+			v = math.NaN()
+		}
+		zoomed[i] = v
+	}
+	return zoomed
+}
+
 // lastDatapointTime returns the timestamp of the goal's most recent datapoint.
 // ok is false when the goal has no datapoints.
 func lastDatapointTime(goal Goal) (t time.Time, ok bool) {
 	if len(goal.Datapoints) == 0 {
 		return time.Time{}, false
 	}
-	latest := goal.Datapoints[0].Timestamp
-	for _, dp := range goal.Datapoints[1:] {
-		if dp.Timestamp > latest {
-			latest = dp.Timestamp
+	var latest int64
+	if goal.series.fits(goal, time.Local) {
+		latest = goal.series.last
+	} else {
+		latest = goal.Datapoints[0].Timestamp
+		for _, dp := range goal.Datapoints[1:] {
+			if dp.Timestamp > latest {
+				latest = dp.Timestamp
+			}
 		}
 	}
 	// Return in the local zone: chartTimeframe resolves every other bound in
@@ -249,66 +364,56 @@ type timedValue struct {
 func processDatapoints(goal Goal, startTime, endTime time.Time) []timedValue {
 	loc := startTime.Location()
 
+	// The days come from the goal's prepared series where it has one (see
+	// chartSeries); bucketing and reducing per day is the aggday module's job,
+	// and what's left here is purely the charting layer: window filtering and
+	// the kyoom running total.
+	series := chartSeriesFor(goal, loc)
+
 	// Drop datapoints after the window end (including future-dated ones) before
 	// bucketing. This matches Beeminder — which filters data to "now" (asof)
 	// before aggregating — and stops a day's aggregate from absorbing same-day
-	// points logged after endTime when the window ends mid-day.
+	// points logged after endTime when the window ends mid-day. Only a window
+	// ending before the last datapoint needs the series rebuilt.
 	endUnix := endTime.Unix()
-	inRange := make([]Datapoint, 0, len(goal.Datapoints))
-	for _, dp := range goal.Datapoints {
-		if dp.Timestamp <= endUnix {
-			inRange = append(inRange, dp)
+	if series.last > endUnix {
+		inRange := make([]Datapoint, 0, len(goal.Datapoints))
+		for _, dp := range goal.Datapoints {
+			if dp.Timestamp <= endUnix {
+				inRange = append(inRange, dp)
+			}
 		}
-	}
-
-	// Bucket + reduce per day in one call (aggday module). What's left here is
-	// purely the charting layer: window filtering and the kyoom running total.
-	days := aggregateByDay(goal, inRange, loc)
-	if len(days) == 0 {
-		return nil
+		goal.Datapoints = inRange
+		series = newChartSeries(goal, loc)
 	}
 
 	// Days are compared against the start of startTime's calendar day, not the
 	// startTime instant itself: when the window begins mid-day (e.g. a stale
 	// goal whose window starts at its last datapoint's timestamp), that day's
 	// midnight-anchored aggregate would otherwise fall just before the window
-	// and be dropped.
+	// and be dropped. Future days are neither plotted nor affect the in-window
+	// line. carry is the running total reached just before the window (used
+	// by kyoom goals only).
 	startDay := startOfDay(startTime, loc)
-
-	var processed []timedValue
-	running := 0.0 // cumulative total of daily aggregates (kyoom only)
-	carry := 0.0   // running total reached just before the window (kyoom only)
-	inWindow := false
-
-	for _, d := range days {
-		if d.day.After(endTime) {
-			continue // future day: not plotted, and doesn't affect the in-window line
-		}
-		ad := d.value
-		switch {
-		case goal.Kyoom:
-			running += ad
-			if d.day.Before(startDay) {
-				carry = running
-			} else {
-				processed = append(processed, timedValue{timestamp: d.day.Unix(), value: running})
-				inWindow = true
-			}
-		case !d.day.Before(startDay):
-			processed = append(processed, timedValue{timestamp: d.day.Unix(), value: ad})
-			inWindow = true
-		}
-	}
-
-	if !inWindow {
+	days, totals, carry := series.window(startDay, endTime)
+	if len(days) == 0 {
 		return nil
 	}
+
+	processed := make([]timedValue, 0, len(days)+1)
 	if goal.Kyoom {
 		// Anchor at the start of the window's day (not the raw startTime instant),
 		// so it sorts at-or-before every day point — which sit at local midnight.
 		// A mid-day startTime would otherwise place the anchor after the first
 		// day point, breaking datapointSeries' ascending-order assumption.
-		processed = append([]timedValue{{timestamp: startDay.Unix(), value: carry}}, processed...)
+		processed = append(processed, timedValue{timestamp: startDay.Unix(), value: carry})
+	}
+	for i, d := range days {
+		value := d.value
+		if goal.Kyoom {
+			value = totals[i]
+		}
+		processed = append(processed, timedValue{timestamp: d.day.Unix(), value: value})
 	}
 	return processed
 }
@@ -437,6 +542,9 @@ func overlayDatapointMarkers(graph string, nodeCols []int, datapointValues, road
 	minimum, maximum := math.Inf(1), math.Inf(-1)
 	for _, series := range [][]float64{roadValues, datapointValues} {
 		for _, v := range series {
+			if math.IsNaN(v) { // a road value zoomed off the chart
+				continue
+			}
 			minimum = math.Min(minimum, v)
 			maximum = math.Max(maximum, v)
 		}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"math"
+	"reflect"
 	"slices"
 	"strings"
 	"testing"
@@ -951,3 +952,136 @@ func TestDaysnapRoad(t *testing.T) {
 		}
 	}
 }
+
+func TestChartWindowTimeframe(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local)
+	initday := time.Date(2026, 9, 1, 12, 0, 0, 0, time.Local)
+	early := time.Date(2026, 8, 20, 9, 0, 0, 0, time.Local)
+	goal := Goal{
+		Initday:    initday.Unix(),
+		Tmin:       "2026-10-01",
+		Datapoints: []Datapoint{{Timestamp: early.Unix()}, {Timestamp: now.Add(-time.Hour).Unix()}},
+	}
+	day := func(t time.Time) string { return t.Format("2006-01-02") }
+
+	tests := []struct {
+		window    chartWindow
+		wantStart string
+		wantLabel string
+	}{
+		{chartWindow{}, "2026-10-01", ""}, // the goal's tmin
+		{chartWindow{days: 7}, "2026-10-09", "last 7 days"},
+		{chartWindow{days: 90, zoomY: true}, "2026-07-18", "last 90 days, zoomed to datapoints"},
+		{chartWindow{days: chartAllDays}, "2026-08-20", "all data"}, // a datapoint before initday
+	}
+	for _, tt := range tests {
+		start, end := tt.window.timeframe(goal, now)
+		if day(start) != tt.wantStart || !end.Equal(now) {
+			t.Errorf("%+v: timeframe = %s to %s, want %s to now", tt.window, day(start), end, tt.wantStart)
+		}
+		if got := tt.window.label(); got != tt.wantLabel {
+			t.Errorf("%+v: label = %q, want %q", tt.window, got, tt.wantLabel)
+		}
+	}
+}
+
+func TestZoomToDatapoints(t *testing.T) {
+	road := []float64{0, 50, 95, 100, 200}
+	zoomed := zoomToDatapoints(road, []float64{90, 100, 110})
+	for i, want := range []float64{math.NaN(), math.NaN(), 95, 100, math.NaN()} {
+		if got := zoomed[i]; got != want && !(math.IsNaN(got) && math.IsNaN(want)) {
+			t.Errorf("zoomed[%d] = %v, want %v", i, got, want)
+		}
+	}
+	if road[0] != 0 {
+		t.Error("zoomToDatapoints modified its input")
+	}
+}
+
+func TestRenderGoalChartWindowZoomed(t *testing.T) {
+	now := time.Now()
+	start := now.AddDate(0, 0, -20)
+	goal := Goal{
+		Slug:  "test-goal",
+		Yaw:   1,
+		Kyoom: true,
+		// The road climbs from 0 to 1000 while the datapoints sit near 100.
+		Roadall: [][]*float64{
+			roadallRow(float64(start.Unix()), fptr(0.0), nil),
+			roadallRow(float64(now.Unix()), fptr(1000.0), nil),
+		},
+		Datapoints: []Datapoint{
+			{Timestamp: now.AddDate(0, 0, -2).Unix(), Value: 95},
+			{Timestamp: now.AddDate(0, 0, -1).Unix(), Value: 10},
+		},
+		Initday: start.Unix(),
+	}
+
+	full := renderGoalChart(goal, 80)
+	zoomed := renderGoalChartWindow(goal, 80, chartWindow{days: 7, zoomY: true})
+	if !strings.Contains(zoomed, "(last 7 days, zoomed to datapoints)") {
+		t.Errorf("zoomed chart header lacks its window:\n%s", zoomed)
+	}
+	// The top of the Y axis follows the datapoints, not the road.
+	topLabel := func(chart string) string {
+		for _, line := range strings.Split(chart, "\n") {
+			if strings.Contains(line, "┤") || strings.Contains(line, "┼") {
+				return strings.TrimSpace(strings.FieldsFunc(line, func(r rune) bool { return r == '┤' || r == '┼' })[0])
+			}
+		}
+		return ""
+	}
+	if got := topLabel(full); got != "1000" {
+		t.Errorf("full chart's top label = %q, want 1000", got)
+	}
+	if got := topLabel(zoomed); got != "105" {
+		t.Errorf("zoomed chart's top label = %q, want 105", got)
+	}
+}
+
+func TestPreparedChartSeries(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+	goal := Goal{
+		Kyoom: true,
+		Datapoints: []Datapoint{
+			{Timestamp: start.AddDate(0, 0, 3).Unix(), Daystamp: "20240104", Value: 3},
+			{Timestamp: start.AddDate(0, 0, -1).Unix(), Daystamp: "20231231", Value: 10},
+			{Timestamp: start.AddDate(0, 0, 1).Unix(), Daystamp: "20240102", Value: 5},
+		},
+	}
+	plain := goal
+	goal.prepareChart()
+	if !goal.series.fits(goal, time.Local) {
+		t.Fatal("a prepared goal's series should fit it")
+	}
+
+	// The prepared series charts the same as reducing the datapoints afresh,
+	// including a window ending before the last datapoint.
+	for _, end := range []time.Time{start.AddDate(0, 0, 5), start.AddDate(0, 0, 2)} {
+		got, want := processDatapoints(goal, start, end), processDatapoints(plain, start, end)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("window to %s: prepared %v, fresh %v", end.Format("Jan 2"), got, want)
+		}
+	}
+	if got, want := renderGoalChart(goal, 80), renderGoalChart(plain, 80); got != want {
+		t.Errorf("prepared chart differs:\n%s\nwant:\n%s", got, want)
+	}
+
+	// A copy whose datapoints were replaced doesn't chart the stale series.
+	copied := goal
+	copied.Datapoints = goal.Datapoints[2:]
+	if copied.series.fits(copied, time.Local) {
+		t.Error("a series shouldn't fit other datapoints")
+	}
+	if got := processDatapoints(copied, start, start.AddDate(0, 0, 5)); len(got) != 2 || got[1].value != 5 {
+		t.Errorf("replaced datapoints charted as %v, want [0 5]", got)
+	}
+}
+
+func BenchmarkRenderGoalChart(b *testing.B) {
+	goal := syntheticGoals(1)[0]
+	goal.prepareChart()
+	for b.Loop() {
+		renderGoalChart(goal, 100)
+	}
+}
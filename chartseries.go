@@ -0,0 +1,88 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// chartSeries is a goal's datapoints reduced once to what its chart needs:
+// one aggregate per day, ascending, with the running totals cumulative goals
+// plot. Bucketing a goal's datapoints into days copies and sorts all of them,
+// so a goal whose details were fetched carries its series (prepareChart) and
+// re-rendering it — moving between goals, resizing, changing the timeframe —
+// only has to pick out the days in the window.
+type chartSeries struct {
+	datapoints []Datapoint    // the datapoints the series was computed from
+	loc        *time.Location // the zone days were bucketed in
+	aggday     string         // how each day was aggregated
+	days       []dayValue     // one aggregate per day, ascending
+	totals     []float64      // totals[i] is the sum of days[0..i]
+	first      int64          // earliest datapoint timestamp
+	last       int64          // latest datapoint timestamp
+}
+
+// newChartSeries reduces the goal's datapoints to a chart series in loc.
+func newChartSeries(goal Goal, loc *time.Location) *chartSeries {
+	s := &chartSeries{
+		datapoints: goal.Datapoints,
+		loc:        loc,
+		aggday:     resolveAggday(goal),
+		days:       aggregateByDay(goal, goal.Datapoints, loc),
+	}
+	s.totals = make([]float64, len(s.days))
+	running := 0.0
+	for i, d := range s.days {
+		running += d.value
+		s.totals[i] = running
+	}
+	for i, dp := range goal.Datapoints {
+		if i == 0 || dp.Timestamp < s.first {
+			s.first = dp.Timestamp
+		}
+		if i == 0 || dp.Timestamp > s.last {
+			s.last = dp.Timestamp
+		}
+	}
+	return s
+}
+
+// prepareChart computes the goal's chart series in the local zone, where
+// charts are drawn, so rendering the goal doesn't have to. It does nothing
+// to a nil goal.
+func (g *Goal) prepareChart() {
+	if g != nil && len(g.Datapoints) > 0 {
+		g.series = newChartSeries(*g, time.Local)
+	}
+}
+
+// fits reports whether the series still describes the goal's datapoints as
+// charted in loc. A copy of a goal whose datapoints were since replaced (or
+// whose aggday differs) carries a series that no longer fits.
+func (s *chartSeries) fits(goal Goal, loc *time.Location) bool {
+	return s != nil && s.loc == loc && s.aggday == resolveAggday(goal) &&
+		len(s.datapoints) == len(goal.Datapoints) && len(s.datapoints) > 0 &&
+		&s.datapoints[0] == &goal.Datapoints[0]
+}
+
+// chartSeriesFor returns the goal's prepared series when it fits, and
+// otherwise computes one.
+func chartSeriesFor(goal Goal, loc *time.Location) *chartSeries {
+	if goal.series.fits(goal, loc) {
+		return goal.series
+	}
+	return newChartSeries(goal, loc)
+}
+
+// window returns the days in [startDay, end] and the running total reached
+// before them. Days are ascending, so both ends are binary searches.
+func (s *chartSeries) window(startDay, end time.Time) (days []dayValue, totals []float64, carry float64) {
+	lo := sort.Search(len(s.days), func(i int) bool { return !s.days[i].day.Before(startDay) })
+	hi := sort.Search(len(s.days), func(i int) bool { return s.days[i].day.After(end) })
+	if lo >= hi {
+		return nil, nil, 0
+	}
+	if lo > 0 {
+		carry = s.totals[lo-1]
+	}
+	return s.days[lo:hi], s.totals[lo:hi], carry
+}
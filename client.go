@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -26,14 +27,30 @@ const httpClientTimeout = 30 * time.Second
 // further interface changes — that wiring is tracked in a follow-up.
 type Client interface {
 	FetchGoals(ctx context.Context) ([]Goal, error)
+	// FetchGoalSummaries returns the user's goals without their bright red
+	// lines (roadall), which are most of a goal's weight on a long-running
+	// account, so listing hundreds of goals stays a small response. Fetch a
+	// goal's details (FetchGoalWithDatapoints) for its road.
+	FetchGoalSummaries(ctx context.Context) ([]Goal, error)
 	// FetchArchivedGoals returns the user's archived goals. Beeminder exposes
 	// these on a separate endpoint from active goals; the response uses the
 	// same Goal shape.
 	FetchArchivedGoals(ctx context.Context) ([]Goal, error)
+	// StreamGoals fetches the user's goals, or archived goals when archived
+	// is true, calling fn with each as soon as it's decoded from the
+	// response, in the order Beeminder sends them. An error from fn stops
+	// the fetch and is returned.
+	StreamGoals(ctx context.Context, archived bool, fn func(Goal) error) error
 	// FetchUserTimezone returns the IANA timezone configured on the user's
 	// Beeminder account (e.g. "America/New_York"), or an empty string if the
 	// account has none set.
 	FetchUserTimezone(ctx context.Context) (string, error)
+	// FetchUser returns the user the auth token belongs to, from
+	// users/me.json, so it also checks that the token works.
+	FetchUser(ctx context.Context) (*User, error)
+	// FetchDatapointsSince returns the user's goals, each carrying only the
+	// datapoints added or changed since the given time.
+	FetchDatapointsSince(ctx context.Context, since time.Time) ([]Goal, error)
 	// APIRequest performs a raw, authenticated request against the Beeminder
 	// API. path is relative to the API root (e.g. "users/me.json"); a leading
 	// slash is optional. The configured auth_token is added automatically.
@@ -44,15 +61,41 @@ type Client interface {
 	APIRequest(ctx context.Context, method, path string, params url.Values) (int, []byte, error)
 	FetchGoal(ctx context.Context, goalSlug string) (*Goal, error)
 	FetchGoalWithDatapoints(ctx context.Context, goalSlug string) (*Goal, error)
+	// FetchDatapoints returns a goal's count most recent datapoints, newest
+	// first, from the datapoints endpoint.
+	FetchDatapoints(ctx context.Context, goalSlug string, count int) ([]Datapoint, error)
+	// StreamDatapoints fetches all of a goal's datapoints a page at a time,
+	// newest first, calling fn with each as soon as it's decoded. An error
+	// from fn stops the fetch and is returned.
+	StreamDatapoints(ctx context.Context, goalSlug string, fn func(Datapoint) error) error
 	FetchGoalRawJSON(ctx context.Context, goalSlug string, includeDatapoints bool) (json.RawMessage, error)
 	GetLastDatapointValue(ctx context.Context, goalSlug string) (float64, error)
 	CreateDatapoint(ctx context.Context, goalSlug, timestamp, value, comment, requestid string) (*Datapoint, error)
 	CreateDatapointWithDaystamp(ctx context.Context, goalSlug, timestamp, daystamp, value, comment, requestid string) (*Datapoint, error)
+	// UpdateDatapoint changes the value, comment, or timestamp of the goal's
+	// datapoint id and returns the updated datapoint. A nil field is left as
+	// it is.
+	UpdateDatapoint(ctx context.Context, goalSlug, id string, value, comment, timestamp *string) (*Datapoint, error)
+	// DeleteDatapoint deletes the goal's datapoint id and returns it as it was.
+	DeleteDatapoint(ctx context.Context, goalSlug, id string) (*Datapoint, error)
 	CreateCharge(ctx context.Context, amount float64, note string, dryrun bool) (*Charge, error)
-	CreateGoal(ctx context.Context, slug, title, goalType, gunits, goaldate, goalval, rate string) (*Goal, error)
+	CreateGoal(ctx context.Context, slug, title, goalType, gunits, goaldate, goalval, rate, runits string) (*Goal, error)
 	CallUncle(ctx context.Context, goalSlug string) (*Goal, error)
+	ShortCircuitGoal(ctx context.Context, goalSlug string) (*Goal, error)
 	RatchetGoal(ctx context.Context, goalSlug string, ratchet int) (*Goal, error)
+	StepDownGoal(ctx context.Context, goalSlug string) (*Goal, error)
 	UpdateGoalDeadline(ctx context.Context, goalSlug string, deadline int) (*Goal, error)
+	UpdateGoalWeekendsOff(ctx context.Context, goalSlug string, weekendsOff bool) (*Goal, error)
+	UpdateGoalTags(ctx context.Context, goalSlug string, tags []string) (*Goal, error)
+	// UpdateGoalRoad replaces the goal's bright red line with roadall, rows of
+	// [t, v, r] like Goal.Roadall. Beeminder refuses a road that's easier
+	// before the akrasia horizon.
+	UpdateGoalRoad(ctx context.Context, goalSlug string, roadall [][]*float64) (*Goal, error)
+	RenameGoal(ctx context.Context, goalSlug, newSlug string) (*Goal, error)
+	UpdateGoalVisibility(ctx context.Context, goalSlug string, secret, dataPublic *bool) (*Goal, error)
+	// UpdateGoalPledgeCap sets the most the goal's pledge rises to after a
+	// derailment.
+	UpdateGoalPledgeCap(ctx context.Context, goalSlug string, pledgeCap float64) (*Goal, error)
 	RefreshGoal(ctx context.Context, goalSlug string) (bool, error)
 }
 
@@ -103,6 +146,7 @@ func (c *HTTPClient) doRequest(ctx context.Context, method, url string, body io.
 		return nil, err
 	}
 	LogResponse(c.config, resp.StatusCode, url)
+	noteServerDate(resp.Header.Get("Date"), time.Now())
 	return resp, nil
 }
 
@@ -120,12 +164,23 @@ type apiStatusError struct {
 }
 
 func (e *apiStatusError) Error() string {
+	if e.status == http.StatusUnauthorized {
+		// The body ("unauthorized" or similar) adds nothing; say what to do.
+		return "auth token invalid or expired (API returned status 401); run 'buzz auth login' to sign in again"
+	}
 	if e.body != "" {
 		return fmt.Sprintf("API returned status %d: %s", e.status, e.body)
 	}
 	return fmt.Sprintf("API returned status %d", e.status)
 }
 
+// isUnauthorized reports whether err is a 401 from Beeminder, meaning the
+// saved auth token was rejected and the user needs to log in again.
+func isUnauthorized(err error) bool {
+	var se *apiStatusError
+	return errors.As(err, &se) && se.status == http.StatusUnauthorized
+}
+
 // send runs an authenticated request and, on a 200 OK, returns the live
 // response for the caller to stream — the caller owns resp.Body and must close
 // it. It centralises the status-check that every typed method shared: a
@@ -169,6 +224,36 @@ func doJSON[T any](ctx context.Context, c *HTTPClient, method, url, failMsg stri
 	return out, nil
 }
 
+// streamJSON is send plus a decode of the success body, a JSON array, one
+// element at a time: fn gets each element as soon as it has arrived, rather
+// than once the whole list has. It returns how many elements fn was given.
+func streamJSON[T any](ctx context.Context, c *HTTPClient, url, failMsg string, fn func(T) error) (int, error) {
+	resp, err := c.send(ctx, http.MethodGet, url, failMsg, nil, "")
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	dec := json.NewDecoder(resp.Body)
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		return 0, fmt.Errorf("%s: failed to decode response: expected a list", failMsg)
+	}
+	n := 0
+	for dec.More() {
+		var item T
+		if err := dec.Decode(&item); err != nil {
+			return n, fmt.Errorf("%s: failed to decode response: %w", failMsg, err)
+		}
+		if err := fn(item); err != nil {
+			return n, err
+		}
+		n++
+	}
+	if _, err := dec.Token(); err != nil {
+		return n, fmt.Errorf("%s: failed to decode response: %w", failMsg, err)
+	}
+	return n, nil
+}
+
 // FetchGoals fetches the user's goals from Beeminder API.
 func (c *HTTPClient) FetchGoals(ctx context.Context) ([]Goal, error) {
 	url := fmt.Sprintf("%s/api/v1/users/%s/goals.json?auth_token=%s",
@@ -176,6 +261,14 @@ func (c *HTTPClient) FetchGoals(ctx context.Context) ([]Goal, error) {
 	return doJSON[[]Goal](ctx, c, http.MethodGet, url, "failed to fetch goals", nil, "")
 }
 
+// FetchGoalSummaries fetches the user's goals from the Beeminder API with
+// emaciated=true, which leaves out each goal's road, roadall, and fullroad.
+func (c *HTTPClient) FetchGoalSummaries(ctx context.Context) ([]Goal, error) {
+	url := fmt.Sprintf("%s/api/v1/users/%s/goals.json?auth_token=%s&emaciated=true",
+		c.baseURL(), c.config.Username, c.config.AuthToken)
+	return doJSON[[]Goal](ctx, c, http.MethodGet, url, "failed to fetch goals", nil, "")
+}
+
 // FetchArchivedGoals fetches the user's archived goals from the Beeminder API.
 func (c *HTTPClient) FetchArchivedGoals(ctx context.Context) ([]Goal, error) {
 	url := fmt.Sprintf("%s/api/v1/users/%s/goals/archived.json?auth_token=%s",
@@ -183,6 +276,20 @@ func (c *HTTPClient) FetchArchivedGoals(ctx context.Context) ([]Goal, error) {
 	return doJSON[[]Goal](ctx, c, http.MethodGet, url, "failed to fetch archived goals", nil, "")
 }
 
+// StreamGoals fetches the user's goals, or archived goals, from the same
+// endpoints as FetchGoals and FetchArchivedGoals, handing fn each goal as it's
+// decoded.
+func (c *HTTPClient) StreamGoals(ctx context.Context, archived bool, fn func(Goal) error) error {
+	path, failMsg := "goals.json", "failed to fetch goals"
+	if archived {
+		path, failMsg = "goals/archived.json", "failed to fetch archived goals"
+	}
+	url := fmt.Sprintf("%s/api/v1/users/%s/%s?auth_token=%s",
+		c.baseURL(), c.config.Username, path, c.config.AuthToken)
+	_, err := streamJSON(ctx, c, url, failMsg, fn)
+	return err
+}
+
 // FetchUserTimezone fetches the IANA timezone configured on the user's
 // Beeminder account from the user endpoint. Returns an empty string (no error)
 // if the account has no timezone set.
@@ -198,6 +305,33 @@ func (c *HTTPClient) FetchUserTimezone(ctx context.Context) (string, error) {
 	return result.Timezone, nil
 }
 
+// FetchUser fetches the user the auth token belongs to. It asks for "me"
+// rather than the configured username, so a token that doesn't match the
+// username still shows whose it is.
+func (c *HTTPClient) FetchUser(ctx context.Context) (*User, error) {
+	apiURL := fmt.Sprintf("%s/api/v1/users/me.json?auth_token=%s", c.baseURL(), c.config.AuthToken)
+	user, err := doJSON[User](ctx, c, http.MethodGet, apiURL, "failed to fetch user", nil, "")
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FetchDatapointsSince fetches, in one request, the user's goals carrying only
+// the datapoints added or changed since the given time, using the user
+// endpoint's diff_since parameter.
+func (c *HTTPClient) FetchDatapointsSince(ctx context.Context, since time.Time) ([]Goal, error) {
+	apiURL := fmt.Sprintf("%s/api/v1/users/%s.json?auth_token=%s&diff_since=%d",
+		c.baseURL(), c.config.Username, c.config.AuthToken, since.Unix())
+	result, err := doJSON[struct {
+		Goals []Goal `json:"goals"`
+	}](ctx, c, http.MethodGet, apiURL, "failed to fetch recent datapoints", nil, "")
+	if err != nil {
+		return nil, err
+	}
+	return result.Goals, nil
+}
+
 // APIRequest performs a raw, authenticated request against the Beeminder API.
 // See the Client interface for the contract. The auth_token is injected into
 // the query string for GET/DELETE and into the form body for methods that
@@ -300,6 +434,51 @@ func (c *HTTPClient) CreateDatapointWithDaystamp(ctx context.Context, goalSlug,
 	return &dp, nil
 }
 
+// UpdateDatapoint changes an existing datapoint with a PUT to its
+// datapoints/:id.json, sending only the fields that are set.
+func (c *HTTPClient) UpdateDatapoint(ctx context.Context, goalSlug, id string, value, comment, timestamp *string) (*Datapoint, error) {
+	apiURL := fmt.Sprintf("%s/api/v1/users/%s/goals/%s/datapoints/%s.json",
+		c.baseURL(), c.config.Username, url.PathEscape(goalSlug), url.PathEscape(id))
+
+	data := url.Values{}
+	data.Set("auth_token", c.config.AuthToken)
+	if value != nil {
+		data.Set("value", *value)
+	}
+	if comment != nil {
+		data.Set("comment", *comment)
+	}
+	if timestamp != nil {
+		data.Set("timestamp", *timestamp)
+	}
+
+	dp, err := doJSON[Datapoint](ctx, c, http.MethodPut, apiURL, "failed to update datapoint", strings.NewReader(data.Encode()), formContentType)
+	if err != nil {
+		var se *apiStatusError
+		if errors.As(err, &se) && se.status == http.StatusNotFound {
+			return nil, fmt.Errorf("datapoint %s not found on goal %s", id, goalSlug)
+		}
+		return nil, err
+	}
+	return &dp, nil
+}
+
+// DeleteDatapoint deletes a datapoint with a DELETE to its datapoints/:id.json.
+func (c *HTTPClient) DeleteDatapoint(ctx context.Context, goalSlug, id string) (*Datapoint, error) {
+	apiURL := fmt.Sprintf("%s/api/v1/users/%s/goals/%s/datapoints/%s.json?auth_token=%s",
+		c.baseURL(), c.config.Username, url.PathEscape(goalSlug), url.PathEscape(id), c.config.AuthToken)
+
+	dp, err := doJSON[Datapoint](ctx, c, http.MethodDelete, apiURL, "failed to delete datapoint", nil, "")
+	if err != nil {
+		var se *apiStatusError
+		if errors.As(err, &se) && se.status == http.StatusNotFound {
+			return nil, fmt.Errorf("datapoint %s not found on goal %s", id, goalSlug)
+		}
+		return nil, err
+	}
+	return &dp, nil
+}
+
 // CreateCharge creates a new charge for the authenticated user and returns it.
 func (c *HTTPClient) CreateCharge(ctx context.Context, amount float64, note string, dryrun bool) (*Charge, error) {
 	apiURL := fmt.Sprintf("%s/api/v1/charges.json", c.baseURL())
@@ -333,6 +512,34 @@ func (c *HTTPClient) CallUncle(ctx context.Context, goalSlug string) (*Goal, err
 	return &goal, nil
 }
 
+// ShortCircuitGoal charges a goal's current pledge right away, as if it had
+// derailed, and raises the pledge to the next step. It costs real money
+// whatever state the goal is in, so callers should confirm first.
+func (c *HTTPClient) ShortCircuitGoal(ctx context.Context, goalSlug string) (*Goal, error) {
+	apiURL := fmt.Sprintf("%s/api/v1/users/%s/goals/%s/shortcircuit.json?auth_token=%s",
+		c.baseURL(), c.config.Username, url.PathEscape(goalSlug), c.config.AuthToken)
+
+	goal, err := doJSON[Goal](ctx, c, http.MethodPost, apiURL, "failed to short-circuit goal", strings.NewReader(""), formContentType)
+	if err != nil {
+		return nil, err
+	}
+	return &goal, nil
+}
+
+// StepDownGoal schedules a decrease of the goal's pledge to the previous
+// step. Like other ways of making a goal easier, it only takes effect past the
+// akrasia horizon; the returned goal's Contract says when.
+func (c *HTTPClient) StepDownGoal(ctx context.Context, goalSlug string) (*Goal, error) {
+	apiURL := fmt.Sprintf("%s/api/v1/users/%s/goals/%s/stepdown.json?auth_token=%s",
+		c.baseURL(), c.config.Username, url.PathEscape(goalSlug), c.config.AuthToken)
+
+	goal, err := doJSON[Goal](ctx, c, http.MethodPost, apiURL, "failed to step down pledge", strings.NewReader(""), formContentType)
+	if err != nil {
+		return nil, err
+	}
+	return &goal, nil
+}
+
 // RatchetGoal removes safety buffer from a goal, leaving at most `ratchet` days
 // of buffer between today and the bright red line. Beeminder ignores requests
 // that would *add* buffer, so a goal already at or below `ratchet` days is left
@@ -380,6 +587,47 @@ func (c *HTTPClient) FetchGoalWithDatapoints(ctx context.Context, goalSlug strin
 	return &goal, nil
 }
 
+// FetchDatapoints fetches a goal's most recent datapoints, newest first.
+func (c *HTTPClient) FetchDatapoints(ctx context.Context, goalSlug string, count int) ([]Datapoint, error) {
+	apiURL := fmt.Sprintf("%s/api/v1/users/%s/goals/%s/datapoints.json?auth_token=%s&sort=timestamp&count=%d",
+		c.baseURL(), c.config.Username, url.PathEscape(goalSlug), c.config.AuthToken, count)
+
+	dps, err := doJSON[[]Datapoint](ctx, c, http.MethodGet, apiURL, "failed to fetch datapoints", nil, "")
+	if err != nil {
+		var se *apiStatusError
+		if errors.As(err, &se) && se.status == http.StatusNotFound {
+			return nil, fmt.Errorf("goal not found: %s", goalSlug)
+		}
+		return nil, err
+	}
+	return dps, nil
+}
+
+// datapointPageSize is how many datapoints StreamDatapoints asks for at a
+// time.
+const datapointPageSize = 500
+
+// StreamDatapoints pages through the goal's datapoints endpoint newest first,
+// handing fn each datapoint as it's decoded. A page shorter than
+// datapointPageSize is the last.
+func (c *HTTPClient) StreamDatapoints(ctx context.Context, goalSlug string, fn func(Datapoint) error) error {
+	for page := 1; ; page++ {
+		apiURL := fmt.Sprintf("%s/api/v1/users/%s/goals/%s/datapoints.json?auth_token=%s&sort=timestamp&page=%d&per=%d",
+			c.baseURL(), c.config.Username, url.PathEscape(goalSlug), c.config.AuthToken, page, datapointPageSize)
+		n, err := streamJSON(ctx, c, apiURL, "failed to fetch datapoints", fn)
+		if err != nil {
+			var se *apiStatusError
+			if errors.As(err, &se) && se.status == http.StatusNotFound {
+				return fmt.Errorf("goal not found: %s", goalSlug)
+			}
+			return err
+		}
+		if n < datapointPageSize {
+			return nil
+		}
+	}
+}
+
 // FetchGoalsWithDatapoints fetches the user's goals and populates the recent
 // datapoints for each one. Datapoints are fetched concurrently with a bounded
 // worker pool to keep the N+1 round trips fast for users with many goals.
@@ -458,7 +706,7 @@ func (c *HTTPClient) FetchGoalRawJSON(ctx context.Context, goalSlug string, incl
 
 // CreateGoal creates a new goal for the user.
 // Requires slug, title, goal_type, gunits, and exactly 2 of 3: goaldate, goalval, rate.
-func (c *HTTPClient) CreateGoal(ctx context.Context, slug, title, goalType, gunits, goaldate, goalval, rate string) (*Goal, error) {
+func (c *HTTPClient) CreateGoal(ctx context.Context, slug, title, goalType, gunits, goaldate, goalval, rate, runits string) (*Goal, error) {
 	apiURL := fmt.Sprintf("%s/api/v1/users/%s/goals.json",
 		c.baseURL(), c.config.Username)
 
@@ -471,6 +719,9 @@ func (c *HTTPClient) CreateGoal(ctx context.Context, slug, title, goalType, guni
 	data.Set("goaldate", goaldate)
 	data.Set("goalval", goalval)
 	data.Set("rate", rate)
+	if runits != "" {
+		data.Set("runits", runits)
+	}
 
 	goal, err := doJSON[Goal](ctx, c, http.MethodPost, apiURL, "failed to create goal", strings.NewReader(data.Encode()), formContentType)
 	if err != nil {
@@ -483,15 +734,72 @@ func (c *HTTPClient) CreateGoal(ctx context.Context, slug, title, goalType, guni
 // The deadline parameter is undocumented in the official API but is supported:
 // https://forum.beeminder.com/t/api-deadline/10666
 func (c *HTTPClient) UpdateGoalDeadline(ctx context.Context, goalSlug string, deadline int) (*Goal, error) {
-	escapedSlug := url.PathEscape(goalSlug)
-	apiURL := fmt.Sprintf("%s/api/v1/users/%s/goals/%s.json",
-		c.baseURL(), c.config.Username, escapedSlug)
+	data := url.Values{}
+	data.Set("deadline", fmt.Sprintf("%d", deadline))
+	return c.updateGoal(ctx, goalSlug, data, "failed to update goal deadline")
+}
+
+// UpdateGoalWeekendsOff turns a goal's automatic weekend breaks on or off.
+func (c *HTTPClient) UpdateGoalWeekendsOff(ctx context.Context, goalSlug string, weekendsOff bool) (*Goal, error) {
+	data := url.Values{}
+	data.Set("weekends_off", strconv.FormatBool(weekendsOff))
+	return c.updateGoal(ctx, goalSlug, data, "failed to update weekends off")
+}
+
+// UpdateGoalTags replaces a goal's tags.
+func (c *HTTPClient) UpdateGoalTags(ctx context.Context, goalSlug string, tags []string) (*Goal, error) {
+	data := url.Values{}
+	data["tags[]"] = tags
+	return c.updateGoal(ctx, goalSlug, data, "failed to update goal tags")
+}
+
+// UpdateGoalRoad replaces a goal's bright red line (its roadall).
+func (c *HTTPClient) UpdateGoalRoad(ctx context.Context, goalSlug string, roadall [][]*float64) (*Goal, error) {
+	road, err := json.Marshal(roadall)
+	if err != nil {
+		return nil, err
+	}
+	data := url.Values{}
+	data.Set("roadall", string(road))
+	return c.updateGoal(ctx, goalSlug, data, "failed to update bright red line")
+}
 
+// RenameGoal changes a goal's slug, returning the goal under its new slug.
+func (c *HTTPClient) RenameGoal(ctx context.Context, goalSlug, newSlug string) (*Goal, error) {
 	data := url.Values{}
+	data.Set("slug", newSlug)
+	return c.updateGoal(ctx, goalSlug, data, "failed to rename goal")
+}
+
+// UpdateGoalVisibility sets whether a goal is secret and whether its data is
+// public. A nil setting is left unchanged.
+func (c *HTTPClient) UpdateGoalVisibility(ctx context.Context, goalSlug string, secret, dataPublic *bool) (*Goal, error) {
+	data := url.Values{}
+	if secret != nil {
+		data.Set("secret", strconv.FormatBool(*secret))
+	}
+	if dataPublic != nil {
+		data.Set("datapublic", strconv.FormatBool(*dataPublic))
+	}
+	return c.updateGoal(ctx, goalSlug, data, "failed to update goal visibility")
+}
+
+// UpdateGoalPledgeCap sets the goal's pledge cap.
+func (c *HTTPClient) UpdateGoalPledgeCap(ctx context.Context, goalSlug string, pledgeCap float64) (*Goal, error) {
+	data := url.Values{}
+	data.Set("pledge_cap", strconv.FormatFloat(pledgeCap, 'f', -1, 64))
+	return c.updateGoal(ctx, goalSlug, data, "failed to update pledge cap")
+}
+
+// updateGoal PUTs the given goal attributes to the goal update endpoint and
+// returns the updated goal.
+func (c *HTTPClient) updateGoal(ctx context.Context, goalSlug string, data url.Values, errMsg string) (*Goal, error) {
+	apiURL := fmt.Sprintf("%s/api/v1/users/%s/goals/%s.json",
+		c.baseURL(), c.config.Username, url.PathEscape(goalSlug))
+
 	data.Set("auth_token", c.config.AuthToken)
-	data.Set("deadline", fmt.Sprintf("%d", deadline))
 
-	goal, err := doJSON[Goal](ctx, c, http.MethodPut, apiURL, "failed to update goal deadline", strings.NewReader(data.Encode()), formContentType)
+	goal, err := doJSON[Goal](ctx, c, http.MethodPut, apiURL, errMsg, strings.NewReader(data.Encode()), formContentType)
 	if err != nil {
 		return nil, err
 	}
@@ -1,10 +1,13 @@
 package main
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
 	"net/url"
+	"slices"
+	"time"
 )
 
 // FakeClient is a test double for the Client interface. Each API method is
@@ -25,20 +28,34 @@ import (
 // explicit context-capture field when the need arises.
 type FakeClient struct {
 	FetchGoalsFunc                  func() ([]Goal, error)
+	FetchGoalSummariesFunc          func() ([]Goal, error)
 	FetchArchivedGoalsFunc          func() ([]Goal, error)
 	FetchUserTimezoneFunc           func() (string, error)
+	FetchUserFunc                   func() (*User, error)
+	FetchDatapointsSinceFunc        func(since time.Time) ([]Goal, error)
 	APIRequestFunc                  func(method, path string, params url.Values) (int, []byte, error)
 	FetchGoalFunc                   func(goalSlug string) (*Goal, error)
 	FetchGoalWithDatapointsFunc     func(goalSlug string) (*Goal, error)
+	FetchDatapointsFunc             func(goalSlug string, count int) ([]Datapoint, error)
 	FetchGoalRawJSONFunc            func(goalSlug string, includeDatapoints bool) (json.RawMessage, error)
 	GetLastDatapointValueFunc       func(goalSlug string) (float64, error)
 	CreateDatapointFunc             func(goalSlug, timestamp, value, comment, requestid string) (*Datapoint, error)
 	CreateDatapointWithDaystampFunc func(goalSlug, timestamp, daystamp, value, comment, requestid string) (*Datapoint, error)
+	UpdateDatapointFunc             func(goalSlug, id string, value, comment, timestamp *string) (*Datapoint, error)
+	DeleteDatapointFunc             func(goalSlug, id string) (*Datapoint, error)
 	CreateChargeFunc                func(amount float64, note string, dryrun bool) (*Charge, error)
-	CreateGoalFunc                  func(slug, title, goalType, gunits, goaldate, goalval, rate string) (*Goal, error)
+	CreateGoalFunc                  func(slug, title, goalType, gunits, goaldate, goalval, rate, runits string) (*Goal, error)
 	CallUncleFunc                   func(goalSlug string) (*Goal, error)
+	ShortCircuitGoalFunc            func(goalSlug string) (*Goal, error)
 	RatchetGoalFunc                 func(goalSlug string, ratchet int) (*Goal, error)
+	StepDownGoalFunc                func(goalSlug string) (*Goal, error)
 	UpdateGoalDeadlineFunc          func(goalSlug string, deadline int) (*Goal, error)
+	UpdateGoalWeekendsOffFunc       func(goalSlug string, weekendsOff bool) (*Goal, error)
+	UpdateGoalRoadFunc              func(goalSlug string, roadall [][]*float64) (*Goal, error)
+	UpdateGoalTagsFunc              func(goalSlug string, tags []string) (*Goal, error)
+	RenameGoalFunc                  func(goalSlug, newSlug string) (*Goal, error)
+	UpdateGoalVisibilityFunc        func(goalSlug string, secret, dataPublic *bool) (*Goal, error)
+	UpdateGoalPledgeCapFunc         func(goalSlug string, pledgeCap float64) (*Goal, error)
 	RefreshGoalFunc                 func(goalSlug string) (bool, error)
 }
 
@@ -54,6 +71,15 @@ func (c *FakeClient) FetchGoals(ctx context.Context) ([]Goal, error) {
 	return c.FetchGoalsFunc()
 }
 
+// FetchGoalSummaries falls back to FetchGoalsFunc, so a fake configured with
+// the goal list serves both.
+func (c *FakeClient) FetchGoalSummaries(ctx context.Context) ([]Goal, error) {
+	if c.FetchGoalSummariesFunc == nil {
+		return c.FetchGoals(ctx)
+	}
+	return c.FetchGoalSummariesFunc()
+}
+
 func (c *FakeClient) FetchArchivedGoals(ctx context.Context) ([]Goal, error) {
 	if c.FetchArchivedGoalsFunc == nil {
 		return nil, errFakeNotConfigured
@@ -61,6 +87,25 @@ func (c *FakeClient) FetchArchivedGoals(ctx context.Context) ([]Goal, error) {
 	return c.FetchArchivedGoalsFunc()
 }
 
+// StreamGoals hands fn the goals from FetchGoals, or FetchArchivedGoals, one
+// at a time.
+func (c *FakeClient) StreamGoals(ctx context.Context, archived bool, fn func(Goal) error) error {
+	fetch := c.FetchGoals
+	if archived {
+		fetch = c.FetchArchivedGoals
+	}
+	goals, err := fetch(ctx)
+	if err != nil {
+		return err
+	}
+	for _, g := range goals {
+		if err := fn(g); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (c *FakeClient) FetchUserTimezone(ctx context.Context) (string, error) {
 	if c.FetchUserTimezoneFunc == nil {
 		return "", errFakeNotConfigured
@@ -68,6 +113,20 @@ func (c *FakeClient) FetchUserTimezone(ctx context.Context) (string, error) {
 	return c.FetchUserTimezoneFunc()
 }
 
+func (c *FakeClient) FetchUser(ctx context.Context) (*User, error) {
+	if c.FetchUserFunc == nil {
+		return nil, errFakeNotConfigured
+	}
+	return c.FetchUserFunc()
+}
+
+func (c *FakeClient) FetchDatapointsSince(ctx context.Context, since time.Time) ([]Goal, error) {
+	if c.FetchDatapointsSinceFunc == nil {
+		return nil, errFakeNotConfigured
+	}
+	return c.FetchDatapointsSinceFunc(since)
+}
+
 func (c *FakeClient) APIRequest(ctx context.Context, method, path string, params url.Values) (int, []byte, error) {
 	if c.APIRequestFunc == nil {
 		return 0, nil, errFakeNotConfigured
@@ -89,6 +148,30 @@ func (c *FakeClient) FetchGoalWithDatapoints(ctx context.Context, goalSlug strin
 	return c.FetchGoalWithDatapointsFunc(goalSlug)
 }
 
+func (c *FakeClient) FetchDatapoints(ctx context.Context, goalSlug string, count int) ([]Datapoint, error) {
+	if c.FetchDatapointsFunc == nil {
+		return nil, errFakeNotConfigured
+	}
+	return c.FetchDatapointsFunc(goalSlug, count)
+}
+
+// StreamDatapoints hands fn the datapoints of FetchGoalWithDatapoints one at a
+// time, newest first like the datapoints endpoint.
+func (c *FakeClient) StreamDatapoints(ctx context.Context, goalSlug string, fn func(Datapoint) error) error {
+	goal, err := c.FetchGoalWithDatapoints(ctx, goalSlug)
+	if err != nil {
+		return err
+	}
+	dps := slices.Clone(goal.Datapoints)
+	slices.SortStableFunc(dps, func(a, b Datapoint) int { return cmp.Compare(b.Timestamp, a.Timestamp) })
+	for _, dp := range dps {
+		if err := fn(dp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (c *FakeClient) FetchGoalRawJSON(ctx context.Context, goalSlug string, includeDatapoints bool) (json.RawMessage, error) {
 	if c.FetchGoalRawJSONFunc == nil {
 		return nil, errFakeNotConfigured
@@ -117,6 +200,20 @@ func (c *FakeClient) CreateDatapointWithDaystamp(ctx context.Context, goalSlug,
 	return c.CreateDatapointWithDaystampFunc(goalSlug, timestamp, daystamp, value, comment, requestid)
 }
 
+func (c *FakeClient) UpdateDatapoint(ctx context.Context, goalSlug, id string, value, comment, timestamp *string) (*Datapoint, error) {
+	if c.UpdateDatapointFunc == nil {
+		return nil, errFakeNotConfigured
+	}
+	return c.UpdateDatapointFunc(goalSlug, id, value, comment, timestamp)
+}
+
+func (c *FakeClient) DeleteDatapoint(ctx context.Context, goalSlug, id string) (*Datapoint, error) {
+	if c.DeleteDatapointFunc == nil {
+		return nil, errFakeNotConfigured
+	}
+	return c.DeleteDatapointFunc(goalSlug, id)
+}
+
 func (c *FakeClient) CreateCharge(ctx context.Context, amount float64, note string, dryrun bool) (*Charge, error) {
 	if c.CreateChargeFunc == nil {
 		return nil, errFakeNotConfigured
@@ -124,11 +221,11 @@ func (c *FakeClient) CreateCharge(ctx context.Context, amount float64, note stri
 	return c.CreateChargeFunc(amount, note, dryrun)
 }
 
-func (c *FakeClient) CreateGoal(ctx context.Context, slug, title, goalType, gunits, goaldate, goalval, rate string) (*Goal, error) {
+func (c *FakeClient) CreateGoal(ctx context.Context, slug, title, goalType, gunits, goaldate, goalval, rate, runits string) (*Goal, error) {
 	if c.CreateGoalFunc == nil {
 		return nil, errFakeNotConfigured
 	}
-	return c.CreateGoalFunc(slug, title, goalType, gunits, goaldate, goalval, rate)
+	return c.CreateGoalFunc(slug, title, goalType, gunits, goaldate, goalval, rate, runits)
 }
 
 func (c *FakeClient) CallUncle(ctx context.Context, goalSlug string) (*Goal, error) {
@@ -138,6 +235,13 @@ func (c *FakeClient) CallUncle(ctx context.Context, goalSlug string) (*Goal, err
 	return c.CallUncleFunc(goalSlug)
 }
 
+func (c *FakeClient) ShortCircuitGoal(ctx context.Context, goalSlug string) (*Goal, error) {
+	if c.ShortCircuitGoalFunc == nil {
+		return nil, errFakeNotConfigured
+	}
+	return c.ShortCircuitGoalFunc(goalSlug)
+}
+
 func (c *FakeClient) RatchetGoal(ctx context.Context, goalSlug string, ratchet int) (*Goal, error) {
 	if c.RatchetGoalFunc == nil {
 		return nil, errFakeNotConfigured
@@ -145,6 +249,13 @@ func (c *FakeClient) RatchetGoal(ctx context.Context, goalSlug string, ratchet i
 	return c.RatchetGoalFunc(goalSlug, ratchet)
 }
 
+func (c *FakeClient) StepDownGoal(ctx context.Context, goalSlug string) (*Goal, error) {
+	if c.StepDownGoalFunc == nil {
+		return nil, errFakeNotConfigured
+	}
+	return c.StepDownGoalFunc(goalSlug)
+}
+
 func (c *FakeClient) UpdateGoalDeadline(ctx context.Context, goalSlug string, deadline int) (*Goal, error) {
 	if c.UpdateGoalDeadlineFunc == nil {
 		return nil, errFakeNotConfigured
@@ -152,6 +263,48 @@ func (c *FakeClient) UpdateGoalDeadline(ctx context.Context, goalSlug string, de
 	return c.UpdateGoalDeadlineFunc(goalSlug, deadline)
 }
 
+func (c *FakeClient) UpdateGoalWeekendsOff(ctx context.Context, goalSlug string, weekendsOff bool) (*Goal, error) {
+	if c.UpdateGoalWeekendsOffFunc == nil {
+		return nil, errFakeNotConfigured
+	}
+	return c.UpdateGoalWeekendsOffFunc(goalSlug, weekendsOff)
+}
+
+func (c *FakeClient) UpdateGoalRoad(ctx context.Context, goalSlug string, roadall [][]*float64) (*Goal, error) {
+	if c.UpdateGoalRoadFunc == nil {
+		return nil, errFakeNotConfigured
+	}
+	return c.UpdateGoalRoadFunc(goalSlug, roadall)
+}
+
+func (c *FakeClient) UpdateGoalTags(ctx context.Context, goalSlug string, tags []string) (*Goal, error) {
+	if c.UpdateGoalTagsFunc == nil {
+		return nil, errFakeNotConfigured
+	}
+	return c.UpdateGoalTagsFunc(goalSlug, tags)
+}
+
+func (c *FakeClient) RenameGoal(ctx context.Context, goalSlug, newSlug string) (*Goal, error) {
+	if c.RenameGoalFunc == nil {
+		return nil, errFakeNotConfigured
+	}
+	return c.RenameGoalFunc(goalSlug, newSlug)
+}
+
+func (c *FakeClient) UpdateGoalVisibility(ctx context.Context, goalSlug string, secret, dataPublic *bool) (*Goal, error) {
+	if c.UpdateGoalVisibilityFunc == nil {
+		return nil, errFakeNotConfigured
+	}
+	return c.UpdateGoalVisibilityFunc(goalSlug, secret, dataPublic)
+}
+
+func (c *FakeClient) UpdateGoalPledgeCap(ctx context.Context, goalSlug string, pledgeCap float64) (*Goal, error) {
+	if c.UpdateGoalPledgeCapFunc == nil {
+		return nil, errFakeNotConfigured
+	}
+	return c.UpdateGoalPledgeCapFunc(goalSlug, pledgeCap)
+}
+
 func (c *FakeClient) RefreshGoal(ctx context.Context, goalSlug string) (bool, error) {
 	if c.RefreshGoalFunc == nil {
 		return false, errFakeNotConfigured
@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 // TestAPIStatusErrorMessage pins the message format every HTTPClient method now
@@ -30,6 +31,11 @@ func TestAPIStatusErrorMessage(t *testing.T) {
 			err:  &apiStatusError{status: http.StatusUnprocessableEntity, body: `{"errors":"bad"}`},
 			want: `API returned status 422: {"errors":"bad"}`,
 		},
+		{
+			name: "401 says how to recover instead of echoing the body",
+			err:  &apiStatusError{status: http.StatusUnauthorized, body: `{"errors":"unauthorized"}`},
+			want: "auth token invalid or expired (API returned status 401); run 'buzz auth login' to sign in again",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -55,6 +61,18 @@ func TestAPIStatusErrorAsTarget(t *testing.T) {
 	}
 }
 
+func TestIsUnauthorized(t *testing.T) {
+	if !isUnauthorized(fmt.Errorf("failed to fetch goals: %w", &apiStatusError{status: http.StatusUnauthorized})) {
+		t.Error("wrapped 401 should be unauthorized")
+	}
+	if isUnauthorized(&apiStatusError{status: http.StatusForbidden}) {
+		t.Error("403 should not be unauthorized")
+	}
+	if isUnauthorized(nil) {
+		t.Error("nil should not be unauthorized")
+	}
+}
+
 // TestDoJSONDecodeErrorAttributesEndpoint pins the behavior this refactor
 // restored: a malformed 200 body produces a decode error wrapped with the
 // calling endpoint's failMsg, so the failure can be traced to the specific API
@@ -77,3 +95,66 @@ func TestDoJSONDecodeErrorAttributesEndpoint(t *testing.T) {
 		t.Errorf("decode error should be attributed to the endpoint, got: %v", err)
 	}
 }
+
+// TestStreamGoalsBeforeTheResponseEnds checks StreamGoals hands over a goal as
+// soon as it has arrived: the server holds the rest of the list back until
+// the first goal has reached fn.
+func TestStreamGoalsBeforeTheResponseEnds(t *testing.T) {
+	gotFirst := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`[{"slug":"a"},`))
+		w.(http.Flusher).Flush()
+		select {
+		case <-gotFirst:
+		case <-time.After(5 * time.Second):
+		}
+		_, _ = w.Write([]byte(`{"slug":"b"}]`))
+	}))
+	defer srv.Close()
+
+	c := NewHTTPClient(&Config{Username: "u", AuthToken: "t", BaseURL: srv.URL})
+	var slugs []string
+	err := c.StreamGoals(context.Background(), false, func(g Goal) error {
+		if len(slugs) == 0 {
+			close(gotFirst)
+		}
+		slugs = append(slugs, g.Slug)
+		return nil
+	})
+	if err != nil || strings.Join(slugs, ",") != "a,b" {
+		t.Fatalf("StreamGoals = %v, %v; want a,b", slugs, err)
+	}
+}
+
+func TestStreamDatapointsPages(t *testing.T) {
+	var pages []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+		n := datapointPageSize
+		if page != "1" {
+			n = 1
+		}
+		var b strings.Builder
+		b.WriteString("[")
+		for i := range n {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			fmt.Fprintf(&b, `{"id":"%s-%d"}`, page, i)
+		}
+		b.WriteString("]")
+		_, _ = w.Write([]byte(b.String()))
+	}))
+	defer srv.Close()
+
+	c := NewHTTPClient(&Config{Username: "u", AuthToken: "t", BaseURL: srv.URL})
+	count := 0
+	err := c.StreamDatapoints(context.Background(), "g", func(Datapoint) error {
+		count++
+		return nil
+	})
+	if err != nil || count != datapointPageSize+1 || strings.Join(pages, ",") != "1,2" {
+		t.Errorf("StreamDatapoints = %d datapoints from pages %v, %v; want %d from pages 1,2", count, pages, err, datapointPageSize+1)
+	}
+}
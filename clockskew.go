@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Clock skew. Datapoints added "now" are stamped with the local clock and land
+// on the daystamp it implies, so a clock a few minutes off near a deadline
+// puts them on the wrong day. Every API response carries the server's Date
+// header; doRequest notes it, and the TUI and `buzz doctor` warn when the two
+// clocks disagree by more than clockSkewThreshold.
+
+// clockSkewThreshold is how far the local clock may drift from Beeminder's
+// before buzz warns. The Date header only has whole seconds, and a response
+// can take a while to arrive, so small differences are noise.
+const clockSkewThreshold = 3 * time.Minute
+
+// observedSkew is the latest clock skew seen, local minus server time.
+var observedSkew struct {
+	sync.Mutex
+	skew time.Duration
+	ok   bool
+}
+
+// noteServerDate records the skew between the local clock at now and a
+// response's Date header. A missing or malformed header is ignored.
+func noteServerDate(date string, now time.Time) {
+	server, err := http.ParseTime(date)
+	if err != nil {
+		return
+	}
+	observedSkew.Lock()
+	defer observedSkew.Unlock()
+	observedSkew.skew = now.Sub(server)
+	observedSkew.ok = true
+}
+
+// clockSkew returns the latest skew noted, with ok=false before any response
+// has carried a Date header.
+func clockSkew() (time.Duration, bool) {
+	observedSkew.Lock()
+	defer observedSkew.Unlock()
+	return observedSkew.skew, observedSkew.ok
+}
+
+// clockSkewWarning describes skew when it's past clockSkewThreshold, and is
+// "" otherwise.
+func clockSkewWarning(skew time.Duration) string {
+	direction := "ahead of"
+	if skew < 0 {
+		skew, direction = -skew, "behind"
+	}
+	if skew < clockSkewThreshold {
+		return ""
+	}
+	return fmt.Sprintf("Your clock is %d minutes %s Beeminder's, so datapoints may land on the wrong day. Fix the system clock.", int(skew.Round(time.Minute)/time.Minute), direction)
+}
+
+// checkClock compares the local clock with Beeminder's, making a request with
+// client to get the server's time. client is nil when there's no config.
+func checkClock(ctx context.Context, client Client) doctorCheck {
+	if client == nil {
+		return doctorCheck{"clock", checkWarn, "not checked; run 'buzz auth login' to compare with Beeminder"}
+	}
+	if _, err := client.FetchUserTimezone(ctx); err != nil {
+		return doctorCheck{"clock", checkWarn, fmt.Sprintf("not checked; can't reach Beeminder: %s", redactError(err))}
+	}
+	skew, ok := clockSkew()
+	if !ok {
+		return doctorCheck{"clock", checkWarn, "not checked; Beeminder sent no Date header"}
+	}
+	if warning := clockSkewWarning(skew); warning != "" {
+		return doctorCheck{"clock", checkFail, warning}
+	}
+	return doctorCheck{"clock", checkOK, fmt.Sprintf("within %d minutes of Beeminder's", int(clockSkewThreshold/time.Minute))}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClockSkewWarning(t *testing.T) {
+	for _, tt := range []struct {
+		skew time.Duration
+		want string
+	}{
+		{0, ""},
+		{2 * time.Minute, ""},
+		{-2*time.Minute - 59*time.Second, ""},
+		{5 * time.Minute, "Your clock is 5 minutes ahead of Beeminder's"},
+		{-10 * time.Minute, "Your clock is 10 minutes behind Beeminder's"},
+	} {
+		got := clockSkewWarning(tt.skew)
+		if tt.want == "" && got != "" || !strings.HasPrefix(got, tt.want) {
+			t.Errorf("clockSkewWarning(%s) = %q, want %q...", tt.skew, got, tt.want)
+		}
+	}
+}
+
+func TestCheckClock(t *testing.T) {
+	now := time.Date(2024, 1, 8, 12, 0, 0, 0, time.UTC)
+	client := &FakeClient{FetchUserTimezoneFunc: func() (string, error) { return "", nil }}
+
+	noteServerDate(now.Format(http.TimeFormat), now.Add(30*time.Second))
+	if skew, ok := clockSkew(); !ok || skew != 30*time.Second {
+		t.Errorf("clockSkew() = %s, %v; want 30s", skew, ok)
+	}
+	if c := checkClock(context.Background(), client); c.status != checkOK {
+		t.Errorf("small skew: %+v", c)
+	}
+
+	noteServerDate("not a date", now)
+	noteServerDate(now.Format(http.TimeFormat), now.Add(-7*time.Minute))
+	if c := checkClock(context.Background(), client); c.status != checkFail || !strings.Contains(c.detail, "7 minutes behind") {
+		t.Errorf("large skew: %+v", c)
+	}
+
+	if c := checkClock(context.Background(), nil); c.status != checkWarn {
+		t.Errorf("no config: %+v", c)
+	}
+	offline := &FakeClient{FetchUserTimezoneFunc: func() (string, error) { return "", errors.New("no network") }}
+	if c := checkClock(context.Background(), offline); c.status != checkWarn || !strings.Contains(c.detail, "no network") {
+		t.Errorf("offline: %+v", c)
+	}
+	noteServerDate(now.Format(http.TimeFormat), now)
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"io"
 )
@@ -11,15 +12,35 @@ import (
 // caller should exit non-zero). Extracting it means the credentialed commands
 // stop repeating the ConfigExists → LoadConfig → NewHTTPClient dance, and their
 // real logic moves into testable run* cores that take a Client.
+//
+// The client keeps the slug cache current with every goal list it fetches, and
+// journals every change it makes to the account (see journalingClient).
 func loadClient(stderr io.Writer) (Client, bool) {
+	_, client, ok := loadConfigAndClient(stderr)
+	return client, ok
+}
+
+// loadConfigAndClient is loadClient for commands that also need the config.
+func loadConfigAndClient(stderr io.Writer) (*Config, Client, bool) {
 	if !ConfigExists() {
 		fmt.Fprintln(stderr, "Error: No configuration found. Please run 'buzz auth login' to authenticate.")
-		return nil, false
+		return nil, nil, false
 	}
 	config, err := LoadConfig()
 	if err != nil {
 		fmt.Fprintf(stderr, "Error: Failed to load config: %s\n", redactError(err))
-		return nil, false
+		return nil, nil, false
+	}
+	for _, warning := range configWarnings(config) {
+		fmt.Fprintf(stderr, "Warning: %s\n", warning)
 	}
-	return NewHTTPClient(config), true
+	return config, journaling(recordingSlugs(NewHTTPClient(config), config.Username)), true
+}
+
+// dryRunFlag defines --dry-run on fs, plus --dryrun, the spelling add and
+// charge started with, so either spelling works on every command that has one.
+func dryRunFlag(fs *flag.FlagSet, usage string) *bool {
+	dryRun := fs.Bool("dry-run", false, usage)
+	fs.BoolVar(dryRun, "dryrun", false, usage)
+	return dryRun
 }
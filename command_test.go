@@ -2,14 +2,21 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"errors"
+	"reflect"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 // noStdin simulates an unpiped stdin (readValueFromStdin's error path).
 func noStdin() (string, error) { return "", errors.New("stdin is not piped") }
 
+// ttyStdin simulates running from a terminal with nothing piped.
+func ttyStdin() (string, error) { return "", errStdinNotPiped }
+
 // pipedStdin simulates a piped value on stdin.
 func pipedStdin(v string) func() (string, error) {
 	return func() (string, error) { return v, nil }
@@ -44,6 +51,45 @@ func TestRunRefreshCommand(t *testing.T) {
 	}
 }
 
+func TestRunOdometerResetCommand(t *testing.T) {
+	goalOfType := func(goalType string) func(string) (*Goal, error) {
+		return func(slug string) (*Goal, error) { return &Goal{Slug: slug, GoalType: goalType}, nil }
+	}
+	tests := []struct {
+		name             string
+		args             []string
+		fetch            func(string) (*Goal, error)
+		wantCode         int
+		wantOut, wantErr string
+		wantDatapoint    bool
+	}{
+		{name: "missing arg", wantCode: 1, wantErr: "Missing required argument"},
+		{name: "too many args", args: []string{"a", "b"}, wantCode: 1, wantErr: "Too many arguments"},
+		{name: "unknown goal", args: []string{"bike"}, fetch: func(string) (*Goal, error) { return nil, errors.New("goal not found: bike") }, wantCode: 1, wantErr: "goal not found"},
+		{name: "not an odometer goal", args: []string{"bike"}, fetch: goalOfType("hustler"), wantCode: 1, wantErr: "not an odometer goal"},
+		{name: "reset recorded", args: []string{"bike"}, fetch: goalOfType("biker"), wantOut: "Recorded an odometer reset for bike", wantDatapoint: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setHome(t, t.TempDir()) // contain createRefreshFlag's file write
+			var out, errb bytes.Buffer
+			var gotValue, gotComment string
+			client := &FakeClient{
+				FetchGoalFunc: tt.fetch,
+				CreateDatapointFunc: func(_, _, value, comment, _ string) (*Datapoint, error) {
+					gotValue, gotComment = value, comment
+					return &Datapoint{}, nil
+				},
+			}
+			code := runOdometerResetCommand(tt.args, client, &out, &errb)
+			checkResult(t, code, out.String(), errb.String(), tt.wantCode, tt.wantOut, tt.wantErr)
+			if tt.wantDatapoint && (gotValue != "0" || gotComment != odometerResetComment) {
+				t.Errorf("datapoint value=%q comment=%q, want a zero reset datapoint", gotValue, gotComment)
+			}
+		})
+	}
+}
+
 func TestRunChargeCommand(t *testing.T) {
 	okCharge := func(amount float64, note string, _ bool) (*Charge, error) {
 		return &Charge{ID: "c1", Amount: amount, Note: note, Username: "u"}, nil
@@ -61,7 +107,8 @@ func TestRunChargeCommand(t *testing.T) {
 		{"below minimum", []string{"0.50", "note"}, nil, 1, "", "at least 1.00"},
 		{"empty note", []string{"5", "   "}, nil, 1, "", "Note is required"},
 		{"success", []string{"5", "my", "note"}, okCharge, 0, "Successfully created charge c1", ""},
-		{"dryrun anywhere", []string{"5", "note", "--dryrun"}, okCharge, 0, "Dry run: Would charge", ""},
+		{"dry-run anywhere", []string{"5", "--dry-run", "note"}, okCharge, 0, "Dry run: Would charge", ""},
+		{"dryrun spelling", []string{"5", "note", "--dryrun"}, okCharge, 0, "Dry run: Would charge", ""},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -120,6 +167,10 @@ func TestParseAddArgs(t *testing.T) {
 		if strings.Contains(req.value, ":") {
 			t.Errorf("time value not converted to decimal: %q", req.value)
 		}
+		// Kept as typed for completeAddRequest to convert to the goal's units.
+		if req.timeValue != "1:30:00" {
+			t.Errorf("timeValue = %q, want 1:30:00", req.timeValue)
+		}
 	})
 
 	t.Run("invalid daystamp", func(t *testing.T) {
@@ -130,6 +181,50 @@ func TestParseAddArgs(t *testing.T) {
 		}
 	})
 
+	t.Run("date flag", func(t *testing.T) {
+		req, _, done := parseAddArgs([]string{"--date=2024-01-15", "goal", "42"}, noStdin, &bytes.Buffer{}, &bytes.Buffer{})
+		if done || req.daystamp != "20240115" {
+			t.Errorf("done=%v daystamp=%q, want 20240115", done, req.daystamp)
+		}
+
+		future := time.Now().AddDate(0, 0, 3).Format("2006-01-02")
+		for _, args := range [][]string{
+			{"--date=20240115", "goal", "42"},
+			{"--date=" + future, "goal", "42"},
+			{"--timestamp=yesterday", "goal", "42"},
+			{"--timestamp=" + strconv.FormatInt(time.Now().AddDate(0, 0, 3).Unix(), 10), "goal", "42"},
+			{"--date=2024-01-15", "--daystamp=20240115", "goal", "42"},
+		} {
+			var errb bytes.Buffer
+			_, code, done := parseAddArgs(args, noStdin, &bytes.Buffer{}, &errb)
+			if !done || code != 1 || !strings.HasPrefix(errb.String(), "Error:") {
+				t.Errorf("%v: done=%v code=%d err=%q", args, done, code, errb.String())
+			}
+		}
+	})
+
+	t.Run("timestamp flag", func(t *testing.T) {
+		ts := time.Date(2024, 1, 15, 18, 30, 0, 0, time.Local).Unix()
+		req, _, done := parseAddArgs([]string{"--timestamp", strconv.FormatInt(ts, 10), "goal", "42"}, noStdin, &bytes.Buffer{}, &bytes.Buffer{})
+		if done || req.timestamp != strconv.FormatInt(ts, 10) || req.daystamp != "" {
+			t.Fatalf("done=%v req=%+v", done, req)
+		}
+		// The derived request ID is for the timestamp's day, not today's.
+		req = withDefaultRequestID(req, &Config{}, time.Now())
+		if !strings.HasPrefix(req.requestid, "buzz-20240115-") {
+			t.Errorf("requestid = %q, want one for 20240115", req.requestid)
+		}
+	})
+
+	t.Run("dry-run flag in either spelling", func(t *testing.T) {
+		for _, flag := range []string{"--dry-run", "--dryrun"} {
+			req, _, done := parseAddArgs([]string{flag, "goal", "42"}, noStdin, &bytes.Buffer{}, &bytes.Buffer{})
+			if done || !req.dryRun {
+				t.Errorf("%s: done=%v dryRun=%v, want a dry run", flag, done, req.dryRun)
+			}
+		}
+	})
+
 	t.Run("non-numeric value rejected", func(t *testing.T) {
 		var errb bytes.Buffer
 		_, code, done := parseAddArgs([]string{"goal", "notanumber"}, noStdin, &bytes.Buffer{}, &errb)
@@ -146,6 +241,42 @@ func TestParseAddArgs(t *testing.T) {
 		}
 	})
 
+	t.Run("expression value evaluated", func(t *testing.T) {
+		req, _, done := parseAddArgs([]string{"goal", "3*12+5"}, noStdin, &bytes.Buffer{}, &bytes.Buffer{})
+		if done {
+			t.Fatal("unexpected done")
+		}
+		if req.value != "41" {
+			t.Errorf("value = %q, want 41", req.value)
+		}
+	})
+
+	t.Run("weight units flag", func(t *testing.T) {
+		req, _, done := parseAddArgs([]string{"--kg", "weight", "80.5"}, noStdin, &bytes.Buffer{}, &bytes.Buffer{})
+		if done {
+			t.Fatal("unexpected done")
+		}
+		if req.weightUnits != "kg" || req.value != "80.5" {
+			t.Errorf("got %+v, want weightUnits kg", req)
+		}
+
+		var errb bytes.Buffer
+		_, code, done := parseAddArgs([]string{"--kg", "--lb", "weight", "80"}, noStdin, &bytes.Buffer{}, &errb)
+		if !done || code != 1 || !strings.Contains(errb.String(), "can't be used together") {
+			t.Errorf("done=%v code=%d err=%q", done, code, errb.String())
+		}
+	})
+
+	t.Run("terminal with no value defers to the prompt", func(t *testing.T) {
+		req, _, done := parseAddArgs([]string{"goal"}, ttyStdin, &bytes.Buffer{}, &bytes.Buffer{})
+		if done {
+			t.Fatal("unexpected done")
+		}
+		if !req.promptValue || req.goalSlug != "goal" || req.value != "" {
+			t.Errorf("got %+v, want promptValue for goal", req)
+		}
+	})
+
 	t.Run("flag after positionals warns and is absorbed into comment", func(t *testing.T) {
 		var errb bytes.Buffer
 		// A --daystamp after the positional args is not parsed as a flag; it
@@ -161,11 +292,185 @@ func TestParseAddArgs(t *testing.T) {
 			t.Errorf("flag should be absorbed into comment, got daystamp=%q comment=%q", req.daystamp, req.comment)
 		}
 	})
+
+	t.Run("--comment-stdin reads the comment", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"--comment-stdin", "commits", "1"},
+			{"commits", "1", "--comment-stdin"},
+		} {
+			var errb bytes.Buffer
+			req, _, done := parseAddArgs(args, pipedStdin("Fix --daystamp parsing"), &bytes.Buffer{}, &errb)
+			if done || req.value != "1" || req.comment != "Fix --daystamp parsing" || errb.Len() != 0 {
+				t.Errorf("%v: done=%v req=%+v err=%q", args, done, req, errb.String())
+			}
+		}
+	})
+
+	t.Run("--comment-stdin errors", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			args  []string
+			stdin func() (string, error)
+		}{
+			{"no value", []string{"--comment-stdin", "commits"}, pipedStdin("msg")},
+			{"comment args too", []string{"--comment-stdin", "commits", "1", "extra"}, pipedStdin("msg")},
+			{"nothing piped", []string{"--comment-stdin", "commits", "1"}, noStdin},
+		}
+		for _, tt := range tests {
+			_, code, done := parseAddArgs(tt.args, tt.stdin, &bytes.Buffer{}, &bytes.Buffer{})
+			if !done || code != 1 {
+				t.Errorf("%s: done=%v code=%d", tt.name, done, code)
+			}
+		}
+	})
+
+	t.Run("--no-requestid", func(t *testing.T) {
+		req, _, done := parseAddArgs([]string{"--no-requestid", "goal", "1"}, noStdin, &bytes.Buffer{}, &bytes.Buffer{})
+		if done || !req.noRequestID {
+			t.Errorf("done=%v req=%+v", done, req)
+		}
+	})
+
+	t.Run("--requestid with --no-requestid", func(t *testing.T) {
+		_, code, done := parseAddArgs([]string{"--requestid=x", "--no-requestid", "goal", "1"}, noStdin, &bytes.Buffer{}, &bytes.Buffer{})
+		if !done || code != 1 {
+			t.Errorf("done=%v code=%d", done, code)
+		}
+	})
+}
+
+func TestCompleteAddRequest(t *testing.T) {
+	goalClient := func(goal Goal, last float64, lastErr error) *FakeClient {
+		return &FakeClient{
+			FetchGoalFunc: func(slug string) (*Goal, error) {
+				g := goal
+				g.Slug = slug
+				return &g, nil
+			},
+			GetLastDatapointValueFunc: func(string) (float64, error) { return last, lastErr },
+		}
+	}
+	lastValue := func(v float64, err error) *FakeClient { return goalClient(Goal{}, v, err) }
+	withUnits := func(gunits string) *FakeClient { return goalClient(Goal{Gunits: gunits}, 0, nil) }
+	odometer := goalClient(Goal{GoalType: "biker", Gunits: "miles"}, 1200, nil)
+	weight := func(gunits string) *FakeClient { return goalClient(Goal{GoalType: "fatloser", Gunits: gunits}, 0, nil) }
+
+	prompt := addRequest{goalSlug: "pushups", comment: "Added via buzz", promptValue: true}
+	withWeight := func(req addRequest, units string) addRequest {
+		req.weightUnits = units
+		return req
+	}
+
+	tests := []struct {
+		name       string
+		req        addRequest
+		client     *FakeClient
+		input      string
+		wantDone   bool
+		wantCode   int
+		wantValue  string
+		wantUnits  string
+		wantPrompt string
+		wantOut    string
+		wantErr    string
+	}{
+		{name: "number with last value", req: prompt, client: lastValue(1.5, nil), input: "2\n", wantValue: "2", wantPrompt: "Value for pushups [last: 1.5]: "},
+		{name: "no last value shown when fetch fails", req: prompt, client: lastValue(0, errors.New("boom")), input: "2\n", wantValue: "2", wantPrompt: "Value for pushups: "},
+		{name: "time converted", req: prompt, client: lastValue(0, nil), input: "1:30\n", wantValue: "1.5"},
+		{name: "expression evaluated", req: prompt, client: lastValue(0, nil), input: "3*12+5\n", wantValue: "41"},
+		{name: "input without newline", req: prompt, client: lastValue(0, nil), input: "7", wantValue: "7"},
+		{name: "invalid input asked again", req: prompt, client: lastValue(0, nil), input: "abc\n4\n", wantValue: "4", wantErr: "valid number"},
+		{name: "empty input cancels", req: prompt, client: lastValue(0, nil), input: "\n", wantDone: true, wantOut: "Cancelled."},
+		{name: "EOF cancels", req: prompt, client: lastValue(0, nil), input: "", wantDone: true, wantOut: "Cancelled."},
+		{name: "invalid input at EOF fails", req: prompt, client: lastValue(0, nil), input: "abc", wantDone: true, wantCode: 1, wantErr: "valid number"},
+		{name: "units shown", req: prompt, client: withUnits("reps"), input: "2\n", wantValue: "2", wantUnits: "reps", wantPrompt: "Value for pushups (reps): "},
+		{name: "time converted to minutes", req: prompt, client: withUnits("minutes"), input: "1:30\n", wantValue: "90", wantUnits: "minutes"},
+		{name: "time rejected for non-time units", req: prompt, client: withUnits("reps"), input: "1:30\n5\n", wantValue: "5", wantUnits: "reps", wantErr: "measured in reps"},
+		{name: "unknown goal fails before prompting", req: prompt, client: &FakeClient{FetchGoalFunc: func(slug string) (*Goal, error) { return nil, errors.New("goal not found: " + slug) }}, wantDone: true, wantCode: 1, wantErr: "goal not found"},
+		{name: "odometer shows distance since last reading", req: prompt, client: odometer, input: "1215\n", wantValue: "1215", wantUnits: "miles", wantPrompt: "Odometer reading for pushups (miles) [last: 1200]: ", wantOut: "+15 miles since the last reading"},
+		{name: "odometer rejects a lower reading", req: prompt, client: odometer, input: "15\n1230\n", wantValue: "1230", wantUnits: "miles", wantErr: "odometer-reset pushups"},
+		{name: "odometer accepts zero", req: prompt, client: odometer, input: "0\n", wantValue: "0", wantUnits: "miles"},
+		{name: "no goal needed", req: addRequest{goalSlug: "g", value: "3", comment: "Added via buzz"}, client: &FakeClient{}, wantValue: "3"},
+		{name: "typed time converted to minutes", req: addRequest{goalSlug: "g", value: "1.5", timeValue: "1:30", comment: "Added via buzz"}, client: withUnits("minutes"), wantValue: "90", wantUnits: "minutes"},
+		{name: "typed time kept in hours", req: addRequest{goalSlug: "g", value: "1.5", timeValue: "1:30", comment: "Added via buzz"}, client: withUnits("hours"), wantValue: "1.5", wantUnits: "hours"},
+		{name: "typed time rejected for non-time units", req: addRequest{goalSlug: "g", value: "1.5", timeValue: "1:30", comment: "Added via buzz"}, client: withUnits("pages"), wantDone: true, wantCode: 1, wantErr: "measured in pages"},
+		{name: "kg converted to lb goal", req: withWeight(addRequest{goalSlug: "weight", value: "80", comment: "Added via buzz"}, "kg"), client: weight("lbs"), wantValue: "176.37", wantUnits: "lbs", wantOut: "80 kg = 176.37 lb"},
+		{name: "lb converted to kg goal", req: withWeight(addRequest{goalSlug: "weight", value: "200", comment: "Added via buzz"}, "lb"), client: weight("kg"), wantValue: "90.7185", wantUnits: "kg"},
+		{name: "matching weight units unchanged", req: withWeight(addRequest{goalSlug: "weight", value: "80", comment: "Added via buzz"}, "kg"), client: weight("Kilograms"), wantValue: "80", wantUnits: "Kilograms"},
+		{name: "prompted weight converted", req: withWeight(prompt, "kg"), client: weight("lb"), input: "80\n", wantValue: "176.37", wantUnits: "lb", wantPrompt: "Value for pushups (kg): "},
+		{name: "weight flag on a non-weight goal", req: withWeight(addRequest{goalSlug: "g", value: "80", comment: "Added via buzz"}, "kg"), client: goalClient(Goal{GoalType: "hustler", Gunits: "kg"}, 0, nil), wantDone: true, wantCode: 1, wantErr: "only applies to weight goals"},
+		{name: "weight goal in unknown units", req: withWeight(addRequest{goalSlug: "g", value: "80", comment: "Added via buzz"}, "kg"), client: weight("stone"), wantDone: true, wantCode: 1, wantErr: "Can't convert"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errb bytes.Buffer
+			got, code, done := completeAddRequest(tt.req, tt.client, strings.NewReader(tt.input), &out, &errb)
+			if done != tt.wantDone || code != tt.wantCode {
+				t.Fatalf("done=%v code=%d, want done=%v code=%d (stderr=%q)", done, code, tt.wantDone, tt.wantCode, errb.String())
+			}
+			if !done && (got.value != tt.wantValue || got.promptValue || got.comment != tt.req.comment || got.gunits != tt.wantUnits) {
+				t.Errorf("got %+v, want value %q units %q", got, tt.wantValue, tt.wantUnits)
+			}
+			if tt.wantPrompt != "" && !strings.HasPrefix(out.String(), tt.wantPrompt) {
+				t.Errorf("stdout = %q, want prompt %q", out.String(), tt.wantPrompt)
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("stdout = %q, want %q", out.String(), tt.wantOut)
+			}
+			if !strings.Contains(errb.String(), tt.wantErr) {
+				t.Errorf("stderr = %q, want %q", errb.String(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWithDefaultRequestID(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	base := addRequest{goalSlug: "run", value: "5"}
+
+	derived := withDefaultRequestID(base, &Config{}, now).requestid
+	if !strings.HasPrefix(derived, "buzz-20260310-") {
+		t.Fatalf("derived requestid = %q", derived)
+	}
+
+	// Same date, goal, and value (however the value is written) → same ID.
+	if again := withDefaultRequestID(addRequest{goalSlug: "run", value: "5.0", comment: "x"}, &Config{}, now.Add(time.Hour)).requestid; again != derived {
+		t.Errorf("re-run requestid = %q, want %q", again, derived)
+	}
+
+	// A different day, goal, or value → a different ID.
+	for _, req := range []addRequest{{goalSlug: "run", value: "6"}, {goalSlug: "swim", value: "5"}, {goalSlug: "run", value: "5", daystamp: "20260309"}} {
+		if got := withDefaultRequestID(req, &Config{}, now).requestid; got == derived {
+			t.Errorf("%+v: requestid collides with %q", req, derived)
+		}
+	}
+
+	// A piped --comment-stdin comment tells adds apart, so each commit a hook
+	// logs counts; the same commit twice is still one add.
+	commit := func(msg string) string {
+		return withDefaultRequestID(addRequest{goalSlug: "run", value: "5", comment: msg, distinctComment: true}, &Config{}, now).requestid
+	}
+	if a, b := commit("Fix typo"), commit("Add tests"); a == b || a == derived {
+		t.Errorf("--comment-stdin requestids %q and %q collide", a, b)
+	}
+	if a, b := commit("Fix typo"), commit("Fix typo"); a != b {
+		t.Errorf("the same --comment-stdin add got %q then %q", a, b)
+	}
+
+	if got := withDefaultRequestID(addRequest{goalSlug: "run", value: "5", requestid: "mine"}, &Config{}, now).requestid; got != "mine" {
+		t.Errorf("explicit requestid replaced with %q", got)
+	}
+	if got := withDefaultRequestID(addRequest{goalSlug: "run", value: "5", noRequestID: true}, &Config{}, now).requestid; got != "" {
+		t.Errorf("--no-requestid still got %q", got)
+	}
+	if got := withDefaultRequestID(base, &Config{NoAutoRequestID: true}, now).requestid; got != "" {
+		t.Errorf("no_auto_requestid still got %q", got)
+	}
 }
 
 func TestRunAddCommand(t *testing.T) {
 	t.Run("success forwards request and reports daystamp/requestid", func(t *testing.T) {
-		t.Setenv("HOME", t.TempDir()) // contain createRefreshFlag's file write
+		setHome(t, t.TempDir()) // contain createRefreshFlag's file write
 		var out, errb bytes.Buffer
 		var gotSlug, gotDaystamp, gotValue, gotComment, gotReqID string
 		client := &FakeClient{
@@ -189,8 +494,95 @@ func TestRunAddCommand(t *testing.T) {
 		}
 	})
 
+	t.Run("timestamp forwarded", func(t *testing.T) {
+		setHome(t, t.TempDir())
+		var out bytes.Buffer
+		var gotTimestamp string
+		client := &FakeClient{
+			CreateDatapointWithDaystampFunc: func(_, timestamp, _, _, _, _ string) (*Datapoint, error) {
+				gotTimestamp = timestamp
+				return &Datapoint{}, nil
+			},
+		}
+		req := addRequest{goalSlug: "g", value: "1", comment: "hi", timestamp: "1705300000"}
+		if code := runAddCommand(req, client, &out, &bytes.Buffer{}); code != 0 {
+			t.Fatalf("code=%d", code)
+		}
+		if gotTimestamp != "1705300000" || !strings.Contains(out.String(), "timestamp=1705300000") {
+			t.Errorf("timestamp=%q stdout=%q", gotTimestamp, out.String())
+		}
+	})
+
+	t.Run("success message names the units when known", func(t *testing.T) {
+		setHome(t, t.TempDir())
+		var out bytes.Buffer
+		client := &FakeClient{
+			CreateDatapointWithDaystampFunc: func(_, _, _, _, _, _ string) (*Datapoint, error) { return &Datapoint{}, nil },
+		}
+		req := addRequest{goalSlug: "reading", value: "2", comment: "hi", gunits: "pages"}
+		if code := runAddCommand(req, client, &out, &bytes.Buffer{}); code != 0 {
+			t.Fatalf("code=%d", code)
+		}
+		if !strings.Contains(out.String(), "Successfully added 2 pages to reading") {
+			t.Errorf("stdout=%q", out.String())
+		}
+	})
+
+	t.Run("a plain add fetches the units for the success message", func(t *testing.T) {
+		setHome(t, t.TempDir())
+		setCelebrate(t, "off")
+		var out bytes.Buffer
+		client := &FakeClient{
+			FetchGoalFunc:                   func(slug string) (*Goal, error) { return &Goal{Slug: slug, Gunits: "pages"}, nil },
+			CreateDatapointWithDaystampFunc: func(_, _, _, _, _, _ string) (*Datapoint, error) { return &Datapoint{}, nil },
+		}
+		req, _ := fetchAddGoal(context.Background(), addRequest{goalSlug: "reading", value: "2", comment: "hi"}, client)
+		if code := runAddCommand(req, client, &out, &bytes.Buffer{}); code != 0 {
+			t.Fatalf("code=%d", code)
+		}
+		if !strings.Contains(out.String(), "Successfully added 2 pages to reading") {
+			t.Errorf("stdout=%q", out.String())
+		}
+	})
+
+	t.Run("an add Beeminder already had is not reported as added", func(t *testing.T) {
+		setHome(t, t.TempDir())
+		now := time.Now()
+		dp := &Datapoint{ID: "dp1", UpdatedAt: now.Unix()}
+		client := &FakeClient{
+			CreateDatapointWithDaystampFunc: func(_, _, _, _, _, _ string) (*Datapoint, error) { return dp, nil },
+		}
+		req := addRequest{goalSlug: "reading", value: "5", comment: "hi", gunits: "pages", requestid: "buzz-x"}
+		var out, errb bytes.Buffer
+		code, added := submitAdd(req, client, now, &out, &errb)
+		if code != 0 || !added || !strings.Contains(out.String(), "Successfully added") {
+			t.Fatalf("first add: code=%d added=%v out=%q", code, added, out.String())
+		}
+
+		// The same ID again returns the same datapoint.
+		out.Reset()
+		code, added = submitAdd(req, client, now.Add(time.Minute), &out, &errb)
+		if code != 0 || added || out.String() != "" {
+			t.Errorf("repeat add: code=%d added=%v out=%q", code, added, out.String())
+		}
+		if !strings.Contains(errb.String(), "reading: 5 pages already logged today, not added again") || !strings.Contains(errb.String(), "--no-requestid") || !strings.Contains(errb.String(), "no_auto_requestid") {
+			t.Errorf("stderr = %q", errb.String())
+		}
+
+		// So does a datapoint made before the add was sent, say by the TUI.
+		dp = &Datapoint{ID: "dp0", UpdatedAt: now.Add(-time.Hour).Unix()}
+		if _, added := submitAdd(req, client, now, &bytes.Buffer{}, &bytes.Buffer{}); added {
+			t.Error("an hour-old datapoint was reported as added")
+		}
+		// Without a request ID, nothing can have been deduplicated.
+		req.requestid = ""
+		if _, added := submitAdd(req, client, now, &bytes.Buffer{}, &bytes.Buffer{}); !added {
+			t.Error("an add without a request ID was reported as already logged")
+		}
+	})
+
 	t.Run("api error", func(t *testing.T) {
-		t.Setenv("HOME", t.TempDir())
+		setHome(t, t.TempDir())
 		var out, errb bytes.Buffer
 		client := &FakeClient{
 			CreateDatapointWithDaystampFunc: func(_, _, _, _, _, _ string) (*Datapoint, error) {
@@ -333,6 +725,14 @@ func TestRunDeadlineCommand(t *testing.T) {
 }
 
 // checkResult is a shared assertion for the table-driven run* command tests.
+// setHome points the home directory at dir for the rest of the test: $HOME,
+// and $USERPROFILE, which os.UserHomeDir reads on Windows.
+func setHome(t *testing.T, dir string) {
+	t.Helper()
+	t.Setenv("HOME", dir)
+	t.Setenv("USERPROFILE", dir)
+}
+
 func checkResult(t *testing.T, code int, out, errOut string, wantCode int, wantOut, wantErr string) {
 	t.Helper()
 	if code != wantCode {
@@ -345,3 +745,268 @@ func checkResult(t *testing.T, code int, out, errOut string, wantCode int, wantO
 		t.Errorf("stderr = %q, want contains %q", errOut, wantErr)
 	}
 }
+
+func TestParseWeekendsOffArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		wantDone bool
+		want     weekendsOffRequest
+	}{
+		{"help", []string{"-h"}, 0, true, weekendsOffRequest{}},
+		{"bad flag", []string{"--nope"}, 2, true, weekendsOffRequest{}},
+		{"missing state", []string{"goal"}, 1, true, weekendsOffRequest{}},
+		{"bad state", []string{"goal", "maybe"}, 1, true, weekendsOffRequest{}},
+		{"all with slug", []string{"--all", "goal", "on"}, 1, true, weekendsOffRequest{}},
+		{"single on", []string{"goal", "on"}, 0, false, weekendsOffRequest{goalSlug: "goal", on: true}},
+		{"single off", []string{"goal", "OFF"}, 0, false, weekendsOffRequest{goalSlug: "goal"}},
+		{"all on", []string{"--all", "on"}, 0, false, weekendsOffRequest{all: true, on: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, code, done := parseWeekendsOffArgs(tt.args, &bytes.Buffer{}, &bytes.Buffer{})
+			if code != tt.wantCode || done != tt.wantDone || req != tt.want {
+				t.Errorf("got (%+v, %d, %v), want (%+v, %d, %v)", req, code, done, tt.want, tt.wantCode, tt.wantDone)
+			}
+		})
+	}
+}
+
+func TestRunWeekendsOffCommand(t *testing.T) {
+	t.Run("single goal", func(t *testing.T) {
+		var gotSlug string
+		var gotOn bool
+		client := &FakeClient{UpdateGoalWeekendsOffFunc: func(slug string, on bool) (*Goal, error) {
+			gotSlug, gotOn = slug, on
+			return &Goal{Slug: slug, WeekendsOff: on}, nil
+		}}
+		var out, errb bytes.Buffer
+		code := runWeekendsOffCommand(weekendsOffRequest{goalSlug: "g", on: true}, client, &out, &errb)
+		if code != 0 || gotSlug != "g" || !gotOn || !strings.Contains(out.String(), "Turned weekends off on for g") {
+			t.Errorf("code=%d slug=%q on=%v out=%q err=%q", code, gotSlug, gotOn, out.String(), errb.String())
+		}
+	})
+
+	t.Run("update error", func(t *testing.T) {
+		client := &FakeClient{UpdateGoalWeekendsOffFunc: func(string, bool) (*Goal, error) { return nil, errors.New("boom") }}
+		var errb bytes.Buffer
+		code := runWeekendsOffCommand(weekendsOffRequest{goalSlug: "g"}, client, &bytes.Buffer{}, &errb)
+		if code != 1 || !strings.Contains(errb.String(), "Failed to update weekends off") {
+			t.Errorf("code=%d err=%q", code, errb.String())
+		}
+	})
+
+	t.Run("all updates only do-more goals needing a change", func(t *testing.T) {
+		var updated []string
+		client := &FakeClient{
+			FetchGoalsFunc: func() ([]Goal, error) {
+				return []Goal{
+					{Slug: "run", GoalType: "hustler"},
+					{Slug: "read", GoalType: "biker", WeekendsOff: true},
+					{Slug: "snacks", GoalType: "drinker"},
+					{Slug: "custom", GoalType: "custom", Yaw: 1, Dir: 1},
+				}, nil
+			},
+			UpdateGoalWeekendsOffFunc: func(slug string, on bool) (*Goal, error) {
+				updated = append(updated, slug)
+				return &Goal{Slug: slug, WeekendsOff: on}, nil
+			},
+		}
+		var out, errb bytes.Buffer
+		code := runWeekendsOffCommand(weekendsOffRequest{all: true, on: true}, client, &out, &errb)
+		if code != 0 || strings.Join(updated, ",") != "run,custom" {
+			t.Errorf("code=%d updated=%v out=%q err=%q", code, updated, out.String(), errb.String())
+		}
+	})
+
+	t.Run("all keeps going after a failure", func(t *testing.T) {
+		var updated []string
+		client := &FakeClient{
+			FetchGoalsFunc: func() ([]Goal, error) {
+				return []Goal{{Slug: "a", GoalType: "hustler"}, {Slug: "b", GoalType: "hustler"}}, nil
+			},
+			UpdateGoalWeekendsOffFunc: func(slug string, on bool) (*Goal, error) {
+				if slug == "a" {
+					return nil, errors.New("boom")
+				}
+				updated = append(updated, slug)
+				return &Goal{Slug: slug}, nil
+			},
+		}
+		var errb bytes.Buffer
+		code := runWeekendsOffCommand(weekendsOffRequest{all: true, on: true}, client, &bytes.Buffer{}, &errb)
+		if code != 1 || strings.Join(updated, ",") != "b" || !strings.Contains(errb.String(), "for a") {
+			t.Errorf("code=%d updated=%v err=%q", code, updated, errb.String())
+		}
+	})
+
+	t.Run("all with nothing to change", func(t *testing.T) {
+		client := &FakeClient{FetchGoalsFunc: func() ([]Goal, error) { return []Goal{{Slug: "a", GoalType: "hustler"}}, nil }}
+		var out bytes.Buffer
+		code := runWeekendsOffCommand(weekendsOffRequest{all: true}, client, &out, &bytes.Buffer{})
+		if code != 0 || !strings.Contains(out.String(), "already off") {
+			t.Errorf("code=%d out=%q", code, out.String())
+		}
+	})
+}
+
+func TestParseRenameArgs(t *testing.T) {
+	t.Run("help", func(t *testing.T) {
+		var out bytes.Buffer
+		_, code, done := parseRenameArgs([]string{"-h"}, &out, &bytes.Buffer{})
+		if !done || code != 0 || !strings.Contains(out.String(), "buzz rename") {
+			t.Errorf("done=%v code=%d out=%q", done, code, out.String())
+		}
+	})
+
+	t.Run("missing args", func(t *testing.T) {
+		var errb bytes.Buffer
+		_, code, done := parseRenameArgs([]string{"old"}, &bytes.Buffer{}, &errb)
+		if !done || code != 1 || !strings.Contains(errb.String(), "Missing required arguments") {
+			t.Errorf("done=%v code=%d err=%q", done, code, errb.String())
+		}
+	})
+
+	t.Run("same slug", func(t *testing.T) {
+		_, code, done := parseRenameArgs([]string{"run", "run"}, &bytes.Buffer{}, &bytes.Buffer{})
+		if !done || code != 1 {
+			t.Errorf("done=%v code=%d", done, code)
+		}
+	})
+
+	t.Run("valid with --yes", func(t *testing.T) {
+		req, _, done := parseRenameArgs([]string{"--yes", "run", "jog"}, &bytes.Buffer{}, &bytes.Buffer{})
+		if done || req != (renameRequest{oldSlug: "run", newSlug: "jog", skipConfirm: true}) {
+			t.Errorf("done=%v req=%+v", done, req)
+		}
+	})
+}
+
+func TestRunRenameCommand(t *testing.T) {
+	renamed := func(old, newSlug string) (*Goal, error) { return &Goal{Slug: newSlug}, nil }
+
+	t.Run("renames and updates saved filters", func(t *testing.T) {
+		setHome(t, t.TempDir())
+		if err := SaveConfig(&Config{Username: "u", Filters: map[string]string{
+			"cardio": "slug:run || slug:swim",
+			"urgent": "safebuf < 2",
+		}}); err != nil {
+			t.Fatal(err)
+		}
+		client := &FakeClient{RenameGoalFunc: renamed}
+		var out, errb bytes.Buffer
+		code := runRenameCommand(renameRequest{oldSlug: "run", newSlug: "jog", skipConfirm: true}, strings.NewReader(""), client, &out, &errb)
+		if code != 0 || !strings.Contains(out.String(), "Renamed run to jog") || !strings.Contains(out.String(), "Updated saved filters: cardio") {
+			t.Errorf("code=%d out=%q err=%q", code, out.String(), errb.String())
+		}
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if config.Filters["cardio"] != "slug:jog || slug:swim" || config.Filters["urgent"] != "safebuf < 2" {
+			t.Errorf("filters = %v", config.Filters)
+		}
+	})
+
+	t.Run("renames in groups", func(t *testing.T) {
+		setHome(t, t.TempDir())
+		if err := SaveConfig(&Config{Username: "u", Groups: map[string][]string{
+			"cardio":  {"run", "swim"},
+			"outdoor": {"jog", "run"},
+			"work":    {"write"},
+		}}); err != nil {
+			t.Fatal(err)
+		}
+		client := &FakeClient{RenameGoalFunc: renamed}
+		var out, errb bytes.Buffer
+		code := runRenameCommand(renameRequest{oldSlug: "run", newSlug: "jog", skipConfirm: true}, strings.NewReader(""), client, &out, &errb)
+		if code != 0 || !strings.Contains(out.String(), "Updated groups: cardio, outdoor") || strings.Contains(out.String(), "saved filters") {
+			t.Errorf("code=%d out=%q err=%q", code, out.String(), errb.String())
+		}
+		config, err := LoadConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := map[string][]string{"cardio": {"jog", "swim"}, "outdoor": {"jog"}, "work": {"write"}}
+		if !reflect.DeepEqual(config.Groups, want) {
+			t.Errorf("groups = %v, want %v", config.Groups, want)
+		}
+	})
+
+	t.Run("decline cancels without renaming", func(t *testing.T) {
+		called := false
+		client := &FakeClient{RenameGoalFunc: func(string, string) (*Goal, error) { called = true; return &Goal{}, nil }}
+		var out bytes.Buffer
+		code := runRenameCommand(renameRequest{oldSlug: "run", newSlug: "jog"}, strings.NewReader("n\n"), client, &out, &bytes.Buffer{})
+		if code != 0 || called || !strings.Contains(out.String(), "Cancelled") {
+			t.Errorf("code=%d called=%v out=%q", code, called, out.String())
+		}
+	})
+
+	t.Run("rename error", func(t *testing.T) {
+		setHome(t, t.TempDir())
+		client := &FakeClient{RenameGoalFunc: func(string, string) (*Goal, error) { return nil, errors.New("slug taken") }}
+		var errb bytes.Buffer
+		code := runRenameCommand(renameRequest{oldSlug: "run", newSlug: "jog"}, strings.NewReader("y\n"), client, &bytes.Buffer{}, &errb)
+		if code != 1 || !strings.Contains(errb.String(), "Failed to rename goal") {
+			t.Errorf("code=%d err=%q", code, errb.String())
+		}
+	})
+}
+
+func TestParseSecretArgs(t *testing.T) {
+	on, off := true, false
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		wantDone bool
+		want     secretRequest
+	}{
+		{"help", []string{"-h"}, 0, true, secretRequest{}},
+		{"missing goal", []string{}, 1, true, secretRequest{}},
+		{"missing state", []string{"goal"}, 1, true, secretRequest{}},
+		{"bad state", []string{"goal", "yes"}, 1, true, secretRequest{}},
+		{"bad data-public", []string{"--data-public", "maybe", "goal"}, 1, true, secretRequest{}},
+		{"secret on", []string{"goal", "on"}, 0, false, secretRequest{goalSlug: "goal", secret: &on}},
+		{"data public only", []string{"--data-public", "off", "goal"}, 0, false, secretRequest{goalSlug: "goal", dataPublic: &off}},
+		{"both", []string{"--data-public=on", "goal", "off"}, 0, false, secretRequest{goalSlug: "goal", secret: &off, dataPublic: &on}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, code, done := parseSecretArgs(tt.args, &bytes.Buffer{}, &bytes.Buffer{})
+			if code != tt.wantCode || done != tt.wantDone || !reflect.DeepEqual(req, tt.want) {
+				t.Errorf("got (%+v, %d, %v), want (%+v, %d, %v)", req, code, done, tt.want, tt.wantCode, tt.wantDone)
+			}
+		})
+	}
+}
+
+func TestRunSecretCommand(t *testing.T) {
+	t.Run("updates visibility", func(t *testing.T) {
+		on := true
+		var gotSecret, gotDataPublic *bool
+		client := &FakeClient{UpdateGoalVisibilityFunc: func(slug string, secret, dataPublic *bool) (*Goal, error) {
+			gotSecret, gotDataPublic = secret, dataPublic
+			return &Goal{Slug: slug, Secret: true}, nil
+		}}
+		var out, errb bytes.Buffer
+		code := runSecretCommand(secretRequest{goalSlug: "g", secret: &on}, client, &out, &errb)
+		if code != 0 || gotSecret == nil || !*gotSecret || gotDataPublic != nil {
+			t.Errorf("code=%d secret=%v dataPublic=%v err=%q", code, gotSecret, gotDataPublic, errb.String())
+		}
+		if !strings.Contains(out.String(), "Updated g: "+secretMarker+" secret, data private") {
+			t.Errorf("out=%q", out.String())
+		}
+	})
+
+	t.Run("update error", func(t *testing.T) {
+		client := &FakeClient{UpdateGoalVisibilityFunc: func(string, *bool, *bool) (*Goal, error) { return nil, errors.New("boom") }}
+		var errb bytes.Buffer
+		code := runSecretCommand(secretRequest{goalSlug: "g"}, client, &bytes.Buffer{}, &errb)
+		if code != 1 || !strings.Contains(errb.String(), "Failed to update goal visibility") {
+			t.Errorf("code=%d err=%q", code, errb.String())
+		}
+	})
+}
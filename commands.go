@@ -0,0 +1,1003 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// usageLine is one syntax/description pair in the help screen.
+type usageLine struct {
+	syntax string // full invocation, e.g. "buzz add <goalslug> <value>"
+	desc   string // what that invocation does
+}
+
+// commandDef is the single definition of a subcommand: how it is dispatched
+// and how it is documented. printHelp, the unknown-command hint, and `buzz
+// docs` are all generated from these, so the help text can't drift from the
+// commands that actually exist.
+type commandDef struct {
+	name    string      // dispatch name, e.g. "add"
+	aliases []string    // other spellings that dispatch here, e.g. "--help"
+	summary string      // one-line description for indexes and man page NAME sections
+	usage   []usageLine // syntax/description pairs shown in `buzz help`
+	notes   []string    // extra lines under the usage in the top-level `buzz help` (per-command help lists flags instead)
+
+	// Per-command help (`buzz help <command>` / `buzz <command> --help`)
+	flags     []usageLine // flag/description pairs
+	examples  []string    // complete example invocations
+	exitCodes []usageLine // code/meaning pairs; nil means defaultExitCodes
+
+	run func()
+}
+
+// defaultExitCodes is what most commands return.
+var defaultExitCodes = []usageLine{
+	{"0", "Success"},
+	{"1", "Error: bad arguments, not authenticated, or the Beeminder request failed"},
+}
+
+// flagErrorExitCodes is for commands that reserve exit code 2 for flags they
+// can't parse, separately from other errors.
+var flagErrorExitCodes = []usageLine{
+	{"0", "Success"},
+	{"1", "Error: bad arguments, not authenticated, or the Beeminder request failed"},
+	{"2", "Invalid or unknown flags"},
+}
+
+// Every command that can show what it would do without doing it spells the
+// flag --dry-run, and also takes --dryrun, which add and charge first used
+// (see dryRunFlag). Their flags list both.
+
+// helpColumn is the width of the syntax column in `buzz help`; longer syntax
+// puts its description on the following line.
+const helpColumn = 34
+
+// globalOptions documents the flags main extracts before dispatch.
+var globalOptions = []usageLine{
+	{"--format <table|json|jsonl|csv>", "Output format for the list commands, data, and next (default: table)"},
+	{"--jsonl", "Shorthand for --format jsonl: one JSON object per line, written as it is fetched"},
+	{"--filter <name|expr>", "Filter the list commands and the TUI by a saved filter or an expression"},
+	{"--no-color", "Disable colored output"},
+	{"--no-update-check", "Don't check for or mention buzz updates"},
+	{"-h, --help", "Show this help message"},
+	{"-v, --version", "Show version information"},
+}
+
+// allCommands returns every subcommand in help order. It is a function rather
+// than a package-level var because the help command's run refers back to it.
+func allCommands() []commandDef {
+	return []commandDef{
+		{
+			name:    "next",
+			summary: "Output a terse summary of the next due goal",
+			usage: []usageLine{
+				{"buzz next", "Output a terse summary of the next due goal"},
+				{"buzz next --watch", "Watch mode - continuously refresh every 5 minutes"},
+				{"buzz next -w", "Watch mode (shorthand)"},
+			},
+			flags:     []usageLine{{"-w, --watch", "Refresh every 5 minutes until interrupted"}},
+			examples:  []string{"buzz next", "buzz next --watch", "buzz --format json next"},
+			exitCodes: flagErrorExitCodes,
+			run:       handleNextCommand,
+		},
+		{
+			name:    "list",
+			summary: "List all goals with slug, title, units, rate, and stakes",
+			usage: []usageLine{
+				{"buzz list", "List all goals with slug, title, units, rate, and stakes"},
+				{"buzz list --archived", "List archived goals instead of active ones"},
+			},
+			flags:    []usageLine{{"--archived", "List archived goals instead of active ones"}},
+			examples: []string{"buzz list", "buzz list --archived", "buzz --format csv list"},
+			run:      handleListCommand,
+		},
+		{
+			name:     "all",
+			summary:  "Output all goals",
+			usage:    []usageLine{{"buzz all", "Output all goals"}},
+			examples: []string{"buzz all", "buzz --format json all"},
+			run:      handleAllCommand,
+		},
+		{
+			name:    "today",
+			summary: "Output all goals due today",
+			usage: []usageLine{
+				{"buzz today", "Output all goals due today"},
+				{"buzz today --group-by deadline-hour", "Group today's goals under their deadline time"},
+				{"buzz today --total", "End with the day's bare minimums summed by unit"},
+			},
+			flags: []usageLine{
+				{"--group-by deadline-hour", "Group goals under headings like \"by 2pm:\""},
+				{"--total", "Sum the bare minimums by unit, e.g. \"Total: 2.5 hours, 40 pushups\""},
+			},
+			examples:  []string{"buzz today", "buzz today --group-by deadline-hour", "buzz today --total", "buzz --no-color today"},
+			exitCodes: flagErrorExitCodes,
+			run:       handleTodayCommand,
+		},
+		{
+			name:     "tomorrow",
+			summary:  "Output all goals due tomorrow",
+			usage:    []usageLine{{"buzz tomorrow", "Output all goals due tomorrow"}},
+			examples: []string{"buzz tomorrow"},
+			run:      handleTomorrowCommand,
+		},
+		{
+			name:    "due",
+			summary: "Output all goals due within a duration",
+			usage: []usageLine{
+				{"buzz due <duration>", "Output all goals due within duration (e.g., 10m, 1h, 5d, 1w)"},
+				{"buzz due --within <duration>", "The same, with the duration as a flag"},
+			},
+			flags:     []usageLine{{"--within <duration>", "List goals whose deadline is within duration"}},
+			examples:  []string{"buzz due 1h", "buzz due --within 6h", "buzz due 3d", "buzz due 1w"},
+			exitCodes: flagErrorExitCodes,
+			run:       handleDueCommand,
+		},
+		{
+			name:    "plan",
+			summary: "Plan today's bare minimums, optionally as a time-blocked schedule",
+			usage: []usageLine{
+				{"buzz plan", "List today's bare minimums as a checklist, soonest deadline first"},
+				{"buzz plan --blocks [--start <time>] [--block <duration>]", "Propose a time block for each one, before its deadline where possible"},
+				{"buzz plan --ics > today.ics", "Write the blocks as an iCalendar file to import into a calendar"},
+			},
+			notes: []string{
+				"Goals measured in time (hours or minutes, or a bare minimum like +1:30) get a block as long as their bare minimum; the others get --block (default 30m). Blocks start at --start or now, whichever is later, and run back to back in deadline order; one that would end after its goal's deadline is flagged. Do-less goals are left out.",
+			},
+			flags: []usageLine{
+				{"--blocks", "Propose a time-blocked schedule"},
+				{"--ics", "Write the schedule as iCalendar (implies --blocks)"},
+				{"--start <time>", "Time to start the first block, e.g. 9:00 AM or 09:00 (default now)"},
+				{"--block <duration>", "Block length for goals not measured in time (default 30m)"},
+			},
+			examples:  []string{"buzz plan", "buzz plan --blocks", "buzz plan --blocks --start 9:00 --block 20m", "buzz plan --ics > today.ics"},
+			exitCodes: flagErrorExitCodes,
+			run:       handlePlanCommand,
+		},
+		{
+			name:      "cal",
+			summary:   "Show a calendar of the goals that derail each day",
+			usage:     []usageLine{{"buzz cal [days]", "Show the next days (default 7; e.g. 14 or 30) as a Monday-first calendar, listing the goals that derail each day"}},
+			notes:     []string{"Goals are coloured by urgency. A goal with a deadline after midnight is shown on the day before, the day Beeminder counts it for; goals already past their deadline are shown on today. The global --filter narrows the goals shown."},
+			examples:  []string{"buzz cal", "buzz cal 14", "buzz cal 30", "buzz --format json cal 14"},
+			exitCodes: flagErrorExitCodes,
+			run:       handleCalCommand,
+		},
+		{
+			name:     "less",
+			summary:  "Output all do-less type goals",
+			usage:    []usageLine{{"buzz less", "Output all do-less type goals"}},
+			examples: []string{"buzz less"},
+			run:      handleLessCommand,
+		},
+		{
+			name:    "goals",
+			summary: "List goals with chosen columns, filters, and sort order",
+			usage: []usageLine{
+				{"buzz goals", "List goals with slug, pledge, baremin, safebuf, deadline, and autodata"},
+				{"buzz goals [flags]", "Filter, sort, and pick the columns with the flags below"},
+			},
+			notes: []string{
+				"Columns: slug, title, type, units, rate, pledge, baremin, safebuf, due, deadline, autodata. The global --filter narrows the goals listed too.",
+			},
+			flags: []usageLine{
+				{"--color <color>", "Only goals of this urgency colour (red, orange, blue, green, gray)"},
+				{"--min-pledge <amount>", "Only goals pledging at least amount"},
+				{"--type <type>", "Only goals of this type (hustler, biker, fatloser, gainer, inboxer, drinker)"},
+				{"--sort <order>", "Sort by due (default), slug, pledge, or safebuf"},
+				{"--columns <list>", "Comma-separated columns to show"},
+				{"--by-group", "List the goals in a section per group from ~/.buzzrc"},
+			},
+			examples: []string{
+				"buzz goals --color red",
+				"buzz goals --type drinker --sort slug",
+				"buzz goals --min-pledge 30 --sort pledge",
+				"buzz goals --columns slug,title,rate",
+				"buzz goals --by-group",
+			},
+			exitCodes: flagErrorExitCodes,
+			run:       handleGoalsCommand,
+		},
+		{
+			name:    "groups",
+			summary: "Show how many goals in each group are on track this week",
+			usage:   []usageLine{{"buzz groups", "Roll up each goal group from ~/.buzzrc, e.g. \"health: 3/4 on track this week\""}},
+			notes: []string{
+				"Define groups under \"groups\" in ~/.buzzrc, e.g. \"groups\": {\"health\": [\"run\", \"lift\", \"sleep\"]}. A goal is on track when it's safe through Sunday midnight. Filter a group as a unit with group:health in --filter.",
+			},
+			examples:  []string{"buzz groups", "buzz goals --filter group:health", "buzz --format json groups"},
+			exitCodes: flagErrorExitCodes,
+			run:       handleGroupsCommand,
+		},
+		{
+			name:    "exposure",
+			summary: "Total the pledges at stake on goals due within a duration",
+			usage:   []usageLine{{"buzz exposure [duration]", "Sum the pledges of goals that derail if nothing is done within duration (default 48h)"}},
+			notes: []string{
+				"Goals that have reached their end value are left out. The global --filter narrows the goals counted.",
+			},
+			examples:  []string{"buzz exposure", "buzz exposure 1w", "buzz --format json exposure 3d"},
+			exitCodes: flagErrorExitCodes,
+			run:       handleExposureCommand,
+		},
+		{
+			name:     "buffer",
+			summary:  "Show a histogram of goals by safety buffer",
+			usage:    []usageLine{{"buzz buffer", "Count goals with 0, 1, 2, 3-6, and 7+ days of safety buffer"}},
+			notes:    []string{"The TUI footer shows the same counts in one line. The global --filter narrows the goals counted."},
+			examples: []string{"buzz buffer", "buzz --format json buffer"},
+			run:      handleBufferCommand,
+		},
+		{
+			name:    "whoami",
+			summary: "Show the account buzz is logged in as",
+			usage: []usageLine{
+				{"buzz whoami", "Print your username, timezone, urgency load, plan, and number of goals"},
+			},
+			notes:     []string{"Exits non-zero when buzz isn't logged in or the token is rejected, so scripts can use it as an auth check."},
+			examples:  []string{"buzz whoami", "buzz --format json whoami", "buzz whoami >/dev/null || buzz auth login"},
+			exitCodes: flagErrorExitCodes,
+			run:       handleWhoamiCommand,
+		},
+		{
+			name:    "stats",
+			summary: "Summarize the account: goals by colour, money pledged, autodata, and what's due",
+			usage: []usageLine{
+				{"buzz stats", "Print total goals, counts per buffer colour, total pledged, manual vs. autodata goals, and goals due in the next 24h"},
+				{"buzz stats --self", "Show how often you've run each buzz command, counted locally and never sent anywhere"},
+			},
+			notes:     []string{"The global --filter narrows the goals counted."},
+			flags:     []usageLine{{"--self", "Show your own command usage, kept in ~/.buzz_usage"}},
+			examples:  []string{"buzz stats", "buzz --format json stats", "buzz --filter tag:work stats", "buzz stats --self"},
+			exitCodes: flagErrorExitCodes,
+			run:       handleStatsCommand,
+		},
+		{
+			name:    "report",
+			summary: "Report on goals that look unused, to consider archiving",
+			usage:   []usageLine{{"buzz report --unused [--no-prompt]", "List goals that look unused, then ask about archiving each one"}},
+			notes: []string{
+				"A goal is listed when at least two of these hold: its rate is under 1 unit a month (or flat), it has more than 30 days of buffer, and it has had no datapoint in 90 days (goals with autodata are exempt from this one).",
+				"Beeminder's API can't archive goals, so answering yes opens the goal on beeminder.com to archive it there. Buzz only asks when run in a terminal.",
+			},
+			flags: []usageLine{
+				{"--unused", "List goals that look unused"},
+				{"--no-prompt", "Just list them, without asking about each one"},
+			},
+			examples:  []string{"buzz report --unused", "buzz --format csv report --unused"},
+			exitCodes: flagErrorExitCodes,
+			run:       handleReportCommand,
+		},
+		{
+			name:    "share",
+			summary: "Write a shareable summary of goal adherence for a coach or partner",
+			usage:   []usageLine{{"buzz share [flags]", "Write a markdown summary of the last 7 days: days logged, datapoints, totals, and buffer per goal"}},
+			notes: []string{
+				"Secret goals are left out unless named in --goals. The global --filter narrows the goals shared.",
+			},
+			flags: []usageLine{
+				{"--week", "Cover this week since Monday instead of the last 7 days"},
+				{"--redact-amounts", "Leave out the pledge amounts"},
+				{"--goals <slug,...>", "Share only these goals"},
+				{"--html", "Write a standalone HTML page instead of markdown"},
+			},
+			examples:  []string{"buzz share --week --redact-amounts", "buzz share --goals exercise,reading > week.md", "buzz share --html > week.html"},
+			exitCodes: flagErrorExitCodes,
+			run:       handleShareCommand,
+		},
+		{
+			name:    "add",
+			summary: "Add a datapoint to a goal",
+			usage: []usageLine{
+				{"buzz add [--requestid=<id>|--no-requestid] [--date=<date>] <goalslug> <value> [comment]", "Add a datapoint to a goal"},
+				{"echo \"<value>\" | buzz add [--requestid=<id>|--no-requestid] [--date=<date>] <goalslug> [comment]", "Add a datapoint with value from stdin"},
+				{"echo \"<comment>\" | buzz add [flags] <goalslug> <value> --comment-stdin", "Add a datapoint with the comment from stdin"},
+				{"buzz add --bulk -|--file=<path>", "Add datapoints from CSV lines of slug,value,comment[,date]"},
+			},
+			notes: []string{
+				"--date: Date in YYYY-MM-DD format, up to a day ahead (default: current time); --daystamp takes YYYYMMDD and --timestamp a Unix time",
+				"Note: Flags must come BEFORE positional args",
+				"<value> may be a number, a time like 1:30 (converted to the goal's units, so 90 for a goal in minutes), or an arithmetic expression like 3*12+5. Run from a terminal with no value and nothing piped, buzz add prompts for the value, showing the goal's last one.",
+				"--lb and --kg only apply to weight goals (fatloser or gainer) measured in lb or kg.",
+				"Without --requestid, a request ID is derived from the date, goal, and value, so re-running the same add on the same day (from shell history, or a retrying script) is ignored rather than logged twice. Use --no-requestid to log the same value again on purpose, or set \"no_auto_requestid\": true in ~/.buzzrc to turn this off.",
+			},
+			flags: []usageLine{
+				{"--requestid=<id>", "Idempotency key; retrying with the same ID won't create a duplicate (default: derived from date, goal, and value)"},
+				{"--no-requestid", "Don't derive a request ID, so an identical add logs another datapoint"},
+				{"--comment-stdin", "Read the comment from the first line of stdin (may follow the positional args)"},
+				{"--date=<date>", "Date for the datapoint in YYYY-MM-DD format, at most a day ahead (default: now)"},
+				{"--daystamp=<date>", "Date for the datapoint in YYYYMMDD format"},
+				{"--timestamp=<epoch>", "Time of the datapoint as a Unix timestamp in seconds, at most a day ahead"},
+				{"--lb, --kg", "The value is in pounds or kilograms; converted to a weight goal's units"},
+				{"--dry-run, --dryrun", "Show roughly what the datapoint would do to the goal's buffer and value, without adding it"},
+				{"--bulk", "Add many datapoints, one per CSV line of slug,value,comment[,date], from stdin (-) or --file"},
+				{"--file=<path>", "CSV file for --bulk"},
+			},
+			examples: []string{
+				"buzz add opsec 1",
+				"buzz add workout 2.5 'morning run'",
+				"buzz add study 1:30",
+				"buzz add pushups '3*12+5'",
+				"buzz add --kg weight 80.5",
+				"buzz add --daystamp=20240115 exercise 1",
+				"buzz add --date=2024-01-15 exercise 1 'logged the next morning'",
+				"echo 3 | buzz add reading",
+				"git log -1 --format=%s | buzz add commits 1 --comment-stdin",
+				"buzz add --bulk --file=export.csv",
+				"buzz add --dry-run weight 80.5",
+			},
+			run: handleAddCommand,
+		},
+		{
+			name:    "simulate",
+			summary: "Show what adding a value would do to a goal, without adding it",
+			usage:   []usageLine{{"buzz simulate <goalslug> <value>", "Show the goal's safety buffer and value before and after adding <value> today"}},
+			notes: []string{
+				"The result is worked out locally from the goal's bright red line and datapoints, so it's an estimate; nothing is sent to Beeminder.",
+				"<value> may be a number, a time like 1:30, or an expression like 3*12+5, as with buzz add.",
+				"In the TUI's add-datapoint form, press ? in the date or value field for the same preview.",
+			},
+			examples:  []string{"buzz simulate reading 30", "buzz simulate study 0:30"},
+			exitCodes: flagErrorExitCodes,
+			run:       handleSimulateCommand,
+		},
+		{
+			name:    "pom",
+			summary: "Run a pomodoro timer and log the session to a goal",
+			usage:   []usageLine{{"buzz pom [--units hours|minutes|count] [--comment <text>] <goalslug> [duration]", "Count down duration (default 25m), then log it to the goal"}},
+			flags: []usageLine{
+				{"--units <unit>", "Log decimal hours (default), minutes, or a count of 1 (default from pom_units in ~/.buzzrc)"},
+				{"--comment <text>", "Datapoint comment (default: \"<duration> pomodoro\")"},
+			},
+			notes:     []string{"Press Ctrl+C to abandon a session without logging anything."},
+			examples:  []string{"buzz pom writing", "buzz pom writing 50m", "buzz pom --units count reading"},
+			exitCodes: flagErrorExitCodes,
+			run:       handlePomCommand,
+		},
+		{
+			name:    "inbox",
+			summary: "Count a mailbox and submit the count to an inbox-zero goal",
+			usage:   []usageLine{{"buzz inbox (--imap | --maildir <path>) [--unread] [--dry-run] <goalslug>", "Count messages in a mailbox and add the count as a datapoint"}},
+			flags: []usageLine{
+				{"--imap", "Count the IMAP mailbox configured under \"imap\" in ~/.buzzrc (over TLS)"},
+				{"--maildir <path>", "Count the messages in a local Maildir"},
+				{"--unread", "Count only unread messages (default: every message)"},
+				{"--dry-run, --dryrun", "Print the count without submitting it"},
+			},
+			notes: []string{
+				"Run it from cron to keep an inboxer goal current without IFTTT.",
+			},
+			examples: []string{
+				"buzz inbox --imap email",
+				"buzz inbox --maildir ~/Mail/INBOX --unread email",
+				"buzz inbox --imap --dry-run email",
+			},
+			exitCodes: flagErrorExitCodes,
+			run:       handleInboxCommand,
+		},
+		{
+			name:    "autodata",
+			summary: "Submit a goal's datapoints from another service",
+			usage: []usageLine{
+				{"buzz autodata github [--user <login>] [--dry-run] --goal <goalslug>", "Submit today's GitHub contribution count"},
+				{"buzz autodata http --url <url> --jsonpath <path> [--header 'Name: value']... [--dry-run] --goal <goalslug>", "Submit a number read from a JSON URL"},
+				{"buzz autodata wordcount --glob <pattern> [--mode total|delta] [--dry-run] --goal <goalslug>", "Submit the word count of a set of files"},
+			},
+			flags: []usageLine{
+				{"--goal <goalslug>", "Goal to submit today's value to (required)"},
+				{"--user <login>", "github: GitHub login to count (default: me, the token's own account)"},
+				{"--url <url>", "http: URL to fetch; the response must be JSON (required)"},
+				{"--jsonpath <path>", "http: where the number is, e.g. $.count or .data[0].total (required)"},
+				{"--header 'Name: value'", "http: request header to send (repeatable)"},
+				{"--glob <pattern>", "wordcount: files to count; ** matches any number of directories (required)"},
+				{"--mode total|delta", "wordcount: submit the total (default for odometer goals) or the words added since the last run"},
+				{"--dry-run, --dryrun", "Print the value without submitting it"},
+			},
+			notes: []string{
+				"Each source submits one datapoint per day: running it again (from cron, say) updates that day's value instead of adding another.",
+				"github: the token comes from \"github_token\" in ~/.buzzrc, or $GITHUB_TOKEN.",
+				"wordcount --mode delta adds a datapoint per run and remembers the last total in ~/.buzz_wordcount_state; the first run only records a baseline.",
+			},
+			examples: []string{
+				"buzz autodata github --goal commits",
+				"buzz autodata github --user octocat --dry-run --goal commits",
+				"buzz autodata http --url https://example.com/stats.json --jsonpath $.count --goal x",
+				"buzz autodata wordcount --glob 'drafts/**/*.md' --goal writing",
+			},
+			exitCodes: flagErrorExitCodes,
+			run:       handleAutodataCommand,
+		},
+		{
+			name:    "import",
+			summary: "Backfill a goal's datapoints from another service's data",
+			usage: []usageLine{
+				{"buzz import health --file <export> --metric <metric> [--since <date>] [--dry-run] --goal <goalslug>", "Import an Apple Health or Google Fit export"},
+				{"buzz import rescuetime [--date <date>] [--dry-run] --goal <goalslug>", "Submit a day's productive hours from RescueTime"},
+			},
+			flags: []usageLine{
+				{"--file <export>", "health: Apple Health export.zip or export.xml, or Google Fit Takeout .zip or \"Daily activity metrics.csv\" (required)"},
+				{"--metric <metric>", "health: steps, weight, or distance (required)"},
+				{"--goal <goalslug>", "Goal to backfill, one datapoint per day (required)"},
+				{"--since <date>", "health: skip days before this date (YYYY-MM-DD)"},
+				{"--date <date>", "rescuetime: day to import (YYYY-MM-DD, default: today)"},
+				{"--dry-run, --dryrun", "Print the values without submitting anything"},
+			},
+			notes: []string{
+				"Each day's datapoint has a request ID derived from the source, metric, and date, so importing an overlapping export again updates those days instead of duplicating them.",
+				"health: weights are submitted in lb when the goal's units are lb, otherwise kg; distances in miles when the goal's units are miles, otherwise km.",
+				"rescuetime: submits the day's productive and very productive time as decimal hours. The API key comes from \"rescuetime_key\" in ~/.buzzrc, or $RESCUETIME_KEY.",
+			},
+			examples: []string{
+				"buzz import health --file export.zip --metric steps --goal steps",
+				"buzz import health --file export.xml --metric weight --since 2026-01-01 --dry-run --goal weight",
+				"buzz import rescuetime --goal productive-time",
+			},
+			exitCodes: flagErrorExitCodes,
+			run:       handleImportCommand,
+		},
+		{
+			name:    "odometer-reset",
+			summary: "Record that an odometer goal's odometer was reset",
+			usage:   []usageLine{{"buzz odometer-reset <goalslug>", "Add the zero datapoint that marks an odometer reset"}},
+			notes: []string{
+				"Only for odometer (biker) goals. Readings after the reset are counted on top of the total before it, so keep entering what the odometer shows.",
+			},
+			examples: []string{"buzz odometer-reset bike"},
+			run:      handleOdometerResetCommand,
+		},
+		{
+			name:     "refresh",
+			summary:  "Refresh autodata for a goal",
+			usage:    []usageLine{{"buzz refresh <goalslug>", "Refresh autodata for a goal"}},
+			examples: []string{"buzz refresh fitbit"},
+			run:      handleRefreshCommand,
+		},
+		{
+			name:    "view",
+			summary: "View detailed information about a specific goal",
+			usage: []usageLine{
+				{"buzz view <goalslug>", "View detailed information about a specific goal"},
+				{"buzz view <goalslug> --web", "Open the goal in the browser"},
+				{"buzz view <goalslug> --qr", "Print the goal's URL and a QR code of it"},
+				{"buzz view <goalslug> --json", "Output goal data as JSON"},
+				{"buzz view <goalslug> --json --datapoints", "Include datapoints in JSON output"},
+			},
+			flags: []usageLine{
+				{"--web", "Open the goal in the browser (or print its URL where there's no display)"},
+				{"--qr", "Print the goal's URL with a QR code to scan, instead of opening it"},
+				{"--json", "Output the goal as JSON"},
+				{"--datapoints", "Include datapoints (with --json)"},
+			},
+			examples:  []string{"buzz view exercise", "buzz view exercise --json --datapoints"},
+			exitCodes: flagErrorExitCodes,
+			run:       handleViewCommand,
+		},
+		{
+			name:    "open",
+			summary: "Open your Beeminder dashboard or a goal's page in the browser",
+			usage: []usageLine{
+				{"buzz open", "Open your Beeminder dashboard"},
+				{"buzz open <goalslug>", "Open the goal's page, like buzz view <goalslug> --web"},
+			},
+			flags:     []usageLine{{"--qr", "Print the URL with a QR code to scan, instead of opening it"}},
+			examples:  []string{"buzz open", "buzz open exercise"},
+			exitCodes: flagErrorExitCodes,
+			run:       handleOpenCommand,
+		},
+		{
+			name:    "graph",
+			summary: "Print a goal's chart and exit",
+			usage:   []usageLine{{"buzz graph <goalslug> [flags]", "Print the chart the review screen draws for the goal: datapoints against the bright red line"}},
+			notes: []string{
+				"Flags may come before or after the goal. With --no-color the chart is plain text, for scripts and tmux popups.",
+			},
+			flags: []usageLine{
+				{"--days <n|all>", "Chart the last n days, or all of the goal's data (default: the goal's graph window)"},
+				{"--width <columns>", "Width of the chart, axis labels included (default 80)"},
+				{"--zoom", "Scale the Y axis to the datapoints"},
+			},
+			examples:  []string{"buzz graph pushups", "buzz graph pushups --days 60 --width 100", "buzz --no-color graph pushups --days all"},
+			exitCodes: flagErrorExitCodes,
+			run:       handleGraphCommand,
+		},
+		{
+			name:    "datapoints",
+			summary: "List a goal's most recent datapoints with their ids",
+			usage:   []usageLine{{"buzz datapoints [--count N] [--json] <goalslug>", "List a goal's recent datapoints, newest first (daystamp, value, comment, id)"}},
+			flags: []usageLine{
+				{"--count N", "How many datapoints to list (default 10)"},
+				{"--json", "Output the datapoints as JSON"},
+			},
+			examples:  []string{"buzz datapoints exercise", "buzz datapoints exercise --count 3", "buzz datapoints exercise --json"},
+			exitCodes: flagErrorExitCodes,
+			run:       handleDatapointsCommand,
+		},
+		{
+			name:    "edit-datapoint",
+			summary: "Change an existing datapoint's value, comment, or date",
+			usage:   []usageLine{{"buzz edit-datapoint <goalslug> <id> [--value V] [--comment C] [--date D|--daystamp D]", "Update a datapoint (find its id with buzz datapoints)"}},
+			flags: []usageLine{
+				{"--value V", "New value: a number, time, or expression, as for buzz add"},
+				{"--comment C", "New comment (\"\" clears it)"},
+				{"--date D", "Move the datapoint to this day (YYYY-MM-DD or YYYYMMDD)"},
+				{"--daystamp D", "Move the datapoint to this day (YYYYMMDD), as for buzz add"},
+			},
+			examples:  []string{"buzz edit-datapoint exercise 5f1e2d --value 3.5", "buzz edit-datapoint reading 5f1e2d --comment \"ch. 4-6\" --date 2024-01-15"},
+			exitCodes: flagErrorExitCodes,
+			run:       handleEditDatapointCommand,
+		},
+		{
+			name:      "delete-datapoint",
+			summary:   "Delete a datapoint",
+			usage:     []usageLine{{"buzz delete-datapoint [--yes|-y] <goalslug> <id>", "Delete a datapoint (find its id with buzz datapoints)"}},
+			flags:     []usageLine{{"--yes, -y", "Skip the confirmation prompt"}},
+			examples:  []string{"buzz delete-datapoint exercise 5f1e2d", "buzz delete-datapoint --yes exercise 5f1e2d"},
+			exitCodes: flagErrorExitCodes,
+			run:       handleDeleteDatapointCommand,
+		},
+		{
+			name:    "undo",
+			summary: "Delete the datapoint buzz add last added to a goal",
+			usage:   []usageLine{{"buzz undo [--yes|-y] [--force] <goalslug>", "Show the datapoint buzz add last added to the goal and delete it"}},
+			flags: []usageLine{
+				{"--yes, -y", "Skip the confirmation prompt"},
+				{"--force", "Delete the latest datapoint even if buzz add didn't create it"},
+			},
+			examples:  []string{"buzz undo exercise", "buzz undo --force exercise"},
+			exitCodes: flagErrorExitCodes,
+			run:       handleUndoCommand,
+		},
+		{
+			name:    "journal",
+			summary: "Show the changes buzz has made to your account",
+			usage:   []usageLine{{"buzz journal [--goal=<slug>] [--limit=<n>] [--json]", "Show the latest changes buzz made: datapoints added, edited, and deleted, goals created and changed, and charges"}},
+			flags: []usageLine{
+				{"--goal=<slug>", "Only show changes to this goal"},
+				{"--limit=<n>", "Show the last <n> changes (default: 20; 0 for all)"},
+				{"--json", "Output the changes as JSON"},
+			},
+			examples:  []string{"buzz journal", "buzz journal --goal exercise --limit 0"},
+			exitCodes: flagErrorExitCodes,
+			run:       handleJournalCommand,
+		},
+		{
+			name:    "data",
+			summary: "List a goal's datapoints",
+			usage:   []usageLine{{"buzz data [--asc|--desc] <goalslug>", "List a goal's datapoints (date, value, comment)"}},
+			notes:   []string{"--asc: oldest-first (default)  --desc: newest-first"},
+			flags: []usageLine{
+				{"--asc", "Oldest first (default)"},
+				{"--desc", "Newest first"},
+			},
+			examples:  []string{"buzz data exercise", "buzz data --desc exercise", "buzz --format csv data exercise"},
+			exitCodes: flagErrorExitCodes,
+			run:       handleDataCommand,
+		},
+		{
+			name:     "review",
+			summary:  "Interactive review of all goals",
+			usage:    []usageLine{{"buzz review", "Interactive review of all goals"}},
+			examples: []string{"buzz review"},
+			run:      handleReviewCommand,
+		},
+		{
+			name:    "watch",
+			summary: "Full-screen wallboard that rotates through goal pages",
+			usage:   []usageLine{{"buzz watch [--pages today,chart,activity] [--interval 30s]", "Rotate through goals due today, the most urgent goal's chart, and yesterday's datapoints"}},
+			flags: []usageLine{
+				{"--pages <list>", "Pages to rotate through, in order (default: today,chart,activity)"},
+				{"--interval <duration>", "How long each page is shown (default: 30s, minimum: 5s)"},
+			},
+			notes:     []string{"Data is refetched at the start of every rotation. Press q or Ctrl+C to exit."},
+			examples:  []string{"buzz watch", "buzz watch --pages today,chart --interval 1m"},
+			exitCodes: flagErrorExitCodes,
+			run:       handleWatchCommand,
+		},
+		{
+			name:      "focus",
+			summary:   "Full-screen view of one goal with a field to add to it",
+			usage:     []usageLine{{"buzz focus <goalslug>", "Show a big countdown, the chart, and recent datapoints, with a field to add datapoints, until the goal is safe for today"}},
+			notes:     []string{"The goal is refetched after each add and every minute. Once a goal that was due becomes safe, buzz celebrates and exits. Press Esc or Ctrl+C to exit sooner."},
+			examples:  []string{"buzz focus pushups"},
+			exitCodes: flagErrorExitCodes,
+			run:       handleFocusCommand,
+		},
+		{
+			name:     "charge",
+			summary:  "Create a charge for the authenticated user",
+			usage:    []usageLine{{"buzz charge <amount> <note> [--dry-run]", "Create a charge for the authenticated user"}},
+			flags:    []usageLine{{"--dry-run, --dryrun", "Validate the charge without creating it"}},
+			examples: []string{"buzz charge 10 'Missed workout' --dry-run", "buzz charge 10 'Missed workout'"},
+			run:      handleChargeCommand,
+		},
+		{
+			name:    "create",
+			summary: "Create a new Beeminder goal",
+			usage: []usageLine{
+				{"buzz create", "Interactively create a new Beeminder goal"},
+				{"buzz create --slug=<s> --units=<u> [--title --type --goaldate --goalval --rate --deadline]", "Non-interactively create a goal (see --help)"},
+				{"buzz create <slug> [title] --units=<u> [flags]", "The same, with the slug and title as arguments"},
+				{"buzz create --template <name> [flags] [slug [title]]", "Create a goal from a template saved in ~/.buzzrc"},
+				{"buzz create --from <manifest> [--template <name>] [--dry-run]", "Create every goal in a YAML or CSV manifest"},
+			},
+			flags: []usageLine{
+				{"--template <name>", "Start from a saved template (flags override its settings)"},
+				{"--from <manifest>", "Create the goals listed in a .yaml, .yml, .json, or .csv file"},
+				{"--dry-run, --dryrun", "With --from, show what would be created without creating anything"},
+				{"--slug <slug>", "Goal slug (required, unless given as an argument)"},
+				{"--units <units>", "Goal units (required); --gunits is the same"},
+				{"--title <title>", "Goal title (default: the slug)"},
+				{"--type <type>", "Goal type name, label, or menu number (default: hustler)"},
+				{"--goaldate <epoch>", "Goal date as an epoch timestamp"},
+				{"--goalval <value>", "Goal value"},
+				{"--rate <rate>", "Rate"},
+				{"--deadline <seconds>", "Deadline in seconds from midnight (may be negative)"},
+				{"--json", "Print the created goal as JSON instead of progress messages"},
+			},
+			examples: []string{
+				"buzz create",
+				"buzz create --slug=pushups --units=reps --goalval=1000 --rate=10",
+				"buzz create --template habit meditate \"Meditate\"",
+				"buzz create --from goals.yaml --dry-run",
+			},
+			run: handleCreateCommand,
+		},
+		{
+			name:     "deadline",
+			summary:  "Change a goal's deadline",
+			usage:    []usageLine{{"buzz deadline [--yes] <goalslug> <time>", "Change a goal's deadline (e.g., \"3:00 PM\" or \"15:00\")"}},
+			flags:    []usageLine{{"-y, --yes", "Skip the confirmation prompt"}},
+			examples: []string{"buzz deadline exercise '3:00 PM'", "buzz deadline --yes reading 23:30"},
+			run:      handleDeadlineCommand,
+		},
+		{
+			name:    "weekends-off",
+			summary: "Turn automatic weekend breaks on or off",
+			usage: []usageLine{
+				{"buzz weekends-off <goalslug> on|off", "Turn weekends off on or off for a goal"},
+				{"buzz weekends-off --all on|off", "Turn weekends off on or off for every do-more goal"},
+			},
+			flags:     []usageLine{{"--all", "Apply to every do-more goal"}},
+			examples:  []string{"buzz weekends-off exercise on", "buzz weekends-off --all off"},
+			exitCodes: flagErrorExitCodes,
+			run:       handleWeekendsOffCommand,
+		},
+		{
+			name:      "rename",
+			summary:   "Change a goal's slug",
+			usage:     []usageLine{{"buzz rename [--yes] <old-slug> <new-slug>", "Change a goal's slug, updating saved filters, groups, and local state that refer to it"}},
+			flags:     []usageLine{{"-y, --yes", "Skip the confirmation prompt"}},
+			examples:  []string{"buzz rename run jog", "buzz rename --yes reading books"},
+			exitCodes: flagErrorExitCodes,
+			run:       handleRenameCommand,
+		},
+		{
+			name:    "secret",
+			summary: "Make a goal secret or public, and its data public or private",
+			usage: []usageLine{
+				{"buzz secret <goalslug> on|off", "Make a goal secret (on) or visible to others (off)"},
+				{"buzz secret --data-public on|off <goalslug>", "Make a goal's datapoints public (on) or private (off)"},
+			},
+			flags:     []usageLine{{"--data-public on|off", "Also set whether the goal's datapoints are public"}},
+			examples:  []string{"buzz secret journal on", "buzz secret --data-public off exercise", "buzz secret --data-public on reading off"},
+			exitCodes: flagErrorExitCodes,
+			run:       handleSecretCommand,
+		},
+		{
+			name:     "schedule",
+			summary:  "Display goal deadline distribution throughout a 24-hour day",
+			usage:    []usageLine{{"buzz schedule", "Display goal deadline distribution throughout a 24-hour day"}},
+			examples: []string{"buzz schedule"},
+			run:      handleScheduleCommand,
+		},
+		{
+			name:      "uncle",
+			summary:   "Instantly derail a goal that is in the red, paying the pledge",
+			usage:     []usageLine{{"buzz uncle [-y|--yes] <goalslug>", "Instantly derail a goal that is in the red, paying the pledge"}},
+			notes:     []string{"-y, --yes: Skip the confirmation prompt"},
+			flags:     []usageLine{{"-y, --yes", "Skip the confirmation prompt"}},
+			examples:  []string{"buzz uncle exercise", "buzz uncle --yes exercise"},
+			exitCodes: flagErrorExitCodes,
+			run:       handleUncleCommand,
+		},
+		{
+			name:      "shortcircuit",
+			summary:   "Charge a goal's pledge now and raise it",
+			usage:     []usageLine{{"buzz shortcircuit [-y|--yes] <goalslug>", "Charge the goal's current pledge now, as if it had derailed"}},
+			notes:     []string{"Without --yes, you must type the goal's slug to confirm"},
+			flags:     []usageLine{{"-y, --yes", "Skip the confirmation prompt"}},
+			examples:  []string{"buzz shortcircuit exercise"},
+			exitCodes: flagErrorExitCodes,
+			run:       handleShortCircuitCommand,
+		},
+		{
+			name:      "stepdown",
+			summary:   "Schedule a decrease of a goal's pledge",
+			usage:     []usageLine{{"buzz stepdown <goalslug>", "Lower the goal's pledge a step, from the akrasia horizon on"}},
+			examples:  []string{"buzz stepdown exercise"},
+			exitCodes: flagErrorExitCodes,
+			run:       handleStepdownCommand,
+		},
+		{
+			name:    "pledge",
+			summary: "Show a goal's pledge schedule, or set its pledge cap",
+			usage: []usageLine{
+				{"buzz pledge <goalslug>", "Show the pledge, its cap, and the steps a derailment moves it through"},
+				{"buzz pledge <goalslug> --cap <amount>", "Set the most the pledge rises to: $5, $10, $30, $90, $270, $810, or $2430"},
+			},
+			flags:     []usageLine{{"--cap <amount>", "Set the pledge cap to one of Beeminder's pledge steps"}},
+			examples:  []string{"buzz pledge reading", "buzz pledge reading --cap 30"},
+			exitCodes: flagErrorExitCodes,
+			run:       handlePledgeCommand,
+		},
+		{
+			name:    "ratchet",
+			summary: "Remove safety buffer from a goal",
+			usage:   []usageLine{{"buzz ratchet [-y|--yes] [--dry-run] <goalslug> <days>", "Remove safety buffer, leaving <days> of buffer on the goal"}},
+			notes:   []string{"-y, --yes: Skip the confirmation prompt"},
+			flags: []usageLine{
+				{"-y, --yes", "Skip the confirmation prompt"},
+				{"--days <days>", "Days of buffer to leave, instead of the <days> argument"},
+				{"--dry-run, --dryrun", "Show how the goal's deadline would move, without ratcheting"},
+			},
+			examples:  []string{"buzz ratchet exercise 2", "buzz ratchet reading --days 2 --dry-run", "buzz ratchet -y reading 0"},
+			exitCodes: flagErrorExitCodes,
+			run:       handleRatchetCommand,
+		},
+		{
+			name:    "dial",
+			summary: "Change a goal's rate from the akrasia horizon on",
+			usage:   []usageLine{{"buzz dial [-y|--yes] <goalslug> --rate <rate> [--runits <y|m|w|d|h>]", "Run the goal's bright red line at <rate> from a week from today"}},
+			flags: []usageLine{
+				{"--rate <rate>", "The new rate (required)"},
+				{"--runits <y|m|w|d|h>", "Units of the new rate (default: the goal's)"},
+				{"-y, --yes", "Skip the confirmation prompt, unless scheduled rate changes or breaks would be replaced"},
+			},
+			examples:  []string{"buzz dial reading --rate 2 --runits w", "buzz dial -y exercise --rate 0"},
+			exitCodes: flagErrorExitCodes,
+			run:       handleDialCommand,
+		},
+		{
+			name:    "break",
+			summary: "Schedule a flat spot on a goal, e.g. for a vacation",
+			usage:   []usageLine{{"buzz break [--rate <rate>] [-y|--yes] <goalslug> <start> <end>", "Flatten the bright red line from <start> through <end> (YYYY-MM-DD)"}},
+			notes:   []string{"The break must start on or after the akrasia horizon, a week from today"},
+			flags: []usageLine{
+				{"--rate <rate>", "Rate during the break, in the goal's rate units (default 0)"},
+				{"-y, --yes", "Skip the confirmation prompt"},
+			},
+			examples:  []string{"buzz break reading 2024-07-01 2024-07-14", "buzz break --rate 0.5 exercise 2024-12-23 2025-01-01"},
+			exitCodes: flagErrorExitCodes,
+			run:       handleBreakCommand,
+		},
+		{
+			name:    "api",
+			summary: "Make a raw authenticated Beeminder API request",
+			usage:   []usageLine{{"buzz api [-X <method>] [-d <key=value>]... <path>", "Make a raw authenticated Beeminder API request"}},
+			notes:   []string{"e.g. buzz api users/me.json"},
+			flags: []usageLine{
+				{"-X, --method <METHOD>", "HTTP method: GET (default), POST, PUT, PATCH, DELETE"},
+				{"-d, --data <key=value>", "Request parameter (repeatable)"},
+			},
+			examples: []string{
+				"buzz api users/me.json",
+				"buzz api -X POST -d value=1 -d \"comment=via buzz\" users/me/goals/read/datapoints.json",
+			},
+			exitCodes: flagErrorExitCodes,
+			run:       handleAPICommand,
+		},
+		{
+			name:    "auth",
+			summary: "Authenticate with Beeminder",
+			usage: []usageLine{
+				{"buzz auth login", "Authenticate by pasting your Beeminder API credentials"},
+				{"buzz auth encrypt", "Encrypt ~/.buzzrc with a passphrase, or change it"},
+				{"buzz auth decrypt", "Store ~/.buzzrc unencrypted again"},
+			},
+			notes:    []string{"An encrypted ~/.buzzrc is unlocked when buzz starts, with a passphrase prompt or from $BUZZ_PASSPHRASE."},
+			examples: []string{"buzz auth login", "buzz auth login < creds.json", "buzz auth encrypt"},
+			run:      handleAuthCommand,
+		},
+		{
+			name:    "cache",
+			summary: "Refresh or clear the goal slug cache used for completion",
+			usage: []usageLine{
+				{"buzz cache refresh", "Refetch your goals and rewrite the slug cache"},
+				{"buzz cache clear", "Delete the slug cache"},
+			},
+			examples: []string{"buzz cache refresh", "buzz cache clear"},
+			run:      handleCacheCommand,
+		},
+		{
+			name:     "changelog",
+			summary:  "Show release notes for this (or the given) version",
+			usage:    []usageLine{{"buzz changelog [version]", "Show release notes for this (or the given) version"}},
+			examples: []string{"buzz changelog", "buzz changelog v0.40.0"},
+			run:      handleChangelogCommand,
+		},
+		{
+			name:     "completion",
+			summary:  "Print goal slugs for shell completion",
+			usage:    []usageLine{{"buzz completion --goals [query]", "Print your goal slugs (from the slug cache), optionally fuzzy-matched"}},
+			examples: []string{"buzz completion --goals", "buzz completion --goals wrk"},
+			run:      handleCompletionCommand,
+		},
+		{
+			name:     "docs",
+			summary:  "Generate man pages or markdown reference docs",
+			usage:    []usageLine{{"buzz docs <man|markdown> [--dir <dir>]", "Generate man pages or markdown reference docs"}},
+			flags:    []usageLine{{"--dir <dir>", "Directory to write the files to (default: current directory)"}},
+			examples: []string{"buzz docs man --dir ./man", "buzz docs markdown --dir ./reference"},
+			run:      handleDocsCommand,
+		},
+		{
+			name:     "doctor",
+			summary:  "Check that buzz works on this machine",
+			usage:    []usageLine{{"buzz doctor", "Check the platform, terminal, config, refresh flag, browser, clock, and goals"}},
+			examples: []string{"buzz doctor"},
+			run:      handleDoctorCommand,
+		},
+		{
+			name:    "help",
+			aliases: []string{"-h", "--help"},
+			summary: "Show the help message",
+			usage: []usageLine{
+				{"buzz help", "Show this help message"},
+				{"buzz help <command>", "Show detailed help for a command (or: buzz <command> --help)"},
+			},
+			examples: []string{"buzz help", "buzz help add", "buzz add --help"},
+			run:      handleHelpCommand,
+		},
+		{
+			name:      "version",
+			aliases:   []string{"-v", "--version"},
+			summary:   "Show version information",
+			examples:  []string{"buzz version"},
+			exitCodes: []usageLine{{"0", "Success"}},
+			run:       printVersion,
+		},
+	}
+}
+
+// findCommand returns the command dispatched by name (or one of its aliases).
+func findCommand(name string) (commandDef, bool) {
+	for _, cmd := range allCommands() {
+		if cmd.name == name {
+			return cmd, true
+		}
+		for _, alias := range cmd.aliases {
+			if alias == name {
+				return cmd, true
+			}
+		}
+	}
+	return commandDef{}, false
+}
+
+// commandNames lists every command's dispatch name, in help order.
+func commandNames() []string {
+	var names []string
+	for _, cmd := range allCommands() {
+		names = append(names, cmd.name)
+	}
+	return names
+}
+
+func printHelp() {
+	writeHelp(os.Stdout)
+}
+
+// writeHelp writes the top-level help screen, generated from allCommands.
+func writeHelp(w io.Writer) {
+	fmt.Fprintln(w, "buzz - A terminal user interface for Beeminder")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "USAGE:")
+	writeUsageLine(w, usageLine{"buzz", "Launch the interactive TUI"})
+	for _, cmd := range allCommands() {
+		for _, u := range cmd.usage {
+			writeUsageLine(w, u)
+		}
+		for _, note := range cmd.notes {
+			fmt.Fprintf(w, "  %s%s\n", strings.Repeat(" ", helpColumn), note)
+		}
+	}
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "GLOBAL OPTIONS:")
+	for _, opt := range globalOptions {
+		writeUsageLine(w, opt)
+	}
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "For more information, visit: https://buzz.nathanarthur.com")
+}
+
+// writeUsageLine writes one aligned help line, wrapping the description onto
+// its own line when the syntax is too wide for the column.
+func writeUsageLine(w io.Writer, u usageLine) {
+	if len(u.syntax) < helpColumn {
+		fmt.Fprintf(w, "  %-*s%s\n", helpColumn, u.syntax, u.desc)
+		return
+	}
+	fmt.Fprintf(w, "  %s\n", u.syntax)
+	fmt.Fprintf(w, "  %s%s\n", strings.Repeat(" ", helpColumn), u.desc)
+}
+
+// handleHelpCommand prints the top-level help, or a command's detailed help
+// for `buzz help <command>`.
+func handleHelpCommand() {
+	if len(os.Args) < 3 {
+		printHelp()
+		return
+	}
+	os.Exit(runHelpCommand(os.Args[2], os.Stdout, os.Stderr))
+}
+
+// runHelpCommand is the testable core of `buzz help <command>`.
+func runHelpCommand(name string, stdout, stderr io.Writer) int {
+	cmd, ok := findCommand(name)
+	if !ok {
+		fmt.Fprintf(stderr, "Error: Unknown command: %s\n", name)
+		fmt.Fprintf(stderr, "Available commands: %s\n", strings.Join(commandNames(), ", "))
+		return 1
+	}
+	writeCommandHelp(stdout, cmd)
+	return 0
+}
+
+// wantsHelp reports whether a command's arguments ask for help. Arguments
+// after a "--" terminator are data, not flags.
+func wantsHelp(args []string) bool {
+	for _, arg := range args {
+		switch arg {
+		case "--":
+			return false
+		case "-h", "-help", "--help":
+			return true
+		}
+	}
+	return false
+}
+
+// commandExitCodes returns cmd's documented exit codes.
+func commandExitCodes(cmd commandDef) []usageLine {
+	if cmd.exitCodes != nil {
+		return cmd.exitCodes
+	}
+	return defaultExitCodes
+}
+
+// writeCommandHelp writes the detailed help screen for a single command.
+func writeCommandHelp(w io.Writer, cmd commandDef) {
+	fmt.Fprintf(w, "buzz %s - %s\n", cmd.name, cmd.summary)
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "USAGE:")
+	for _, line := range commandSynopsis(cmd) {
+		fmt.Fprintf(w, "  %s\n", line)
+	}
+
+	if len(cmd.flags) > 0 {
+		fmt.Fprintln(w, "")
+		fmt.Fprintln(w, "FLAGS:")
+		for _, f := range cmd.flags {
+			writeUsageLine(w, f)
+		}
+	}
+
+	if len(cmd.examples) > 0 {
+		fmt.Fprintln(w, "")
+		fmt.Fprintln(w, "EXAMPLES:")
+		for _, ex := range cmd.examples {
+			fmt.Fprintf(w, "  %s\n", ex)
+		}
+	}
+
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "EXIT CODES:")
+	for _, code := range commandExitCodes(cmd) {
+		fmt.Fprintf(w, "  %-4s%s\n", code.syntax, code.desc)
+	}
+
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "Run 'buzz help' for global options and the full command list.")
+}
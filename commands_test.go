@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestFindCommand(t *testing.T) {
+	for _, name := range []string{"add", "help", "-h", "--help", "-v", "--version", "version"} {
+		if _, ok := findCommand(name); !ok {
+			t.Errorf("findCommand(%q) not found", name)
+		}
+	}
+	if cmd, _ := findCommand("--help"); cmd.name != "help" {
+		t.Errorf("--help dispatches to %q, want help", cmd.name)
+	}
+	if _, ok := findCommand("bogus"); ok {
+		t.Error("findCommand(bogus) should not be found")
+	}
+}
+
+// TestCommandDefinitionsComplete guards the registry every generated doc
+// relies on: each command must be runnable, summarized, and have examples,
+// with unique names.
+func TestCommandDefinitionsComplete(t *testing.T) {
+	seen := map[string]bool{}
+	for _, cmd := range allCommands() {
+		if cmd.run == nil {
+			t.Errorf("command %q has no run func", cmd.name)
+		}
+		if cmd.summary == "" {
+			t.Errorf("command %q has no summary", cmd.name)
+		}
+		if len(cmd.examples) == 0 {
+			t.Errorf("command %q has no examples", cmd.name)
+		}
+		for _, name := range append([]string{cmd.name}, cmd.aliases...) {
+			if seen[name] {
+				t.Errorf("duplicate command name %q", name)
+			}
+			seen[name] = true
+		}
+	}
+}
+
+func TestWriteHelp(t *testing.T) {
+	var buf bytes.Buffer
+	writeHelp(&buf)
+	out := buf.String()
+
+	for _, want := range []string{
+		"  buzz next                         Output a terse summary of the next due goal\n",
+		"  buzz data [--asc|--desc] <goalslug>\n                                    List a goal's datapoints",
+		"  --no-color                        Disable colored output\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("help missing %q", want)
+		}
+	}
+}
+
+func TestRunHelpCommand(t *testing.T) {
+	t.Run("known command shows flags, examples, and exit codes", func(t *testing.T) {
+		var out, errOut bytes.Buffer
+		code := runHelpCommand("ratchet", &out, &errOut)
+		checkResult(t, code, out.String(), errOut.String(), 0, "buzz ratchet - Remove safety buffer", "")
+		for _, want := range []string{"FLAGS:\n  -y, --yes", "EXAMPLES:\n  buzz ratchet exercise 2", "EXIT CODES:", "  2   Invalid or unknown flags"} {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("help missing %q:\n%s", want, out.String())
+			}
+		}
+	})
+
+	t.Run("aliases resolve to their command", func(t *testing.T) {
+		var out, errOut bytes.Buffer
+		code := runHelpCommand("--version", &out, &errOut)
+		checkResult(t, code, out.String(), errOut.String(), 0, "buzz version - Show version information", "")
+	})
+
+	t.Run("unknown command", func(t *testing.T) {
+		var out, errOut bytes.Buffer
+		code := runHelpCommand("bogus", &out, &errOut)
+		checkResult(t, code, out.String(), errOut.String(), 1, "", "Unknown command: bogus")
+	})
+}
+
+func TestWantsHelp(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{nil, false},
+		{[]string{"--help"}, true},
+		{[]string{"goal", "-h"}, true},
+		{[]string{"-help"}, true},
+		{[]string{"goal", "1", "--", "--help"}, false},
+		{[]string{"goal", "1", "help me"}, false},
+	}
+	for _, tt := range tests {
+		if got := wantsHelp(tt.args); got != tt.want {
+			t.Errorf("wantsHelp(%q) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
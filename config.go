@@ -1,11 +1,15 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -15,6 +19,41 @@ type Config struct {
 	AuthToken string `json:"auth_token"`
 	BaseURL   string `json:"base_url,omitempty"` // Optional base URL for API, defaults to https://www.beeminder.com
 	LogFile   string `json:"log_file,omitempty"` // Optional path to log file
+
+	UpdateCheck string `json:"update_check,omitempty"` // "daily" (default), "weekly", "version", or "off"
+
+	Filters map[string]string `json:"filters,omitempty"` // Named goal filters (name → expression) for --filter and the TUI's f key
+
+	Templates map[string]GoalTemplate `json:"templates,omitempty"` // Named goal presets for `buzz create --template` and the TUI's create form
+
+	Groups map[string][]string `json:"groups,omitempty"` // Named goal groups (name → slugs) for group: filters, `buzz goals --by-group`, and `buzz groups`
+
+	PomUnits string `json:"pom_units,omitempty"` // What `buzz pom` logs: "hours" (default), "minutes", or "count"
+
+	Celebrate string `json:"celebrate,omitempty"` // "on" (default) or "bell" to celebrate an add that takes a goal out of the red, with the terminal bell for "bell"; "off" not to
+
+	NoAutoRequestID bool `json:"no_auto_requestid,omitempty"` // Stop `buzz add` deriving a request ID when --requestid isn't given
+
+	NoUsageStats bool `json:"no_usage_stats,omitempty"` // Stop counting command runs in ~/.buzz_usage for `buzz stats --self`
+
+	IMAP *IMAPConfig `json:"imap,omitempty"` // Mailbox counted by `buzz inbox --imap`
+
+	GitHubToken string `json:"github_token,omitempty"` // Token for `buzz autodata github` (falls back to $GITHUB_TOKEN)
+
+	RescueTimeKey string `json:"rescuetime_key,omitempty"` // API key for `buzz import rescuetime` (falls back to $RESCUETIME_KEY)
+
+	TrustedPlugins []string `json:"trusted_plugins,omitempty"` // Plugins (names without "buzz-") given the auth token in BUZZ_AUTH_TOKEN
+
+	Hooks string `json:"hooks,omitempty"` // Path to a Starlark script of hooks (see hooks.go)
+
+	Locale string `json:"locale,omitempty"` // Locale for numbers and dates, e.g. "de_DE" (overrides $LC_ALL/$LC_NUMERIC/$LANG)
+
+	Clock string `json:"clock,omitempty"` // "12h" or "24h" for deadlines; defaults to the locale's
+
+	OpenCommand string `json:"open_command,omitempty"` // Command that opens goal pages, with {url} for the URL (overrides $BROWSER)
+	OpenMode    string `json:"open_mode,omitempty"`    // "auto" (default), "browser", or "print" to show goal URLs instead of opening them
+
+	problems []configProblem // Unknown and deprecated keys LoadConfig found; see configschema.go
 }
 
 // getConfigPath returns the path to the config file
@@ -36,8 +75,29 @@ func ConfigExists() bool {
 	return err == nil
 }
 
-// LoadConfig reads and parses the config file from ~/.buzzrc
+// LoadConfig reads and parses the config file from ~/.buzzrc, asking for the
+// passphrase if it's encrypted. A value of the wrong type is an error; unknown
+// and deprecated keys are kept as warnings (see configWarnings). Loading it
+// also counts the run countCommandUsage had to leave pending while it was
+// locked.
 func LoadConfig() (*Config, error) {
+	config, err := loadConfig(unlockConfig)
+	if err == nil {
+		countPendingUsage(config)
+	}
+	return config, err
+}
+
+// loadUnlockedConfig is LoadConfig for settings that don't warrant asking
+// for the passphrase, such as the update check: an encrypted config this run
+// hasn't unlocked yet comes back as errConfigLocked. Commands that need the
+// credentials unlock it with LoadConfig.
+func loadUnlockedConfig() (*Config, error) {
+	return loadConfig(openUnlockedConfig)
+}
+
+// loadConfig is LoadConfig, opening an encrypted config with unlock.
+func loadConfig(unlock func(encryptedConfig) ([]byte, error)) (*Config, error) {
 	path, err := getConfigPath()
 	if err != nil {
 		return nil, err
@@ -47,16 +107,51 @@ func LoadConfig() (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	return parseConfig(data, unlock)
+}
+
+// parseConfig parses a config file's contents, opening them with unlock if
+// they're encrypted.
+func parseConfig(data []byte, unlock func(encryptedConfig) ([]byte, error)) (*Config, error) {
+	var err error
+	if enc, ok := parseEncryptedConfig(data); ok {
+		if data, err = unlock(enc); err != nil {
+			return nil, err
+		}
+	}
+
+	var problems, fatal []configProblem
+	for _, p := range checkConfigSchema(data) {
+		if p.fatal {
+			fatal = append(fatal, p)
+		} else {
+			problems = append(problems, p)
+		}
+	}
+	if len(fatal) > 0 {
+		msgs := make([]string, len(fatal))
+		for i, p := range fatal {
+			msgs[i] = p.String()
+		}
+		return nil, errors.New(strings.Join(msgs, "; "))
+	}
 
 	var config Config
 	if err := json.Unmarshal(data, &config); err != nil {
-		return nil, err
+		return nil, configSyntaxError(data, err)
 	}
+	config.problems = problems
+	applyLocale(config.Locale)
+	applyClock(config.Clock)
+	applyGroups(config.Groups)
+	applyCelebrate(config.Celebrate)
 
 	return &config, nil
 }
 
-// SaveConfig writes the config to ~/.buzzrc with secure permissions
+// SaveConfig writes the config to ~/.buzzrc with secure permissions, and
+// encrypted if it was before. To change a setting, use updateConfig, so a
+// change another buzz saves in the meantime isn't lost.
 func SaveConfig(config *Config) error {
 	path, err := getConfigPath()
 	if err != nil {
@@ -68,12 +163,206 @@ func SaveConfig(config *Config) error {
 		return err
 	}
 
-	// Write with 0600 permissions (read/write for owner only)
-	if err := os.WriteFile(path, data, 0600); err != nil {
+	// Keep an encrypted config encrypted, even when this run hasn't unlocked
+	// it yet (as with `buzz auth login`).
+	if err := unlockConfigFile(path); err != nil {
 		return err
 	}
+	return updateFileAtomic(path, 0600, func(existing []byte) ([]byte, error) {
+		return resealConfig(existing, data)
+	})
+}
 
-	return nil
+// updateConfig loads ~/.buzzrc, lets update change it, and saves it, all
+// under the file's lock so a change another buzz saves in between isn't lost.
+// The config is kept encrypted if it was, and only the settings update
+// changed are rewritten (see mergeConfigJSON). An update returning
+// errUnchanged saves nothing.
+func updateConfig(update func(*Config) error) error {
+	path, err := getConfigPath()
+	if err != nil {
+		return err
+	}
+	if err := unlockConfigFile(path); err != nil {
+		return err
+	}
+	return updateFileAtomic(path, 0600, func(existing []byte) ([]byte, error) {
+		if existing == nil {
+			return nil, os.ErrNotExist
+		}
+		plain := existing
+		if enc, ok := parseEncryptedConfig(existing); ok {
+			var err error
+			if plain, err = unlockConfig(enc); err != nil {
+				return nil, err
+			}
+		}
+		config, err := parseConfig(plain, unlockConfig)
+		if err != nil {
+			return nil, err
+		}
+		if err := update(config); err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(config)
+		if err != nil {
+			return nil, err
+		}
+		if data, err = mergeConfigJSON(plain, data); err != nil {
+			return nil, err
+		}
+		return resealConfig(existing, data)
+	})
+}
+
+// mergeConfigJSON writes updated, the changed Config marshaled, over
+// existing, the config file's JSON, touching only the settings that changed.
+// Every other entry keeps its place and its bytes, keys buzz doesn't know are
+// kept (LoadConfig warns about them, but they're the user's to fix), and
+// settings the change cleared are dropped. The result is indented like
+// existing, so a hand-formatted ~/.buzzrc stays that way.
+func mergeConfigJSON(existing, updated []byte) ([]byte, error) {
+	oldEntries, err := jsonObjectEntries(existing)
+	if err != nil {
+		return nil, err
+	}
+	newEntries, err := jsonObjectEntries(updated)
+	if err != nil {
+		return nil, err
+	}
+	newValues := map[string]json.RawMessage{}
+	for _, e := range newEntries {
+		newValues[e.key] = e.value
+	}
+	fields := configFieldTypes()
+
+	var merged []jsonEntry
+	seen := map[string]bool{}
+	for _, e := range oldEntries {
+		seen[e.key] = true
+		t, known := fields[e.key]
+		v, kept := newValues[e.key]
+		switch {
+		case !known:
+			merged = append(merged, e)
+		case !kept:
+			// Cleared, so omitted from updated.
+		case sameConfigValue(t, e.value, v):
+			merged = append(merged, e)
+		default:
+			merged = append(merged, jsonEntry{e.key, v})
+		}
+	}
+	for _, e := range newEntries {
+		if !seen[e.key] {
+			merged = append(merged, e)
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, e := range merged {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(e.key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(e.value)
+	}
+	buf.WriteByte('}')
+
+	var out bytes.Buffer
+	if indent := jsonIndentOf(existing); indent != "" {
+		err = json.Indent(&out, buf.Bytes(), "", indent)
+	} else {
+		err = json.Compact(&out, buf.Bytes())
+	}
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasSuffix(existing, []byte("\n")) {
+		out.WriteByte('\n')
+	}
+	return out.Bytes(), nil
+}
+
+// jsonEntry is one key and its raw value in a JSON object.
+type jsonEntry struct {
+	key   string
+	value json.RawMessage
+}
+
+// jsonObjectEntries returns the entries of the JSON object in data, in file
+// order, with each value's bytes as written.
+func jsonObjectEntries(data []byte) ([]jsonEntry, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil {
+		return nil, err
+	} else if tok != json.Delim('{') {
+		return nil, errors.New("config is not a JSON object")
+	}
+	var entries []jsonEntry
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := tok.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		entries = append(entries, jsonEntry{key, value})
+	}
+	return entries, nil
+}
+
+// configFieldTypes maps Config's JSON keys to their field types.
+func configFieldTypes() map[string]reflect.Type {
+	t := reflect.TypeOf(Config{})
+	fields := map[string]reflect.Type{}
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if f.IsExported() && name != "" && name != "-" {
+			fields[name] = f.Type
+		}
+	}
+	return fields
+}
+
+// sameConfigValue reports whether old, as read from the config file, is the
+// setting updated marshals, once decoded as t: so a setting the change
+// didn't touch is kept as written, down to its spacing and the nested keys
+// buzz doesn't know.
+func sameConfigValue(t reflect.Type, old, updated json.RawMessage) bool {
+	v := reflect.New(t)
+	if err := json.Unmarshal(old, v.Interface()); err != nil {
+		return false
+	}
+	normalized, err := json.Marshal(v.Elem().Interface())
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(normalized, updated)
+}
+
+// jsonIndentOf returns the indent of the first nested line of data, or "" if
+// it's all on one line.
+func jsonIndentOf(data []byte) string {
+	_, rest, ok := bytes.Cut(bytes.TrimSpace(data), []byte("\n"))
+	if !ok {
+		return ""
+	}
+	indent := rest[:len(rest)-len(bytes.TrimLeft(rest, " \t"))]
+	if len(indent) == 0 {
+		return "  "
+	}
+	return string(indent)
 }
 
 // getRefreshFlagPath returns the path to the refresh flag file
@@ -92,7 +381,7 @@ func createRefreshFlag() error {
 		return err
 	}
 	timestamp := fmt.Sprintf("%d", time.Now().Unix())
-	return os.WriteFile(path, []byte(timestamp), 0600)
+	return writeFileAtomic(path, []byte(timestamp), 0600)
 }
 
 // deleteRefreshFlag deletes the refresh flag file
@@ -132,7 +421,7 @@ func getRefreshFlagTimestamp() int64 {
 		return 0
 	}
 
-	timestamp, err := strconv.ParseInt(string(data), 10, 64)
+	timestamp, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
 	if err != nil {
 		return 0
 	}
@@ -147,7 +436,7 @@ func logToFile(config *Config, message string) {
 		return // Logging disabled
 	}
 
-	f, err := os.OpenFile(config.LogFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	f, err := os.OpenFile(expandHome(config.LogFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 	if err != nil {
 		return // Fail silently if can't open log
 	}
@@ -499,3 +499,64 @@ func TestLoggingRedactsAuthToken(t *testing.T) {
 		}
 	})
 }
+
+func TestUpdateConfigKeepsTheRestOfTheFile(t *testing.T) {
+	dir := t.TempDir()
+	setHome(t, dir)
+	path := filepath.Join(dir, ".buzzrc")
+	original := `{
+    "username": "alice",
+    "auth_token":   "secret",
+    "groups": {"cardio": ["run", "swim"]},
+    "imap": {"host": "mail.example.com", "username": "alice", "password": "pw", "folder": "INBOX"},
+    "my_note": "keep me",
+    "filters": {
+        "cardio": "slug:run || slug:swim"
+    },
+    "pom_units": "minutes"
+}
+`
+	if err := os.WriteFile(path, []byte(original), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	err := updateConfig(func(config *Config) error {
+		renameSlugInConfig(config, "run", "jog")
+		config.PomUnits = ""
+		config.Locale = "de_DE"
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Only the changed settings are rewritten; the unknown key, the nested
+	// "folder" buzz doesn't read, and the file's order and indent stay.
+	want := `{
+    "username": "alice",
+    "auth_token": "secret",
+    "groups": {
+        "cardio": [
+            "jog",
+            "swim"
+        ]
+    },
+    "imap": {
+        "host": "mail.example.com",
+        "username": "alice",
+        "password": "pw",
+        "folder": "INBOX"
+    },
+    "my_note": "keep me",
+    "filters": {
+        "cardio": "slug:jog || slug:swim"
+    },
+    "locale": "de_DE"
+}
+`
+	if string(got) != want {
+		t.Errorf("config =\n%s\nwant\n%s", got, want)
+	}
+}
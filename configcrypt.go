@@ -0,0 +1,307 @@
+package main
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// Encrypted config. On a shared machine ~/.buzzrc's auth token is only as safe
+// as its file permissions, so `buzz auth encrypt` can seal the file with a
+// passphrase instead: AES-256-GCM under a key derived from the passphrase with
+// PBKDF2. LoadConfig notices an encrypted file and asks for the passphrase
+// (once per run, at the terminal, or from $BUZZ_PASSPHRASE for scripts), and
+// SaveConfig seals the file again, so everything else reads and writes the
+// config as before.
+
+// configPassphraseEnv names the environment variable that supplies the
+// passphrase without a prompt, for cron jobs and scripts.
+const configPassphraseEnv = "BUZZ_PASSPHRASE"
+
+// configKDFIterations is the PBKDF2 work factor for newly encrypted configs;
+// the count used is stored in the file. A var so tests can make it cheap.
+var configKDFIterations = 600_000
+
+// errConfigLocked is returned when the config is encrypted and couldn't be
+// unlocked.
+var errConfigLocked = errors.New("~/.buzzrc is encrypted and couldn't be unlocked")
+
+// errConfigNotUnlocked is returned, wrapped in errConfigLocked, by
+// loadUnlockedConfig when this run hasn't unlocked the config yet.
+var errConfigNotUnlocked = errors.New("not unlocked yet")
+
+// errWrongPassphrase is returned when a passphrase doesn't open the config.
+var errWrongPassphrase = errors.New("wrong passphrase")
+
+// encryptedConfig is the on-disk form of an encrypted ~/.buzzrc. It's JSON
+// like a plain config, with the sealed config in Ciphertext.
+type encryptedConfig struct {
+	Version    int    `json:"buzz_encrypted"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// configKey is a key derived from the passphrase, with the salt and work
+// factor it was derived with.
+type configKey struct {
+	key        []byte
+	salt       []byte
+	iterations int
+}
+
+// unlockedConfigKey is the key that opened the config this run, so the
+// passphrase is asked for once and SaveConfig can seal the file again. It is
+// nil when the config isn't encrypted.
+var unlockedConfigKey *configKey
+
+// configUnlockErr is why the config couldn't be unlocked this run, so a
+// failed unlock isn't asked for again by every later LoadConfig.
+var configUnlockErr error
+
+// readPassphrase gets the passphrase, showing prompt at a terminal. A var so
+// tests can answer it.
+var readPassphrase = promptPassphrase
+
+// promptPassphrase reads a passphrase from $BUZZ_PASSPHRASE, or else from the
+// terminal without echoing it.
+func promptPassphrase(prompt string) ([]byte, error) {
+	if pass, ok := os.LookupEnv(configPassphraseEnv); ok {
+		return []byte(pass), nil
+	}
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		return nil, fmt.Errorf("no terminal to ask for the passphrase; set $%s", configPassphraseEnv)
+	}
+	fmt.Fprint(os.Stderr, prompt)
+	pass, err := term.ReadPassword(os.Stdin.Fd())
+	fmt.Fprintln(os.Stderr)
+	return pass, err
+}
+
+// deriveConfigKey derives the AES-256 key for passphrase.
+func deriveConfigKey(passphrase, salt []byte, iterations int) (*configKey, error) {
+	key, err := pbkdf2.Key(sha256.New, string(passphrase), salt, iterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	return &configKey{key: key, salt: salt, iterations: iterations}, nil
+}
+
+// newConfigKey derives a key for passphrase with a fresh salt.
+func newConfigKey(passphrase []byte) (*configKey, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return deriveConfigKey(passphrase, salt, configKDFIterations)
+}
+
+// parseEncryptedConfig returns the encrypted form of data, with ok=false when
+// data is a plain config.
+func parseEncryptedConfig(data []byte) (encryptedConfig, bool) {
+	var enc encryptedConfig
+	if json.Unmarshal(data, &enc) != nil || enc.Version == 0 {
+		return encryptedConfig{}, false
+	}
+	return enc, true
+}
+
+// sealConfig encrypts a plain config's contents with k.
+func sealConfig(plain []byte, k *configKey) ([]byte, error) {
+	gcm, err := configCipher(k)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(encryptedConfig{
+		Version:    1,
+		Iterations: k.iterations,
+		Salt:       k.salt,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plain, nil),
+	}, "", "  ")
+}
+
+// openConfig decrypts enc with k.
+func openConfig(enc encryptedConfig, k *configKey) ([]byte, error) {
+	if enc.Version != 1 {
+		return nil, fmt.Errorf("encrypted with a newer buzz (format %d)", enc.Version)
+	}
+	gcm, err := configCipher(k)
+	if err != nil {
+		return nil, err
+	}
+	if len(enc.Nonce) != gcm.NonceSize() {
+		return nil, errors.New("malformed encrypted config")
+	}
+	plain, err := gcm.Open(nil, enc.Nonce, enc.Ciphertext, nil)
+	if err != nil {
+		return nil, errWrongPassphrase
+	}
+	return plain, nil
+}
+
+func configCipher(k *configKey) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(k.key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// unlockConfig decrypts an encrypted config, asking for the passphrase unless
+// it was already given this run. At a terminal a mistyped passphrase can be
+// tried again.
+func unlockConfig(enc encryptedConfig) ([]byte, error) {
+	if plain, err := openUnlockedConfig(enc); !errors.Is(err, errConfigNotUnlocked) {
+		return plain, err
+	}
+	_, fromEnv := os.LookupEnv(configPassphraseEnv)
+	for attempt := 1; ; attempt++ {
+		pass, err := readPassphrase("Passphrase for ~/.buzzrc: ")
+		if err == nil {
+			var k *configKey
+			if k, err = deriveConfigKey(pass, enc.Salt, enc.Iterations); err == nil {
+				var plain []byte
+				if plain, err = openConfig(enc, k); err == nil {
+					unlockedConfigKey = k
+					return plain, nil
+				}
+			}
+		}
+		if !errors.Is(err, errWrongPassphrase) || fromEnv || attempt == 3 {
+			configUnlockErr = fmt.Errorf("%w: %v", errConfigLocked, err)
+			return nil, configUnlockErr
+		}
+		fmt.Fprintln(os.Stderr, "Wrong passphrase, try again.")
+	}
+}
+
+// openUnlockedConfig opens enc with the key this run already unlocked it
+// with, never asking for the passphrase. It returns errConfigNotUnlocked,
+// wrapped in errConfigLocked, when there's no such key.
+func openUnlockedConfig(enc encryptedConfig) ([]byte, error) {
+	if k := unlockedConfigKey; k != nil && bytes.Equal(k.salt, enc.Salt) && k.iterations == enc.Iterations {
+		if plain, err := openConfig(enc, k); err == nil {
+			return plain, nil
+		}
+	}
+	if configUnlockErr != nil {
+		return nil, configUnlockErr
+	}
+	return nil, fmt.Errorf("%w: %w", errConfigLocked, errConfigNotUnlocked)
+}
+
+// encryptConfigFile encrypts the config file at path with a new passphrase,
+// asked for twice. An encrypted config must be unlocked first, so this also
+// changes the passphrase.
+func encryptConfigFile(path string) error {
+	if err := unlockConfigFile(path); err != nil {
+		return err
+	}
+
+	pass, err := readPassphrase("New passphrase: ")
+	if err != nil {
+		return err
+	}
+	if len(pass) == 0 {
+		return errors.New("the passphrase can't be empty")
+	}
+	if _, fromEnv := os.LookupEnv(configPassphraseEnv); !fromEnv {
+		again, err := readPassphrase("Repeat the passphrase: ")
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(pass, again) {
+			return errors.New("the passphrases don't match")
+		}
+	}
+
+	k, err := newConfigKey(pass)
+	if err != nil {
+		return err
+	}
+	err = updateFileAtomic(path, 0600, func(data []byte) ([]byte, error) {
+		if data == nil {
+			return nil, os.ErrNotExist
+		}
+		if enc, ok := parseEncryptedConfig(data); ok {
+			var err error
+			if data, err = unlockConfig(enc); err != nil {
+				return nil, err
+			}
+		}
+		return sealConfig(data, k)
+	})
+	if err != nil {
+		return err
+	}
+	unlockedConfigKey = k
+	return nil
+}
+
+// decryptConfigFile writes the encrypted config file at path back as plain
+// JSON.
+func decryptConfigFile(path string) error {
+	if err := unlockConfigFile(path); err != nil {
+		return err
+	}
+	err := updateFileAtomic(path, 0600, func(data []byte) ([]byte, error) {
+		if data == nil {
+			return nil, os.ErrNotExist
+		}
+		enc, ok := parseEncryptedConfig(data)
+		if !ok {
+			return nil, errors.New("~/.buzzrc isn't encrypted")
+		}
+		return unlockConfig(enc)
+	})
+	if err != nil {
+		return err
+	}
+	unlockedConfigKey = nil
+	return nil
+}
+
+// unlockConfigFile unlocks the config file at path if it's encrypted, so the
+// passphrase is asked for before taking the file's lock rather than while
+// another buzz waits on it. A missing or plain config needs no unlocking.
+func unlockConfigFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if enc, ok := parseEncryptedConfig(data); ok {
+		_, err = unlockConfig(enc)
+	}
+	return err
+}
+
+// resealConfig returns a config's new contents, data, to replace existing:
+// sealed with this run's key if existing is encrypted, as is otherwise.
+func resealConfig(existing, data []byte) ([]byte, error) {
+	if _, ok := parseEncryptedConfig(existing); !ok {
+		return data, nil
+	}
+	if unlockedConfigKey == nil {
+		// Encrypted by another buzz since this one checked.
+		return nil, fmt.Errorf("%w: %w", errConfigLocked, errConfigNotUnlocked)
+	}
+	return sealConfig(data, unlockedConfigKey)
+}
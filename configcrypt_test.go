@@ -0,0 +1,225 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// useTestPassphrases answers passphrase prompts from answers in order, with
+// a cheap key derivation and no passphrase remembered from earlier tests.
+func useTestPassphrases(t *testing.T, answers ...string) *[]string {
+	t.Helper()
+	t.Setenv(configPassphraseEnv, "")
+	os.Unsetenv(configPassphraseEnv)
+	iterations, read := configKDFIterations, readPassphrase
+	configKDFIterations = 1000
+	var prompts []string
+	readPassphrase = func(prompt string) ([]byte, error) {
+		prompts = append(prompts, prompt)
+		if len(answers) == 0 {
+			return nil, errors.New("no terminal")
+		}
+		pass := answers[0]
+		answers = answers[1:]
+		return []byte(pass), nil
+	}
+	resetConfigUnlock := func() { unlockedConfigKey, configUnlockErr = nil, nil }
+	resetConfigUnlock()
+	t.Cleanup(func() {
+		configKDFIterations, readPassphrase = iterations, read
+		resetConfigUnlock()
+	})
+	return &prompts
+}
+
+func TestSealAndOpenConfig(t *testing.T) {
+	useTestPassphrases(t)
+	k, err := newConfigKey([]byte("hunter2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := sealConfig([]byte(`{"username":"alice"}`), k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(sealed), "alice") {
+		t.Errorf("sealed config contains the plain text: %s", sealed)
+	}
+
+	enc, ok := parseEncryptedConfig(sealed)
+	if !ok {
+		t.Fatalf("parseEncryptedConfig didn't recognise %s", sealed)
+	}
+	plain, err := openConfig(enc, k)
+	if err != nil || string(plain) != `{"username":"alice"}` {
+		t.Errorf("openConfig = %q, %v", plain, err)
+	}
+
+	wrong, _ := deriveConfigKey([]byte("hunter3"), enc.Salt, enc.Iterations)
+	if _, err := openConfig(enc, wrong); !errors.Is(err, errWrongPassphrase) {
+		t.Errorf("openConfig with the wrong passphrase = %v, want errWrongPassphrase", err)
+	}
+
+	if _, ok := parseEncryptedConfig([]byte(`{"username":"alice"}`)); ok {
+		t.Error("parseEncryptedConfig recognised a plain config")
+	}
+}
+
+func TestEncryptedConfigRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	setHome(t, dir)
+	path := filepath.Join(dir, ".buzzrc")
+	if err := SaveConfig(&Config{Username: "alice", AuthToken: "secret"}); err != nil {
+		t.Fatal(err)
+	}
+
+	useTestPassphrases(t, "hunter2", "hunter2")
+	if err := encryptConfigFile(path); err != nil {
+		t.Fatalf("encryptConfigFile: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "secret") {
+		t.Fatalf("encrypted config contains the token: %s", data)
+	}
+
+	// A new run asks for the passphrase once, then loads and saves as usual.
+	prompts := useTestPassphrases(t, "hunter2")
+	config, err := LoadConfig()
+	if err != nil || config.AuthToken != "secret" {
+		t.Fatalf("LoadConfig = %+v, %v", config, err)
+	}
+	config.Username = "bob"
+	if err := SaveConfig(config); err != nil {
+		t.Fatal(err)
+	}
+	if config, err = LoadConfig(); err != nil || config.Username != "bob" {
+		t.Fatalf("LoadConfig after save = %+v, %v", config, err)
+	}
+	if len(*prompts) != 1 {
+		t.Errorf("prompts = %q, want one", *prompts)
+	}
+	data, _ = os.ReadFile(path)
+	if _, ok := parseEncryptedConfig(data); !ok {
+		t.Fatalf("SaveConfig didn't keep the config encrypted: %s", data)
+	}
+
+	useTestPassphrases(t, "hunter2")
+	if err := decryptConfigFile(path); err != nil {
+		t.Fatalf("decryptConfigFile: %v", err)
+	}
+	data, _ = os.ReadFile(path)
+	if !strings.Contains(string(data), `"auth_token":"secret"`) {
+		t.Errorf("decrypted config = %s", data)
+	}
+}
+
+func TestUnlockConfigWrongPassphrase(t *testing.T) {
+	dir := t.TempDir()
+	setHome(t, dir)
+	path := filepath.Join(dir, ".buzzrc")
+	if err := SaveConfig(&Config{Username: "alice", AuthToken: "secret"}); err != nil {
+		t.Fatal(err)
+	}
+	useTestPassphrases(t, "hunter2", "hunter2")
+	if err := encryptConfigFile(path); err != nil {
+		t.Fatal(err)
+	}
+
+	// A mistyped passphrase can be tried again.
+	prompts := useTestPassphrases(t, "hunter3", "hunter2")
+	if config, err := LoadConfig(); err != nil || config.Username != "alice" {
+		t.Errorf("LoadConfig = %+v, %v", config, err)
+	}
+	if len(*prompts) != 2 {
+		t.Errorf("prompts = %q, want two", *prompts)
+	}
+
+	// Three wrong tries lock the config for the rest of the run.
+	prompts = useTestPassphrases(t, "a", "b", "c", "hunter2")
+	if _, err := LoadConfig(); !errors.Is(err, errConfigLocked) || !strings.Contains(err.Error(), "wrong passphrase") {
+		t.Errorf("LoadConfig = %v, want a locked config", err)
+	}
+	if _, err := LoadConfig(); !errors.Is(err, errConfigLocked) {
+		t.Errorf("second LoadConfig = %v, want a locked config", err)
+	}
+	if len(*prompts) != 3 {
+		t.Errorf("prompts = %q, want three", *prompts)
+	}
+
+	// A passphrase from the environment gets one try.
+	prompts = useTestPassphrases(t, "hunter3", "hunter2")
+	t.Setenv(configPassphraseEnv, "hunter3")
+	if _, err := LoadConfig(); !errors.Is(err, errConfigLocked) {
+		t.Errorf("LoadConfig = %v, want a locked config", err)
+	}
+	if len(*prompts) != 1 {
+		t.Errorf("prompts = %q, want one", *prompts)
+	}
+}
+
+func TestEncryptConfigFileRejects(t *testing.T) {
+	dir := t.TempDir()
+	setHome(t, dir)
+	path := filepath.Join(dir, ".buzzrc")
+	if err := SaveConfig(&Config{Username: "alice", AuthToken: "secret"}); err != nil {
+		t.Fatal(err)
+	}
+
+	useTestPassphrases(t, "hunter2", "hunter3")
+	if err := encryptConfigFile(path); err == nil || !strings.Contains(err.Error(), "don't match") {
+		t.Errorf("encryptConfigFile with mismatched passphrases = %v", err)
+	}
+	useTestPassphrases(t, "")
+	if err := encryptConfigFile(path); err == nil || !strings.Contains(err.Error(), "empty") {
+		t.Errorf("encryptConfigFile with an empty passphrase = %v", err)
+	}
+	if err := decryptConfigFile(path); err == nil || !strings.Contains(err.Error(), "isn't encrypted") {
+		t.Errorf("decryptConfigFile on a plain config = %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "secret") {
+		t.Errorf("config changed: %s", data)
+	}
+}
+
+func TestLoadUnlockedConfig(t *testing.T) {
+	dir := t.TempDir()
+	setHome(t, dir)
+	path := filepath.Join(dir, ".buzzrc")
+	if err := SaveConfig(&Config{Username: "alice", AuthToken: "secret", UpdateCheck: updateCheckOff}); err != nil {
+		t.Fatal(err)
+	}
+	useTestPassphrases(t, "hunter2", "hunter2")
+	if err := encryptConfigFile(path); err != nil {
+		t.Fatal(err)
+	}
+
+	// Commands that don't need the credentials don't ask for the passphrase.
+	prompts := useTestPassphrases(t, "hunter2")
+	if _, err := loadUnlockedConfig(); !errors.Is(err, errConfigLocked) {
+		t.Errorf("loadUnlockedConfig = %v, want a locked config", err)
+	}
+	if mode := updateCheckMode(); mode != updateCheckDaily {
+		t.Errorf("updateCheckMode = %q, want the default", mode)
+	}
+	if len(*prompts) != 0 {
+		t.Fatalf("prompts = %q, want none", *prompts)
+	}
+
+	// Once a command has unlocked it, they read it too.
+	if _, err := LoadConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if config, err := loadUnlockedConfig(); err != nil || config.Username != "alice" {
+		t.Errorf("loadUnlockedConfig = %+v, %v", config, err)
+	}
+	if mode := updateCheckMode(); mode != updateCheckOff {
+		t.Errorf("updateCheckMode = %q, want off", mode)
+	}
+	if len(*prompts) != 1 {
+		t.Errorf("prompts = %q, want one", *prompts)
+	}
+}
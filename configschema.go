@@ -0,0 +1,289 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Config schema validation. ~/.buzzrc is hand-edited JSON, and encoding/json
+// silently drops keys it doesn't know, so a typo like "log_fle" would quietly
+// do nothing. LoadConfig checks the file against Config's own fields instead:
+// a value of the wrong type is an error, and unknown or deprecated keys are
+// warnings, each with its line and, where buzz can tell, how to fix it.
+
+// deprecatedConfigKeys maps keys buzz no longer reads, by their dotted path in
+// the config, to the key that replaced them. A renamed key goes here so that a
+// config still using the old name gets told what to change it to, rather than
+// just that the key is unknown.
+var deprecatedConfigKeys = map[string]string{}
+
+// configProblem is one thing wrong with the config file.
+type configProblem struct {
+	line  int
+	msg   string
+	fatal bool // the config can't be loaded as written
+}
+
+func (p configProblem) String() string {
+	return fmt.Sprintf("line %d: %s", p.line, p.msg)
+}
+
+// checkConfigSchema checks the config file's contents against Config,
+// returning its problems in file order. It stops at a syntax error, which
+// json.Unmarshal reports.
+func checkConfigSchema(data []byte) []configProblem {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	c := &schemaChecker{data: data, dec: dec}
+	c.value(reflect.TypeOf(Config{}), "")
+	return c.problems
+}
+
+// schemaChecker walks the config's JSON tokens alongside the Go types they
+// decode into.
+type schemaChecker struct {
+	data     []byte
+	dec      *json.Decoder
+	problems []configProblem
+	broken   bool // a syntax error stopped the walk
+}
+
+// line returns the line the decoder has read up to.
+func (c *schemaChecker) line() int {
+	return bytes.Count(c.data[:c.dec.InputOffset()], []byte("\n")) + 1
+}
+
+func (c *schemaChecker) token() json.Token {
+	if c.broken {
+		return nil
+	}
+	tok, err := c.dec.Token()
+	if err != nil {
+		c.broken = true
+		return nil
+	}
+	return tok
+}
+
+func (c *schemaChecker) report(line int, fatal bool, format string, args ...any) {
+	c.problems = append(c.problems, configProblem{line: line, msg: fmt.Sprintf(format, args...), fatal: fatal})
+}
+
+// value checks the next value in the file, which decodes into t at path.
+func (c *schemaChecker) value(t reflect.Type, path string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	tok := c.token()
+	if c.broken || tok == nil {
+		return // a syntax error, or null, which any field accepts
+	}
+	line := c.line()
+	if d, ok := tok.(json.Delim); ok {
+		switch {
+		case d == '{' && t.Kind() == reflect.Struct:
+			c.object(t, path)
+		case d == '{' && t.Kind() == reflect.Map:
+			for c.dec.More() && !c.broken {
+				key, _ := c.token().(string)
+				c.value(t.Elem(), path+key+".")
+			}
+			c.token()
+		case d == '[' && t.Kind() == reflect.Slice:
+			for c.dec.More() && !c.broken {
+				c.value(t.Elem(), path)
+			}
+			c.token()
+		default:
+			c.report(line, true, "%q should be %s, not %s", strings.TrimSuffix(path, "."), schemaTypeName(t), tokenTypeName(tok))
+			c.skipRest()
+		}
+		return
+	}
+	if fix, ok := schemaFits(t, tok); !ok {
+		msg := fmt.Sprintf("%q should be %s, not %s", strings.TrimSuffix(path, "."), schemaTypeName(t), tokenTypeName(tok))
+		if fix != "" {
+			msg += "; " + fix
+		}
+		c.report(line, true, "%s", msg)
+	}
+}
+
+// object checks the keys of an object decoding into the struct t, having
+// read its opening brace.
+func (c *schemaChecker) object(t reflect.Type, path string) {
+	fields := map[string]reflect.Type{}
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if !f.IsExported() || name == "" || name == "-" {
+			continue
+		}
+		fields[name] = f.Type
+		names = append(names, name)
+	}
+
+	for c.dec.More() && !c.broken {
+		key, _ := c.token().(string)
+		if c.broken {
+			return
+		}
+		line := c.line()
+		if ft, ok := fields[key]; ok {
+			c.value(ft, path+key+".")
+			continue
+		}
+		if replacement, ok := deprecatedConfigKeys[path+key]; ok {
+			c.report(line, false, "%q is deprecated; rename it to %q", path+key, replacement)
+		} else if suggestion := closestKey(key, names); suggestion != "" {
+			c.report(line, false, "unknown key %q; did you mean %q?", path+key, suggestion)
+		} else {
+			c.report(line, false, "unknown key %q, which buzz ignores", path+key)
+		}
+		c.skipValue()
+	}
+	c.token()
+}
+
+// skipValue skips the next value, however deeply nested.
+func (c *schemaChecker) skipValue() {
+	if d, ok := c.token().(json.Delim); ok && (d == '{' || d == '[') {
+		c.skipRest()
+	}
+}
+
+// skipRest skips to the end of the object or list just opened.
+func (c *schemaChecker) skipRest() {
+	for depth := 1; depth > 0 && !c.broken; {
+		switch c.token() {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+}
+
+// schemaFits reports whether the scalar tok decodes into t, with a fix to
+// suggest when it doesn't and the fix is obvious.
+func schemaFits(t reflect.Type, tok json.Token) (fix string, ok bool) {
+	switch v := tok.(type) {
+	case string:
+		if t.Kind() == reflect.String {
+			return "", true
+		}
+		if t.Kind() == reflect.Bool && (v == "true" || v == "false") {
+			return "remove the quotes", false
+		}
+		if _, err := strconv.ParseFloat(v, 64); err == nil && isNumberKind(t.Kind()) {
+			return "remove the quotes", false
+		}
+	case json.Number:
+		switch {
+		case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+			return "", true
+		case isNumberKind(t.Kind()):
+			if _, err := v.Int64(); err == nil {
+				return "", true
+			}
+		case t.Kind() == reflect.String:
+			return "put it in quotes", false
+		}
+	case bool:
+		if t.Kind() == reflect.Bool {
+			return "", true
+		}
+		if t.Kind() == reflect.String {
+			return "put it in quotes", false
+		}
+	}
+	return "", false
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// schemaTypeName describes what a value decoding into t must be.
+func schemaTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "true or false"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "a whole number"
+	case reflect.Slice:
+		return "a list"
+	default:
+		return "an object"
+	}
+}
+
+// tokenTypeName describes the JSON value that starts with tok.
+func tokenTypeName(tok json.Token) string {
+	switch v := tok.(type) {
+	case string:
+		return "a string"
+	case json.Number:
+		if _, err := v.Int64(); err != nil {
+			return "a fraction"
+		}
+		return "a number"
+	case bool:
+		return strconv.FormatBool(v)
+	case json.Delim:
+		if v == '[' {
+			return "a list"
+		}
+	}
+	return "an object"
+}
+
+// closestKey returns the known key a typo most likely meant, or "" when none
+// is close.
+func closestKey(key string, names []string) string {
+	best, bestDist := "", 3
+	for _, name := range names {
+		if d := editDistance(strings.ToLower(key), name); d < bestDist {
+			best, bestDist = name, d
+		}
+	}
+	return best
+}
+
+// configWarnings describes the config's unknown and deprecated keys, one line
+// each, naming the file.
+func configWarnings(config *Config) []string {
+	path, err := getConfigPath()
+	if err != nil {
+		path = "config"
+	}
+	warnings := make([]string, len(config.problems))
+	for i, p := range config.problems {
+		warnings[i] = fmt.Sprintf("%s %s", path, p)
+	}
+	return warnings
+}
+
+// configSyntaxError adds the line to a JSON syntax error in the config.
+func configSyntaxError(data []byte, err error) error {
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		return err
+	}
+	line := bytes.Count(data[:syntaxErr.Offset], []byte("\n")) + 1
+	return fmt.Errorf("line %d: %w", line, err)
+}
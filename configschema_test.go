@@ -0,0 +1,79 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckConfigSchema(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		data string
+		want []string // each problem, fatal ones prefixed with "!"
+	}{
+		{"valid", `{"username": "alice", "auth_token": "x", "imap": {"host": "h", "port": 993}, "templates": {"habit": {"rate": 1.5, "tags": ["a"]}}, "filters": null}`, nil},
+		{"typo", "{\n  \"username\": \"alice\",\n  \"log_fle\": \"/tmp/buzz.log\"\n}", []string{`line 3: unknown key "log_fle"; did you mean "log_file"?`}},
+		{"unknown", `{"colour_scheme": {"a": [1, 2]}, "username": "alice"}`, []string{`line 1: unknown key "colour_scheme", which buzz ignores`}},
+		{"nested typo", "{\"imap\": {\n\"hots\": \"h\"}}", []string{`line 2: unknown key "imap.hots"; did you mean "host"?`}},
+		{"quoted number", "{\"imap\": {\"port\": \"993\"}}", []string{`!line 1: "imap.port" should be a whole number, not a string; remove the quotes`}},
+		{"fraction", `{"imap": {"port": 99.3}}`, []string{`!line 1: "imap.port" should be a whole number, not a fraction`}},
+		{"bare bool", `{"no_auto_requestid": "true"}`, []string{`!line 1: "no_auto_requestid" should be true or false, not a string; remove the quotes`}},
+		{"unquoted", `{"locale": true}`, []string{`!line 1: "locale" should be a string, not true; put it in quotes`}},
+		{"object for string", `{"hooks": {"path": "x"}, "log_fle": ""}`, []string{`!line 1: "hooks" should be a string, not an object`, `line 1: unknown key "log_fle"; did you mean "log_file"?`}},
+		{"list in map", `{"groups": {"work": "a"}}`, []string{`!line 1: "groups.work" should be a list, not a string`}},
+		{"syntax error", `{"username": "alice",`, nil},
+	} {
+		var got []string
+		for _, p := range checkConfigSchema([]byte(tt.data)) {
+			s := p.String()
+			if p.fatal {
+				s = "!" + s
+			}
+			got = append(got, s)
+		}
+		if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCheckConfigSchemaDeprecated(t *testing.T) {
+	deprecatedConfigKeys["logfile"] = "log_file"
+	defer delete(deprecatedConfigKeys, "logfile")
+	problems := checkConfigSchema([]byte(`{"logfile": "x"}`))
+	if len(problems) != 1 || problems[0].String() != `line 1: "logfile" is deprecated; rename it to "log_file"` {
+		t.Errorf("got %v", problems)
+	}
+}
+
+func TestLoadConfigValidation(t *testing.T) {
+	home := t.TempDir()
+	setHome(t, home)
+	write := func(data string) {
+		if err := os.WriteFile(filepath.Join(home, ".buzzrc"), []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("{\n  \"username\": \"alice\",\n  \"log_fle\": \"x\"\n}")
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	warnings := configWarnings(config)
+	if config.Username != "alice" || len(warnings) != 1 || !strings.HasSuffix(warnings[0], `.buzzrc line 3: unknown key "log_fle"; did you mean "log_file"?`) {
+		t.Errorf("config %+v, warnings %q", config, warnings)
+	}
+
+	write(`{"username": "alice", "imap": {"port": "993"}}`)
+	if _, err := LoadConfig(); err == nil || !strings.Contains(err.Error(), `line 1: "imap.port" should be a whole number`) {
+		t.Errorf("LoadConfig with a bad type: %v", err)
+	}
+
+	write("{\n  \"username\": \"alice\"\n  \"auth_token\": \"x\"\n}")
+	if _, err := LoadConfig(); err == nil || !strings.HasPrefix(err.Error(), "line 3: ") {
+		t.Errorf("LoadConfig with a syntax error: %v", err)
+	}
+}
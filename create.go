@@ -3,6 +3,7 @@ package main
 import (
 	"bufio"
 	"context"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
@@ -10,31 +11,51 @@ import (
 	"os"
 	"strconv"
 	"strings"
+
+	"github.com/charmbracelet/x/term"
 )
 
 // createUsage documents the non-interactive flag form of `buzz create`.
 const createUsage = `Usage: buzz create                 (interactive; prompts for each field)
        buzz create [flags]         (non-interactive; scriptable)
+       buzz create <slug> [title] [flags]
+       buzz create --template <name> [flags] [slug [title]]
+       buzz create --from <manifest> [--template <name>] [--dry-run]
 
 Flags:
-  --slug       Goal slug (required)
-  --units      Goal units (required)
+  --template   Start from a template saved in ~/.buzzrc
+  --from       Create every goal in a YAML or CSV manifest
+  --dry-run    With --from, show what would be created without creating it
+  --slug       Goal slug (required, unless given as an argument)
+  --units      Goal units (required); --gunits is the same
   --title      Goal title (defaults to the slug if omitted)
   --type       Goal type name/label/number (default: hustler)
   --goaldate   Goal date as an epoch timestamp
   --goalval    Goal value
   --rate       Rate
+  --runits     Rate units: y, m, w, d, or h (or year, month, week, day, hour)
   --deadline   Deadline in seconds from midnight (may be negative)
+  --json       Print the created goal as JSON instead of progress messages
 
-Provide exactly 2 of --goaldate, --goalval, --rate.`
+Provide exactly 2 of --goaldate, --goalval, --rate. A template supplies its
+settings (including a deadline and tags) for any flags not given.`
 
 // createRequest is a fully-gathered `buzz create` invocation, from either the
 // interactive prompts or CLI flags, ready to validate and send.
 type createRequest struct {
 	slug, title, goalType, gunits string
 	goaldate, goalval, rate       string
+	runits                        string // "" leaves Beeminder's default
 	deadline                      int
 	setDeadline                   bool // whether --deadline was explicitly passed
+	tags                          []string
+
+	template string          // --template name, applied by withTemplate
+	flagsSet map[string]bool // flags explicitly passed, which a template doesn't override
+
+	from       string // --from manifest path; the goals come from there instead
+	dryRun     bool
+	jsonOutput bool // print the created goal as JSON
 }
 
 // defaultGoalType is used when the user leaves the goal type prompt blank.
@@ -87,11 +108,33 @@ func handleCreateCommand() {
 		os.Exit(1)
 	}
 
-	var code int
+	// loadClient just loaded the config successfully.
+	config, _ := LoadConfig()
+	if req.from != "" {
+		os.Exit(handleCreateFromManifest(req, config, client))
+	}
+	if req.template != "" {
+		tmpl, err := lookupTemplate(config, req.template)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+		req = req.withTemplate(tmpl)
+	}
+
+	stdin := bufio.NewReader(os.Stdin)
 	if interactive {
-		code = runCreateCommand(os.Stdin, client, os.Stdout, os.Stderr)
-	} else {
-		code = doCreate(req, client, os.Stdout, os.Stderr)
+		req = promptCreateRequest(stdin, os.Stdout)
+	}
+	code := doCreate(req, client, os.Stdout, os.Stderr)
+	// JSON output is for scripts, which want just the goal.
+	if req.jsonOutput {
+		os.Exit(code)
+	}
+	// Only offer a first datapoint when someone is at the terminal to answer.
+	if code == 0 && term.IsTerminal(os.Stdin.Fd()) && term.IsTerminal(os.Stdout.Fd()) {
+		open := func(slug string) error { return openBrowser(config, slug, false, os.Stdout) }
+		code = offerFirstDatapoint(stdin, client, req.slug, req.gunits, open, os.Stdout, os.Stderr)
 	}
 	if code == 0 {
 		// Check for updates and display message if available
@@ -100,6 +143,28 @@ func handleCreateCommand() {
 	os.Exit(code)
 }
 
+// handleCreateFromManifest runs `buzz create --from`, returning the process
+// exit code.
+func handleCreateFromManifest(req createRequest, config *Config, client Client) int {
+	if req.template != "" {
+		// Check the default template once rather than against every goal.
+		if _, err := lookupTemplate(config, req.template); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			return 1
+		}
+	}
+	goals, err := readManifest(req.from)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Failed to read %s: %s\n", req.from, err)
+		return 1
+	}
+	code := runCreateFromManifest(context.Background(), goals, config, req.template, req.dryRun, client, os.Stdout, os.Stderr)
+	if code == 0 && !req.dryRun {
+		fmt.Print(getUpdateMessage())
+	}
+	return code
+}
+
 // parseCreateArgs parses non-interactive `buzz create` flags into a request. It
 // returns a process exit code and done=true when the caller should stop (help
 // shown or a parse error).
@@ -110,51 +175,130 @@ func parseCreateArgs(args []string, stdout, stderr io.Writer) (createRequest, in
 	title := fs.String("title", "", "Goal title (defaults to slug)")
 	goalType := fs.String("type", defaultGoalType, "Goal type")
 	gunits := fs.String("units", "", "Goal units")
+	fs.StringVar(gunits, "gunits", "", "Goal units (same as --units)")
 	goaldate := fs.String("goaldate", "", "Goal date (epoch timestamp)")
 	goalval := fs.String("goalval", "", "Goal value")
 	rate := fs.String("rate", "", "Rate")
+	runits := fs.String("runits", "", "Rate units")
 	deadline := fs.Int("deadline", 0, "Deadline in seconds from midnight")
-	if err := fs.Parse(args); err != nil {
-		if errors.Is(err, flag.ErrHelp) {
-			fmt.Fprintln(stdout, createUsage)
-			return createRequest{}, 0, true
+	template := fs.String("template", "", "Template name")
+	from := fs.String("from", "", "Manifest of goals to create")
+	dryRun := dryRunFlag(fs, "Show what --from would create")
+	jsonOutput := fs.Bool("json", false, "Print the created goal as JSON")
+
+	// Re-parse after each positional, as dial does, so flags can follow the
+	// slug: `buzz create reading --units pages ...`.
+	var positional []string
+	for remaining := args; ; {
+		if err := fs.Parse(remaining); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				fmt.Fprintln(stdout, createUsage)
+				return createRequest{}, 0, true
+			}
+			fmt.Fprintf(stderr, "Error parsing flags: %s\n", redactError(err))
+			fmt.Fprintln(stderr, createUsage)
+			return createRequest{}, 1, true
 		}
-		fmt.Fprintf(stderr, "Error parsing flags: %s\n", redactError(err))
-		fmt.Fprintln(stderr, createUsage)
-		return createRequest{}, 1, true
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		remaining = fs.Args()[1:]
 	}
 
-	// `buzz create` takes no positional arguments; leftovers usually mean a
-	// typo'd flag or a stray value that would otherwise be silently ignored.
-	if fs.NArg() != 0 {
-		fmt.Fprintf(stderr, "Error: unexpected argument(s): %s\n", strings.Join(fs.Args(), " "))
+	// Record which flags were explicitly set: a template fills in only the
+	// others, and 0 (midnight) is a valid deadline, so we can't infer intent
+	// from the values alone.
+	flagsSet := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { flagsSet[f.Name] = true })
+	if flagsSet["gunits"] {
+		flagsSet["units"] = true
+	}
+
+	// A manifest describes every goal itself, so only --template (a default
+	// for its goals) and --dry-run go with it.
+	if *from != "" {
+		for _, name := range []string{"slug", "title", "type", "units", "gunits", "goaldate", "goalval", "rate", "runits", "deadline"} {
+			if flagsSet[name] {
+				fmt.Fprintf(stderr, "Error: --%s can't be combined with --from; set it in the manifest\n", name)
+				fmt.Fprintln(stderr, createUsage)
+				return createRequest{}, 1, true
+			}
+		}
+		if len(positional) != 0 {
+			fmt.Fprintf(stderr, "Error: unexpected argument(s): %s\n", strings.Join(positional, " "))
+			fmt.Fprintln(stderr, createUsage)
+			return createRequest{}, 1, true
+		}
+		if *jsonOutput {
+			fmt.Fprintln(stderr, "Error: --json can't be combined with --from")
+			fmt.Fprintln(stderr, createUsage)
+			return createRequest{}, 1, true
+		}
+		return createRequest{template: *template, from: *from, dryRun: *dryRun}, 0, false
+	}
+	if *dryRun {
+		fmt.Fprintln(stderr, "Error: --dry-run only applies with --from")
 		fmt.Fprintln(stderr, createUsage)
 		return createRequest{}, 1, true
 	}
 
-	// Detect whether --deadline was explicitly set: 0 (midnight) is a valid
-	// deadline, so we can't infer intent from the value alone.
-	setDeadline := false
-	fs.Visit(func(f *flag.Flag) {
-		if f.Name == "deadline" {
-			setDeadline = true
+	// The slug and title may be given as arguments instead of flags, which
+	// keeps `buzz create --template habit meditate "Meditate"` short. Any
+	// other leftovers usually mean a typo'd flag or a stray value that would
+	// otherwise be silently ignored.
+	if len(positional) > 0 && !flagsSet["slug"] {
+		*slug, positional = positional[0], positional[1:]
+		if len(positional) > 0 && !flagsSet["title"] {
+			*title, positional = positional[0], positional[1:]
 		}
-	})
+	}
+	if len(positional) != 0 {
+		fmt.Fprintf(stderr, "Error: unexpected argument(s): %s\n", strings.Join(positional, " "))
+		fmt.Fprintln(stderr, createUsage)
+		return createRequest{}, 1, true
+	}
 
 	return createRequest{
 		slug: *slug, title: *title, goalType: resolveGoalType(*goalType), gunits: *gunits,
-		goaldate: *goaldate, goalval: *goalval, rate: *rate,
-		deadline: *deadline, setDeadline: setDeadline,
+		goaldate: *goaldate, goalval: *goalval, rate: *rate, runits: resolveRunits(*runits),
+		deadline: *deadline, setDeadline: flagsSet["deadline"],
+		template: *template, flagsSet: flagsSet, jsonOutput: *jsonOutput,
 	}, 0, false
 }
 
-// runCreateCommand is the testable core of `buzz create`. It prompts for goal
-// fields on stdin, validates them, creates the goal, and returns the process
-// exit code. Reading line-by-line means it also works with piped input for
-// scripting (e.g. `printf '...' | buzz create`).
-func runCreateCommand(stdin io.Reader, client Client, stdout, stderr io.Writer) int {
-	r := bufio.NewReader(stdin)
+// withTemplate returns the request with tmpl's settings filled in for every
+// flag that wasn't explicitly passed.
+func (req createRequest) withTemplate(tmpl GoalTemplate) createRequest {
+	if tmpl.Type != "" && !req.flagsSet["type"] {
+		req.goalType = resolveGoalType(tmpl.Type)
+	}
+	if tmpl.Units != "" && !req.flagsSet["units"] {
+		req.gunits = tmpl.Units
+	}
+	goaldate, goalval, rate := tmpl.targetFields()
+	if goaldate != "" && !req.flagsSet["goaldate"] {
+		req.goaldate = goaldate
+	}
+	if goalval != "" && !req.flagsSet["goalval"] {
+		req.goalval = goalval
+	}
+	if rate != "" && !req.flagsSet["rate"] {
+		req.rate = rate
+	}
+	// lookupTemplate has already checked the deadline parses.
+	if offset, ok, _ := tmpl.deadlineOffset(); ok && !req.setDeadline {
+		req.deadline, req.setDeadline = offset, true
+	}
+	if len(tmpl.Tags) > 0 {
+		req.tags = tmpl.Tags
+	}
+	return req
+}
 
+// promptCreateRequest prompts for each goal field in turn, gathering them into
+// a request for doCreate.
+func promptCreateRequest(r *bufio.Reader, stdout io.Writer) createRequest {
 	fmt.Fprintln(stdout, "Create a new Beeminder goal")
 	fmt.Fprintln(stdout, "===========================")
 	fmt.Fprintln(stdout, "")
@@ -171,42 +315,98 @@ func runCreateCommand(stdin io.Reader, client Client, stdout, stderr io.Writer)
 	req.goaldate = promptField(r, stdout, "Goal date (epoch timestamp): ")
 	req.goalval = promptField(r, stdout, "Goal value: ")
 	req.rate = promptField(r, stdout, "Rate: ")
+	if req.rate != "" && req.rate != "null" {
+		req.runits = resolveRunits(promptField(r, stdout, "Rate units (y/m/w/d/h, blank for Beeminder's default): "))
+	}
+	return req
+}
+
+// offerFirstDatapoint asks for a new goal's first datapoint and offers to open
+// its graph: a fresh goal with no data is the easiest kind to forget about.
+// A blank value skips the datapoint. It returns the process exit code.
+func offerFirstDatapoint(r *bufio.Reader, client Client, slug, gunits string, open func(slug string) error, stdout, stderr io.Writer) int {
+	fmt.Fprintln(stdout, "")
+	fmt.Fprintln(stdout, "New goals without data are easy to forget. Add a first datapoint now?")
+	if input := promptField(r, stdout, fmt.Sprintf("Value in %s (blank to skip): ", gunits)); input != "" {
+		value, err := parseAddValue(input, gunits)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %s\n", err)
+			return 1
+		}
+		req := addRequest{goalSlug: slug, value: value, comment: "Added via buzz", gunits: gunits}
+		if code := runAddCommand(req, client, stdout, stderr); code != 0 {
+			return code
+		}
+	}
 
-	return doCreate(req, client, stdout, stderr)
+	answer := strings.ToLower(promptField(r, stdout, "Open the goal's graph in your browser? [y/N] "))
+	if answer == "y" || answer == "yes" {
+		if err := open(slug); err != nil {
+			fmt.Fprintf(stderr, "Error: Failed to open %s: %s\n", slug, err)
+		}
+	}
+	return 0
 }
 
 // doCreate validates a gathered request, creates the goal, and (if requested)
-// sets its deadline. Shared by the interactive and non-interactive paths. Title
-// defaults to the slug when omitted, so callers needn't supply one.
+// sets its deadline and tags. Shared by the interactive and non-interactive paths. Title
+// defaults to the slug when omitted, so callers needn't supply one. With
+// jsonOutput, stdout gets only the finished goal as JSON.
 func doCreate(req createRequest, client Client, stdout, stderr io.Writer) int {
 	if req.title == "" {
 		req.title = req.slug
 	}
+	progress := stdout
+	if req.jsonOutput {
+		progress = io.Discard
+	}
 
-	if errMsg := validateCreateGoalInput(req.slug, req.title, req.goalType, req.gunits, req.goaldate, req.goalval, req.rate); errMsg != "" {
+	if errMsg := validateCreateGoalInput(req.slug, req.title, req.goalType, req.gunits, req.goaldate, req.goalval, req.rate, req.runits); errMsg != "" {
 		fmt.Fprintf(stderr, "Error: %s\n", errMsg)
 		return 1
 	}
 
-	fmt.Fprintln(stdout, "")
-	fmt.Fprintln(stdout, "Creating goal...")
+	fmt.Fprintln(progress, "")
+	fmt.Fprintln(progress, "Creating goal...")
 
-	goal, err := client.CreateGoal(context.Background(), req.slug, req.title, req.goalType, req.gunits, req.goaldate, req.goalval, req.rate)
+	goal, err := client.CreateGoal(context.Background(), req.slug, req.title, req.goalType, req.gunits, req.goaldate, req.goalval, req.rate, req.runits)
 	if err != nil {
 		fmt.Fprintf(stderr, "Error: Failed to create goal: %s\n", redactError(err))
 		return 1
 	}
 
-	fmt.Fprintf(stdout, "Successfully created goal: %s\n", goal.Slug)
+	fmt.Fprintf(progress, "Successfully created goal: %s\n", goal.Slug)
+	// result tracks the goal as of the last change, for --json.
+	result := goal
 
 	if req.setDeadline {
-		if _, err := client.UpdateGoalDeadline(context.Background(), goal.Slug, req.deadline); err != nil {
+		updated, err := client.UpdateGoalDeadline(context.Background(), goal.Slug, req.deadline)
+		if err != nil {
 			fmt.Fprintf(stderr, "Error: Goal created but failed to set deadline: %s\n", redactError(err))
 			return 1
 		}
-		fmt.Fprintf(stdout, "Set deadline: %d seconds from midnight\n", req.deadline)
+		result = updated
+		fmt.Fprintf(progress, "Set deadline: %d seconds from midnight\n", req.deadline)
+	}
+
+	if len(req.tags) > 0 {
+		updated, err := client.UpdateGoalTags(context.Background(), goal.Slug, req.tags)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: Goal created but failed to set tags: %s\n", redactError(err))
+			return 1
+		}
+		result = updated
+		fmt.Fprintf(progress, "Set tags: %s\n", strings.Join(req.tags, ", "))
 	}
 
+	if req.jsonOutput {
+		b, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %s\n", err)
+			return 1
+		}
+		fmt.Fprintln(stdout, string(b))
+	}
 	return 0
 }
 
@@ -247,7 +447,8 @@ func promptGoalType(r *bufio.Reader, w io.Writer) string {
 // by both the interactive prompt and the --type flag so `--type=1` or
 // `--type="Do More"` behave the same as picking from the menu. Unrecognized
 // non-empty input is returned unchanged so newly-added Beeminder types still
-// work; the API validates the final value.
+// work; the API validates the final value (validation only catches near
+// misses of known types, as typos).
 func resolveGoalType(choice string) string {
 	// Numeric selection from the menu.
 	if n, err := strconv.Atoi(choice); err == nil && n >= 1 && n <= len(goalTypeOptions) {
@@ -264,3 +465,85 @@ func resolveGoalType(choice string) string {
 	// Pass through anything else; validation / the API decides if it's valid.
 	return choice
 }
+
+// isGoalType reports whether name is one of goalTypeOptions' canonical names.
+func isGoalType(name string) bool {
+	for _, gt := range goalTypeOptions {
+		if gt.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// completeGoalType returns the canonical name of the first goal type whose
+// name or label starts with prefix (case-insensitively), or "" if none does.
+// The create modal completes a partly typed type with it.
+func completeGoalType(prefix string) string {
+	if prefix == "" {
+		return ""
+	}
+	prefix = strings.ToLower(prefix)
+	for _, gt := range goalTypeOptions {
+		if strings.HasPrefix(gt.name, prefix) || strings.HasPrefix(strings.ToLower(gt.label), prefix) {
+			return gt.name
+		}
+	}
+	return ""
+}
+
+// suggestGoalType returns the goal type a mistyped name most likely meant —
+// the one it completes, or the closest within two edits — or "" if none is
+// close.
+func suggestGoalType(choice string) string {
+	if name := completeGoalType(choice); name != "" {
+		return name
+	}
+	best, bestDist := "", 3
+	for _, gt := range goalTypeOptions {
+		if d := editDistance(strings.ToLower(choice), gt.name); d < bestDist {
+			best, bestDist = gt.name, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b, in bytes.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(min(prev[j]+1, cur[j-1]+1), prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// runitsOptions lists Beeminder's rate units, from the longest period to the
+// shortest, as the API's one-letter codes and the words they stand for.
+var runitsOptions = []struct{ code, name string }{
+	{"y", "year"}, {"m", "month"}, {"w", "week"}, {"d", "day"}, {"h", "hour"},
+}
+
+// resolveRunits maps a rate-units choice — a code, its word, or the word's
+// "-ly" form, case-insensitively ("w", "week", "weekly") — to the API's code.
+// Anything else is returned unchanged for validation to reject.
+func resolveRunits(choice string) string {
+	for _, ru := range runitsOptions {
+		if strings.EqualFold(choice, ru.code) || strings.EqualFold(choice, ru.name) ||
+			strings.EqualFold(choice, ru.name+"ly") || (ru.code == "d" && strings.EqualFold(choice, "daily")) {
+			return ru.code
+		}
+	}
+	return choice
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// manifestGoal is one goal in a `buzz create --from` manifest: a slug and
+// title plus the same settings a saved template holds, optionally starting
+// from a saved template by name.
+type manifestGoal struct {
+	Slug         string `yaml:"slug"`
+	Title        string `yaml:"title"`
+	Template     string `yaml:"template"`
+	GoalTemplate `yaml:",inline"`
+}
+
+// manifestCSVColumns are the columns a CSV manifest may have, in the order
+// the docs list them. Tags are separated by spaces.
+var manifestCSVColumns = []string{"slug", "title", "template", "type", "units", "rate", "goalval", "goaldate", "deadline", "tags"}
+
+// readManifest reads the goals from a YAML (or JSON) or CSV manifest, chosen
+// by the file's extension.
+func readManifest(path string) ([]manifestGoal, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+		return parseYAMLManifest(f)
+	case ".csv":
+		return parseCSVManifest(f)
+	default:
+		return nil, fmt.Errorf("unknown manifest format %q (use .yaml, .yml, .json, or .csv)", filepath.Ext(path))
+	}
+}
+
+// parseYAMLManifest reads a YAML list of goals. Unknown keys are errors, so a
+// misspelled setting isn't silently dropped.
+func parseYAMLManifest(r io.Reader) ([]manifestGoal, error) {
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	var goals []manifestGoal
+	if err := dec.Decode(&goals); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return goals, nil
+}
+
+// parseCSVManifest reads a CSV manifest whose header names the columns used,
+// from manifestCSVColumns. Empty cells leave a setting unset.
+func parseCSVManifest(r io.Reader) ([]manifestGoal, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV header: %w", err)
+	}
+	for i, name := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !slices.Contains(manifestCSVColumns, header[i]) {
+			return nil, fmt.Errorf("unknown CSV column %q (expected some of: %s)", name, strings.Join(manifestCSVColumns, ", "))
+		}
+	}
+
+	var goals []manifestGoal
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		var g manifestGoal
+		for i, cell := range row {
+			if cell = strings.TrimSpace(cell); cell == "" {
+				continue
+			}
+			if err := g.setCSVField(header[i], cell); err != nil {
+				return nil, fmt.Errorf("line %d: %s: %w", line, header[i], err)
+			}
+		}
+		goals = append(goals, g)
+	}
+	return goals, nil
+}
+
+// setCSVField sets the setting named by a CSV column from a non-empty cell.
+func (g *manifestGoal) setCSVField(column, cell string) error {
+	parseFloat := func() (*float64, error) {
+		v, err := strconv.ParseFloat(cell, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", cell)
+		}
+		return &v, nil
+	}
+	var err error
+	switch column {
+	case "slug":
+		g.Slug = cell
+	case "title":
+		g.Title = cell
+	case "template":
+		g.Template = cell
+	case "type":
+		g.Type = cell
+	case "units":
+		g.Units = cell
+	case "rate":
+		g.Rate, err = parseFloat()
+	case "goalval":
+		g.Goalval, err = parseFloat()
+	case "goaldate":
+		v, perr := strconv.ParseInt(cell, 10, 64)
+		if perr != nil {
+			return fmt.Errorf("invalid epoch timestamp %q", cell)
+		}
+		g.Goaldate = &v
+	case "deadline":
+		g.Deadline = cell
+	case "tags":
+		g.Tags = strings.Fields(cell)
+	}
+	return err
+}
+
+// overlay returns t with over's settings laid on top. If over sets any of
+// goaldate, goalval, and rate, it replaces all three, so it alone decides
+// which 2 of the 3 are given.
+func (t GoalTemplate) overlay(over GoalTemplate) GoalTemplate {
+	if over.Type != "" {
+		t.Type = over.Type
+	}
+	if over.Units != "" {
+		t.Units = over.Units
+	}
+	if over.hasTarget() {
+		t.Rate, t.Goalval, t.Goaldate = over.Rate, over.Goalval, over.Goaldate
+	}
+	if over.Deadline != "" {
+		t.Deadline = over.Deadline
+	}
+	if len(over.Tags) > 0 {
+		t.Tags = over.Tags
+	}
+	return t
+}
+
+// resolve merges a manifest goal with its template (its own, or else
+// defaultTemplate) and validates the result, returning the request to create
+// it and the settings to apply once it exists.
+func (g manifestGoal) resolve(config *Config, defaultTemplate string) (createRequest, GoalTemplate, error) {
+	settings := g.GoalTemplate
+	if name := cmp.Or(g.Template, defaultTemplate); name != "" {
+		saved, err := lookupTemplate(config, name)
+		if err != nil {
+			return createRequest{}, GoalTemplate{}, err
+		}
+		settings = saved.overlay(g.GoalTemplate)
+	}
+	if _, _, err := settings.deadlineOffset(); err != nil {
+		return createRequest{}, GoalTemplate{}, err
+	}
+
+	req := createRequest{slug: g.Slug, title: cmp.Or(g.Title, g.Slug), goalType: defaultGoalType}.withTemplate(settings)
+	if errMsg := validateCreateGoalInput(req.slug, req.title, req.goalType, req.gunits, req.goaldate, req.goalval, req.rate, req.runits); errMsg != "" {
+		return createRequest{}, GoalTemplate{}, errors.New(errMsg)
+	}
+	return req, settings, nil
+}
+
+// describeCreateRequest summarizes what a request would create, e.g.
+// "hustler in sessions, rate 5, goal value 1000, deadline 11:00 PM, tags habits".
+func describeCreateRequest(req createRequest) string {
+	parts := []string{fmt.Sprintf("%s in %s", req.goalType, req.gunits)}
+	for _, f := range []struct{ name, value string }{
+		{"goal date", req.goaldate}, {"goal value", req.goalval}, {"rate", req.rate},
+	} {
+		if f.value != "" && f.value != "null" {
+			parts = append(parts, f.name+" "+f.value)
+		}
+	}
+	if req.setDeadline {
+		parts = append(parts, "deadline "+formatDueTime(req.deadline))
+	}
+	if len(req.tags) > 0 {
+		parts = append(parts, "tags "+strings.Join(req.tags, " "))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// runCreateFromManifest creates each goal in a manifest, or with dryRun just
+// shows what it would create. A goal that fails doesn't stop the rest; each
+// failure is reported against its goal. It returns the process exit code: 1
+// if any goal failed.
+func runCreateFromManifest(ctx context.Context, goals []manifestGoal, config *Config, defaultTemplate string, dryRun bool, client Client, out, errOut io.Writer) int {
+	if len(goals) == 0 {
+		fmt.Fprintln(errOut, "Error: The manifest lists no goals")
+		return 1
+	}
+
+	seen := map[string]bool{}
+	created, failed := 0, 0
+	for i, g := range goals {
+		label := g.Slug
+		if label == "" {
+			label = fmt.Sprintf("goal %d", i+1)
+		}
+
+		req, settings, err := g.resolve(config, defaultTemplate)
+		if err == nil && seen[req.slug] {
+			err = errors.New("slug is listed more than once")
+		}
+		if err != nil {
+			fmt.Fprintf(errOut, "Error: %s: %s\n", label, err)
+			failed++
+			continue
+		}
+		seen[req.slug] = true
+
+		if dryRun {
+			fmt.Fprintf(out, "Would create %s: %s\n", req.slug, describeCreateRequest(req))
+			continue
+		}
+		goal, err := client.CreateGoal(ctx, req.slug, req.title, req.goalType, req.gunits, req.goaldate, req.goalval, req.rate, req.runits)
+		if err != nil {
+			fmt.Fprintf(errOut, "Error: %s: Failed to create goal: %s\n", label, redactError(err))
+			failed++
+			continue
+		}
+		if err := applyGoalTemplateSettings(ctx, client, goal.Slug, settings); err != nil {
+			fmt.Fprintf(errOut, "Error: %s: Goal created but %s\n", label, redactError(err))
+			failed++
+			continue
+		}
+		fmt.Fprintf(out, "Created %s: %s\n", goal.Slug, describeCreateRequest(req))
+		created++
+	}
+
+	fmt.Fprintln(out)
+	switch {
+	case dryRun && failed == 0:
+		fmt.Fprintf(out, "Dry run: would create %d goals.\n", len(goals))
+	case dryRun:
+		fmt.Fprintf(out, "Dry run: would create %d of %d goals; fix the errors above first.\n", len(goals)-failed, len(goals))
+	default:
+		fmt.Fprintf(out, "Created %d of %d goals.\n", created, len(goals))
+	}
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
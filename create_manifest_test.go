@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseYAMLManifest(t *testing.T) {
+	goals, err := parseYAMLManifest(strings.NewReader(`
+- slug: meditate
+  title: Meditate
+  template: habit
+  tags: [mind]
+- slug: nojunk
+  type: Do Less
+  units: servings
+  goalval: 0
+  rate: 2
+  deadline: "10:00 PM"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(goals) != 2 {
+		t.Fatalf("got %d goals, want 2", len(goals))
+	}
+	if g := goals[0]; g.Slug != "meditate" || g.Template != "habit" || len(g.Tags) != 1 {
+		t.Errorf("goals[0] = %+v", g)
+	}
+	if g := goals[1]; g.Type != "Do Less" || g.Rate == nil || *g.Rate != 2 || g.Goalval == nil || g.Deadline != "10:00 PM" {
+		t.Errorf("goals[1] = %+v", g)
+	}
+
+	if _, err := parseYAMLManifest(strings.NewReader("- slug: x\n  unit: pages\n")); err == nil {
+		t.Error("want an error for the misspelled unit key")
+	}
+}
+
+func TestParseCSVManifest(t *testing.T) {
+	goals, err := parseCSVManifest(strings.NewReader("slug,title,units,rate,goalval,tags\nreading,Reading,pages,10,,books daily\nwriting,,words,,500,\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(goals) != 2 {
+		t.Fatalf("got %d goals, want 2", len(goals))
+	}
+	if g := goals[0]; g.Units != "pages" || *g.Rate != 10 || g.Goalval != nil || strings.Join(g.Tags, ",") != "books,daily" {
+		t.Errorf("goals[0] = %+v", g)
+	}
+	if g := goals[1]; g.Title != "" || g.Rate != nil || *g.Goalval != 500 {
+		t.Errorf("goals[1] = %+v", g)
+	}
+
+	if _, err := parseCSVManifest(strings.NewReader("slug,rate\nx,fast\n")); err == nil || !strings.Contains(err.Error(), "line 2: rate") {
+		t.Errorf("want a line-numbered number error, got %v", err)
+	}
+	if _, err := parseCSVManifest(strings.NewReader("slug,colour\nx,red\n")); err == nil || !strings.Contains(err.Error(), `unknown CSV column "colour"`) {
+		t.Errorf("want an unknown-column error, got %v", err)
+	}
+}
+
+func TestGoalTemplateOverlay(t *testing.T) {
+	rate, goalval := 5.0, 100.0
+	base := GoalTemplate{Type: "hustler", Units: "sessions", Rate: &rate, Goalval: &goalval, Tags: []string{"habits"}}
+
+	got := base.overlay(GoalTemplate{Units: "minutes"})
+	if got.Type != "hustler" || got.Units != "minutes" || got.Rate != &rate || strings.Join(got.Tags, ",") != "habits" {
+		t.Errorf("overlay kept %+v", got)
+	}
+
+	// Setting any of the three replaces all of them.
+	goaldate := int64(1900000000)
+	got = base.overlay(GoalTemplate{Goaldate: &goaldate})
+	if got.Rate != nil || got.Goalval != nil || got.Goaldate != &goaldate {
+		t.Errorf("overlay target = rate %v, goalval %v, goaldate %v", got.Rate, got.Goalval, got.Goaldate)
+	}
+}
+
+func TestRunCreateFromManifest(t *testing.T) {
+	rate, goalval := 1.0, 100.0
+	config := &Config{Templates: map[string]GoalTemplate{
+		"habit": {Units: "sessions", Rate: &rate, Goalval: &goalval, Deadline: "23:00"},
+	}}
+	goals := []manifestGoal{
+		{Slug: "meditate", Template: "habit"},
+		{Slug: "stretch", Template: "habit", GoalTemplate: GoalTemplate{Tags: []string{"body"}}},
+		{Slug: "meditate", Template: "habit"},
+		{Slug: "", Template: "habit"},
+		{Slug: "taken", Template: "habit"},
+	}
+
+	var created []string
+	var deadlines, tagged int
+	client := &FakeClient{
+		CreateGoalFunc: func(slug, _, _, gunits, _, _, _, _ string) (*Goal, error) {
+			if slug == "taken" {
+				return nil, errors.New("slug already exists")
+			}
+			created = append(created, slug+" "+gunits)
+			return &Goal{Slug: slug}, nil
+		},
+		UpdateGoalDeadlineFunc: func(string, int) (*Goal, error) { deadlines++; return &Goal{}, nil },
+		UpdateGoalTagsFunc:     func(string, []string) (*Goal, error) { tagged++; return &Goal{}, nil },
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		var out, errb bytes.Buffer
+		code := runCreateFromManifest(context.Background(), goals, config, "", true, client, &out, &errb)
+		checkResult(t, code, out.String(), errb.String(), 1, "Would create stretch: hustler in sessions, goal value 100, rate 1, deadline 11:00 PM, tags body", "meditate: slug is listed more than once")
+		if !strings.Contains(errb.String(), "goal 4: Slug cannot be empty") {
+			t.Errorf("stderr lacks the empty-slug error:\n%s", errb.String())
+		}
+		if !strings.Contains(out.String(), "would create 3 of 5 goals") {
+			t.Errorf("stdout lacks the summary:\n%s", out.String())
+		}
+		if len(created) != 0 {
+			t.Errorf("dry run created %v", created)
+		}
+	})
+
+	t.Run("create", func(t *testing.T) {
+		var out, errb bytes.Buffer
+		code := runCreateFromManifest(context.Background(), goals, config, "", false, client, &out, &errb)
+		checkResult(t, code, out.String(), errb.String(), 1, "Created 2 of 5 goals.", "taken: Failed to create goal: slug already exists")
+		if strings.Join(created, ",") != "meditate sessions,stretch sessions" || deadlines != 2 || tagged != 1 {
+			t.Errorf("created %v with %d deadlines and %d tag updates", created, deadlines, tagged)
+		}
+	})
+
+	t.Run("default template", func(t *testing.T) {
+		var out, errb bytes.Buffer
+		code := runCreateFromManifest(context.Background(), []manifestGoal{{Slug: "journal"}}, config, "habit", true, client, &out, &errb)
+		checkResult(t, code, out.String(), errb.String(), 0, "Would create journal: hustler in sessions", "")
+	})
+}
+
+func TestParseCreateArgsFrom(t *testing.T) {
+	req, code, done := parseCreateArgs([]string{"--from", "goals.yaml", "--template=habit", "--dry-run"}, &bytes.Buffer{}, &bytes.Buffer{})
+	if done || code != 0 || req.from != "goals.yaml" || req.template != "habit" || !req.dryRun {
+		t.Errorf("parse = %+v, code %d, done %v", req, code, done)
+	}
+
+	var stderr bytes.Buffer
+	if _, code, done := parseCreateArgs([]string{"--from", "goals.yaml", "--units=pages"}, &bytes.Buffer{}, &stderr); !done || code != 1 || !strings.Contains(stderr.String(), "--units can't be combined with --from") {
+		t.Errorf("--units with --from: code %d, done %v, stderr %s", code, done, stderr.String())
+	}
+
+	stderr.Reset()
+	if _, code, done := parseCreateArgs([]string{"--slug=x", "--dry-run"}, &bytes.Buffer{}, &stderr); !done || code != 1 || !strings.Contains(stderr.String(), "--dry-run only applies with --from") {
+		t.Errorf("--dry-run without --from: code %d, done %v, stderr %s", code, done, stderr.String())
+	}
+}
@@ -1,20 +1,22 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
+	"encoding/json"
 	"errors"
 	"strings"
 	"testing"
 )
 
-// TestRunCreateCommandSuccess verifies the happy path: prompts are answered,
+// TestInteractiveCreateSuccess verifies the happy path: prompts are answered,
 // the entered fields are forwarded to CreateGoal, and the created slug is
 // reported. Goal value and rate are provided (goal date left blank), satisfying
 // the "exactly 2 of 3" rule.
-func TestRunCreateCommandSuccess(t *testing.T) {
+func TestInteractiveCreateSuccess(t *testing.T) {
 	var got struct{ slug, title, goalType, gunits, goaldate, goalval, rate string }
 	client := &FakeClient{
-		CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate string) (*Goal, error) {
+		CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate, runits string) (*Goal, error) {
 			got.slug, got.title, got.goalType, got.gunits = slug, title, goalType, gunits
 			got.goaldate, got.goalval, got.rate = goaldate, goalval, rate
 			return &Goal{Slug: slug}, nil
@@ -23,7 +25,7 @@ func TestRunCreateCommandSuccess(t *testing.T) {
 
 	stdin := strings.NewReader("reading\nDaily Reading\nhustler\npages\n\n365\n1\n")
 	var stdout, stderr bytes.Buffer
-	code := runCreateCommand(stdin, client, &stdout, &stderr)
+	code := doCreate(promptCreateRequest(bufio.NewReader(stdin), &stdout), client, &stdout, &stderr)
 
 	if code != 0 {
 		t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, stderr.String())
@@ -39,12 +41,12 @@ func TestRunCreateCommandSuccess(t *testing.T) {
 	}
 }
 
-// TestRunCreateCommandDefaultGoalType verifies that leaving the goal type blank
+// TestInteractiveCreateDefaultGoalType verifies that leaving the goal type blank
 // falls back to the default "hustler" rather than failing validation.
-func TestRunCreateCommandDefaultGoalType(t *testing.T) {
+func TestInteractiveCreateDefaultGoalType(t *testing.T) {
 	var gotType string
 	client := &FakeClient{
-		CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate string) (*Goal, error) {
+		CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate, runits string) (*Goal, error) {
 			gotType = goalType
 			return &Goal{Slug: slug}, nil
 		},
@@ -52,7 +54,7 @@ func TestRunCreateCommandDefaultGoalType(t *testing.T) {
 
 	stdin := strings.NewReader("reading\nDaily Reading\n\npages\n\n365\n1\n")
 	var stdout, stderr bytes.Buffer
-	if code := runCreateCommand(stdin, client, &stdout, &stderr); code != 0 {
+	if code := doCreate(promptCreateRequest(bufio.NewReader(stdin), &stdout), client, &stdout, &stderr); code != 0 {
 		t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, stderr.String())
 	}
 	if gotType != defaultGoalType {
@@ -60,13 +62,13 @@ func TestRunCreateCommandDefaultGoalType(t *testing.T) {
 	}
 }
 
-// TestRunCreateCommandGoalTypeByNumber verifies that selecting a goal type by
+// TestInteractiveCreateGoalTypeByNumber verifies that selecting a goal type by
 // its menu number resolves to the canonical goal_type value (here, choice "2"
 // → "drinker"), and that the menu lists each type with its plain-language label.
-func TestRunCreateCommandGoalTypeByNumber(t *testing.T) {
+func TestInteractiveCreateGoalTypeByNumber(t *testing.T) {
 	var gotType string
 	client := &FakeClient{
-		CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate string) (*Goal, error) {
+		CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate, runits string) (*Goal, error) {
 			gotType = goalType
 			return &Goal{Slug: slug}, nil
 		},
@@ -74,7 +76,7 @@ func TestRunCreateCommandGoalTypeByNumber(t *testing.T) {
 
 	stdin := strings.NewReader("nojunk\nNo Junk Food\n2\nservings\n\n0\n-1\n")
 	var stdout, stderr bytes.Buffer
-	if code := runCreateCommand(stdin, client, &stdout, &stderr); code != 0 {
+	if code := doCreate(promptCreateRequest(bufio.NewReader(stdin), &stdout), client, &stdout, &stderr); code != 0 {
 		t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, stderr.String())
 	}
 	if gotType != "drinker" {
@@ -86,12 +88,12 @@ func TestRunCreateCommandGoalTypeByNumber(t *testing.T) {
 	}
 }
 
-// TestRunCreateCommandGoalTypeCaseInsensitiveName verifies a canonical name
+// TestInteractiveCreateGoalTypeCaseInsensitiveName verifies a canonical name
 // typed in a different case still resolves (EqualFold) to the canonical value.
-func TestRunCreateCommandGoalTypeCaseInsensitiveName(t *testing.T) {
+func TestInteractiveCreateGoalTypeCaseInsensitiveName(t *testing.T) {
 	var gotType string
 	client := &FakeClient{
-		CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate string) (*Goal, error) {
+		CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate, runits string) (*Goal, error) {
 			gotType = goalType
 			return &Goal{Slug: slug}, nil
 		},
@@ -99,7 +101,7 @@ func TestRunCreateCommandGoalTypeCaseInsensitiveName(t *testing.T) {
 
 	stdin := strings.NewReader("reading\nDaily Reading\nHUSTLER\npages\n\n365\n1\n")
 	var stdout, stderr bytes.Buffer
-	if code := runCreateCommand(stdin, client, &stdout, &stderr); code != 0 {
+	if code := doCreate(promptCreateRequest(bufio.NewReader(stdin), &stdout), client, &stdout, &stderr); code != 0 {
 		t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, stderr.String())
 	}
 	if gotType != "hustler" {
@@ -107,11 +109,11 @@ func TestRunCreateCommandGoalTypeCaseInsensitiveName(t *testing.T) {
 	}
 }
 
-// TestRunCreateCommandGoalTypePassthrough verifies the forward-compat escape
+// TestInteractiveCreateGoalTypePassthrough verifies the forward-compat escape
 // hatch: input that is neither a valid menu number nor a known name/label —
 // including an out-of-range number — is forwarded to CreateGoal verbatim, so a
 // goal_type buzz doesn't yet know about still works.
-func TestRunCreateCommandGoalTypePassthrough(t *testing.T) {
+func TestInteractiveCreateGoalTypePassthrough(t *testing.T) {
 	for _, tc := range []struct{ name, input, want string }{
 		{"unknown name", "whittler", "whittler"},
 		{"out-of-range number", "99", "99"},
@@ -119,7 +121,7 @@ func TestRunCreateCommandGoalTypePassthrough(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			var gotType string
 			client := &FakeClient{
-				CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate string) (*Goal, error) {
+				CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate, runits string) (*Goal, error) {
 					gotType = goalType
 					return &Goal{Slug: slug}, nil
 				},
@@ -127,7 +129,7 @@ func TestRunCreateCommandGoalTypePassthrough(t *testing.T) {
 
 			stdin := strings.NewReader("reading\nDaily Reading\n" + tc.input + "\npages\n\n365\n1\n")
 			var stdout, stderr bytes.Buffer
-			if code := runCreateCommand(stdin, client, &stdout, &stderr); code != 0 {
+			if code := doCreate(promptCreateRequest(bufio.NewReader(stdin), &stdout), client, &stdout, &stderr); code != 0 {
 				t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, stderr.String())
 			}
 			if gotType != tc.want {
@@ -137,12 +139,63 @@ func TestRunCreateCommandGoalTypePassthrough(t *testing.T) {
 	}
 }
 
-// TestRunCreateCommandGoalTypeByLabel verifies that a human label typed directly
+// TestInteractiveCreateRejectsGoalTypeTypo verifies that a near miss of a
+// known goal type is taken for a typo and rejected before CreateGoal, with a
+// suggestion, rather than failing server-side.
+func TestInteractiveCreateRejectsGoalTypeTypo(t *testing.T) {
+	client := &FakeClient{
+		CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate, runits string) (*Goal, error) {
+			t.Errorf("CreateGoal called with goal type %q", goalType)
+			return &Goal{Slug: slug}, nil
+		},
+	}
+
+	stdin := strings.NewReader("reading\nDaily Reading\nhustlr\npages\n\n365\n1\n")
+	var stdout, stderr bytes.Buffer
+	if code := doCreate(promptCreateRequest(bufio.NewReader(stdin), &stdout), client, &stdout, &stderr); code != 1 {
+		t.Fatalf("expected exit code 1, got %d", code)
+	}
+	want := `Error: Unknown goal type "hustlr" (did you mean hustler?)`
+	if got := strings.TrimSpace(stderr.String()); got != want {
+		t.Errorf("stderr = %q, want %q", got, want)
+	}
+}
+
+// TestInteractiveCreateRateUnits verifies the rate-units prompt, asked only
+// when a rate is given, accepts a code or word and passes the code on.
+func TestInteractiveCreateRateUnits(t *testing.T) {
+	for _, tc := range []struct{ name, input, want string }{
+		{"code", "reading\n\n1\npages\n\n365\n1\nd\n", "d"},
+		{"word", "reading\n\n1\npages\n\n365\n1\nWeekly\n", "w"},
+		{"blank", "reading\n\n1\npages\n\n365\n1\n\n", ""},
+		{"no rate", "reading\n\n1\npages\n1893456000\n365\n\nd\n", ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			gotRunits := "unset"
+			client := &FakeClient{
+				CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate, runits string) (*Goal, error) {
+					gotRunits = runits
+					return &Goal{Slug: slug}, nil
+				},
+			}
+
+			var stdout, stderr bytes.Buffer
+			if code := doCreate(promptCreateRequest(bufio.NewReader(strings.NewReader(tc.input)), &stdout), client, &stdout, &stderr); code != 0 {
+				t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, stderr.String())
+			}
+			if gotRunits != tc.want {
+				t.Errorf("runits = %q, want %q", gotRunits, tc.want)
+			}
+		})
+	}
+}
+
+// TestInteractiveCreateGoalTypeByLabel verifies that a human label typed directly
 // (case-insensitively) resolves to the canonical goal_type value.
-func TestRunCreateCommandGoalTypeByLabel(t *testing.T) {
+func TestInteractiveCreateGoalTypeByLabel(t *testing.T) {
 	var gotType string
 	client := &FakeClient{
-		CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate string) (*Goal, error) {
+		CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate, runits string) (*Goal, error) {
 			gotType = goalType
 			return &Goal{Slug: slug}, nil
 		},
@@ -150,7 +203,7 @@ func TestRunCreateCommandGoalTypeByLabel(t *testing.T) {
 
 	stdin := strings.NewReader("reading\nDaily Reading\ndo more\npages\n\n365\n1\n")
 	var stdout, stderr bytes.Buffer
-	if code := runCreateCommand(stdin, client, &stdout, &stderr); code != 0 {
+	if code := doCreate(promptCreateRequest(bufio.NewReader(stdin), &stdout), client, &stdout, &stderr); code != 0 {
 		t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, stderr.String())
 	}
 	if gotType != "hustler" {
@@ -158,12 +211,12 @@ func TestRunCreateCommandGoalTypeByLabel(t *testing.T) {
 	}
 }
 
-// TestRunCreateCommandGoalDateAndValue verifies the third accepted permutation
+// TestInteractiveCreateGoalDateAndValue verifies the third accepted permutation
 // of the 2-of-3 rule: goal date + goal value provided, rate left blank.
-func TestRunCreateCommandGoalDateAndValue(t *testing.T) {
+func TestInteractiveCreateGoalDateAndValue(t *testing.T) {
 	var got struct{ goaldate, goalval, rate string }
 	client := &FakeClient{
-		CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate string) (*Goal, error) {
+		CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate, runits string) (*Goal, error) {
 			got.goaldate, got.goalval, got.rate = goaldate, goalval, rate
 			return &Goal{Slug: slug}, nil
 		},
@@ -171,7 +224,7 @@ func TestRunCreateCommandGoalDateAndValue(t *testing.T) {
 
 	stdin := strings.NewReader("reading\nDaily Reading\nhustler\npages\n1700000000\n365\n\n")
 	var stdout, stderr bytes.Buffer
-	if code := runCreateCommand(stdin, client, &stdout, &stderr); code != 0 {
+	if code := doCreate(promptCreateRequest(bufio.NewReader(stdin), &stdout), client, &stdout, &stderr); code != 0 {
 		t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, stderr.String())
 	}
 	if got.goaldate != "1700000000" || got.goalval != "365" || got.rate != "" {
@@ -179,12 +232,12 @@ func TestRunCreateCommandGoalDateAndValue(t *testing.T) {
 	}
 }
 
-// TestRunCreateCommandGoalDateAndRate verifies the other accepted permutation
+// TestInteractiveCreateGoalDateAndRate verifies the other accepted permutation
 // of the 2-of-3 rule: goal date + rate provided, goal value left blank.
-func TestRunCreateCommandGoalDateAndRate(t *testing.T) {
+func TestInteractiveCreateGoalDateAndRate(t *testing.T) {
 	var got struct{ goaldate, goalval, rate string }
 	client := &FakeClient{
-		CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate string) (*Goal, error) {
+		CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate, runits string) (*Goal, error) {
 			got.goaldate, got.goalval, got.rate = goaldate, goalval, rate
 			return &Goal{Slug: slug}, nil
 		},
@@ -192,7 +245,7 @@ func TestRunCreateCommandGoalDateAndRate(t *testing.T) {
 
 	stdin := strings.NewReader("reading\nDaily Reading\nhustler\npages\n1700000000\n\n1\n")
 	var stdout, stderr bytes.Buffer
-	if code := runCreateCommand(stdin, client, &stdout, &stderr); code != 0 {
+	if code := doCreate(promptCreateRequest(bufio.NewReader(stdin), &stdout), client, &stdout, &stderr); code != 0 {
 		t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, stderr.String())
 	}
 	if got.goaldate != "1700000000" || got.goalval != "" || got.rate != "1" {
@@ -200,13 +253,13 @@ func TestRunCreateCommandGoalDateAndRate(t *testing.T) {
 	}
 }
 
-// TestRunCreateCommandTrimsWhitespace verifies that surrounding whitespace and
+// TestInteractiveCreateTrimsWhitespace verifies that surrounding whitespace and
 // Windows CRLF line endings (\r\n) are stripped from each field, so piped or
 // pasted input doesn't leak a stray \r or spaces into the API call.
-func TestRunCreateCommandTrimsWhitespace(t *testing.T) {
+func TestInteractiveCreateTrimsWhitespace(t *testing.T) {
 	var got struct{ slug, title, gunits string }
 	client := &FakeClient{
-		CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate string) (*Goal, error) {
+		CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate, runits string) (*Goal, error) {
 			got.slug, got.title, got.gunits = slug, title, gunits
 			return &Goal{Slug: slug}, nil
 		},
@@ -214,7 +267,7 @@ func TestRunCreateCommandTrimsWhitespace(t *testing.T) {
 
 	stdin := strings.NewReader("  reading  \r\nDaily Reading\r\nhustler\r\n pages \r\n\r\n365\r\n1\r\n")
 	var stdout, stderr bytes.Buffer
-	if code := runCreateCommand(stdin, client, &stdout, &stderr); code != 0 {
+	if code := doCreate(promptCreateRequest(bufio.NewReader(stdin), &stdout), client, &stdout, &stderr); code != 0 {
 		t.Fatalf("expected exit code 0, got %d (stderr: %s)", code, stderr.String())
 	}
 	if got.slug != "reading" || got.title != "Daily Reading" || got.gunits != "pages" {
@@ -222,13 +275,13 @@ func TestRunCreateCommandTrimsWhitespace(t *testing.T) {
 	}
 }
 
-// TestRunCreateCommandTruncatedInput verifies graceful failure when stdin ends
+// TestInteractiveCreateTruncatedInput verifies graceful failure when stdin ends
 // before all prompts are answered (e.g. a short pipe): the missing required
 // fields fail validation, no API call is made, and the exit code is non-zero.
-func TestRunCreateCommandTruncatedInput(t *testing.T) {
+func TestInteractiveCreateTruncatedInput(t *testing.T) {
 	called := false
 	client := &FakeClient{
-		CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate string) (*Goal, error) {
+		CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate, runits string) (*Goal, error) {
 			called = true
 			return &Goal{Slug: slug}, nil
 		},
@@ -238,7 +291,7 @@ func TestRunCreateCommandTruncatedInput(t *testing.T) {
 	// prompts read empty strings at EOF.
 	stdin := strings.NewReader("reading\nDaily Reading")
 	var stdout, stderr bytes.Buffer
-	code := runCreateCommand(stdin, client, &stdout, &stderr)
+	code := doCreate(promptCreateRequest(bufio.NewReader(stdin), &stdout), client, &stdout, &stderr)
 
 	if code != 1 {
 		t.Fatalf("expected exit code 1, got %d", code)
@@ -248,13 +301,13 @@ func TestRunCreateCommandTruncatedInput(t *testing.T) {
 	}
 }
 
-// TestRunCreateCommandValidationError verifies that invalid input (here, all
+// TestInteractiveCreateValidationError verifies that invalid input (here, all
 // three of goaldate/goalval/rate provided, violating the 2-of-3 rule) is
 // rejected before any API call and surfaces a non-zero exit code.
-func TestRunCreateCommandValidationError(t *testing.T) {
+func TestInteractiveCreateValidationError(t *testing.T) {
 	called := false
 	client := &FakeClient{
-		CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate string) (*Goal, error) {
+		CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate, runits string) (*Goal, error) {
 			called = true
 			return &Goal{Slug: slug}, nil
 		},
@@ -262,7 +315,7 @@ func TestRunCreateCommandValidationError(t *testing.T) {
 
 	stdin := strings.NewReader("reading\nDaily Reading\nhustler\npages\n1700000000\n365\n1\n")
 	var stdout, stderr bytes.Buffer
-	code := runCreateCommand(stdin, client, &stdout, &stderr)
+	code := doCreate(promptCreateRequest(bufio.NewReader(stdin), &stdout), client, &stdout, &stderr)
 
 	if code != 1 {
 		t.Fatalf("expected exit code 1, got %d", code)
@@ -280,24 +333,25 @@ func TestRunCreateCommandValidationError(t *testing.T) {
 // through to UpdateGoalDeadline (#332).
 func TestParseCreateArgsNonInteractive(t *testing.T) {
 	req, code, done := parseCreateArgs(
-		[]string{"--slug=reading", "--units=pages", "--goalval=365", "--rate=1", "--deadline=-3600"},
+		[]string{"--slug=reading", "--units=pages", "--goalval=365", "--rate=1", "--runits=daily", "--deadline=-3600"},
 		&bytes.Buffer{}, &bytes.Buffer{},
 	)
 	if done || code != 0 {
 		t.Fatalf("unexpected parse result: code=%d done=%v", code, done)
 	}
-	if req.slug != "reading" || req.gunits != "pages" || req.goalType != defaultGoalType {
+	if req.slug != "reading" || req.gunits != "pages" || req.goalType != defaultGoalType || req.runits != "d" {
 		t.Errorf("unexpected fields: %+v", req)
 	}
 	if !req.setDeadline || req.deadline != -3600 {
 		t.Errorf("deadline not captured: set=%v val=%d", req.setDeadline, req.deadline)
 	}
 
-	var gotTitle string
+	var gotTitle, gotRunits string
 	var gotDeadline int
 	client := &FakeClient{
-		CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate string) (*Goal, error) {
+		CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate, runits string) (*Goal, error) {
 			gotTitle = title
+			gotRunits = runits
 			return &Goal{Slug: slug}, nil
 		},
 		UpdateGoalDeadlineFunc: func(goalSlug string, deadline int) (*Goal, error) {
@@ -316,6 +370,9 @@ func TestParseCreateArgsNonInteractive(t *testing.T) {
 	if gotDeadline != -3600 {
 		t.Errorf("deadline not forwarded, got %d", gotDeadline)
 	}
+	if gotRunits != "d" {
+		t.Errorf("runits not forwarded, got %q", gotRunits)
+	}
 }
 
 // TestParseCreateArgsMidnightDeadline verifies that --deadline=0 (midnight) is
@@ -381,7 +438,7 @@ func TestParseCreateArgsTypeResolution(t *testing.T) {
 // the exit code is non-zero.
 func TestDoCreateDeadlineFailure(t *testing.T) {
 	client := &FakeClient{
-		CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate string) (*Goal, error) {
+		CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate, runits string) (*Goal, error) {
 			return &Goal{Slug: slug}, nil
 		},
 		UpdateGoalDeadlineFunc: func(goalSlug string, deadline int) (*Goal, error) {
@@ -404,7 +461,7 @@ func TestDoCreateDeadlineFailure(t *testing.T) {
 func TestDoCreateNoDeadlineSkipsUpdate(t *testing.T) {
 	deadlineCalled := false
 	client := &FakeClient{
-		CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate string) (*Goal, error) {
+		CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate, runits string) (*Goal, error) {
 			return &Goal{Slug: slug}, nil
 		},
 		UpdateGoalDeadlineFunc: func(goalSlug string, deadline int) (*Goal, error) {
@@ -448,18 +505,18 @@ func TestParseCreateArgsHelp(t *testing.T) {
 	}
 }
 
-// TestRunCreateCommandAPIError verifies that an error from CreateGoal is
+// TestInteractiveCreateAPIError verifies that an error from CreateGoal is
 // reported and produces a non-zero exit code.
-func TestRunCreateCommandAPIError(t *testing.T) {
+func TestInteractiveCreateAPIError(t *testing.T) {
 	client := &FakeClient{
-		CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate string) (*Goal, error) {
+		CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate, runits string) (*Goal, error) {
 			return nil, errors.New("boom")
 		},
 	}
 
 	stdin := strings.NewReader("reading\nDaily Reading\nhustler\npages\n\n365\n1\n")
 	var stdout, stderr bytes.Buffer
-	code := runCreateCommand(stdin, client, &stdout, &stderr)
+	code := doCreate(promptCreateRequest(bufio.NewReader(stdin), &stdout), client, &stdout, &stderr)
 
 	if code != 1 {
 		t.Fatalf("expected exit code 1, got %d", code)
@@ -468,3 +525,87 @@ func TestRunCreateCommandAPIError(t *testing.T) {
 		t.Errorf("expected API error on stderr, got: %s", stderr.String())
 	}
 }
+
+// TestOfferFirstDatapoint verifies the after-create prompts: a value is added
+// as the goal's first datapoint, and yes opens the goal's graph.
+func TestOfferFirstDatapoint(t *testing.T) {
+	var added []string
+	client := &FakeClient{
+		CreateDatapointWithDaystampFunc: func(slug, _, _, value, _, _ string) (*Datapoint, error) {
+			added = append(added, slug+" "+value)
+			return &Datapoint{}, nil
+		},
+	}
+	var opened []string
+	open := func(slug string) error { opened = append(opened, slug); return nil }
+
+	var stdout, stderr bytes.Buffer
+	code := offerFirstDatapoint(bufio.NewReader(strings.NewReader("0:30\ny\n")), client, "meditate", "hours", open, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	if strings.Join(added, ",") != "meditate 0.5" || strings.Join(opened, ",") != "meditate" {
+		t.Errorf("added %v, opened %v", added, opened)
+	}
+
+	// Blank input skips both.
+	added, opened = nil, nil
+	code = offerFirstDatapoint(bufio.NewReader(strings.NewReader("\n\n")), client, "meditate", "hours", open, &stdout, &stderr)
+	if code != 0 || len(added) != 0 || len(opened) != 0 {
+		t.Errorf("blank answers: code %d, added %v, opened %v", code, added, opened)
+	}
+
+	stderr.Reset()
+	code = offerFirstDatapoint(bufio.NewReader(strings.NewReader("lots\n")), client, "meditate", "hours", open, &stdout, &stderr)
+	if code != 1 || !strings.Contains(stderr.String(), "value must be a valid number") {
+		t.Errorf("bad value: code %d, stderr %s", code, stderr.String())
+	}
+}
+
+// TestParseCreateArgsSlugArgument verifies the slug can come first as an
+// argument, with flags after it and --gunits as an alias for --units.
+func TestParseCreateArgsSlugArgument(t *testing.T) {
+	req, code, done := parseCreateArgs(
+		[]string{"reading", "--title", "Read more", "--type", "hustler", "--gunits", "pages", "--rate", "1", "--goalval", "100", "--json"},
+		&bytes.Buffer{}, &bytes.Buffer{},
+	)
+	if done || code != 0 {
+		t.Fatalf("unexpected parse result: code=%d done=%v", code, done)
+	}
+	if req.slug != "reading" || req.title != "Read more" || req.gunits != "pages" || req.rate != "1" || req.goalval != "100" || !req.jsonOutput {
+		t.Errorf("unexpected fields: %+v", req)
+	}
+	if !req.flagsSet["units"] {
+		t.Error("--gunits should count as --units for templates")
+	}
+
+	var stderr bytes.Buffer
+	if _, code, done := parseCreateArgs([]string{"--from=goals.yaml", "--json"}, &bytes.Buffer{}, &stderr); !done || code != 1 {
+		t.Errorf("--json with --from: code=%d done=%v", code, done)
+	}
+}
+
+// TestDoCreateJSON verifies --json prints just the created goal, as it stands
+// after its deadline is set, with no progress messages.
+func TestDoCreateJSON(t *testing.T) {
+	client := &FakeClient{
+		CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate, runits string) (*Goal, error) {
+			return &Goal{Slug: slug, Title: title}, nil
+		},
+		UpdateGoalDeadlineFunc: func(goalSlug string, deadline int) (*Goal, error) {
+			return &Goal{Slug: goalSlug, Title: "Read more", Deadline: deadline}, nil
+		},
+	}
+	req := createRequest{slug: "reading", title: "Read more", gunits: "pages", goalType: "hustler", goalval: "100", rate: "1", deadline: -3600, setDeadline: true, jsonOutput: true}
+	var stdout, stderr bytes.Buffer
+	if code := doCreate(req, client, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0, got %d (stderr: %s)", code, stderr.String())
+	}
+	var got Goal
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("stdout isn't a goal: %v\n%s", err, stdout.String())
+	}
+	if got.Slug != "reading" || got.Deadline != -3600 {
+		t.Errorf("got %+v", got)
+	}
+}
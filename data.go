@@ -77,6 +77,29 @@ func runDataCommand(args []string, client Client, format string, stdout, stderr
 	}
 	goalSlug := positional[0]
 
+	// jsonl pages through the datapoints newest first, so --desc writes each
+	// one as soon as it arrives; oldest-first has to wait for the last page.
+	if format == "jsonl" {
+		var dps []Datapoint
+		write := jsonLines[Datapoint](stdout)
+		if !*desc {
+			write = func(dp Datapoint) error {
+				dps = append(dps, dp)
+				return nil
+			}
+		}
+		err := client.StreamDatapoints(context.Background(), goalSlug, write)
+		if err == nil && !*desc {
+			sortDatapointsByTime(dps, false)
+			err = writeJSONL(stdout, dps)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %s\n", redactError(err))
+			return 1
+		}
+		return 0
+	}
+
 	goal, err := client.FetchGoalWithDatapoints(context.Background(), goalSlug)
 	if err != nil {
 		fmt.Fprintf(stderr, "Error: %s\n", redactError(err))
@@ -86,12 +109,7 @@ func runDataCommand(args []string, client Client, format string, stdout, stderr
 	// The API's datapoint order isn't guaranteed; sort by timestamp so the
 	// output is deterministic. Default oldest-first; --desc flips to newest-first.
 	dps := append([]Datapoint(nil), goal.Datapoints...)
-	sort.SliceStable(dps, func(i, j int) bool {
-		if *desc {
-			return dps[i].Timestamp > dps[j].Timestamp
-		}
-		return dps[i].Timestamp < dps[j].Timestamp
-	})
+	sortDatapointsByTime(dps, *desc)
 
 	// Machine-readable formats emit valid output even when empty ([] / header
 	// row), so they run before the human "No datapoints" short-circuit.
@@ -121,6 +139,17 @@ func runDataCommand(args []string, client Client, format string, stdout, stderr
 	return 0
 }
 
+// sortDatapointsByTime sorts dps by timestamp, oldest first or, with desc,
+// newest first, keeping the order of datapoints with the same timestamp.
+func sortDatapointsByTime(dps []Datapoint, desc bool) {
+	sort.SliceStable(dps, func(i, j int) bool {
+		if desc {
+			return dps[i].Timestamp > dps[j].Timestamp
+		}
+		return dps[i].Timestamp < dps[j].Timestamp
+	})
+}
+
 // renderDatapointsAs renders datapoints as json (the raw datapoint objects) or
 // csv (date, value, comment — matching the human table's columns). The date and
 // value formatting mirror the text output so all three formats agree.
@@ -142,7 +171,7 @@ func renderDatapointsAs(format string, dps []Datapoint) (string, error) {
 		}
 		return encodeCSV([]string{"date", "value", "comment"}, rows)
 	default:
-		return "", fmt.Errorf("unknown format %q (want table, json, or csv)", format)
+		return "", fmt.Errorf("unknown format %q (want table, json, jsonl, or csv)", format)
 	}
 }
 
@@ -155,7 +184,10 @@ func formatDatapointRows(dps []Datapoint) (dates, values []string, maxValueLen i
 	values = make([]string, len(dps))
 	for i, dp := range dps {
 		dates[i] = datapointDate(dp)
-		values[i] = fmt.Sprintf("%.6g", dp.Value)
+		if day, err := time.Parse("2006-01-02", dates[i]); err == nil {
+			dates[i] = formatDate(day)
+		}
+		values[i] = formatValue(dp.Value)
 		if len(values[i]) > maxValueLen {
 			maxValueLen = len(values[i])
 		}
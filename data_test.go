@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 )
 
@@ -65,6 +66,46 @@ func TestRunDataCommand(t *testing.T) {
 		}
 	})
 
+	t.Run("jsonl format emits one datapoint object per line", func(t *testing.T) {
+		var out, errb bytes.Buffer
+		code := runDataCommand([]string{"g"}, &FakeClient{FetchGoalWithDatapointsFunc: twoPoints}, "jsonl", &out, &errb)
+		if code != 0 {
+			t.Fatalf("expected exit 0, got %d (stderr: %s)", code, errb.String())
+		}
+		lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("expected 2 lines, got %d:\n%s", len(lines), out.String())
+		}
+		for _, line := range lines {
+			var dp Datapoint
+			if err := json.Unmarshal([]byte(line), &dp); err != nil {
+				t.Errorf("line is not a JSON object: %v\n%s", err, line)
+			}
+		}
+	})
+
+	t.Run("jsonl format keeps the requested order", func(t *testing.T) {
+		for _, tt := range []struct {
+			args []string
+			want string
+		}{
+			{[]string{"g"}, "first later"},
+			{[]string{"--desc", "g"}, "later first"},
+		} {
+			var out, errb bytes.Buffer
+			code := runDataCommand(tt.args, &FakeClient{FetchGoalWithDatapointsFunc: twoPoints}, "jsonl", &out, &errb)
+			var comments []string
+			for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+				var dp Datapoint
+				_ = json.Unmarshal([]byte(line), &dp)
+				comments = append(comments, dp.Comment)
+			}
+			if got := strings.Join(comments, " "); code != 0 || got != tt.want {
+				t.Errorf("data %v --jsonl = %q (exit %d), want %q", tt.args, got, code, tt.want)
+			}
+		}
+	})
+
 	t.Run("json format emits [] for no datapoints", func(t *testing.T) {
 		empty := func(string) (*Goal, error) { return &Goal{}, nil }
 		var out, errb bytes.Buffer
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// defaultDatapointCount is how many datapoints `buzz datapoints` lists
+// without --count.
+const defaultDatapointCount = 10
+
+// handleDatapointsCommand lists a goal's most recent datapoints.
+func handleDatapointsCommand() {
+	client, ok := loadClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+	code := runDatapointsCommand(os.Args[2:], client, outputFormat, os.Stdout, os.Stderr)
+	if code == 0 && outputFormat == "table" {
+		fmt.Print(getUpdateMessage())
+	}
+	os.Exit(code)
+}
+
+// runDatapointsCommand is the testable core of `buzz datapoints`. It fetches
+// the goal's --count most recent datapoints from the datapoints endpoint and
+// prints them newest first as a table of daystamp, value, comment, and id, so
+// an id can be picked out for a follow-up edit. --json prints them as JSON,
+// like --format json.
+func runDatapointsCommand(args []string, client Client, format string, stdout, stderr io.Writer) int {
+	const usage = "Usage: buzz datapoints [--count N] [--json] <goalslug>"
+
+	fs := flag.NewFlagSet("datapoints", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {}
+	count := fs.Int("count", defaultDatapointCount, "How many datapoints to list")
+	jsonFlag := fs.Bool("json", false, "Output the datapoints as JSON")
+
+	// Flags may sit on either side of the slug, as with `buzz data`.
+	var positional []string
+	remaining := args
+	for len(remaining) > 0 {
+		if err := fs.Parse(remaining); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				fmt.Fprintln(stdout, usage)
+				return 0
+			}
+			fmt.Fprintf(stderr, "Error parsing flags: %s\n", redactError(err))
+			fmt.Fprintln(stderr, usage)
+			return 2
+		}
+		rest := fs.Args()
+		if len(rest) == 0 {
+			break
+		}
+		positional = append(positional, rest[0])
+		remaining = rest[1:]
+	}
+
+	if len(positional) != 1 {
+		if len(positional) == 0 {
+			fmt.Fprintln(stderr, "Error: Missing required argument")
+		} else {
+			fmt.Fprintf(stderr, "Error: Too many arguments: %v\n", positional[1:])
+		}
+		fmt.Fprintln(stderr, usage)
+		return 1
+	}
+	if *count < 1 {
+		fmt.Fprintln(stderr, "Error: --count must be at least 1")
+		return 1
+	}
+	if *jsonFlag {
+		format = "json"
+	}
+	goalSlug := positional[0]
+
+	dps, err := client.FetchDatapoints(context.Background(), goalSlug, *count)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %s\n", redactError(err))
+		return 1
+	}
+	// The endpoint may send more than asked for; keep the newest count.
+	dps = dps[:min(len(dps), *count)]
+
+	// Machine-readable formats run before the "No datapoints" short-circuit,
+	// so they emit valid output even when empty. csv carries the ids too.
+	if format == "jsonl" {
+		if err := writeJSONL(stdout, dps); err != nil {
+			fmt.Fprintf(stderr, "Error: %s\n", redactError(err))
+			return 1
+		}
+		return 0
+	}
+	if format != "table" {
+		rendered, err := renderDatapointsWithIDs(format, dps)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %s\n", redactError(err))
+			return 1
+		}
+		fmt.Fprint(stdout, rendered)
+		return 0
+	}
+
+	if len(dps) == 0 {
+		fmt.Fprintf(stdout, "No datapoints found for goal: %s\n", goalSlug)
+		return 0
+	}
+	fmt.Fprint(stdout, renderDatapointTable(dps))
+	return 0
+}
+
+// renderDatapointsWithIDs is renderDatapointsAs with an id column in csv.
+func renderDatapointsWithIDs(format string, dps []Datapoint) (string, error) {
+	if format != "csv" {
+		return renderDatapointsAs(format, dps)
+	}
+	rows := make([][]string, len(dps))
+	for i, dp := range dps {
+		rows[i] = []string{datapointDate(dp), fmt.Sprintf("%.6g", dp.Value), dp.Comment, dp.ID}
+	}
+	return encodeCSV([]string{"daystamp", "value", "comment", "id"}, rows)
+}
+
+// renderDatapointTable lays datapoints out in aligned columns under a header,
+// like `buzz list`.
+func renderDatapointTable(dps []Datapoint) string {
+	rows := [][]string{{"Daystamp", "Value", "Comment", "ID"}}
+	dates, values, _ := formatDatapointRows(dps)
+	for i, dp := range dps {
+		rows = append(rows, []string{dates[i], values[i], dp.Comment, dp.ID})
+	}
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for j, cell := range row {
+			widths[j] = max(widths[j], len(cell))
+		}
+	}
+
+	var b strings.Builder
+	for i, row := range rows {
+		b.WriteString(padRow(row, widths) + "\n")
+		if i == 0 {
+			rule := make([]string, len(widths))
+			for j, w := range widths {
+				rule[j] = strings.Repeat("-", w)
+			}
+			b.WriteString(padRow(rule, widths) + "\n")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestRunDatapointsCommand(t *testing.T) {
+	var asked []int
+	recent := func(slug string, count int) ([]Datapoint, error) {
+		asked = append(asked, count)
+		return []Datapoint{
+			{ID: "b2", Daystamp: "20240102", Value: 12.5, Comment: "later"},
+			{ID: "a1", Daystamp: "20240101", Value: 3},
+			{ID: "z0", Daystamp: "20231231", Value: 1},
+		}, nil
+	}
+	tests := []struct {
+		name             string
+		args             []string
+		format           string
+		fn               func(string, int) ([]Datapoint, error)
+		wantCode         int
+		wantOut, wantErr string
+	}{
+		{"missing arg", nil, "table", nil, 1, "", "Missing required argument"},
+		{"bad count", []string{"g", "--count", "0"}, "table", nil, 1, "", "--count must be at least 1"},
+		{"unknown flag", []string{"--nope", "g"}, "table", nil, 2, "", "Error parsing flags"},
+		{"api error", []string{"g"}, "table", func(string, int) ([]Datapoint, error) { return nil, errors.New("boom") }, 1, "", "boom"},
+		{"no datapoints", []string{"g"}, "table", func(string, int) ([]Datapoint, error) { return nil, nil }, 0, "No datapoints found for goal: g", ""},
+		{"table of the newest count", []string{"g", "--count", "2"}, "table", recent, 0,
+			"Daystamp    Value  Comment  ID\n" +
+				"----------  -----  -------  --\n" +
+				"2024-01-02  12.5   later    b2\n" +
+				"2024-01-01  3               a1\n", ""},
+		{"csv carries ids", []string{"--count", "1", "g"}, "csv", recent, 0, "daystamp,value,comment,id\n2024-01-02,12.5,later,b2\n", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errb bytes.Buffer
+			code := runDatapointsCommand(tt.args, &FakeClient{FetchDatapointsFunc: tt.fn}, tt.format, &out, &errb)
+			checkResult(t, code, out.String(), errb.String(), tt.wantCode, tt.wantOut, tt.wantErr)
+		})
+	}
+
+	t.Run("--json emits datapoint objects", func(t *testing.T) {
+		asked = nil
+		var out, errb bytes.Buffer
+		if code := runDatapointsCommand([]string{"g", "--json"}, &FakeClient{FetchDatapointsFunc: recent}, "table", &out, &errb); code != 0 {
+			t.Fatalf("expected exit 0, got %d (stderr: %s)", code, errb.String())
+		}
+		var dps []Datapoint
+		if err := json.Unmarshal(out.Bytes(), &dps); err != nil {
+			t.Fatalf("json output not valid: %v\n%s", err, out.String())
+		}
+		if len(dps) != 3 || dps[0].ID != "b2" {
+			t.Errorf("got %+v", dps)
+		}
+		if len(asked) != 1 || asked[0] != defaultDatapointCount {
+			t.Errorf("asked for %v datapoints, want the default %d", asked, defaultDatapointCount)
+		}
+	})
+}
@@ -198,17 +198,17 @@ func FormatAbsoluteDeadlineAt(losedate int64, now time.Time) string {
 
 	// If it's today, show time only (e.g., "3:04 PM")
 	if !t.Before(startOfToday) && t.Before(startOfTomorrow) {
-		return t.Format("3:04 PM")
+		return t.Format(clockLayout())
 	}
 
 	// If it's tomorrow, show "tomorrow" + time (e.g., "tomorrow 3:04 PM")
 	startOfDayAfterTomorrow := startOfTomorrow.AddDate(0, 0, 1)
 	if !t.Before(startOfTomorrow) && t.Before(startOfDayAfterTomorrow) {
-		return "tomorrow " + t.Format("3:04 PM")
+		return "tomorrow " + t.Format(clockLayout())
 	}
 
 	// For other dates, show date and time (e.g., "Jan 2 3:04 PM")
-	return t.Format("Jan 2 3:04 PM")
+	return t.Format("Jan 2 " + clockLayout())
 }
 
 // isDueTodayFilterAt returns true if the goal is due today (relative to now) and
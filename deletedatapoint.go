@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const deleteDatapointUsage = "Usage: buzz delete-datapoint [--yes|-y] <goalslug> <id>"
+
+// handleDeleteDatapointCommand deletes a datapoint.
+func handleDeleteDatapointCommand() {
+	client, ok := loadClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+	os.Exit(runDeleteDatapointCommand(os.Args[2:], os.Stdin, client, os.Stdout, os.Stderr))
+}
+
+// runDeleteDatapointCommand is the testable core of `buzz delete-datapoint`.
+// It asks for confirmation on stdin unless --yes is given, deletes the
+// datapoint, and signals a running TUI to refresh. The id comes from
+// `buzz datapoints`.
+func runDeleteDatapointCommand(args []string, stdin io.Reader, client Client, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("delete-datapoint", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {}
+	yes := fs.Bool("yes", false, "Skip confirmation prompt")
+	yesShort := fs.Bool("y", false, "Skip confirmation prompt (shorthand)")
+
+	// Flags may sit on either side of the slug and id, as with `buzz data`.
+	var positional []string
+	remaining := args
+	for len(remaining) > 0 {
+		if err := fs.Parse(remaining); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				fmt.Fprintln(stdout, deleteDatapointUsage)
+				return 0
+			}
+			fmt.Fprintf(stderr, "Error parsing flags: %s\n", redactError(err))
+			fmt.Fprintln(stderr, deleteDatapointUsage)
+			return 2
+		}
+		rest := fs.Args()
+		if len(rest) == 0 {
+			break
+		}
+		positional = append(positional, rest[0])
+		remaining = rest[1:]
+	}
+
+	if len(positional) != 2 {
+		if len(positional) < 2 {
+			fmt.Fprintln(stderr, "Error: Missing required arguments")
+		} else {
+			fmt.Fprintf(stderr, "Error: Too many arguments: %v\n", positional[2:])
+		}
+		fmt.Fprintln(stderr, deleteDatapointUsage)
+		return 1
+	}
+	goalSlug, id := positional[0], positional[1]
+
+	if !*yes && !*yesShort {
+		fmt.Fprintf(stdout, "Delete datapoint %s from %s? This cannot be undone. [y/N] ", id, goalSlug)
+		line, err := bufio.NewReader(stdin).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			fmt.Fprintln(stdout, "Cancelled.")
+			return 0
+		}
+		response := strings.TrimSpace(strings.ToLower(line))
+		if response != "y" && response != "yes" {
+			fmt.Fprintln(stdout, "Cancelled.")
+			return 0
+		}
+	}
+
+	dp, err := client.DeleteDatapoint(context.Background(), goalSlug, id)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to delete datapoint: %s\n", redactError(err))
+		return 1
+	}
+	fmt.Fprintf(stdout, "Deleted datapoint %s from %s: %s  %s  %q\n", dp.ID, goalSlug, datapointDate(*dp), formatValue(dp.Value), dp.Comment)
+
+	// Let a running TUI pick up the change, as `buzz add` does.
+	if err := createRefreshFlag(); err != nil {
+		fmt.Fprintf(stderr, "Warning: Could not create refresh flag: %s\n", redactError(err))
+	}
+	return 0
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRunDeleteDatapointCommand(t *testing.T) {
+	setHome(t, t.TempDir()) // contain createRefreshFlag's file write
+
+	tests := []struct {
+		name             string
+		args             []string
+		stdin            string
+		wantDeleted      bool
+		wantCode         int
+		wantOut, wantErr string
+	}{
+		{"missing id", []string{"g"}, "", false, 1, "", "Missing required arguments"},
+		{"declined", []string{"g", "abc"}, "n\n", false, 0, "Cancelled.", ""},
+		{"no answer", []string{"g", "abc"}, "", false, 0, "Cancelled.", ""},
+		{"confirmed", []string{"g", "abc"}, "y\n", true, 0, `Deleted datapoint abc from g: 2024-01-15  2  "oops"`, ""},
+		{"--yes skips the prompt", []string{"g", "--yes", "abc"}, "", true, 0, "Deleted datapoint abc", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deleted := false
+			client := &FakeClient{DeleteDatapointFunc: func(slug, id string) (*Datapoint, error) {
+				deleted = true
+				return &Datapoint{ID: id, Daystamp: "20240115", Value: 2, Comment: "oops"}, nil
+			}}
+			var out, errb bytes.Buffer
+			code := runDeleteDatapointCommand(tt.args, strings.NewReader(tt.stdin), client, &out, &errb)
+			checkResult(t, code, out.String(), errb.String(), tt.wantCode, tt.wantOut, tt.wantErr)
+			if deleted != tt.wantDeleted {
+				t.Errorf("deleted = %v, want %v", deleted, tt.wantDeleted)
+			}
+		})
+	}
+}
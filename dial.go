@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// `buzz dial` changes a goal's rate the way Beeminder's road editor does: the
+// bright red line stays as it is until the akrasia horizon, a week out, and
+// runs at the new rate from there to the goal's end date.
+
+const dialUsage = `Usage: buzz dial [--yes|-y] <goalslug> --rate <rate> [--runits <y|m|w|d|h>]
+  Change the goal's rate from the akrasia horizon, a week from today, on.
+  --runits defaults to the goal's own rate units.`
+
+// akrasiaHorizonDays is how far out Beeminder lets the bright red line change.
+const akrasiaHorizonDays = 7
+
+// dialRequest holds the parsed `buzz dial` arguments.
+type dialRequest struct {
+	goalSlug    string
+	rate        float64
+	runits      string // "" for the goal's own
+	skipConfirm bool
+}
+
+// handleDialCommand changes a goal's rate.
+func handleDialCommand() {
+	req, code, done := parseDialArgs(os.Args[2:], os.Stdout, os.Stderr)
+	if done {
+		os.Exit(code)
+	}
+
+	client, ok := loadClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+
+	code = runDialCommand(context.Background(), client, req, time.Now(), os.Stdin, os.Stdout, os.Stderr)
+	if code == 0 {
+		fmt.Print(getUpdateMessage())
+	}
+	os.Exit(code)
+}
+
+// parseDialArgs parses `buzz dial` arguments, returning the request, a process
+// exit code, and done=true when the caller should stop (help shown, or a
+// usage error). Flags may come before or after the goal.
+func parseDialArgs(args []string, stdout, stderr io.Writer) (dialRequest, int, bool) {
+	dialFlags := flag.NewFlagSet("dial", flag.ContinueOnError)
+	// Silence the flag package's own output; we print our own usage.
+	dialFlags.SetOutput(io.Discard)
+	rate := dialFlags.String("rate", "", "New rate")
+	runits := dialFlags.String("runits", "", "Units of the new rate: y, m, w, d, or h")
+	yes := dialFlags.Bool("yes", false, "Skip confirmation prompt")
+	yesShort := dialFlags.Bool("y", false, "Skip confirmation prompt (shorthand)")
+
+	// Re-parse after each positional, as graph does, so flags can follow the
+	// goal: `buzz dial reading --rate 2`.
+	var positional []string
+	for remaining := args; ; {
+		if err := dialFlags.Parse(remaining); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				fmt.Fprintln(stdout, dialUsage)
+				return dialRequest{}, 0, true
+			}
+			fmt.Fprintf(stderr, "Error parsing flags: %s\n", err)
+			fmt.Fprintln(stderr, dialUsage)
+			return dialRequest{}, 2, true
+		}
+		if dialFlags.NArg() == 0 {
+			break
+		}
+		positional = append(positional, dialFlags.Arg(0))
+		remaining = dialFlags.Args()[1:]
+	}
+	if len(positional) != 1 {
+		fmt.Fprintln(stderr, "Error: buzz dial takes one goal")
+		fmt.Fprintln(stderr, dialUsage)
+		return dialRequest{}, 1, true
+	}
+	if *rate == "" {
+		fmt.Fprintln(stderr, "Error: --rate is required")
+		fmt.Fprintln(stderr, dialUsage)
+		return dialRequest{}, 1, true
+	}
+	r, err := strconv.ParseFloat(*rate, 64)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: invalid --rate %q (want a number)\n", *rate)
+		return dialRequest{}, 1, true
+	}
+	if *runits != "" && !isKnownRunits(*runits) {
+		fmt.Fprintf(stderr, "Error: invalid --runits %q (want y, m, w, d, or h)\n", *runits)
+		return dialRequest{}, 1, true
+	}
+	return dialRequest{goalSlug: positional[0], rate: r, runits: *runits, skipConfirm: *yes || *yesShort}, 0, false
+}
+
+// akrasiaHorizon is the first moment the bright red line may change: the
+// start of the day a week from now.
+func akrasiaHorizon(now time.Time) time.Time {
+	return startOfDay(now, now.Location()).AddDate(0, 0, akrasiaHorizonDays)
+}
+
+// dialRoad returns roadall with the line unchanged up to horizon and running
+// at rate, in the goal's runits, from there to the goal's end. Rows past the
+// horizon are replaced, so any rate changes already scheduled there are
+// dropped.
+func dialRoad(roadall [][]*float64, runits string, rate float64, horizon time.Time) ([][]*float64, error) {
+	r, err := parseRoad(roadall, runits)
+	if err != nil {
+		return nil, err
+	}
+	if len(r) == 0 {
+		return nil, errors.New("the goal has no bright red line to dial")
+	}
+	end := *roadall[len(roadall)-1][0]
+	h := float64(horizon.Unix())
+	if end <= h {
+		return nil, fmt.Errorf("the goal ends before the akrasia horizon (%s)", horizon.Format("Mon Jan 2"))
+	}
+
+	dialed := [][]*float64{roadall[0]}
+	for _, row := range roadall[1:] {
+		if *row[0] < h {
+			dialed = append(dialed, row)
+		}
+	}
+	// Pin the line at the horizon so everything before it stays put.
+	v := r.valueAt(horizon)
+	dialed = append(dialed, []*float64{&h, &v, nil})
+	dialed = append(dialed, []*float64{&end, nil, &rate})
+	return dialed, nil
+}
+
+// scheduledPastHorizon lists the stretches of the goal's bright red line past
+// the horizon, as describeRoad does, when there's more than one: rate
+// changes and breaks already scheduled there, which dialing replaces. It's
+// nil when the line runs at one rate from the horizon on.
+func scheduledPastHorizon(goal Goal, horizon time.Time) []string {
+	r, err := parseRoad(goal.Roadall, goal.Runits)
+	if err != nil {
+		return nil
+	}
+	if lines := describeRoad(r, goal.Runits, goal.Gunits, horizon); len(lines) > 1 {
+		return lines
+	}
+	return nil
+}
+
+// runDialCommand fetches the goal, confirms the change on stdin unless
+// skipConfirm is set, and updates its bright red line. A dial that would
+// replace rate changes or breaks scheduled past the horizon lists them and
+// always asks. It returns the process exit code.
+func runDialCommand(ctx context.Context, client Client, req dialRequest, now time.Time, stdin io.Reader, out, errOut io.Writer) int {
+	goal, err := client.FetchGoal(ctx, req.goalSlug)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: Failed to fetch goal: %s\n", redactError(err))
+		return 1
+	}
+
+	// Rates in roadall are in the goal's runits, so convert one given in others.
+	runits, rate := goal.Runits, req.rate
+	if req.runits != "" && req.runits != goal.Runits {
+		if !isKnownRunits(goal.Runits) {
+			fmt.Fprintf(errOut, "Error: %s has unknown rate units %q\n", goal.Slug, goal.Runits)
+			return 1
+		}
+		runits = req.runits
+		rate = ratePerDay(req.rate, req.runits) / ratePerDay(1, goal.Runits)
+	}
+
+	horizon := akrasiaHorizon(now)
+	roadall, err := dialRoad(goal.Roadall, goal.Runits, rate, horizon)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: Can't dial %s: %s\n", goal.Slug, err)
+		return 1
+	}
+	newRate := formatRate(req.rate, runits, goal.Gunits)
+	effective := horizon.Format("Mon Jan 2")
+
+	// Dialing replaces the whole line past the horizon, so rate changes and
+	// breaks already scheduled there are listed, and --yes can't skip
+	// confirming their loss.
+	if replaced := scheduledPastHorizon(*goal, horizon); len(replaced) > 0 {
+		w, msg := out, "Dialing %s replaces the bright red line scheduled from %s on:\n"
+		if req.skipConfirm {
+			w, msg = errOut, "Error: Dialing %s replaces the bright red line scheduled from %s on, so run it without --yes to confirm:\n"
+		}
+		fmt.Fprintf(w, msg, goal.Slug, effective)
+		for _, line := range replaced {
+			fmt.Fprintf(w, "  %s\n", line)
+		}
+		if req.skipConfirm {
+			return 1
+		}
+	}
+
+	if !req.skipConfirm {
+		current := "its current rate"
+		if goal.Rate != nil {
+			current = formatRate(*goal.Rate, goal.Runits, goal.Gunits)
+		}
+		fmt.Fprintf(out, "Dial %s from %s to %s, starting %s (the akrasia horizon)? [y/N] ", goal.Slug, current, newRate, effective)
+		// A piped "y" without a newline still confirms.
+		line, err := bufio.NewReader(stdin).ReadString('\n')
+		answer := strings.ToLower(strings.TrimSpace(line))
+		if err != nil && !errors.Is(err, io.EOF) || answer != "y" && answer != "yes" {
+			fmt.Fprintln(out, "Cancelled.")
+			return 0
+		}
+	}
+
+	if _, err := client.UpdateGoalRoad(ctx, goal.Slug, roadall); err != nil {
+		fmt.Fprintf(errOut, "Error: Failed to dial goal: %s\n", redactError(err))
+		return 1
+	}
+	fmt.Fprintf(out, "Dialed %s to %s, effective %s.\n", goal.Slug, newRate, effective)
+	return 0
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDialArgs(t *testing.T) {
+	for _, tt := range []struct {
+		args     []string
+		want     dialRequest
+		wantCode int
+		wantDone bool
+	}{
+		{[]string{"reading", "--rate", "2", "--runits", "w"}, dialRequest{goalSlug: "reading", rate: 2, runits: "w"}, 0, false},
+		{[]string{"-y", "--rate=0.5", "reading"}, dialRequest{goalSlug: "reading", rate: 0.5, skipConfirm: true}, 0, false},
+		{[]string{"reading"}, dialRequest{}, 1, true},
+		{[]string{"--rate", "2"}, dialRequest{}, 1, true},
+		{[]string{"reading", "--rate", "lots"}, dialRequest{}, 1, true},
+		{[]string{"reading", "--rate", "2", "--runits", "fortnight"}, dialRequest{}, 1, true},
+		{[]string{"reading", "--nope"}, dialRequest{}, 2, true},
+		{[]string{"--help"}, dialRequest{}, 0, true},
+	} {
+		req, code, done := parseDialArgs(tt.args, &bytes.Buffer{}, &bytes.Buffer{})
+		if req != tt.want || code != tt.wantCode || done != tt.wantDone {
+			t.Errorf("parseDialArgs(%v) = %+v, %d, %v", tt.args, req, code, done)
+		}
+	}
+}
+
+func TestDialRoad(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	unix := func(d int) float64 { return float64(day(d).Unix()) }
+	// 1/day from Jan 1, then 2/day from Jan 10 to Jan 30.
+	roadall := [][]*float64{
+		roadallRow(unix(1), fptr(0), nil),
+		roadallRow(unix(10), nil, fptr(1)),
+		roadallRow(unix(30), nil, fptr(2)),
+	}
+
+	got, err := dialRoad(roadall, "d", 3, day(15))
+	if err != nil {
+		t.Fatalf("dialRoad: %v", err)
+	}
+	if len(got) != 4 {
+		t.Fatalf("dialRoad returned %d rows, want 4", len(got))
+	}
+	// The line is unchanged up to the horizon: 9 by Jan 10, then 10 more by Jan 15.
+	if *got[2][0] != unix(15) || *got[2][1] != 19 || got[2][2] != nil {
+		t.Errorf("horizon row = %v, %v, %v; want Jan 15, 19, nil", *got[2][0], *got[2][1], got[2][2])
+	}
+	if *got[3][0] != unix(30) || got[3][1] != nil || *got[3][2] != 3 {
+		t.Errorf("end row = %v, %v, %v; want Jan 30, nil, 3", *got[3][0], got[3][1], *got[3][2])
+	}
+	r, err := parseRoad(got, "d")
+	if err != nil {
+		t.Fatalf("dialed road doesn't parse: %v", err)
+	}
+	if v := r.valueAt(day(30)); v != 19+15*3 {
+		t.Errorf("dialed road ends at %v, want %v", v, 19+15*3)
+	}
+
+	if _, err := dialRoad(roadall, "d", 3, day(30)); err == nil || !strings.Contains(err.Error(), "ends before the akrasia horizon") {
+		t.Errorf("dialRoad past the end: err = %v", err)
+	}
+	if _, err := dialRoad(nil, "d", 3, day(15)); err == nil {
+		t.Error("dialRoad with no road: want an error")
+	}
+}
+
+func TestRunDialCommand(t *testing.T) {
+	now := time.Date(2024, 1, 8, 15, 0, 0, 0, time.Local)
+	start := float64(now.AddDate(0, 0, -30).Unix())
+	end := float64(now.AddDate(0, 0, 60).Unix())
+	var dialed [][]*float64
+	client := &FakeClient{
+		FetchGoalFunc: func(slug string) (*Goal, error) {
+			return &Goal{Slug: slug, Runits: "d", Gunits: "pages", Rate: fptr(1),
+				Roadall: [][]*float64{roadallRow(start, fptr(0), nil), roadallRow(end, nil, fptr(1))}}, nil
+		},
+		UpdateGoalRoadFunc: func(slug string, roadall [][]*float64) (*Goal, error) {
+			dialed = roadall
+			return &Goal{Slug: slug}, nil
+		},
+	}
+	run := func(req dialRequest, stdin string) (int, string, string) {
+		var out, errOut bytes.Buffer
+		code := runDialCommand(context.Background(), client, req, now, strings.NewReader(stdin), &out, &errOut)
+		return code, out.String(), errOut.String()
+	}
+
+	code, out, errOut := run(dialRequest{goalSlug: "reading", rate: 14, runits: "w"}, "y\n")
+	checkResult(t, code, out, errOut, 0, "", "")
+	if !strings.Contains(out, "Dial reading from 1 pages / day to 14 pages / week, starting Mon Jan 15 (the akrasia horizon)?") ||
+		!strings.HasSuffix(out, "Dialed reading to 14 pages / week, effective Mon Jan 15.\n") {
+		t.Errorf("confirmed dial: out %q", out)
+	}
+	if len(dialed) != 3 || *dialed[2][2] != 2 {
+		t.Errorf("14/week should be sent as 2/day, got %v rows", len(dialed))
+	}
+
+	dialed = nil
+	code, out, errOut = run(dialRequest{goalSlug: "reading", rate: 2}, "n\n")
+	checkResult(t, code, out, errOut, 0, "", "")
+	if !strings.HasSuffix(out, "Cancelled.\n") || dialed != nil {
+		t.Errorf("declined dial: out %q, dialed %v", out, dialed)
+	}
+
+	code, out, errOut = run(dialRequest{goalSlug: "reading", rate: 2, skipConfirm: true}, "")
+	checkResult(t, code, out, errOut, 0, "Dialed reading to 2 pages / day, effective Mon Jan 15.\n", "")
+
+	t.Run("scheduled break", func(t *testing.T) {
+		// A break from Jan 20 to Jan 25, past the horizon.
+		breakStart := float64(time.Date(2024, 1, 20, 0, 0, 0, 0, time.Local).Unix())
+		breakEnd := float64(time.Date(2024, 1, 25, 0, 0, 0, 0, time.Local).Unix())
+		client.FetchGoalFunc = func(slug string) (*Goal, error) {
+			return &Goal{Slug: slug, Runits: "d", Gunits: "pages", Rate: fptr(1),
+				Roadall: [][]*float64{
+					roadallRow(start, fptr(0), nil),
+					roadallRow(breakStart, nil, fptr(1)),
+					roadallRow(breakEnd, nil, fptr(0)),
+					roadallRow(end, nil, fptr(1)),
+				}}, nil
+		}
+
+		dialed = nil
+		code, out, errOut := run(dialRequest{goalSlug: "reading", rate: 2}, "y\n")
+		checkResult(t, code, out, errOut, 0, "Dialing reading replaces the bright red line scheduled from Mon Jan 15 on:\n", "")
+		if !strings.Contains(out, "2024-01-20 → 2024-01-25  0 pages / day") || dialed == nil {
+			t.Errorf("out %q, dialed %v", out, dialed)
+		}
+
+		dialed = nil
+		code, out, errOut = run(dialRequest{goalSlug: "reading", rate: 2, skipConfirm: true}, "")
+		checkResult(t, code, out, errOut, 1, "", "run it without --yes to confirm")
+		if !strings.Contains(errOut, "2024-01-20 → 2024-01-25  0 pages / day") || dialed != nil {
+			t.Errorf("err %q, dialed %v", errOut, dialed)
+		}
+	})
+}
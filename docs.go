@@ -0,0 +1,244 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// docsUsage documents `buzz docs`.
+const docsUsage = `Usage: buzz docs <man|markdown> [--dir <dir>]
+
+Generate reference documentation from buzz's command definitions:
+  man        buzz.1 plus one buzz-<command>.1 page per command
+  markdown   buzz.md plus one buzz-<command>.md page per command
+
+Flags:
+  --dir    Directory to write the files to (default: current directory)`
+
+// docPage is one generated documentation file.
+type docPage struct {
+	name    string // file name, e.g. "buzz-add.1"
+	content string
+}
+
+// handleDocsCommand writes man pages or markdown docs for every command.
+func handleDocsCommand() {
+	os.Exit(runDocsCommand(os.Args[2:], os.Stdout, os.Stderr))
+}
+
+// runDocsCommand is the testable core of `buzz docs`.
+func runDocsCommand(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("docs", flag.ContinueOnError)
+	fs.SetOutput(io.Discard) // we print our own usage
+	dir := fs.String("dir", ".", "Output directory")
+
+	// Accept --dir on either side of the format argument.
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				fmt.Fprintln(stdout, docsUsage)
+				return 0
+			}
+			fmt.Fprintf(stderr, "Error: %s\n", err)
+			fmt.Fprintln(stderr, docsUsage)
+			return 1
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	if len(positional) != 1 {
+		fmt.Fprintln(stderr, "Error: docs requires exactly one format (man or markdown)")
+		fmt.Fprintln(stderr, docsUsage)
+		return 1
+	}
+
+	var pages []docPage
+	switch positional[0] {
+	case "man":
+		pages = manPages(allCommands())
+	case "markdown", "md":
+		pages = markdownPages(allCommands())
+	default:
+		fmt.Fprintf(stderr, "Error: unknown docs format %q (want man or markdown)\n", positional[0])
+		return 1
+	}
+
+	if err := os.MkdirAll(*dir, 0755); err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to create %s: %s\n", *dir, err)
+		return 1
+	}
+	for _, page := range pages {
+		path := filepath.Join(*dir, page.name)
+		if err := os.WriteFile(path, []byte(page.content), 0644); err != nil {
+			fmt.Fprintf(stderr, "Error: Failed to write %s: %s\n", path, err)
+			return 1
+		}
+		fmt.Fprintln(stdout, path)
+	}
+	return 0
+}
+
+// commandSynopsis returns a command's invocation lines, falling back to the
+// bare command when it documents none.
+func commandSynopsis(cmd commandDef) []string {
+	if len(cmd.usage) == 0 {
+		return []string{"buzz " + cmd.name}
+	}
+	lines := make([]string, len(cmd.usage))
+	for i, u := range cmd.usage {
+		lines[i] = u.syntax
+	}
+	return lines
+}
+
+// manPages renders buzz(1) and one buzz-<command>(1) page per command.
+func manPages(cmds []commandDef) []docPage {
+	var b strings.Builder
+	writeManHeader(&b, "buzz")
+	b.WriteString(".SH NAME\nbuzz \\- A terminal user interface for Beeminder\n")
+	b.WriteString(".SH SYNOPSIS\n.nf\nbuzz [options]\nbuzz [options] <command> [args]\n.fi\n")
+	b.WriteString(".SH DESCRIPTION\nRun without arguments to launch the interactive TUI, " +
+		"or with a command for one-shot, scriptable output.\n")
+	b.WriteString(".SH COMMANDS\n")
+	for _, cmd := range cmds {
+		fmt.Fprintf(&b, ".TP\n.B %s\n%s (see \\fBbuzz-%s\\fR(1))\n", roffEscape(cmd.name), roffEscape(cmd.summary), roffEscape(cmd.name))
+	}
+	b.WriteString(".SH OPTIONS\n")
+	for _, opt := range globalOptions {
+		fmt.Fprintf(&b, ".TP\n.B %s\n%s\n", roffEscape(opt.syntax), roffEscape(opt.desc))
+	}
+	b.WriteString(".SH FILES\n.TP\n.I ~/.buzzrc\nBeeminder credentials and settings\n")
+	b.WriteString(".SH SEE ALSO\nhttps://buzz.nathanarthur.com\n")
+
+	pages := []docPage{{name: "buzz.1", content: b.String()}}
+	for _, cmd := range cmds {
+		pages = append(pages, docPage{name: "buzz-" + cmd.name + ".1", content: manPage(cmd)})
+	}
+	return pages
+}
+
+// manPage renders the buzz-<command>(1) page for cmd.
+func manPage(cmd commandDef) string {
+	var b strings.Builder
+	writeManHeader(&b, "buzz-"+cmd.name)
+	fmt.Fprintf(&b, ".SH NAME\nbuzz\\-%s \\- %s\n", roffEscape(cmd.name), roffEscape(cmd.summary))
+	b.WriteString(".SH SYNOPSIS\n.nf\n")
+	for _, line := range commandSynopsis(cmd) {
+		b.WriteString(roffEscape(line) + "\n")
+	}
+	b.WriteString(".fi\n")
+	if len(cmd.usage) > 0 {
+		b.WriteString(".SH DESCRIPTION\n")
+		for _, u := range cmd.usage {
+			fmt.Fprintf(&b, ".TP\n.B %s\n%s\n", roffEscape(u.syntax), roffEscape(u.desc))
+		}
+	}
+	if len(cmd.flags) > 0 {
+		b.WriteString(".SH OPTIONS\n")
+		for _, f := range cmd.flags {
+			fmt.Fprintf(&b, ".TP\n.B %s\n%s\n", roffEscape(f.syntax), roffEscape(f.desc))
+		}
+	}
+	if len(cmd.examples) > 0 {
+		b.WriteString(".SH EXAMPLES\n.nf\n")
+		for _, ex := range cmd.examples {
+			b.WriteString(roffEscape(ex) + "\n")
+		}
+		b.WriteString(".fi\n")
+	}
+	b.WriteString(".SH EXIT STATUS\n")
+	for _, code := range commandExitCodes(cmd) {
+		fmt.Fprintf(&b, ".TP\n.B %s\n%s\n", roffEscape(code.syntax), roffEscape(code.desc))
+	}
+	b.WriteString(".SH SEE ALSO\n\\fBbuzz\\fR(1)\n")
+	return b.String()
+}
+
+// writeManHeader writes the .TH title line shared by every page.
+func writeManHeader(b *strings.Builder, title string) {
+	fmt.Fprintf(b, ".TH %s 1 \"\" \"buzz %s\" \"buzz manual\"\n", strings.ToUpper(roffEscape(title)), roffEscape(version))
+}
+
+// roffEscape escapes text for use in a man page: backslashes and hyphens are
+// escaped, and a leading control character is neutralized so a line starting
+// with "." or "'" isn't read as a request.
+func roffEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	s = strings.ReplaceAll(s, "-", `\-`)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
+
+// markdownPages renders buzz.md (a command index) and one buzz-<command>.md
+// page per command.
+func markdownPages(cmds []commandDef) []docPage {
+	var b strings.Builder
+	b.WriteString("# buzz\n\nA terminal user interface for Beeminder. Run `buzz` with no arguments to " +
+		"launch the interactive TUI, or with a command for one-shot, scriptable output.\n\n")
+	b.WriteString("## Commands\n\n| Command | Description |\n| --- | --- |\n")
+	for _, cmd := range cmds {
+		fmt.Fprintf(&b, "| [`buzz %s`](buzz-%s.md) | %s |\n", cmd.name, cmd.name, markdownCell(cmd.summary))
+	}
+	b.WriteString("\n## Global options\n\n| Option | Description |\n| --- | --- |\n")
+	for _, opt := range globalOptions {
+		fmt.Fprintf(&b, "| `%s` | %s |\n", markdownCell(opt.syntax), markdownCell(opt.desc))
+	}
+
+	pages := []docPage{{name: "buzz.md", content: b.String()}}
+	for _, cmd := range cmds {
+		pages = append(pages, docPage{name: "buzz-" + cmd.name + ".md", content: markdownPage(cmd)})
+	}
+	return pages
+}
+
+// markdownPage renders the buzz-<command>.md page for cmd.
+func markdownPage(cmd commandDef) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# buzz %s\n\n%s\n\n## Usage\n\n```\n", cmd.name, cmd.summary)
+	for _, line := range commandSynopsis(cmd) {
+		b.WriteString(line + "\n")
+	}
+	b.WriteString("```\n")
+	if len(cmd.usage) > 0 {
+		b.WriteString("\n")
+		for _, u := range cmd.usage {
+			fmt.Fprintf(&b, "- `%s` — %s\n", u.syntax, u.desc)
+		}
+	}
+	if len(cmd.flags) > 0 {
+		b.WriteString("\n## Flags\n\n| Flag | Description |\n| --- | --- |\n")
+		for _, f := range cmd.flags {
+			fmt.Fprintf(&b, "| `%s` | %s |\n", markdownCell(f.syntax), markdownCell(f.desc))
+		}
+	}
+	if len(cmd.examples) > 0 {
+		b.WriteString("\n## Examples\n\n```bash\n")
+		for _, ex := range cmd.examples {
+			b.WriteString(ex + "\n")
+		}
+		b.WriteString("```\n")
+	}
+	b.WriteString("\n## Exit codes\n\n| Code | Meaning |\n| --- | --- |\n")
+	for _, code := range commandExitCodes(cmd) {
+		fmt.Fprintf(&b, "| %s | %s |\n", code.syntax, markdownCell(code.desc))
+	}
+	b.WriteString("\nSee also: [buzz](buzz.md)\n")
+	return b.String()
+}
+
+// markdownCell escapes pipes so text can sit inside a markdown table cell.
+func markdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
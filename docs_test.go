@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunDocsCommand(t *testing.T) {
+	t.Run("man writes one page per command plus buzz.1", func(t *testing.T) {
+		dir := t.TempDir()
+		var out, errOut bytes.Buffer
+		code := runDocsCommand([]string{"man", "--dir", dir}, &out, &errOut)
+		checkResult(t, code, out.String(), errOut.String(), 0, "buzz-add.1", "")
+
+		page, err := os.ReadFile(filepath.Join(dir, "buzz-add.1"))
+		if err != nil {
+			t.Fatalf("reading buzz-add.1: %v", err)
+		}
+		for _, want := range []string{".TH BUZZ\\-ADD 1", ".SH NAME\nbuzz\\-add \\- Add a datapoint to a goal", ".SH SYNOPSIS"} {
+			if !strings.Contains(string(page), want) {
+				t.Errorf("buzz-add.1 missing %q:\n%s", want, page)
+			}
+		}
+		if _, err := os.Stat(filepath.Join(dir, "buzz.1")); err != nil {
+			t.Errorf("buzz.1 not written: %v", err)
+		}
+	})
+
+	t.Run("markdown with --dir before the format", func(t *testing.T) {
+		dir := t.TempDir()
+		var out, errOut bytes.Buffer
+		code := runDocsCommand([]string{"--dir", dir, "markdown"}, &out, &errOut)
+		checkResult(t, code, out.String(), errOut.String(), 0, "buzz.md", "")
+
+		index, err := os.ReadFile(filepath.Join(dir, "buzz.md"))
+		if err != nil {
+			t.Fatalf("reading buzz.md: %v", err)
+		}
+		if !strings.Contains(string(index), "[`buzz add`](buzz-add.md)") {
+			t.Errorf("index missing link to buzz-add.md:\n%s", index)
+		}
+	})
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"no format", nil, "exactly one format"},
+		{"unknown format", []string{"pdf"}, `unknown docs format "pdf"`},
+		{"extra argument", []string{"man", "extra"}, "exactly one format"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			code := runDocsCommand(tt.args, &out, &errOut)
+			checkResult(t, code, out.String(), errOut.String(), 1, "", tt.wantErr)
+		})
+	}
+}
+
+func TestRoffEscape(t *testing.T) {
+	tests := map[string]string{
+		"--yes":       `\-\-yes`,
+		".hidden":     `\&.hidden`,
+		`back\slash`:  `back\eslash`,
+		"plain words": "plain words",
+	}
+	for in, want := range tests {
+		if got := roffEscape(in); got != want {
+			t.Errorf("roffEscape(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// checkStatus is how a doctor check came out.
+type checkStatus int
+
+const (
+	checkOK checkStatus = iota
+	checkWarn
+	checkFail
+)
+
+// doctorCheck is one line of `buzz doctor`'s report.
+type doctorCheck struct {
+	name   string
+	status checkStatus
+	detail string
+}
+
+// handleDoctorCommand checks that buzz can work on this machine.
+func handleDoctorCommand() {
+	os.Exit(runDoctorCommand(os.Args[2:], platformChecks(exec.LookPath), os.Stdout, os.Stderr))
+}
+
+// runDoctorCommand is the testable core of `buzz doctor`: it prints each
+// check and fails when any check did.
+func runDoctorCommand(args []string, checks []doctorCheck, stdout, stderr io.Writer) int {
+	if len(args) > 0 {
+		if args[0] == "-h" || args[0] == "--help" {
+			fmt.Fprintln(stdout, "Usage: buzz doctor")
+			return 0
+		}
+		fmt.Fprintf(stderr, "Error: Unexpected arguments: %v\n", args)
+		fmt.Fprintln(stderr, "Usage: buzz doctor")
+		return 1
+	}
+
+	code := 0
+	for _, c := range checks {
+		mark := "ok  "
+		switch c.status {
+		case checkWarn:
+			mark = "warn"
+		case checkFail:
+			mark = "FAIL"
+			code = 1
+		}
+		fmt.Fprintf(stdout, "[%s] %-10s %s\n", mark, c.name, c.detail)
+	}
+	return code
+}
+
+// platformChecks checks what buzz relies on from the platform: a terminal
+// that understands its escape codes, a home directory to keep its config and
+// the TUI's refresh flag in, a way to open the browser, and a clock that
+// agrees with Beeminder's; then whether it can read every goal. lookPath
+// finds programs (exec.LookPath).
+func platformChecks(lookPath func(string) (string, error)) []doctorCheck {
+	var config *Config
+	var client Client
+	if ConfigExists() {
+		config, _ = LoadConfig() // a broken config is checkConfig's to report
+	}
+	if config != nil {
+		client = NewHTTPClient(config)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	checks := []doctorCheck{
+		{"platform", checkOK, fmt.Sprintf("buzz %s on %s/%s", version, runtime.GOOS, runtime.GOARCH)},
+		checkTerminal(),
+		checkConfig(),
+		checkRefreshFlag(),
+		checkBrowser(config, lookPath),
+		checkClock(ctx, client),
+	}
+	return append(checks, checkGoals(ctx, client)...)
+}
+
+// checkTerminal reports whether stdout is a terminal and which colors it gets.
+// A Windows console that still can't take ANSI escape codes after
+// enableTerminalANSI is reported as colorless.
+func checkTerminal() doctorCheck {
+	if fi, err := os.Stdout.Stat(); err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+		return doctorCheck{"terminal", checkOK, "stdout is not a terminal (output is plain)"}
+	}
+	switch lipgloss.ColorProfile() {
+	case termenv.TrueColor:
+		return doctorCheck{"terminal", checkOK, "true color"}
+	case termenv.ANSI256:
+		return doctorCheck{"terminal", checkOK, "256 colors"}
+	case termenv.ANSI:
+		return doctorCheck{"terminal", checkOK, "16 colors"}
+	}
+	detail := "no colors (set TERM, or unset NO_COLOR, for color)"
+	if runtime.GOOS == "windows" {
+		detail = "no colors; this console doesn't understand ANSI escape codes (try Windows Terminal)"
+	}
+	return doctorCheck{"terminal", checkWarn, detail}
+}
+
+// checkConfig reports where the config is and whether it loads.
+func checkConfig() doctorCheck {
+	path, err := getConfigPath()
+	if err != nil {
+		return doctorCheck{"config", checkFail, fmt.Sprintf("no home directory: %s", err)}
+	}
+	if !ConfigExists() {
+		return doctorCheck{"config", checkWarn, fmt.Sprintf("%s not found; run 'buzz auth login'", path)}
+	}
+	config, err := LoadConfig()
+	if err != nil {
+		return doctorCheck{"config", checkFail, fmt.Sprintf("%s can't be read: %s", path, redactError(err))}
+	}
+	if len(config.problems) > 0 {
+		msgs := make([]string, len(config.problems))
+		for i, p := range config.problems {
+			msgs[i] = p.String()
+		}
+		return doctorCheck{"config", checkWarn, fmt.Sprintf("%s: %s", path, strings.Join(msgs, "; "))}
+	}
+	return doctorCheck{"config", checkOK, path}
+}
+
+// checkRefreshFlag reports whether commands can leave the refresh flag that
+// tells a running TUI to reload, by writing a scratch file next to it.
+func checkRefreshFlag() doctorCheck {
+	path, err := getRefreshFlagPath()
+	if err != nil {
+		return doctorCheck{"refresh", checkFail, fmt.Sprintf("no home directory: %s", err)}
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), ".buzz-doctor-*")
+	if err != nil {
+		return doctorCheck{"refresh", checkFail, fmt.Sprintf("can't write next to %s: %s", path, err)}
+	}
+	f.Close()
+	os.Remove(f.Name())
+	return doctorCheck{"refresh", checkOK, path}
+}
+
+// checkBrowser reports which program opens goal pages (see browserCommand)
+// and whether it's there. config may be nil.
+func checkBrowser(config *Config, lookPath func(string) (string, error)) doctorCheck {
+	argv, _, ok := browserCommand(config, "", lookPath)
+	if !ok || len(argv) == 0 {
+		return doctorCheck{"browser", checkWarn, fmt.Sprintf("buzz can't open a browser on %s; set open_command", runtime.GOOS)}
+	}
+	if _, err := lookPath(argv[0]); err != nil {
+		return doctorCheck{"browser", checkWarn, fmt.Sprintf("%s not found; goal pages can't be opened", argv[0])}
+	}
+	return doctorCheck{"browser", checkOK, "opens with " + argv[0]}
+}
+
+// checkGoals fetches the goals and reports, one line per goal, what buzz
+// couldn't make sense of in each (see goalWarnings). client may be nil.
+func checkGoals(ctx context.Context, client Client) []doctorCheck {
+	if client == nil {
+		return []doctorCheck{{"goals", checkWarn, "not checked; run 'buzz auth login' to read your goals"}}
+	}
+	goals, err := client.FetchGoals(ctx)
+	if err != nil {
+		return []doctorCheck{{"goals", checkWarn, fmt.Sprintf("not checked; can't fetch goals: %s", redactError(err))}}
+	}
+	var checks []doctorCheck
+	for _, g := range goals {
+		if warnings := goalWarnings(g); len(warnings) > 0 {
+			checks = append(checks, doctorCheck{"goals", checkWarn, fmt.Sprintf("%s: %s", g.Slug, strings.Join(warnings, "; "))})
+		}
+	}
+	if len(checks) == 0 {
+		return []doctorCheck{{"goals", checkOK, fmt.Sprintf("%d goals read cleanly", len(goals))}}
+	}
+	return checks
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunDoctorCommand(t *testing.T) {
+	checks := []doctorCheck{
+		{"platform", checkOK, "buzz dev on linux/amd64"},
+		{"browser", checkWarn, "xdg-open not found"},
+	}
+	var out, errb bytes.Buffer
+	code := runDoctorCommand(nil, checks, &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 0, "[ok  ] platform   buzz dev on linux/amd64\n[warn] browser    xdg-open not found\n", "")
+
+	out.Reset()
+	code = runDoctorCommand(nil, append(checks, doctorCheck{"config", checkFail, "unreadable"}), &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 1, "[FAIL] config", "")
+
+	code = runDoctorCommand([]string{"extra"}, checks, &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 1, "", "Unexpected arguments")
+}
+
+func TestDoctorChecks(t *testing.T) {
+	home := t.TempDir()
+	setHome(t, home)
+
+	if c := checkConfig(); c.status != checkWarn || !strings.Contains(c.detail, "buzz auth login") {
+		t.Errorf("missing config: %+v", c)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".buzzrc"), []byte("{not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if c := checkConfig(); c.status != checkFail {
+		t.Errorf("broken config: %+v", c)
+	}
+
+	if c := checkRefreshFlag(); c.status != checkOK {
+		t.Errorf("writable home: %+v", c)
+	}
+	if entries, _ := os.ReadDir(home); len(entries) != 1 {
+		t.Errorf("the refresh check left files behind: %v", entries)
+	}
+
+	missing := func(string) (string, error) { return "", errors.New("not found") }
+	t.Setenv("BROWSER", "")
+	if c := checkBrowser(&Config{OpenCommand: "lynx {url}"}, missing); c.status != checkWarn || !strings.Contains(c.detail, "lynx not found") {
+		t.Errorf("missing lynx: %+v", c)
+	}
+}
+
+func TestCheckGoals(t *testing.T) {
+	ctx := context.Background()
+	if c := checkGoals(ctx, nil); len(c) != 1 || c[0].status != checkWarn || !strings.Contains(c[0].detail, "buzz auth login") {
+		t.Errorf("logged out: %+v", c)
+	}
+
+	failing := &FakeClient{FetchGoalsFunc: func() ([]Goal, error) { return nil, errors.New("offline") }}
+	if c := checkGoals(ctx, failing); len(c) != 1 || c[0].status != checkWarn || !strings.Contains(c[0].detail, "offline") {
+		t.Errorf("fetch error: %+v", c)
+	}
+
+	goals := []Goal{{Slug: "fine"}, {Slug: "odd", warnings: []string{"safebuf was null"}}}
+	client := &FakeClient{FetchGoalsFunc: func() ([]Goal, error) { return goals, nil }}
+	c := checkGoals(ctx, client)
+	if len(c) != 1 || c[0].status != checkWarn || c[0].detail != "odd: safebuf was null" {
+		t.Errorf("goal warnings: %+v", c)
+	}
+
+	goals = goals[:1]
+	if c := checkGoals(ctx, client); len(c) != 1 || c[0].status != checkOK {
+		t.Errorf("clean goals: %+v", c)
+	}
+}
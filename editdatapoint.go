@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const editDatapointUsage = "Usage: buzz edit-datapoint <goalslug> <id> [--value V] [--comment C] [--date YYYY-MM-DD|--daystamp YYYYMMDD]"
+
+// handleEditDatapointCommand changes an existing datapoint.
+func handleEditDatapointCommand() {
+	client, ok := loadClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+	os.Exit(runEditDatapointCommand(os.Args[2:], client, os.Stdout, os.Stderr))
+}
+
+// runEditDatapointCommand is the testable core of `buzz edit-datapoint`. It
+// updates only what's given: --value (a number, time, or expression, as for
+// `buzz add`), --comment (which may be "" to clear it), and --date or
+// --daystamp, which moves the datapoint to that Beeminder day. The id comes
+// from `buzz datapoints`.
+func runEditDatapointCommand(args []string, client Client, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("edit-datapoint", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {}
+	valueFlag := fs.String("value", "", "New value")
+	commentFlag := fs.String("comment", "", "New comment")
+	dateFlag := fs.String("date", "", "New date (YYYY-MM-DD or YYYYMMDD)")
+	daystampFlag := fs.String("daystamp", "", "New date (YYYYMMDD)")
+
+	// Flags may sit on either side of the slug and id, as with `buzz data`.
+	var positional []string
+	remaining := args
+	for len(remaining) > 0 {
+		if err := fs.Parse(remaining); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				fmt.Fprintln(stdout, editDatapointUsage)
+				return 0
+			}
+			fmt.Fprintf(stderr, "Error parsing flags: %s\n", redactError(err))
+			fmt.Fprintln(stderr, editDatapointUsage)
+			return 2
+		}
+		rest := fs.Args()
+		if len(rest) == 0 {
+			break
+		}
+		positional = append(positional, rest[0])
+		remaining = rest[1:]
+	}
+
+	if len(positional) != 2 {
+		if len(positional) < 2 {
+			fmt.Fprintln(stderr, "Error: Missing required arguments")
+		} else {
+			fmt.Fprintf(stderr, "Error: Too many arguments: %v\n", positional[2:])
+		}
+		fmt.Fprintln(stderr, editDatapointUsage)
+		return 1
+	}
+	goalSlug, id := positional[0], positional[1]
+
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	if len(set) == 0 {
+		fmt.Fprintln(stderr, "Error: Nothing to change; give --value, --comment, or --date")
+		fmt.Fprintln(stderr, editDatapointUsage)
+		return 1
+	}
+
+	var value, comment, timestamp *string
+	if set["value"] {
+		v, err := parseAddValue(*valueFlag, "")
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %s\n", err)
+			return 1
+		}
+		value = &v
+	}
+	if set["comment"] {
+		comment = commentFlag
+	}
+	if set["date"] && set["daystamp"] {
+		fmt.Fprintln(stderr, "Error: Give --date or --daystamp, not both")
+		return 1
+	}
+	if set["date"] || set["daystamp"] {
+		date := *dateFlag
+		if set["daystamp"] {
+			date = *daystampFlag
+		}
+		given := date
+		if len(date) == 8 && !strings.Contains(date, "-") {
+			date = date[:4] + "-" + date[4:6] + "-" + date[6:]
+		}
+		if msg := validateDatapointDate(date); msg != "" {
+			fmt.Fprintf(stderr, "Error: %s: %s\n", msg, given)
+			return 1
+		}
+		// The API takes a timestamp here, not a daystamp, so aim for the
+		// middle of that Beeminder day, which depends on the goal's deadline.
+		goal, err := client.FetchGoal(context.Background(), goalSlug)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: Failed to fetch goal: %s\n", redactError(err))
+			return 1
+		}
+		day, _ := time.ParseInLocation("2006-01-02", date, time.Local)
+		ts := strconv.FormatInt(dayTimestamp(day, goal.Deadline), 10)
+		timestamp = &ts
+	}
+
+	dp, err := client.UpdateDatapoint(context.Background(), goalSlug, id, value, comment, timestamp)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to update datapoint: %s\n", redactError(err))
+		return 1
+	}
+	fmt.Fprintf(stdout, "Updated datapoint %s on %s: %s  %s  %q\n", dp.ID, goalSlug, datapointDate(*dp), formatValue(dp.Value), dp.Comment)
+
+	// Let a running TUI pick up the change, as `buzz add` does.
+	if err := createRefreshFlag(); err != nil {
+		fmt.Fprintf(stderr, "Warning: Could not create refresh flag: %s\n", redactError(err))
+	}
+	return 0
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestRunEditDatapointCommand(t *testing.T) {
+	setHome(t, t.TempDir()) // contain createRefreshFlag's file write
+
+	deref := func(s *string) string {
+		if s == nil {
+			return "<unset>"
+		}
+		return *s
+	}
+	var got [3]string
+	updated := func(slug, id string, value, comment, timestamp *string) (*Datapoint, error) {
+		got = [3]string{deref(value), deref(comment), deref(timestamp)}
+		return &Datapoint{ID: id, Daystamp: "20240115", Value: 3.5, Comment: "fixed"}, nil
+	}
+	jan15 := strconv.FormatInt(time.Date(2024, 1, 15, 12, 0, 0, 0, time.Local).Unix(), 10)
+	// With a 3am deadline, Jan 15's Beeminder day runs 3am Jan 15 to 3am Jan 16.
+	jan15Late := strconv.FormatInt(time.Date(2024, 1, 15, 15, 0, 0, 0, time.Local).Unix(), 10)
+	goal := func(slug string) (*Goal, error) {
+		deadline := 0
+		if slug == "late" {
+			deadline = 3 * 3600
+		}
+		return &Goal{Slug: slug, Deadline: deadline}, nil
+	}
+
+	tests := []struct {
+		name             string
+		args             []string
+		fn               func(string, string, *string, *string, *string) (*Datapoint, error)
+		wantCode         int
+		want             [3]string
+		wantOut, wantErr string
+	}{
+		{"missing id", []string{"g"}, nil, 1, [3]string{}, "", "Missing required arguments"},
+		{"nothing to change", []string{"g", "abc"}, nil, 1, [3]string{}, "", "Nothing to change"},
+		{"bad value", []string{"g", "abc", "--value", "lots"}, nil, 1, [3]string{}, "", "value must be a valid number"},
+		{"bad date", []string{"g", "abc", "--date", "15/01/2024"}, nil, 1, [3]string{}, "", "Invalid date format"},
+		{"api error", []string{"g", "abc", "--value", "1"}, func(string, string, *string, *string, *string) (*Datapoint, error) {
+			return nil, errors.New("datapoint abc not found on goal g")
+		}, 1, [3]string{}, "", "not found"},
+		{"value only", []string{"g", "abc", "--value", "3*2"}, updated, 0, [3]string{"6", "<unset>", "<unset>"},
+			`Updated datapoint abc on g: 2024-01-15  3.5  "fixed"`, ""},
+		{"clear comment and move", []string{"--comment", "", "g", "abc", "--date", "20240115"}, updated, 0, [3]string{"<unset>", "", jan15}, "Updated datapoint abc", ""},
+		{"daystamp past a late deadline", []string{"late", "abc", "--daystamp", "20240115"}, updated, 0, [3]string{"<unset>", "<unset>", jan15Late}, "Updated datapoint abc", ""},
+		{"date and daystamp", []string{"g", "abc", "--date", "2024-01-15", "--daystamp", "20240115"}, nil, 1, [3]string{}, "", "not both"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = [3]string{}
+			var out, errb bytes.Buffer
+			code := runEditDatapointCommand(tt.args, &FakeClient{UpdateDatapointFunc: tt.fn, FetchGoalFunc: goal}, &out, &errb)
+			checkResult(t, code, out.String(), errb.String(), tt.wantCode, tt.wantOut, tt.wantErr)
+			if got != tt.want {
+				t.Errorf("sent value, comment, timestamp = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
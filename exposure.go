@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+const exposureUsage = `Usage: buzz exposure [duration]
+  Sum the pledges of the goals that would derail if you did nothing for
+  duration (default 48h; e.g. 12h, 3d, 1w).`
+
+// defaultExposureWindow is the window `buzz exposure` uses without a duration.
+const defaultExposureWindow = "48h"
+
+// handleExposureCommand totals what's at stake on goals due within a window.
+func handleExposureCommand() {
+	window, label, code, done := parseExposureArgs(os.Args[2:], os.Stdout, os.Stderr)
+	if done {
+		os.Exit(code)
+	}
+
+	client, ok := loadClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+
+	code = runExposureCommand(context.Background(), client, window, label, goalFilter, outputFormat, time.Now(), os.Stdout, os.Stderr)
+	if code == 0 && outputFormat == "table" {
+		fmt.Print(getUpdateMessage())
+	}
+	os.Exit(code)
+}
+
+// parseExposureArgs parses `buzz exposure` arguments, returning the window and
+// how it was written, a process exit code, and done=true when the caller
+// should stop (help shown, or a parse error).
+func parseExposureArgs(args []string, stdout, stderr io.Writer) (time.Duration, string, int, bool) {
+	exposureFlags := flag.NewFlagSet("exposure", flag.ContinueOnError)
+	// Silence the flag package's own output; we print our own usage.
+	exposureFlags.SetOutput(io.Discard)
+	if err := exposureFlags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stdout, exposureUsage)
+			return 0, "", 0, true
+		}
+		fmt.Fprintf(stderr, "Error parsing flags: %s\n", err)
+		fmt.Fprintln(stderr, exposureUsage)
+		return 0, "", 2, true
+	}
+	if exposureFlags.NArg() > 1 {
+		fmt.Fprintf(stderr, "Error: unexpected argument %q\n", exposureFlags.Arg(1))
+		fmt.Fprintln(stderr, exposureUsage)
+		return 0, "", 1, true
+	}
+
+	label := defaultExposureWindow
+	if exposureFlags.NArg() == 1 {
+		label = exposureFlags.Arg(0)
+	}
+	window, ok := ParseDuration(label)
+	if !ok || window <= 0 {
+		fmt.Fprintf(stderr, "Error: Invalid duration format: %s\n", label)
+		fmt.Fprintln(stderr, exposureUsage)
+		return 0, "", 1, true
+	}
+	return window, label, 0, false
+}
+
+// exposedGoals returns the goals that derail within window of now if nothing
+// is done, soonest first: goals with a deadline in the window that haven't
+// already reached their end value. keep, when non-nil, is the global --filter.
+func exposedGoals(goals []Goal, window time.Duration, keep func(Goal) bool, now time.Time) []Goal {
+	var exposed []Goal
+	for _, g := range keepGoals(filterOutEndValueReached(goals), keep) {
+		if IsDueWithinAt(g.Losedate, window, now) {
+			exposed = append(exposed, g)
+		}
+	}
+	SortGoals(exposed)
+	return exposed
+}
+
+// totalPledge sums the goals' pledges.
+func totalPledge(goals []Goal) float64 {
+	total := 0.0
+	for _, g := range goals {
+		total += g.Pledge
+	}
+	return total
+}
+
+// exposureSummary is the one-line headline for the goals at risk in a window
+// written as label.
+func exposureSummary(goals []Goal, label string) string {
+	if len(goals) == 0 {
+		return fmt.Sprintf("Nothing derails in the next %s.", label)
+	}
+	noun := "goals"
+	if len(goals) == 1 {
+		noun = "goal"
+	}
+	return fmt.Sprintf("If you do nothing for %s you lose $%s across %d %s.", label, formatPledge(totalPledge(goals)), len(goals), noun)
+}
+
+// formatPledge formats a dollar amount, without its "$", in the locale's
+// number format like formatMoney, leaving off the cents when it's whole.
+func formatPledge(amount float64) string {
+	decimals := 2
+	if amount == float64(int64(amount)) {
+		decimals = 0
+	}
+	return activeLocale.number(fmt.Sprintf("%.*f", decimals, amount))
+}
+
+// runExposureCommand fetches the goals and prints what's at stake within
+// window. It returns the process exit code.
+func runExposureCommand(ctx context.Context, client Client, window time.Duration, label string, keep func(Goal) bool, format string, now time.Time, out, errOut io.Writer) int {
+	goals, err := client.FetchGoals(ctx)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: Failed to fetch goals: %s\n", redactError(err))
+		return 1
+	}
+	exposed := exposedGoals(goals, window, keep, now)
+
+	table := Table{
+		Colorize: true,
+		Columns: []Column{
+			{Header: "Slug", Cell: func(g Goal) string { return g.Slug }},
+			{Header: "Pledge", Cell: func(g Goal) string { return "$" + formatPledge(g.Pledge) }},
+			{Header: "Baremin", Cell: func(g Goal) string { return g.Baremin }},
+			{Header: "Due", Cell: func(g Goal) string { return FormatDueDateAt(g.Losedate, now) }},
+		},
+	}
+
+	// Machine-readable formats: emit just the goals, like the list commands.
+	if format == "jsonl" {
+		if err := writeJSONL(out, exposed); err != nil {
+			fmt.Fprintf(errOut, "Error: %s\n", redactError(err))
+			return 1
+		}
+		return 0
+	}
+	if format != "table" {
+		rendered, err := table.RenderAs(format, exposed)
+		if err != nil {
+			fmt.Fprintf(errOut, "Error: %s\n", redactError(err))
+			return 1
+		}
+		fmt.Fprint(out, rendered)
+		return 0
+	}
+
+	fmt.Fprintln(out, exposureSummary(exposed, label))
+	if len(exposed) > 0 {
+		fmt.Fprintln(out)
+		fmt.Fprint(out, table.Render(exposed))
+	}
+	return 0
+}
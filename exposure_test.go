@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseExposureArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantWindow time.Duration
+		wantDone   bool
+		wantCode   int
+		wantErr    string
+	}{
+		{name: "default", args: nil, wantWindow: 48 * time.Hour},
+		{name: "days", args: []string{"3d"}, wantWindow: 72 * time.Hour},
+		{name: "help", args: []string{"--help"}, wantDone: true},
+		{name: "bad duration", args: []string{"soon"}, wantDone: true, wantCode: 1, wantErr: "Invalid duration format: soon"},
+		{name: "extra", args: []string{"1d", "2d"}, wantDone: true, wantCode: 1, wantErr: `unexpected argument "2d"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errb bytes.Buffer
+			window, _, code, done := parseExposureArgs(tt.args, &out, &errb)
+			if done != tt.wantDone || code != tt.wantCode || window != tt.wantWindow {
+				t.Fatalf("window=%v code=%d done=%v, want %v %d %v", window, code, done, tt.wantWindow, tt.wantCode, tt.wantDone)
+			}
+			if !strings.Contains(errb.String(), tt.wantErr) {
+				t.Errorf("stderr = %q, want %q", errb.String(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunExposureCommand(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	goalval := 10.0
+	curval := 10.0
+	goals := []Goal{
+		{Slug: "soon", Pledge: 5, Losedate: now.Add(3 * time.Hour).Unix(), Baremin: "+1"},
+		{Slug: "tomorrow", Pledge: 90, Losedate: now.Add(30 * time.Hour).Unix(), Baremin: "+2"},
+		{Slug: "later", Pledge: 810, Losedate: now.Add(72 * time.Hour).Unix()},
+		{Slug: "done", Pledge: 30, Losedate: now.Add(time.Hour).Unix(), Goalval: &goalval, Curval: &curval, Dir: 1},
+	}
+	client := &FakeClient{FetchGoalsFunc: func() ([]Goal, error) { return goals, nil }}
+
+	var out, errb bytes.Buffer
+	code := runExposureCommand(context.Background(), client, 48*time.Hour, "48h", nil, "table", now, &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 0, "If you do nothing for 48h you lose $95 across 2 goals.", "")
+	if strings.Contains(out.String(), "later") || strings.Contains(out.String(), "done") {
+		t.Errorf("output lists goals outside the window or complete:\n%s", out.String())
+	}
+	if strings.Index(out.String(), "soon") > strings.Index(out.String(), "tomorrow") {
+		t.Errorf("goals not soonest first:\n%s", out.String())
+	}
+
+	out.Reset()
+	onlyLater := func(g Goal) bool { return g.Slug == "later" }
+	code = runExposureCommand(context.Background(), client, 48*time.Hour, "48h", onlyLater, "table", now, &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 0, "Nothing derails in the next 48h.", "")
+
+	out.Reset()
+	code = runExposureCommand(context.Background(), client, 48*time.Hour, "48h", nil, "csv", now, &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 0, "soon,$5,+1", "")
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Safe writes. Several buzz processes can run at once — the TUI open while a
+// cron job adds datapoints — and each may write ~/.buzzrc and the caches and
+// state files beside it. A plain os.WriteFile truncates first, so a reader
+// could see an empty or half-written file, and two read-modify-write updates
+// can lose one another's changes. So files buzz keeps in the home directory
+// are written through writeFileAtomic, and read-modify-write updates to them
+// (updateConfig, setLastAdd, …) go through updateFileAtomic: the file is
+// replaced by renaming a fully written temporary file over it, while holding a
+// lock file beside it.
+
+// fileLockTimeout is how long to wait for another buzz to finish writing.
+const fileLockTimeout = 5 * time.Second
+
+// fileLockStale is how old a lock file must be before it's taken to be left
+// over from a buzz that crashed while holding it. Writes take milliseconds.
+const fileLockStale = 30 * time.Second
+
+// lockFile takes the lock for path, a path+".lock" file created exclusively,
+// waiting up to fileLockTimeout for another process to release it. Call the
+// returned function to release it.
+func lockFile(path string) (func(), error) {
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(fileLockTimeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, err
+		}
+		if lockIsStale(lockPath) && breakStaleLock(lockPath) {
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for another buzz to finish writing %s (delete %s if none is running)", path, lockPath)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// lockIsStale reports whether the lock file at lockPath is older than
+// fileLockStale.
+func lockIsStale(lockPath string) bool {
+	info, err := os.Stat(lockPath)
+	return err == nil && time.Since(info.ModTime()) > fileLockStale
+}
+
+// breakStaleLock removes the lock file at lockPath if it's stale, reporting
+// whether it did. Two waiters can both see a stale lock; if both removed it,
+// the second could remove the fresh lock the first had just taken, and both
+// would write at once. So only the waiter holding lockPath+".break" removes
+// it, after checking it's still stale.
+func breakStaleLock(lockPath string) bool {
+	breakPath := lockPath + ".break"
+	f, err := os.OpenFile(breakPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		// Another waiter is breaking it, or crashed while it was, which
+		// takes even less time than a write.
+		if lockIsStale(breakPath) {
+			os.Remove(breakPath)
+		}
+		return false
+	}
+	f.Close()
+	defer os.Remove(breakPath)
+	if !lockIsStale(lockPath) {
+		return false
+	}
+	return os.Remove(lockPath) == nil
+}
+
+// writeFileAtomic replaces the file at path with data, under its lock, so
+// concurrent readers see the old contents or the new, never a mix.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	unlock, err := lockFile(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return replaceFile(path, data, perm)
+}
+
+// errUnchanged is returned by an updateFileAtomic update function to leave
+// the file as it is.
+var errUnchanged = errors.New("unchanged")
+
+// updateFileAtomic reads the file at path, passes its contents to update (nil
+// when the file doesn't exist yet), and writes back what update returns, all
+// under the file's lock so a concurrent update can't be lost in between. An
+// update returning errUnchanged writes nothing, and isn't an error.
+func updateFileAtomic(path string, perm os.FileMode, update func([]byte) ([]byte, error)) error {
+	unlock, err := lockFile(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if data, err = update(data); err != nil {
+		if errors.Is(err, errUnchanged) {
+			return nil
+		}
+		return err
+	}
+	return replaceFile(path, data, perm)
+}
+
+// replaceFile writes data to a temporary file beside path and renames it over
+// path. The caller holds the lock.
+func replaceFile(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // a no-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestUpdateFileAtomicConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "counter")
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := updateFileAtomic(path, 0600, func(data []byte) ([]byte, error) {
+				n, _ := strconv.Atoi(string(data))
+				return []byte(strconv.Itoa(n + 1)), nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "20" {
+		t.Errorf("counter = %q, %v; want 20 with no update lost", data, err)
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("left behind %d files, want only the counter", len(entries)-1)
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state")
+	if err := os.WriteFile(path, []byte("old contents"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFileAtomic(path, []byte("new"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "new" {
+		t.Errorf("contents = %q, want %q", data, "new")
+	}
+	if perm := info.Mode().Perm(); perm != 0600 && os.PathSeparator == '/' {
+		t.Errorf("mode = %v, want 0600", perm)
+	}
+}
+
+func TestLockFileStale(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state")
+	// A lock left by a buzz that crashed doesn't block writes forever.
+	if err := os.WriteFile(path+".lock", nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * fileLockStale)
+	if err := os.Chtimes(path+".lock", old, old); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeFileAtomic(path, []byte("new"), 0600); err != nil {
+		t.Fatalf("writeFileAtomic with a stale lock: %v", err)
+	}
+	if _, err := os.Stat(path + ".lock"); !os.IsNotExist(err) {
+		t.Errorf("lock file left behind: %v", err)
+	}
+}
+
+func TestBreakStaleLock(t *testing.T) {
+	lockPath := filepath.Join(t.TempDir(), "state.lock")
+	if err := os.WriteFile(lockPath, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// A fresh lock, such as one another waiter has just taken after breaking
+	// the stale one, is left alone.
+	if breakStaleLock(lockPath) {
+		t.Error("broke a fresh lock")
+	}
+	if _, err := os.Stat(lockPath); err != nil {
+		t.Errorf("fresh lock removed: %v", err)
+	}
+
+	old := time.Now().Add(-2 * fileLockStale)
+	if err := os.Chtimes(lockPath, old, old); err != nil {
+		t.Fatal(err)
+	}
+	// Only the waiter holding the break lock may remove it.
+	if err := os.WriteFile(lockPath+".break", nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if breakStaleLock(lockPath) {
+		t.Error("broke a lock another waiter is breaking")
+	}
+	os.Remove(lockPath + ".break")
+	if !breakStaleLock(lockPath) {
+		t.Error("didn't break a stale lock")
+	}
+	entries, _ := os.ReadDir(filepath.Dir(lockPath))
+	if len(entries) != 0 {
+		t.Errorf("left behind %d files", len(entries))
+	}
+}
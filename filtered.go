@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
+	"io"
 	"math"
 	"os"
 	"sort"
@@ -32,9 +34,165 @@ func handleAllCommand() {
 	handleFilteredCommand("all", allGoalsFilter)
 }
 
-// handleTodayCommand outputs all goals that are due today
+// todayUsage documents `buzz today`.
+const todayUsage = "Usage: buzz today [--group-by deadline-hour] [--total]"
+
+// handleTodayCommand outputs all goals that are due today, optionally grouped
+// under their deadline clock time and followed by the day's total workload.
 func handleTodayCommand() {
-	handleFilteredCommand("today", isDueTodayFilter)
+	groupBy, total, code, done := parseTodayArgs(os.Args[2:], os.Stderr)
+	if done {
+		os.Exit(code)
+	}
+	var groupFor func(Goal) string
+	if groupBy == "deadline-hour" {
+		groupFor = deadlineGroupLabel
+	}
+	var legendFor func([]Goal) string
+	if total {
+		legendFor = workloadLegend
+	}
+	handleFilteredCommandWithDisplay("today", isDueTodayFilter,
+		func(g Goal) string { return g.Baremin },
+		func(g Goal) int64 { return g.Losedate },
+		legendFor, groupFor,
+	)
+}
+
+// parseTodayArgs parses the `buzz today` flags, returning the --group-by value
+// ("" for none) and --total. done is true with a non-zero exit code on a usage
+// error.
+func parseTodayArgs(args []string, errOut io.Writer) (groupBy string, total bool, exitCode int, done bool) {
+	fs := flag.NewFlagSet("today", flag.ContinueOnError)
+	fs.SetOutput(io.Discard) // we print our own usage
+	group := fs.String("group-by", "", "Group goals (deadline-hour)")
+	totalFlag := fs.Bool("total", false, "Sum the bare minimums by unit")
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(errOut, "Error: %s\n", err)
+		fmt.Fprintln(errOut, todayUsage)
+		return "", false, 2, true
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(errOut, "Error: Unknown arguments: %v\n", fs.Args())
+		fmt.Fprintln(errOut, todayUsage)
+		return "", false, 2, true
+	}
+	if *group != "" && *group != "deadline-hour" {
+		fmt.Fprintf(errOut, "Error: invalid --group-by value %q (want deadline-hour)\n", *group)
+		fmt.Fprintln(errOut, todayUsage)
+		return "", false, 2, true
+	}
+	return *group, *totalFlag, 0, false
+}
+
+// workloadItem is one unit's share of a day's workload.
+type workloadItem struct {
+	units  string
+	amount float64
+}
+
+// workload sums the goals' bare minimums by unit, in the order each unit first
+// appears, leaving out the goals bareminAmount skips. Time-formatted bare
+// minimums ("+1:30") count as hours.
+func workload(goals []Goal) []workloadItem {
+	var items []workloadItem
+	index := map[string]int{}
+	for _, g := range goals {
+		amount, _, ok := bareminAmount(g)
+		if !ok {
+			continue
+		}
+		units := strings.TrimSpace(g.Gunits)
+		if units == "" {
+			units = "units"
+		}
+		key := strings.ToLower(units)
+		if i, ok := index[key]; ok {
+			items[i].amount += amount
+			continue
+		}
+		index[key] = len(items)
+		items = append(items, workloadItem{units: units, amount: amount})
+	}
+	return items
+}
+
+// bareminAmount is how much of g is left to do today, from its bare minimum.
+// A time-formatted bare minimum ("+1:30") is returned in hours, with inHours
+// set. ok is false for do-less goals (a limit, not work), goals at their end
+// value, and goals with nothing left to do.
+func bareminAmount(g Goal) (amount float64, inHours bool, ok bool) {
+	if IsDoLessGoal(g) || IsEndValueReached(g) {
+		return 0, false, false
+	}
+	value := ParseBareminValue(g.Baremin)
+	if strings.Contains(value, ":") {
+		seconds, _, valid := parseTimeValue(value)
+		amount, inHours = float64(seconds)/3600, true
+		return amount, inHours, valid && amount > 0
+	}
+	amount, err := strconv.ParseFloat(value, 64)
+	return amount, false, err == nil && amount > 0
+}
+
+// workloadLegend is the line `buzz today --total` ends with, e.g. "Total: 2.5
+// hours, 40 pushups, 1 blog post".
+func workloadLegend(goals []Goal) string {
+	items := workload(goals)
+	if len(items) == 0 {
+		return "\nTotal: nothing left to do today\n"
+	}
+	parts := make([]string, len(items))
+	for i, item := range items {
+		parts[i] = formatValue(math.Round(item.amount*100)/100) + " " + item.units
+	}
+	return "\nTotal: " + strings.Join(parts, ", ") + "\n"
+}
+
+// deadlineGroupLabel names the group a goal falls under in `today --group-by
+// deadline-hour`: its deadline clock time from the Deadline offset, e.g.
+// "by 2pm" or "by 11:30pm" ("by 14:00" on a 24-hour clock).
+func deadlineGroupLabel(g Goal) string {
+	const secondsPerDay = 24 * 60 * 60
+	normalized := ((g.Deadline % secondsPerDay) + secondsPerDay) % secondsPerDay
+	t := time.Unix(int64(normalized), 0).UTC()
+	if activeLocale.clock24 {
+		return "by " + t.Format("15:04")
+	}
+	if t.Minute() == 0 {
+		return "by " + t.Format("3pm")
+	}
+	return "by " + t.Format("3:04pm")
+}
+
+// groupRenderedRows interleaves group headings into a rendered table whose
+// lines correspond one-to-one with goals. Goals arrive sorted by deadline, so
+// groups appear in chronological order of their first goal; a later goal with
+// an earlier-seen label (a deadline tomorrow at the same clock time) joins
+// that group.
+func groupRenderedRows(rendered string, goals []Goal, groupFor func(Goal) string) string {
+	lines := strings.Split(strings.TrimSuffix(rendered, "\n"), "\n")
+	var order []string
+	groups := make(map[string][]string)
+	for i, g := range goals {
+		label := groupFor(g)
+		if _, ok := groups[label]; !ok {
+			order = append(order, label)
+		}
+		groups[label] = append(groups[label], lines[i])
+	}
+
+	var b strings.Builder
+	for i, label := range order {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(label + ":\n")
+		for _, line := range groups[label] {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+	return b.String()
 }
 
 // handleTomorrowCommand outputs all goals that are due tomorrow. Goals that
@@ -72,7 +230,7 @@ func handleTomorrowCommand() {
 	losedateFor := func(g Goal) int64 { return viewFor(g).losedate }
 	// Explain the "(!)" marker, but only when a flagged goal is actually shown.
 	legendFor := func(goals []Goal) string { return tomorrowLegend(goals, viewFor) }
-	handleFilteredCommandWithDisplay("tomorrow", filter, bareminFor, losedateFor, legendFor)
+	handleFilteredCommandWithDisplay("tomorrow", filter, bareminFor, losedateFor, legendFor, nil)
 }
 
 // tomorrowMalformedLegend is the footnote shown beneath the tomorrow table when
@@ -98,27 +256,17 @@ func handleLessCommand() {
 	handleFilteredCommand("do-less", isDoLessFilter)
 }
 
+// dueUsage documents `buzz due`.
+const dueUsage = `Usage: buzz due <duration>
+       buzz due --within <duration>
+  Examples: buzz due 10m, buzz due 1h, buzz due --within 6h, buzz due 1w
+  Supported units: m (minutes), h (hours), d (days), w (weeks)`
+
 // handleDueCommand outputs all goals due within the specified duration
 func handleDueCommand() {
-	// Check arguments: buzz due <duration>
-	if len(os.Args) < 3 {
-		fmt.Println("Error: Missing required duration argument")
-		fmt.Println("Usage: buzz due <duration>")
-		fmt.Println("  Examples: buzz due 10m, buzz due 1h, buzz due 5d, buzz due 1w")
-		fmt.Println("  Supported units: m (minutes), h (hours), d (days), w (weeks)")
-		os.Exit(1)
-	}
-
-	durationStr := os.Args[2]
-
-	// Parse the duration
-	duration, ok := ParseDuration(durationStr)
-	if !ok {
-		fmt.Printf("Error: Invalid duration format: %s\n", durationStr)
-		fmt.Println("Usage: buzz due <duration>")
-		fmt.Println("  Examples: buzz due 10m, buzz due 1h, buzz due 5d, buzz due 1w")
-		fmt.Println("  Supported units: m (minutes), h (hours), d (days), w (weeks)")
-		os.Exit(1)
+	label, duration, code, done := parseDueArgs(os.Args[2:], os.Stdout)
+	if done {
+		os.Exit(code)
 	}
 
 	// Create filter function that captures the duration
@@ -127,10 +275,47 @@ func handleDueCommand() {
 	}
 
 	// Format the filter name for display
-	filterName := fmt.Sprintf("due within %s", durationStr)
+	filterName := fmt.Sprintf("due within %s", label)
 	handleFilteredCommand(filterName, isDueWithinFilter)
 }
 
+// parseDueArgs parses `buzz due` arguments: the duration, given as the only
+// argument or with --within. It returns the duration as written and parsed;
+// done is true with a non-zero exit code on a usage error.
+func parseDueArgs(args []string, errOut io.Writer) (label string, duration time.Duration, exitCode int, done bool) {
+	fs := flag.NewFlagSet("due", flag.ContinueOnError)
+	fs.SetOutput(io.Discard) // we print our own usage
+	within := fs.String("within", "", "List goals due within this duration")
+	if err := fs.Parse(args); err != nil {
+		fmt.Fprintf(errOut, "Error: %s\n", err)
+		fmt.Fprintln(errOut, dueUsage)
+		return "", 0, 2, true
+	}
+
+	label = *within
+	switch {
+	case fs.NArg() > 1 || fs.NArg() == 1 && label != "":
+		fmt.Fprintf(errOut, "Error: Unknown arguments: %v\n", fs.Args())
+		fmt.Fprintln(errOut, dueUsage)
+		return "", 0, 1, true
+	case fs.NArg() == 1:
+		label = fs.Arg(0)
+	case label == "":
+		fmt.Fprintln(errOut, "Error: Missing required duration argument")
+		fmt.Fprintln(errOut, dueUsage)
+		return "", 0, 1, true
+	}
+
+	// Parse the duration
+	duration, ok := ParseDuration(label)
+	if !ok {
+		fmt.Fprintf(errOut, "Error: Invalid duration format: %s\n", label)
+		fmt.Fprintln(errOut, dueUsage)
+		return "", 0, 1, true
+	}
+	return label, duration, 0, false
+}
+
 // handleFilteredCommand is a shared helper that outputs all goals matching the given filter
 // filterName is used in messages (e.g., "today", "tomorrow", or "do-less")
 // filter is a function that takes a Goal and returns true if the goal matches
@@ -138,7 +323,7 @@ func handleFilteredCommand(filterName string, filter func(Goal) bool) {
 	handleFilteredCommandWithDisplay(filterName, filter,
 		func(g Goal) string { return g.Baremin },
 		func(g Goal) int64 { return g.Losedate },
-		nil,
+		nil, nil,
 	)
 }
 
@@ -162,7 +347,11 @@ func sortGoalsByDisplayedLosedate(goals []Goal, losedateFor func(Goal) int64) {
 // and may return a footnote (e.g. explaining a marker the cells carry); an
 // empty string prints nothing. The tomorrow view uses it to explain its "(!)"
 // malformed-bright-red-line marker only when a flagged goal is actually shown.
-func handleFilteredCommandWithDisplay(filterName string, filter func(Goal) bool, bareminFor func(Goal) string, losedateFor func(Goal) int64, legendFor func([]Goal) string) {
+//
+// groupFor, when non-nil, labels each goal's group; the human table is then
+// printed under one heading per label (`today --group-by deadline-hour`).
+// Machine formats ignore it.
+func handleFilteredCommandWithDisplay(filterName string, filter func(Goal) bool, bareminFor func(Goal) string, losedateFor func(Goal) int64, legendFor func([]Goal) string, groupFor func(Goal) string) {
 	// Load config
 	if !ConfigExists() {
 		fmt.Println("Error: No configuration found. Please run 'buzz auth login' to authenticate.")
@@ -175,7 +364,24 @@ func handleFilteredCommandWithDisplay(filterName string, filter func(Goal) bool,
 		os.Exit(1)
 	}
 
-	client := NewHTTPClient(config)
+	client := journaling(recordingSlugs(NewHTTPClient(config), config.Username))
+
+	// jsonl writes each matching goal as soon as it arrives, so it skips the
+	// sort and comes out in the order Beeminder sends the goals.
+	if outputFormat == "jsonl" {
+		write := jsonLines[Goal](os.Stdout)
+		err := client.StreamGoals(context.Background(), false, func(goal Goal) error {
+			if !filter(goal) || (goalFilter != nil && !goalFilter(goal)) {
+				return nil
+			}
+			return write(goal)
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Failed to fetch goals: %s\n", redactError(err))
+			os.Exit(1)
+		}
+		return
+	}
 
 	// Fetch goals
 	goals, err := client.FetchGoals(context.Background())
@@ -190,7 +396,7 @@ func handleFilteredCommandWithDisplay(filterName string, filter func(Goal) bool,
 	// Filter goals that match the criteria
 	var filteredGoals []Goal
 	for _, goal := range goals {
-		if filter(goal) {
+		if filter(goal) && (goalFilter == nil || goalFilter(goal)) {
 			filteredGoals = append(filteredGoals, goal)
 		}
 	}
@@ -209,22 +415,7 @@ func handleFilteredCommandWithDisplay(filterName string, filter func(Goal) bool,
 	// displayed losedates are equal.
 	sortGoalsByDisplayedLosedate(filteredGoals, losedateFor)
 
-	// Headers are unused by the colorized text table (ShowHeader stays false)
-	// but label the columns for --format csv.
-	table := Table{
-		Colorize: true,
-		Columns: []Column{
-			{Header: "Slug", Cell: func(g Goal) string { return g.Slug }},
-			{Header: "Baremin", Cell: func(g Goal) string { return bareminFor(g) }},
-			{Header: "Due", Cell: func(g Goal) string {
-				if IsEndValueReached(g) {
-					return "COMPLETE"
-				}
-				return FormatDueDate(losedateFor(g))
-			}},
-			{Header: "Deadline", Cell: func(g Goal) string { return FormatAbsoluteDeadline(losedateFor(g)) }},
-		},
-	}
+	table := dueTable(bareminFor, losedateFor)
 
 	// Machine-readable formats: emit just the data, no legend or update banner
 	// (they'd corrupt json/csv output).
@@ -238,7 +429,11 @@ func handleFilteredCommandWithDisplay(filterName string, filter func(Goal) bool,
 		return
 	}
 
-	fmt.Print(table.Render(filteredGoals))
+	rendered := table.Render(filteredGoals)
+	if groupFor != nil {
+		rendered = groupRenderedRows(rendered, filteredGoals, groupFor)
+	}
+	fmt.Print(rendered)
 
 	if legendFor != nil {
 		if legend := legendFor(filteredGoals); legend != "" {
@@ -250,6 +445,27 @@ func handleFilteredCommandWithDisplay(filterName string, filter func(Goal) bool,
 	fmt.Print(getUpdateMessage())
 }
 
+// dueTable is the colorized slug / baremin / due / deadline table the filtered
+// list views render, with baremin and losedate supplied per view. Headers are
+// unused by the text table (ShowHeader stays false) but label the columns for
+// --format csv.
+func dueTable(bareminFor func(Goal) string, losedateFor func(Goal) int64) Table {
+	return Table{
+		Colorize: true,
+		Columns: []Column{
+			{Header: "Slug", Cell: func(g Goal) string { return g.Slug }},
+			{Header: "Baremin", Cell: func(g Goal) string { return bareminFor(g) }},
+			{Header: "Due", Cell: func(g Goal) string {
+				if IsEndValueReached(g) {
+					return "COMPLETE"
+				}
+				return FormatDueDate(losedateFor(g))
+			}},
+			{Header: "Deadline", Cell: func(g Goal) string { return FormatAbsoluteDeadline(losedateFor(g)) }},
+		},
+	}
+}
+
 // tomorrowView is what a goal shows in the "due tomorrow" view: the baremin
 // string, the losedate timestamp, and whether the goal's bright red line failed
 // to parse. baremin and losedate are vended together so they always reflect the
@@ -428,6 +644,29 @@ func todayDaystampFor(g Goal, now time.Time) string {
 	return time.Date(shifted.Year(), shifted.Month(), shifted.Day(), 0, 0, 0, 0, now.Location()).Format("20060102")
 }
 
+// goalDayAt returns local midnight of the goal's current Beeminder day (see
+// todayDaystampFor), the date a datapoint added at now lands on.
+func goalDayAt(g Goal, now time.Time) time.Time {
+	day, _ := time.ParseInLocation("20060102", todayDaystampFor(g, now), now.Location())
+	return day
+}
+
+// dayTimestamp returns a Unix time inside the Beeminder day that starts on
+// day's date, for a goal whose deadline is deadline seconds from midnight:
+// its middle, so neither the deadline nor a DST change can tip it into a
+// neighbouring day. For where the API takes a timestamp but not a daystamp.
+func dayTimestamp(day time.Time, deadline int) int64 {
+	noon := time.Date(day.Year(), day.Month(), day.Day(), 12, 0, 0, 0, day.Location())
+	return noon.Add(time.Duration(deadline) * time.Second).Unix()
+}
+
+// yesterdayDaystampFor returns the YYYYMMDD daystamp of the day before the
+// goal's current Beeminder day (see todayDaystampFor).
+func yesterdayDaystampFor(g Goal, now time.Time) string {
+	today, _ := time.ParseInLocation("20060102", todayDaystampFor(g, now), now.Location())
+	return today.AddDate(0, 0, -1).Format("20060102")
+}
+
 // tomorrowDaystampFor returns the YYYYMMDD daystamp representing the day after
 // the goal's current Beeminder day (see todayDaystampFor for the deadline-shift
 // rationale).
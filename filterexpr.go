@@ -0,0 +1,402 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Goal filters: a small expression language (compileFilter) used by the
+// global --filter flag (list commands and the TUI) and by the saved filters
+// defined under "filters" in ~/.buzzrc, which the TUI's f key cycles through.
+// The same engine backs every consumer so a filter means the same thing
+// everywhere.
+
+// goalFilterName and goalFilter hold the global --filter selection, set once
+// in main. goalFilter is nil when no filter was given, which keeps every goal.
+var (
+	goalFilterName string
+	goalFilter     func(Goal) bool
+)
+
+// filterField is a goal attribute a filter expression can test. Exactly one
+// accessor is set, which decides the comparisons it supports.
+type filterField struct {
+	num  func(Goal) float64  // numbers: all comparison operators
+	str  func(Goal) string   // strings: == and != (case-insensitive)
+	list func(Goal) []string // lists: == is "contains", != "doesn't contain"
+}
+
+// filterFields are the fields filter expressions can reference.
+var filterFields = map[string]filterField{
+	"safebuf": {num: func(g Goal) float64 { return float64(g.Safebuf) }},
+	"pledge":  {num: func(g Goal) float64 { return g.Pledge }},
+	"slug":    {str: func(g Goal) string { return g.Slug }},
+	"title":   {str: func(g Goal) string { return g.Title }},
+	"type":    {str: func(g Goal) string { return g.GoalType }},
+	"gunits":  {str: func(g Goal) string { return g.Gunits }},
+	"tag":     {list: func(g Goal) []string { return g.Tags }},
+	"group":   {list: func(g Goal) []string { return groupsOf(g.Slug) }},
+}
+
+// compileFilter compiles a filter expression into a goal predicate, e.g.
+//
+//	safebuf < 2 && pledge >= 10 && type == "hustler"
+//	tag:work AND (safebuf < 3 OR pledge > 30)
+//
+// Comparisons are field <op> value with ==, =, !=, <, <=, > or >=; field:value
+// is shorthand for field == value. Combine them with && / AND, || / OR,
+// ! / NOT and parentheses. Values are numbers, "quoted strings", or bare
+// words.
+func compileFilter(expr string) (func(Goal) bool, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, fmt.Errorf("empty filter expression")
+	}
+	tokens, err := lexFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{tokens: tokens}
+	fn, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != filterEOF {
+		return nil, fmt.Errorf("unexpected %q in filter", tok.text)
+	}
+	return fn, nil
+}
+
+// filterTokenKind classifies a filter expression token.
+type filterTokenKind int
+
+const (
+	filterEOF filterTokenKind = iota
+	filterWord
+	filterNumber
+	filterString
+	filterOp // comparison and logical operators, parentheses, and ':'
+)
+
+// filterToken is one lexed token of a filter expression.
+type filterToken struct {
+	kind filterTokenKind
+	text string
+	pos  int // byte offset of text in the expression
+}
+
+// filterSymbols are the operator tokens, longest first so "<=" wins over "<".
+var filterSymbols = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "=", "!", "(", ")", ":"}
+
+// lexFilter splits a filter expression into tokens.
+func lexFilter(expr string) ([]filterToken, error) {
+	var tokens []filterToken
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '"':
+			end := strings.IndexByte(expr[i+1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated string in filter")
+			}
+			tokens = append(tokens, filterToken{filterString, expr[i+1 : i+1+end], i + 1})
+			i += end + 2
+		case isFilterDigit(c) || (c == '-' || c == '.') && i+1 < len(expr) && isFilterDigit(expr[i+1]):
+			j := i + 1
+			for j < len(expr) && (isFilterDigit(expr[j]) || expr[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, filterToken{filterNumber, expr[i:j], i})
+			i = j
+		case isFilterWordByte(c):
+			j := i
+			for j < len(expr) && isFilterWordByte(expr[j]) {
+				j++
+			}
+			tokens = append(tokens, filterToken{filterWord, expr[i:j], i})
+			i = j
+		default:
+			matched := false
+			for _, sym := range filterSymbols {
+				if strings.HasPrefix(expr[i:], sym) {
+					tokens = append(tokens, filterToken{filterOp, sym, i})
+					i += len(sym)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected character %q in filter", c)
+			}
+		}
+	}
+	return append(tokens, filterToken{kind: filterEOF, pos: len(expr)}), nil
+}
+
+func isFilterDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func isFilterWordByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || isFilterDigit(c) || c == '_' || c == '-' || c == '.'
+}
+
+// filterParser is a recursive-descent parser over lexed filter tokens.
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+}
+
+func (p *filterParser) peek() filterToken { return p.tokens[p.pos] }
+
+func (p *filterParser) next() filterToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != filterEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes the next token if it is one of the given operators or
+// (case-insensitive) keywords.
+func (p *filterParser) accept(texts ...string) bool {
+	tok := p.peek()
+	if tok.kind != filterOp && tok.kind != filterWord {
+		return false
+	}
+	for _, text := range texts {
+		if tok.text == text || tok.kind == filterWord && strings.EqualFold(tok.text, text) {
+			p.pos++
+			return true
+		}
+	}
+	return false
+}
+
+// parseOr parses: and { ("||" | OR) and }
+func (p *filterParser) parseOr() (func(Goal) bool, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||", "or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(g Goal) bool { return l(g) || right(g) }
+	}
+	return left, nil
+}
+
+// parseAnd parses: unary { ("&&" | AND) unary }
+func (p *filterParser) parseAnd() (func(Goal) bool, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&", "and") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(g Goal) bool { return l(g) && right(g) }
+	}
+	return left, nil
+}
+
+// parseUnary parses: ("!" | NOT) unary | "(" or ")" | comparison
+func (p *filterParser) parseUnary() (func(Goal) bool, error) {
+	if p.accept("!", "not") {
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(g Goal) bool { return !inner(g) }, nil
+	}
+	if p.accept("(") {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, fmt.Errorf("missing ) in filter")
+		}
+		return inner, nil
+	}
+	return p.parseComparison()
+}
+
+// parseComparison parses: field op value, or the field:value shorthand.
+func (p *filterParser) parseComparison() (func(Goal) bool, error) {
+	fieldTok := p.next()
+	if fieldTok.kind != filterWord {
+		if fieldTok.kind == filterEOF {
+			return nil, fmt.Errorf("filter ends unexpectedly")
+		}
+		return nil, fmt.Errorf("expected a field name in filter, got %q", fieldTok.text)
+	}
+	name := strings.ToLower(fieldTok.text)
+	field, ok := filterFields[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown filter field %q (want %s)", fieldTok.text, strings.Join(filterFieldNames(), ", "))
+	}
+
+	opTok := p.next()
+	op := opTok.text
+	switch {
+	case opTok.kind == filterOp && op == ":":
+		op = "=="
+	case opTok.kind == filterOp && slices.Contains([]string{"==", "=", "!=", "<", "<=", ">", ">="}, op):
+		if op == "=" {
+			op = "=="
+		}
+	default:
+		return nil, fmt.Errorf("expected a comparison after %q in filter", fieldTok.text)
+	}
+
+	valueTok := p.next()
+	if valueTok.kind != filterWord && valueTok.kind != filterNumber && valueTok.kind != filterString {
+		return nil, fmt.Errorf("expected a value after %s %s in filter", fieldTok.text, opTok.text)
+	}
+	value := valueTok.text
+
+	switch {
+	case field.num != nil:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q for %s in filter", value, name)
+		}
+		return func(g Goal) bool { return compareFloat(field.num(g), op, n) }, nil
+	case op != "==" && op != "!=":
+		return nil, fmt.Errorf("%s only supports == and != in filter", name)
+	case field.str != nil:
+		want := op == "=="
+		return func(g Goal) bool { return strings.EqualFold(field.str(g), value) == want }, nil
+	default:
+		want := op == "=="
+		return func(g Goal) bool {
+			return slices.ContainsFunc(field.list(g), func(s string) bool { return strings.EqualFold(s, value) }) == want
+		}, nil
+	}
+}
+
+// filterFieldNames returns the filterable field names in sorted order.
+func filterFieldNames() []string {
+	names := make([]string, 0, len(filterFields))
+	for name := range filterFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// compareFloat applies a filter comparison operator.
+func compareFloat(a float64, op string, b float64) bool {
+	switch op {
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	case "!=":
+		return a != b
+	default:
+		return a == b
+	}
+}
+
+// renameFilterSlug rewrites slug comparisons against oldSlug in a filter
+// expression to use newSlug, reporting whether anything changed. Expressions
+// that don't lex are returned unchanged.
+func renameFilterSlug(expr, oldSlug, newSlug string) (string, bool) {
+	tokens, err := lexFilter(expr)
+	if err != nil {
+		return expr, false
+	}
+	var b strings.Builder
+	last := 0
+	for i := 0; i+2 < len(tokens); i++ {
+		field, op, value := tokens[i], tokens[i+1], tokens[i+2]
+		if field.kind != filterWord || !strings.EqualFold(field.text, "slug") || op.kind != filterOp ||
+			!slices.Contains([]string{":", "==", "=", "!="}, op.text) ||
+			value.kind == filterOp || value.kind == filterEOF || !strings.EqualFold(value.text, oldSlug) {
+			continue
+		}
+		b.WriteString(expr[last:value.pos])
+		b.WriteString(newSlug)
+		last = value.pos + len(value.text)
+	}
+	if last == 0 {
+		return expr, false
+	}
+	b.WriteString(expr[last:])
+	return b.String(), true
+}
+
+// namedFilter compiles the saved filter called name from config.
+func namedFilter(config *Config, name string) (func(Goal) bool, error) {
+	expr, ok := config.Filters[name]
+	if !ok {
+		if len(config.Filters) == 0 {
+			return nil, fmt.Errorf("unknown filter %q (no filters are defined in ~/.buzzrc)", name)
+		}
+		return nil, fmt.Errorf("unknown filter %q (defined: %s)", name, strings.Join(filterNames(config), ", "))
+	}
+	fn, err := compileFilter(expr)
+	if err != nil {
+		return nil, fmt.Errorf("filter %q: %w", name, err)
+	}
+	return fn, nil
+}
+
+// filterNames returns the saved filter names in sorted order.
+func filterNames(config *Config) []string {
+	names := make([]string, 0, len(config.Filters))
+	for name := range config.Filters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// loadGoalFilter resolves the global --filter value: the name of a saved
+// filter, or otherwise an inline expression. It runs before dispatch so a
+// typo fails before any work.
+func loadGoalFilter(value string) (func(Goal) bool, error) {
+	if ConfigExists() {
+		config, err := LoadConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config: %w", err)
+		}
+		if _, ok := config.Filters[value]; ok {
+			return namedFilter(config, value)
+		}
+	}
+	fn, err := compileFilter(value)
+	if err != nil {
+		return nil, fmt.Errorf("--filter %q is not a saved filter or a valid expression: %w", value, err)
+	}
+	return fn, nil
+}
+
+// keepGoals returns the goals keep accepts; a nil keep returns goals as-is.
+func keepGoals(goals []Goal, keep func(Goal) bool) []Goal {
+	if keep == nil {
+		return goals
+	}
+	var kept []Goal
+	for _, g := range goals {
+		if keep(g) {
+			kept = append(kept, g)
+		}
+	}
+	return kept
+}
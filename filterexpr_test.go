@@ -0,0 +1,124 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompileFilter(t *testing.T) {
+	work := Goal{Slug: "work", Tags: []string{"work"}, Safebuf: 1, Pledge: 30, GoalType: "hustler"}
+	gym := Goal{Slug: "gym", Tags: []string{"health"}, Safebuf: 5, Pledge: 5, GoalType: "biker"}
+
+	tests := []struct {
+		expr      string
+		wantWork  bool
+		wantGym   bool
+		wantError string
+	}{
+		{expr: "tag:work", wantWork: true},
+		{expr: "tag:work AND safebuf<3", wantWork: true},
+		{expr: "tag:health and safebuf<3"},
+		{expr: "safebuf >= 5", wantGym: true},
+		{expr: "pledge>5", wantWork: true},
+		{expr: "pledge!=5", wantWork: true},
+		{expr: "type:Biker", wantGym: true},
+		{expr: `safebuf < 2 && pledge >= 10 && type == "hustler"`, wantWork: true},
+		{expr: "tag == work || safebuf > 4", wantWork: true, wantGym: true},
+		{expr: "!tag:work", wantGym: true},
+		{expr: "NOT (tag:work OR tag:health)"},
+		{expr: "tag:work and (safebuf > 3 or pledge = 30)", wantWork: true},
+		{expr: "safebuf > -1 && tag != health", wantWork: true},
+		{expr: `title == ""`, wantWork: true, wantGym: true},
+		{expr: "", wantError: "empty filter expression"},
+		{expr: "colour == red", wantError: "unknown filter field"},
+		{expr: "slug < 3", wantError: "only supports == and !="},
+		{expr: "safebuf<soon", wantError: "invalid number"},
+		{expr: "safebuf", wantError: "expected a comparison"},
+		{expr: "(tag:work", wantError: "missing )"},
+		{expr: "tag:work &&", wantError: "ends unexpectedly"},
+		{expr: "tag:work safebuf<1", wantError: "unexpected"},
+		{expr: `type == "hustler`, wantError: "unterminated string"},
+		{expr: "safebuf < 1 # x", wantError: "unexpected character"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			fn, err := compileFilter(tt.expr)
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Fatalf("compileFilter(%q) error = %v, want %q", tt.expr, err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("compileFilter(%q) unexpected error: %v", tt.expr, err)
+			}
+			if got := fn(work); got != tt.wantWork {
+				t.Errorf("work goal: got %v, want %v", got, tt.wantWork)
+			}
+			if got := fn(gym); got != tt.wantGym {
+				t.Errorf("gym goal: got %v, want %v", got, tt.wantGym)
+			}
+		})
+	}
+}
+
+func TestNamedFilter(t *testing.T) {
+	config := &Config{Filters: map[string]string{"work": "tag:work", "broken": "nope"}}
+
+	if _, err := namedFilter(config, "work"); err != nil {
+		t.Errorf("namedFilter(work) unexpected error: %v", err)
+	}
+	if _, err := namedFilter(config, "broken"); err == nil || !strings.Contains(err.Error(), `filter "broken"`) {
+		t.Errorf("namedFilter(broken) error = %v, want it to name the filter", err)
+	}
+	_, err := namedFilter(config, "home")
+	if err == nil || !strings.Contains(err.Error(), "defined: broken, work") {
+		t.Errorf("namedFilter(home) error = %v, want the defined names listed", err)
+	}
+	if _, err := namedFilter(&Config{}, "home"); err == nil || !strings.Contains(err.Error(), "no filters are defined") {
+		t.Errorf("namedFilter with no filters error = %v", err)
+	}
+}
+
+func TestLoadGoalFilter(t *testing.T) {
+	setHome(t, t.TempDir())
+	if err := SaveConfig(&Config{Username: "u", AuthToken: "t", Filters: map[string]string{"work": "tag:work"}}); err != nil {
+		t.Fatal(err)
+	}
+	tagged := Goal{Tags: []string{"work"}, Safebuf: 5}
+
+	saved, err := loadGoalFilter("work")
+	if err != nil || !saved(tagged) {
+		t.Errorf("loadGoalFilter(work) should resolve the saved filter, err = %v", err)
+	}
+	inline, err := loadGoalFilter("safebuf < 2")
+	if err != nil || inline(tagged) {
+		t.Errorf("loadGoalFilter should compile an inline expression, err = %v", err)
+	}
+	if _, err := loadGoalFilter("home"); err == nil || !strings.Contains(err.Error(), "not a saved filter or a valid expression") {
+		t.Errorf("loadGoalFilter(home) error = %v", err)
+	}
+}
+
+func TestRenameFilterSlug(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		want    string
+		changed bool
+	}{
+		{"shorthand", "slug:run", "slug:jog", true},
+		{"comparison", `slug == "run" || slug != RUN`, `slug == "jog" || slug != jog`, true},
+		{"other slugs untouched", "slug:running && safebuf < 2", "slug:running && safebuf < 2", false},
+		{"other fields untouched", "title:run", "title:run", false},
+		{"invalid expression untouched", `slug:"run`, `slug:"run`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, changed := renameFilterSlug(tt.expr, "run", "jog")
+			if got != tt.want || changed != tt.changed {
+				t.Errorf("renameFilterSlug(%q) = (%q, %v), want (%q, %v)", tt.expr, got, changed, tt.want, tt.changed)
+			}
+		})
+	}
+}
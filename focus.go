@@ -0,0 +1,334 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// `buzz focus` gives one goal the whole screen: a big countdown to its
+// deadline, its chart, its recent datapoints, and an always-ready field for
+// adding to it. It refetches the goal after every add (and every minute), and
+// once the goal is safe for the day it celebrates and exits.
+
+const focusUsage = `Usage: buzz focus <goalslug>
+  Open a full-screen view of one goal with a field to add datapoints, until
+  the goal is safe for today. Press Esc or Ctrl+C to exit.`
+
+const (
+	// focusRefreshInterval is how often the goal is refetched between adds.
+	focusRefreshInterval = time.Minute
+	// focusCelebrateFor is how long the celebration shows before exiting.
+	focusCelebrateFor = 3 * time.Second
+	// focusRecentDatapoints is how many datapoints are listed.
+	focusRecentDatapoints = 5
+)
+
+// handleFocusCommand runs focus mode for a goal until it's safe or the user
+// quits.
+func handleFocusCommand() {
+	goalSlug, code, done := parseFocusArgs(os.Args[2:], os.Stdout, os.Stderr)
+	if done {
+		os.Exit(code)
+	}
+
+	client, ok := loadClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+	// Adds made in focus mode go through before_add, as `buzz add` does.
+	useConfiguredHooks(os.Stderr)
+
+	// Cancelled when the program exits so an in-flight request doesn't outlive it.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p := tea.NewProgram(newFocusModel(ctx, client, goalSlug), tea.WithAltScreen())
+	final, err := p.Run()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", redactError(err))
+		os.Exit(1)
+	}
+	if m, ok := final.(focusModel); ok && m.celebrating {
+		fmt.Println(m.celebration())
+	}
+}
+
+// parseFocusArgs parses `buzz focus` arguments, returning the goal slug, a
+// process exit code, and done=true when the caller should stop (help shown,
+// or a usage error).
+func parseFocusArgs(args []string, stdout, stderr io.Writer) (string, int, bool) {
+	focusFlags := flag.NewFlagSet("focus", flag.ContinueOnError)
+	// Silence the flag package's own output; we print our own usage.
+	focusFlags.SetOutput(io.Discard)
+	if err := focusFlags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stdout, focusUsage)
+			return "", 0, true
+		}
+		fmt.Fprintf(stderr, "Error parsing flags: %s\n", err)
+		fmt.Fprintln(stderr, focusUsage)
+		return "", 2, true
+	}
+	if focusFlags.NArg() != 1 {
+		fmt.Fprintln(stderr, "Error: buzz focus takes one goal")
+		fmt.Fprintln(stderr, focusUsage)
+		return "", 1, true
+	}
+	return focusFlags.Arg(0), 0, false
+}
+
+// focusModel is focus mode's Bubble Tea model.
+type focusModel struct {
+	ctx      context.Context
+	client   Client
+	goalSlug string
+
+	goal        *Goal // nil until the first fetch succeeds
+	wasDue      bool  // the goal has been due today while focused, so becoming safe is worth celebrating
+	celebrating bool  // the goal just became safe; exiting shortly
+	input       textinput.Model
+	adding      bool   // a datapoint is being submitted
+	status      string // what the last add did
+	err         error  // the latest fetch or add error
+
+	now   time.Time
+	width int
+}
+
+func newFocusModel(ctx context.Context, client Client, goalSlug string) focusModel {
+	input := textinput.New()
+	input.Prompt = "Add: "
+	input.Placeholder = "value, e.g. 10 or 1:30"
+	input.CharLimit = 40
+	input.Focus()
+	return focusModel{ctx: ctx, client: client, goalSlug: goalSlug, input: input, now: time.Now()}
+}
+
+// focusGoalMsg carries a refetched goal.
+type focusGoalMsg struct {
+	goal *Goal
+	err  error
+}
+
+// focusAddedMsg reports a quick-add's result.
+type focusAddedMsg struct {
+	value string
+	err   error
+}
+
+// focusTickMsg redraws the countdown.
+type focusTickMsg time.Time
+
+// focusRefreshMsg asks for the goal to be refetched.
+type focusRefreshMsg struct{}
+
+func focusTickCmd() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg { return focusTickMsg(t) })
+}
+
+func focusRefreshCmd() tea.Cmd {
+	return tea.Tick(focusRefreshInterval, func(time.Time) tea.Msg { return focusRefreshMsg{} })
+}
+
+// loadFocusGoalCmd fetches the goal with its datapoints.
+func loadFocusGoalCmd(ctx context.Context, client Client, goalSlug string) tea.Cmd {
+	return func() tea.Msg {
+		goal, err := client.FetchGoalWithDatapoints(ctx, goalSlug)
+		goal.prepareChart()
+		return focusGoalMsg{goal: goal, err: err}
+	}
+}
+
+// addFocusDatapointCmd adds value to the goal now.
+func addFocusDatapointCmd(ctx context.Context, client Client, goalSlug, value, comment string) tea.Cmd {
+	return func() tea.Msg {
+		_, err := client.CreateDatapoint(ctx, goalSlug, fmt.Sprintf("%d", time.Now().Unix()), value, comment, "")
+		return focusAddedMsg{value: value, err: err}
+	}
+}
+
+func (m focusModel) Init() tea.Cmd {
+	return tea.Batch(loadFocusGoalCmd(m.ctx, m.client, m.goalSlug), focusTickCmd(), focusRefreshCmd(), textinput.Blink)
+}
+
+func (m focusModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.celebrating {
+			return m, tea.Quit
+		}
+		switch msg.String() {
+		case "esc", "ctrl+c":
+			return m, tea.Quit
+		case "enter":
+			return m.submit()
+		}
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+
+	case focusTickMsg:
+		m.now = time.Time(msg)
+		return m, focusTickCmd()
+
+	case focusRefreshMsg:
+		return m, tea.Batch(loadFocusGoalCmd(m.ctx, m.client, m.goalSlug), focusRefreshCmd())
+
+	case focusGoalMsg:
+		m.err = msg.err
+		if msg.err != nil {
+			return m, nil
+		}
+		m.goal = msg.goal
+		if isDueTodayFilterAt(*m.goal, m.now) {
+			m.wasDue = true
+		} else if m.wasDue && !m.celebrating {
+			m.celebrating = true
+			return m, tea.Batch(ringBellCmd(), tea.Tick(focusCelebrateFor, func(time.Time) tea.Msg { return tea.QuitMsg{} }))
+		}
+		return m, nil
+
+	case focusAddedMsg:
+		m.adding = false
+		m.err = msg.err
+		if msg.err != nil {
+			return m, nil
+		}
+		m.status = fmt.Sprintf("Added %s%s.", msg.value, unitsSuffix(m.goal.Gunits))
+		m.input.Reset()
+		return m, loadFocusGoalCmd(m.ctx, m.client, m.goalSlug)
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+// submit adds the entered value to the goal, through the before_add hook.
+func (m focusModel) submit() (tea.Model, tea.Cmd) {
+	raw := strings.TrimSpace(m.input.Value())
+	if m.goal == nil || m.adding || raw == "" {
+		return m, nil
+	}
+	var comment string
+	value, err := parseAddValue(raw, m.goal.Gunits)
+	if err == nil {
+		value, comment, err = activeHooks.BeforeAdd(*m.goal, value, "", io.Discard)
+	}
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.adding = true
+	m.err = nil
+	m.status = ""
+	return m, addFocusDatapointCmd(m.ctx, m.client, m.goal.Slug, value, comment)
+}
+
+// celebration is the message shown once the goal is safe.
+func (m focusModel) celebration() string {
+	msg := fmt.Sprintf("🎉 %s is safe for today!", m.goalSlug)
+	if m.goal != nil {
+		msg += fmt.Sprintf(" Next due %s.", FormatDueDateAt(m.goal.Losedate, m.now))
+	}
+	return msg
+}
+
+func (m focusModel) View() string {
+	title := lipgloss.NewStyle().Bold(true).Render(m.goalSlug)
+	switch {
+	case m.celebrating:
+		return "\n" + lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("10")).Padding(0, 2).Render(m.celebration()) + "\n"
+	case m.goal == nil && m.err == nil:
+		return title + "\n\nLoading goal...\n"
+	case m.goal == nil:
+		return title + "\n\n" + UrgencyOverdue.TextStyle().Render(fmt.Sprintf("Error: %s", redactError(m.err))) + "\n\nEsc to exit\n"
+	}
+
+	g := *m.goal
+	if g.Title != "" {
+		title += "  " + g.Title
+	}
+	style := UrgencyFor(g.Safebuf).TextStyle()
+	var sb strings.Builder
+	sb.WriteString(title + "\n\n")
+	sb.WriteString(style.Render(bigText(focusCountdown(g.Losedate, m.now))) + "\n")
+	needs := "safe for today"
+	if isDueTodayFilterAt(g, m.now) {
+		needs = planAmount(g) + " needed"
+	}
+	sb.WriteString(style.Render(fmt.Sprintf("Due %s · %s", FormatAbsoluteDeadlineAt(g.Losedate, m.now), needs)) + "\n")
+
+	sb.WriteString(renderGoalChart(g, max(m.width, minChartWidth+10)))
+	sb.WriteString(formatRecentDatapoints(g.Datapoints))
+	sb.WriteString("\n" + m.input.View() + "\n")
+	switch {
+	case m.adding:
+		sb.WriteString("Adding...\n")
+	case m.err != nil:
+		sb.WriteString(UrgencyOverdue.TextStyle().Render(fmt.Sprintf("Error: %s", redactError(m.err))) + "\n")
+	case m.status != "":
+		sb.WriteString(m.status + "\n")
+	}
+	sb.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render("Enter to add · Esc to exit") + "\n")
+	return sb.String()
+}
+
+// focusCountdown is the time left until losedate as H:MM:SS, or 0:00:00 once
+// it has passed.
+func focusCountdown(losedate int64, now time.Time) string {
+	left := time.Unix(losedate, 0).Sub(now)
+	if left < 0 {
+		left = 0
+	}
+	secs := int64(left / time.Second)
+	return fmt.Sprintf("%d:%02d:%02d", secs/3600, secs/60%60, secs%60)
+}
+
+// bigFont draws digits and colons three columns wide and five rows tall.
+var bigFont = map[rune][5]string{
+	'0': {"███", "█ █", "█ █", "█ █", "███"},
+	'1': {" ██", "  █", "  █", "  █", "  █"},
+	'2': {"███", "  █", "███", "█  ", "███"},
+	'3': {"███", "  █", "███", "  █", "███"},
+	'4': {"█ █", "█ █", "███", "  █", "  █"},
+	'5': {"███", "█  ", "███", "  █", "███"},
+	'6': {"███", "█  ", "███", "█ █", "███"},
+	'7': {"███", "  █", "  █", "  █", "  █"},
+	'8': {"███", "█ █", "███", "█ █", "███"},
+	'9': {"███", "█ █", "███", "  █", "███"},
+	':': {"   ", " █ ", "   ", " █ ", "   "},
+}
+
+// bigText draws s in bigFont, skipping characters it has no glyph for.
+func bigText(s string) string {
+	var rows [5]strings.Builder
+	for _, r := range s {
+		glyph, ok := bigFont[r]
+		if !ok {
+			continue
+		}
+		for i := range rows {
+			rows[i].WriteString(glyph[i] + " ")
+		}
+	}
+	lines := make([]string, len(rows))
+	for i := range rows {
+		lines[i] = strings.TrimRight(rows[i].String(), " ")
+	}
+	return strings.Join(lines, "\n")
+}
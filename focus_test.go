@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseFocusArgs(t *testing.T) {
+	for _, tt := range []struct {
+		args     []string
+		want     string
+		wantCode int
+		wantDone bool
+	}{
+		{[]string{"pushups"}, "pushups", 0, false},
+		{nil, "", 1, true},
+		{[]string{"pushups", "situps"}, "", 1, true},
+		{[]string{"--nope"}, "", 2, true},
+		{[]string{"--help"}, "", 0, true},
+	} {
+		slug, code, done := parseFocusArgs(tt.args, &bytes.Buffer{}, &bytes.Buffer{})
+		if slug != tt.want || code != tt.wantCode || done != tt.wantDone {
+			t.Errorf("parseFocusArgs(%v) = %q, %d, %v", tt.args, slug, code, done)
+		}
+	}
+}
+
+func TestFocusCountdown(t *testing.T) {
+	now := time.Date(2024, 1, 17, 9, 0, 0, 0, time.Local)
+	if got := focusCountdown(now.Add(2*time.Hour+3*time.Minute+4*time.Second).Unix(), now); got != "2:03:04" {
+		t.Errorf("focusCountdown = %q, want 2:03:04", got)
+	}
+	if got := focusCountdown(now.Add(-time.Minute).Unix(), now); got != "0:00:00" {
+		t.Errorf("focusCountdown after the deadline = %q, want 0:00:00", got)
+	}
+}
+
+func TestBigText(t *testing.T) {
+	lines := strings.Split(bigText("1:05"), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("bigText has %d rows, want 5", len(lines))
+	}
+	if lines[0] != " ██     ███ ███" {
+		t.Errorf("bigText top row = %q", lines[0])
+	}
+}
+
+func TestFocusModel(t *testing.T) {
+	now := time.Now()
+	due := &Goal{Slug: "pushups", Gunits: "pushups", Baremin: "+40", Safebuf: 0, Losedate: now.Add(time.Hour).Unix()}
+	safe := &Goal{Slug: "pushups", Gunits: "pushups", Safebuf: 1, Losedate: now.Add(30 * time.Hour).Unix()}
+	var added string
+	client := &FakeClient{
+		CreateDatapointFunc: func(slug, timestamp, value, comment, requestid string) (*Datapoint, error) {
+			added = slug + " " + value
+			return &Datapoint{}, nil
+		},
+	}
+	m := newFocusModel(context.Background(), client, "pushups")
+
+	updated, _ := m.Update(focusGoalMsg{goal: due})
+	m = updated.(focusModel)
+	if view := m.View(); !strings.Contains(view, "+40 pushups needed") || !strings.Contains(view, "Add: ") {
+		t.Errorf("view should show what's needed and the add field:\n%s", view)
+	}
+
+	for _, r := range "40" {
+		updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = updated.(focusModel)
+	}
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = updated.(focusModel)
+	if !m.adding || cmd == nil {
+		t.Fatal("enter should submit the value")
+	}
+	msg := cmd()
+	if added != "pushups 40" {
+		t.Errorf("added %q, want pushups 40", added)
+	}
+	updated, cmd = m.Update(msg)
+	m = updated.(focusModel)
+	if m.adding || m.status != "Added 40 pushups." || m.input.Value() != "" || cmd == nil {
+		t.Errorf("after adding: adding=%v status=%q input=%q", m.adding, m.status, m.input.Value())
+	}
+
+	updated, cmd = m.Update(focusGoalMsg{goal: safe})
+	m = updated.(focusModel)
+	if !m.celebrating || cmd == nil {
+		t.Fatal("the goal becoming safe should celebrate and exit")
+	}
+	if view := m.View(); !strings.Contains(view, "pushups is safe for today!") {
+		t.Errorf("celebration view:\n%s", view)
+	}
+}
+
+func TestFocusModelOpenedOnSafeGoal(t *testing.T) {
+	m := newFocusModel(context.Background(), &FakeClient{}, "reading")
+	updated, _ := m.Update(focusGoalMsg{goal: &Goal{Slug: "reading", Safebuf: 3, Losedate: time.Now().Add(72 * time.Hour).Unix()}})
+	m = updated.(focusModel)
+	if m.celebrating {
+		t.Error("a goal already safe when focus opens shouldn't celebrate")
+	}
+	if view := m.View(); !strings.Contains(view, "safe for today") {
+		t.Errorf("view should say the goal is safe:\n%s", view)
+	}
+}
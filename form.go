@@ -1,6 +1,9 @@
 package main
 
 import (
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 	"unicode"
 	"unicode/utf8"
@@ -103,11 +106,32 @@ func filterDecimal(char, _ string) bool {
 	return (char >= "0" && char <= "9") || char == "." || char == "-"
 }
 
+// filterRunits accepts a single rate-units code (y, m, w, d, or h).
+func filterRunits(char, cur string) bool {
+	return cur == "" && isKnownRunits(char)
+}
+
+// filterTimeValue accepts what filterDecimal does plus colons, for HH:MM
+// entry on goals measured in time.
+func filterTimeValue(char, cur string) bool {
+	return filterDecimal(char, cur) || char == ":"
+}
+
 // datapointForm is the in-progress datapoint entry shown inside the goal detail
 // modal: the date/value/comment fields plus whether a submission is in flight.
 type datapointForm struct {
 	form
+	gunits     string // the goal's units; times like 1:30 are accepted only when they measure time
 	submitting bool
+	// preview is the `?` simulation of adding the entry, computed for the
+	// date and value in previewFor; see previewText.
+	preview, previewFor string
+	// placeholder is the value shown while the goal's last value is being
+	// fetched ("" once it has arrived); see fillLastValue.
+	placeholder string
+	// celebration is shown once the submitted entry is added, when it takes
+	// the goal out of the red; see celebrationFor.
+	celebration string
 }
 
 // Field indices for datapointForm.
@@ -119,28 +143,107 @@ const (
 
 // newDatapointForm builds a datapoint entry form with sensible defaults.
 // defaultValue is the pre-filled value field (typically the goal's last
-// datapoint value, or "1").
-func newDatapointForm(defaultValue string) datapointForm {
+// datapoint value, or "1"); gunits are the goal's units.
+func newDatapointForm(defaultValue, gunits string) datapointForm {
+	valueFilter := filterDecimal
+	if isTimeUnits(gunits) {
+		valueFilter = filterTimeValue
+	}
 	fields := make([]field, 3)
 	fields[dpDate] = field{value: time.Now().Format("2006-01-02"), filter: filterDate}
-	fields[dpValue] = field{value: defaultValue, filter: filterDecimal}
+	fields[dpValue] = field{value: defaultValue, filter: valueFilter}
 	fields[dpComment] = field{value: "Added via buzz", filter: filterPrintable}
-	return datapointForm{form: form{fields: fields}}
+	return datapointForm{form: form{fields: fields}, gunits: gunits}
+}
+
+// rollDate moves the date field on to day (YYYY-MM-DD) when the day has
+// rolled over since prevDay, unless the user has already changed it.
+func (d *datapointForm) rollDate(prevDay, day string) {
+	if len(d.fields) == 0 || d.date() != prevDay {
+		return
+	}
+	d.fields[dpDate].value = day
+}
+
+// fillLastValue replaces the placeholder value with the goal's last datapoint
+// value once it has been fetched, unless the user has already changed it. A
+// failed fetch or a last value of 0 leaves the placeholder in place.
+func (d *datapointForm) fillLastValue(value float64, err error) {
+	placeholder := d.placeholder
+	d.placeholder = ""
+	if placeholder == "" || err != nil || value == 0 || d.value() != placeholder {
+		return
+	}
+	d.fields[dpValue].value = fmt.Sprintf("%.1f", value)
 }
 
 func (d *datapointForm) date() string    { return d.val(dpDate) }
 func (d *datapointForm) value() string   { return d.val(dpValue) }
 func (d *datapointForm) comment() string { return d.val(dpComment) }
 
+// submitValue returns the value to send to Beeminder: the value field, with a
+// time like 1:30 converted to the goal's units. Only meaningful once validate
+// passes.
+func (d *datapointForm) submitValue() string {
+	if isTimeFormat(d.value()) && isTimeUnits(d.gunits) {
+		if v, ok := timeToUnits(d.value(), d.gunits); ok {
+			return fmt.Sprintf("%.6g", v)
+		}
+	}
+	return normalizeDecimal(d.value())
+}
+
+// previewKey identifies the entry a preview was computed for.
+func (d *datapointForm) previewKey() string { return d.date() + "\x00" + d.value() }
+
+// previewText returns the preview, or "" once the date or value has changed
+// since it was computed.
+func (d *datapointForm) previewText() string {
+	if d.previewFor != d.previewKey() {
+		return ""
+	}
+	return d.preview
+}
+
+// fieldErrors reports what's wrong with each field as it stands, indexed like
+// the fields ("" for one that's fine), for the hints shown under the date and
+// value as they're typed.
+func (d *datapointForm) fieldErrors() []string {
+	errs := make([]string, len(d.fields))
+	if len(d.fields) == 0 {
+		return errs
+	}
+	errs[dpDate] = validateDatapointDate(d.date())
+	errs[dpValue] = validateDatapointValue(d.submitValue())
+	if isTimeFormat(d.value()) && isTimeUnits(d.gunits) {
+		if _, ok := timeToUnits(d.value(), d.gunits); !ok {
+			errs[dpValue] = "Invalid time (use HH:MM or HH:MM:SS)"
+		}
+	}
+	return errs
+}
+
 // validate reports a validation error message, or "" when the form is valid.
 func (d *datapointForm) validate() string {
-	return validateDatapointInput(d.date(), d.value())
+	if isTimeFormat(d.value()) && isTimeUnits(d.gunits) {
+		if _, ok := timeToUnits(d.value(), d.gunits); !ok {
+			return "Invalid time (use HH:MM or HH:MM:SS)"
+		}
+	}
+	return validateDatapointInput(d.date(), d.submitValue())
 }
 
 // createGoalForm is the in-progress new-goal entry shown in the create modal.
 type createGoalForm struct {
 	form
 	creating bool
+	// template names the preset the fields were filled from ("" for none);
+	// preset's deadline and tags are applied once the goal exists.
+	template string
+	preset   GoalTemplate
+	// fieldErrs holds a hint per field, indexed like the fields, for those
+	// found invalid when focus left them; see checkField.
+	fieldErrs []string
 }
 
 // Field indices for createGoalForm.
@@ -152,12 +255,14 @@ const (
 	cgGoaldate
 	cgGoalval
 	cgRate
+	cgRunits
 )
 
 // newCreateGoalForm builds a goal-creation form with the default goal type,
-// units, value, and rate pre-filled.
+// units, value, and rate pre-filled. The rate units start blank, for
+// Beeminder's default.
 func newCreateGoalForm() createGoalForm {
-	fields := make([]field, 7)
+	fields := make([]field, 8)
 	fields[cgSlug] = field{filter: filterSlug}
 	fields[cgTitle] = field{filter: filterPrintable}
 	fields[cgGoalType] = field{value: "hustler", filter: filterLetter}
@@ -165,9 +270,141 @@ func newCreateGoalForm() createGoalForm {
 	fields[cgGoaldate] = field{filter: filterIntOrNull}
 	fields[cgGoalval] = field{value: "0", filter: filterDecimalOrNull}
 	fields[cgRate] = field{value: "1", filter: filterDecimalOrNull}
+	fields[cgRunits] = field{filter: filterRunits}
 	return createGoalForm{form: form{fields: fields}}
 }
 
+// applyTemplate fills the goal type, units, and goal date/value/rate from
+// tmpl, or restores their defaults when name is "". The slug and title are
+// kept, so the user can pick a preset after typing them.
+func (c *createGoalForm) applyTemplate(name string, tmpl GoalTemplate) {
+	defaults := newCreateGoalForm()
+	for _, i := range []int{cgGoalType, cgGunits, cgGoaldate, cgGoalval, cgRate} {
+		c.fields[i].value = defaults.fields[i].value
+	}
+	c.template, c.preset = name, tmpl
+	if tmpl.Type != "" {
+		c.fields[cgGoalType].value = resolveGoalType(tmpl.Type)
+	}
+	if tmpl.Units != "" {
+		c.fields[cgGunits].value = tmpl.Units
+	}
+	if tmpl.hasTarget() {
+		goaldate, goalval, rate := tmpl.targetFields()
+		c.fields[cgGoaldate].value = goaldate
+		c.fields[cgGoalval].value = goalval
+		c.fields[cgRate].value = rate
+	}
+	for _, i := range []int{cgGoalType, cgGunits, cgGoaldate, cgGoalval, cgRate} {
+		c.recheckField(i)
+	}
+}
+
+// checkField validates field i, showing a hint under it if it's invalid and
+// clearing any hint if it's not. It runs as focus leaves a field.
+func (c *createGoalForm) checkField(i int) {
+	if i < 0 || i >= len(c.fields) {
+		return
+	}
+	if len(c.fieldErrs) != len(c.fields) {
+		c.fieldErrs = make([]string, len(c.fields))
+	}
+	c.fieldErrs[i] = validateCreateGoalField(i, c.val(i))
+}
+
+// recheckField revalidates field i if it's showing a hint, so the hint goes
+// as soon as the field is fixed rather than when focus next leaves it.
+func (c *createGoalForm) recheckField(i int) {
+	if c.fieldError(i) != "" {
+		c.checkField(i)
+	}
+}
+
+// fieldError returns the hint shown under field i, or "" for none.
+func (c *createGoalForm) fieldError(i int) string {
+	if i < 0 || i >= len(c.fieldErrs) {
+		return ""
+	}
+	return c.fieldErrs[i]
+}
+
+// fieldErrors returns the hints for every field, indexed like the fields.
+func (c *createGoalForm) fieldErrors() []string {
+	errs := make([]string, len(c.fields))
+	for i := range errs {
+		errs[i] = c.fieldError(i)
+	}
+	return errs
+}
+
+// tab completes a partly typed goal type and checks the field being left,
+// then moves focus as form.tab does.
+func (c *createGoalForm) tab(reverse bool) {
+	if c.focus == cgGoalType {
+		if name := completeGoalType(c.goalType()); name != "" {
+			c.fields[cgGoalType].value = name
+		}
+	}
+	c.checkField(c.focus)
+	c.form.tab(reverse)
+}
+
+// completion returns the rest of the goal type that tab would complete the
+// goal type field to, shown after what's been typed, or "" when the goal type
+// isn't focused or there's nothing to add.
+func (c *createGoalForm) completion() string {
+	if c.focus != cgGoalType {
+		return ""
+	}
+	typed := c.goalType()
+	name := completeGoalType(typed)
+	if len(name) <= len(typed) || !strings.HasPrefix(name, strings.ToLower(typed)) {
+		return ""
+	}
+	return name[len(typed):]
+}
+
+// cycleChoice replaces the focused goal type or rate units with the next of
+// its choices (the previous when delta is negative), wrapping around; the
+// rate units' choices include blank. It reports whether the focused field
+// has choices.
+func (c *createGoalForm) cycleChoice(delta int) bool {
+	var choices []string
+	switch c.focus {
+	case cgGoalType:
+		for _, gt := range goalTypeOptions {
+			choices = append(choices, gt.name)
+		}
+	case cgRunits:
+		choices = []string{""}
+		for _, ru := range runitsOptions {
+			choices = append(choices, ru.code)
+		}
+	default:
+		return false
+	}
+	i := slices.Index(choices, c.val(c.focus))
+	if i == -1 && delta < 0 {
+		i = 0
+	}
+	c.fields[c.focus].value = choices[((i+delta)%len(choices)+len(choices))%len(choices)]
+	c.recheckField(c.focus)
+	return true
+}
+
+// handleRune is form.handleRune, keeping the focused field's hint current.
+func (c *createGoalForm) handleRune(r rune) bool {
+	handled := c.form.handleRune(r)
+	c.recheckField(c.focus)
+	return handled
+}
+
+// backspace is form.backspace, keeping the focused field's hint current.
+func (c *createGoalForm) backspace() {
+	c.form.backspace()
+	c.recheckField(c.focus)
+}
+
 func (c *createGoalForm) slug() string     { return c.val(cgSlug) }
 func (c *createGoalForm) title() string    { return c.val(cgTitle) }
 func (c *createGoalForm) goalType() string { return c.val(cgGoalType) }
@@ -175,9 +412,63 @@ func (c *createGoalForm) gunits() string   { return c.val(cgGunits) }
 func (c *createGoalForm) goaldate() string { return c.val(cgGoaldate) }
 func (c *createGoalForm) goalval() string  { return c.val(cgGoalval) }
 func (c *createGoalForm) rate() string     { return c.val(cgRate) }
+func (c *createGoalForm) runits() string   { return c.val(cgRunits) }
 
 // validate reports a validation error message, or "" when the form is valid.
 func (c *createGoalForm) validate() string {
 	return validateCreateGoalInput(c.slug(), c.title(), c.goalType(), c.gunits(),
-		c.goaldate(), c.goalval(), c.rate())
+		c.goaldate(), c.goalval(), c.rate(), c.runits())
+}
+
+// chargeForm is the "charge yourself" form opened with $: the amount and
+// note, then a dry run of the charge, which must be confirmed before the real
+// one is made.
+type chargeForm struct {
+	form
+	checking bool    // the dry run is in flight
+	preview  *Charge // the dry run's result; non-nil while awaiting confirmation
+	charging bool    // the real charge is in flight
+	done     *Charge // the real charge, once made
+}
+
+// Field indices for chargeForm.
+const (
+	chAmount = iota
+	chNote
+)
+
+// newChargeForm builds an empty charge form with the amount focused.
+func newChargeForm() chargeForm {
+	fields := make([]field, 2)
+	fields[chAmount] = field{filter: filterChargeAmount}
+	fields[chNote] = field{filter: filterPrintable}
+	return chargeForm{form: form{fields: fields}}
+}
+
+// filterChargeAmount accepts digits and a decimal point: charges are positive.
+func filterChargeAmount(char, _ string) bool {
+	return (char >= "0" && char <= "9") || char == "."
+}
+
+func (c *chargeForm) amount() string { return c.val(chAmount) }
+func (c *chargeForm) note() string   { return c.val(chNote) }
+
+// busy reports whether a dry run or the real charge is in flight.
+func (c *chargeForm) busy() bool { return c.checking || c.charging }
+
+// editable reports whether the fields take input: not while busy, awaiting
+// confirmation, or done.
+func (c *chargeForm) editable() bool {
+	return !c.busy() && c.preview == nil && c.done == nil
+}
+
+// validate reports a validation error message, or "" when the form is valid.
+func (c *chargeForm) validate() string {
+	if _, err := parseChargeAmount(c.amount()); err != nil {
+		return err.Error()
+	}
+	if strings.TrimSpace(c.note()) == "" {
+		return "Note is required"
+	}
+	return ""
 }
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"strings"
 	"testing"
 	"unicode/utf8"
 )
@@ -129,7 +130,7 @@ func TestFormValOutOfRange(t *testing.T) {
 // TestDatapointFormDefaults verifies the datapoint form is constructed with the
 // expected defaults and the provided value.
 func TestDatapointFormDefaults(t *testing.T) {
-	d := newDatapointForm("7.5")
+	d := newDatapointForm("7.5", "")
 	if d.value() != "7.5" {
 		t.Errorf("value() = %q, want %q", d.value(), "7.5")
 	}
@@ -145,7 +146,7 @@ func TestDatapointFormDefaults(t *testing.T) {
 // the right characters via handleRune — the char-filter ↔ focus interaction the
 // issue says only surfaced in manual TUI use.
 func TestDatapointFormFieldFilters(t *testing.T) {
-	d := newDatapointForm("")
+	d := newDatapointForm("", "")
 	// Clear the constructor's pre-filled date/comment so each field starts empty.
 	d.fields[dpDate].value = ""
 	d.fields[dpComment].value = ""
@@ -282,7 +283,7 @@ func TestCreateGoalFormValidate(t *testing.T) {
 // TestDatapointFormValidate verifies validate() delegates to the existing
 // datapoint validator.
 func TestDatapointFormValidate(t *testing.T) {
-	d := newDatapointForm("5")
+	d := newDatapointForm("5", "")
 	if got := d.validate(); got != "" {
 		t.Errorf("validate() with defaults = %q, want no error", got)
 	}
@@ -292,3 +293,149 @@ func TestDatapointFormValidate(t *testing.T) {
 		t.Error("validate() should fail for non-numeric value")
 	}
 }
+
+// TestDatapointFormTimeValues verifies a goal measured in time accepts an
+// HH:MM value, converted to its units on submit, while other goals don't.
+func TestDatapointFormTimeValues(t *testing.T) {
+	for _, tt := range []struct {
+		gunits string
+		want   string
+	}{
+		{gunits: "hours", want: "1.5"},
+		{gunits: "Minutes", want: "90"},
+	} {
+		d := newDatapointForm("", tt.gunits)
+		d.tab(false)
+		typeInto(&d.form, "1:30")
+		if d.value() != "1:30" {
+			t.Fatalf("%s: value() = %q, want colon accepted", tt.gunits, d.value())
+		}
+		if got := d.validate(); got != "" {
+			t.Errorf("%s: validate() = %q, want no error", tt.gunits, got)
+		}
+		if got := d.submitValue(); got != tt.want {
+			t.Errorf("%s: submitValue() = %q, want %q", tt.gunits, got, tt.want)
+		}
+	}
+
+	d := newDatapointForm("", "hours")
+	d.fields[dpValue].value = "1:75"
+	if got := d.validate(); !strings.Contains(got, "Invalid time") {
+		t.Errorf("validate() for 1:75 = %q, want invalid time", got)
+	}
+
+	d = newDatapointForm("", "pages")
+	d.tab(false)
+	typeInto(&d.form, "1:30")
+	if d.value() != "130" {
+		t.Errorf("value() = %q, want colon rejected for a pages goal", d.value())
+	}
+}
+
+// TestDatapointFormFieldErrors verifies the date and value hints follow the
+// fields as they're typed.
+func TestDatapointFormFieldErrors(t *testing.T) {
+	d := newDatapointForm("1", "")
+	if errs := d.fieldErrors(); errs[dpDate] != "" || errs[dpValue] != "" {
+		t.Fatalf("fresh form has hints %q", errs)
+	}
+
+	d.focus = dpValue
+	d.backspace()
+	d.handleRune('-')
+	if got := d.fieldErrors()[dpValue]; got != "Value must be a valid number" {
+		t.Errorf("value hint for %q = %q", d.value(), got)
+	}
+	d.handleRune('2')
+	if got := d.fieldErrors()[dpValue]; got != "" {
+		t.Errorf("value hint for %q = %q, want none", d.value(), got)
+	}
+
+	d.focus = dpDate
+	d.backspace()
+	if got := d.fieldErrors()[dpDate]; got != "Invalid date format (use YYYY-MM-DD)" {
+		t.Errorf("date hint for %q = %q", d.date(), got)
+	}
+
+	hours := newDatapointForm("1:75", "hours")
+	if got := hours.fieldErrors()[dpValue]; got != "Invalid time (use HH:MM or HH:MM:SS)" {
+		t.Errorf("value hint for 1:75 hours = %q", got)
+	}
+}
+
+// TestCreateGoalFormFieldErrors verifies a create-goal field is checked as
+// focus leaves it, and its hint clears as soon as it's fixed.
+func TestCreateGoalFormFieldErrors(t *testing.T) {
+	c := newCreateGoalForm()
+	typeInto(&c.form, "x")
+	if c.fieldError(cgSlug) != "" {
+		t.Fatal("a field shouldn't be checked before focus leaves it")
+	}
+	c.backspace()
+	c.tab(false)
+	if got := c.fieldError(cgSlug); got != "Slug cannot be empty" {
+		t.Errorf("slug hint = %q", got)
+	}
+
+	c.tab(true)
+	c.handleRune('p')
+	if got := c.fieldError(cgSlug); got != "" {
+		t.Errorf("slug hint after typing = %q, want none", got)
+	}
+
+	c.focus = cgGoaldate
+	c.fields[cgGoaldate].value = "12.5"
+	c.tab(false)
+	if got := c.fieldError(cgGoaldate); got != "Goal date must be a valid epoch timestamp or 'null'" {
+		t.Errorf("goal date hint = %q", got)
+	}
+	if got := c.fieldErrors(); len(got) != len(c.fields) || got[cgGoaldate] == "" {
+		t.Errorf("fieldErrors() = %q", got)
+	}
+}
+
+// TestCreateGoalFormChoices verifies the goal type completes as it's typed
+// and on Tab, and that the goal type and rate units cycle through their
+// choices.
+func TestCreateGoalFormChoices(t *testing.T) {
+	c := newCreateGoalForm()
+	c.focus = cgGoalType
+	c.fields[cgGoalType].value = ""
+	typeInto(&c.form, "hus")
+	if got := c.completion(); got != "tler" {
+		t.Errorf("completion of %q = %q, want %q", c.goalType(), got, "tler")
+	}
+	c.tab(false)
+	if c.goalType() != "hustler" || c.fieldError(cgGoalType) != "" {
+		t.Errorf("goal type after Tab = %q (hint %q), want hustler", c.goalType(), c.fieldError(cgGoalType))
+	}
+	if got := c.completion(); got != "" {
+		t.Errorf("completion with the units focused = %q, want none", got)
+	}
+
+	c.focus = cgGoalType
+	if !c.cycleChoice(1) || c.goalType() != "drinker" {
+		t.Errorf("Down from hustler = %q, want drinker", c.goalType())
+	}
+	c.cycleChoice(-1)
+	c.cycleChoice(-1)
+	if c.goalType() != "custom" {
+		t.Errorf("Up twice from drinker = %q, want custom", c.goalType())
+	}
+
+	c.focus = cgRunits
+	for _, want := range []string{"y", "m", "w", "d", "h", ""} {
+		c.cycleChoice(1)
+		if c.runits() != want {
+			t.Fatalf("rate units = %q, want %q", c.runits(), want)
+		}
+	}
+	if typeInto(&c.form, "xd")[0] || c.runits() != "d" {
+		t.Errorf("rate units accepted %q", c.runits())
+	}
+
+	c.focus = cgSlug
+	if c.cycleChoice(1) {
+		t.Error("the slug has no choices to cycle")
+	}
+}
@@ -9,6 +9,9 @@ require (
 	github.com/charmbracelet/x/term v0.2.1
 	github.com/guptarohit/asciigraph v0.9.0
 	github.com/muesli/termenv v0.16.0
+	go.starlark.net v0.0.0-20260210143700-b62fd896b91b
+	gopkg.in/yaml.v3 v3.0.1
+	rsc.io/qr v0.2.0
 )
 
 require (
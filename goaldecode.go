@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// Beeminder occasionally sends a goal with a field buzz can't read: a custom
+// goal's roadall with an odd entry, a number as a string, a null where a
+// number belongs. Decoding a goal is defensive about it: a field that won't
+// decode is left at its zero value and recorded as a warning rather than
+// failing the goal (and with it the whole goal list), and a null in a field
+// whose zero value would read as real data is recorded too. goalWarnings
+// collects them for the goal details, so odd data is called out instead of
+// drawn as though it were right.
+
+// goalJSON is Goal without its UnmarshalJSON, to decode into.
+type goalJSON Goal
+
+// goalNullables holds the fields that are null in a goal as sent, among those
+// whose zero value would pass for real data: a null losedate reads as due in
+// 1970, a null safebuf as overdue.
+type goalNullables struct {
+	Losedate json.RawMessage `json:"losedate"`
+	Safebuf  json.RawMessage `json:"safebuf"`
+	Pledge   json.RawMessage `json:"pledge"`
+	Yaw      json.RawMessage `json:"yaw"`
+}
+
+// UnmarshalJSON decodes a goal, recording the fields that couldn't be read
+// instead of failing. It fails only when data isn't a JSON object.
+func (g *Goal) UnmarshalJSON(data []byte) error {
+	var warnings []string
+	if err := json.Unmarshal(data, (*goalJSON)(g)); err != nil {
+		var fields map[string]json.RawMessage
+		if json.Unmarshal(data, &fields) != nil {
+			return err
+		}
+		// Decode field by field, keeping the ones that decode.
+		*g = Goal{}
+		keys := make([]string, 0, len(fields))
+		for key := range fields {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			name, _ := json.Marshal(key)
+			field := []byte(fmt.Sprintf("{%s:%s}", name, fields[key]))
+			var probe goalJSON
+			if err := json.Unmarshal(field, &probe); err != nil {
+				warnings = append(warnings, fieldWarning(key, err))
+				continue
+			}
+			json.Unmarshal(field, (*goalJSON)(g))
+		}
+	}
+
+	var nullables goalNullables
+	if json.Unmarshal(data, &nullables) == nil {
+		for _, f := range []struct {
+			name  string
+			value json.RawMessage
+		}{
+			{"losedate", nullables.Losedate},
+			{"safebuf", nullables.Safebuf},
+			{"pledge", nullables.Pledge},
+			{"yaw", nullables.Yaw},
+		} {
+			if string(f.value) == "null" {
+				warnings = append(warnings, f.name+" is missing (null)")
+			}
+		}
+	}
+
+	g.warnings = warnings
+	return nil
+}
+
+// fieldWarning describes a field that didn't decode.
+func fieldWarning(key string, err error) string {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return fmt.Sprintf("%s couldn't be read (a %s where %s was expected)", key, typeErr.Value, typeErr.Type)
+	}
+	return fmt.Sprintf("%s couldn't be read (%v)", key, err)
+}
+
+// goalWarnings lists what buzz couldn't make sense of in the goal: fields that
+// didn't decode or were null, and a malformed bright red line.
+func goalWarnings(g Goal) []string {
+	warnings := slices.Clone(g.warnings)
+	if _, err := parseRoad(g.Roadall, g.Runits); err != nil {
+		warnings = append(warnings, "bright red line couldn't be read: "+err.Error())
+	}
+	return warnings
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGoalDecodingIsDefensive(t *testing.T) {
+	data := `[
+		{"slug": "fine", "losedate": 100, "safebuf": 3, "pledge": 5, "yaw": 1},
+		{"slug": "odd", "title": "Odd", "losedate": "soon", "safebuf": null, "pledge": 5, "yaw": 1,
+		 "roadall": [[1, 0, null], [2, "x", null]]},
+		{"slug": "crooked", "losedate": 100, "safebuf": 3, "pledge": 5, "yaw": 1,
+		 "roadall": [[1, 0, null], [2, 1, 1]]}
+	]`
+	var goals []Goal
+	if err := json.Unmarshal([]byte(data), &goals); err != nil {
+		t.Fatalf("one odd goal failed the list: %v", err)
+	}
+	if len(goals) != 3 {
+		t.Fatalf("decoded %d goals, want 3", len(goals))
+	}
+
+	if w := goalWarnings(goals[0]); len(w) != 0 {
+		t.Errorf("a well-formed goal has warnings: %v", w)
+	}
+
+	// The odd goal keeps the fields that decoded and warns about the rest.
+	odd := goals[1]
+	if odd.Slug != "odd" || odd.Title != "Odd" || odd.Pledge != 5 || odd.Losedate != 0 || odd.Roadall != nil {
+		t.Errorf("odd goal decoded as %+v", odd)
+	}
+	want := []string{
+		"losedate couldn't be read (a string where int64 was expected)",
+		"roadall couldn't be read (a string where float64 was expected)",
+		"safebuf is missing (null)",
+	}
+	if got := goalWarnings(odd); !slices.Equal(got, want) {
+		t.Errorf("odd goal's warnings = %q, want %q", got, want)
+	}
+
+	// A road that decodes but doesn't make sense is called out too.
+	if got := goalWarnings(goals[2]); len(got) != 1 || !strings.HasPrefix(got[0], "bright red line couldn't be read: road row 1") {
+		t.Errorf("crooked goal's warnings = %q", got)
+	}
+
+	var g Goal
+	if err := json.Unmarshal([]byte(`"not a goal"`), &g); err == nil {
+		t.Error("a goal that isn't an object should fail to decode")
+	}
+}
+
+func TestGoalWarningsShown(t *testing.T) {
+	var g Goal
+	if err := json.Unmarshal([]byte(`{"slug": "odd", "losedate": null}`), &g); err != nil {
+		t.Fatal(err)
+	}
+	if content := goalDetailContent(&g, "", "", "", 0, false, "", nil, "", false, ""); !strings.Contains(content, "⚠ losedate is missing (null)") {
+		t.Errorf("goal details lack the warning:\n%s", content)
+	}
+	if details := formatGoalDetails(&g, &Config{Username: "u"}, time.Now()); !strings.Contains(details, "Warnings:") {
+		t.Errorf("review details lack the warning:\n%s", details)
+	}
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const goalsUsage = `Usage: buzz goals [--color <color>] [--min-pledge <amount>] [--type <type>]
+                  [--sort due|slug|pledge|safebuf] [--columns <list>] [--by-group]`
+
+// goalsColumnNames lists the columns `buzz goals --columns` can show, in the
+// order `buzz goals --help` lists them.
+var goalsColumnNames = []string{"slug", "title", "type", "units", "rate", "pledge", "baremin", "safebuf", "due", "deadline", "autodata"}
+
+// defaultGoalsColumns are the columns `buzz goals` shows without --columns.
+var defaultGoalsColumns = []string{"slug", "pledge", "baremin", "safebuf", "deadline", "autodata"}
+
+// goalsSorts lists the --sort orders; "due" is the order every other list
+// command uses.
+var goalsSorts = []string{"due", "slug", "pledge", "safebuf"}
+
+// urgencyColors lists the --color names, most urgent first.
+var urgencyColors = []string{"red", "orange", "blue", "green", "gray"}
+
+// goalsOptions holds the parsed `buzz goals` flags.
+type goalsOptions struct {
+	color     string   // urgency colour to keep ("" for any)
+	minPledge float64  // smallest pledge to keep
+	goalType  string   // goal type to keep ("" for any)
+	sort      string   // one of goalsSorts
+	columns   []string // columns to show, from goalsColumnNames
+	byGroup   bool     // list the goals in a section per group
+}
+
+// handleGoalsCommand lists goals with the columns and filters given by flags.
+func handleGoalsCommand() {
+	opts, code, done := parseGoalsArgs(os.Args[2:], os.Stdout, os.Stderr)
+	if done {
+		os.Exit(code)
+	}
+
+	client, ok := loadClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+
+	code = runGoalsCommand(context.Background(), client, opts, goalFilter, outputFormat, time.Now(), os.Stdout, os.Stderr)
+	if code == 0 && outputFormat == "table" {
+		fmt.Print(getUpdateMessage())
+	}
+	os.Exit(code)
+}
+
+// parseGoalsArgs parses `buzz goals` arguments, returning the options, a
+// process exit code, and done=true when the caller should stop (help shown,
+// or a usage error).
+func parseGoalsArgs(args []string, stdout, stderr io.Writer) (goalsOptions, int, bool) {
+	goalsFlags := flag.NewFlagSet("goals", flag.ContinueOnError)
+	// Silence the flag package's own output; we print our own usage.
+	goalsFlags.SetOutput(io.Discard)
+	color := goalsFlags.String("color", "", "Only goals of this urgency colour")
+	minPledge := goalsFlags.Float64("min-pledge", 0, "Only goals pledging at least this much")
+	goalType := goalsFlags.String("type", "", "Only goals of this type")
+	sortBy := goalsFlags.String("sort", "due", "Sort order")
+	columns := goalsFlags.String("columns", strings.Join(defaultGoalsColumns, ","), "Comma-separated columns to show")
+	byGroup := goalsFlags.Bool("by-group", false, "List goals in a section per group")
+	if err := goalsFlags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stdout, goalsUsage)
+			return goalsOptions{}, 0, true
+		}
+		fmt.Fprintf(stderr, "Error parsing flags: %s\n", err)
+		fmt.Fprintln(stderr, goalsUsage)
+		return goalsOptions{}, 2, true
+	}
+
+	fail := func(format string, a ...any) (goalsOptions, int, bool) {
+		fmt.Fprintf(stderr, "Error: "+format+"\n", a...)
+		fmt.Fprintln(stderr, goalsUsage)
+		return goalsOptions{}, 1, true
+	}
+	if goalsFlags.NArg() > 0 {
+		return fail("unexpected argument %q", goalsFlags.Arg(0))
+	}
+	opts := goalsOptions{
+		color:     strings.ToLower(*color),
+		minPledge: *minPledge,
+		goalType:  strings.ToLower(*goalType),
+		sort:      strings.ToLower(*sortBy),
+		byGroup:   *byGroup,
+	}
+	if opts.color != "" && !slices.Contains(urgencyColors, opts.color) {
+		return fail("invalid --color %q (want %s)", *color, strings.Join(urgencyColors, ", "))
+	}
+	if opts.minPledge < 0 {
+		return fail("--min-pledge can't be negative")
+	}
+	if !slices.Contains(goalsSorts, opts.sort) {
+		return fail("invalid --sort %q (want %s)", *sortBy, strings.Join(goalsSorts, ", "))
+	}
+	for _, name := range strings.Split(*columns, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if !slices.Contains(goalsColumnNames, name) {
+			return fail("unknown column %q (want %s)", name, strings.Join(goalsColumnNames, ", "))
+		}
+		opts.columns = append(opts.columns, name)
+	}
+	if len(opts.columns) == 0 {
+		return fail("--columns needs at least one column")
+	}
+	return opts, 0, false
+}
+
+// matchingGoals returns the goals that pass opts' filters and keep (the global
+// --filter), in opts' sort order.
+func matchingGoals(goals []Goal, opts goalsOptions, keep func(Goal) bool) []Goal {
+	var matched []Goal
+	for _, g := range keepGoals(goals, keep) {
+		if opts.color != "" && UrgencyFor(g.Safebuf).String() != opts.color {
+			continue
+		}
+		if g.Pledge < opts.minPledge {
+			continue
+		}
+		if opts.goalType != "" && !strings.EqualFold(g.GoalType, opts.goalType) {
+			continue
+		}
+		matched = append(matched, g)
+	}
+
+	// SortGoals first, so the other orders break ties the usual way.
+	SortGoals(matched)
+	switch opts.sort {
+	case "slug":
+		SortGoalsBySlug(matched)
+	case "pledge":
+		sort.SliceStable(matched, func(i, j int) bool { return matched[i].Pledge > matched[j].Pledge })
+	case "safebuf":
+		sort.SliceStable(matched, func(i, j int) bool { return matched[i].Safebuf < matched[j].Safebuf })
+	}
+	return matched
+}
+
+// goalsColumn returns the table column `buzz goals` shows for name.
+func goalsColumn(name string, now time.Time) Column {
+	orDash := func(s string) string {
+		if s == "" {
+			return "-"
+		}
+		return s
+	}
+	switch name {
+	case "title":
+		return Column{Header: "Title", Cell: func(g Goal) string { return orDash(g.Title) }}
+	case "type":
+		return Column{Header: "Type", Cell: func(g Goal) string { return orDash(g.GoalType) }}
+	case "units":
+		return Column{Header: "Units", Cell: func(g Goal) string { return getDisplayUnits(g.Gunits) }}
+	case "rate":
+		return Column{Header: "Rate", Cell: func(g Goal) string { return formatListRate(g.Rate, g.Runits) }}
+	case "pledge":
+		return Column{Header: "Pledge", Cell: func(g Goal) string { return "$" + formatPledge(g.Pledge) }}
+	case "baremin":
+		return Column{Header: "Baremin", Cell: func(g Goal) string { return g.Baremin }}
+	case "safebuf":
+		return Column{Header: "Safebuf", Cell: func(g Goal) string { return strconv.Itoa(g.Safebuf) }}
+	case "due":
+		return Column{Header: "Due", Cell: func(g Goal) string { return FormatDueDateAt(g.Losedate, now) }}
+	case "deadline":
+		return Column{Header: "Deadline", Cell: func(g Goal) string { return FormatAbsoluteDeadline(g.Losedate) }}
+	case "autodata":
+		return Column{Header: "Autodata", Cell: func(g Goal) string { return orDash(g.Autodata) }}
+	default:
+		return Column{Header: "Slug", Cell: func(g Goal) string { return g.Slug }}
+	}
+}
+
+// runGoalsCommand fetches the goals and prints those matching opts. It
+// returns the process exit code.
+func runGoalsCommand(ctx context.Context, client Client, opts goalsOptions, keep func(Goal) bool, format string, now time.Time, out, errOut io.Writer) int {
+	goals, err := client.FetchGoals(ctx)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: Failed to fetch goals: %s\n", redactError(err))
+		return 1
+	}
+	matched := matchingGoals(goals, opts, keep)
+
+	table := Table{ShowHeader: true, Colorize: true}
+	for _, name := range opts.columns {
+		table.Columns = append(table.Columns, goalsColumn(name, now))
+	}
+
+	// Machine-readable formats: emit just the goals, like the list commands.
+	if format == "jsonl" {
+		if err := writeJSONL(out, matched); err != nil {
+			fmt.Fprintf(errOut, "Error: %s\n", redactError(err))
+			return 1
+		}
+		return 0
+	}
+	if format != "table" {
+		rendered, err := table.RenderAs(format, matched)
+		if err != nil {
+			fmt.Fprintf(errOut, "Error: %s\n", redactError(err))
+			return 1
+		}
+		fmt.Fprint(out, rendered)
+		return 0
+	}
+
+	if len(matched) == 0 {
+		fmt.Fprintln(out, "No goals match.")
+		return 0
+	}
+	rendered := table.Render(matched)
+	if opts.byGroup {
+		rendered = groupSections(rendered, matched)
+	}
+	fmt.Fprint(out, rendered)
+	return 0
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseGoalsArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantDone bool
+		wantCode int
+		wantErr  string
+	}{
+		{name: "defaults", args: nil},
+		{name: "all flags", args: []string{"--color", "Red", "--min-pledge=30", "--type", "drinker", "--sort", "pledge", "--columns", "slug, title"}},
+		{name: "help", args: []string{"--help"}, wantDone: true},
+		{name: "bad color", args: []string{"--color", "purple"}, wantDone: true, wantCode: 1, wantErr: `invalid --color "purple"`},
+		{name: "bad sort", args: []string{"--sort", "name"}, wantDone: true, wantCode: 1, wantErr: `invalid --sort "name"`},
+		{name: "bad column", args: []string{"--columns", "slug,color"}, wantDone: true, wantCode: 1, wantErr: `unknown column "color"`},
+		{name: "no columns", args: []string{"--columns", ""}, wantDone: true, wantCode: 1, wantErr: "at least one column"},
+		{name: "negative pledge", args: []string{"--min-pledge", "-5"}, wantDone: true, wantCode: 1, wantErr: "can't be negative"},
+		{name: "bad pledge", args: []string{"--min-pledge", "lots"}, wantDone: true, wantCode: 2, wantErr: "Error parsing flags"},
+		{name: "extra", args: []string{"reading"}, wantDone: true, wantCode: 1, wantErr: `unexpected argument "reading"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errb bytes.Buffer
+			_, code, done := parseGoalsArgs(tt.args, &out, &errb)
+			if done != tt.wantDone || code != tt.wantCode {
+				t.Fatalf("code=%d done=%v, want %d %v", code, done, tt.wantCode, tt.wantDone)
+			}
+			if !strings.Contains(errb.String(), tt.wantErr) {
+				t.Errorf("stderr = %q, want %q", errb.String(), tt.wantErr)
+			}
+		})
+	}
+
+	opts, _, _ := parseGoalsArgs([]string{"--color", "Red", "--columns", "slug, Title"}, &bytes.Buffer{}, &bytes.Buffer{})
+	if opts.color != "red" || opts.sort != "due" || strings.Join(opts.columns, ",") != "slug,title" {
+		t.Errorf("opts = %+v", opts)
+	}
+	opts, _, _ = parseGoalsArgs(nil, &bytes.Buffer{}, &bytes.Buffer{})
+	if strings.Join(opts.columns, ",") != strings.Join(defaultGoalsColumns, ",") {
+		t.Errorf("default columns = %v", opts.columns)
+	}
+}
+
+func TestMatchingGoals(t *testing.T) {
+	goals := []Goal{
+		{Slug: "beer", GoalType: "drinker", Pledge: 30, Safebuf: 0, Losedate: 300},
+		{Slug: "write", GoalType: "hustler", Pledge: 90, Safebuf: 0, Losedate: 200},
+		{Slug: "run", GoalType: "biker", Pledge: 5, Safebuf: 4, Losedate: 100},
+		{Slug: "read", GoalType: "hustler", Pledge: 10, Safebuf: 10, Losedate: 400},
+	}
+	slugs := func(opts goalsOptions) string {
+		var s []string
+		for _, g := range matchingGoals(goals, opts, nil) {
+			s = append(s, g.Slug)
+		}
+		return strings.Join(s, ",")
+	}
+	for _, tt := range []struct {
+		opts goalsOptions
+		want string
+	}{
+		{goalsOptions{sort: "due"}, "run,write,beer,read"},
+		{goalsOptions{sort: "slug"}, "beer,read,run,write"},
+		{goalsOptions{sort: "pledge"}, "write,beer,read,run"},
+		{goalsOptions{sort: "safebuf"}, "write,beer,run,read"},
+		{goalsOptions{sort: "due", color: "red"}, "write,beer"},
+		{goalsOptions{sort: "due", minPledge: 10}, "write,beer,read"},
+		{goalsOptions{sort: "due", goalType: "hustler"}, "write,read"},
+	} {
+		if got := slugs(tt.opts); got != tt.want {
+			t.Errorf("matchingGoals(%+v) = %s, want %s", tt.opts, got, tt.want)
+		}
+	}
+}
+
+func TestRunGoalsCommand(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	goals := []Goal{
+		{Slug: "write", Title: "Write daily", Pledge: 90, Baremin: "+1", Losedate: now.Add(3 * time.Hour).Unix()},
+		{Slug: "steps", Pledge: 5, Safebuf: 8, Autodata: "fitbit", Losedate: now.Add(200 * time.Hour).Unix()},
+	}
+	client := &FakeClient{FetchGoalsFunc: func() ([]Goal, error) { return goals, nil }}
+
+	var out, errb bytes.Buffer
+	opts := goalsOptions{sort: "due", columns: []string{"slug", "pledge", "autodata"}}
+	code := runGoalsCommand(context.Background(), client, opts, nil, "table", now, &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 0, "Slug", "")
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 || strings.Join(strings.Fields(lines[3]), " ") != "steps $5 fitbit" {
+		t.Errorf("table =\n%s", out.String())
+	}
+
+	out.Reset()
+	opts.columns = []string{"slug", "title"}
+	code = runGoalsCommand(context.Background(), client, opts, nil, "csv", now, &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 0, "", "")
+	if out.String() != "Slug,Title\nwrite,Write daily\nsteps,-\n" {
+		t.Errorf("csv = %q", out.String())
+	}
+
+	out.Reset()
+	opts.color = "orange"
+	code = runGoalsCommand(context.Background(), client, opts, nil, "table", now, &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 0, "No goals match.", "")
+
+	client.FetchGoalsFunc = nil
+	code = runGoalsCommand(context.Background(), client, opts, nil, "table", now, &out, &errb)
+	checkResult(t, code, "", errb.String(), 1, "", "Failed to fetch goals")
+}
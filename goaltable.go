@@ -4,6 +4,7 @@ import (
 	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
 )
 
@@ -140,7 +141,31 @@ func (t Table) RenderAs(format string, goals []Goal) (string, error) {
 		}
 		return encodeCSV(headers, rows)
 	default:
-		return "", fmt.Errorf("unknown format %q (want table, json, or csv)", format)
+		return "", fmt.Errorf("unknown format %q (want table, json, jsonl, or csv)", format)
+	}
+}
+
+// writeJSONL writes items to w as JSON Lines: one compact object per line, so
+// jq and friends can process the output a line at a time. An empty list
+// writes nothing. Lists fetched a piece at a time write each piece with
+// jsonLines instead.
+func writeJSONL[T any](w io.Writer, items []T) error {
+	write := jsonLines[T](w)
+	for _, item := range items {
+		if err := write(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// jsonLines returns a func that writes each item it's given to w as a line of
+// JSON Lines straight away, so a goal or datapoint reaches jq as soon as it has
+// been fetched rather than once they all have.
+func jsonLines[T any](w io.Writer) func(T) error {
+	enc := json.NewEncoder(w)
+	return func(item T) error {
+		return enc.Encode(item)
 	}
 }
 
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+const graphUsage = `Usage: buzz graph <goalslug> [--days <n|all>] [--width <columns>] [--zoom]
+  Print a goal's chart, as the review screen draws it, and exit.`
+
+// defaultGraphWidth is how wide `buzz graph` draws the chart, axis labels
+// included, without --width.
+const defaultGraphWidth = 80
+
+// graphRequest holds the parsed `buzz graph` arguments.
+type graphRequest struct {
+	goalSlug string
+	width    int
+	window   chartWindow
+}
+
+// handleGraphCommand prints a goal's chart.
+func handleGraphCommand() {
+	req, code, done := parseGraphArgs(os.Args[2:], os.Stdout, os.Stderr)
+	if done {
+		os.Exit(code)
+	}
+
+	client, ok := loadClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+	os.Exit(runGraphCommand(context.Background(), client, req, os.Stdout, os.Stderr))
+}
+
+// parseGraphArgs parses `buzz graph` arguments, returning the request, a
+// process exit code, and done=true when the caller should stop (help shown,
+// or a usage error). Flags may come before or after the goal.
+func parseGraphArgs(args []string, stdout, stderr io.Writer) (graphRequest, int, bool) {
+	graphFlags := flag.NewFlagSet("graph", flag.ContinueOnError)
+	// Silence the flag package's own output; we print our own usage.
+	graphFlags.SetOutput(io.Discard)
+	days := graphFlags.String("days", "", "Chart the last n days, or all of them")
+	width := graphFlags.Int("width", defaultGraphWidth, "Width of the chart in columns")
+	zoom := graphFlags.Bool("zoom", false, "Scale the Y axis to the datapoints")
+
+	// Re-parse after each positional, as view does, so flags can follow the
+	// goal: `buzz graph pushups --days 60`.
+	var positional []string
+	for remaining := args; ; {
+		if err := graphFlags.Parse(remaining); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				fmt.Fprintln(stdout, graphUsage)
+				return graphRequest{}, 0, true
+			}
+			fmt.Fprintf(stderr, "Error parsing flags: %s\n", err)
+			fmt.Fprintln(stderr, graphUsage)
+			return graphRequest{}, 2, true
+		}
+		if graphFlags.NArg() == 0 {
+			break
+		}
+		positional = append(positional, graphFlags.Arg(0))
+		remaining = graphFlags.Args()[1:]
+	}
+	if len(positional) != 1 {
+		fmt.Fprintln(stderr, "Error: buzz graph takes one goal")
+		fmt.Fprintln(stderr, graphUsage)
+		return graphRequest{}, 1, true
+	}
+
+	req := graphRequest{goalSlug: positional[0], width: *width, window: chartWindow{zoomY: *zoom}}
+	if *width < minChartWidth+10 {
+		fmt.Fprintf(stderr, "Error: invalid --width %d (want at least %d)\n", *width, minChartWidth+10)
+		return graphRequest{}, 1, true
+	}
+	switch *days {
+	case "":
+	case "all":
+		req.window.days = chartAllDays
+	default:
+		n, err := strconv.Atoi(*days)
+		if err != nil || n < 1 {
+			fmt.Fprintf(stderr, "Error: invalid --days %q (want a number of days, or all)\n", *days)
+			return graphRequest{}, 1, true
+		}
+		req.window.days = n
+	}
+	return req, 0, false
+}
+
+// runGraphCommand fetches the goal and prints its chart. It returns the
+// process exit code.
+func runGraphCommand(ctx context.Context, client Client, req graphRequest, out, errOut io.Writer) int {
+	goal, err := client.FetchGoalWithDatapoints(ctx, req.goalSlug)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: %s\n", redactError(err))
+		return 1
+	}
+	chart := plotGoalChart(*goal, req.width-10, req.window)
+	if chart == "" {
+		fmt.Fprintf(errOut, "Error: %s has no datapoints to chart\n", goal.Slug)
+		return 1
+	}
+	// asciigraph colours its series itself, so honour --no-color here.
+	if lipgloss.ColorProfile() == termenv.Ascii {
+		chart = ansiPattern.ReplaceAllString(chart, "")
+	}
+	fmt.Fprint(out, chart)
+	return 0
+}
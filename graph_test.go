@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+func TestParseGraphArgs(t *testing.T) {
+	for _, tt := range []struct {
+		args     []string
+		want     graphRequest
+		wantCode int
+		wantDone bool
+	}{
+		{[]string{"pushups"}, graphRequest{goalSlug: "pushups", width: defaultGraphWidth}, 0, false},
+		{[]string{"pushups", "--days", "60", "--width", "100"}, graphRequest{goalSlug: "pushups", width: 100, window: chartWindow{days: 60}}, 0, false},
+		{[]string{"--days", "all", "--zoom", "pushups"}, graphRequest{goalSlug: "pushups", width: defaultGraphWidth, window: chartWindow{days: chartAllDays, zoomY: true}}, 0, false},
+		{[]string{"pushups", "--days", "0"}, graphRequest{}, 1, true},
+		{[]string{"pushups", "--width", "20"}, graphRequest{}, 1, true},
+		{[]string{"pushups", "situps"}, graphRequest{}, 1, true},
+		{nil, graphRequest{}, 1, true},
+		{[]string{"pushups", "--nope"}, graphRequest{}, 2, true},
+		{[]string{"--help"}, graphRequest{}, 0, true},
+	} {
+		req, code, done := parseGraphArgs(tt.args, &bytes.Buffer{}, &bytes.Buffer{})
+		if req != tt.want || code != tt.wantCode || done != tt.wantDone {
+			t.Errorf("parseGraphArgs(%v) = %+v, %d, %v", tt.args, req, code, done)
+		}
+	}
+}
+
+func TestRunGraphCommand(t *testing.T) {
+	prev := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.Ascii)
+	t.Cleanup(func() { lipgloss.SetColorProfile(prev) })
+
+	now := time.Now()
+	yesterday := now.AddDate(0, 0, -1)
+	goal := &Goal{
+		Slug: "pushups",
+		Datapoints: []Datapoint{
+			{Timestamp: yesterday.Unix(), Value: 5},
+			{Timestamp: now.Unix(), Value: 10},
+		},
+		Roadall: [][]*float64{
+			roadallRow(float64(yesterday.Unix()), fptr(0.0), nil),
+			roadallRow(float64(now.Unix()), fptr(5.0), nil),
+		},
+	}
+	client := &FakeClient{FetchGoalWithDatapointsFunc: func(slug string) (*Goal, error) {
+		if slug != "pushups" {
+			return nil, errors.New("no such goal")
+		}
+		return goal, nil
+	}}
+
+	var out, errOut bytes.Buffer
+	code := runGraphCommand(context.Background(), client, graphRequest{goalSlug: "pushups", width: 120, window: chartWindow{days: 7}}, &out, &errOut)
+	checkResult(t, code, out.String(), errOut.String(), 0, "", "")
+	chart := out.String()
+	if !strings.Contains(chart, "Goal Progress Chart") || !strings.Contains(chart, "(last 7 days)") {
+		t.Errorf("chart missing its header:\n%s", chart)
+	}
+	if strings.Contains(chart, "\x1b[") {
+		t.Errorf("chart should have no colour codes under --no-color:\n%q", chart)
+	}
+	widest := 0
+	for _, line := range strings.Split(chart, "\n") {
+		widest = max(widest, len([]rune(line)))
+	}
+	if widest <= maxChartWidth+10 {
+		t.Errorf("chart is %d columns wide, want --width 120 to widen it past the review screen's cap", widest)
+	}
+
+	out.Reset()
+	goal.Datapoints = nil
+	code = runGraphCommand(context.Background(), client, graphRequest{goalSlug: "pushups", width: 80}, &out, &errOut)
+	checkResult(t, code, out.String(), errOut.String(), 1, "", "Error: pushups has no datapoints to chart\n")
+
+	errOut.Reset()
+	code = runGraphCommand(context.Background(), client, graphRequest{goalSlug: "situps", width: 80}, &out, &errOut)
+	checkResult(t, code, out.String(), errOut.String(), 1, "", "Error: no such goal\n")
+}
@@ -2,30 +2,37 @@ package main
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 )
 
-// CommonGoalTypes is a list of common Beeminder goal types
-const CommonGoalTypes = "hustler, biker, fatloser, gainer, inboxer, drinker"
-
 // RenderGrid renders the goals grid based on the app model
-func RenderGrid(goals []Goal, width, height, scrollRow, cursor int, hasNavigated bool, username string, searchMode bool, searchQuery string) string {
+func RenderGrid(goals []Goal, width, height, scrollRow, cursor int, hasNavigated bool, changed, newlyDue map[string]bool, cellTexts map[string]string, username, filterName string, searchMode bool, searchQuery string) string {
 	if len(goals) == 0 {
 		if searchMode && searchQuery != "" {
 			return fmt.Sprintf("No goals match '%s'.\n\nPress Esc to clear filter, q to quit.\n", searchQuery)
 		}
+		if filterName != "" {
+			return fmt.Sprintf("No goals match the saved filter '%s'.\n\nPress f for the next filter, q to quit.\n", filterName)
+		}
 		return "No goals found.\n\nPress q to quit.\n"
 	}
 
 	// The header
-	s := fmt.Sprintf("Beeminder Goals - %s", username)
+	var s strings.Builder
+	fmt.Fprintf(&s, "Beeminder Goals - %s", username)
+	if filterName != "" {
+		fmt.Fprintf(&s, " | Saved filter: %s (f for next)", filterName)
+	}
 	if searchMode {
-		s += fmt.Sprintf(" | Filter: /%s", searchQuery)
+		fmt.Fprintf(&s, " | Filter: /%s", searchQuery)
 	}
-	s += "\n\n"
+	s.WriteString("\n\n")
 
 	// Grid geometry (columns, total rows, visible rows) for this size.
 	layout := gridLayout(width, height, len(goals))
@@ -38,8 +45,10 @@ func RenderGrid(goals []Goal, width, height, scrollRow, cursor int, hasNavigated
 	endRow := min(totalRows, startRow+maxVisibleRows)
 
 	// Build grid - only render visible rows
+	now := time.Now()
+	rowCells := make([]renderedCell, 0, cols)
 	for row := startRow; row < endRow; row++ {
-		var rowCells []string
+		rowCells = rowCells[:0]
 		for col := 0; col < cols; col++ {
 			idx := row*cols + col
 			if idx >= len(goals) {
@@ -47,44 +56,144 @@ func RenderGrid(goals []Goal, width, height, scrollRow, cursor int, hasNavigated
 			}
 
 			goal := goals[idx]
-			urgency := UrgencyFor(goal.Safebuf)
 
-			// Selected goal (after navigation) gets the highlighted cell; everything else
-			// uses the normal cell style. Both share the urgency's foreground colour.
-			var style lipgloss.Style
+			// Selected goal (after navigation) gets the highlighted cell, a goal
+			// that just changed on refresh gets the changed cell, and everything
+			// else uses the normal cell style. All share the urgency's foreground
+			// colour.
+			variant := cellNormal
 			if idx == cursor && hasNavigated {
-				style = urgency.HighlightedGridCellStyle()
-			} else {
-				style = urgency.GridCellStyle()
+				variant = cellHighlighted
+			} else if changed[goal.Slug] {
+				variant = cellChanged
 			}
 
 			// Format goal display
 			deltaValue := ParseBareminValue(goal.Baremin)
-			firstLine := formatGoalFirstLine(goal.Slug, goal.Pledge, goal.PledgeCap)
+			markers := goalMarkers(goal, now)
+			if newlyDue[goal.Slug] {
+				markers = newlyDueMarker + markers
+			}
+			firstLine := formatMarkedGoalFirstLine(markers, goal.Slug, goal.Pledge, goal.PledgeCap)
 			secondLine := formatGoalSecondLine(deltaValue, FormatGoalDueDate(goal))
-			display := fmt.Sprintf("%s\n%s", firstLine, secondLine)
+			if text, ok := cellTexts[goal.Slug]; ok {
+				secondLine = text
+			}
+			display := firstLine + "\n" + secondLine
 
-			cell := style.Render(display)
-			rowCells = append(rowCells, cell)
+			rowCells = append(rowCells, renderGridCell(UrgencyFor(goal.Safebuf), variant, display))
 		}
-		s += lipgloss.JoinHorizontal(lipgloss.Top, rowCells...)
-		s += "\n"
+		joinCells(&s, rowCells)
+		s.WriteString("\n")
+	}
+
+	return s.String()
+}
+
+// cellVariant is which of an urgency's grid cell styles a cell is drawn in.
+type cellVariant int
+
+const (
+	cellNormal cellVariant = iota
+	cellHighlighted
+	cellChanged
+)
+
+// gridCellStyles holds every urgency's cell styles, built once rather than per
+// cell per frame.
+var gridCellStyles = func() map[Urgency][3]lipgloss.Style {
+	styles := map[Urgency][3]lipgloss.Style{}
+	for u := UrgencyOverdue; u <= UrgencyDistant; u++ {
+		styles[u] = [3]lipgloss.Style{u.GridCellStyle(), u.HighlightedGridCellStyle(), u.ChangedGridCellStyle()}
 	}
+	return styles
+}()
+
+// renderedCell is a grid cell rendered and split into lines, each padded to
+// the width of the widest.
+type renderedCell struct {
+	lines []string
+	width int
+}
 
-	return s
+// cellKey identifies a rendered cell: the same text in the same style renders
+// the same way under the same colour profile.
+type cellKey struct {
+	urgency Urgency
+	variant cellVariant
+	profile termenv.Profile
+	display string
+}
+
+// maxCachedCells bounds gridCellCache. Cell text changes as deadlines
+// approach, so entries go stale; starting afresh once it fills keeps it small.
+const maxCachedCells = 4096
+
+// gridCellCache keeps rendered cells between frames. Lipgloss measures every
+// line of a cell as it draws it, which dominates the cost of rendering the
+// grid, while from one frame to the next almost every cell is unchanged.
+var gridCellCache = struct {
+	sync.Mutex
+	cells map[cellKey]renderedCell
+}{cells: map[cellKey]renderedCell{}}
+
+// renderGridCell renders a cell's display text in its style, reusing the
+// cell from an earlier frame where there is one.
+func renderGridCell(u Urgency, variant cellVariant, display string) renderedCell {
+	key := cellKey{urgency: u, variant: variant, profile: lipgloss.ColorProfile(), display: display}
+	gridCellCache.Lock()
+	defer gridCellCache.Unlock()
+	if cell, ok := gridCellCache.cells[key]; ok {
+		return cell
+	}
+	lines := strings.Split(gridCellStyles[u][variant].Render(display), "\n")
+	widths := make([]int, len(lines))
+	cell := renderedCell{lines: lines}
+	for i, line := range lines {
+		widths[i] = lipgloss.Width(line)
+		cell.width = max(cell.width, widths[i])
+	}
+	for i := range lines {
+		lines[i] += strings.Repeat(" ", cell.width-widths[i])
+	}
+	if len(gridCellCache.cells) >= maxCachedCells {
+		clear(gridCellCache.cells)
+	}
+	gridCellCache.cells[key] = cell
+	return cell
+}
+
+// joinCells writes cells side by side, top-aligned, as
+// lipgloss.JoinHorizontal would, without measuring them again: their lines
+// were padded to width when they were rendered.
+func joinCells(s *strings.Builder, cells []renderedCell) {
+	height := 0
+	for _, cell := range cells {
+		height = max(height, len(cell.lines))
+	}
+	for i := range height {
+		for _, cell := range cells {
+			if i < len(cell.lines) {
+				s.WriteString(cell.lines[i])
+			} else {
+				s.WriteString(strings.Repeat(" ", cell.width))
+			}
+		}
+		if i < height-1 {
+			s.WriteByte('\n')
+		}
+	}
 }
 
 // RenderFooter renders the footer with scroll and refresh information
 func RenderFooter(goals []Goal, width, height, scrollRow int, refreshActive bool) string {
 	// The footer with scroll information
 	layout := gridLayout(width, height, len(goals))
-	footerTotalRows := layout.totalRows
-	footerMaxVisibleRows := layout.visibleRows
 
+	// Position indicator, so it's clear how much of the grid is off-screen
 	scrollInfo := ""
-	if footerTotalRows > footerMaxVisibleRows {
-		scrollInfo = fmt.Sprintf(" | Scroll: %d/%d (u/d or pgup/pgdown)",
-			scrollRow+1, max(1, footerTotalRows-footerMaxVisibleRows+1))
+	if position := layout.positionLabel(scrollRow); position != "" {
+		scrollInfo = fmt.Sprintf(" | %s (pgup/pgdown or d)", position)
 	}
 
 	// Refresh status
@@ -94,21 +203,26 @@ func RenderFooter(goals []Goal, width, height, scrollRow int, refreshActive bool
 	}
 	refreshInfo := fmt.Sprintf(" | Auto-refresh: %s (t to toggle, r to refresh now)", refreshStatus)
 
+	// A one-glance health check: how many goals have how much buffer
+	histogram := compactBufferHistogram(goals, false)
+
 	// Build the full footer text
-	footerText := fmt.Sprintf("Press q to quit%s%s | / to filter | n to create goal | Arrow keys to navigate, Enter for details", scrollInfo, refreshInfo)
+	footerText := fmt.Sprintf("%s | Press q to quit%s%s | / to filter | n to create goal | : for commands | Arrow keys to navigate, Enter for details", histogram, scrollInfo, refreshInfo)
 
 	// If the footer is too wide, wrap it
 	if len(footerText) > width {
 		// Split into multiple lines based on available width
-		lines := wrapText(footerText, width)
-		return "\n" + strings.Join(lines, "\n") + "\n"
+		footerText = strings.Join(wrapText(footerText, width), "\n")
 	}
 
+	// Colour the histogram only now, so the escape codes don't count towards
+	// the width. (If wrapping split it, it stays plain.)
+	footerText = strings.Replace(footerText, histogram, compactBufferHistogram(goals, true), 1)
 	return fmt.Sprintf("\n%s\n", footerText)
 }
 
 // RenderModal renders a modal with detailed goal information and data input form
-func RenderModal(goal *Goal, width, height int, inputDate, inputValue, inputComment string, inputFocus int, inputMode bool, inputError string, submitting bool) string {
+func RenderModal(goal *Goal, width, height int, inputDate, inputValue, inputComment string, inputFocus int, inputMode bool, inputError string, fieldErrors []string, preview string, submitting bool) string {
 	if goal == nil {
 		return ""
 	}
@@ -116,18 +230,69 @@ func RenderModal(goal *Goal, width, height int, inputDate, inputValue, inputComm
 	modalStyle := CreateModalStyle()
 
 	// Calculate modal dimensions (80% of screen width, auto height)
-	modalWidth := width * 8 / 10
-	if modalWidth > 80 {
-		modalWidth = 80
+	modalWidth := modalWidthFor(width)
+
+	content := goalDetailContent(goal, inputDate, inputValue, inputComment, inputFocus, inputMode, inputError, fieldErrors, preview, submitting,
+		"Left/Right or h/l: Previous/Next goal • 'a': Add datapoint • 'u': Undo • ESC: Close")
+
+	// Apply width constraint to content
+	styledContent := modalStyle.Width(modalWidth).Render(content)
+
+	// Center the modal horizontally, a quarter of the way down (approximately)
+	return placeModal(styledContent, width, height, height/4)
+}
+
+// RenderDetailPane renders the details pane shown beside the grid on a wide
+// terminal: the same details and datapoint form as RenderModal, plus the
+// goal's chart once its datapoints are loaded. active marks the pane as
+// holding the keyboard (the goal is open, not just selected); hint is the key
+// help shown when the form isn't.
+func RenderDetailPane(goal *Goal, width, height int, inputDate, inputValue, inputComment string, inputFocus int, inputMode bool, inputError string, fieldErrors []string, preview string, submitting, active, loading bool, hint string) string {
+	style := CreateModalStyle().Padding(0, 1).Margin(0)
+	if !active {
+		style = style.BorderForeground(lipgloss.Color("241"))
 	}
-	if modalWidth < 40 {
-		modalWidth = 40
+	// lipgloss widths include the padding but not the border.
+	style = style.Width(max(1, width-style.GetHorizontalBorderSize()))
+	if goal == nil {
+		return style.Render("No goal selected.")
 	}
 
+	content := goalDetailContent(goal, inputDate, inputValue, inputComment, inputFocus, inputMode, inputError, fieldErrors, preview, submitting, hint)
+	if chart := fitGoalChart(*goal, width-style.GetHorizontalFrameSize()); chart != "" {
+		content += "\n" + chart
+	} else if loading {
+		content += "\n\nLoading chart..."
+	}
+
+	// Keep the pane on screen: drop the lines that don't fit, chart first.
+	lines := strings.Split(content, "\n")
+	if room := height - style.GetVerticalFrameSize(); room > 0 && len(lines) > room {
+		lines = lines[:room]
+	}
+	return style.Render(strings.Join(lines, "\n"))
+}
+
+// fitGoalChart renders a goal's chart no wider than width where it can: the
+// axis labels' width depends on the values, so it renders once, then again
+// narrower by however much the first try overflowed.
+func fitGoalChart(goal Goal, width int) string {
+	chart := renderGoalChart(goal, width)
+	if over := lipgloss.Width(chart) - width; over > 0 {
+		chart = renderGoalChart(goal, width-over)
+	}
+	return chart
+}
+
+// goalDetailContent is the body of the goal detail modal and pane: the goal's
+// details and recent datapoints, then either the datapoint form (inputMode)
+// or the hint line. fieldErrors are the hints shown under the form's fields,
+// indexed like them.
+func goalDetailContent(goal *Goal, inputDate, inputValue, inputComment string, inputFocus int, inputMode bool, inputError string, fieldErrors []string, preview string, submitting bool, hint string) string {
 	// Goal details content
-	pledgeDisplay := fmt.Sprintf("$%.2f", goal.Pledge)
+	pledgeDisplay := formatMoney(goal.Pledge, 2)
 	if goal.PledgeCap != nil && *goal.PledgeCap > 0 && *goal.PledgeCap != goal.Pledge {
-		pledgeDisplay = fmt.Sprintf("$%.2f / $%.2f", goal.Pledge, *goal.PledgeCap)
+		pledgeDisplay = formatMoney(goal.Pledge, 2) + " / " + formatMoney(*goal.PledgeCap, 2)
 	}
 	content := fmt.Sprintf("Goal Details\n\n"+
 		"Slug: %s\n"+
@@ -136,13 +301,25 @@ func RenderModal(goal *Goal, width, height int, inputDate, inputValue, inputComm
 		"Safe Buffer: %d days\n"+
 		"Due Date: %s\n"+
 		"Buffer Color: %s",
-		goal.Slug,
+		goalSlugLabel(goal),
 		goal.Title,
 		pledgeDisplay,
 		goal.Safebuf,
 		FormatGoalDueDate(*goal),
 		UrgencyFor(goal.Safebuf))
 
+	if isAutodataGoal(*goal) {
+		content += fmt.Sprintf("\nAutodata: %s", autodataSourceName(*goal))
+		if warning := autodataWarning(*goal, time.Now()); warning != "" {
+			content += "\n" + UrgencyOverdue.TextStyle().Render("⚠ "+warning)
+		}
+	}
+
+	// Data buzz couldn't read, so odd values above aren't taken at face value
+	for _, warning := range goalWarnings(*goal) {
+		content += "\n" + UrgencyOverdue.TextStyle().Render("⚠ "+warning)
+	}
+
 	// Add recent datapoints if available
 	if len(goal.Datapoints) > 0 {
 		content += "\n\n--- Recent Datapoints ---\n"
@@ -166,10 +343,15 @@ func RenderModal(goal *Goal, width, height int, inputDate, inputValue, inputComm
 	// Data input form
 	var formContent string
 	if inputMode {
+		// Name the units so it's clear what the value counts.
+		valueLabel := "Value"
+		if goal.Gunits != "" {
+			valueLabel = fmt.Sprintf("Value (%s)", goal.Gunits)
+		}
 		if submitting {
 			// Show submitting state
-			formContent = fmt.Sprintf("\n\n--- Add Datapoint ---\nDate: %s\nValue: %s\nComment: %s\n\n%s",
-				inputDate, inputValue, inputComment,
+			formContent = fmt.Sprintf("\n\n--- Add Datapoint ---\nDate: %s\n%s: %s\nComment: %s\n\n%s",
+				inputDate, valueLabel, inputValue, inputComment,
 				lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render("Submitting datapoint..."))
 		} else {
 			// Create input fields with focus highlighting
@@ -187,66 +369,43 @@ func RenderModal(goal *Goal, width, height int, inputDate, inputValue, inputComm
 				commentField = lipgloss.NewStyle().Background(lipgloss.Color("4")).Render(commentField)
 			}
 
+			// A failed submit that a field's hint already explains isn't repeated.
 			errorMsg := ""
-			if inputError != "" {
+			if inputError != "" && !slices.Contains(fieldErrors, inputError) {
 				errorMsg = fmt.Sprintf("\n%s", lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Render("Error: "+inputError))
 			}
+			previewMsg := ""
+			if preview != "" {
+				previewMsg = "\n\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("6")).Render(strings.TrimRight(preview, "\n"))
+			}
 
-			formContent = fmt.Sprintf("\n\n--- Add Datapoint ---\nDate: %s\nValue: %s\nComment: %s%s\n\nTab/Shift+Tab: Navigate • Enter: Submit • Esc: Cancel",
-				dateField, valueField, commentField, errorMsg)
+			formContent = fmt.Sprintf("\n\n--- Add Datapoint ---\nDate: %s%s\n%s: %s%s\nComment: %s%s%s\n\nTab/Shift+Tab: Navigate • Enter: Submit • ?: Preview • Esc: Cancel",
+				dateField, fieldHint(fieldErrors, dpDate), valueLabel, valueField, fieldHint(fieldErrors, dpValue), commentField, errorMsg, previewMsg)
 		}
 	} else {
-		formContent = "\n\nLeft/Right or h/l: Previous/Next goal • 'a': Add datapoint • ESC: Close"
-	}
-
-	content += formContent
-
-	// Apply width constraint to content
-	styledContent := modalStyle.Width(modalWidth).Render(content)
-
-	// Center the modal horizontally
-	leftPadding := (width - modalWidth) / 2
-	if leftPadding < 0 {
-		leftPadding = 0
+		formContent = "\n\n" + hint
 	}
 
-	// Center the modal vertically (approximately)
-	topPadding := height / 4
-	if topPadding < 1 {
-		topPadding = 1
-	}
-
-	// Add vertical spacing
-	verticalPadding := ""
-	for i := 0; i < topPadding; i++ {
-		verticalPadding += "\n"
-	}
+	return content + formContent
+}
 
-	// Add horizontal centering
-	centeredModal := ""
-	for _, line := range []string{styledContent} {
-		padding := ""
-		for i := 0; i < leftPadding; i++ {
-			padding += " "
-		}
-		centeredModal += padding + line
+// fieldHint renders the hint shown under form field i for what's wrong with
+// it, as a line of its own, or "" when there's nothing wrong.
+func fieldHint(fieldErrors []string, i int) string {
+	if i < 0 || i >= len(fieldErrors) || fieldErrors[i] == "" {
+		return ""
 	}
-
-	return verticalPadding + centeredModal
+	return "\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Render("  ↳ "+fieldErrors[i])
 }
 
 // RenderCreateGoalModal renders a modal for creating a new goal
-func RenderCreateGoalModal(width, height int, slug, title, goalType, gunits, goaldate, goalval, rate string, focus int, createError string, creating bool) string {
+// completion is the rest of the goal type that Tab would fill in, shown dimmed
+// after what's been typed.
+func RenderCreateGoalModal(width, height int, slug, title, goalType, completion, gunits, goaldate, goalval, rate, runits, template string, haveTemplates bool, focus int, createError string, fieldErrors []string, creating bool) string {
 	modalStyle := CreateModalStyle()
 
 	// Calculate modal dimensions (80% of screen width, auto height)
-	modalWidth := width * 8 / 10
-	if modalWidth > 80 {
-		modalWidth = 80
-	}
-	if modalWidth < 40 {
-		modalWidth = 40
-	}
+	modalWidth := modalWidthFor(width)
 
 	// Create input fields with focus highlighting
 	slugField := slug
@@ -256,6 +415,7 @@ func RenderCreateGoalModal(width, height int, slug, title, goalType, gunits, goa
 	goaldateField := goaldate
 	goalvalField := goalval
 	rateField := rate
+	runitsField := runits
 
 	// Add placeholder for empty fields to make focus visible
 	if focus == 0 {
@@ -275,6 +435,9 @@ func RenderCreateGoalModal(width, height int, slug, title, goalType, gunits, goa
 			goalTypeField = "_"
 		}
 		goalTypeField = lipgloss.NewStyle().Background(lipgloss.Color("4")).Render(goalTypeField)
+		if completion != "" {
+			goalTypeField += lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(completion)
+		}
 	}
 	if focus == 3 {
 		if gunitsField == "" {
@@ -300,9 +463,16 @@ func RenderCreateGoalModal(width, height int, slug, title, goalType, gunits, goa
 		}
 		rateField = lipgloss.NewStyle().Background(lipgloss.Color("4")).Render(rateField)
 	}
+	if focus == 7 {
+		if runitsField == "" {
+			runitsField = "_"
+		}
+		runitsField = lipgloss.NewStyle().Background(lipgloss.Color("4")).Render(runitsField)
+	}
 
+	// A failed submit that a field's hint already explains isn't repeated.
 	errorMsg := ""
-	if createError != "" {
+	if createError != "" && !slices.Contains(fieldErrors, createError) {
 		errorMsg = fmt.Sprintf("\n\n%s", lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Render("Error: "+createError))
 	}
 
@@ -311,49 +481,161 @@ func RenderCreateGoalModal(width, height int, slug, title, goalType, gunits, goa
 		statusMsg = fmt.Sprintf("\n\n%s", lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render("Creating goal..."))
 	}
 
+	// Saved templates are picked with Ctrl+T rather than typed, so the line
+	// only appears when there are some to pick from.
+	templateLine, templateHint := "", ""
+	if haveTemplates {
+		if template == "" {
+			template = "none"
+		}
+		templateLine = fmt.Sprintf("Template: %s\n", template)
+		templateHint = " • Ctrl+T: Template"
+	}
+
 	content := fmt.Sprintf("Create New Goal\n\n"+
-		"Slug: %s\n"+
-		"Title: %s\n"+
-		"Goal Type: %s\n"+
-		"Goal Units: %s\n"+
-		"Goal Date: %s\n"+
-		"Goal Value: %s\n"+
-		"Rate: %s%s%s\n\n"+
+		"%s"+
+		"Slug: %s%s\n"+
+		"Title: %s%s\n"+
+		"Goal Type: %s%s\n"+
+		"Goal Units: %s%s\n"+
+		"Goal Date: %s%s\n"+
+		"Goal Value: %s%s\n"+
+		"Rate: %s%s\n"+
+		"Rate Units: %s%s%s%s\n\n"+
 		"Note: Provide exactly 2 of 3: goaldate, goalval, rate (use 'null' to skip)\n"+
-		"Common goal types: %s\n\n"+
-		"Tab/Shift+Tab: Navigate • Enter: Submit • Esc: Cancel",
-		slugField, titleField, goalTypeField, gunitsField, goaldateField, goalvalField, rateField, errorMsg, statusMsg, CommonGoalTypes)
+		"Goal types: %s; rate units: y, m, w, d, h (↑/↓ to choose)\n\n"+
+		"Tab/Shift+Tab: Navigate%s • Enter: Submit • Esc: Cancel",
+		templateLine,
+		slugField, fieldHint(fieldErrors, cgSlug),
+		titleField, fieldHint(fieldErrors, cgTitle),
+		goalTypeField, fieldHint(fieldErrors, cgGoalType),
+		gunitsField, fieldHint(fieldErrors, cgGunits),
+		goaldateField, fieldHint(fieldErrors, cgGoaldate),
+		goalvalField, fieldHint(fieldErrors, cgGoalval),
+		rateField, fieldHint(fieldErrors, cgRate),
+		runitsField, fieldHint(fieldErrors, cgRunits), errorMsg, statusMsg, goalTypeNames(), templateHint)
 
 	// Apply width constraint to content
 	styledContent := modalStyle.Width(modalWidth).Render(content)
 
-	// Center the modal horizontally
-	leftPadding := (width - modalWidth) / 2
-	if leftPadding < 0 {
-		leftPadding = 0
+	// Center the modal horizontally, a sixth of the way down (approximately)
+	return placeModal(styledContent, width, height, height/6)
+}
+
+// RenderChargeModal renders the charge-yourself form: the amount and note,
+// then the dry run's result awaiting confirmation, then the charge made.
+func RenderChargeModal(width, height int, amount, note string, focus int, chargeError string, checking bool, preview *Charge, charging bool, done *Charge) string {
+	modalStyle := CreateModalStyle()
+
+	// Calculate modal dimensions (80% of screen width, auto height)
+	modalWidth := modalWidthFor(width)
+
+	amountField := amount
+	noteField := note
+	if preview == nil && done == nil && !checking && !charging {
+		// Add placeholder for empty fields to make focus visible
+		if focus == 0 {
+			if amountField == "" {
+				amountField = "_"
+			}
+			amountField = lipgloss.NewStyle().Background(lipgloss.Color("4")).Render(amountField)
+		}
+		if focus == 1 {
+			if noteField == "" {
+				noteField = "_"
+			}
+			noteField = lipgloss.NewStyle().Background(lipgloss.Color("4")).Render(noteField)
+		}
+	}
+
+	var status, help string
+	switch {
+	case done != nil:
+		status = lipgloss.NewStyle().Foreground(lipgloss.Color("2")).Render(
+			fmt.Sprintf("Charged $%.2f (charge %s) with note: %q", done.Amount, done.ID, done.Note))
+		help = "Press any key to close"
+	case charging:
+		status = lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render("Charging...")
+	case preview != nil:
+		status = fmt.Sprintf("Dry run: Would charge $%.2f with note: %q for %s\n\n%s",
+			preview.Amount, preview.Note, preview.Username,
+			UrgencyOverdue.TextStyle().Render(fmt.Sprintf("Charge $%.2f for real? This can't be undone.", preview.Amount)))
+		help = "y: Charge • n: Back to edit • Esc: Cancel"
+	case checking:
+		status = lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render("Checking the charge (dry run)...")
+	default:
+		help = "Tab/Shift+Tab: Navigate • Enter: Dry run • Esc: Cancel"
 	}
 
-	// Center the modal vertically (approximately)
-	topPadding := height / 6
-	if topPadding < 1 {
-		topPadding = 1
+	errorMsg := ""
+	if chargeError != "" {
+		errorMsg = "\n\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("1")).Render("Error: "+chargeError)
+	}
+	if status != "" {
+		status = "\n\n" + status
 	}
+	if help != "" {
+		help = "\n\n" + help
+	}
+
+	content := fmt.Sprintf("Charge Yourself\n\n"+
+		"Amount ($): %s\n"+
+		"Note: %s%s%s%s",
+		amountField, noteField, errorMsg, status, help)
+
+	// Apply width constraint to content
+	styledContent := modalStyle.Width(modalWidth).Render(content)
+
+	// Center the modal horizontally, a quarter of the way down (approximately)
+	return placeModal(styledContent, width, height, height/4)
+}
 
-	// Add vertical spacing
-	verticalPadding := ""
-	for i := 0; i < topPadding; i++ {
-		verticalPadding += "\n"
+// goalTypeNames lists the goal types' names for the create modal.
+func goalTypeNames() string {
+	names := make([]string, len(goalTypeOptions))
+	for i, gt := range goalTypeOptions {
+		names[i] = gt.name
 	}
+	return strings.Join(names, ", ")
+}
 
-	// Add horizontal centering
-	centeredModal := ""
-	for _, line := range []string{styledContent} {
-		padding := ""
-		for i := 0; i < leftPadding; i++ {
-			padding += " "
-		}
-		centeredModal += padding + line
+// RenderWhatsNewModal renders the post-upgrade release notes pane
+func RenderWhatsNewModal(width, height int, notes string) string {
+	modalStyle := CreateModalStyle()
+
+	// Calculate modal dimensions (80% of screen width, auto height)
+	modalWidth := modalWidthFor(width)
+
+	content := fmt.Sprintf("What's new in buzz %s\n\n%s\n\nPress any key to continue", version, notes)
+	styledContent := modalStyle.Width(modalWidth).Render(content)
+	return placeModal(styledContent, width, height, height/8)
+}
+
+// modalWidthFor returns the content width of a modal on a terminal width
+// columns wide: 80% of it, between 40 and 80 columns, but never so wide that
+// the modal's border and margins run off a narrow terminal.
+func modalWidthFor(width int) int {
+	frame := CreateModalStyle().GetHorizontalBorderSize() + CreateModalStyle().GetHorizontalMargins()
+	return max(1, min(min(max(width*8/10, 40), 80), width-frame))
+}
+
+// placeModal positions a rendered modal on a width×height terminal: centered
+// horizontally, and topPadding rows down (at least 1), or less when that would
+// push it off the bottom. A modal taller than the terminal keeps its bottom
+// rows, where the input fields and key hints are, and loses its top.
+func placeModal(modal string, width, height, topPadding int) string {
+	lines := strings.Split(modal, "\n")
+	if height > 0 && len(lines) > height {
+		lines = lines[len(lines)-height:]
+	}
+	topPadding = max(1, topPadding)
+	if height > 0 {
+		topPadding = min(topPadding, height-len(lines))
 	}
 
-	return verticalPadding + centeredModal
+	padding := strings.Repeat(" ", max(0, (width-lipgloss.Width(modal))/2))
+	for i, line := range lines {
+		lines[i] = padding + line
+	}
+	return strings.Repeat("\n", topPadding) + strings.Join(lines, "\n")
 }
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The rendering benchmarks below run over benchGoals goals, a large account,
+// to keep a full-screen render of the Goals tab within a few milliseconds.
+// Run them with: go test -run '^$' -bench . -benchmem
+const benchGoals = 500
+
+// syntheticGoals returns n goals spread across every urgency, each with a
+// bright red line and 90 days of datapoints.
+func syntheticGoals(n int) []Goal {
+	now := time.Now()
+	start := now.AddDate(0, 0, -90)
+	goals := make([]Goal, n)
+	for i := range goals {
+		rate := float64(i%5 + 1)
+		dps := make([]Datapoint, 90)
+		for d := range dps {
+			t := start.AddDate(0, 0, d)
+			dps[d] = Datapoint{Timestamp: t.Unix(), Daystamp: t.Format("20060102"), Value: float64((d*7+i)%10 + 1)}
+		}
+		goals[i] = Goal{
+			Slug:       fmt.Sprintf("goal-%03d", i),
+			Title:      fmt.Sprintf("Synthetic goal number %d", i),
+			Safebuf:    i % 10,
+			Pledge:     float64(5 * (i%4 + 1)),
+			Baremin:    fmt.Sprintf("+%d in %d days", i%7+1, i%10),
+			Losedate:   now.Add(time.Duration(i%10) * 24 * time.Hour).Unix(),
+			Kyoom:      i%2 == 0,
+			Yaw:        1,
+			Runits:     "d",
+			Rate:       &rate,
+			Initday:    start.Unix(),
+			Roadall:    [][]*float64{roadallRow(float64(start.Unix()), fptr(0), nil), roadallRow(float64(now.AddDate(0, 0, 30).Unix()), nil, &rate)},
+			Datapoints: dps,
+		}
+	}
+	return goals
+}
+
+func TestRenderGridMatchesLipgloss(t *testing.T) {
+	goals := syntheticGoals(12)
+	want := func(cursor int, changed map[string]bool) string {
+		var rows []string
+		for row := 0; row < 3; row++ {
+			var cells []string
+			for _, idx := range []int{row * 4, row*4 + 1, row*4 + 2, row*4 + 3} {
+				g := goals[idx]
+				u := UrgencyFor(g.Safebuf)
+				style := u.GridCellStyle()
+				if idx == cursor {
+					style = u.HighlightedGridCellStyle()
+				} else if changed[g.Slug] {
+					style = u.ChangedGridCellStyle()
+				}
+				display := formatMarkedGoalFirstLine(goalMarkers(g, time.Now()), g.Slug, g.Pledge, g.PledgeCap) + "\n" +
+					formatGoalSecondLine(ParseBareminValue(g.Baremin), FormatGoalDueDate(g))
+				cells = append(cells, style.Render(display))
+			}
+			rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, cells...)+"\n")
+		}
+		return "Beeminder Goals - u\n\n" + strings.Join(rows, "")
+	}
+
+	// Rendering again, from cached cells, and moving the cursor or marking a
+	// goal changed, draws what lipgloss would.
+	for _, tt := range []struct {
+		cursor  int
+		changed map[string]bool
+	}{{0, nil}, {0, nil}, {5, map[string]bool{"goal-002": true}}} {
+		if got := RenderGrid(goals, 80, 40, 0, tt.cursor, true, tt.changed, nil, nil, "u", "", false, ""); got != want(tt.cursor, tt.changed) {
+			t.Errorf("cursor %d: got\n%s\nwant\n%s", tt.cursor, got, want(tt.cursor, tt.changed))
+		}
+	}
+}
+
+func BenchmarkRenderGrid(b *testing.B) {
+	goals := syntheticGoals(benchGoals)
+	for b.Loop() {
+		RenderGrid(goals, 200, 60, 0, 3, true, map[string]bool{"goal-007": true}, nil, nil, "u", "", false, "")
+	}
+}
+
+// BenchmarkView renders the whole Goals tab, the grid and its footer, as a
+// keypress on a large account does.
+func BenchmarkView(b *testing.B) {
+	m := model{state: "app", appModel: appModel{
+		ctx:          context.Background(),
+		client:       &FakeClient{},
+		config:       &Config{Username: "u"},
+		goals:        syntheticGoals(benchGoals),
+		width:        200,
+		height:       60,
+		hasNavigated: true,
+	}}
+	for b.Loop() {
+		m.View()
+	}
+}
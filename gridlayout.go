@@ -1,5 +1,7 @@
 package main
 
+import "fmt"
+
 // Goal-grid geometry. The Browse grid lays goals out in fixed-size cells; these
 // constants and gridLayout are the single source of truth for its shape.
 // Rendering (grid.go), scroll math (utils.go), and navigation/hit-testing
@@ -44,3 +46,14 @@ func gridLayout(width, height, goalCount int) gridGeometry {
 func calculateColumns(width int) int {
 	return max(1, width/gridCellWidth)
 }
+
+// positionLabel describes which cell-rows are on screen when scrolled to
+// scrollRow, e.g. "Rows 4–9 of 23". It is empty when every row fits.
+func (g gridGeometry) positionLabel(scrollRow int) string {
+	if g.totalRows <= g.visibleRows {
+		return ""
+	}
+	first := scrollRow + 1
+	last := min(g.totalRows, scrollRow+g.visibleRows)
+	return fmt.Sprintf("Rows %d–%d of %d", first, last, g.totalRows)
+}
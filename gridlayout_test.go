@@ -29,3 +29,26 @@ func TestGridLayout(t *testing.T) {
 		})
 	}
 }
+
+func TestPositionLabel(t *testing.T) {
+	tests := []struct {
+		name      string
+		goalCount int
+		scrollRow int
+		want      string
+	}{
+		{"everything fits", 10, 0, ""},
+		{"top", 40, 0, "Rows 1–5 of 10"},
+		{"middle", 40, 3, "Rows 4–8 of 10"},
+		{"bottom", 40, 5, "Rows 6–10 of 10"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 80x24 → 4 columns, 5 visible rows
+			g := gridLayout(80, 24, tt.goalCount)
+			if got := g.positionLabel(tt.scrollRow); got != tt.want {
+				t.Errorf("positionLabel(%d) = %q, want %q", tt.scrollRow, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Goal groups: named sets of goals defined under "groups" in ~/.buzzrc, e.g.
+//
+//	"groups": {"health": ["run", "lift", "sleep"]}
+//
+// A group can be filtered as a unit (group:health, in any filter), listed as a
+// section (`buzz goals --by-group`), and rolled up (`buzz groups` and the
+// Reports tab).
+
+// goalGroups holds the groups from the loaded config, by name. LoadConfig sets
+// it, like the locale, so filters compiled before a config loads still see it.
+var goalGroups map[string][]string
+
+// applyGroups makes groups the ones group: filters and rollups use.
+func applyGroups(groups map[string][]string) {
+	goalGroups = groups
+}
+
+// groupNames returns the group names in sorted order.
+func groupNames() []string {
+	names := make([]string, 0, len(goalGroups))
+	for name := range goalGroups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// groupsOf returns the names of the groups slug belongs to, in sorted order.
+func groupsOf(slug string) []string {
+	var names []string
+	for _, name := range groupNames() {
+		if slices.ContainsFunc(goalGroups[name], func(s string) bool { return strings.EqualFold(s, slug) }) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// groupSections renders a table (with its two header lines) whose data lines
+// match goals one-to-one as a section per group, in name order, followed by
+// the goals in no group. A goal in two groups is listed under both.
+func groupSections(rendered string, goals []Goal) string {
+	lines := strings.Split(strings.TrimSuffix(rendered, "\n"), "\n")
+	header, rows := lines[:2], lines[2:]
+
+	var b strings.Builder
+	b.WriteString("  " + header[0] + "\n  " + header[1] + "\n")
+	section := func(label string, in func(Goal) bool) {
+		var members []string
+		for i, g := range goals {
+			if in(g) {
+				members = append(members, rows[i])
+			}
+		}
+		if len(members) == 0 {
+			return
+		}
+		b.WriteString("\n" + label + ":\n")
+		for _, line := range members {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+	for _, name := range groupNames() {
+		section(name, func(g Goal) bool { return slices.Contains(groupsOf(g.Slug), name) })
+	}
+	section("ungrouped", func(g Goal) bool { return len(groupsOf(g.Slug)) == 0 })
+	return b.String()
+}
+
+// groupRollup is how a group is doing: how many of its goals are on track
+// this week (see onTrackThisWeek).
+type groupRollup struct {
+	Name    string   `json:"group"`
+	OnTrack int      `json:"on_track"`
+	Total   int      `json:"goals"`
+	Behind  []string `json:"behind"` // slugs of the goals not on track
+}
+
+// onTrackThisWeek reports whether g is safe through the end of the week
+// containing now (Sunday midnight), or has reached its end value.
+func onTrackThisWeek(g Goal, now time.Time) bool {
+	return IsEndValueReached(g) || g.Losedate >= weekStart(now, 1).Unix()
+}
+
+// groupRollups rolls up each group over goals, in name order. Slugs in a group
+// that aren't among goals (archived, or a typo) aren't counted; a group with
+// none of its goals present is left out.
+func groupRollups(goals []Goal, now time.Time) []groupRollup {
+	var rollups []groupRollup
+	for _, name := range groupNames() {
+		r := groupRollup{Name: name, Behind: []string{}}
+		for _, g := range goals {
+			if !slices.Contains(groupsOf(g.Slug), name) {
+				continue
+			}
+			r.Total++
+			if onTrackThisWeek(g, now) {
+				r.OnTrack++
+			} else {
+				r.Behind = append(r.Behind, g.Slug)
+			}
+		}
+		if r.Total > 0 {
+			rollups = append(rollups, r)
+		}
+	}
+	return rollups
+}
+
+// String is the rollup's line, e.g. "health: 3/4 on track this week".
+func (r groupRollup) String() string {
+	return fmt.Sprintf("%s: %d/%d on track this week", r.Name, r.OnTrack, r.Total)
+}
+
+// renderGroupRollups lists the rollups under a heading, naming the goals
+// behind in each group; "" when there are none.
+func renderGroupRollups(rollups []groupRollup) string {
+	if len(rollups) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("Groups:\n")
+	for _, r := range rollups {
+		sb.WriteString("  " + r.String())
+		if len(r.Behind) > 0 {
+			sb.WriteString(" (behind: " + strings.Join(r.Behind, ", ") + ")")
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+const groupsUsage = "Usage: buzz groups"
+
+// handleGroupsCommand rolls up the goal groups defined in ~/.buzzrc.
+func handleGroupsCommand() {
+	groupsFlags := flag.NewFlagSet("groups", flag.ContinueOnError)
+	groupsFlags.SetOutput(io.Discard)
+	if err := groupsFlags.Parse(os.Args[2:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fmt.Println(groupsUsage)
+			return
+		}
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %s\n", err)
+		fmt.Fprintln(os.Stderr, groupsUsage)
+		os.Exit(2)
+	}
+	if groupsFlags.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Error: unexpected argument %q\n", groupsFlags.Arg(0))
+		fmt.Fprintln(os.Stderr, groupsUsage)
+		os.Exit(1)
+	}
+
+	client, ok := loadClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+	os.Exit(runGroupsCommand(context.Background(), client, goalFilter, outputFormat, time.Now(), os.Stdout, os.Stderr))
+}
+
+// runGroupsCommand fetches the goals and prints each group's rollup. It
+// returns the process exit code.
+func runGroupsCommand(ctx context.Context, client Client, keep func(Goal) bool, format string, now time.Time, out, errOut io.Writer) int {
+	if len(goalGroups) == 0 {
+		fmt.Fprintln(errOut, `Error: No groups are defined. Add them to ~/.buzzrc, e.g. "groups": {"health": ["run", "lift"]}`)
+		return 1
+	}
+	goals, err := client.FetchGoals(ctx)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: Failed to fetch goals: %s\n", redactError(err))
+		return 1
+	}
+	rollups := groupRollups(keepGoals(goals, keep), now)
+
+	switch format {
+	case "json":
+		if rollups == nil {
+			rollups = []groupRollup{}
+		}
+		data, err := json.MarshalIndent(rollups, "", "  ")
+		if err != nil {
+			fmt.Fprintf(errOut, "Error: %s\n", err)
+			return 1
+		}
+		fmt.Fprintln(out, string(data))
+	case "jsonl":
+		if err := writeJSONL(out, rollups); err != nil {
+			fmt.Fprintf(errOut, "Error: %s\n", redactError(err))
+			return 1
+		}
+	case "csv":
+		rows := make([][]string, len(rollups))
+		for i, r := range rollups {
+			rows[i] = []string{r.Name, strconv.Itoa(r.OnTrack), strconv.Itoa(r.Total), strings.Join(r.Behind, " ")}
+		}
+		rendered, err := encodeCSV([]string{"Group", "On track", "Goals", "Behind"}, rows)
+		if err != nil {
+			fmt.Fprintf(errOut, "Error: %s\n", err)
+			return 1
+		}
+		fmt.Fprint(out, rendered)
+	default:
+		if len(rollups) == 0 {
+			fmt.Fprintln(out, "None of the grouped goals were found.")
+			return 0
+		}
+		fmt.Fprint(out, renderGroupRollups(rollups))
+	}
+	return 0
+}
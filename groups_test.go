@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// setGroups installs groups for the test, restoring the previous ones after.
+func setGroups(t *testing.T, groups map[string][]string) {
+	t.Helper()
+	old := goalGroups
+	applyGroups(groups)
+	t.Cleanup(func() { applyGroups(old) })
+}
+
+func TestGroupFilter(t *testing.T) {
+	setGroups(t, map[string][]string{"health": {"run", "Lift"}, "gym": {"lift"}})
+	if got := groupsOf("lift"); strings.Join(got, ",") != "gym,health" {
+		t.Errorf("groupsOf(lift) = %v", got)
+	}
+	keep, err := compileFilter("group:health AND NOT slug:run")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for slug, want := range map[string]bool{"run": false, "lift": true, "read": false} {
+		if got := keep(Goal{Slug: slug}); got != want {
+			t.Errorf("filter(%s) = %v, want %v", slug, got, want)
+		}
+	}
+}
+
+func TestGroupRollups(t *testing.T) {
+	setGroups(t, map[string][]string{"health": {"run", "lift", "sleep", "gone"}, "work": {"write"}, "empty": {"nothing"}})
+	now := time.Date(2026, 3, 11, 9, 0, 0, 0, time.Local) // a Wednesday
+	goals := []Goal{
+		{Slug: "run", Losedate: now.AddDate(0, 0, 6).Unix()},
+		{Slug: "lift", Losedate: now.AddDate(0, 0, 1).Unix()},
+		{Slug: "sleep", Losedate: now.AddDate(0, 0, 10).Unix()},
+		{Slug: "write", Losedate: now.Unix()},
+		{Slug: "read", Losedate: now.Unix()},
+	}
+	got := renderGroupRollups(groupRollups(goals, now))
+	want := "Groups:\n  health: 2/3 on track this week (behind: lift)\n  work: 0/1 on track this week (behind: write)\n"
+	if got != want {
+		t.Errorf("rollups =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGroupSections(t *testing.T) {
+	setGroups(t, map[string][]string{"health": {"run", "lift"}, "gym": {"lift"}})
+	goals := []Goal{{Slug: "run"}, {Slug: "lift"}, {Slug: "read"}}
+	table := Table{ShowHeader: true, Columns: []Column{{Header: "Slug", Cell: func(g Goal) string { return g.Slug }}}}
+	got := groupSections(table.Render(goals), goals)
+	want := "  Slug\n  ----\n\ngym:\n  lift\n\nhealth:\n  run\n  lift\n\nungrouped:\n  read\n"
+	if got != want {
+		t.Errorf("sections =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestRunGroupsCommand(t *testing.T) {
+	now := time.Date(2026, 3, 11, 9, 0, 0, 0, time.Local)
+	client := &FakeClient{FetchGoalsFunc: func() ([]Goal, error) {
+		return []Goal{{Slug: "run", Losedate: now.AddDate(0, 0, 7).Unix()}}, nil
+	}}
+	var out, errb bytes.Buffer
+
+	setGroups(t, nil)
+	code := runGroupsCommand(context.Background(), client, nil, "table", now, &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 1, "", "No groups are defined")
+
+	setGroups(t, map[string][]string{"health": {"run"}})
+	code = runGroupsCommand(context.Background(), client, nil, "table", now, &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 0, "health: 1/1 on track this week", "")
+
+	out.Reset()
+	code = runGroupsCommand(context.Background(), client, nil, "csv", now, &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 0, "Group,On track,Goals,Behind\nhealth,1,1,\n", "")
+}
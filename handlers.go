@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"math"
 	"strconv"
 	"strings"
@@ -28,6 +29,38 @@ func handleSearchInput(m model, msg tea.KeyMsg) (model, bool) {
 	return m, false
 }
 
+// handleJumpInput handles keys while a type-ahead jump is active: printable
+// characters and backspace edit the query and move the cursor, Esc ends the
+// jump, and any other key ends it and is then handled normally (so Enter
+// opens the goal that was jumped to).
+func handleJumpInput(m model, msg tea.KeyMsg) (model, bool) {
+	if !m.appModel.jumpActive {
+		return m, false
+	}
+	switch {
+	case len(msg.Runes) == 1 && unicode.IsPrint(msg.Runes[0]):
+		m.appModel.jumpQuery += string(msg.Runes)
+	case msg.Type == tea.KeyBackspace:
+		if len(m.appModel.jumpQuery) > 0 {
+			_, size := utf8.DecodeLastRuneInString(m.appModel.jumpQuery)
+			m.appModel.jumpQuery = m.appModel.jumpQuery[:len(m.appModel.jumpQuery)-size]
+		}
+	case msg.Type == tea.KeyEsc:
+		m.appModel.exitJump()
+		return m, true
+	default:
+		m.appModel.exitJump()
+		return m, false
+	}
+
+	if m.appModel.jumpToMatch() {
+		m.appModel.hasNavigated = true
+		m.appModel.lastNavigationTime = time.Now()
+		updateScrollForCursor(&m, len(m.appModel.getDisplayGoals()))
+	}
+	return m, true
+}
+
 // isAlphanumericOrDash checks if character is alphanumeric, dash, or underscore
 func isAlphanumericOrDash(char string) bool {
 	if len(char) != 1 {
@@ -88,6 +121,18 @@ func handleCreateModalInput(m model, msg tea.KeyMsg) (model, bool) {
 	if m.appModel.mode != modeCreateGoal || m.appModel.createGoal.creating {
 		return m, false
 	}
+	// Ctrl+T fills the form from the next saved goal template.
+	if msg.String() == "ctrl+t" {
+		m.appModel.cycleCreateTemplate()
+		return m, true
+	}
+	// Up/Down choose from the goal types or rate units when one is focused.
+	switch msg.String() {
+	case "up":
+		return m, m.appModel.createGoal.cycleChoice(-1)
+	case "down":
+		return m, m.appModel.createGoal.cycleChoice(1)
+	}
 	if len(msg.Runes) != 1 {
 		return m, false
 	}
@@ -102,6 +147,12 @@ func handleDatapointInput(m model, msg tea.KeyMsg) (model, bool) {
 	// can still be typed in comment fields
 	if m.appModel.mode == modeDatapointInput && !m.appModel.datapoint.submitting {
 		if len(msg.Runes) == 1 {
+			// ? previews the add, except in the comment, where it's text.
+			if msg.Runes[0] == '?' && m.appModel.datapoint.focus != dpComment {
+				m.appModel.datapoint.preview = previewDatapoint(m.appModel.modalGoal, &m.appModel.datapoint, time.Now())
+				m.appModel.datapoint.previewFor = m.appModel.datapoint.previewKey()
+				return m, true
+			}
 			handled := m.appModel.datapoint.handleRune(msg.Runes[0])
 			return m, handled
 		}
@@ -111,6 +162,39 @@ func handleDatapointInput(m model, msg tea.KeyMsg) (model, bool) {
 
 // handleKeyPress processes keyboard input and returns updated model and command
 func handleKeyPress(m model, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// A rejected token blocks everything else until the user logs in again
+	if m.appModel.authExpired {
+		return handleAuthExpiredKey(m, msg)
+	}
+
+	// Tabs other than Goals handle their own keys
+	if m.appModel.tab != tabGoals {
+		return handleOtherTabKey(m, msg)
+	}
+
+	// The release notes pane is dismissed by any key except ctrl+c
+	if m.appModel.mode == modeWhatsNew && msg.String() != "ctrl+c" {
+		m.appModel.closeWhatsNew()
+		return m, nil
+	}
+
+	// The charge form handles its own keys
+	if m.appModel.mode == modeCharge {
+		return handleChargeKey(m, msg)
+	}
+
+	// So does the command palette
+	if m.appModel.mode == modePalette {
+		return handlePaletteKey(m, msg)
+	}
+
+	// Type-ahead jump consumes its own keys; the timeout clears the highlight
+	// once the user stops typing
+	var jumpHandled bool
+	if m, jumpHandled = handleJumpInput(m, msg); jumpHandled {
+		return m, navigationTimeoutCmd(navigationTimeout)
+	}
+
 	// Handle text input in search mode FIRST
 	if updatedModel, handled := handleSearchInput(m, msg); handled {
 		return updatedModel, nil
@@ -141,7 +225,12 @@ func handleKeyPress(m model, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "a":
 		return handleAddDatapoint(m)
 
-	// Tab navigation between form fields (datapoint-input or create-goal mode, not while busy)
+	// Switch tabs with the number keys (only in Browse mode with no active search)
+	case "1", "2", "3", "4":
+		return handleSelectTab(m, msg.String())
+
+	// Tab navigation between form fields (datapoint-input or create-goal mode,
+	// not while busy), or between tabs in Browse mode
 	case "tab":
 		return handleTabKey(m, false)
 
@@ -170,14 +259,33 @@ func handleKeyPress(m model, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "right", "l":
 		return handleNavigationRight(m)
 
-	// Scroll up with Page Up or 'u' (only in Browse mode)
-	case "pgup", "u":
+	// Scroll up with Page Up (only in Browse mode)
+	case "pgup":
 		return handleScrollUp(m)
 
+	// Undo the latest datapoint added or deleted with 'u' or 'U' (Browse mode
+	// or a goal's details)
+	case "u", "U":
+		return handleUndo(m)
+
 	// Scroll down with Page Down or 'd' (only in Browse mode)
 	case "pgdown", "d":
 		return handleScrollDown(m)
 
+	// Jump to the first/last goal with Home/End or g/G (only in Browse mode)
+	case "home", "g":
+		return handleJumpToGoal(m, func(int) int { return 0 })
+
+	case "end", "G":
+		return handleJumpToGoal(m, func(n int) int { return n - 1 })
+
+	// Half-page cursor movement with ctrl+d/ctrl+u (only in Browse mode)
+	case "ctrl+d":
+		return handleHalfPage(m, 1)
+
+	case "ctrl+u":
+		return handleHalfPage(m, -1)
+
 	// Manual refresh with 'r' (only in Browse mode)
 	case "r":
 		return handleRefresh(m)
@@ -190,14 +298,85 @@ func handleKeyPress(m model, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "/":
 		return handleEnterSearch(m)
 
+	// Cycle through saved filters with 'f' (only in Browse mode)
+	case "f":
+		return handleCycleFilter(m)
+
+	// Start a type-ahead jump with ' (only in Browse mode with no active search)
+	case "'":
+		return handleEnterJump(m)
+
 	// Open create goal modal with 'n' for new (only in Browse mode with no active search)
 	case "n":
 		return handleCreateGoal(m)
+
+	// Open the charge-yourself form with '$' (only in Browse mode with no active search)
+	case "$":
+		m.appModel.openCharge()
+		return m, nil
+
+	// Open the command palette with ':' (only in Browse mode with no active search)
+	case ":":
+		m.appModel.openPalette()
+		return m, nil
 	}
 
 	return m, nil
 }
 
+// handleChargeKey handles keys in the charge form. Enter first runs the charge
+// as a dry run; only y on its result makes the real charge, and n goes back to
+// edit. Nothing but ctrl+c works while either is in flight, and once the
+// charge is made any key closes the form.
+func handleChargeKey(m model, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	c := &m.appModel.charge
+	key := msg.String()
+	switch {
+	case key == "ctrl+c":
+		return m, tea.Quit
+	case c.busy():
+		return m, nil
+	case c.done != nil:
+		m.appModel.closeCharge()
+		return m, nil
+	case key == "esc":
+		m.appModel.closeCharge()
+		return m, nil
+	case c.preview != nil:
+		switch key {
+		case "y", "Y":
+			// The dry run checked these exact values; charge them for real.
+			amount, _ := parseChargeAmount(c.amount())
+			c.charging = true
+			return m, createChargeCmd(m.appModel.ctx, m.appModel.client, amount, c.note(), false)
+		case "n", "N":
+			c.preview = nil
+		}
+		return m, nil
+	}
+
+	switch key {
+	case "tab", "shift+tab":
+		c.tab(key == "shift+tab")
+	case "backspace":
+		c.backspace()
+	case "enter":
+		c.err = ""
+		if errMsg := c.validate(); errMsg != "" {
+			c.err = errMsg
+			return m, nil
+		}
+		amount, _ := parseChargeAmount(c.amount())
+		c.checking = true
+		return m, createChargeCmd(m.appModel.ctx, m.appModel.client, amount, c.note(), true)
+	default:
+		if len(msg.Runes) == 1 {
+			c.handleRune(msg.Runes[0])
+		}
+	}
+	return m, nil
+}
+
 // handleEscapeKey handles the Escape key press as a "back out one level" ladder.
 // Foreground modes are unwound before the search layer, so Esc on a goal-detail
 // modal opened over a search closes the modal while keeping the search.
@@ -228,15 +407,33 @@ func handleEscapeKey(m model) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleAuthExpiredKey handles keys on the "token invalid" screen: Enter
+// returns to the auth flow (whose success re-creates the app with the new
+// credentials), q/esc/ctrl+c quit, and everything else is ignored.
+func handleAuthExpiredKey(m model, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		m.state = "auth"
+		m.authModel = initialAuthModel()
+		return m, m.authModel.Init()
+	case "ctrl+c", "q", "esc":
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
 // handleAddDatapoint enters input mode for adding a datapoint
 func handleAddDatapoint(m model) (tea.Model, tea.Cmd) {
 	if m.appModel.mode == modeGoalDetail {
-		// Try to get the last datapoint value, default to "1" if it fails
-		defaultValue := "1"
-		if lastValue, err := m.appModel.client.GetLastDatapointValue(m.appModel.ctx, m.appModel.modalGoal.Slug); err == nil && lastValue != 0 {
-			defaultValue = fmt.Sprintf("%.1f", lastValue)
-		}
-		m.appModel.startDatapointInput(newDatapointForm(defaultValue))
+		// Open the form at once with "1" and fetch the last datapoint value
+		// in the background; it replaces the "1" if it arrives first
+		form := newDatapointForm("1", m.appModel.modalGoal.Gunits)
+		form.placeholder = "1"
+		// Default to the goal's Beeminder day, which differs from the
+		// calendar's between midnight and a goal's early-morning deadline.
+		form.fields[dpDate].value = goalDayAt(*m.appModel.modalGoal, time.Now()).Format("2006-01-02")
+		m.appModel.startDatapointInput(form)
+		return m, loadLastValueCmd(m.appModel.ctx, m.appModel.client, m.appModel.modalGoal.Slug)
 	}
 	return m, nil
 }
@@ -247,6 +444,19 @@ func handleTabKey(m model, reverse bool) (tea.Model, tea.Cmd) {
 		m.appModel.createGoal.tab(reverse)
 	} else if m.appModel.mode == modeDatapointInput && !m.appModel.datapoint.submitting {
 		m.appModel.datapoint.tab(reverse)
+	} else if m.appModel.mode == modeBrowse && !m.appModel.searchActive {
+		return m, m.appModel.cycleTab(reverse)
+	}
+	return m, nil
+}
+
+// handleSelectTab switches to the tab a number key selects
+func handleSelectTab(m model, key string) (tea.Model, tea.Cmd) {
+	if m.appModel.mode != modeBrowse || m.appModel.searchActive {
+		return m, nil
+	}
+	if t, ok := tabForKey(key); ok {
+		return m, m.appModel.switchTab(t)
 	}
 	return m, nil
 }
@@ -283,6 +493,19 @@ func validateDatapointInput(inputDate, inputValue string) string {
 		return "Value cannot be empty"
 	}
 
+	if errMsg := validateDatapointDate(inputDate); errMsg != "" {
+		return errMsg
+	}
+	return validateDatapointValue(inputValue)
+}
+
+// validateDatapointDate validates the datapoint date field on its own, for
+// both submission and the hint shown under the field as it's typed
+func validateDatapointDate(inputDate string) string {
+	if inputDate == "" {
+		return "Date cannot be empty"
+	}
+
 	// Parse and validate date. Interpret the calendar date in local time so the
 	// comparison below against the local time.Now() is timezone-consistent
 	// (parsing without a location would assume UTC and shift the boundary).
@@ -295,6 +518,14 @@ func validateDatapointInput(inputDate, inputValue string) string {
 	if date.After(time.Now().AddDate(0, 0, 1)) {
 		return "Date cannot be more than 1 day in the future"
 	}
+	return ""
+}
+
+// validateDatapointValue validates the datapoint value field on its own
+func validateDatapointValue(inputValue string) string {
+	if inputValue == "" {
+		return "Value cannot be empty"
+	}
 
 	// Parse and validate value (must be a valid, finite number). ParseFloat
 	// accepts "NaN"/"Inf"/"+Inf"/"-Inf"/"Infinity"/"+Infinity"/"-Infinity", so
@@ -302,7 +533,6 @@ func validateDatapointInput(inputDate, inputValue string) string {
 	if v, err := strconv.ParseFloat(inputValue, 64); err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
 		return "Value must be a valid number"
 	}
-
 	return ""
 }
 
@@ -327,54 +557,77 @@ func isValidFloat(s string) bool {
 }
 
 // validateCreateGoalInput validates create goal input fields and returns error message if invalid
-func validateCreateGoalInput(slug, title, goalType, gunits, goaldate, goalval, rate string) string {
-	if slug == "" {
-		return "Slug cannot be empty"
-	}
-
-	if title == "" {
-		return "Title cannot be empty"
+func validateCreateGoalInput(slug, title, goalType, gunits, goaldate, goalval, rate, runits string) string {
+	for i, value := range []string{slug, title, goalType, gunits, goaldate, goalval, rate, runits} {
+		if errMsg := validateCreateGoalField(i, value); errMsg != "" {
+			return errMsg
+		}
 	}
 
-	if goalType == "" {
-		return "Goal type cannot be empty"
+	// Validate that exactly 2 out of 3 (goaldate, goalval, rate) are provided
+	countProvided := 0
+	for _, value := range []string{goaldate, goalval, rate} {
+		if value != "" && value != "null" {
+			countProvided++
+		}
 	}
-
-	if gunits == "" {
-		return "Goal units cannot be empty"
+	if countProvided != 2 {
+		return "Exactly 2 out of 3 (goaldate, goalval, rate) must be provided"
 	}
 
-	// Validate that exactly 2 out of 3 (goaldate, goalval, rate) are provided
-	countProvided := 0
+	return ""
+}
 
-	// Validate goaldate: must be empty, "null", or a valid integer (epoch timestamp)
-	if goaldate != "" && goaldate != "null" {
-		if !isValidInteger(goaldate) {
+// validateCreateGoalField validates one create goal field on its own (i is a
+// createGoalForm field index), for both submission and the hint shown under
+// the field once focus leaves it. Whether exactly 2 of goaldate, goalval and
+// rate are given depends on all three, so only submission checks that.
+func validateCreateGoalField(i int, value string) string {
+	switch i {
+	case cgSlug:
+		if value == "" {
+			return "Slug cannot be empty"
+		}
+	case cgTitle:
+		if value == "" {
+			return "Title cannot be empty"
+		}
+	case cgGoalType:
+		if value == "" {
+			return "Goal type cannot be empty"
+		}
+		// Other names pass through for the API to judge, so a goal type newer
+		// than buzz still works; only a near miss of a known one is a typo.
+		if !isGoalType(value) {
+			if suggestion := suggestGoalType(value); suggestion != "" {
+				return fmt.Sprintf("Unknown goal type %q (did you mean %s?)", value, suggestion)
+			}
+		}
+	case cgGunits:
+		if value == "" {
+			return "Goal units cannot be empty"
+		}
+	case cgGoaldate:
+		// Must be empty, "null", or a valid integer (epoch timestamp)
+		if value != "" && value != "null" && !isValidInteger(value) {
 			return "Goal date must be a valid epoch timestamp or 'null'"
 		}
-		countProvided++
-	}
-
-	// Validate goalval: must be empty, "null", or a valid number
-	if goalval != "" && goalval != "null" {
-		if !isValidFloat(goalval) {
+	case cgGoalval:
+		// Must be empty, "null", or a valid number
+		if value != "" && value != "null" && !isValidFloat(value) {
 			return "Goal value must be a valid number or 'null'"
 		}
-		countProvided++
-	}
-
-	// Validate rate: must be empty, "null", or a valid number
-	if rate != "" && rate != "null" {
-		if !isValidFloat(rate) {
+	case cgRate:
+		// Must be empty, "null", or a valid number
+		if value != "" && value != "null" && !isValidFloat(value) {
 			return "Rate must be a valid number or 'null'"
 		}
-		countProvided++
-	}
-
-	if countProvided != 2 {
-		return "Exactly 2 out of 3 (goaldate, goalval, rate) must be provided"
+	case cgRunits:
+		// Empty leaves Beeminder's default
+		if value != "" && !isKnownRunits(value) {
+			return "Rate units must be one of y, m, w, d, h"
+		}
 	}
-
 	return ""
 }
 
@@ -394,7 +647,7 @@ func handleEnterKey(m model) (tea.Model, tea.Cmd) {
 		m.appModel.createGoal.creating = true
 		return m, createGoalCmd(m.appModel.ctx, m.appModel.client, m.appModel.createGoal.slug(), m.appModel.createGoal.title(),
 			m.appModel.createGoal.goalType(), m.appModel.createGoal.gunits(), m.appModel.createGoal.goaldate(),
-			m.appModel.createGoal.goalval(), m.appModel.createGoal.rate())
+			m.appModel.createGoal.goalval(), m.appModel.createGoal.rate(), m.appModel.createGoal.runits(), m.appModel.createGoal.preset)
 	} else if m.appModel.mode == modeDatapointInput && !m.appModel.datapoint.submitting {
 		// Clear previous error
 		m.appModel.datapoint.err = ""
@@ -405,16 +658,27 @@ func handleEnterKey(m model) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
-		// Parse date to get timestamp. Interpret the entered calendar date in
-		// local time (matching validateDatapointInput) so the datapoint lands on
-		// the day the user intended rather than being shifted by the UTC offset.
+		// Send the date as a daystamp, the Beeminder day the user picked,
+		// rather than a timestamp, which a goal's deadline or the UTC offset
+		// could put on a neighbouring day.
 		date, _ := time.ParseInLocation("2006-01-02", m.appModel.datapoint.date(), time.Local)
-		timestamp := fmt.Sprintf("%d", date.Unix())
+		daystamp := date.Format("20060102")
+
+		// A hooks script may rewrite the value or comment, or refuse the add.
+		value, comment, err := activeHooks.BeforeAdd(*m.appModel.modalGoal, m.appModel.datapoint.submitValue(), m.appModel.datapoint.comment(), io.Discard)
+		if err != nil {
+			m.appModel.datapoint.err = err.Error()
+			return m, nil
+		}
 
 		// Set submitting state and submit datapoint asynchronously
 		m.appModel.datapoint.submitting = true
+		m.appModel.datapoint.celebration = ""
+		if v, err := strconv.ParseFloat(value, 64); err == nil && m.appModel.modalGoal.Datapoints != nil {
+			m.appModel.datapoint.celebration = celebrationFor(*m.appModel.modalGoal, v, date, time.Now())
+		}
 		return m, submitDatapointCmd(m.appModel.ctx, m.appModel.client, m.appModel.modalGoal.Slug,
-			timestamp, m.appModel.datapoint.value(), m.appModel.datapoint.comment())
+			"", daystamp, value, comment)
 	} else if m.appModel.mode == modeBrowse {
 		// Show goal details modal (existing functionality)
 		displayGoals := m.appModel.getDisplayGoals()
@@ -445,7 +709,7 @@ func handleNavigationUp(m model) (tea.Model, tea.Cmd) {
 		if len(displayGoals) > 0 {
 			m.appModel.hasNavigated = true
 			m.appModel.lastNavigationTime = time.Now()
-			cols := calculateColumns(m.appModel.width)
+			cols := calculateColumns(m.appModel.layout().gridWidth)
 			newCursor := m.appModel.cursor - cols
 			if newCursor >= 0 {
 				m.appModel.cursor = newCursor
@@ -465,7 +729,7 @@ func handleNavigationDown(m model) (tea.Model, tea.Cmd) {
 		if len(displayGoals) > 0 {
 			m.appModel.hasNavigated = true
 			m.appModel.lastNavigationTime = time.Now()
-			cols := calculateColumns(m.appModel.width)
+			cols := calculateColumns(m.appModel.layout().gridWidth)
 			newCursor := m.appModel.cursor + cols
 			if newCursor < len(displayGoals) {
 				m.appModel.cursor = newCursor
@@ -493,7 +757,7 @@ func handleNavigationLeft(m model) (tea.Model, tea.Cmd) {
 		if len(displayGoals) > 0 {
 			m.appModel.hasNavigated = true
 			m.appModel.lastNavigationTime = time.Now()
-			cols := calculateColumns(m.appModel.width)
+			cols := calculateColumns(m.appModel.layout().gridWidth)
 			currentCol := m.appModel.cursor % cols
 			if currentCol > 0 {
 				m.appModel.cursor--
@@ -521,7 +785,7 @@ func handleNavigationRight(m model) (tea.Model, tea.Cmd) {
 		if len(displayGoals) > 0 {
 			m.appModel.hasNavigated = true
 			m.appModel.lastNavigationTime = time.Now()
-			cols := calculateColumns(m.appModel.width)
+			cols := calculateColumns(m.appModel.layout().gridWidth)
 			currentCol := m.appModel.cursor % cols
 			if currentCol < cols-1 && m.appModel.cursor+1 < len(displayGoals) {
 				m.appModel.cursor++
@@ -542,11 +806,21 @@ func handleScrollUp(m model) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleUndo undoes the latest datapoint added or deleted through buzz since
+// the TUI started (see undoLastChangeCmd), from Browse or a goal's details.
+func handleUndo(m model) (tea.Model, tea.Cmd) {
+	if m.appModel.mode != modeBrowse && m.appModel.mode != modeGoalDetail || m.appModel.undoBusy {
+		return m, nil
+	}
+	m.appModel.undoBusy = true
+	return m, undoLastChangeCmd(m.appModel.ctx, m.appModel.client, m.appModel.startedAt, m.appModel.undone)
+}
+
 // handleScrollDown handles page down/d key
 func handleScrollDown(m model) (tea.Model, tea.Cmd) {
 	if m.appModel.mode == modeBrowse {
 		displayGoals := m.appModel.getDisplayGoals()
-		layout := gridLayout(m.appModel.width, m.appModel.height, len(displayGoals))
+		layout := gridLayout(m.appModel.layout().gridWidth, m.appModel.height, len(displayGoals))
 		if m.appModel.scrollRow < layout.totalRows-layout.visibleRows {
 			m.appModel.scrollRow++
 		}
@@ -554,6 +828,32 @@ func handleScrollDown(m model) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleJumpToGoal moves the grid cursor to the index target picks for the
+// current number of display goals, scrolling it into view.
+func handleJumpToGoal(m model, target func(n int) int) (tea.Model, tea.Cmd) {
+	if m.appModel.mode != modeBrowse {
+		return m, nil
+	}
+	displayGoals := m.appModel.getDisplayGoals()
+	if len(displayGoals) == 0 {
+		return m, nil
+	}
+	m.appModel.hasNavigated = true
+	m.appModel.lastNavigationTime = time.Now()
+	m.appModel.cursor = max(0, min(target(len(displayGoals)), len(displayGoals)-1))
+	updateScrollForCursor(&m, len(displayGoals))
+	return m, navigationTimeoutCmd(navigationTimeout)
+}
+
+// handleHalfPage handles ctrl+d (dir 1) and ctrl+u (dir -1): the cursor moves
+// half a screen of rows, staying in its column, and stops at the first/last
+// goal.
+func handleHalfPage(m model, dir int) (tea.Model, tea.Cmd) {
+	layout := gridLayout(m.appModel.layout().gridWidth, m.appModel.height, len(m.appModel.getDisplayGoals()))
+	step := max(1, layout.visibleRows/2) * layout.cols * dir
+	return handleJumpToGoal(m, func(int) int { return m.appModel.cursor + step })
+}
+
 // handleRefresh handles the 'r' key for manual refresh
 func handleRefresh(m model) (tea.Model, tea.Cmd) {
 	if m.appModel.mode == modeBrowse {
@@ -583,6 +883,20 @@ func handleEnterSearch(m model) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// handleCycleFilter handles the 'f' key for switching saved filters
+func handleCycleFilter(m model) (tea.Model, tea.Cmd) {
+	if m.appModel.mode == modeBrowse {
+		m.appModel.cycleFilter()
+	}
+	return m, nil
+}
+
+// handleEnterJump handles the ' key for type-ahead jump
+func handleEnterJump(m model) (tea.Model, tea.Cmd) {
+	m.appModel.enterJump()
+	return m, nil
+}
+
 // handleCreateGoal handles the 'n' key for creating a new goal
 func handleCreateGoal(m model) (tea.Model, tea.Cmd) {
 	if m.appModel.mode == modeBrowse && !m.appModel.searchActive {
@@ -607,13 +921,18 @@ func handleMouseClick(m model, msg tea.MouseMsg) (tea.Model, tea.Cmd) {
 	}
 	gridRow := clickRow / gridCellHeight
 
-	// Calculate column based on terminal width
-	cols := calculateColumns(m.appModel.width)
+	// Calculate column based on the grid's width; clicks on the details
+	// pane beside it (on a wide terminal) aren't on a goal.
+	gridWidth := m.appModel.layout().gridWidth
+	if msg.X >= gridWidth {
+		return m, nil
+	}
+	cols := calculateColumns(gridWidth)
 	if cols < 1 {
 		cols = 1
 	}
 	// Approximate cell width
-	cellWidth := m.appModel.width / cols
+	cellWidth := gridWidth / cols
 	if cellWidth < 1 {
 		cellWidth = 1
 	}
@@ -1,11 +1,16 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
 	"testing"
 	"time"
 	"unicode/utf8"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 // mockKeyMsg creates a mock KeyMsg for testing
@@ -178,6 +183,7 @@ func TestValidateCreateGoalInput(t *testing.T) {
 		goaldate    string
 		goalval     string
 		rate        string
+		runits      string
 		expectError bool
 		errorMsg    string
 	}{
@@ -409,12 +415,56 @@ func TestValidateCreateGoalInput(t *testing.T) {
 			expectError: false,
 			errorMsg:    "",
 		},
+		{
+			name:        "misspelled goal type",
+			slug:        "testgoal",
+			title:       "Test Goal",
+			goalType:    "hustlr",
+			gunits:      "units",
+			goalval:     "10",
+			rate:        "1",
+			expectError: true,
+			errorMsg:    `Unknown goal type "hustlr" (did you mean hustler?)`,
+		},
+		{
+			name:        "unknown goal type passes through",
+			slug:        "testgoal",
+			title:       "Test Goal",
+			goalType:    "zzzzzz",
+			gunits:      "units",
+			goalval:     "10",
+			rate:        "1",
+			expectError: false,
+		},
+		{
+			name:        "valid rate units",
+			slug:        "testgoal",
+			title:       "Test Goal",
+			goalType:    "hustler",
+			gunits:      "units",
+			goalval:     "10",
+			rate:        "1",
+			runits:      "d",
+			expectError: false,
+		},
+		{
+			name:        "invalid rate units",
+			slug:        "testgoal",
+			title:       "Test Goal",
+			goalType:    "hustler",
+			gunits:      "units",
+			goalval:     "10",
+			rate:        "1",
+			runits:      "x",
+			expectError: true,
+			errorMsg:    "Rate units must be one of y, m, w, d, h",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			result := validateCreateGoalInput(tt.slug, tt.title, tt.goalType, tt.gunits,
-				tt.goaldate, tt.goalval, tt.rate)
+				tt.goaldate, tt.goalval, tt.rate, tt.runits)
 			if tt.expectError {
 				if result == "" {
 					t.Errorf("Expected error message '%s', got no error", tt.errorMsg)
@@ -819,7 +869,7 @@ func TestIssueEdgeCases(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := validateCreateGoalInput("slug", "title", "hustler", "units", tt.goaldate, tt.goalval, tt.rate)
+			result := validateCreateGoalInput("slug", "title", "hustler", "units", tt.goaldate, tt.goalval, tt.rate, "")
 			gotErr := result != ""
 			if gotErr != tt.wantErr {
 				t.Errorf("got error=%v, want error=%v; error message: %q", gotErr, tt.wantErr, result)
@@ -883,7 +933,7 @@ func TestHandleTabKeyCreateGoal(t *testing.T) {
 // TestHandleTabKeyDatapoint verifies tab/shift+tab cycle focus through the
 // datapoint form when in input mode.
 func TestHandleTabKeyDatapoint(t *testing.T) {
-	m := model{appModel: appModel{mode: modeDatapointInput, datapoint: newDatapointForm("1")}}
+	m := model{appModel: appModel{mode: modeDatapointInput, datapoint: newDatapointForm("1", "")}}
 
 	updated, _ := handleTabKey(m, false)
 	if got := mustModel(t, updated).appModel.datapoint.focus; got != 1 {
@@ -891,7 +941,7 @@ func TestHandleTabKeyDatapoint(t *testing.T) {
 	}
 
 	// Shift+tab from focus 0 wraps to the last field (index 2).
-	updated, _ = handleTabKey(model{appModel: appModel{mode: modeDatapointInput, datapoint: newDatapointForm("1")}}, true)
+	updated, _ = handleTabKey(model{appModel: appModel{mode: modeDatapointInput, datapoint: newDatapointForm("1", "")}}, true)
 	if got := mustModel(t, updated).appModel.datapoint.focus; got != 2 {
 		t.Errorf("after shift+tab wrap, datapoint.focus = %d, want 2", got)
 	}
@@ -915,7 +965,7 @@ func TestHandleBackspaceCreateGoal(t *testing.T) {
 // TestHandleBackspaceDatapoint verifies backspace trims the focused datapoint
 // field when in input mode.
 func TestHandleBackspaceDatapoint(t *testing.T) {
-	dp := newDatapointForm("1")
+	dp := newDatapointForm("1", "")
 	dp.focus = dpComment
 	dp.fields[dpComment].value = "note😀"
 	m := model{appModel: appModel{mode: modeDatapointInput, datapoint: dp}}
@@ -1061,7 +1111,7 @@ func TestHandleDatapointInputUnicode(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Create a mock model in input mode with comment field focused.
 			// Start the comment empty so the typed character is the whole value.
-			dp := newDatapointForm("1")
+			dp := newDatapointForm("1", "")
 			dp.fields[dpComment].value = ""
 			dp.focus = dpComment
 			m := model{
@@ -1836,111 +1886,106 @@ func TestHandleMouseClick(t *testing.T) {
 }
 
 // handleAddDatapoint is the modal-open path that pre-fills the input form
-// with the goal's last datapoint value (defaulting to "1" if the value is
-// zero or the API errors). It was previously untestable because every
-// invocation hit the real HTTPClient; now FakeClient lets us cover all
-// three branches cheaply.
-
-func TestHandleAddDatapointPrefillsLastValue(t *testing.T) {
+// with the goal's last datapoint value (keeping "1" if the value is zero or
+// the API errors). The form opens at once with "1"; the value is fetched by
+// the returned command and filled in when its message arrives.
+
+// addDatapointWithLastValue opens the datapoint form on goal slug and
+// delivers the last-value fetch through Update, reporting whether the client
+// was asked for it.
+func addDatapointWithLastValue(t *testing.T, slug string, value float64, err error) (appModel, bool) {
+	t.Helper()
+	called := false
 	fake := &FakeClient{
-		GetLastDatapointValueFunc: func(slug string) (float64, error) {
-			if slug != "exercise" {
-				t.Errorf("client called with slug=%q, want exercise", slug)
+		GetLastDatapointValueFunc: func(got string) (float64, error) {
+			called = true
+			if got != slug {
+				t.Errorf("client called with slug=%q, want %s", got, slug)
 			}
-			return 2.5, nil
+			return value, err
 		},
 	}
 	m := model{
 		state: "app",
 		appModel: appModel{
 			client:    fake,
-			modalGoal: &Goal{Slug: "exercise"},
+			modalGoal: &Goal{Slug: slug},
 			mode:      modeGoalDetail,
 		},
 	}
 
-	updated, _ := handleAddDatapoint(m)
-	got := updated.(model).appModel
-	if got.datapoint.value() != "2.5" {
-		t.Errorf("inputValue = %q, want %q", got.datapoint.value(), "2.5")
+	updated, cmd := handleAddDatapoint(m)
+	got := mustModel(t, updated)
+	if called {
+		t.Error("GetLastDatapointValue should run in a command, not in Update")
 	}
-	if got.mode != modeDatapointInput {
-		t.Error("expected mode to be modeDatapointInput after handleAddDatapoint")
+	if got.appModel.mode != modeDatapointInput || got.appModel.datapoint.value() != "1" {
+		t.Fatalf("form should open at once with the placeholder; mode = %d, value = %q", got.appModel.mode, got.appModel.datapoint.value())
 	}
-	if got.datapoint.comment() != "Added via buzz" {
-		t.Errorf("inputComment = %q, want default %q", got.datapoint.comment(), "Added via buzz")
+	if got.appModel.datapoint.comment() != "Added via buzz" {
+		t.Errorf("inputComment = %q, want default %q", got.appModel.datapoint.comment(), "Added via buzz")
+	}
+	if cmd == nil {
+		t.Fatal("expected a command fetching the last value")
+	}
+	got = mustModel(t, mustTeaModel(got.Update(cmd())))
+	return got.appModel, called
+}
+
+func TestHandleAddDatapointPrefillsLastValue(t *testing.T) {
+	got, _ := addDatapointWithLastValue(t, "exercise", 2.5, nil)
+	if got.datapoint.value() != "2.5" {
+		t.Errorf("inputValue = %q, want %q", got.datapoint.value(), "2.5")
 	}
 }
 
 func TestHandleAddDatapointDefaultsToOneOnZeroValue(t *testing.T) {
 	// API returned the goal but the last datapoint value was zero — buzz
-	// treats that as "no useful default" and falls back to "1".
+	// treats that as "no useful default" and keeps "1".
 	// Track whether the client was called so a future change that stops
 	// querying it (and just hard-codes "1") would fail this test.
-	called := false
-	fake := &FakeClient{
-		GetLastDatapointValueFunc: func(string) (float64, error) {
-			called = true
-			return 0, nil
-		},
-	}
-	m := model{
-		state: "app",
-		appModel: appModel{
-			client:    fake,
-			modalGoal: &Goal{Slug: "any"},
-			mode:      modeGoalDetail,
-		},
-	}
-
-	updated, _ := handleAddDatapoint(m)
+	got, called := addDatapointWithLastValue(t, "any", 0, nil)
 	if !called {
 		t.Error("expected GetLastDatapointValue to be called")
 	}
-	got := updated.(model).appModel
 	if got.datapoint.value() != "1" {
 		t.Errorf("inputValue with zero last value = %q, want %q", got.datapoint.value(), "1")
 	}
-	if got.mode != modeDatapointInput {
-		t.Error("expected mode to be modeDatapointInput after handleAddDatapoint")
-	}
-	if got.datapoint.comment() != "Added via buzz" {
-		t.Errorf("inputComment = %q, want default %q", got.datapoint.comment(), "Added via buzz")
-	}
 }
 
 func TestHandleAddDatapointDefaultsToOneOnFetchError(t *testing.T) {
 	// API errored — same fallback to "1" rather than blocking the modal.
 	// Track the call so the fallback can't accidentally short-circuit it.
-	called := false
-	fake := &FakeClient{
-		GetLastDatapointValueFunc: func(string) (float64, error) {
-			called = true
-			return 0, errFakeNotConfigured
-		},
-	}
-	m := model{
-		state: "app",
-		appModel: appModel{
-			client:    fake,
-			modalGoal: &Goal{Slug: "any"},
-			mode:      modeGoalDetail,
-		},
-	}
-
-	updated, _ := handleAddDatapoint(m)
+	got, called := addDatapointWithLastValue(t, "any", 0, errFakeNotConfigured)
 	if !called {
 		t.Error("expected GetLastDatapointValue to be called")
 	}
-	got := updated.(model).appModel
 	if got.datapoint.value() != "1" {
 		t.Errorf("inputValue on fetch error = %q, want %q", got.datapoint.value(), "1")
 	}
-	if got.mode != modeDatapointInput {
-		t.Error("expected mode to be modeDatapointInput after handleAddDatapoint")
+}
+
+func TestLastValueDoesNotOverwriteEdits(t *testing.T) {
+	m := model{state: "app", appModel: appModel{
+		client:    &FakeClient{},
+		modalGoal: &Goal{Slug: "exercise"},
+		mode:      modeGoalDetail,
+	}}
+	m = mustModel(t, mustTeaModel(handleAddDatapoint(m)))
+	m.appModel.datapoint.focus = dpValue
+	m = mustModel(t, mustTeaModel(m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("5")})))
+
+	m = mustModel(t, mustTeaModel(m.Update(lastValueLoadedMsg{slug: "exercise", value: 2.5})))
+	if got := m.appModel.datapoint.value(); got != "15" {
+		t.Errorf("value = %q, want the user's edit %q", got, "15")
 	}
-	if got.datapoint.comment() != "Added via buzz" {
-		t.Errorf("inputComment = %q, want default %q", got.datapoint.comment(), "Added via buzz")
+
+	// A late value for another goal's form is ignored too.
+	m.appModel.datapoint = newDatapointForm("1", "")
+	m.appModel.datapoint.placeholder = "1"
+	m = mustModel(t, mustTeaModel(m.Update(lastValueLoadedMsg{slug: "reading", value: 2.5})))
+	if got := m.appModel.datapoint.value(); got != "1" {
+		t.Errorf("value = %q after another goal's last value, want %q", got, "1")
 	}
 }
 
@@ -2054,7 +2099,7 @@ func TestMouseClickIntegration(t *testing.T) {
 func TestHandleEscapeKeyLadder(t *testing.T) {
 	t.Run("datapoint input cancels back to goal detail", func(t *testing.T) {
 		m := model{appModel: appModel{modalGoal: &Goal{Slug: "g"}, mode: modeGoalDetail}}
-		m.appModel.startDatapointInput(newDatapointForm("1"))
+		m.appModel.startDatapointInput(newDatapointForm("1", ""))
 		got := mustModel(t, mustTeaModel(handleEscapeKey(m))).appModel
 		if got.mode != modeGoalDetail {
 			t.Errorf("Esc from datapoint input: mode = %d, want modeGoalDetail", got.mode)
@@ -2063,7 +2108,7 @@ func TestHandleEscapeKeyLadder(t *testing.T) {
 
 	t.Run("Esc is locked while a datapoint submit is in-flight", func(t *testing.T) {
 		m := model{appModel: appModel{modalGoal: &Goal{Slug: "g"}, mode: modeDatapointInput}}
-		m.appModel.datapoint = newDatapointForm("1")
+		m.appModel.datapoint = newDatapointForm("1", "")
 		m.appModel.datapoint.submitting = true
 		got := mustModel(t, mustTeaModel(handleEscapeKey(m))).appModel
 		if got.mode != modeDatapointInput {
@@ -2194,3 +2239,460 @@ func TestModeHandlerTransitions(t *testing.T) {
 		}
 	})
 }
+
+// TestAuthExpiredFlow covers the 401 recovery path: a rejected token replaces
+// the grid with the re-login prompt, and Enter hands over to the auth screen.
+func TestAuthExpiredFlow(t *testing.T) {
+	unauthorized := fmt.Errorf("failed to fetch goals: %w", &apiStatusError{status: http.StatusUnauthorized})
+
+	m := model{state: "app", appModel: appModel{loading: true, config: &Config{Username: "u"}}}
+	m = mustModel(t, mustTeaModel(m.Update(goalsLoadedMsg{err: unauthorized})))
+	if !m.appModel.authExpired {
+		t.Fatal("401 from loading goals should set authExpired")
+	}
+	if m.appModel.err != nil {
+		t.Errorf("401 should not be reported as a generic load error, got %v", m.appModel.err)
+	}
+	if view := m.View(); !strings.Contains(view, "auth token is invalid or has expired") {
+		t.Errorf("view should show the re-login prompt, got %q", view)
+	}
+
+	// Unrelated keys are ignored rather than navigating an empty grid.
+	m = mustModel(t, mustTeaModel(m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})))
+	if m.state != "app" || !m.appModel.authExpired {
+		t.Error("non-Enter keys should leave the prompt up")
+	}
+
+	m = mustModel(t, mustTeaModel(m.Update(tea.KeyMsg{Type: tea.KeyEnter})))
+	if m.state != "auth" {
+		t.Errorf("Enter should switch to the auth screen, state = %q", m.state)
+	}
+
+	t.Run("401 while submitting a datapoint", func(t *testing.T) {
+		m := model{state: "app", appModel: appModel{modalGoal: &Goal{Slug: "g"}, mode: modeDatapointInput}}
+		m.appModel.datapoint.submitting = true
+		m = mustModel(t, mustTeaModel(m.Update(datapointSubmittedMsg{err: unauthorized})))
+		if !m.appModel.authExpired {
+			t.Error("401 from a datapoint submit should set authExpired")
+		}
+	})
+}
+
+// TestFocusRefresh checks that regaining focus reloads goals only after the
+// terminal has been in the background for at least focusRefreshThreshold.
+func TestFocusRefresh(t *testing.T) {
+	m := model{state: "app", appModel: appModel{client: &FakeClient{}}}
+
+	m = mustModel(t, mustTeaModel(m.Update(tea.BlurMsg{})))
+	if m.appModel.blurredAt.IsZero() {
+		t.Fatal("BlurMsg should record when focus was lost")
+	}
+
+	tm, cmd := m.Update(tea.FocusMsg{})
+	if cmd != nil {
+		t.Error("a brief blur should not trigger a refresh")
+	}
+	m = mustModel(t, tm)
+	if !m.appModel.blurredAt.IsZero() {
+		t.Error("FocusMsg should clear blurredAt")
+	}
+
+	m.appModel.blurredAt = time.Now().Add(-focusRefreshThreshold - time.Second)
+	if _, cmd := m.Update(tea.FocusMsg{}); cmd == nil {
+		t.Error("focus after a long blur should trigger a refresh")
+	}
+
+	// A focus event without a preceding blur (e.g. at startup) is ignored.
+	m.appModel.blurredAt = time.Time{}
+	if _, cmd := m.Update(tea.FocusMsg{}); cmd != nil {
+		t.Error("focus without a prior blur should not trigger a refresh")
+	}
+}
+
+// TestSuspendResume checks that ctrl+z suspends, that refreshes are skipped
+// while suspended, and that resuming catches up with a refresh.
+func TestSuspendResume(t *testing.T) {
+	m := model{state: "app", appModel: appModel{client: &FakeClient{}, refreshActive: true}}
+
+	tm, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlZ})
+	m = mustModel(t, tm)
+	if !m.suspended {
+		t.Fatal("ctrl+z should mark the model suspended")
+	}
+	if cmd == nil {
+		t.Fatal("ctrl+z should return the suspend command")
+	}
+	if _, ok := cmd().(tea.SuspendMsg); !ok {
+		t.Errorf("ctrl+z command should produce SuspendMsg")
+	}
+
+	// A queued tick only reschedules; it must not start a load.
+	if _, cmd := m.Update(refreshTickMsg{}); cmd == nil {
+		t.Error("a tick while suspended should keep the ticker alive")
+	}
+
+	tm, cmd = m.Update(tea.ResumeMsg{})
+	m = mustModel(t, tm)
+	if m.suspended {
+		t.Error("ResumeMsg should clear suspended")
+	}
+	if cmd == nil {
+		t.Error("resuming should re-measure the window and refresh goals")
+	}
+}
+
+// TestJumpKeys covers Home/End (g/G) and ctrl+d/ctrl+u half-page movement.
+func TestJumpKeys(t *testing.T) {
+	// 10 goals at 40x16: 2 columns, 5 rows, 3 visible rows (half page = 1 row).
+	goals := make([]Goal, 10)
+	for i := range goals {
+		goals[i] = Goal{Slug: fmt.Sprintf("goal%d", i)}
+	}
+	newModel := func(cursor int) model {
+		return model{appModel: appModel{goals: goals, width: 40, height: 16, cursor: cursor}}
+	}
+	press := func(m model, key tea.KeyMsg) appModel {
+		return mustModel(t, mustTeaModel(handleKeyPress(m, key))).appModel
+	}
+
+	tests := []struct {
+		name       string
+		cursor     int
+		key        tea.KeyMsg
+		wantCursor int
+		wantScroll int
+	}{
+		{"G jumps to the last goal", 0, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("G")}, 9, 2},
+		{"End jumps to the last goal", 3, tea.KeyMsg{Type: tea.KeyEnd}, 9, 2},
+		{"g jumps to the first goal", 9, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")}, 0, 0},
+		{"Home jumps to the first goal", 5, tea.KeyMsg{Type: tea.KeyHome}, 0, 0},
+		{"ctrl+d moves half a page down", 1, tea.KeyMsg{Type: tea.KeyCtrlD}, 3, 0},
+		{"ctrl+d stops at the last goal", 9, tea.KeyMsg{Type: tea.KeyCtrlD}, 9, 2},
+		{"ctrl+u stops at the first goal", 1, tea.KeyMsg{Type: tea.KeyCtrlU}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newModel(tt.cursor)
+			got := press(m, tt.key)
+			if got.cursor != tt.wantCursor {
+				t.Errorf("cursor = %d, want %d", got.cursor, tt.wantCursor)
+			}
+			if got.scrollRow != tt.wantScroll {
+				t.Errorf("scrollRow = %d, want %d", got.scrollRow, tt.wantScroll)
+			}
+			if !got.hasNavigated {
+				t.Error("jumping should show the selection highlight")
+			}
+		})
+	}
+}
+
+// TestTypeAheadJump checks that ' moves the cursor to the first fuzzy match
+// without filtering, and how the jump ends.
+func TestTypeAheadJump(t *testing.T) {
+	goals := []Goal{{Slug: "reading"}, {Slug: "workout"}, {Slug: "walking"}}
+	m := model{appModel: appModel{goals: goals, width: 80, height: 24}}
+	key := func(m model, s string) model {
+		return mustModel(t, mustTeaModel(handleKeyPress(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)})))
+	}
+
+	m = key(m, "'")
+	if !m.appModel.jumpActive {
+		t.Fatal("' should start a type-ahead jump")
+	}
+	m = key(m, "w")
+	m = key(m, "k")
+	if m.appModel.cursor != 1 || !m.appModel.hasNavigated {
+		t.Errorf("'wk should select workout, cursor = %d", m.appModel.cursor)
+	}
+	if got := len(m.appModel.getDisplayGoals()); got != len(goals) {
+		t.Errorf("jump must not filter the grid, got %d goals", got)
+	}
+	if m.appModel.searchQuery != "" {
+		t.Error("jump characters should not reach the search query")
+	}
+
+	m = mustModel(t, mustTeaModel(handleKeyPress(m, tea.KeyMsg{Type: tea.KeyBackspace})))
+	if m.appModel.jumpQuery != "w" || m.appModel.cursor != 1 {
+		t.Errorf("backspace should trim the query, got %q cursor %d", m.appModel.jumpQuery, m.appModel.cursor)
+	}
+
+	// Enter ends the jump and opens the selected goal.
+	m = mustModel(t, mustTeaModel(handleKeyPress(m, tea.KeyMsg{Type: tea.KeyEnter})))
+	if m.appModel.jumpActive {
+		t.Error("Enter should end the jump")
+	}
+	if m.appModel.mode != modeGoalDetail || m.appModel.modalGoal.Slug != "workout" {
+		t.Errorf("Enter should open the jumped-to goal, mode = %d", m.appModel.mode)
+	}
+
+	t.Run("Esc stops without quitting", func(t *testing.T) {
+		m := model{appModel: appModel{goals: goals, width: 80, height: 24}}
+		m = key(m, "'")
+		// Handled by the jump itself, so it never reaches the Esc ladder (which
+		// would quit from a bare Browse grid).
+		got, handled := handleJumpInput(m, tea.KeyMsg{Type: tea.KeyEsc})
+		if !handled {
+			t.Error("Esc during a jump should be consumed by the jump")
+		}
+		if got.appModel.jumpActive {
+			t.Error("Esc should end the jump")
+		}
+	})
+
+	t.Run("' is part of the query while searching", func(t *testing.T) {
+		m := model{appModel: appModel{goals: goals, searchActive: true}}
+		m = key(m, "'")
+		if m.appModel.jumpActive || m.appModel.searchQuery != "'" {
+			t.Errorf("' during search should be typed into the query, got jump=%v query=%q", m.appModel.jumpActive, m.appModel.searchQuery)
+		}
+	})
+}
+
+// TestGoalCreatedOpensFirstDatapoint checks that creating a goal in the TUI
+// goes straight to adding its first datapoint, and that a goal created with
+// failing template settings keeps the form open with the error.
+func TestGoalCreatedOpensFirstDatapoint(t *testing.T) {
+	m := model{state: "app", appModel: appModel{client: &FakeClient{}, mode: modeCreateGoal, createGoal: newCreateGoalForm()}}
+	m.appModel.createGoal.creating = true
+
+	tm, cmd := m.Update(goalCreatedMsg{goal: &Goal{Slug: "meditate", Gunits: "minutes"}})
+	m = mustModel(t, tm)
+	if m.appModel.mode != modeDatapointInput || m.appModel.modalGoal == nil || m.appModel.modalGoal.Slug != "meditate" {
+		t.Fatalf("mode = %v, modalGoal = %v; want datapoint input on meditate", m.appModel.mode, m.appModel.modalGoal)
+	}
+	if m.appModel.datapoint.focus != dpValue || m.appModel.datapoint.gunits != "minutes" {
+		t.Errorf("datapoint form focus = %d, gunits = %q", m.appModel.datapoint.focus, m.appModel.datapoint.gunits)
+	}
+	if cmd == nil {
+		t.Error("want commands to reload goals and the new goal's details")
+	}
+
+	m = model{state: "app", appModel: appModel{mode: modeCreateGoal, createGoal: newCreateGoalForm()}}
+	m = mustModel(t, mustTeaModel(m.Update(goalCreatedMsg{goal: &Goal{Slug: "meditate"}, err: fmt.Errorf("failed to set tags: boom")})))
+	if m.appModel.mode != modeCreateGoal || !strings.Contains(m.appModel.createGoal.err, "Goal meditate created, but failed to set tags") {
+		t.Errorf("mode = %v, err = %q", m.appModel.mode, m.appModel.createGoal.err)
+	}
+}
+
+// TestModalsFitAfterResize checks that each modal and form is laid out again
+// for the new size when the terminal is resized while it's open: every line
+// is indented alike and fits, and the key hints at the bottom stay on screen.
+func TestModalsFitAfterResize(t *testing.T) {
+	goal := Goal{Slug: "reading", Title: "Read more", Gunits: "pages", Datapoints: []Datapoint{
+		{Timestamp: 1, Value: 1}, {Timestamp: 2, Value: 2}, {Timestamp: 3, Value: 3}, {Timestamp: 4, Value: 4}, {Timestamp: 5, Value: 5},
+	}}
+	open := map[string]func(m *appModel){
+		"detail": func(m *appModel) { m.openGoalDetail(&goal) },
+		"datapoint": func(m *appModel) {
+			m.openGoalDetail(&goal)
+			m.startDatapointInput(newDatapointForm("1", goal.Gunits))
+		},
+		"create":    func(m *appModel) { m.openCreateGoal() },
+		"whats new": func(m *appModel) { m.openWhatsNew("- Faster startup\n- Templates") },
+	}
+	hints := map[string]string{"detail": "Close", "datapoint": "Esc: Cancel", "create": "Esc: Cancel", "whats new": "continue"}
+
+	for name, openModal := range open {
+		for _, size := range []tea.WindowSizeMsg{{Width: 120, Height: 40}, {Width: 44, Height: 18}} {
+			m := model{state: "app", appModel: appModel{config: &Config{Username: "u"}, goals: []Goal{goal}, width: 120, height: 40}}
+			openModal(&m.appModel)
+			m = mustModel(t, mustTeaModel(m.Update(size)))
+
+			view := m.View()
+			lines := strings.Split(view, "\n")
+			if len(lines) > size.Height {
+				t.Errorf("%s at %dx%d: %d lines, more than the terminal's height", name, size.Width, size.Height, len(lines))
+			}
+			indent := -1
+			for _, line := range lines {
+				if w := lipgloss.Width(line); w > size.Width {
+					t.Errorf("%s at %dx%d: line is %d wide: %q", name, size.Width, size.Height, w, line)
+				}
+				if strings.TrimSpace(line) == "" {
+					continue
+				}
+				lead := len(line) - len(strings.TrimLeft(line, " "))
+				if indent == -1 {
+					indent = lead
+				} else if lead != indent {
+					t.Errorf("%s at %dx%d: lines indented %d and %d; the modal is misaligned", name, size.Width, size.Height, indent, lead)
+					break
+				}
+			}
+			if !strings.Contains(view, hints[name]) {
+				t.Errorf("%s at %dx%d: the key hints were cut off:\n%s", name, size.Width, size.Height, view)
+			}
+		}
+	}
+}
+
+func TestFormsShowFieldHints(t *testing.T) {
+	goal := &Goal{Slug: "reading"}
+	m := model{state: "app", appModel: appModel{config: &Config{Username: "u"}, goals: []Goal{*goal}, width: 100, height: 40}}
+	m.appModel.openGoalDetail(goal)
+	m.appModel.startDatapointInput(newDatapointForm("1", ""))
+	m.appModel.datapoint.focus = dpValue
+
+	// The value is checked as it's typed, before Enter.
+	m = mustModel(t, mustTeaModel(m.Update(tea.KeyMsg{Type: tea.KeyBackspace})))
+	if view := m.View(); !strings.Contains(view, "↳ Value cannot be empty") {
+		t.Errorf("emptied value has no hint:\n%s", view)
+	}
+
+	// Enter's error isn't repeated below the form when the hint explains it.
+	m = mustModel(t, mustTeaModel(m.Update(tea.KeyMsg{Type: tea.KeyEnter})))
+	if view := m.View(); strings.Contains(view, "Error: Value cannot be empty") {
+		t.Errorf("hint repeated as an error:\n%s", view)
+	}
+
+	// Create-goal fields are checked as focus leaves them.
+	m.appModel.closeModal()
+	m.appModel.openCreateGoal()
+	m = mustModel(t, mustTeaModel(m.Update(tea.KeyMsg{Type: tea.KeyTab})))
+	if view := m.View(); !strings.Contains(view, "↳ Slug cannot be empty") || strings.Contains(view, "↳ Title") {
+		t.Errorf("create form hints after leaving the slug:\n%s", view)
+	}
+}
+
+func TestChargeForm(t *testing.T) {
+	type call struct {
+		amount float64
+		note   string
+		dryrun bool
+	}
+	var calls []call
+	client := &FakeClient{
+		CreateChargeFunc: func(amount float64, note string, dryrun bool) (*Charge, error) {
+			calls = append(calls, call{amount, note, dryrun})
+			return &Charge{ID: "c1", Amount: amount, Note: note, Username: "u"}, nil
+		},
+	}
+	m := model{state: "app", appModel: appModel{ctx: context.Background(), client: client, config: &Config{Username: "u"}, width: 100, height: 40}}
+	key := func(k string) tea.Cmd {
+		var msg tea.KeyMsg
+		switch k {
+		case "tab":
+			msg = tea.KeyMsg{Type: tea.KeyTab}
+		case "enter":
+			msg = tea.KeyMsg{Type: tea.KeyEnter}
+		case "esc":
+			msg = tea.KeyMsg{Type: tea.KeyEsc}
+		default:
+			msg = tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+		}
+		tm, cmd := m.Update(msg)
+		m = mustModel(t, tm)
+		return cmd
+	}
+	typeText := func(s string) {
+		for _, r := range s {
+			key(string(r))
+		}
+	}
+
+	key("$")
+	if m.appModel.mode != modeCharge {
+		t.Fatalf("$ should open the charge form, mode = %d", m.appModel.mode)
+	}
+
+	// An invalid amount is caught before anything is sent.
+	typeText("0.5")
+	key("tab")
+	typeText("yes")
+	if cmd := key("enter"); cmd != nil || !strings.Contains(m.View(), "Amount must be at least 1.00") {
+		t.Fatalf("a 0.50 charge should be refused:\n%s", m.View())
+	}
+
+	// Enter runs a dry run; its result waits for confirmation.
+	m.appModel.charge = newChargeForm()
+	typeText("5")
+	key("tab")
+	typeText("Missed the gym")
+	cmd := key("enter")
+	if cmd == nil || !m.appModel.charge.checking {
+		t.Fatal("Enter should start a dry run")
+	}
+	m = mustModel(t, mustTeaModel(m.Update(cmd())))
+	if len(calls) != 1 || !calls[0].dryrun || calls[0].amount != 5 || calls[0].note != "Missed the gym" {
+		t.Fatalf("calls = %+v, want one dry run of $5", calls)
+	}
+	if view := m.View(); !strings.Contains(view, "Dry run: Would charge $5.00") || !strings.Contains(view, "y: Charge") {
+		t.Errorf("dry run result not shown:\n%s", view)
+	}
+
+	// n goes back to editing without charging; typing works again.
+	key("n")
+	typeText("!")
+	if m.appModel.charge.preview != nil || m.appModel.charge.note() != "Missed the gym!" {
+		t.Errorf("n should return to editing; note = %q", m.appModel.charge.note())
+	}
+
+	// y on a fresh dry run makes the real charge.
+	m = mustModel(t, mustTeaModel(m.Update(key("enter")())))
+	cmd = key("y")
+	if cmd == nil || !m.appModel.charge.charging {
+		t.Fatal("y should make the charge")
+	}
+	if key("esc"); m.appModel.mode != modeCharge {
+		t.Error("Esc shouldn't close the form while the charge is in flight")
+	}
+	m = mustModel(t, mustTeaModel(m.Update(cmd())))
+	if len(calls) != 3 || calls[2].dryrun || calls[2].note != "Missed the gym!" {
+		t.Fatalf("calls = %+v, want a real charge last", calls)
+	}
+	if view := m.View(); !strings.Contains(view, "Charged $5.00 (charge c1)") {
+		t.Errorf("charge not reported:\n%s", view)
+	}
+	key("x")
+	if m.appModel.mode != modeBrowse {
+		t.Error("any key should close the form once the charge is made")
+	}
+}
+
+func TestCommandPalette(t *testing.T) {
+	m := model{state: "app", appModel: appModel{ctx: context.Background(), client: &FakeClient{}, config: &Config{Username: "u"}, width: 100, height: 40}}
+	key := func(msg tea.KeyMsg) tea.Cmd {
+		tm, cmd := m.Update(msg)
+		m = mustModel(t, tm)
+		return cmd
+	}
+	typeText := func(s string) {
+		for _, r := range s {
+			key(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+
+	typeText(":")
+	if m.appModel.mode != modePalette {
+		t.Fatalf(": should open the palette, mode = %d", m.appModel.mode)
+	}
+	if view := m.View(); !strings.Contains(view, "Charge yourself") || !strings.Contains(view, "Create a goal") {
+		t.Errorf("palette should list the actions:\n%s", view)
+	}
+
+	// Typing narrows the list; q is part of the query, not Quit.
+	typeText("chq")
+	if view := m.View(); !strings.Contains(view, "No matching commands") {
+		t.Errorf("chq should match nothing:\n%s", view)
+	}
+	key(tea.KeyMsg{Type: tea.KeyBackspace})
+	if view := m.View(); !strings.Contains(view, "Charge yourself") || strings.Contains(view, "Create a goal") {
+		t.Errorf("ch should match only the charge action:\n%s", view)
+	}
+
+	// Enter runs the selected action.
+	key(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.appModel.mode != modeCharge {
+		t.Fatalf("Enter on Charge yourself should open the charge form, mode = %d", m.appModel.mode)
+	}
+
+	// Escape closes the palette without running anything.
+	m.appModel.closeCharge()
+	typeText(":")
+	key(tea.KeyMsg{Type: tea.KeyDown})
+	key(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.appModel.mode != modeBrowse {
+		t.Errorf("Esc should close the palette, mode = %d", m.appModel.mode)
+	}
+}
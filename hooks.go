@@ -0,0 +1,294 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+)
+
+// Hooks let power users customize buzz with a script: "hooks" in ~/.buzzrc
+// names a Starlark file (a small, sandboxed dialect of Python) that may define
+//
+//	before_add(goal, value, comment)  called before `buzz add` or the TUI's
+//	                                  add-datapoint form submits. Return None
+//	                                  to add as is, a number to replace the
+//	                                  value, or a dict with "value" and/or
+//	                                  "comment". fail("why") refuses the add.
+//	cell_text(goal)                   text for the second line of the goal's
+//	                                  grid cell, or None for the default
+//
+// goal is a struct of the goal's fields (goal.slug, goal.safebuf, …; see
+// goalStarlarkValue). Scripts can't touch the filesystem or network, and
+// each call is cut off after hookMaxSteps steps so a runaway loop can't hang
+// the TUI.
+
+// hookMaxSteps bounds the work one hook call may do.
+const hookMaxSteps = 1_000_000
+
+// scriptHooks is a loaded hooks script. Its globals are frozen after loading,
+// so hooks may be called from any goroutine.
+type scriptHooks struct {
+	path      string
+	beforeAdd starlark.Callable // nil when the script doesn't define it
+	cellText  starlark.Callable // nil when the script doesn't define it
+}
+
+// activeHooks is the hooks script from ~/.buzzrc, set by useConfiguredHooks
+// in the commands that run hooks; nil when none is configured.
+var activeHooks *scriptHooks
+
+// errAddVetoed wraps a before_add hook's refusal.
+var errAddVetoed = errors.New("refused by hooks")
+
+// loadConfiguredHooks loads the hooks script named in ~/.buzzrc, returning nil
+// when buzz isn't logged in or no script is configured.
+func loadConfiguredHooks() (*scriptHooks, error) {
+	if !ConfigExists() {
+		return nil, nil
+	}
+	config, err := LoadConfig()
+	if err != nil || config.Hooks == "" {
+		// A broken config is reported by whichever command needs it.
+		return nil, nil
+	}
+	return loadHooks(expandHome(config.Hooks))
+}
+
+// useConfiguredHooks loads the hooks script named in ~/.buzzrc into
+// activeHooks. Only the commands that run hooks call it, so a broken script
+// doesn't stop any other command; here it's reported and buzz carries on
+// without hooks.
+func useConfiguredHooks(stderr io.Writer) {
+	hooks, err := loadConfiguredHooks()
+	if err != nil {
+		fmt.Fprintf(stderr, "Warning: Failed to load hooks, continuing without them: %s\n", err)
+		return
+	}
+	activeHooks = hooks
+}
+
+// loadHooks runs a hooks script and picks out the hooks it defines.
+func loadHooks(path string) (*scriptHooks, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	thread := newHookThread(path, io.Discard)
+	opts := &syntax.FileOptions{Set: true, While: true, TopLevelControl: true, GlobalReassign: true}
+	globals, err := starlark.ExecFileOptions(opts, thread, path, src, nil)
+	if err != nil {
+		return nil, hookError(err)
+	}
+	globals.Freeze()
+
+	hooks := &scriptHooks{path: path}
+	for name, dst := range map[string]*starlark.Callable{"before_add": &hooks.beforeAdd, "cell_text": &hooks.cellText} {
+		v, ok := globals[name]
+		if !ok {
+			continue
+		}
+		fn, ok := v.(starlark.Callable)
+		if !ok {
+			return nil, fmt.Errorf("%s: %s is a %s, not a function", path, name, v.Type())
+		}
+		*dst = fn
+	}
+	return hooks, nil
+}
+
+// newHookThread returns a thread for one hook call; print() goes to out.
+func newHookThread(name string, out io.Writer) *starlark.Thread {
+	thread := &starlark.Thread{
+		Name:  name,
+		Print: func(_ *starlark.Thread, msg string) { fmt.Fprintln(out, msg) },
+	}
+	thread.SetMaxExecutionSteps(hookMaxSteps)
+	return thread
+}
+
+// hookError shortens a Starlark runtime error to its message and the script
+// line it happened on. Syntax errors already carry their position.
+func hookError(err error) error {
+	var evalErr *starlark.EvalError
+	if !errors.As(err, &evalErr) {
+		return err
+	}
+	for i := len(evalErr.CallStack) - 1; i >= 0; i-- {
+		if pos := evalErr.CallStack[i].Pos; pos.Filename() != "<builtin>" {
+			return fmt.Errorf("line %d: %s", pos.Line, evalErr.Msg)
+		}
+	}
+	return errors.New(evalErr.Msg)
+}
+
+// BeforeAdd runs the before_add hook, if any, returning the value and comment
+// to submit. A refusal comes back wrapped in errAddVetoed; print() output
+// goes to out.
+func (h *scriptHooks) BeforeAdd(goal Goal, value, comment string, out io.Writer) (string, string, error) {
+	if h == nil || h.beforeAdd == nil {
+		return value, comment, nil
+	}
+	var v starlark.Value = starlark.String(value)
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		v = starlark.Float(f)
+	}
+	args := starlark.Tuple{goalStarlarkValue(goal), v, starlark.String(comment)}
+	result, err := starlark.Call(newHookThread(h.path, out), h.beforeAdd, args, nil)
+	if err != nil {
+		var evalErr *starlark.EvalError
+		if errors.As(err, &evalErr) && strings.HasPrefix(evalErr.Msg, "fail: ") {
+			return "", "", fmt.Errorf("%w: %s", errAddVetoed, strings.TrimPrefix(evalErr.Msg, "fail: "))
+		}
+		return "", "", fmt.Errorf("before_add: %w", hookError(err))
+	}
+
+	switch r := result.(type) {
+	case starlark.NoneType:
+		return value, comment, nil
+	case *starlark.Dict:
+		if nv, found, _ := r.Get(starlark.String("value")); found {
+			if value, err = hookNumber(nv); err != nil {
+				return "", "", err
+			}
+		}
+		if c, found, _ := r.Get(starlark.String("comment")); found {
+			s, ok := starlark.AsString(c)
+			if !ok {
+				return "", "", fmt.Errorf("before_add: comment must be a string, got %s", c.Type())
+			}
+			comment = s
+		}
+		return value, comment, nil
+	default:
+		value, err = hookNumber(result)
+		return value, comment, err
+	}
+}
+
+// hookNumber formats a value a hook returned as a datapoint value: a number,
+// or a string holding one.
+func hookNumber(v starlark.Value) (string, error) {
+	if s, ok := starlark.AsString(v); ok {
+		if _, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+			return strings.TrimSpace(s), nil
+		}
+	} else if f, ok := starlark.AsFloat(v); ok && !math.IsInf(f, 0) && !math.IsNaN(f) {
+		return strconv.FormatFloat(f, 'f', -1, 64), nil
+	}
+	return "", fmt.Errorf("before_add: value must be a number, got %s", v.String())
+}
+
+// CellText runs the cell_text hook, if any, returning the goal's custom grid
+// text. ok is false when there's no hook, it returned None, or it failed, so
+// the caller shows its default instead.
+func (h *scriptHooks) CellText(goal Goal) (text string, ok bool) {
+	if h == nil || h.cellText == nil {
+		return "", false
+	}
+	// print() would scribble over the TUI, so it goes nowhere.
+	result, err := starlark.Call(newHookThread(h.path, io.Discard), h.cellText, starlark.Tuple{goalStarlarkValue(goal)}, nil)
+	if err != nil {
+		return "", false
+	}
+	s, isString := starlark.AsString(result)
+	return s, isString
+}
+
+// CellTexts runs the cell_text hook on each goal, returning the grid text for
+// those it customizes, fitted to the cell, by slug. It's nil when there's no
+// hook. The TUI computes these once per goal list rather than on every
+// redraw.
+func (h *scriptHooks) CellTexts(goals []Goal) map[string]string {
+	if h == nil || h.cellText == nil {
+		return nil
+	}
+	texts := make(map[string]string)
+	for _, goal := range goals {
+		if text, ok := h.CellText(goal); ok {
+			texts[goal.Slug] = fitCellText(text)
+		}
+	}
+	return texts
+}
+
+// goalStarlarkValue exposes a goal to hooks as a frozen struct. Optional
+// numbers are None when Beeminder doesn't set them.
+func goalStarlarkValue(goal Goal) starlark.Value {
+	optional := func(f *float64) starlark.Value {
+		if f == nil {
+			return starlark.None
+		}
+		return starlark.Float(*f)
+	}
+	tags := make([]starlark.Value, len(goal.Tags))
+	for i, tag := range goal.Tags {
+		tags[i] = starlark.String(tag)
+	}
+	s := starlarkstruct.FromStringDict(starlark.String("goal"), starlark.StringDict{
+		"slug":      starlark.String(goal.Slug),
+		"title":     starlark.String(goal.Title),
+		"goal_type": starlark.String(goal.GoalType),
+		"gunits":    starlark.String(goal.Gunits),
+		"runits":    starlark.String(goal.Runits),
+		"rate":      optional(goal.Rate),
+		"curval":    optional(goal.Curval),
+		"goalval":   optional(goal.Goalval),
+		"safebuf":   starlark.MakeInt(goal.Safebuf),
+		"losedate":  starlark.MakeInt64(goal.Losedate),
+		"pledge":    starlark.Float(goal.Pledge),
+		"baremin":   starlark.String(goal.Baremin),
+		"limsum":    starlark.String(goal.Limsum),
+		"autodata":  starlark.String(goal.Autodata),
+		"tags":      starlark.NewList(tags),
+		"due":       starlark.String(FormatGoalDueDate(goal)),
+	})
+	s.Freeze()
+	return s
+}
+
+// applyAddHook runs the before_add hook on a `buzz add` request, fetching the
+// goal for it. Without a before_add hook the request is returned unchanged
+// without an API call. It returns the request to send, a process exit code,
+// and done=true when the caller should stop (the add was refused, or the
+// hook failed).
+func applyAddHook(req addRequest, hooks *scriptHooks, client Client, stderr io.Writer) (addRequest, int, bool) {
+	if hooks == nil || hooks.beforeAdd == nil {
+		return req, 0, false
+	}
+	goal, err := client.FetchGoal(context.Background(), req.goalSlug)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %s\n", redactError(err))
+		return addRequest{}, 1, true
+	}
+	req.value, req.comment, err = hooks.BeforeAdd(*goal, req.value, req.comment, stderr)
+	if err != nil {
+		if errors.Is(err, errAddVetoed) {
+			fmt.Fprintf(stderr, "Error: Datapoint %s\n", err)
+		} else {
+			fmt.Fprintf(stderr, "Error: Hooks script %s: %s\n", hooks.path, err)
+		}
+		return addRequest{}, 1, true
+	}
+	return req, 0, false
+}
+
+// fitCellText pads or truncates a cell_text result to the grid cell's width,
+// like formatGoalSecondLine, keeping only its first line.
+func fitCellText(text string) string {
+	const width = 16
+	text, _, _ = strings.Cut(text, "\n")
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text + strings.Repeat(" ", width-len(runes))
+	}
+	return string(runes[:width-3]) + "..."
+}
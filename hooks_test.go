@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeHooks writes a hooks script to a temp file and loads it.
+func writeHooks(t *testing.T, src string) *scriptHooks {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "hooks.star")
+	if err := os.WriteFile(path, []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	hooks, err := loadHooks(path)
+	if err != nil {
+		t.Fatalf("loadHooks: %v", err)
+	}
+	return hooks
+}
+
+func TestLoadHooksErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		wantErr string
+	}{
+		{"syntax error", "def before_add(goal, value, comment)\n", "want ':'"},
+		{"runtime error", "x = 1\ny = x + None\n", "line 2: unknown binary op"},
+		{"not a function", "cell_text = 3\n", "cell_text is a int, not a function"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "hooks.star")
+			if err := os.WriteFile(path, []byte(tt.src), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			_, err := loadHooks(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBeforeAdd(t *testing.T) {
+	hooks := writeHooks(t, `
+def before_add(goal, value, comment):
+    if goal.slug == "weight" and value > 500:
+        fail("that's grams, not kg")
+    if goal.slug == "pages":
+        return value * 2
+    if goal.slug == "tagged":
+        print("tagging")
+        return {"comment": comment + " #" + goal.tags[0]}
+    if goal.slug == "text":
+        return "3.5"
+    if goal.slug == "bad":
+        return "lots"
+    if goal.slug == "loop":
+        while True:
+            pass
+    return None
+`)
+
+	tests := []struct {
+		name        string
+		goal        Goal
+		value       string
+		wantValue   string
+		wantComment string
+		wantOut     string
+		wantVeto    bool
+		wantErr     string
+	}{
+		{name: "unchanged", goal: Goal{Slug: "other"}, value: "1", wantValue: "1", wantComment: "hi"},
+		{name: "transformed", goal: Goal{Slug: "pages"}, value: "1.5", wantValue: "3", wantComment: "hi"},
+		{name: "comment", goal: Goal{Slug: "tagged", Tags: []string{"work"}}, value: "1", wantValue: "1", wantComment: "hi #work", wantOut: "tagging\n"},
+		{name: "numeric string", goal: Goal{Slug: "text"}, value: "1", wantValue: "3.5", wantComment: "hi"},
+		{name: "vetoed", goal: Goal{Slug: "weight"}, value: "800", wantVeto: true, wantErr: "that's grams, not kg"},
+		{name: "not a number", goal: Goal{Slug: "bad"}, value: "1", wantErr: "value must be a number"},
+		{name: "runaway", goal: Goal{Slug: "loop"}, value: "1", wantErr: "too many steps"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			value, comment, err := hooks.BeforeAdd(tt.goal, tt.value, "hi", &out)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				if errors.Is(err, errAddVetoed) != tt.wantVeto {
+					t.Errorf("errors.Is(err, errAddVetoed) = %v, want %v", !tt.wantVeto, tt.wantVeto)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if value != tt.wantValue || comment != tt.wantComment || out.String() != tt.wantOut {
+				t.Errorf("got %q, %q, out %q; want %q, %q, out %q", value, comment, out.String(), tt.wantValue, tt.wantComment, tt.wantOut)
+			}
+		})
+	}
+
+	t.Run("no hooks", func(t *testing.T) {
+		var none *scriptHooks
+		value, comment, err := none.BeforeAdd(Goal{}, "1", "hi", &bytes.Buffer{})
+		if err != nil || value != "1" || comment != "hi" {
+			t.Errorf("got %q, %q, %v", value, comment, err)
+		}
+	})
+}
+
+func TestCellText(t *testing.T) {
+	hooks := writeHooks(t, `
+def cell_text(goal):
+    if goal.safebuf > 7:
+        return "safe %d days" % goal.safebuf
+    if goal.rate == None:
+        return "this text is far too long for a cell"
+    return None
+`)
+	rate := 1.0
+
+	if text, ok := hooks.CellText(Goal{Safebuf: 9}); !ok || fitCellText(text) != "safe 9 days     " {
+		t.Errorf("CellText = %q, %v", text, ok)
+	}
+	if text, ok := hooks.CellText(Goal{Safebuf: 1}); !ok || fitCellText(text) != "this text is ..." {
+		t.Errorf("CellText = %q, %v", text, ok)
+	}
+	if text, ok := hooks.CellText(Goal{Safebuf: 1, Rate: &rate}); ok {
+		t.Errorf("CellText = %q, want the default", text)
+	}
+	var none *scriptHooks
+	if _, ok := none.CellText(Goal{}); ok {
+		t.Error("nil hooks returned cell text")
+	}
+}
+
+func TestCellTexts(t *testing.T) {
+	hooks := writeHooks(t, `
+def cell_text(goal):
+    if goal.safebuf > 7:
+        return "safe"
+    return None
+`)
+	texts := hooks.CellTexts([]Goal{{Slug: "a", Safebuf: 9}, {Slug: "b", Safebuf: 1}})
+	if want := map[string]string{"a": fitCellText("safe")}; !reflect.DeepEqual(texts, want) {
+		t.Errorf("CellTexts = %q, want %q", texts, want)
+	}
+	var none *scriptHooks
+	if texts := none.CellTexts([]Goal{{Slug: "a"}}); texts != nil {
+		t.Errorf("nil hooks CellTexts = %q", texts)
+	}
+}
+
+func TestUseConfiguredHooks(t *testing.T) {
+	dir := t.TempDir()
+	setHome(t, dir)
+	t.Cleanup(func() { activeHooks = nil })
+	path := filepath.Join(dir, "hooks.star")
+	if err := SaveConfig(&Config{Username: "u", Hooks: path}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A broken script is a warning, and buzz carries on without hooks.
+	if err := os.WriteFile(path, []byte("def cell_text(goal):\n    return (\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	activeHooks = nil
+	var errb bytes.Buffer
+	useConfiguredHooks(&errb)
+	if activeHooks != nil || !strings.Contains(errb.String(), "Warning: Failed to load hooks") {
+		t.Errorf("activeHooks = %v, stderr = %q", activeHooks, errb.String())
+	}
+
+	if err := os.WriteFile(path, []byte("def cell_text(goal):\n    return None\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	errb.Reset()
+	useConfiguredHooks(&errb)
+	if activeHooks == nil || activeHooks.cellText == nil || errb.Len() != 0 {
+		t.Errorf("activeHooks = %v, stderr = %q", activeHooks, errb.String())
+	}
+}
+
+func TestApplyAddHook(t *testing.T) {
+	hooks := writeHooks(t, `
+def before_add(goal, value, comment):
+    if goal.gunits == "minutes":
+        return value * 60
+    fail("no adds to " + goal.slug)
+`)
+	client := &FakeClient{
+		FetchGoalFunc: func(slug string) (*Goal, error) {
+			gunits := "hours"
+			if slug == "focus" {
+				gunits = "minutes"
+			}
+			return &Goal{Slug: slug, Gunits: gunits}, nil
+		},
+	}
+
+	var errb bytes.Buffer
+	req, code, done := applyAddHook(addRequest{goalSlug: "focus", value: "1.5"}, hooks, client, &errb)
+	if done || code != 0 || req.value != "90" {
+		t.Errorf("req = %+v, code %d, done %v (stderr %q)", req, code, done, errb.String())
+	}
+
+	errb.Reset()
+	_, code, done = applyAddHook(addRequest{goalSlug: "sleep", value: "8"}, hooks, client, &errb)
+	if !done || code != 1 || !strings.Contains(errb.String(), "Error: Datapoint refused by hooks: no adds to sleep") {
+		t.Errorf("code %d, done %v, stderr %q", code, done, errb.String())
+	}
+
+	// Without a before_add hook the goal isn't fetched.
+	req, _, done = applyAddHook(addRequest{goalSlug: "sleep", value: "8"}, nil, &FakeClient{}, &errb)
+	if done || req.value != "8" {
+		t.Errorf("req = %+v, done %v", req, done)
+	}
+}
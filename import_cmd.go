@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// `buzz import <source>` backfills a goal from another service's data, one
+// datapoint per day. Each day's datapoint carries a request ID derived
+// from the source, metric, and date, so importing the same (or a newer,
+// overlapping) export again updates those days instead of duplicating them.
+
+// printImportHelp prints usage for the `buzz import` command group.
+func printImportHelp(w io.Writer) {
+	fmt.Fprintln(w, "buzz import - Backfill a goal's datapoints from another service's data")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "USAGE:")
+	fmt.Fprintln(w, "  buzz import health --file <export> --metric <metric> [--since <date>] [--dry-run] --goal <goalslug>")
+	fmt.Fprintln(w, "                                    Import an Apple Health or Google Fit export")
+	fmt.Fprintln(w, "  buzz import rescuetime [--date <date>] [--dry-run] --goal <goalslug>")
+	fmt.Fprintln(w, "                                    Submit a day's productive hours from RescueTime")
+	fmt.Fprintln(w, "  buzz import help                  Show this help message")
+}
+
+// handleImportCommand dispatches `buzz import <source>`.
+func handleImportCommand() {
+	if len(os.Args) < 3 {
+		printImportHelp(os.Stderr)
+		os.Exit(1)
+	}
+
+	switch os.Args[2] {
+	case "health":
+		handleImportHealthCommand(os.Args[3:])
+	case "rescuetime":
+		handleImportRescueTimeCommand(os.Args[3:])
+	case "help", "-h", "--help":
+		printImportHelp(os.Stdout)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown import source: %s\n", os.Args[2])
+		printImportHelp(os.Stderr)
+		os.Exit(1)
+	}
+}
+
+// importRequestID is the request ID for an imported day's datapoint (day is
+// YYYYMMDD).
+func importRequestID(source, metric, daystamp string) string {
+	return "buzz-import-" + source + "-" + metric + "-" + daystamp
+}
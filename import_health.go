@@ -0,0 +1,394 @@
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const importHealthUsage = `Usage: buzz import health --file <export> --metric <metric> [--since <date>] [--dry-run] --goal <goalslug>
+  --file     Apple Health export (export.zip or export.xml), or Google Fit
+             Takeout (the .zip, or "Daily activity metrics.csv")
+  --metric   What to import: steps, weight, or distance
+  --goal     Goal to backfill, one datapoint per day
+  --since    Skip days before this date (YYYY-MM-DD)
+  --dry-run  Print each day's value without submitting anything`
+
+// healthMetric describes where an importable metric lives in each export
+// format.
+type healthMetric struct {
+	appleType    string // Apple Health Record type
+	googleColumn string // Google Fit daily metrics CSV column
+	sum          bool   // Add up the day's readings, rather than keep the last
+}
+
+// healthMetrics are the metrics `buzz import health` understands. Weights are
+// read in kg and distances in km, then converted to the goal's units when
+// those are lb or miles.
+var healthMetrics = map[string]healthMetric{
+	"steps":    {appleType: "HKQuantityTypeIdentifierStepCount", googleColumn: "Step count", sum: true},
+	"weight":   {appleType: "HKQuantityTypeIdentifierBodyMass", googleColumn: "Average weight (kg)"},
+	"distance": {appleType: "HKQuantityTypeIdentifierDistanceWalkingRunning", googleColumn: "Distance (m)", sum: true},
+}
+
+// kmPerMile is the exact international mile.
+const kmPerMile = 1.609344
+
+// importHealthRequest is a parsed, validated `buzz import health` invocation.
+type importHealthRequest struct {
+	goalSlug string
+	file     string
+	metric   string
+	since    string // YYYYMMDD, or "" for every day in the export
+	dryRun   bool
+}
+
+// handleImportHealthCommand backfills a goal from a health data export.
+func handleImportHealthCommand(args []string) {
+	req, code, done := parseImportHealthArgs(args, os.Stdout, os.Stderr)
+	if done {
+		os.Exit(code)
+	}
+
+	client, ok := loadClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+
+	code = runImportHealthCommand(context.Background(), req, client, os.Stdout, os.Stderr)
+	if code == 0 {
+		fmt.Print(getUpdateMessage())
+	}
+	os.Exit(code)
+}
+
+// parseImportHealthArgs parses and validates `buzz import health` arguments,
+// returning the request, a process exit code, and done=true when the caller
+// should stop (help shown, or a parse/validation error).
+func parseImportHealthArgs(args []string, stdout, stderr io.Writer) (importHealthRequest, int, bool) {
+	healthFlags := flag.NewFlagSet("import health", flag.ContinueOnError)
+	// Silence the flag package's own output; we print our own usage.
+	healthFlags.SetOutput(io.Discard)
+	goal := healthFlags.String("goal", "", "Goal to backfill")
+	file := healthFlags.String("file", "", "Export to import")
+	metric := healthFlags.String("metric", "", "steps, weight, or distance")
+	since := healthFlags.String("since", "", "Skip days before this date")
+	dryRun := dryRunFlag(healthFlags, "Print each day's value without submitting anything")
+	if err := healthFlags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stdout, importHealthUsage)
+			return importHealthRequest{}, 0, true
+		}
+		fmt.Fprintf(stderr, "Error parsing flags: %s\n", redactError(err))
+		fmt.Fprintln(stderr, importHealthUsage)
+		return importHealthRequest{}, 2, true
+	}
+	if healthFlags.NArg() > 0 {
+		fmt.Fprintf(stderr, "Error: unexpected argument %q\n", healthFlags.Arg(0))
+		fmt.Fprintln(stderr, importHealthUsage)
+		return importHealthRequest{}, 1, true
+	}
+	if *goal == "" || *file == "" || *metric == "" {
+		fmt.Fprintln(stderr, "Error: --file, --metric, and --goal are required")
+		fmt.Fprintln(stderr, importHealthUsage)
+		return importHealthRequest{}, 1, true
+	}
+	if _, ok := healthMetrics[*metric]; !ok {
+		fmt.Fprintf(stderr, "Error: Unknown metric %q (use steps, weight, or distance)\n", *metric)
+		return importHealthRequest{}, 1, true
+	}
+
+	req := importHealthRequest{goalSlug: *goal, file: *file, metric: *metric, dryRun: *dryRun}
+	if *since != "" {
+		t, err := time.Parse("2006-01-02", *since)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: Invalid date format for --since: %s (expected YYYY-MM-DD)\n", *since)
+			return importHealthRequest{}, 1, true
+		}
+		req.since = t.Format("20060102")
+	}
+	return req, 0, false
+}
+
+// runImportHealthCommand reads the export and submits one datapoint per day.
+// It returns the process exit code.
+func runImportHealthCommand(ctx context.Context, req importHealthRequest, client Client, stdout, stderr io.Writer) int {
+	goal, err := client.FetchGoal(ctx, req.goalSlug)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to fetch goal: %s\n", redactError(err))
+		return 1
+	}
+
+	days, err := readHealthExport(req.file, healthMetrics[req.metric])
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to read %s: %s\n", req.file, redactError(err))
+		return 1
+	}
+
+	daystamps := make([]string, 0, len(days))
+	for d := range days {
+		if d >= req.since {
+			daystamps = append(daystamps, d)
+		}
+	}
+	if len(daystamps) == 0 {
+		fmt.Fprintf(stderr, "Error: No %s data found in %s\n", req.metric, req.file)
+		return 1
+	}
+	sort.Strings(daystamps)
+
+	units := healthUnits(req.metric, goal.Gunits)
+	for _, d := range daystamps {
+		value := formatImportValue(convertHealthValue(req.metric, days[d], units))
+		date := isoDate(d)
+		if req.dryRun {
+			fmt.Fprintf(stdout, "%s  %s\n", date, value)
+			continue
+		}
+		comment := fmt.Sprintf("%s %s, imported by buzz", value, strings.TrimSpace(req.metric+" "+units))
+		if _, err := client.CreateDatapointWithDaystamp(ctx, req.goalSlug, "", d, value, comment, importRequestID("health", req.metric, d)); err != nil {
+			fmt.Fprintf(stderr, "Error: Failed to add datapoint for %s: %s\n", date, redactError(err))
+			fmt.Fprintln(stderr, "Days already imported are kept; running the import again picks up where it stopped.")
+			return 1
+		}
+	}
+
+	span := fmt.Sprintf("%s to %s", isoDate(daystamps[0]), isoDate(daystamps[len(daystamps)-1]))
+	if req.dryRun {
+		fmt.Fprintf(stdout, "%d days of %s, %s (dry run; nothing was submitted to %s)\n", len(daystamps), req.metric, span, req.goalSlug)
+		return 0
+	}
+	fmt.Fprintf(stdout, "Imported %d days of %s into %s, %s\n", len(daystamps), req.metric, req.goalSlug, span)
+
+	// Signal any running TUI instances to refresh so they pick up the new
+	// datapoints. Don't fail the command if flag creation fails.
+	if err := createRefreshFlag(); err != nil {
+		fmt.Fprintf(stderr, "Warning: Could not create refresh flag: %s\n", redactError(err))
+	}
+	return 0
+}
+
+// isoDate turns a YYYYMMDD daystamp into YYYY-MM-DD.
+func isoDate(daystamp string) string {
+	return daystamp[:4] + "-" + daystamp[4:6] + "-" + daystamp[6:]
+}
+
+// healthUnits returns the units a metric is submitted in for a goal with the
+// given units: "lb" or "kg" for weight, "mi" or "km" for distance, "" for
+// steps.
+func healthUnits(metric, gunits string) string {
+	switch metric {
+	case "weight":
+		if weightUnitsOf(gunits) == "lb" {
+			return "lb"
+		}
+		return "kg"
+	case "distance":
+		switch strings.ToLower(strings.TrimSpace(gunits)) {
+		case "mi", "mile", "miles":
+			return "mi"
+		}
+		return "km"
+	}
+	return ""
+}
+
+// convertHealthValue converts a metric's value from the kg or km it was read
+// in to units.
+func convertHealthValue(metric string, v float64, units string) float64 {
+	switch {
+	case metric == "weight" && units == "lb":
+		return convertWeight(v, "kg")
+	case metric == "distance" && units == "mi":
+		return v / kmPerMile
+	}
+	return v
+}
+
+// formatImportValue formats an imported value, rounded to three decimals.
+func formatImportValue(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1000)/1000, 'f', -1, 64)
+}
+
+// readHealthExport reads a metric's per-day values (keyed by YYYYMMDD) from an
+// Apple Health or Google Fit export, zipped or not.
+func readHealthExport(file string, metric healthMetric) (map[string]float64, error) {
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".xml":
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return parseAppleHealth(f, metric)
+	case ".csv":
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return parseGoogleFitDaily(f, metric)
+	case ".zip":
+		archive, err := zip.OpenReader(file)
+		if err != nil {
+			return nil, err
+		}
+		defer archive.Close()
+		for _, entry := range archive.File {
+			var parse func(io.Reader, healthMetric) (map[string]float64, error)
+			switch path.Base(entry.Name) {
+			case "export.xml":
+				parse = parseAppleHealth
+			case "Daily activity metrics.csv":
+				parse = parseGoogleFitDaily
+			default:
+				continue
+			}
+			r, err := entry.Open()
+			if err != nil {
+				return nil, err
+			}
+			defer r.Close()
+			return parse(r, metric)
+		}
+		return nil, fmt.Errorf(`no Apple Health export.xml or Google Fit "Daily activity metrics.csv" in the archive`)
+	}
+	return nil, fmt.Errorf("unrecognized export (expected a .zip, .xml, or .csv file)")
+}
+
+// appleHealthTime is the layout of an Apple Health Record's dates.
+const appleHealthTime = "2006-01-02 15:04:05 -0700"
+
+// parseAppleHealth reads a metric's per-day values from an Apple Health
+// export.xml, streaming it since exports run to gigabytes. Readings count
+// toward the day they started on, in the offset they were recorded in. Summed
+// metrics take the day's total from whichever device recorded the most, since
+// a phone and a watch both counting the same steps would otherwise add up to
+// double.
+func parseAppleHealth(r io.Reader, metric healthMetric) (map[string]float64, error) {
+	bySource := map[string]map[string]float64{} // day -> source -> total
+	last := map[string]time.Time{}
+	days := map[string]float64{}
+
+	decoder := xml.NewDecoder(r)
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "Record" {
+			continue
+		}
+		attrs := map[string]string{}
+		for _, a := range start.Attr {
+			attrs[a.Name.Local] = a.Value
+		}
+		if attrs["type"] != metric.appleType {
+			continue
+		}
+		at, err := time.Parse(appleHealthTime, attrs["startDate"])
+		if err != nil {
+			continue
+		}
+		v, err := strconv.ParseFloat(attrs["value"], 64)
+		if err != nil {
+			continue
+		}
+		switch attrs["unit"] {
+		case "lb":
+			v *= kgPerLb
+		case "g":
+			v /= 1000
+		case "mi":
+			v *= kmPerMile
+		case "m":
+			v /= 1000
+		}
+
+		day := at.Format("20060102")
+		if !metric.sum {
+			if !at.Before(last[day]) {
+				last[day], days[day] = at, v
+			}
+			continue
+		}
+		if bySource[day] == nil {
+			bySource[day] = map[string]float64{}
+		}
+		bySource[day][attrs["sourceName"]] += v
+	}
+
+	for day, sources := range bySource {
+		for _, total := range sources {
+			days[day] = math.Max(days[day], total)
+		}
+	}
+	return days, nil
+}
+
+// parseGoogleFitDaily reads a metric's per-day values from Google Fit
+// Takeout's "Daily activity metrics.csv", skipping days without a value.
+func parseGoogleFitDaily(r io.Reader, metric healthMetric) (map[string]float64, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading CSV header: %w", err)
+	}
+	dateCol, valueCol := -1, -1
+	for i, name := range header {
+		switch strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")) {
+		case "Date":
+			dateCol = i
+		case metric.googleColumn:
+			valueCol = i
+		}
+	}
+	if dateCol < 0 || valueCol < 0 {
+		return nil, fmt.Errorf("expected Date and %q columns", metric.googleColumn)
+	}
+
+	days := map[string]float64{}
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if dateCol >= len(row) || valueCol >= len(row) || strings.TrimSpace(row[valueCol]) == "" {
+			continue
+		}
+		date, err := time.Parse("2006-01-02", strings.TrimSpace(row[dateCol]))
+		if err != nil {
+			continue
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(row[valueCol]), 64)
+		if err != nil {
+			continue
+		}
+		if metric.googleColumn == "Distance (m)" {
+			v /= 1000
+		}
+		days[date.Format("20060102")] = v
+	}
+	return days, nil
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const appleHealthExport = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE HealthData [
+<!ELEMENT HealthData (ExportDate,Me,(Record|Workout)*)>
+]>
+<HealthData locale="en_US">
+ <ExportDate value="2026-03-12 08:00:00 -0700"/>
+ <Record type="HKQuantityTypeIdentifierStepCount" sourceName="Phone" unit="count" startDate="2026-03-10 08:00:00 -0700" endDate="2026-03-10 08:10:00 -0700" value="1000"/>
+ <Record type="HKQuantityTypeIdentifierStepCount" sourceName="Phone" unit="count" startDate="2026-03-10 18:00:00 -0700" endDate="2026-03-10 18:10:00 -0700" value="500"/>
+ <Record type="HKQuantityTypeIdentifierStepCount" sourceName="Watch" unit="count" startDate="2026-03-10 08:00:00 -0700" endDate="2026-03-10 08:10:00 -0700" value="1200"/>
+ <Record type="HKQuantityTypeIdentifierStepCount" sourceName="Phone" unit="count" startDate="2026-03-11 23:30:00 -0700" endDate="2026-03-11 23:40:00 -0700" value="42"/>
+ <Record type="HKQuantityTypeIdentifierBodyMass" sourceName="Scale" unit="lb" startDate="2026-03-10 07:00:00 -0700" endDate="2026-03-10 07:00:00 -0700" value="180"/>
+ <Record type="HKQuantityTypeIdentifierBodyMass" sourceName="Scale" unit="kg" startDate="2026-03-10 21:00:00 -0700" endDate="2026-03-10 21:00:00 -0700" value="81"/>
+ <Record type="HKQuantityTypeIdentifierBodyMass" sourceName="Scale" unit="kg" startDate="2026-03-10 06:00:00 -0700" endDate="2026-03-10 06:00:00 -0700" value="82"/>
+ <Record type="HKQuantityTypeIdentifierDistanceWalkingRunning" sourceName="Phone" unit="mi" startDate="2026-03-10 08:00:00 -0700" endDate="2026-03-10 08:10:00 -0700" value="1"/>
+</HealthData>
+`
+
+const googleFitDaily = "\ufeffDate,Move Minutes count,Distance (m),Step count,Average weight (kg)\n" +
+	"2026-03-10,30,2500.5,4000,80.2\n" +
+	"2026-03-11,10,800,1200,\n" +
+	"2026-03-12,,,,\n"
+
+func TestParseImportHealthArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantDone bool
+		wantCode int
+		wantReq  importHealthRequest
+		wantErr  string
+	}{
+		{name: "basic", args: []string{"--file", "export.zip", "--metric", "steps", "--goal", "steps"}, wantReq: importHealthRequest{goalSlug: "steps", file: "export.zip", metric: "steps"}},
+		{name: "since, dry run", args: []string{"--file", "e.xml", "--metric", "weight", "--since", "2026-01-02", "--dry-run", "--goal", "weight"}, wantReq: importHealthRequest{goalSlug: "weight", file: "e.xml", metric: "weight", since: "20260102", dryRun: true}},
+		{name: "help", args: []string{"-h"}, wantDone: true},
+		{name: "bad flag", args: []string{"--nope"}, wantDone: true, wantCode: 2, wantErr: "Error parsing flags"},
+		{name: "missing metric", args: []string{"--file", "e.xml", "--goal", "steps"}, wantDone: true, wantCode: 1, wantErr: "are required"},
+		{name: "unknown metric", args: []string{"--file", "e.xml", "--metric", "sleep", "--goal", "steps"}, wantDone: true, wantCode: 1, wantErr: "Unknown metric"},
+		{name: "bad since", args: []string{"--file", "e.xml", "--metric", "steps", "--since", "20260102", "--goal", "steps"}, wantDone: true, wantCode: 1, wantErr: "Invalid date format for --since"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errb bytes.Buffer
+			req, code, done := parseImportHealthArgs(tt.args, &out, &errb)
+			if done != tt.wantDone || code != tt.wantCode {
+				t.Fatalf("done=%v code=%d, want done=%v code=%d (stderr=%q)", done, code, tt.wantDone, tt.wantCode, errb.String())
+			}
+			if !done && req != tt.wantReq {
+				t.Errorf("req = %+v, want %+v", req, tt.wantReq)
+			}
+			if !strings.Contains(errb.String(), tt.wantErr) {
+				t.Errorf("stderr = %q, want %q", errb.String(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseAppleHealth(t *testing.T) {
+	steps, err := parseAppleHealth(strings.NewReader(appleHealthExport), healthMetrics["steps"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The phone's 1500 beats the watch's 1200; they aren't added together.
+	if len(steps) != 2 || steps["20260310"] != 1500 || steps["20260311"] != 42 {
+		t.Errorf("steps = %v", steps)
+	}
+
+	weight, err := parseAppleHealth(strings.NewReader(appleHealthExport), healthMetrics["weight"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(weight) != 1 || weight["20260310"] != 81 {
+		t.Errorf("weight = %v, want the day's last reading", weight)
+	}
+
+	distance, err := parseAppleHealth(strings.NewReader(appleHealthExport), healthMetrics["distance"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if distance["20260310"] != kmPerMile {
+		t.Errorf("distance = %v, want 1 mile in km", distance)
+	}
+}
+
+func TestParseGoogleFitDaily(t *testing.T) {
+	steps, err := parseGoogleFitDaily(strings.NewReader(googleFitDaily), healthMetrics["steps"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(steps) != 2 || steps["20260310"] != 4000 || steps["20260311"] != 1200 {
+		t.Errorf("steps = %v", steps)
+	}
+
+	weight, err := parseGoogleFitDaily(strings.NewReader(googleFitDaily), healthMetrics["weight"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(weight) != 1 || weight["20260310"] != 80.2 {
+		t.Errorf("weight = %v", weight)
+	}
+
+	distance, err := parseGoogleFitDaily(strings.NewReader(googleFitDaily), healthMetrics["distance"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if distance["20260310"] != 2.5005 {
+		t.Errorf("distance = %v, want km", distance)
+	}
+
+	if _, err := parseGoogleFitDaily(strings.NewReader("Date,Calories\n"), healthMetrics["steps"]); err == nil {
+		t.Error("expected an error for a CSV without the metric's column")
+	}
+}
+
+func TestReadHealthExport(t *testing.T) {
+	dir := t.TempDir()
+	zipPath := filepath.Join(dir, "export.zip")
+	f, err := os.Create(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for name, content := range map[string]string{
+		"apple_health_export/export_cda.xml": "<ClinicalDocument/>",
+		"apple_health_export/export.xml":     appleHealthExport,
+	} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	steps, err := readHealthExport(zipPath, healthMetrics["steps"])
+	if err != nil || steps["20260310"] != 1500 {
+		t.Errorf("zip: steps = %v, %v", steps, err)
+	}
+
+	csvPath := filepath.Join(dir, "Daily activity metrics.csv")
+	if err := os.WriteFile(csvPath, []byte(googleFitDaily), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if steps, err := readHealthExport(csvPath, healthMetrics["steps"]); err != nil || steps["20260310"] != 4000 {
+		t.Errorf("csv: steps = %v, %v", steps, err)
+	}
+
+	if _, err := readHealthExport(filepath.Join(dir, "export.json"), healthMetrics["steps"]); err == nil {
+		t.Error("expected an error for an unrecognized file type")
+	}
+}
+
+func TestRunImportHealthCommand(t *testing.T) {
+	dir := t.TempDir()
+	xmlPath := filepath.Join(dir, "export.xml")
+	if err := os.WriteFile(xmlPath, []byte(appleHealthExport), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	type submission struct{ daystamp, value, requestid string }
+	run := func(t *testing.T, req importHealthRequest, gunits string) (int, string, string, []submission) {
+		t.Helper()
+		setHome(t, t.TempDir()) // contain createRefreshFlag's file write
+		var out, errb bytes.Buffer
+		var got []submission
+		client := &FakeClient{
+			FetchGoalFunc: func(slug string) (*Goal, error) {
+				return &Goal{Slug: slug, Gunits: gunits}, nil
+			},
+			CreateDatapointWithDaystampFunc: func(_, _, daystamp, value, _, requestid string) (*Datapoint, error) {
+				got = append(got, submission{daystamp, value, requestid})
+				return &Datapoint{}, nil
+			},
+		}
+		req.file = xmlPath
+		code := runImportHealthCommand(context.Background(), req, client, &out, &errb)
+		return code, out.String(), errb.String(), got
+	}
+
+	t.Run("backfills each day idempotently", func(t *testing.T) {
+		code, out, errOut, got := run(t, importHealthRequest{goalSlug: "steps", metric: "steps"}, "steps")
+		checkResult(t, code, out, errOut, 0, "Imported 2 days of steps into steps, 2026-03-10 to 2026-03-11", "")
+		want := []submission{
+			{"20260310", "1500", "buzz-import-health-steps-20260310"},
+			{"20260311", "42", "buzz-import-health-steps-20260311"},
+		}
+		if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+			t.Errorf("submitted %v, want %v", got, want)
+		}
+	})
+
+	t.Run("since", func(t *testing.T) {
+		_, _, _, got := run(t, importHealthRequest{goalSlug: "steps", metric: "steps", since: "20260311"}, "")
+		if len(got) != 1 || got[0].daystamp != "20260311" {
+			t.Errorf("submitted %v, want only 2026-03-11", got)
+		}
+	})
+
+	t.Run("weight in the goal's pounds", func(t *testing.T) {
+		_, _, _, got := run(t, importHealthRequest{goalSlug: "weight", metric: "weight"}, "lbs")
+		if len(got) != 1 || got[0].value != "178.574" {
+			t.Errorf("submitted %v, want 81 kg in lb", got)
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		code, out, errOut, got := run(t, importHealthRequest{goalSlug: "steps", metric: "steps", dryRun: true}, "")
+		checkResult(t, code, out, errOut, 0, "2026-03-10  1500\n2026-03-11  42\n2 days of steps", "")
+		if len(got) != 0 {
+			t.Errorf("dry run submitted %v", got)
+		}
+	})
+
+	t.Run("no data", func(t *testing.T) {
+		code, out, errOut, _ := run(t, importHealthRequest{goalSlug: "steps", metric: "steps", since: "20270101"}, "")
+		checkResult(t, code, out, errOut, 1, "", "No steps data found")
+	})
+}
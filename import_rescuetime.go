@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+const importRescueTimeUsage = `Usage: buzz import rescuetime [--date <date>] [--dry-run] --goal <goalslug>
+  --goal     Goal to submit the day's productive hours to
+  --date     Day to import (YYYY-MM-DD, default: today)
+  --dry-run  Print the hours without submitting them
+The API key comes from "rescuetime_key" in ~/.buzzrc, or $RESCUETIME_KEY.`
+
+// rescueTimeAPIURL is RescueTime's Analytic Data API. A variable so tests can
+// point it elsewhere.
+var rescueTimeAPIURL = "https://www.rescuetime.com/anapi/data"
+
+// importRescueTimeRequest is a parsed, validated `buzz import rescuetime`
+// invocation.
+type importRescueTimeRequest struct {
+	goalSlug string
+	date     string // YYYY-MM-DD, or "" for today
+	dryRun   bool
+}
+
+// handleImportRescueTimeCommand submits a day's productive hours from
+// RescueTime.
+func handleImportRescueTimeCommand(args []string) {
+	req, code, done := parseImportRescueTimeArgs(args, os.Stdout, os.Stderr)
+	if done {
+		os.Exit(code)
+	}
+
+	client, ok := loadClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+	// loadClient just loaded the config successfully.
+	key := os.Getenv("RESCUETIME_KEY")
+	if config, err := LoadConfig(); err == nil && config.RescueTimeKey != "" {
+		key = config.RescueTimeKey
+	}
+	if key == "" {
+		fmt.Fprintln(os.Stderr, `Error: No RescueTime API key: set "rescuetime_key" in ~/.buzzrc or $RESCUETIME_KEY`)
+		os.Exit(1)
+	}
+
+	code = runImportRescueTimeCommand(context.Background(), req, rescueTimeAPIURL, key, client, time.Now(), os.Stdout, os.Stderr)
+	if code == 0 {
+		fmt.Print(getUpdateMessage())
+	}
+	os.Exit(code)
+}
+
+// parseImportRescueTimeArgs parses and validates `buzz import rescuetime`
+// arguments, returning the request, a process exit code, and done=true when
+// the caller should stop (help shown, or a parse/validation error).
+func parseImportRescueTimeArgs(args []string, stdout, stderr io.Writer) (importRescueTimeRequest, int, bool) {
+	rescueTimeFlags := flag.NewFlagSet("import rescuetime", flag.ContinueOnError)
+	// Silence the flag package's own output; we print our own usage.
+	rescueTimeFlags.SetOutput(io.Discard)
+	goal := rescueTimeFlags.String("goal", "", "Goal to submit to")
+	date := rescueTimeFlags.String("date", "", "Day to import")
+	dryRun := dryRunFlag(rescueTimeFlags, "Print the hours without submitting them")
+	if err := rescueTimeFlags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stdout, importRescueTimeUsage)
+			return importRescueTimeRequest{}, 0, true
+		}
+		fmt.Fprintf(stderr, "Error parsing flags: %s\n", redactError(err))
+		fmt.Fprintln(stderr, importRescueTimeUsage)
+		return importRescueTimeRequest{}, 2, true
+	}
+	if rescueTimeFlags.NArg() > 0 {
+		fmt.Fprintf(stderr, "Error: unexpected argument %q\n", rescueTimeFlags.Arg(0))
+		fmt.Fprintln(stderr, importRescueTimeUsage)
+		return importRescueTimeRequest{}, 1, true
+	}
+	if *goal == "" {
+		fmt.Fprintln(stderr, "Error: --goal is required")
+		fmt.Fprintln(stderr, importRescueTimeUsage)
+		return importRescueTimeRequest{}, 1, true
+	}
+	if *date != "" {
+		if _, err := time.Parse("2006-01-02", *date); err != nil {
+			fmt.Fprintf(stderr, "Error: Invalid date format for --date: %s (expected YYYY-MM-DD)\n", *date)
+			return importRescueTimeRequest{}, 1, true
+		}
+	}
+
+	return importRescueTimeRequest{goalSlug: *goal, date: *date, dryRun: *dryRun}, 0, false
+}
+
+// runImportRescueTimeCommand fetches the day's productive hours and submits
+// them as the goal's datapoint for that day. It returns the process exit
+// code.
+func runImportRescueTimeCommand(ctx context.Context, req importRescueTimeRequest, apiURL, key string, client Client, now time.Time, stdout, stderr io.Writer) int {
+	day := now
+	if req.date != "" {
+		// Validated by parseImportRescueTimeArgs.
+		day, _ = time.ParseInLocation("2006-01-02", req.date, now.Location())
+	}
+
+	seconds, err := fetchRescueTimeProductiveSeconds(ctx, apiURL, key, day.Format("2006-01-02"))
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to fetch RescueTime data: %s\n", redactError(err))
+		return 1
+	}
+
+	hours := math.Round(float64(seconds)/3600*100) / 100
+	comment := fmt.Sprintf("%s productive, via RescueTime", pomDurationLabel((time.Duration(seconds) * time.Second).Round(time.Minute)))
+	requestID := importRequestID("rescuetime", "productive", day.Format("20060102"))
+	return submitAutodataValue(ctx, client, req.goalSlug, "rescuetime", hours, comment, requestID, req.dryRun, day, stdout, stderr)
+}
+
+// fetchRescueTimeProductiveSeconds returns the seconds RescueTime logged as
+// productive or very productive on date (YYYY-MM-DD).
+func fetchRescueTimeProductiveSeconds(ctx context.Context, apiURL, key, date string) (int, error) {
+	params := url.Values{}
+	params.Set("key", key)
+	params.Set("format", "json")
+	params.Set("perspective", "interval")
+	params.Set("resolution_time", "day")
+	params.Set("restrict_kind", "productivity")
+	params.Set("restrict_begin", date)
+	params.Set("restrict_end", date)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", "buzz-cli")
+
+	resp, err := (&http.Client{Timeout: httpClientTimeout}).Do(req)
+	if err != nil {
+		// The request URL carries the API key; report only the cause.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return 0, urlErr.Err
+		}
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return 0, fmt.Errorf("RescueTime rejected the API key")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("RescueTime API returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		RowHeaders []string `json:"row_headers"`
+		Rows       [][]any  `json:"rows"`
+		Error      string   `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("decoding RescueTime response: %w", err)
+	}
+	if result.Error != "" {
+		return 0, fmt.Errorf("RescueTime: %s", result.Error)
+	}
+
+	timeCol, productivityCol := -1, -1
+	for i, h := range result.RowHeaders {
+		switch h {
+		case "Time Spent (seconds)":
+			timeCol = i
+		case "Productivity":
+			productivityCol = i
+		}
+	}
+	if len(result.Rows) > 0 && (timeCol < 0 || productivityCol < 0) {
+		return 0, fmt.Errorf("unexpected RescueTime columns: %v", result.RowHeaders)
+	}
+
+	// Productivity runs from -2 (very distracting) to 2 (very productive).
+	total := 0
+	for _, row := range result.Rows {
+		if timeCol >= len(row) || productivityCol >= len(row) {
+			continue
+		}
+		seconds, ok1 := row[timeCol].(float64)
+		productivity, ok2 := row[productivityCol].(float64)
+		if ok1 && ok2 && productivity > 0 {
+			total += int(seconds)
+		}
+	}
+	return total, nil
+}
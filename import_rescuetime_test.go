@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseImportRescueTimeArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantDone bool
+		wantCode int
+		wantReq  importRescueTimeRequest
+		wantErr  string
+	}{
+		{name: "today", args: []string{"--goal", "productive-time"}, wantReq: importRescueTimeRequest{goalSlug: "productive-time"}},
+		{name: "date, dry run", args: []string{"--date", "2026-03-09", "--dry-run", "--goal", "pt"}, wantReq: importRescueTimeRequest{goalSlug: "pt", date: "2026-03-09", dryRun: true}},
+		{name: "help", args: []string{"--help"}, wantDone: true},
+		{name: "bad flag", args: []string{"--nope"}, wantDone: true, wantCode: 2, wantErr: "Error parsing flags"},
+		{name: "missing goal", args: nil, wantDone: true, wantCode: 1, wantErr: "--goal is required"},
+		{name: "bad date", args: []string{"--date", "yesterday", "--goal", "pt"}, wantDone: true, wantCode: 1, wantErr: "Invalid date format for --date"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errb bytes.Buffer
+			req, code, done := parseImportRescueTimeArgs(tt.args, &out, &errb)
+			if done != tt.wantDone || code != tt.wantCode {
+				t.Fatalf("done=%v code=%d, want done=%v code=%d (stderr=%q)", done, code, tt.wantDone, tt.wantCode, errb.String())
+			}
+			if !done && req != tt.wantReq {
+				t.Errorf("req = %+v, want %+v", req, tt.wantReq)
+			}
+			if !strings.Contains(errb.String(), tt.wantErr) {
+				t.Errorf("stderr = %q, want %q", errb.String(), tt.wantErr)
+			}
+		})
+	}
+}
+
+// rescueTimeServer serves a canned Analytic Data API response, recording the
+// last request's query.
+func rescueTimeServer(t *testing.T, status int, response string) (*httptest.Server, *url.Values) {
+	t.Helper()
+	var gotQuery url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.Query()
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &gotQuery
+}
+
+const rescueTimeProductivity = `{
+  "notes": "data is an array of arrays (rows), column names for rows in row_headers",
+  "row_headers": ["Date", "Time Spent (seconds)", "Number of People", "Productivity"],
+  "rows": [
+    ["2026-03-10T00:00:00", 3600, 1, 2],
+    ["2026-03-10T00:00:00", 2700, 1, 1],
+    ["2026-03-10T00:00:00", 1800, 1, 0],
+    ["2026-03-10T00:00:00", 900, 1, -1],
+    ["2026-03-10T00:00:00", 600, 1, -2]
+  ]
+}`
+
+func TestFetchRescueTimeProductiveSeconds(t *testing.T) {
+	t.Run("adds productive and very productive time", func(t *testing.T) {
+		srv, query := rescueTimeServer(t, http.StatusOK, rescueTimeProductivity)
+		got, err := fetchRescueTimeProductiveSeconds(context.Background(), srv.URL, "sekrit", "2026-03-10")
+		if err != nil || got != 6300 {
+			t.Fatalf("got %d, %v; want 6300", got, err)
+		}
+		q := *query
+		if q.Get("key") != "sekrit" || q.Get("restrict_kind") != "productivity" || q.Get("restrict_begin") != "2026-03-10" || q.Get("restrict_end") != "2026-03-10" {
+			t.Errorf("query = %v", q)
+		}
+	})
+
+	t.Run("no data yet", func(t *testing.T) {
+		srv, _ := rescueTimeServer(t, http.StatusOK, `{"row_headers":[],"rows":[]}`)
+		if got, err := fetchRescueTimeProductiveSeconds(context.Background(), srv.URL, "k", "2026-03-10"); err != nil || got != 0 {
+			t.Errorf("got %d, %v; want 0", got, err)
+		}
+	})
+
+	errorCases := []struct {
+		name     string
+		status   int
+		response string
+		wantErr  string
+	}{
+		{"bad key", http.StatusForbidden, `{}`, "rejected the API key"},
+		{"server error", http.StatusInternalServerError, ``, "status 500"},
+		{"api error", http.StatusOK, `{"error":"# key not found"}`, "key not found"},
+		{"unexpected columns", http.StatusOK, `{"row_headers":["Rank"],"rows":[[1]]}`, "unexpected RescueTime columns"},
+	}
+	for _, tt := range errorCases {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, _ := rescueTimeServer(t, tt.status, tt.response)
+			_, err := fetchRescueTimeProductiveSeconds(context.Background(), srv.URL, "k", "2026-03-10")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("transport errors don't leak the key", func(t *testing.T) {
+		_, err := fetchRescueTimeProductiveSeconds(context.Background(), "http://127.0.0.1:1/anapi/data", "sekrit", "2026-03-10")
+		if err == nil || strings.Contains(err.Error(), "sekrit") {
+			t.Errorf("err = %v, want an error without the key", err)
+		}
+	})
+}
+
+func TestRunImportRescueTimeCommand(t *testing.T) {
+	now := time.Date(2026, 3, 10, 22, 30, 0, 0, time.Local)
+	srv, query := rescueTimeServer(t, http.StatusOK, rescueTimeProductivity)
+
+	t.Run("submits today's hours", func(t *testing.T) {
+		setHome(t, t.TempDir()) // contain createRefreshFlag's file write
+		var out, errb bytes.Buffer
+		var gotDaystamp, gotValue, gotComment, gotReqID string
+		client := &FakeClient{
+			CreateDatapointWithDaystampFunc: func(_, _, daystamp, value, comment, requestid string) (*Datapoint, error) {
+				gotDaystamp, gotValue, gotComment, gotReqID = daystamp, value, comment, requestid
+				return &Datapoint{}, nil
+			},
+		}
+		req := importRescueTimeRequest{goalSlug: "productive-time"}
+		code := runImportRescueTimeCommand(context.Background(), req, srv.URL, "k", client, now, &out, &errb)
+		checkResult(t, code, out.String(), errb.String(), 0, "Submitted 1.75 to productive-time for 2026-03-10", "")
+		if gotDaystamp != "20260310" || gotValue != "1.75" || gotReqID != "buzz-import-rescuetime-productive-20260310" {
+			t.Errorf("daystamp=%q value=%q requestid=%q", gotDaystamp, gotValue, gotReqID)
+		}
+		if gotComment != "1h45m productive, via RescueTime" {
+			t.Errorf("comment = %q", gotComment)
+		}
+	})
+
+	t.Run("another day, dry run", func(t *testing.T) {
+		var out, errb bytes.Buffer
+		req := importRescueTimeRequest{goalSlug: "productive-time", date: "2026-03-09", dryRun: true}
+		code := runImportRescueTimeCommand(context.Background(), req, srv.URL, "k", &FakeClient{}, now, &out, &errb)
+		checkResult(t, code, out.String(), errb.String(), 0, "rescuetime: 1.75 (dry run", "")
+		if (*query).Get("restrict_begin") != "2026-03-09" {
+			t.Errorf("query = %v, want 2026-03-09", *query)
+		}
+	})
+}
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// `buzz inbox` is an autodata bridge for inbox-zero (inboxer) goals: it counts
+// the messages in a mailbox — over IMAP, or in a local Maildir — and submits
+// the count, so the goal can be kept current from cron without IFTTT.
+
+const inboxUsage = `Usage: buzz inbox (--imap | --maildir <path>) [--unread] [--dry-run] <goalslug>
+  --imap       Count the IMAP mailbox configured under "imap" in ~/.buzzrc
+  --maildir    Count the Maildir at <path> (its new/ and cur/ messages)
+  --unread     Count only unread messages (default: every message)
+  --dry-run    Print the count without submitting it`
+
+// IMAPConfig is the mailbox `buzz inbox --imap` counts. The connection always
+// uses TLS.
+type IMAPConfig struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port,omitempty"` // Default 993
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Mailbox  string `json:"mailbox,omitempty"` // Default INBOX
+}
+
+// imapTimeout bounds the whole IMAP conversation.
+const imapTimeout = 30 * time.Second
+
+// inboxRequest is a parsed, validated `buzz inbox` invocation.
+type inboxRequest struct {
+	goalSlug string
+	imap     bool
+	maildir  string
+	unread   bool
+	dryRun   bool
+}
+
+// handleInboxCommand counts a mailbox and submits the count to a goal.
+func handleInboxCommand() {
+	req, code, done := parseInboxArgs(os.Args[2:], os.Stdout, os.Stderr)
+	if done {
+		os.Exit(code)
+	}
+
+	client, ok := loadClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+
+	var count inboxCounter
+	if req.imap {
+		// loadClient just loaded the config successfully.
+		config, err := LoadConfig()
+		if err != nil || config.IMAP == nil || config.IMAP.Host == "" {
+			fmt.Fprintln(os.Stderr, `Error: --imap needs an "imap" section with at least a host in ~/.buzzrc`)
+			os.Exit(1)
+		}
+		imapConfig := *config.IMAP
+		count = func(ctx context.Context) (int, error) { return countIMAPMessages(ctx, imapConfig, req.unread) }
+	} else {
+		count = func(context.Context) (int, error) { return countMaildirMessages(req.maildir, req.unread) }
+	}
+
+	code = runInboxCommand(context.Background(), req, count, client, os.Stdout, os.Stderr)
+	if code == 0 {
+		fmt.Print(getUpdateMessage())
+	}
+	os.Exit(code)
+}
+
+// parseInboxArgs parses and validates `buzz inbox` arguments, returning the
+// request, a process exit code, and done=true when the caller should stop
+// (help shown, or a parse/validation error).
+func parseInboxArgs(args []string, stdout, stderr io.Writer) (inboxRequest, int, bool) {
+	inboxFlags := flag.NewFlagSet("inbox", flag.ContinueOnError)
+	// Silence the flag package's own output; we print our own usage.
+	inboxFlags.SetOutput(io.Discard)
+	imap := inboxFlags.Bool("imap", false, "Count the configured IMAP mailbox")
+	maildir := inboxFlags.String("maildir", "", "Count the Maildir at this path")
+	unread := inboxFlags.Bool("unread", false, "Count only unread messages")
+	dryRun := dryRunFlag(inboxFlags, "Print the count without submitting it")
+	if err := inboxFlags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stdout, inboxUsage)
+			return inboxRequest{}, 0, true
+		}
+		fmt.Fprintf(stderr, "Error parsing flags: %s\n", redactError(err))
+		fmt.Fprintln(stderr, inboxUsage)
+		return inboxRequest{}, 2, true
+	}
+
+	if inboxFlags.NArg() != 1 {
+		fmt.Fprintln(stderr, "Error: Missing required arguments")
+		fmt.Fprintln(stderr, inboxUsage)
+		return inboxRequest{}, 1, true
+	}
+	if *imap == (*maildir != "") {
+		fmt.Fprintln(stderr, "Error: Choose one mailbox: --imap or --maildir <path>")
+		fmt.Fprintln(stderr, inboxUsage)
+		return inboxRequest{}, 1, true
+	}
+
+	return inboxRequest{
+		goalSlug: inboxFlags.Arg(0),
+		imap:     *imap,
+		maildir:  *maildir,
+		unread:   *unread,
+		dryRun:   *dryRun,
+	}, 0, false
+}
+
+// inboxCounter returns the number of messages in the requested mailbox.
+type inboxCounter func(ctx context.Context) (int, error)
+
+// runInboxCommand counts the mailbox and, unless it's a dry run, submits the
+// count to the goal. It returns the process exit code.
+func runInboxCommand(ctx context.Context, req inboxRequest, count inboxCounter, client Client, stdout, stderr io.Writer) int {
+	n, err := count(ctx)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to count messages: %s\n", redactError(err))
+		return 1
+	}
+
+	what := "messages"
+	if req.unread {
+		what = "unread messages"
+	}
+	if req.dryRun {
+		fmt.Fprintf(stdout, "%d %s (dry run; nothing was submitted to %s)\n", n, what, req.goalSlug)
+		return 0
+	}
+
+	value := strconv.Itoa(n)
+	comment := fmt.Sprintf("%d %s, counted by buzz inbox", n, what)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	if _, err := client.CreateDatapoint(ctx, req.goalSlug, timestamp, value, comment, ""); err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to add datapoint: %s\n", redactError(err))
+		return 1
+	}
+	fmt.Fprintf(stdout, "Submitted %d %s to %s\n", n, what, req.goalSlug)
+
+	// Signal any running TUI instances to refresh so they pick up the new
+	// datapoint. Don't fail the command if flag creation fails.
+	if err := createRefreshFlag(); err != nil {
+		fmt.Fprintf(stderr, "Warning: Could not create refresh flag: %s\n", redactError(err))
+	}
+	return 0
+}
+
+// countMaildirMessages counts the messages in a Maildir: everything in new/
+// and cur/, or with unread, everything in new/ plus the cur/ messages not
+// flagged seen (S).
+func countMaildirMessages(dir string, unread bool) (int, error) {
+	count := 0
+	for _, sub := range []string{"new", "cur"} {
+		entries, err := os.ReadDir(filepath.Join(dir, sub))
+		if err != nil {
+			return 0, err
+		}
+		for _, e := range entries {
+			name := e.Name()
+			if e.IsDir() || strings.HasPrefix(name, ".") {
+				continue
+			}
+			if unread && sub == "cur" && maildirSeen(name) {
+				continue
+			}
+			count++
+		}
+	}
+	return count, nil
+}
+
+// maildirSeen reports whether a Maildir file name carries the seen (S) flag
+// in its ":2,<flags>" info suffix.
+func maildirSeen(name string) bool {
+	_, info, ok := strings.Cut(name, ":2,")
+	return ok && strings.Contains(info, "S")
+}
+
+// countIMAPMessages logs in to the configured IMAP server over TLS and
+// returns the mailbox's message count (or unseen count, with unread).
+func countIMAPMessages(ctx context.Context, config IMAPConfig, unread bool) (int, error) {
+	port := config.Port
+	if port == 0 {
+		port = 993
+	}
+	ctx, cancel := context.WithTimeout(ctx, imapTimeout)
+	defer cancel()
+
+	dialer := &tls.Dialer{Config: &tls.Config{ServerName: config.Host}}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(config.Host, strconv.Itoa(port)))
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	return imapMailboxCount(conn, config, unread)
+}
+
+// imapMailboxCount speaks just enough IMAP over conn — LOGIN, STATUS, LOGOUT
+// — to read a mailbox's MESSAGES or UNSEEN count.
+func imapMailboxCount(conn io.ReadWriter, config IMAPConfig, unread bool) (int, error) {
+	mailbox := config.Mailbox
+	if mailbox == "" {
+		mailbox = "INBOX"
+	}
+	// Quote everything before sending anything, so a bad value fails alone.
+	username, err := imapQuote(config.Username)
+	if err != nil {
+		return 0, fmt.Errorf("IMAP username: %w", err)
+	}
+	password, err := imapQuote(config.Password)
+	if err != nil {
+		return 0, fmt.Errorf("IMAP password: %w", err)
+	}
+	quotedMailbox, err := imapQuote(mailbox)
+	if err != nil {
+		return 0, fmt.Errorf("IMAP mailbox: %w", err)
+	}
+
+	r := bufio.NewReader(conn)
+	greeting, err := r.ReadString('\n')
+	if err != nil {
+		return 0, fmt.Errorf("reading IMAP greeting: %w", err)
+	}
+	if !strings.HasPrefix(greeting, "* OK") && !strings.HasPrefix(greeting, "* PREAUTH") {
+		return 0, fmt.Errorf("unexpected IMAP greeting: %s", strings.TrimSpace(greeting))
+	}
+
+	tag := 0
+	// command sends one tagged command and returns the untagged lines of its
+	// response, or an error unless the server answers OK.
+	command := func(cmd string) ([]string, error) {
+		tag++
+		prefix := fmt.Sprintf("a%d ", tag)
+		if _, err := fmt.Fprintf(conn, "%s%s\r\n", prefix, cmd); err != nil {
+			return nil, err
+		}
+		var untagged []string
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return nil, err
+			}
+			line = strings.TrimRight(line, "\r\n")
+			if !strings.HasPrefix(line, prefix) {
+				untagged = append(untagged, line)
+				continue
+			}
+			status := strings.TrimPrefix(line, prefix)
+			if !strings.HasPrefix(status, "OK") {
+				return nil, fmt.Errorf("IMAP server: %s", status)
+			}
+			return untagged, nil
+		}
+	}
+
+	if !strings.HasPrefix(greeting, "* PREAUTH") {
+		if _, err := command("LOGIN " + username + " " + password); err != nil {
+			return 0, err
+		}
+	}
+
+	item := "MESSAGES"
+	if unread {
+		item = "UNSEEN"
+	}
+	lines, err := command("STATUS " + quotedMailbox + " (" + item + ")")
+	if err != nil {
+		return 0, err
+	}
+	_, _ = command("LOGOUT")
+
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "* STATUS ") {
+			continue
+		}
+		// "* STATUS INBOX (MESSAGES 12)": the counts are in the last parens.
+		open := strings.LastIndex(line, "(")
+		fields := strings.Fields(strings.Trim(line[open+1:], ")"))
+		for i := 0; i+1 < len(fields); i += 2 {
+			if strings.EqualFold(fields[i], item) {
+				return strconv.Atoi(fields[i+1])
+			}
+		}
+	}
+	return 0, fmt.Errorf("IMAP server sent no %s count for %s", item, mailbox)
+}
+
+// imapQuote returns s as an IMAP quoted string. A quoted string can't hold a
+// line break or NUL, and sending one would end the command early and start
+// another the server would run, so those are an error.
+func imapQuote(s string) (string, error) {
+	if strings.ContainsAny(s, "\r\n\x00") {
+		return "", errors.New("contains a line break or NUL, which IMAP can't send")
+	}
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`, nil
+}
@@ -0,0 +1,220 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseInboxArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantDone bool
+		wantCode int
+		wantReq  inboxRequest
+		wantErr  string
+	}{
+		{name: "imap", args: []string{"--imap", "email"}, wantReq: inboxRequest{goalSlug: "email", imap: true}},
+		{name: "maildir unread dry run", args: []string{"--maildir", "/m", "--unread", "--dry-run", "email"}, wantReq: inboxRequest{goalSlug: "email", maildir: "/m", unread: true, dryRun: true}},
+		{name: "help", args: []string{"-h"}, wantDone: true},
+		{name: "bad flag", args: []string{"--nope", "email"}, wantDone: true, wantCode: 2, wantErr: "Error parsing flags"},
+		{name: "missing goal", args: []string{"--imap"}, wantDone: true, wantCode: 1, wantErr: "Missing required arguments"},
+		{name: "no mailbox", args: []string{"email"}, wantDone: true, wantCode: 1, wantErr: "Choose one mailbox"},
+		{name: "both mailboxes", args: []string{"--imap", "--maildir", "/m", "email"}, wantDone: true, wantCode: 1, wantErr: "Choose one mailbox"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errb bytes.Buffer
+			req, code, done := parseInboxArgs(tt.args, &out, &errb)
+			if done != tt.wantDone || code != tt.wantCode {
+				t.Fatalf("done=%v code=%d, want done=%v code=%d (stderr=%q)", done, code, tt.wantDone, tt.wantCode, errb.String())
+			}
+			if !done && req != tt.wantReq {
+				t.Errorf("req = %+v, want %+v", req, tt.wantReq)
+			}
+			if !strings.Contains(errb.String(), tt.wantErr) {
+				t.Errorf("stderr = %q, want %q", errb.String(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCountMaildirMessages(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"new/1700000000.1.host":        "",
+		"new/1700000001.2.host":        "",
+		"cur/1700000002.3.host:2,S":    "",
+		"cur/1700000003.4.host:2,RS":   "",
+		"cur/1700000004.5.host:2,F":    "",
+		"cur/1700000005.6.host:2,":     "",
+		"cur/.hidden":                  "",
+		"tmp/1700000006.7.host":        "",
+		"cur/1700000007.8.host:2,DFST": "",
+	}
+	for name := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got, err := countMaildirMessages(dir, false); err != nil || got != 7 {
+		t.Errorf("all = %d, %v; want 7", got, err)
+	}
+	if got, err := countMaildirMessages(dir, true); err != nil || got != 4 {
+		t.Errorf("unread = %d, %v; want 4", got, err)
+	}
+	if _, err := countMaildirMessages(filepath.Join(dir, "missing"), false); err == nil {
+		t.Error("expected an error for a missing Maildir")
+	}
+}
+
+// fakeIMAPServer answers one IMAP conversation on conn with canned replies,
+// recording the commands it receives.
+func fakeIMAPServer(conn net.Conn, greeting string, replies map[string][]string) *[]string {
+	var received []string
+	go func() {
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		conn.Write([]byte(greeting + "\r\n"))
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			tag, cmd, _ := strings.Cut(strings.TrimRight(line, "\r\n"), " ")
+			received = append(received, cmd)
+			verb, _, _ := strings.Cut(cmd, " ")
+			for _, reply := range replies[verb] {
+				conn.Write([]byte(reply + "\r\n"))
+			}
+			status := "OK done"
+			if verb == "LOGIN" && replies["LOGIN"] != nil {
+				status = "NO [AUTHENTICATIONFAILED] Invalid credentials"
+			}
+			conn.Write([]byte(tag + " " + status + "\r\n"))
+			if verb == "LOGOUT" {
+				return
+			}
+		}
+	}()
+	return &received
+}
+
+func TestIMAPMailboxCount(t *testing.T) {
+	config := IMAPConfig{Username: "me@example.com", Password: `pa"ss`}
+
+	t.Run("messages", func(t *testing.T) {
+		client, server := net.Pipe()
+		received := fakeIMAPServer(server, "* OK ready", map[string][]string{
+			"STATUS": {"* STATUS INBOX (MESSAGES 12)"},
+		})
+		got, err := imapMailboxCount(client, config, false)
+		if err != nil || got != 12 {
+			t.Fatalf("got %d, %v; want 12", got, err)
+		}
+		if (*received)[0] != `LOGIN "me@example.com" "pa\"ss"` || (*received)[1] != `STATUS "INBOX" (MESSAGES)` {
+			t.Errorf("commands = %q", *received)
+		}
+	})
+
+	t.Run("unseen in a named mailbox", func(t *testing.T) {
+		client, server := net.Pipe()
+		received := fakeIMAPServer(server, "* OK ready", map[string][]string{
+			"STATUS": {`* STATUS "Work" (UNSEEN 3)`},
+		})
+		cfg := config
+		cfg.Mailbox = "Work"
+		got, err := imapMailboxCount(client, cfg, true)
+		if err != nil || got != 3 {
+			t.Fatalf("got %d, %v; want 3", got, err)
+		}
+		if (*received)[1] != `STATUS "Work" (UNSEEN)` {
+			t.Errorf("commands = %q", *received)
+		}
+	})
+
+	t.Run("login rejected", func(t *testing.T) {
+		client, server := net.Pipe()
+		fakeIMAPServer(server, "* OK ready", map[string][]string{"LOGIN": {}})
+		_, err := imapMailboxCount(client, config, false)
+		if err == nil || !strings.Contains(err.Error(), "Invalid credentials") {
+			t.Errorf("err = %v, want the server's rejection", err)
+		}
+	})
+
+	t.Run("line break in a value", func(t *testing.T) {
+		client, server := net.Pipe()
+		defer client.Close()
+		received := fakeIMAPServer(server, "* OK ready", nil)
+		cfg := config
+		cfg.Password = "pw\r\na2 DELETE INBOX"
+		if _, err := imapMailboxCount(client, cfg, false); err == nil || !strings.Contains(err.Error(), "IMAP password") {
+			t.Errorf("err = %v, want the password refused", err)
+		}
+		if len(*received) != 0 {
+			t.Errorf("commands = %q, want none sent", *received)
+		}
+	})
+
+	t.Run("bad greeting", func(t *testing.T) {
+		client, server := net.Pipe()
+		fakeIMAPServer(server, "* BYE go away", nil)
+		if _, err := imapMailboxCount(client, config, false); err == nil {
+			t.Error("expected an error for a BYE greeting")
+		}
+	})
+}
+
+func TestRunInboxCommand(t *testing.T) {
+	count := func(n int, err error) inboxCounter {
+		return func(context.Context) (int, error) { return n, err }
+	}
+	tests := []struct {
+		name        string
+		req         inboxRequest
+		count       inboxCounter
+		wantCode    int
+		wantOut     string
+		wantErr     string
+		wantSubmits bool
+	}{
+		{name: "submits count", req: inboxRequest{goalSlug: "email", imap: true}, count: count(12, nil), wantOut: "Submitted 12 messages to email", wantSubmits: true},
+		{name: "unread", req: inboxRequest{goalSlug: "email", imap: true, unread: true}, count: count(3, nil), wantOut: "Submitted 3 unread messages", wantSubmits: true},
+		{name: "dry run", req: inboxRequest{goalSlug: "email", imap: true, dryRun: true}, count: count(12, nil), wantOut: "12 messages (dry run"},
+		{name: "count fails", req: inboxRequest{goalSlug: "email", imap: true}, count: count(0, errors.New("connection refused")), wantCode: 1, wantErr: "Failed to count messages"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setHome(t, t.TempDir()) // contain createRefreshFlag's file write
+			var out, errb bytes.Buffer
+			var gotValue string
+			submitted := false
+			client := &FakeClient{
+				CreateDatapointFunc: func(_, _, value, _, _ string) (*Datapoint, error) {
+					submitted, gotValue = true, value
+					return &Datapoint{}, nil
+				},
+			}
+			code := runInboxCommand(context.Background(), tt.req, tt.count, client, &out, &errb)
+			checkResult(t, code, out.String(), errb.String(), tt.wantCode, tt.wantOut, tt.wantErr)
+			if submitted != tt.wantSubmits {
+				t.Errorf("submitted = %v, want %v", submitted, tt.wantSubmits)
+			}
+			if submitted && !strings.Contains(tt.wantOut, gotValue) {
+				t.Errorf("submitted value %q, want the count", gotValue)
+			}
+		})
+	}
+}
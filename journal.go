@@ -0,0 +1,385 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const journalUsage = "Usage: buzz journal [--goal=<slug>] [--limit=<n>] [--json]"
+
+// JournalEntry is one change buzz made to the Beeminder account.
+type JournalEntry struct {
+	Time   time.Time `json:"time"`
+	Action string    `json:"action"`         // e.g. "add", "delete", "charge"
+	Goal   string    `json:"goal,omitempty"` // the goal changed, if any
+	Detail string    `json:"detail,omitempty"`
+	// Datapoint is the datapoint added or deleted, so the change can be
+	// undone (see lastUndoable).
+	Datapoint *Datapoint `json:"datapoint,omitempty"`
+	// Undo marks a change made to undo an earlier one.
+	Undo bool `json:"undo,omitempty"`
+}
+
+// getJournalPath returns the path to the journal file.
+func getJournalPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".buzz_journal"), nil
+}
+
+// appendJournal adds an entry to the end of the journal, one JSON object per
+// line. The journal is only ever appended to, so earlier entries can't be
+// lost to a crash or a concurrent buzz.
+func appendJournal(entry JournalEntry) error {
+	path, err := getJournalPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// loadJournal reads the journal, oldest entry first. A missing journal is
+// not an error; it returns no entries. Lines that don't parse (say, one cut
+// short by a full disk) are skipped.
+func loadJournal() ([]JournalEntry, error) {
+	path, err := getJournalPath()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []JournalEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err == nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// journalingClient is a Client that records every change it makes to the
+// account in the journal, so there's a trail of what buzz did — from the TUI
+// or the command line. Only writes that succeed are recorded, and failing to
+// write the journal never fails the change itself.
+type journalingClient struct {
+	Client
+}
+
+// journaling wraps client so its changes to the account are journaled.
+func journaling(client Client) *journalingClient {
+	return &journalingClient{Client: client}
+}
+
+// undoContextKey marks a context whose changes undo earlier ones.
+type undoContextKey struct{}
+
+// undoing returns a context whose changes are journaled as undoing earlier
+// ones, so they aren't offered for undoing themselves.
+func undoing(ctx context.Context) context.Context {
+	return context.WithValue(ctx, undoContextKey{}, true)
+}
+
+// record journals a change unless it failed.
+func (c *journalingClient) record(ctx context.Context, err error, entry JournalEntry) {
+	if err != nil {
+		return
+	}
+	entry.Time = time.Now()
+	entry.Undo, _ = ctx.Value(undoContextKey{}).(bool)
+	_ = appendJournal(entry)
+}
+
+// datapointDetail describes a datapoint buzz added or changed.
+func datapointDetail(dp *Datapoint, value, comment string) string {
+	detail := fmt.Sprintf("value=%s comment=%q", value, comment)
+	if dp != nil && dp.ID != "" {
+		detail += " id=" + dp.ID
+	}
+	return detail
+}
+
+// recordAdd journals an add sent at sentAt. With a request ID Beeminder may
+// have handed back a datapoint it already had (see alreadyLogged) instead of
+// adding one, and that mustn't be journaled as an add, or undoing it would
+// delete the earlier datapoint. One left as it was isn't journaled at all,
+// and one the journal already has as an add (its comment changed, say) is
+// journaled as an edit.
+func (c *journalingClient) recordAdd(ctx context.Context, err error, sentAt time.Time, goalSlug, requestid, detail string, dp *Datapoint) {
+	if err == nil && requestid != "" && dp != nil && dp.ID != "" {
+		if alreadyLogged(dp, "", sentAt) {
+			return
+		}
+		if journaledAdd(goalSlug, dp.ID) {
+			c.record(ctx, nil, JournalEntry{Action: "edit", Goal: goalSlug, Detail: detail + " (already logged)"})
+			return
+		}
+	}
+	c.record(ctx, err, JournalEntry{Action: "add", Goal: goalSlug, Detail: detail, Datapoint: dp})
+}
+
+// journaledAdd reports whether the journal has the datapoint id as added to
+// goalSlug.
+func journaledAdd(goalSlug, id string) bool {
+	entries, _ := loadJournal()
+	for _, e := range entries {
+		if e.Action == "add" && e.Goal == goalSlug && e.Datapoint != nil && e.Datapoint.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *journalingClient) CreateDatapoint(ctx context.Context, goalSlug, timestamp, value, comment, requestid string) (*Datapoint, error) {
+	sentAt := time.Now()
+	dp, err := c.Client.CreateDatapoint(ctx, goalSlug, timestamp, value, comment, requestid)
+	c.recordAdd(ctx, err, sentAt, goalSlug, requestid, datapointDetail(dp, value, comment), dp)
+	return dp, err
+}
+
+func (c *journalingClient) CreateDatapointWithDaystamp(ctx context.Context, goalSlug, timestamp, daystamp, value, comment, requestid string) (*Datapoint, error) {
+	sentAt := time.Now()
+	dp, err := c.Client.CreateDatapointWithDaystamp(ctx, goalSlug, timestamp, daystamp, value, comment, requestid)
+	detail := datapointDetail(dp, value, comment)
+	if daystamp != "" {
+		detail += " daystamp=" + daystamp
+	}
+	c.recordAdd(ctx, err, sentAt, goalSlug, requestid, detail, dp)
+	return dp, err
+}
+
+func (c *journalingClient) UpdateDatapoint(ctx context.Context, goalSlug, id string, value, comment, timestamp *string) (*Datapoint, error) {
+	dp, err := c.Client.UpdateDatapoint(ctx, goalSlug, id, value, comment, timestamp)
+	var changes []string
+	if value != nil {
+		changes = append(changes, "value="+*value)
+	}
+	if comment != nil {
+		changes = append(changes, fmt.Sprintf("comment=%q", *comment))
+	}
+	if timestamp != nil {
+		changes = append(changes, "timestamp="+*timestamp)
+	}
+	c.record(ctx, err, JournalEntry{Action: "edit", Goal: goalSlug, Detail: strings.Join(append(changes, "id="+id), " ")})
+	return dp, err
+}
+
+func (c *journalingClient) DeleteDatapoint(ctx context.Context, goalSlug, id string) (*Datapoint, error) {
+	dp, err := c.Client.DeleteDatapoint(ctx, goalSlug, id)
+	detail := "id=" + id
+	if dp != nil {
+		detail = fmt.Sprintf("value=%s comment=%q id=%s", formatValue(dp.Value), dp.Comment, id)
+	}
+	c.record(ctx, err, JournalEntry{Action: "delete", Goal: goalSlug, Detail: detail, Datapoint: dp})
+	return dp, err
+}
+
+func (c *journalingClient) CreateCharge(ctx context.Context, amount float64, note string, dryrun bool) (*Charge, error) {
+	charge, err := c.Client.CreateCharge(ctx, amount, note, dryrun)
+	if !dryrun {
+		c.record(ctx, err, JournalEntry{Action: "charge", Detail: fmt.Sprintf("%s note=%q", formatMoney(amount, 2), note)})
+	}
+	return charge, err
+}
+
+func (c *journalingClient) CreateGoal(ctx context.Context, slug, title, goalType, gunits, goaldate, goalval, rate, runits string) (*Goal, error) {
+	goal, err := c.Client.CreateGoal(ctx, slug, title, goalType, gunits, goaldate, goalval, rate, runits)
+	c.record(ctx, err, JournalEntry{Action: "create", Goal: slug, Detail: fmt.Sprintf("type=%s title=%q", goalType, title)})
+	return goal, err
+}
+
+func (c *journalingClient) CallUncle(ctx context.Context, goalSlug string) (*Goal, error) {
+	goal, err := c.Client.CallUncle(ctx, goalSlug)
+	c.record(ctx, err, JournalEntry{Action: "uncle", Goal: goalSlug})
+	return goal, err
+}
+
+func (c *journalingClient) ShortCircuitGoal(ctx context.Context, goalSlug string) (*Goal, error) {
+	goal, err := c.Client.ShortCircuitGoal(ctx, goalSlug)
+	c.record(ctx, err, JournalEntry{Action: "shortcircuit", Goal: goalSlug})
+	return goal, err
+}
+
+func (c *journalingClient) RatchetGoal(ctx context.Context, goalSlug string, ratchet int) (*Goal, error) {
+	goal, err := c.Client.RatchetGoal(ctx, goalSlug, ratchet)
+	c.record(ctx, err, JournalEntry{Action: "ratchet", Goal: goalSlug, Detail: fmt.Sprintf("to %d days of buffer", ratchet)})
+	return goal, err
+}
+
+func (c *journalingClient) StepDownGoal(ctx context.Context, goalSlug string) (*Goal, error) {
+	goal, err := c.Client.StepDownGoal(ctx, goalSlug)
+	c.record(ctx, err, JournalEntry{Action: "stepdown", Goal: goalSlug})
+	return goal, err
+}
+
+func (c *journalingClient) UpdateGoalDeadline(ctx context.Context, goalSlug string, deadline int) (*Goal, error) {
+	goal, err := c.Client.UpdateGoalDeadline(ctx, goalSlug, deadline)
+	c.record(ctx, err, JournalEntry{Action: "deadline", Goal: goalSlug, Detail: "to " + formatDueTime(deadline)})
+	return goal, err
+}
+
+func (c *journalingClient) UpdateGoalWeekendsOff(ctx context.Context, goalSlug string, weekendsOff bool) (*Goal, error) {
+	goal, err := c.Client.UpdateGoalWeekendsOff(ctx, goalSlug, weekendsOff)
+	c.record(ctx, err, JournalEntry{Action: "weekends-off", Goal: goalSlug, Detail: strconv.FormatBool(weekendsOff)})
+	return goal, err
+}
+
+func (c *journalingClient) UpdateGoalTags(ctx context.Context, goalSlug string, tags []string) (*Goal, error) {
+	goal, err := c.Client.UpdateGoalTags(ctx, goalSlug, tags)
+	c.record(ctx, err, JournalEntry{Action: "tags", Goal: goalSlug, Detail: strings.Join(tags, ",")})
+	return goal, err
+}
+
+func (c *journalingClient) UpdateGoalRoad(ctx context.Context, goalSlug string, roadall [][]*float64) (*Goal, error) {
+	goal, err := c.Client.UpdateGoalRoad(ctx, goalSlug, roadall)
+	c.record(ctx, err, JournalEntry{Action: "road", Goal: goalSlug, Detail: fmt.Sprintf("%d rows", len(roadall))})
+	return goal, err
+}
+
+func (c *journalingClient) RenameGoal(ctx context.Context, goalSlug, newSlug string) (*Goal, error) {
+	goal, err := c.Client.RenameGoal(ctx, goalSlug, newSlug)
+	c.record(ctx, err, JournalEntry{Action: "rename", Goal: goalSlug, Detail: "to " + newSlug})
+	return goal, err
+}
+
+func (c *journalingClient) UpdateGoalVisibility(ctx context.Context, goalSlug string, secret, dataPublic *bool) (*Goal, error) {
+	goal, err := c.Client.UpdateGoalVisibility(ctx, goalSlug, secret, dataPublic)
+	var changes []string
+	if secret != nil {
+		changes = append(changes, "secret="+strconv.FormatBool(*secret))
+	}
+	if dataPublic != nil {
+		changes = append(changes, "datapublic="+strconv.FormatBool(*dataPublic))
+	}
+	c.record(ctx, err, JournalEntry{Action: "visibility", Goal: goalSlug, Detail: strings.Join(changes, " ")})
+	return goal, err
+}
+
+func (c *journalingClient) UpdateGoalPledgeCap(ctx context.Context, goalSlug string, pledgeCap float64) (*Goal, error) {
+	goal, err := c.Client.UpdateGoalPledgeCap(ctx, goalSlug, pledgeCap)
+	c.record(ctx, err, JournalEntry{Action: "pledge-cap", Goal: goalSlug, Detail: "to $" + formatPledge(pledgeCap)})
+	return goal, err
+}
+
+// APIRequest journals any request but a GET that Beeminder accepted, as
+// `buzz api` can change anything. The parameters aren't recorded.
+func (c *journalingClient) APIRequest(ctx context.Context, method, path string, params url.Values) (int, []byte, error) {
+	status, body, err := c.Client.APIRequest(ctx, method, path, params)
+	if method != http.MethodGet && err == nil && status >= 200 && status < 300 {
+		c.record(ctx, nil, JournalEntry{Action: "api", Detail: method + " " + path})
+	}
+	return status, body, err
+}
+
+// handleJournalCommand shows the journal.
+func handleJournalCommand() {
+	os.Exit(runJournalCommand(os.Args[2:], os.Stdout, os.Stderr))
+}
+
+// runJournalCommand is the testable core of `buzz journal`. It prints the
+// most recent journal entries, oldest first, optionally for one goal.
+func runJournalCommand(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("journal", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {}
+	goal := fs.String("goal", "", "Only show changes to this goal")
+	limit := fs.Int("limit", 20, "Show at most this many entries (0 for all)")
+	jsonOutput := fs.Bool("json", false, "Output entries as JSON")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stdout, journalUsage)
+			return 0
+		}
+		fmt.Fprintf(stderr, "Error parsing flags: %s\n", redactError(err))
+		fmt.Fprintln(stderr, journalUsage)
+		return 2
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "Error: Unexpected arguments: %v\n", fs.Args())
+		fmt.Fprintln(stderr, journalUsage)
+		return 1
+	}
+	if *limit < 0 {
+		fmt.Fprintln(stderr, "Error: --limit can't be negative")
+		return 1
+	}
+
+	entries, err := loadJournal()
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to read the journal: %s\n", err)
+		return 1
+	}
+	if *goal != "" {
+		var matching []JournalEntry
+		for _, e := range entries {
+			if e.Goal == *goal {
+				matching = append(matching, e)
+			}
+		}
+		entries = matching
+	}
+	if *limit > 0 && len(entries) > *limit {
+		entries = entries[len(entries)-*limit:]
+	}
+
+	if *jsonOutput {
+		if entries == nil {
+			entries = []JournalEntry{}
+		}
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %s\n", err)
+			return 1
+		}
+		fmt.Fprintln(stdout, string(data))
+		return 0
+	}
+
+	if len(entries) == 0 {
+		fmt.Fprintln(stdout, "The journal is empty.")
+		return 0
+	}
+	for _, e := range entries {
+		t := e.Time.Local()
+		line := fmt.Sprintf("%s %s  %-12s %s", formatDate(t), t.Format(clockLayout()), e.Action, e.Goal)
+		if e.Detail != "" {
+			line += "  " + e.Detail
+		}
+		fmt.Fprintln(stdout, strings.TrimRight(line, " "))
+	}
+	return 0
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJournalingClient(t *testing.T) {
+	setHome(t, t.TempDir())
+	client := journaling(&FakeClient{
+		CreateDatapointWithDaystampFunc: func(_, _, _, value, comment, _ string) (*Datapoint, error) {
+			if value == "0" {
+				return nil, errors.New("boom")
+			}
+			return &Datapoint{ID: "dp1"}, nil
+		},
+		DeleteDatapointFunc: func(_, id string) (*Datapoint, error) {
+			return &Datapoint{ID: id, Value: 2, Comment: "oops"}, nil
+		},
+		CreateChargeFunc: func(amount float64, note string, _ bool) (*Charge, error) {
+			return &Charge{Amount: amount, Note: note}, nil
+		},
+		APIRequestFunc: func(method, _ string, _ url.Values) (int, []byte, error) {
+			if method == "DELETE" {
+				return 404, nil, nil
+			}
+			return 200, nil, nil
+		},
+	})
+	ctx := context.Background()
+
+	client.CreateDatapointWithDaystamp(ctx, "read", "", "20240115", "3", "chapter 2", "")
+	client.CreateDatapointWithDaystamp(ctx, "read", "", "", "0", "failed", "") // not recorded
+	client.DeleteDatapoint(ctx, "read", "dp9")
+	client.CreateCharge(ctx, 5, "dry", true) // not recorded
+	client.CreateCharge(ctx, 5, "late", false)
+	client.APIRequest(ctx, "GET", "users/me.json", nil)   // not recorded
+	client.APIRequest(ctx, "DELETE", "goals/x.json", nil) // rejected, not recorded
+	client.APIRequest(ctx, "PUT", "goals/read.json", nil)
+
+	entries, err := loadJournal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, strings.Join(strings.Fields(e.Action+" "+e.Goal+" "+e.Detail), " "))
+	}
+	want := []string{
+		`add read value=3 comment="chapter 2" id=dp1 daystamp=20240115`,
+		`delete read value=2 comment="oops" id=dp9`,
+		`charge $5.00 note="late"`,
+		`api PUT goals/read.json`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("journal =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestJournalingClientAlreadyLoggedAdds(t *testing.T) {
+	setHome(t, t.TempDir())
+	now := time.Now()
+	client := journaling(&FakeClient{
+		CreateDatapointWithDaystampFunc: func(_, _, _, _, comment, requestid string) (*Datapoint, error) {
+			if requestid == "old" { // logged an hour ago, left as it was
+				return &Datapoint{ID: "dp0", UpdatedAt: now.Add(-time.Hour).Unix()}, nil
+			}
+			return &Datapoint{ID: "dp1", Comment: comment, UpdatedAt: now.Unix()}, nil
+		},
+	})
+	ctx := context.Background()
+
+	client.CreateDatapointWithDaystamp(ctx, "read", "", "", "3", "first", "new")
+	client.CreateDatapointWithDaystamp(ctx, "read", "", "", "3", "second", "new") // Beeminder updated dp1's comment
+	client.CreateDatapointWithDaystamp(ctx, "read", "", "", "3", "again", "old")  // not recorded
+
+	entries, err := loadJournal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Action+" "+e.Detail)
+	}
+	want := []string{
+		`add value=3 comment="first" id=dp1`,
+		`edit value=3 comment="second" id=dp1 (already logged)`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("journal =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if entry, ok := lastUndoable(entries, time.Time{}, nil); !ok || entry.Action != "add" || entry.Datapoint.ID != "dp1" {
+		t.Errorf("lastUndoable = %+v, %v; want dp1's add", entry, ok)
+	}
+}
+
+func TestLoadJournalSkipsDamagedLines(t *testing.T) {
+	home := t.TempDir()
+	setHome(t, home)
+	if entries, err := loadJournal(); err != nil || entries != nil {
+		t.Fatalf("missing journal = %v, %v; want none", entries, err)
+	}
+	if err := appendJournal(JournalEntry{Action: "add", Goal: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	path, _ := getJournalPath()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"action":"del` + "\n")
+	f.Close()
+	if err := appendJournal(JournalEntry{Action: "add", Goal: "b"}); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := loadJournal()
+	if err != nil || len(entries) != 2 || entries[1].Goal != "b" {
+		t.Errorf("entries = %+v, %v; want the two whole lines", entries, err)
+	}
+}
+
+func TestRunJournalCommand(t *testing.T) {
+	setHome(t, t.TempDir())
+	var out, errb bytes.Buffer
+	code := runJournalCommand(nil, &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 0, "The journal is empty.", "")
+
+	start := time.Date(2024, 1, 15, 9, 0, 0, 0, time.Local)
+	for i, goal := range []string{"read", "write", "read", "read"} {
+		appendJournal(JournalEntry{Time: start.Add(time.Duration(i) * time.Hour), Action: "add", Goal: goal, Detail: "value=1"})
+	}
+
+	out.Reset()
+	code = runJournalCommand([]string{"--goal", "read", "--limit=2"}, &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 0, "", "")
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "2024-01-15 11:00 AM  add") || !strings.HasSuffix(lines[1], "read  value=1") {
+		t.Errorf("output = %q, want the last two changes to read", out.String())
+	}
+
+	out.Reset()
+	code = runJournalCommand([]string{"--json", "--limit", "0"}, &out, &errb)
+	var entries []JournalEntry
+	if code != 0 || json.Unmarshal(out.Bytes(), &entries) != nil || len(entries) != 4 {
+		t.Errorf("--json = %d, %q", code, out.String())
+	}
+
+	code = runJournalCommand([]string{"--limit=-1"}, &out, &errb)
+	checkResult(t, code, "", errb.String(), 1, "", "can't be negative")
+	code = runJournalCommand([]string{"extra"}, &out, &errb)
+	checkResult(t, code, "", errb.String(), 1, "", "Unexpected arguments")
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Responsive layout for the Goals tab. On a terminal at least splitMinWidth
+// columns wide the grid shares the screen with a details pane for the selected
+// goal, which also hosts the detail view and datapoint form that are modals on
+// narrower terminals. Everything that sizes the grid reads its width from
+// here, so the split is decided in one place.
+const (
+	// splitMinWidth is the narrowest terminal that gets the split layout.
+	splitMinWidth = 140
+	// detailPaneMinWidth and detailPaneMaxWidth bound the details pane: room
+	// for the narrowest and widest charts (minChartWidth, maxChartWidth) with
+	// their axis labels, plus the pane's border and padding.
+	detailPaneMinWidth = minChartWidth + 14
+	detailPaneMaxWidth = maxChartWidth + 14
+)
+
+// screenLayout is how the Goals tab divides the terminal's width.
+type screenLayout struct {
+	split     bool // the details pane is shown beside the grid
+	gridWidth int  // columns for the grid and its footer
+	paneWidth int  // columns for the details pane; 0 unless split
+}
+
+// layoutFor returns the layout for a terminal width: the whole width for the
+// grid below splitMinWidth, and otherwise about two fifths for the details
+// pane, within its bounds.
+func layoutFor(width int) screenLayout {
+	if width < splitMinWidth {
+		return screenLayout{gridWidth: width}
+	}
+	pane := max(detailPaneMinWidth, min(width*2/5, detailPaneMaxWidth))
+	return screenLayout{split: true, gridWidth: width - pane, paneWidth: pane}
+}
+
+// layout returns the Goals tab's layout for the current terminal size.
+func (m *appModel) layout() screenLayout {
+	return layoutFor(m.width)
+}
+
+// selectedGoal returns the goal under the grid cursor, or nil when there are
+// no goals to show.
+func (m *appModel) selectedGoal() *Goal {
+	goals := m.getDisplayGoals()
+	if m.cursor < 0 || m.cursor >= len(goals) {
+		return nil
+	}
+	return &goals[m.cursor]
+}
+
+// followSelection fetches the selected goal's datapoints for the details pane
+// when it's shown and the selection has moved. Only one fetch runs at a time;
+// when it lands, the next call catches up with wherever the cursor went.
+func (m *appModel) followSelection() tea.Cmd {
+	g := m.selectedGoal()
+	if !m.layout().split || g == nil || g.Slug == m.paneSlug || m.paneLoading {
+		return nil
+	}
+	m.paneSlug = g.Slug
+	m.paneLoading = true
+	return loadGoalDetailsCmd(m.ctx, m.client, g.Slug)
+}
+
+// paneGoalFor returns what the details pane shows for goal g: its fetched
+// details when they're for g, else g itself, without datapoints.
+func (m *appModel) paneGoalFor(g *Goal) *Goal {
+	if g != nil && m.paneGoal != nil && m.paneGoal.Slug == g.Slug {
+		return m.paneGoal
+	}
+	return g
+}
+
+// besidePane lays the grid out to width columns with the details pane on its
+// right. It is what rendering the grid in a Width style and joining the two
+// with lipgloss.JoinHorizontal gives, but measures each grid line once and
+// only wraps the (rare) line that is too long, as every frame on a wide
+// terminal goes through here.
+func besidePane(grid string, width int, pane string) string {
+	var left []string
+	for _, line := range strings.Split(grid, "\n") {
+		w := lipgloss.Width(line)
+		if w > width {
+			left = append(left, strings.Split(lipgloss.NewStyle().Width(width).Render(line), "\n")...)
+			continue
+		}
+		left = append(left, line+strings.Repeat(" ", width-w))
+	}
+	right := strings.Split(pane, "\n")
+
+	var b strings.Builder
+	for i := range max(len(left), len(right)) {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		if i < len(left) {
+			b.WriteString(left[i])
+		} else {
+			b.WriteString(strings.Repeat(" ", width))
+		}
+		if i < len(right) {
+			b.WriteString(right[i])
+		}
+	}
+	return b.String()
+}
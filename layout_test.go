@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestLayoutFor(t *testing.T) {
+	tests := []struct {
+		width int
+		want  screenLayout
+	}{
+		{80, screenLayout{gridWidth: 80}},
+		{139, screenLayout{gridWidth: 139}},
+		{140, screenLayout{split: true, gridWidth: 84, paneWidth: 56}},
+		{300, screenLayout{split: true, gridWidth: 206, paneWidth: detailPaneMaxWidth}},
+	}
+	for _, tt := range tests {
+		if got := layoutFor(tt.width); got != tt.want {
+			t.Errorf("layoutFor(%d) = %+v, want %+v", tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestSplitPaneFollowsSelection(t *testing.T) {
+	var fetched []string
+	client := &FakeClient{
+		FetchGoalWithDatapointsFunc: func(slug string) (*Goal, error) {
+			fetched = append(fetched, slug)
+			return &Goal{Slug: slug, Datapoints: []Datapoint{{Timestamp: 1, Value: 7, Comment: "from " + slug}}}, nil
+		},
+	}
+	m := model{state: "app", appModel: appModel{
+		ctx:    context.Background(),
+		client: client,
+		config: &Config{Username: "u"},
+		goals:  []Goal{{Slug: "alpha"}, {Slug: "beta"}},
+		width:  160,
+		height: 40,
+	}}
+
+	// Any update on a wide terminal fetches the selected goal for the pane.
+	tm, cmd := m.Update(tea.WindowSizeMsg{Width: 160, Height: 40})
+	m = mustModel(t, tm)
+	if cmd == nil {
+		t.Fatal("a wide terminal should fetch the selected goal's details")
+	}
+	m = mustModel(t, mustTeaModel(m.Update(cmd())))
+	view := m.View()
+	if !strings.Contains(view, "Beeminder Goals - u") || !strings.Contains(view, "from alpha") {
+		t.Errorf("split view lacks the grid or alpha's details:\n%s", view)
+	}
+
+	// Moving the cursor fetches the next goal; staying put doesn't refetch.
+	m = mustModel(t, mustTeaModel(m.Update(tea.KeyMsg{Type: tea.KeyRight})))
+	if m.appModel.paneSlug != "beta" || !m.appModel.paneLoading {
+		t.Fatalf("pane fetch = %q (loading %v), want beta", m.appModel.paneSlug, m.appModel.paneLoading)
+	}
+	m = mustModel(t, mustTeaModel(m.Update(loadGoalDetailsCmd(m.appModel.ctx, client, "beta")())))
+	if !strings.Contains(m.View(), "from beta") {
+		t.Errorf("pane didn't follow the cursor:\n%s", m.View())
+	}
+	m = mustModel(t, mustTeaModel(m.Update(tea.FocusMsg{})))
+	if m.appModel.paneLoading {
+		t.Error("an update that doesn't move the cursor shouldn't refetch")
+	}
+
+	// Enter opens the goal in the pane rather than as a modal over the grid.
+	m = mustModel(t, mustTeaModel(m.Update(tea.KeyMsg{Type: tea.KeyEnter})))
+	if view := m.View(); !m.appModel.inGoalModal() || !strings.Contains(view, "Beeminder Goals - u") || !strings.Contains(view, "ESC: Close") {
+		t.Errorf("open goal should share the screen with the grid:\n%s", view)
+	}
+
+	// Clicks on the pane aren't on a goal.
+	m.appModel.closeModal()
+	m = mustModel(t, mustTeaModel(m.Update(tea.MouseMsg{X: 150, Y: 3, Action: tea.MouseActionRelease, Button: tea.MouseButtonLeft})))
+	if m.appModel.inGoalModal() {
+		t.Error("a click on the pane opened a goal")
+	}
+}
@@ -33,8 +33,8 @@ func handleListCommand() {
 		os.Exit(1)
 	}
 
-	client := NewHTTPClient(config)
-	code = runListCommand(context.Background(), client, archived, outputFormat, os.Stdout, os.Stderr)
+	client := journaling(recordingSlugs(NewHTTPClient(config), config.Username))
+	code = runListCommand(context.Background(), client, archived, goalFilter, outputFormat, os.Stdout, os.Stderr)
 	if code == 0 && outputFormat == "table" {
 		// Check for updates and display message if available. Skipped for json/csv
 		// so the update banner never corrupts machine-readable output.
@@ -75,9 +75,10 @@ func parseListArgs(args []string, out, errOut io.Writer) (archived bool, exitCod
 // runListCommand is the testable core of `buzz list`. It fetches the requested
 // set of goals (active, or archived when archived is true), renders the table
 // to out, writes any fetch error to errOut, and returns the process exit code.
+// keep, when non-nil, drops goals it rejects (the global --filter).
 // Splitting stdout (out) from stderr (errOut) keeps the table pipeable and
-// matches the other command cores (e.g. runCreateCommand).
-func runListCommand(ctx context.Context, client Client, archived bool, format string, out, errOut io.Writer) int {
+// matches the other command cores (e.g. runDataCommand).
+func runListCommand(ctx context.Context, client Client, archived bool, keep func(Goal) bool, format string, out, errOut io.Writer) int {
 	noun := "goals"
 	fetch := client.FetchGoals
 	if archived {
@@ -85,13 +86,32 @@ func runListCommand(ctx context.Context, client Client, archived bool, format st
 		fetch = client.FetchArchivedGoals
 	}
 
+	// jsonl writes each goal as soon as it arrives, so it skips the sort and
+	// comes out in the order Beeminder sends the goals.
+	if format == "jsonl" {
+		write := jsonLines[Goal](out)
+		err := client.StreamGoals(ctx, archived, func(g Goal) error {
+			if keep != nil && !keep(g) {
+				return nil
+			}
+			return write(g)
+		})
+		if err != nil {
+			fmt.Fprintf(errOut, "Error: Failed to fetch %s: %s\n", noun, redactError(err))
+			return 1
+		}
+		return 0
+	}
+
 	goals, err := fetch(ctx)
 	if err != nil {
 		fmt.Fprintf(errOut, "Error: Failed to fetch %s: %s\n", noun, redactError(err))
 		return 1
 	}
 
-	// Sort goals alphabetically by slug for easy scanning
+	// Apply the saved --filter, if any, then sort alphabetically by slug for
+	// easy scanning
+	goals = keepGoals(goals, keep)
 	SortGoalsBySlug(goals)
 
 	table := Table{
@@ -152,7 +152,7 @@ func TestRunListCommand(t *testing.T) {
 		}
 
 		var out, errOut bytes.Buffer
-		code := runListCommand(context.Background(), client, false, "table", &out, &errOut)
+		code := runListCommand(context.Background(), client, false, nil, "table", &out, &errOut)
 		if code != 0 {
 			t.Fatalf("expected exit code 0, got %d", code)
 		}
@@ -168,6 +168,28 @@ func TestRunListCommand(t *testing.T) {
 		}
 	})
 
+	t.Run("applies the saved filter", func(t *testing.T) {
+		client := &FakeClient{
+			FetchGoalsFunc: func() ([]Goal, error) {
+				return []Goal{{Slug: "work", Tags: []string{"work"}}, {Slug: "gym"}}, nil
+			},
+		}
+		keep, err := compileFilter("tag:work")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var out, errOut bytes.Buffer
+		code := runListCommand(context.Background(), client, false, keep, "table", &out, &errOut)
+		if code != 0 {
+			t.Fatalf("expected exit code 0, got %d", code)
+		}
+		got := out.String()
+		if !strings.Contains(got, "Total goals: 1") || strings.Contains(got, "gym") {
+			t.Errorf("expected only the tagged goal, got:\n%s", got)
+		}
+	})
+
 	t.Run("lists archived goals", func(t *testing.T) {
 		client := &FakeClient{
 			FetchArchivedGoalsFunc: func() ([]Goal, error) {
@@ -176,7 +198,7 @@ func TestRunListCommand(t *testing.T) {
 		}
 
 		var out, errOut bytes.Buffer
-		code := runListCommand(context.Background(), client, true, "table", &out, &errOut)
+		code := runListCommand(context.Background(), client, true, nil, "table", &out, &errOut)
 		if code != 0 {
 			t.Fatalf("expected exit code 0, got %d", code)
 		}
@@ -196,7 +218,7 @@ func TestRunListCommand(t *testing.T) {
 		client := &FakeClient{FetchGoalsFunc: func() ([]Goal, error) { return nil, nil }}
 
 		var out, errOut bytes.Buffer
-		code := runListCommand(context.Background(), client, false, "table", &out, &errOut)
+		code := runListCommand(context.Background(), client, false, nil, "table", &out, &errOut)
 		if code != 0 {
 			t.Fatalf("expected exit code 0, got %d", code)
 		}
@@ -209,7 +231,7 @@ func TestRunListCommand(t *testing.T) {
 		client := &FakeClient{FetchArchivedGoalsFunc: func() ([]Goal, error) { return nil, nil }}
 
 		var out, errOut bytes.Buffer
-		code := runListCommand(context.Background(), client, true, "table", &out, &errOut)
+		code := runListCommand(context.Background(), client, true, nil, "table", &out, &errOut)
 		if code != 0 {
 			t.Fatalf("expected exit code 0, got %d", code)
 		}
@@ -226,7 +248,7 @@ func TestRunListCommand(t *testing.T) {
 		}
 
 		var out, errOut bytes.Buffer
-		code := runListCommand(context.Background(), client, false, "json", &out, &errOut)
+		code := runListCommand(context.Background(), client, false, nil, "json", &out, &errOut)
 		if code != 0 {
 			t.Fatalf("expected exit code 0, got %d", code)
 		}
@@ -247,7 +269,7 @@ func TestRunListCommand(t *testing.T) {
 		client := &FakeClient{FetchGoalsFunc: func() ([]Goal, error) { return nil, nil }}
 
 		var out, errOut bytes.Buffer
-		code := runListCommand(context.Background(), client, false, "json", &out, &errOut)
+		code := runListCommand(context.Background(), client, false, nil, "json", &out, &errOut)
 		if code != 0 {
 			t.Fatalf("expected exit code 0, got %d", code)
 		}
@@ -264,7 +286,7 @@ func TestRunListCommand(t *testing.T) {
 		}
 
 		var out, errOut bytes.Buffer
-		code := runListCommand(context.Background(), client, false, "csv", &out, &errOut)
+		code := runListCommand(context.Background(), client, false, nil, "csv", &out, &errOut)
 		if code != 0 {
 			t.Fatalf("expected exit code 0, got %d", code)
 		}
@@ -289,7 +311,7 @@ func TestRunListCommand(t *testing.T) {
 		}
 
 		var out, errOut bytes.Buffer
-		code := runListCommand(context.Background(), client, true, "table", &out, &errOut)
+		code := runListCommand(context.Background(), client, true, nil, "table", &out, &errOut)
 		if code != 1 {
 			t.Fatalf("expected exit code 1, got %d", code)
 		}
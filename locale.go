@@ -0,0 +1,168 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// localeFormat is how numbers, dates, and times are written in a locale.
+// Only what buzz's human-readable output needs: machine-readable output
+// (json, jsonl, csv) and everything sent to Beeminder stay locale-neutral.
+type localeFormat struct {
+	decimal string // decimal separator
+	group   string // thousands separator; "" for none
+	date    string // time layout for a calendar date
+	clock24 bool   // whether times of day are written 17:30 rather than 5:30 PM
+}
+
+// defaultLocale is buzz's long-standing formatting: a decimal point, no
+// grouping, ISO dates, and a 12-hour clock. English locales keep it.
+var defaultLocale = localeFormat{decimal: ".", date: "2006-01-02"}
+
+// localeFormats maps language (and a few language_REGION) codes to their
+// conventions. A language missing here gets defaultLocale.
+var localeFormats = map[string]localeFormat{
+	"de":    {decimal: ",", group: ".", date: "02.01.2006", clock24: true},
+	"de_ch": {decimal: ".", group: "'", date: "02.01.2006", clock24: true},
+	"fr":    {decimal: ",", group: " ", date: "02/01/2006", clock24: true},
+	"es":    {decimal: ",", group: ".", date: "02/01/2006", clock24: true},
+	"it":    {decimal: ",", group: ".", date: "02/01/2006", clock24: true},
+	"pt":    {decimal: ",", group: ".", date: "02/01/2006", clock24: true},
+	"nl":    {decimal: ",", group: ".", date: "02-01-2006", clock24: true},
+	"da":    {decimal: ",", group: ".", date: "02.01.2006", clock24: true},
+	"nb":    {decimal: ",", group: " ", date: "02.01.2006", clock24: true},
+	"no":    {decimal: ",", group: " ", date: "02.01.2006", clock24: true},
+	"sv":    {decimal: ",", group: " ", date: "2006-01-02", clock24: true},
+	"fi":    {decimal: ",", group: " ", date: "02.01.2006", clock24: true},
+	"pl":    {decimal: ",", group: " ", date: "02.01.2006", clock24: true},
+	"cs":    {decimal: ",", group: " ", date: "02.01.2006", clock24: true},
+	"ru":    {decimal: ",", group: " ", date: "02.01.2006", clock24: true},
+	"uk":    {decimal: ",", group: " ", date: "02.01.2006", clock24: true},
+	"tr":    {decimal: ",", group: ".", date: "02.01.2006", clock24: true},
+}
+
+// activeLocale is the locale buzz formats for: the config's locale when set
+// (applyLocale), otherwise the environment's.
+var activeLocale = localeFromEnv()
+
+// localeFromEnv reads the locale the way POSIX tools pick their numeric
+// locale: $LC_ALL, then $LC_NUMERIC, then $LANG.
+func localeFromEnv() localeFormat {
+	for _, name := range []string{"LC_ALL", "LC_NUMERIC", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			return lookupLocale(value)
+		}
+	}
+	return defaultLocale
+}
+
+// lookupLocale returns the conventions for a locale name such as "de",
+// "de_DE", "pt-BR", or "fr_FR.UTF-8@euro", falling back from the region to
+// the language and then to defaultLocale.
+func lookupLocale(name string) localeFormat {
+	name = strings.ToLower(name)
+	if i := strings.IndexAny(name, ".@"); i >= 0 {
+		name = name[:i]
+	}
+	name = strings.ReplaceAll(name, "-", "_")
+	if f, ok := localeFormats[name]; ok {
+		return f
+	}
+	lang, _, _ := strings.Cut(name, "_")
+	if f, ok := localeFormats[lang]; ok {
+		return f
+	}
+	return defaultLocale
+}
+
+// applyLocale makes the config's locale override the environment's. An empty
+// name leaves the environment's in place.
+func applyLocale(name string) {
+	if name != "" {
+		activeLocale = lookupLocale(name)
+	}
+}
+
+// applyClock makes the config's clock setting, "12h" or "24h", override the
+// locale's. Anything else leaves the locale's in place.
+func applyClock(setting string) {
+	switch setting {
+	case "12h":
+		activeLocale.clock24 = false
+	case "24h":
+		activeLocale.clock24 = true
+	}
+}
+
+// number localizes a number already formatted with a decimal point, such as
+// the output of %.6g or %.2f: the decimal point becomes the locale's, and an
+// integer part of five or more digits is grouped in thousands (leaving
+// four-digit numbers like years alone, as many of these locales do).
+// Exponents are left as they are.
+func (l localeFormat) number(s string) string {
+	if l.decimal == "." && l.group == "" {
+		return s
+	}
+	sign := ""
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		sign, s = s[:1], s[1:]
+	}
+	end := strings.IndexFunc(s, func(r rune) bool { return r < '0' || r > '9' })
+	if end < 0 {
+		end = len(s)
+	}
+	intPart, rest := s[:end], s[end:]
+	if l.group != "" && len(intPart) >= 5 {
+		var b strings.Builder
+		for i, r := range intPart {
+			if i > 0 && (len(intPart)-i)%3 == 0 {
+				b.WriteString(l.group)
+			}
+			b.WriteRune(r)
+		}
+		intPart = b.String()
+	}
+	if strings.HasPrefix(rest, ".") {
+		rest = l.decimal + rest[1:]
+	}
+	return sign + intPart + rest
+}
+
+// formatValue formats a datapoint or goal value for display.
+func formatValue(v float64) string {
+	return activeLocale.number(fmt.Sprintf("%.6g", v))
+}
+
+// formatMoney formats a dollar amount with the given number of decimals.
+// Beeminder charges in US dollars whatever the locale, so the amount keeps
+// its "$" and only the number is localized.
+func formatMoney(amount float64, decimals int) string {
+	return "$" + activeLocale.number(fmt.Sprintf("%.*f", decimals, amount))
+}
+
+// formatDate formats a calendar date for display.
+func formatDate(t time.Time) string {
+	return t.Format(activeLocale.date)
+}
+
+// clockLayout returns the time layout for a time of day, "3:04 PM" or
+// "15:04" per the clock preference.
+func clockLayout() string {
+	if activeLocale.clock24 {
+		return "15:04"
+	}
+	return "3:04 PM"
+}
+
+// normalizeDecimal rewrites a value typed with the locale's decimal comma,
+// such as "3,5", to the decimal point Beeminder and strconv expect. Input
+// already using a point, or typed under a decimal-point locale, is returned
+// unchanged, so "1,000" isn't misread as one where commas group thousands.
+func normalizeDecimal(s string) string {
+	if activeLocale.decimal != "," || strings.Contains(s, ".") {
+		return s
+	}
+	return strings.ReplaceAll(s, ",", ".")
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// TestMain pins the default locale so output assertions don't depend on the
+// locale of the machine running the tests.
+func TestMain(m *testing.M) {
+	activeLocale = defaultLocale
+	os.Exit(m.Run())
+}
+
+// withLocale makes name the active locale for the rest of the test.
+func withLocale(t *testing.T, name string) {
+	t.Helper()
+	saved := activeLocale
+	activeLocale = lookupLocale(name)
+	t.Cleanup(func() { activeLocale = saved })
+}
+
+func TestLookupLocale(t *testing.T) {
+	tests := []struct {
+		name string
+		want localeFormat
+	}{
+		{"de_DE.UTF-8", localeFormats["de"]},
+		{"de-CH", localeFormats["de_ch"]},
+		{"fr_FR@euro", localeFormats["fr"]},
+		{"PT_br", localeFormats["pt"]},
+		{"en_US.UTF-8", defaultLocale},
+		{"C", defaultLocale},
+		{"xx", defaultLocale},
+	}
+	for _, tt := range tests {
+		if got := lookupLocale(tt.name); got != tt.want {
+			t.Errorf("lookupLocale(%q) = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLocaleFromEnv(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_NUMERIC", "fr_FR.UTF-8")
+	t.Setenv("LANG", "de_DE.UTF-8")
+	if got := localeFromEnv(); got != localeFormats["fr"] {
+		t.Errorf("LC_NUMERIC should win over LANG, got %+v", got)
+	}
+	t.Setenv("LC_ALL", "C")
+	if got := localeFromEnv(); got != defaultLocale {
+		t.Errorf("LC_ALL should win, got %+v", got)
+	}
+}
+
+func TestLocalizedFormatting(t *testing.T) {
+	day := time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC)
+	if formatValue(12345.5) != "12345.5" || formatMoney(1270, 2) != "$1270.00" || formatPledge(2.5) != "2.50" || formatDate(day) != "2024-03-09" {
+		t.Errorf("default locale changed: %s %s %s %s", formatValue(12345.5), formatMoney(1270, 2), formatPledge(2.5), formatDate(day))
+	}
+
+	withLocale(t, "de_DE")
+	tests := []struct{ got, want string }{
+		{formatValue(3.5), "3,5"},
+		{formatValue(-12345.5), "-12.345,5"},
+		{formatValue(2024), "2024"},
+		{formatValue(1.5e20), "1,5e+20"},
+		{formatMoney(12345, 2), "$12.345,00"},
+		{formatMoney(30, 0), "$30"},
+		{formatPledge(2.5), "2,50"},
+		{formatPledge(12345), "12.345"},
+		{formatDate(day), "09.03.2024"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("got %q, want %q", tt.got, tt.want)
+		}
+	}
+}
+
+func TestDecimalCommaInput(t *testing.T) {
+	if got, err := parseAddValue("3,5", ""); err == nil {
+		t.Errorf("decimal-point locale accepted 3,5 as %s", got)
+	}
+
+	withLocale(t, "fr_FR")
+	for in, want := range map[string]string{"3,5": "3.5", "2,5*2": "5", "1.25": "1.25"} {
+		if got, err := parseAddValue(in, ""); err != nil || got != want {
+			t.Errorf("parseAddValue(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+	if msg := validateDatapointValue(normalizeDecimal("0,75")); msg != "" {
+		t.Errorf("0,75 rejected: %s", msg)
+	}
+}
+
+func TestClockPreference(t *testing.T) {
+	saved := activeLocale
+	t.Cleanup(func() { activeLocale = saved })
+	now := time.Date(2024, 3, 9, 9, 0, 0, 0, time.UTC)
+	losedate := time.Date(2024, 3, 9, 17, 30, 0, 0, time.UTC).Unix()
+
+	if got := formatDueTime(17*3600 + 30*60); got != "5:30 PM" {
+		t.Errorf("default clock: formatDueTime = %q", got)
+	}
+
+	applyClock("24h")
+	if got := formatDueTime(17*3600 + 30*60); got != "17:30" {
+		t.Errorf("24h: formatDueTime = %q", got)
+	}
+	if got := FormatAbsoluteDeadlineAt(losedate, now); got != "17:30" {
+		t.Errorf("24h: FormatAbsoluteDeadlineAt = %q", got)
+	}
+	if got := deadlineGroupLabel(Goal{Deadline: 14 * 3600}); got != "by 14:00" {
+		t.Errorf("24h: deadlineGroupLabel = %q", got)
+	}
+
+	// A locale on a 24-hour clock gives way to the config's "12h".
+	activeLocale = lookupLocale("de_DE")
+	if got := formatDueTime(-3600); got != "23:00" {
+		t.Errorf("de_DE: formatDueTime = %q", got)
+	}
+	applyClock("12h")
+	if got := formatDueTime(-3600); got != "11:00 PM" {
+		t.Errorf("de_DE with 12h: formatDueTime = %q", got)
+	}
+}
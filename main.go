@@ -2,9 +2,11 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	// Embed the IANA timezone database so time.LoadLocation works on systems
 	// without system tzdata (e.g. Windows, minimal containers). The schedule
@@ -19,96 +21,51 @@ import (
 // version is set via ldflags during build
 var version = "dev"
 
-// outputFormat holds the global --format value ("table", "json", or "csv"),
+// outputFormat holds the global --format value ("table", "json", "jsonl", or "csv"),
 // set once in main from the CLI. The list-style read commands, `data`, and
 // `next` honor it; other commands ignore it (like --no-color).
 var outputFormat = "table"
 
 // validFormats are the accepted --format values.
-var validFormats = map[string]bool{"table": true, "json": true, "csv": true}
-
-func printHelp() {
-	fmt.Println("buzz - A terminal user interface for Beeminder")
-	fmt.Println("")
-	fmt.Println("USAGE:")
-	fmt.Println("  buzz                              Launch the interactive TUI")
-	fmt.Println("  buzz next                         Output a terse summary of the next due goal")
-	fmt.Println("  buzz next --watch                 Watch mode - continuously refresh every 5 minutes")
-	fmt.Println("  buzz next -w                      Watch mode (shorthand)")
-	fmt.Println("  buzz list                         List all goals with slug, title, units, rate, and stakes")
-	fmt.Println("  buzz list --archived              List archived goals instead of active ones")
-	fmt.Println("  buzz all                          Output all goals")
-	fmt.Println("  buzz today                        Output all goals due today")
-	fmt.Println("  buzz tomorrow                     Output all goals due tomorrow")
-	fmt.Println("  buzz due <duration>               Output all goals due within duration (e.g., 10m, 1h, 5d, 1w)")
-	fmt.Println("  buzz less                         Output all do-less type goals")
-	fmt.Println("  buzz add [--requestid=<id>] [--daystamp=<date>] <goalslug> <value> [comment]")
-	fmt.Println("                                    Add a datapoint to a goal")
-	fmt.Println("                                    --daystamp: Date in YYYYMMDD format (default: current time)")
-	fmt.Println("                                    Note: Flags must come BEFORE positional args")
-	fmt.Println("  echo \"<value>\" | buzz add [--requestid=<id>] [--daystamp=<date>] <goalslug> [comment]")
-	fmt.Println("                                    Add a datapoint with value from stdin")
-	fmt.Println("  buzz refresh <goalslug>           Refresh autodata for a goal")
-	fmt.Println("  buzz view <goalslug>              View detailed information about a specific goal")
-	fmt.Println("  buzz view <goalslug> --web        Open the goal in the browser")
-	fmt.Println("  buzz view <goalslug> --json       Output goal data as JSON")
-	fmt.Println("  buzz view <goalslug> --json --datapoints  Include datapoints in JSON output")
-	fmt.Println("  buzz data [--asc|--desc] <goalslug>")
-	fmt.Println("                                    List a goal's datapoints (date, value, comment)")
-	fmt.Println("                                    --asc: oldest-first (default)  --desc: newest-first")
-	fmt.Println("  buzz review                       Interactive review of all goals")
-	fmt.Println("  buzz charge <amount> <note> [--dryrun]")
-	fmt.Println("                                    Create a charge for the authenticated user")
-	fmt.Println("  buzz create                       Interactively create a new Beeminder goal")
-	fmt.Println("  buzz create --slug=<s> --units=<u> [--title --type --goaldate --goalval --rate --deadline]")
-	fmt.Println("                                    Non-interactively create a goal (see --help)")
-	fmt.Println("  buzz deadline [--yes] <goalslug> <time>")
-	fmt.Println("                                    Change a goal's deadline (e.g., \"3:00 PM\" or \"15:00\")")
-	fmt.Println("  buzz schedule                     Display goal deadline distribution throughout a 24-hour day")
-	fmt.Println("  buzz uncle [-y|--yes] <goalslug>  Instantly derail a goal that is in the red, paying the pledge")
-	fmt.Println("                                    -y, --yes: Skip the confirmation prompt")
-	fmt.Println("  buzz ratchet [-y|--yes] <goalslug> <days>")
-	fmt.Println("                                    Remove safety buffer, leaving <days> of buffer on the goal")
-	fmt.Println("                                    -y, --yes: Skip the confirmation prompt")
-	fmt.Println("  buzz api [-X <method>] [-d <key=value>]... <path>")
-	fmt.Println("                                    Make a raw authenticated Beeminder API request")
-	fmt.Println("                                    e.g. buzz api users/me.json")
-	fmt.Println("  buzz auth login                   Authenticate by pasting your Beeminder API credentials")
-	fmt.Println("  buzz help                         Show this help message")
-	fmt.Println("")
-	fmt.Println("GLOBAL OPTIONS:")
-	fmt.Println("  --format <table|json|csv>         Output format for the list commands, data, and next (default: table)")
-	fmt.Println("  --no-color                        Disable colored output")
-	fmt.Println("  -h, --help                        Show this help message")
-	fmt.Println("  -v, --version                     Show version information")
-	fmt.Println("")
-	fmt.Println("For more information, visit: https://buzz.nathanarthur.com")
-}
+var validFormats = map[string]bool{"table": true, "json": true, "jsonl": true, "csv": true}
 
 func printVersion() {
 	fmt.Printf("buzz version %s\n", version)
 
 	// Check for updates and display message if available
-	fmt.Print(getUpdateMessage())
+	fmt.Print(getVersionUpdateMessage())
 }
 
 // parseNoColorFlag extracts the --no-color flag from the provided arguments
 // and returns whether the flag was found and the filtered arguments without the flag
 func parseNoColorFlag(args []string) (noColor bool, filteredArgs []string) {
+	return extractBoolFlag(args, "--no-color")
+}
+
+// parseNoUpdateCheckFlag extracts the global --no-update-check flag, like
+// parseNoColorFlag.
+func parseNoUpdateCheckFlag(args []string) (noCheck bool, filteredArgs []string) {
+	return extractBoolFlag(args, "--no-update-check")
+}
+
+// extractBoolFlag removes every occurrence of a valueless global flag from
+// args (keeping the program name) and reports whether it was present.
+func extractBoolFlag(args []string, name string) (found bool, filteredArgs []string) {
 	filteredArgs = []string{args[0]} // Keep program name
 	for i := 1; i < len(args); i++ {
-		if args[i] == "--no-color" {
-			noColor = true
+		if args[i] == name {
+			found = true
 		} else {
 			filteredArgs = append(filteredArgs, args[i])
 		}
 	}
-	return noColor, filteredArgs
+	return found, filteredArgs
 }
 
 // parseFormatFlag extracts a global --format <value> (or --format=<value>) flag
 // from args, returning the chosen format ("table" when absent) and args with
-// the flag removed. A missing or unknown value is an error.
+// the flag removed. --jsonl is shorthand for --format jsonl. A missing or
+// unknown value is an error.
 func parseFormatFlag(args []string) (format string, filteredArgs []string, err error) {
 	format = "table"
 	filteredArgs = []string{args[0]} // Keep program name
@@ -117,10 +74,12 @@ func parseFormatFlag(args []string) (format string, filteredArgs []string, err e
 		switch {
 		case arg == "--format":
 			if i+1 >= len(args) {
-				return "", nil, fmt.Errorf("--format requires a value (table, json, or csv)")
+				return "", nil, fmt.Errorf("--format requires a value (table, json, jsonl, or csv)")
 			}
 			format = args[i+1]
 			i++
+		case arg == "--jsonl":
+			format = "jsonl"
 		case strings.HasPrefix(arg, "--format="):
 			format = strings.TrimPrefix(arg, "--format=")
 		default:
@@ -128,13 +87,38 @@ func parseFormatFlag(args []string) (format string, filteredArgs []string, err e
 			continue
 		}
 		if !validFormats[format] {
-			return "", nil, fmt.Errorf("invalid --format value %q (want table, json, or csv)", format)
+			return "", nil, fmt.Errorf("invalid --format value %q (want table, json, jsonl, or csv)", format)
 		}
 	}
 	return format, filteredArgs, nil
 }
 
+// parseFilterFlag extracts a global --filter <value> (or --filter=<value>) flag
+// from args, like parseFormatFlag. The value, a saved filter name or an
+// expression, is resolved by loadGoalFilter; a missing value is an error.
+func parseFilterFlag(args []string) (name string, filteredArgs []string, err error) {
+	filteredArgs = []string{args[0]} // Keep program name
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--filter":
+			if i+1 >= len(args) {
+				return "", nil, fmt.Errorf("--filter requires a saved filter name or an expression")
+			}
+			name = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "--filter="):
+			name = strings.TrimPrefix(arg, "--filter=")
+		default:
+			filteredArgs = append(filteredArgs, arg)
+		}
+	}
+	return name, filteredArgs, nil
+}
+
 func main() {
+	enableTerminalANSI()
+
 	// Check for global --no-color flag before processing other commands
 	noColor, filteredArgs := parseNoColorFlag(os.Args)
 	os.Args = filteredArgs
@@ -144,6 +128,10 @@ func main() {
 		lipgloss.SetColorProfile(termenv.Ascii)
 	}
 
+	// --no-update-check suppresses the update-available message (and the
+	// GitHub request behind it) for this invocation
+	noUpdateCheck, os.Args = parseNoUpdateCheckFlag(os.Args)
+
 	// Extract the global --format flag before command dispatch, mirroring
 	// --no-color. Handlers read outputFormat; unknown values fail fast.
 	format, formatFiltered, err := parseFormatFlag(os.Args)
@@ -154,83 +142,64 @@ func main() {
 	os.Args = formatFiltered
 	outputFormat = format
 
+	// --filter applies a saved filter from ~/.buzzrc, or an inline expression,
+	// to the list commands and the TUI. Resolve it now so a typo fails before
+	// any work.
+	filterName, filterFiltered, err := parseFilterFlag(os.Args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(2)
+	}
+	os.Args = filterFiltered
+	if filterName != "" {
+		goalFilter, err = loadGoalFilter(filterName)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(2)
+		}
+		goalFilterName = filterName
+	}
+
 	// Check for CLI arguments
 	if len(os.Args) > 1 {
-		switch os.Args[1] {
-		case "next":
-			handleNextCommand()
-			return
-		case "list":
-			handleListCommand()
-			return
-		case "all":
-			handleAllCommand()
-			return
-		case "today":
-			handleTodayCommand()
-			return
-		case "tomorrow":
-			handleTomorrowCommand()
-			return
-		case "due":
-			handleDueCommand()
-			return
-		case "less":
-			handleLessCommand()
-			return
-		case "add":
-			handleAddCommand()
-			return
-		case "refresh":
-			handleRefreshCommand()
-			return
-		case "view":
-			handleViewCommand()
-			return
-		case "data":
-			handleDataCommand()
-			return
-		case "review":
-			handleReviewCommand()
-			return
-		case "charge":
-			handleChargeCommand()
-			return
-		case "create":
-			handleCreateCommand()
-			return
-		case "deadline":
-			handleDeadlineCommand()
-			return
-		case "schedule":
-			handleScheduleCommand()
-			return
-		case "uncle":
-			handleUncleCommand()
-			return
-		case "ratchet":
-			handleRatchetCommand()
-			return
-		case "api":
-			handleAPICommand()
-			return
-		case "auth":
-			handleAuthCommand()
-			return
-		case "help", "-h", "--help":
-			printHelp()
-			return
-		case "-v", "--version", "version":
-			printVersion()
-			return
-		default:
+		cmd, ok := findCommand(os.Args[1])
+		if !ok {
+			// Not a built-in: run the buzz-<name> plugin from PATH, if any.
+			if path, found := findPlugin(os.Args[1]); found {
+				handlePluginCommand(os.Args[1], path, os.Args[2:], noColor)
+			}
 			fmt.Printf("Unknown command: %s\n", os.Args[1])
-			fmt.Println("Available commands: next, list, all, today, tomorrow, due, less, add, refresh, view, data, review, charge, create, deadline, schedule, uncle, ratchet, api, auth, help, version")
+			fmt.Printf("Available commands: %s\n", strings.Join(commandNames(), ", "))
 			fmt.Println("Run 'buzz --help' for more information.")
 			os.Exit(1)
 		}
+		// `buzz <command> --help` shows the same detailed help as `buzz help
+		// <command>`, so every subcommand answers --help consistently.
+		if cmd.name != "help" && wantsHelp(os.Args[2:]) {
+			writeCommandHelp(os.Stdout, cmd)
+			return
+		}
+		// Counted locally for `buzz stats --self`.
+		countCommandUsage(cmd.name, time.Now())
+		cmd.run()
+		return
 	}
 
+	// Unlock an encrypted config before the TUI takes over the screen, where
+	// there'd be nowhere to ask for the passphrase.
+	if ConfigExists() {
+		if _, err := LoadConfig(); errors.Is(err, errConfigLocked) {
+			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+			os.Exit(1)
+		}
+	}
+	// Counted once the config is unlocked, so its no_usage_stats is seen.
+	countCommandUsage(tuiUsageName, time.Now())
+
+	// A hooks script named in ~/.buzzrc customizes adds and the grid. Load it
+	// now so a broken script is reported before the TUI takes the screen.
+	useConfiguredHooks(os.Stderr)
+
 	// No arguments, run the interactive TUI. The cancellable context is
 	// stored on the model and threaded into every Client call; the deferred
 	// cancel fires when p.Run() returns (user quit, error, or signal) so
@@ -238,7 +207,7 @@ func main() {
 	// http.Client.Timeout fires.
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	p := tea.NewProgram(initialModel(ctx), tea.WithAltScreen(), tea.WithMouseCellMotion())
+	p := tea.NewProgram(initialModel(ctx), tea.WithAltScreen(), tea.WithMouseCellMotion(), tea.WithReportFocus())
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Alas, there's been an error: %s", redactError(err))
 		os.Exit(1)
@@ -259,7 +228,7 @@ func loadConfigAndGoals() (*Config, Client, []Goal, error) {
 		return nil, nil, nil, fmt.Errorf("failed to load config: %w", err)
 	}
 
-	client := NewHTTPClient(config)
+	client := journaling(recordingSlugs(NewHTTPClient(config), config.Username))
 	goals, err := client.FetchGoals(context.Background())
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("failed to fetch goals: %w", err)
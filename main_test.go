@@ -168,6 +168,23 @@ func TestNoColorFlag(t *testing.T) {
 	}
 }
 
+// TestParseNoUpdateCheckFlag verifies --no-update-check is detected anywhere
+// on the command line and removed before dispatch.
+func TestParseNoUpdateCheckFlag(t *testing.T) {
+	found, filtered := parseNoUpdateCheckFlag([]string{"buzz", "today", "--no-update-check"})
+	if !found {
+		t.Error("expected --no-update-check to be detected")
+	}
+	if len(filtered) != 2 || filtered[1] != "today" {
+		t.Errorf("filtered args = %v, want [buzz today]", filtered)
+	}
+
+	found, filtered = parseNoUpdateCheckFlag([]string{"buzz", "today"})
+	if found || len(filtered) != 2 {
+		t.Errorf("found = %v, filtered = %v; want false, [buzz today]", found, filtered)
+	}
+}
+
 // TestParseFormatFlag covers the global --format extraction: default, both flag
 // spellings, flag removal from args, and error cases (missing/invalid value).
 func TestParseFormatFlag(t *testing.T) {
@@ -181,6 +198,8 @@ func TestParseFormatFlag(t *testing.T) {
 		{"no flag defaults to table", []string{"buzz", "list"}, "table", []string{"buzz", "list"}, false},
 		{"--format json (space)", []string{"buzz", "--format", "json", "list"}, "json", []string{"buzz", "list"}, false},
 		{"--format=csv (equals)", []string{"buzz", "list", "--format=csv"}, "csv", []string{"buzz", "list"}, false},
+		{"--format jsonl", []string{"buzz", "--format", "jsonl", "today"}, "jsonl", []string{"buzz", "today"}, false},
+		{"--jsonl shorthand", []string{"buzz", "list", "--jsonl"}, "jsonl", []string{"buzz", "list"}, false},
 		{"invalid value errors", []string{"buzz", "--format", "yaml", "list"}, "", nil, true},
 		{"missing value errors", []string{"buzz", "list", "--format"}, "", nil, true},
 	}
@@ -208,6 +227,38 @@ func TestParseFormatFlag(t *testing.T) {
 	}
 }
 
+func TestParseFilterFlag(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantName string
+		wantArgs []string
+		wantErr  bool
+	}{
+		{"no flag", []string{"buzz", "today"}, "", []string{"buzz", "today"}, false},
+		{"--filter work (space)", []string{"buzz", "--filter", "work", "today"}, "work", []string{"buzz", "today"}, false},
+		{"--filter=work (equals)", []string{"buzz", "list", "--filter=work"}, "work", []string{"buzz", "list"}, false},
+		{"missing value errors", []string{"buzz", "list", "--filter"}, "", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, filtered, err := parseFilterFlag(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr = %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if name != tt.wantName {
+				t.Errorf("name = %q, want %q", name, tt.wantName)
+			}
+			if strings.Join(filtered, " ") != strings.Join(tt.wantArgs, " ") {
+				t.Errorf("filtered args = %v, want %v", filtered, tt.wantArgs)
+			}
+		})
+	}
+}
+
 // TestDueFiltersSkipEndValueReached verifies that the today and tomorrow filters
 // exclude goals whose end value has already been reached — those goals can show
 // a negative baremin and shouldn't be surfaced as due.
@@ -978,3 +1029,115 @@ func TestFormatTimeValue(t *testing.T) {
 		})
 	}
 }
+
+func TestParseTodayArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		wantGroup string
+		wantTotal bool
+		wantCode  int
+		wantDone  bool
+	}{
+		{"no flags", nil, "", false, 0, false},
+		{"group by deadline hour", []string{"--group-by", "deadline-hour"}, "deadline-hour", false, 0, false},
+		{"total", []string{"--total"}, "", true, 0, false},
+		{"unknown grouping", []string{"--group-by=pledge"}, "", false, 2, true},
+		{"stray argument", []string{"extra"}, "", false, 2, true},
+		{"unknown flag", []string{"--sideways"}, "", false, 2, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errOut strings.Builder
+			group, total, code, done := parseTodayArgs(tt.args, &errOut)
+			if group != tt.wantGroup || total != tt.wantTotal || code != tt.wantCode || done != tt.wantDone {
+				t.Errorf("parseTodayArgs(%v) = (%q, %v, %d, %v), want (%q, %v, %d, %v)",
+					tt.args, group, total, code, done, tt.wantGroup, tt.wantTotal, tt.wantCode, tt.wantDone)
+			}
+			if done && !strings.Contains(errOut.String(), todayUsage) {
+				t.Errorf("usage errors should print the usage, got %q", errOut.String())
+			}
+		})
+	}
+}
+
+func TestWorkloadLegend(t *testing.T) {
+	goals := []Goal{
+		{Slug: "write", Baremin: "+1:30 within 1 day", Gunits: "hours"},
+		{Slug: "pushups", Baremin: "+40 in 5 hours", Gunits: "pushups"},
+		{Slug: "code", Baremin: "+1 within 1 day", Gunits: "Hours"},
+		{Slug: "blog", Baremin: "+1 in 2 hours", Gunits: "blog post"},
+		{Slug: "beer", Baremin: "+2 within 1 day", Gunits: "beers", GoalType: "drinker"},
+		{Slug: "done", Baremin: "0 today", Gunits: "pages"},
+	}
+	if got, want := workloadLegend(goals), "\nTotal: 2.5 hours, 40 pushups, 1 blog post\n"; got != want {
+		t.Errorf("workloadLegend = %q, want %q", got, want)
+	}
+	if got := workloadLegend(goals[4:]); !strings.Contains(got, "nothing left to do") {
+		t.Errorf("workloadLegend with no work = %q", got)
+	}
+}
+
+func TestParseDueArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		wantLabel string
+		wantDur   time.Duration
+		wantCode  int
+		wantDone  bool
+	}{
+		{"positional", []string{"1h"}, "1h", time.Hour, 0, false},
+		{"within flag", []string{"--within", "6h"}, "6h", 6 * time.Hour, 0, false},
+		{"within equals", []string{"--within=2d"}, "2d", 48 * time.Hour, 0, false},
+		{"missing", nil, "", 0, 1, true},
+		{"both", []string{"--within", "6h", "1h"}, "", 0, 1, true},
+		{"two durations", []string{"1h", "2h"}, "", 0, 1, true},
+		{"bad duration", []string{"--within", "soon"}, "", 0, 1, true},
+		{"unknown flag", []string{"--sideways"}, "", 0, 2, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errOut strings.Builder
+			label, dur, code, done := parseDueArgs(tt.args, &errOut)
+			if label != tt.wantLabel || dur != tt.wantDur || code != tt.wantCode || done != tt.wantDone {
+				t.Errorf("parseDueArgs(%v) = (%q, %v, %d, %v), want (%q, %v, %d, %v)",
+					tt.args, label, dur, code, done, tt.wantLabel, tt.wantDur, tt.wantCode, tt.wantDone)
+			}
+			if done && !strings.Contains(errOut.String(), dueUsage) {
+				t.Errorf("usage errors should print the usage, got %q", errOut.String())
+			}
+		})
+	}
+}
+
+func TestDeadlineGroupLabel(t *testing.T) {
+	tests := []struct {
+		deadline int
+		want     string
+	}{
+		{0, "by 12am"},
+		{14 * 3600, "by 2pm"},
+		{-3600, "by 11pm"},
+		{-1800, "by 11:30pm"},
+		{3 * 3600, "by 3am"},
+	}
+	for _, tt := range tests {
+		if got := deadlineGroupLabel(Goal{Deadline: tt.deadline}); got != tt.want {
+			t.Errorf("deadlineGroupLabel(%d) = %q, want %q", tt.deadline, got, tt.want)
+		}
+	}
+}
+
+func TestGroupRenderedRows(t *testing.T) {
+	goals := []Goal{
+		{Slug: "a", Deadline: 14 * 3600},
+		{Slug: "b", Deadline: -7200},
+		{Slug: "c", Deadline: 14 * 3600},
+	}
+	got := groupRenderedRows("a row\nb row\nc row\n", goals, deadlineGroupLabel)
+	want := "by 2pm:\n  a row\n  c row\n\nby 10pm:\n  b row\n"
+	if got != want {
+		t.Errorf("groupRenderedRows =\n%s\nwant\n%s", got, want)
+	}
+}
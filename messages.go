@@ -30,19 +30,51 @@ type goalDetailsLoadedMsg struct {
 	err  error
 }
 
+// lastValueLoadedMsg carries a goal's last datapoint value, fetched to
+// pre-fill the datapoint form
+type lastValueLoadedMsg struct {
+	slug  string
+	value float64
+	err   error
+}
+
 // goalCreatedMsg is sent when a goal creation completes
 type goalCreatedMsg struct {
 	goal *Goal
 	err  error
 }
 
+// chargeCreatedMsg is sent when a charge, or its dry run, completes
+type chargeCreatedMsg struct {
+	charge *Charge
+	dryrun bool
+	err    error
+}
+
+// undoneMsg is sent when an undo (see undoLastChangeCmd) completes. ok is
+// false when there was nothing to undo.
+type undoneMsg struct {
+	entry JournalEntry
+	ok    bool
+	err   error
+}
+
 // checkRefreshFlagMsg is sent periodically to check for external refresh requests
 type checkRefreshFlagMsg struct{}
 
+// whatsNewMsg carries the post-upgrade release notes summary ("" when there
+// is nothing new to show)
+type whatsNewMsg struct {
+	notes string
+}
+
 // navigationTimeoutMsg is sent when navigation highlight should be auto-disabled
 type navigationTimeoutMsg struct{}
 
-// loadGoalsCmd fetches goals from Beeminder API.
+// loadGoalsCmd fetches goals from Beeminder API. It fetches them without
+// their roads (FetchGoalSummaries), so startup and every refresh stay a small
+// response however many goals the account has; the road of the goal being
+// looked at arrives with its details (loadGoalDetailsCmd).
 //
 // The ctx is captured into the returned Cmd so cancellation from the caller
 // propagates through to the in-flight HTTP request. Today the only ctx
@@ -50,7 +82,7 @@ type navigationTimeoutMsg struct{}
 // quit-cancellation wiring will turn that into a cancellable parent.
 func loadGoalsCmd(ctx context.Context, client Client) tea.Cmd {
 	return func() tea.Msg {
-		goals, err := client.FetchGoals(ctx)
+		goals, err := client.FetchGoalSummaries(ctx)
 		if err != nil {
 			return goalsLoadedMsg{err: err}
 		}
@@ -66,27 +98,73 @@ func refreshTickCmd() tea.Cmd {
 	})
 }
 
-// submitDatapointCmd submits a datapoint to Beeminder API
-func submitDatapointCmd(ctx context.Context, client Client, goalSlug, timestamp, value, comment string) tea.Cmd {
+// submitDatapointCmd submits a datapoint to Beeminder API, on daystamp
+// (YYYYMMDD) when it's set and at timestamp otherwise
+func submitDatapointCmd(ctx context.Context, client Client, goalSlug, timestamp, daystamp, value, comment string) tea.Cmd {
 	return func() tea.Msg {
-		_, err := client.CreateDatapoint(ctx, goalSlug, timestamp, value, comment, "")
+		var err error
+		if daystamp != "" {
+			_, err = client.CreateDatapointWithDaystamp(ctx, goalSlug, timestamp, daystamp, value, comment, "")
+		} else {
+			_, err = client.CreateDatapoint(ctx, goalSlug, timestamp, value, comment, "")
+		}
 		return datapointSubmittedMsg{err: err}
 	}
 }
 
+// undoLastChangeCmd undoes the most recent change buzz journaled since since
+// that can be undone (see lastUndoable), skipping those already undone.
+func undoLastChangeCmd(ctx context.Context, client Client, since time.Time, undone map[string]bool) tea.Cmd {
+	return func() tea.Msg {
+		entries, err := loadJournal()
+		if err != nil {
+			return undoneMsg{err: err}
+		}
+		entry, ok := lastUndoable(entries, since, undone)
+		if !ok {
+			return undoneMsg{}
+		}
+		return undoneMsg{entry: entry, ok: true, err: undoJournalEntry(ctx, client, entry)}
+	}
+}
+
 // loadGoalDetailsCmd fetches detailed goal information including datapoints
 func loadGoalDetailsCmd(ctx context.Context, client Client, goalSlug string) tea.Cmd {
 	return func() tea.Msg {
 		goal, err := client.FetchGoalWithDatapoints(ctx, goalSlug)
+		goal.prepareChart()
 		return goalDetailsLoadedMsg{goal: goal, err: err}
 	}
 }
 
-// createGoalCmd submits a new goal to Beeminder API
-func createGoalCmd(ctx context.Context, client Client, slug, title, goalType, gunits, goaldate, goalval, rate string) tea.Cmd {
+// loadLastValueCmd fetches a goal's last datapoint value for the datapoint
+// form, off the UI goroutine so a slow network doesn't freeze the form
+func loadLastValueCmd(ctx context.Context, client Client, goalSlug string) tea.Cmd {
 	return func() tea.Msg {
-		goal, err := client.CreateGoal(ctx, slug, title, goalType, gunits, goaldate, goalval, rate)
-		return goalCreatedMsg{goal: goal, err: err}
+		value, err := client.GetLastDatapointValue(ctx, goalSlug)
+		return lastValueLoadedMsg{slug: goalSlug, value: value, err: err}
+	}
+}
+
+// createGoalCmd submits a new goal to Beeminder API, then sets the deadline
+// and tags from the template preset, if any. If those fail, the message
+// carries both the created goal and the error.
+func createGoalCmd(ctx context.Context, client Client, slug, title, goalType, gunits, goaldate, goalval, rate, runits string, preset GoalTemplate) tea.Cmd {
+	return func() tea.Msg {
+		goal, err := client.CreateGoal(ctx, slug, title, goalType, gunits, goaldate, goalval, rate, runits)
+		if err != nil {
+			return goalCreatedMsg{goal: goal, err: err}
+		}
+		return goalCreatedMsg{goal: goal, err: applyGoalTemplateSettings(ctx, client, goal.Slug, preset)}
+	}
+}
+
+// createChargeCmd creates a charge for the user, or with dryrun only checks
+// what it would be
+func createChargeCmd(ctx context.Context, client Client, amount float64, note string, dryrun bool) tea.Cmd {
+	return func() tea.Msg {
+		charge, err := client.CreateCharge(ctx, amount, note, dryrun)
+		return chargeCreatedMsg{charge: charge, dryrun: dryrun, err: err}
 	}
 }
 
@@ -103,3 +181,21 @@ func navigationTimeoutCmd(duration time.Duration) tea.Cmd {
 		return navigationTimeoutMsg{}
 	})
 }
+
+// whatsNewCmd checks whether this is the first launch since an upgrade and, if
+// so, fetches the release notes to show. It runs off the UI goroutine because
+// the fetch may hit GitHub.
+func whatsNewCmd() tea.Cmd {
+	return func() tea.Msg {
+		return whatsNewMsg{notes: pendingWhatsNew()}
+	}
+}
+
+// recordRunVersionCmd records the running version as seen once its release
+// notes are on screen, so they're shown once per upgrade.
+func recordRunVersionCmd() tea.Cmd {
+	return func() tea.Msg {
+		recordRunVersion(version)
+		return nil
+	}
+}
@@ -66,7 +66,7 @@ func TestSubmitDatapointCmdPassesArgs(t *testing.T) {
 		},
 	}
 
-	msg, ok := submitDatapointCmd(context.Background(), fake, "exercise", "1700000000", "1.5", "morning run")().(datapointSubmittedMsg)
+	msg, ok := submitDatapointCmd(context.Background(), fake, "exercise", "1700000000", "", "1.5", "morning run")().(datapointSubmittedMsg)
 	if !ok {
 		t.Fatalf("submitDatapointCmd produced %T, want datapointSubmittedMsg", msg)
 	}
@@ -88,7 +88,7 @@ func TestSubmitDatapointCmdError(t *testing.T) {
 		CreateDatapointFunc: func(_, _, _, _, _ string) (*Datapoint, error) { return nil, wantErr },
 	}
 
-	msg := submitDatapointCmd(context.Background(), fake, "any", "0", "1", "")().(datapointSubmittedMsg)
+	msg := submitDatapointCmd(context.Background(), fake, "any", "0", "", "1", "")().(datapointSubmittedMsg)
 	if !errors.Is(msg.err, wantErr) {
 		t.Errorf("submitDatapointCmd err = %v, want %v", msg.err, wantErr)
 	}
@@ -136,13 +136,13 @@ func TestLoadGoalDetailsCmdError(t *testing.T) {
 
 // createGoalCmd forwards every positional argument to client.CreateGoal and
 // wraps the result in goalCreatedMsg. Use a single happy-path test to verify
-// argument plumbing — the seven-arg signature is what matters here.
+// argument plumbing — the eight-arg signature is what matters here.
 
 func TestCreateGoalCmdPassesArgs(t *testing.T) {
 	wantGoal := &Goal{Slug: "newg"}
-	var gotSlug, gotTitle, gotType, gotGunits, gotGoaldate, gotGoalval, gotRate string
+	var gotSlug, gotTitle, gotType, gotGunits, gotGoaldate, gotGoalval, gotRate, gotRunits string
 	fake := &FakeClient{
-		CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate string) (*Goal, error) {
+		CreateGoalFunc: func(slug, title, goalType, gunits, goaldate, goalval, rate, runits string) (*Goal, error) {
 			gotSlug = slug
 			gotTitle = title
 			gotType = goalType
@@ -150,11 +150,12 @@ func TestCreateGoalCmdPassesArgs(t *testing.T) {
 			gotGoaldate = goaldate
 			gotGoalval = goalval
 			gotRate = rate
+			gotRunits = runits
 			return wantGoal, nil
 		},
 	}
 
-	msg := createGoalCmd(context.Background(), fake, "newg", "New Goal", "hustler", "pages", "20260101", "null", "5")().(goalCreatedMsg)
+	msg := createGoalCmd(context.Background(), fake, "newg", "New Goal", "hustler", "pages", "20260101", "null", "5", "d", GoalTemplate{})().(goalCreatedMsg)
 	if msg.goal != wantGoal {
 		t.Errorf("createGoalCmd goal = %v, want %v", msg.goal, wantGoal)
 	}
@@ -162,19 +163,19 @@ func TestCreateGoalCmdPassesArgs(t *testing.T) {
 		t.Errorf("createGoalCmd err = %v, want nil", msg.err)
 	}
 	if gotSlug != "newg" || gotTitle != "New Goal" || gotType != "hustler" || gotGunits != "pages" ||
-		gotGoaldate != "20260101" || gotGoalval != "null" || gotRate != "5" {
-		t.Errorf("createGoalCmd passed (%q, %q, %q, %q, %q, %q, %q), want (newg, New Goal, hustler, pages, 20260101, null, 5)",
-			gotSlug, gotTitle, gotType, gotGunits, gotGoaldate, gotGoalval, gotRate)
+		gotGoaldate != "20260101" || gotGoalval != "null" || gotRate != "5" || gotRunits != "d" {
+		t.Errorf("createGoalCmd passed (%q, %q, %q, %q, %q, %q, %q, %q), want (newg, New Goal, hustler, pages, 20260101, null, 5, d)",
+			gotSlug, gotTitle, gotType, gotGunits, gotGoaldate, gotGoalval, gotRate, gotRunits)
 	}
 }
 
 func TestCreateGoalCmdError(t *testing.T) {
 	wantErr := errors.New("slug already exists")
 	fake := &FakeClient{
-		CreateGoalFunc: func(_, _, _, _, _, _, _ string) (*Goal, error) { return nil, wantErr },
+		CreateGoalFunc: func(_, _, _, _, _, _, _, _ string) (*Goal, error) { return nil, wantErr },
 	}
 
-	msg := createGoalCmd(context.Background(), fake, "dup", "", "", "", "", "", "")().(goalCreatedMsg)
+	msg := createGoalCmd(context.Background(), fake, "dup", "", "", "", "", "", "", "", GoalTemplate{})().(goalCreatedMsg)
 	if !errors.Is(msg.err, wantErr) {
 		t.Errorf("createGoalCmd err = %v, want %v", msg.err, wantErr)
 	}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"slices"
 	"time"
 )
 
@@ -16,6 +17,9 @@ const (
 	modeGoalDetail                 // a single goal's detail popup, over the grid
 	modeDatapointInput             // datapoint entry form, reachable only from modeGoalDetail
 	modeCreateGoal                 // new-goal form, reachable only from modeBrowse (no active search)
+	modeWhatsNew                   // post-upgrade release notes, reachable only from modeBrowse
+	modeCharge                     // charge-yourself form, reachable only from modeBrowse (no active search)
+	modePalette                    // command palette, reachable only from modeBrowse (no active search)
 )
 
 // appModel is the main application model (previously just "model")
@@ -31,10 +35,12 @@ type appModel struct {
 	height             int             // terminal height
 	scrollRow          int             // current scroll position (in rows)
 	refreshActive      bool            // whether auto-refresh is active
+	authExpired        bool            // an API call returned 401; the app shows the re-login prompt instead of the grid
 	mode               mode            // current foreground screen (see transition methods)
 	modalGoal          *Goal           // the goal shown in the detail modal; non-nil iff mode is modeGoalDetail/modeDatapointInput
 	hasNavigated       bool            // whether user has used arrow keys
 	lastNavigationTime time.Time       // last time user navigated with arrow keys
+	blurredAt          time.Time       // when the terminal lost focus; zero while focused
 
 	// Datapoint entry form (shown inside the goal detail modal)
 	datapoint datapointForm // date/value/comment fields + submitting flag
@@ -44,10 +50,97 @@ type appModel struct {
 	searchActive bool   // whether the search/filter layer is active
 	searchQuery  string // current search query
 
+	// Saved filter (from --filter or the f key) applied beneath search
+	filterName string          // name of the active saved filter; "" for none
+	filterFn   func(Goal) bool // compiled filterName; nil keeps every goal
+
+	// Type-ahead jump moves the cursor to the first matching goal without
+	// filtering the grid; like search, it only runs over Browse.
+	jumpActive bool   // whether a ' jump is being typed
+	jumpQuery  string // characters typed since '
+
 	// Goal creation form
 	createGoal createGoalForm // slug/title/type/... fields + creating flag
+
+	// Charge-yourself form
+	charge chargeForm // amount/note fields + the dry run awaiting confirmation
+
+	// Command palette
+	palette paletteState // query + selection; zero unless modePalette is active
+
+	// Post-upgrade release notes
+	whatsNew string // summary shown in modeWhatsNew; non-empty iff that mode is active
+
+	// The details pane beside the grid on a wide terminal (see layout.go)
+	paneGoal    *Goal  // the last goal fetched with its datapoints for the pane
+	paneSlug    string // the goal the pane last fetched, so a failed fetch isn't retried in a loop
+	paneLoading bool   // a pane fetch is in flight
+
+	// Tabs. mode and everything above belong to the Goals tab; the other
+	// tabs keep their own state here.
+	tab           tab         // the tab on screen
+	review        reviewModel // the Review tab, once reviewStarted
+	reviewStarted bool        // the Review tab has been opened
+
+	activity        activityModel // the Activity tab, once activityStarted
+	activityStarted bool          // the Activity tab has been opened
+
+	reports        reportsModel // the Reports tab, once reportsStarted
+	reportsStarted bool         // the Reports tab has been opened
+
+	// Refreshes that land while a modal or form is open are held here and
+	// applied on the way back to Browse, so goals never reorder under the
+	// modal's cursor mid-edit.
+	pendingGoals []Goal // latest deferred goal list; nil when nothing is pending
+
+	// Goals whose urgency colour or delta changed in the last refresh, so
+	// the grid can flash them for changeHighlightDuration.
+	changedGoals map[string]bool // by slug
+	changedAt    time.Time       // when changedGoals was recorded
+
+	// Day rollover (see rollover.go): the goals due as of the last load, and
+	// when the day next rolls over for one of them.
+	dueToday   map[string]bool // by slug; nil until the first load
+	newlyDue   map[string]bool // goals that became due since, until opened
+	rolloverAt time.Time
+
+	// cellTexts is the hooks script's cell_text for each goal, by slug,
+	// computed when the goals load rather than on every redraw.
+	cellTexts map[string]string
+
+	// Undo (u or U) reverses the latest datapoint added or deleted through buzz
+	// since the TUI started, as the journal recorded it.
+	startedAt time.Time       // when the TUI started; older changes aren't undone
+	undone    map[string]bool // ids of the datapoints already undone
+	undoBusy  bool            // an undo is in flight
+
+	// A toast is a short confirmation shown under the grid for toastDuration.
+	toast   string
+	toastAt time.Time
+}
+
+// toastDuration is how long a toast stays on screen. Like the change
+// highlight, it's cleared by the refresh-flag poller's redraw.
+const toastDuration = 4 * time.Second
+
+// showToast puts msg on screen for toastDuration.
+func (m *appModel) showToast(msg string) {
+	m.toast = msg
+	m.toastAt = time.Now()
+}
+
+// activeToast returns the toast to show, or "" once it has expired.
+func (m *appModel) activeToast() string {
+	if time.Since(m.toastAt) >= toastDuration {
+		return ""
+	}
+	return m.toast
 }
 
+// changeHighlightDuration is how long refreshed cells stay highlighted. The
+// 1s refresh-flag poller triggers the redraw that clears them.
+const changeHighlightDuration = 3 * time.Second
+
 // inGoalModal reports whether a goal-detail modal is on screen (whether or not
 // the nested datapoint-input form is focused).
 func (m *appModel) inGoalModal() bool {
@@ -68,6 +161,7 @@ func (m *appModel) openGoalDetail(g *Goal) {
 	}
 	m.mode = modeGoalDetail
 	m.modalGoal = g
+	delete(m.newlyDue, g.Slug)
 }
 
 // startDatapointInput focuses the datapoint-entry form nested in the goal-detail
@@ -94,8 +188,13 @@ func (m *appModel) exitDatapointInput() {
 // closeModal closes the goal-detail modal and returns to Browse, leaving any
 // active search in place.
 func (m *appModel) closeModal() {
+	slug := ""
+	if m.modalGoal != nil {
+		slug = m.modalGoal.Slug
+	}
 	m.mode = modeBrowse
 	m.modalGoal = nil
+	m.applyPendingGoals(slug)
 }
 
 // openCreateGoal opens the new-goal form with fresh fields. It is a no-op
@@ -109,10 +208,159 @@ func (m *appModel) openCreateGoal() {
 	m.createGoal = newCreateGoalForm()
 }
 
+// startFirstDatapoint opens a just-created goal's detail modal with the
+// datapoint form focused on the value, so its first datapoint is a keystroke
+// away.
+func (m *appModel) startFirstDatapoint(g *Goal) {
+	m.openGoalDetail(g)
+	form := newDatapointForm("1", g.Gunits)
+	form.focus = dpValue
+	m.startDatapointInput(form)
+}
+
 // closeCreateGoal closes the new-goal form and returns to Browse.
 func (m *appModel) closeCreateGoal() {
 	m.mode = modeBrowse
 	m.createGoal.err = ""
+	m.applyPendingGoals("")
+}
+
+// openCharge opens the charge-yourself form with empty fields. Like
+// openCreateGoal, it is a no-op unless in Browse mode with no active search.
+func (m *appModel) openCharge() {
+	if m.mode != modeBrowse || m.searchActive {
+		return
+	}
+	m.mode = modeCharge
+	m.charge = newChargeForm()
+}
+
+// closeCharge closes the charge form and returns to Browse.
+func (m *appModel) closeCharge() {
+	if m.mode != modeCharge {
+		return
+	}
+	m.mode = modeBrowse
+	m.charge = chargeForm{}
+	m.applyPendingGoals("")
+}
+
+// openPalette opens the command palette. It is a no-op unless in Browse mode
+// with no active search.
+func (m *appModel) openPalette() {
+	if m.mode != modeBrowse || m.searchActive {
+		return
+	}
+	m.mode = modePalette
+	m.palette = paletteState{}
+}
+
+// closePalette closes the command palette and returns to Browse.
+func (m *appModel) closePalette() {
+	if m.mode != modePalette {
+		return
+	}
+	m.mode = modeBrowse
+	m.palette = paletteState{}
+	m.applyPendingGoals("")
+}
+
+// openWhatsNew shows the post-upgrade release notes. It is a no-op unless in
+// Browse mode, so notes arriving late never cover a form the user is typing in.
+func (m *appModel) openWhatsNew(notes string) {
+	if m.mode != modeBrowse || notes == "" {
+		return
+	}
+	m.mode = modeWhatsNew
+	m.whatsNew = notes
+}
+
+// closeWhatsNew dismisses the release notes and returns to Browse.
+func (m *appModel) closeWhatsNew() {
+	if m.mode != modeWhatsNew {
+		return
+	}
+	m.mode = modeBrowse
+	m.whatsNew = ""
+	m.applyPendingGoals("")
+}
+
+// receiveGoals installs a freshly loaded goal list. Outside Browse it is
+// deferred to pendingGoals instead (a newer load replaces an older pending
+// one) and applied by the close transitions above.
+func (m *appModel) receiveGoals(goals []Goal) {
+	if m.mode != modeBrowse {
+		m.pendingGoals = goals
+		return
+	}
+	m.setGoals(goals)
+	m.pendingGoals = nil
+}
+
+// applyPendingGoals installs any deferred goal list. The cursor indexes the
+// display goals (after search and the saved filter), so it follows slug (the
+// goal the modal was showing) there when it is still shown, and is otherwise
+// clamped so it never points past the end of the new list.
+func (m *appModel) applyPendingGoals(slug string) {
+	if m.pendingGoals == nil {
+		return
+	}
+	m.setGoals(m.pendingGoals)
+	m.pendingGoals = nil
+	displayGoals := m.getDisplayGoals()
+	if slug != "" {
+		for i, g := range displayGoals {
+			if g.Slug == slug {
+				m.cursor = i
+				return
+			}
+		}
+	}
+	if m.cursor >= len(displayGoals) {
+		m.cursor = max(len(displayGoals)-1, 0)
+	}
+}
+
+// setGoals replaces the goal list, recording which goals changed visibly so
+// the grid can highlight them.
+func (m *appModel) setGoals(goals []Goal) {
+	if changed := changedGoalSlugs(m.goals, goals); len(changed) > 0 {
+		m.changedGoals = changed
+		m.changedAt = time.Now()
+	}
+	m.goals = goals
+	m.cellTexts = activeHooks.CellTexts(goals)
+	m.trackDue(time.Now())
+}
+
+// highlightedGoals returns the goals to flash in the grid, or nil once the
+// highlight has expired.
+func (m *appModel) highlightedGoals() map[string]bool {
+	if time.Since(m.changedAt) >= changeHighlightDuration {
+		return nil
+	}
+	return m.changedGoals
+}
+
+// changedGoalSlugs reports the goals in next whose grid cell would look
+// different from prev: a new urgency colour, a new delta, or a goal that
+// wasn't there before. An empty prev (the initial load) changes nothing.
+func changedGoalSlugs(prev, next []Goal) map[string]bool {
+	if len(prev) == 0 {
+		return nil
+	}
+	old := make(map[string]Goal, len(prev))
+	for _, g := range prev {
+		old[g.Slug] = g
+	}
+	changed := make(map[string]bool)
+	for _, g := range next {
+		before, ok := old[g.Slug]
+		if !ok || UrgencyFor(before.Safebuf) != UrgencyFor(g.Safebuf) || before.Baremin != g.Baremin {
+			changed[g.Slug] = true
+		}
+	}
+	return changed
 }
 
 // enterSearch activates the search filter layer with an empty query. It is a
@@ -126,6 +374,34 @@ func (m *appModel) enterSearch() {
 	m.searchQuery = ""
 }
 
+// enterJump starts a type-ahead jump. It is a no-op unless in Browse mode with
+// no active search or jump (while searching, ' is just part of the query).
+func (m *appModel) enterJump() {
+	if m.mode != modeBrowse || m.searchActive || m.jumpActive {
+		return
+	}
+	m.jumpActive = true
+	m.jumpQuery = ""
+}
+
+// exitJump ends a type-ahead jump, leaving the cursor where it landed.
+func (m *appModel) exitJump() {
+	m.jumpActive = false
+	m.jumpQuery = ""
+}
+
+// jumpToMatch moves the cursor to the first display goal whose slug fuzzy-
+// matches the jump query, reporting whether one was found.
+func (m *appModel) jumpToMatch() bool {
+	for i, goal := range m.getDisplayGoals() {
+		if fuzzyMatch(m.jumpQuery, goal.Slug) {
+			m.cursor = i
+			return true
+		}
+	}
+	return false
+}
+
 // exitSearch clears the search filter layer and resets grid navigation.
 func (m *appModel) exitSearch() {
 	m.searchActive = false
@@ -147,16 +423,21 @@ type model struct {
 	width                int             // terminal width
 	height               int             // terminal height
 	lastRefreshTimestamp int64           // last processed refresh flag timestamp
+	suspended            bool            // ctrl+z has backgrounded the process; refreshes are skipped until ResumeMsg
 }
 
 func initialAppModel(config *Config, ctx context.Context) appModel {
 	return appModel{
 		goals:         []Goal{},
 		config:        config,
-		client:        NewHTTPClient(config),
+		client:        newCachingClient(journaling(recordingSlugs(NewHTTPClient(config), config.Username))),
 		ctx:           ctx,
 		loading:       true,
 		refreshActive: true,
+		filterName:    goalFilterName,
+		filterFn:      goalFilter,
+		startedAt:     time.Now(),
+		undone:        map[string]bool{},
 		// mode defaults to modeBrowse and searchActive to false (zero values).
 	}
 }
@@ -166,11 +447,16 @@ func initialAppModel(config *Config, ctx context.Context) appModel {
 // exitSearch), so an empty query is the single "show everything" condition.
 func (m *appModel) filterGoals() []Goal {
 	if m.searchQuery == "" {
-		return m.goals
+		return keepGoals(m.goals, m.filterFn)
 	}
 
+	// Apply the saved filter and the search in one pass, so the goals aren't
+	// copied twice on every keystroke.
 	var filtered []Goal
 	for _, goal := range m.goals {
+		if m.filterFn != nil && !m.filterFn(goal) {
+			continue
+		}
 		// Match against slug or title
 		if fuzzyMatch(m.searchQuery, goal.Slug) || fuzzyMatch(m.searchQuery, goal.Title) {
 			filtered = append(filtered, goal)
@@ -179,6 +465,51 @@ func (m *appModel) filterGoals() []Goal {
 	return filtered
 }
 
+// cycleFilter switches to the next saved filter in name order, wrapping
+// around to no filter after the last one. Filters whose expression doesn't
+// compile are skipped.
+func (m *appModel) cycleFilter() {
+	if m.config == nil {
+		return
+	}
+	names := filterNames(m.config)
+	start := 0
+	if m.filterName != "" {
+		start = slices.Index(names, m.filterName) + 1
+	}
+	m.filterName, m.filterFn = "", nil
+	for _, name := range names[start:] {
+		if fn, err := namedFilter(m.config, name); err == nil {
+			m.filterName, m.filterFn = name, fn
+			break
+		}
+	}
+	m.cursor = 0
+	m.scrollRow = 0
+	m.hasNavigated = false
+}
+
+// cycleCreateTemplate fills the create form from the next saved goal template
+// in name order, wrapping around to no template after the last one.
+// Templates whose deadline doesn't parse are skipped.
+func (m *appModel) cycleCreateTemplate() {
+	if m.config == nil {
+		return
+	}
+	names := templateNames(m.config)
+	start := 0
+	if m.createGoal.template != "" {
+		start = slices.Index(names, m.createGoal.template) + 1
+	}
+	for _, name := range names[start:] {
+		if tmpl, err := lookupTemplate(m.config, name); err == nil {
+			m.createGoal.applyTemplate(name, tmpl)
+			return
+		}
+	}
+	m.createGoal.applyTemplate("", GoalTemplate{})
+}
+
 // getDisplayGoals returns the goals to display (either filtered or all)
 func (m *appModel) getDisplayGoals() []Goal {
 	return m.filterGoals()
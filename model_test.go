@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"time"
 )
 
 // TestFilterGoals tests the filterGoals method
@@ -263,7 +265,7 @@ func TestModeTransitions(t *testing.T) {
 	t.Run("startDatapointInput only works from goal detail", func(t *testing.T) {
 		// From Browse it is a no-op.
 		m := appModel{}
-		m.startDatapointInput(newDatapointForm("1"))
+		m.startDatapointInput(newDatapointForm("1", ""))
 		if m.mode != modeBrowse {
 			t.Errorf("startDatapointInput from Browse should be a no-op, mode = %d", m.mode)
 		}
@@ -271,14 +273,14 @@ func TestModeTransitions(t *testing.T) {
 		// With a goal-detail mode but no attached goal it is also a no-op (the
 		// submit path dereferences modalGoal.Slug).
 		orphan := appModel{mode: modeGoalDetail}
-		orphan.startDatapointInput(newDatapointForm("1"))
+		orphan.startDatapointInput(newDatapointForm("1", ""))
 		if orphan.mode != modeGoalDetail {
 			t.Errorf("startDatapointInput with nil modalGoal should be a no-op, mode = %d", orphan.mode)
 		}
 
 		// From goal detail it enters input mode.
 		m.openGoalDetail(&Goal{Slug: "exercise"})
-		m.startDatapointInput(newDatapointForm("2.5"))
+		m.startDatapointInput(newDatapointForm("2.5", ""))
 		if m.mode != modeDatapointInput {
 			t.Errorf("mode = %d, want modeDatapointInput", m.mode)
 		}
@@ -293,7 +295,7 @@ func TestModeTransitions(t *testing.T) {
 	t.Run("exitDatapointInput returns to goal detail", func(t *testing.T) {
 		m := appModel{}
 		m.openGoalDetail(&Goal{Slug: "exercise"})
-		m.startDatapointInput(newDatapointForm("1"))
+		m.startDatapointInput(newDatapointForm("1", ""))
 		m.exitDatapointInput()
 		if m.mode != modeGoalDetail {
 			t.Errorf("mode = %d, want modeGoalDetail after exitDatapointInput", m.mode)
@@ -371,6 +373,74 @@ func TestModeTransitions(t *testing.T) {
 		}
 	})
 
+	t.Run("openWhatsNew only opens from Browse", func(t *testing.T) {
+		m := appModel{mode: modeCreateGoal}
+		m.openWhatsNew("notes")
+		if m.mode != modeCreateGoal {
+			t.Errorf("openWhatsNew outside Browse should be a no-op, mode = %d", m.mode)
+		}
+
+		b := appModel{}
+		b.openWhatsNew("")
+		if b.mode != modeBrowse {
+			t.Errorf("openWhatsNew with empty notes should be a no-op, mode = %d", b.mode)
+		}
+		b.openWhatsNew("- faster grid")
+		if b.mode != modeWhatsNew || b.whatsNew != "- faster grid" {
+			t.Errorf("mode = %d whatsNew = %q, want modeWhatsNew with notes", b.mode, b.whatsNew)
+		}
+		b.closeWhatsNew()
+		if b.mode != modeBrowse || b.whatsNew != "" {
+			t.Error("closeWhatsNew should return to Browse and clear the notes")
+		}
+	})
+
+	t.Run("refreshes are deferred while a modal is open and re-resolved by slug", func(t *testing.T) {
+		m := appModel{goals: []Goal{{Slug: "a"}, {Slug: "b"}, {Slug: "c"}}, cursor: 1}
+		m.openGoalDetail(&m.goals[1])
+		m.receiveGoals([]Goal{{Slug: "b"}, {Slug: "c"}, {Slug: "a"}})
+		if m.goals[1].Slug != "b" {
+			t.Fatal("goals must not change while the goal modal is open")
+		}
+		m.closeModal()
+		if m.pendingGoals != nil || m.goals[0].Slug != "b" {
+			t.Fatalf("closeModal should apply the pending goals, got %v", m.goals)
+		}
+		if m.cursor != 0 {
+			t.Errorf("cursor = %d, want 0 (the modal goal's new index)", m.cursor)
+		}
+
+		c := appModel{goals: []Goal{{Slug: "a"}, {Slug: "b"}, {Slug: "c"}}, cursor: 2}
+		c.openCreateGoal()
+		c.receiveGoals([]Goal{{Slug: "a"}})
+		c.closeCreateGoal()
+		if len(c.goals) != 1 || c.cursor != 0 {
+			t.Errorf("closeCreateGoal should apply pending goals and clamp the cursor, got %d goals cursor %d", len(c.goals), c.cursor)
+		}
+	})
+
+	t.Run("deferred refreshes re-resolve the modal goal among the displayed goals", func(t *testing.T) {
+		m := appModel{goals: []Goal{{Slug: "read-a"}, {Slug: "run"}, {Slug: "read-b"}, {Slug: "write"}}}
+		m.enterSearch()
+		m.searchQuery = "read"
+		m.cursor = 1 // read-b, the second goal shown
+		m.openGoalDetail(&m.getDisplayGoals()[m.cursor])
+		m.receiveGoals([]Goal{{Slug: "write"}, {Slug: "read-b"}, {Slug: "run"}, {Slug: "read-a"}})
+		m.closeModal()
+		if got := m.getDisplayGoals()[m.cursor].Slug; got != "read-b" {
+			t.Errorf("cursor %d points at %s, want read-b", m.cursor, got)
+		}
+
+		// With the goal gone, the cursor is clamped to the goals shown.
+		m.cursor = 1
+		m.openGoalDetail(&m.getDisplayGoals()[m.cursor])
+		m.receiveGoals([]Goal{{Slug: "read-a"}, {Slug: "run"}, {Slug: "write"}})
+		m.closeModal()
+		if m.cursor != 0 {
+			t.Errorf("cursor = %d, want 0 (the only goal shown)", m.cursor)
+		}
+	})
+
 	t.Run("enterSearch and exitSearch", func(t *testing.T) {
 		m := appModel{cursor: 5, scrollRow: 3, hasNavigated: true}
 		m.enterSearch()
@@ -396,3 +466,119 @@ func getSlugs(goals []Goal) []string {
 	}
 	return slugs
 }
+
+func TestChangedGoalSlugs(t *testing.T) {
+	prev := []Goal{
+		{Slug: "same", Safebuf: 5, Baremin: "+1"},
+		{Slug: "recolor", Safebuf: 5, Baremin: "+1"},
+		{Slug: "delta", Safebuf: 5, Baremin: "+1"},
+	}
+	next := []Goal{
+		{Slug: "same", Safebuf: 6, Baremin: "+1"}, // same urgency band
+		{Slug: "recolor", Safebuf: 0, Baremin: "+1"},
+		{Slug: "delta", Safebuf: 5, Baremin: "+2"},
+		{Slug: "new", Safebuf: 5, Baremin: "+1"},
+	}
+	got := changedGoalSlugs(prev, next)
+	want := map[string]bool{"recolor": true, "delta": true, "new": true}
+	if len(got) != len(want) {
+		t.Fatalf("changedGoalSlugs = %v, want %v", got, want)
+	}
+	for slug := range want {
+		if !got[slug] {
+			t.Errorf("expected %q to be marked changed", slug)
+		}
+	}
+
+	if changed := changedGoalSlugs(nil, next); changed != nil {
+		t.Errorf("initial load should highlight nothing, got %v", changed)
+	}
+
+	m := appModel{goals: prev}
+	m.setGoals(next)
+	if len(m.highlightedGoals()) != 3 {
+		t.Errorf("highlightedGoals should return fresh changes, got %v", m.highlightedGoals())
+	}
+	m.changedAt = time.Now().Add(-changeHighlightDuration)
+	if m.highlightedGoals() != nil {
+		t.Error("highlightedGoals should expire after changeHighlightDuration")
+	}
+}
+
+func TestCycleFilter(t *testing.T) {
+	config := &Config{Filters: map[string]string{
+		"work":   "tag:work",
+		"bad":    "not a filter",
+		"urgent": "safebuf<2",
+	}}
+	m := appModel{
+		config: config,
+		goals:  []Goal{{Slug: "a", Tags: []string{"work"}, Safebuf: 5}, {Slug: "b", Safebuf: 1}},
+		cursor: 1,
+	}
+
+	// "bad" sorts first but doesn't compile, so it is skipped.
+	var seen []string
+	for range 3 {
+		m.cycleFilter()
+		seen = append(seen, m.filterName)
+	}
+	if strings.Join(seen, ",") != "urgent,work," {
+		t.Errorf("cycle order = %v, want [urgent work \"\"]", seen)
+	}
+
+	m.cycleFilter() // urgent
+	if got := m.getDisplayGoals(); len(got) != 1 || got[0].Slug != "b" {
+		t.Errorf("urgent filter should keep only b, got %v", got)
+	}
+	if m.cursor != 0 {
+		t.Errorf("switching filters should reset the cursor, got %d", m.cursor)
+	}
+
+	m.searchQuery = "a"
+	if got := m.getDisplayGoals(); len(got) != 0 {
+		t.Errorf("search should apply on top of the saved filter, got %v", got)
+	}
+}
+
+func TestCycleCreateTemplate(t *testing.T) {
+	rate := 5.0
+	config := &Config{Templates: map[string]GoalTemplate{
+		"habit":  {Type: "Do More", Units: "sessions", Rate: &rate, Deadline: "23:00"},
+		"broken": {Deadline: "6:30 AM"},
+		"rest":   {Type: "drinker"},
+	}}
+	m := appModel{config: config, mode: modeCreateGoal, createGoal: newCreateGoalForm()}
+	m.createGoal.fields[cgSlug].value = "meditate"
+
+	// "broken" sorts first but its deadline isn't allowed, so it is skipped.
+	var seen []string
+	for range 3 {
+		m.cycleCreateTemplate()
+		seen = append(seen, m.createGoal.template)
+	}
+	if strings.Join(seen, ",") != "habit,rest," {
+		t.Errorf("cycle order = %v, want [habit rest \"\"]", seen)
+	}
+
+	m.cycleCreateTemplate() // habit
+	cg := m.createGoal
+	if cg.slug() != "meditate" || cg.goalType() != "hustler" || cg.gunits() != "sessions" {
+		t.Errorf("habit template gave slug %q, type %q, units %q", cg.slug(), cg.goalType(), cg.gunits())
+	}
+	if cg.goaldate() != "" || cg.goalval() != "" || cg.rate() != "5" {
+		t.Errorf("habit template gave date %q, value %q, rate %q", cg.goaldate(), cg.goalval(), cg.rate())
+	}
+
+	m.cycleCreateTemplate() // rest sets no target, so the defaults come back
+	if cg := m.createGoal; cg.goalType() != "drinker" || cg.gunits() != "units" || cg.goalval() != "0" || cg.rate() != "1" {
+		t.Errorf("rest template gave type %q, units %q, value %q, rate %q", cg.goalType(), cg.gunits(), cg.goalval(), cg.rate())
+	}
+}
+
+func BenchmarkFilterGoals(b *testing.B) {
+	m := appModel{ctx: context.Background(), goals: syntheticGoals(benchGoals), searchQuery: "gl9"}
+	for b.Loop() {
+		m.filterGoals()
+	}
+}
@@ -81,9 +81,12 @@ func displayNextGoal() error {
 	// Format the output: "goalslug baremin timeframe"
 	timeframe := FormatGoalDueDateAt(nextGoal, now)
 
-	// Machine-readable formats emit just the goal (json = the raw object, csv =
-	// one row), skipping the update banner so the output stays parseable.
+	// Machine-readable formats emit just the goal (json = the raw object, jsonl
+	// = the same on one line, csv = one row), skipping the update banner so the
+	// output stays parseable.
 	switch outputFormat {
+	case "jsonl":
+		return writeJSONL(os.Stdout, []Goal{nextGoal})
 	case "json":
 		b, err := json.MarshalIndent(nextGoal, "", "  ")
 		if err != nil {
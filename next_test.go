@@ -28,7 +28,7 @@ func TestClearScreen(t *testing.T) {
 
 // TestDisplayNextGoalNoConfig tests displayNextGoal when config doesn't exist
 func TestDisplayNextGoalNoConfig(t *testing.T) {
-	t.Setenv("HOME", t.TempDir())
+	setHome(t, t.TempDir())
 	if err := displayNextGoal(); err == nil {
 		t.Fatalf("expected error when no config present")
 	}
@@ -41,7 +41,7 @@ func TestDisplayNextGoalWithTimestamp(t *testing.T) {
 			t.Errorf("displayNextGoalWithTimestamp() panicked: %v", r)
 		}
 	}()
-	t.Setenv("HOME", t.TempDir())
+	setHome(t, t.TempDir())
 	displayNextGoalWithTimestamp()
 }
 
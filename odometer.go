@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+)
+
+// odometerResetComment is the comment on the datapoint that records a reset.
+const odometerResetComment = "Odometer reset"
+
+// handleOdometerResetCommand records that an odometer goal's odometer was
+// reset to zero.
+func handleOdometerResetCommand() {
+	client, ok := loadClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+	code := runOdometerResetCommand(os.Args[2:], client, os.Stdout, os.Stderr)
+	if code == 0 {
+		fmt.Print(getUpdateMessage())
+	}
+	os.Exit(code)
+}
+
+// runOdometerResetCommand is the testable core of `buzz odometer-reset`. It
+// expects a single <goalslug> argument naming an odometer (biker) goal and
+// adds the zero datapoint Beeminder treats as an odometer reset: readings
+// after it are counted on top of the total before it. It returns the process
+// exit code.
+func runOdometerResetCommand(args []string, client Client, stdout, stderr io.Writer) int {
+	if len(args) != 1 {
+		if len(args) < 1 {
+			fmt.Fprintln(stderr, "Error: Missing required argument")
+		} else {
+			fmt.Fprintf(stderr, "Error: Too many arguments: %v\n", args[1:])
+		}
+		fmt.Fprintln(stderr, "Usage: buzz odometer-reset <goalslug>")
+		return 1
+	}
+	goalSlug := args[0]
+
+	goal, err := client.FetchGoal(context.Background(), goalSlug)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %s\n", redactError(err))
+		return 1
+	}
+	if goal.GoalType != "biker" {
+		fmt.Fprintf(stderr, "Error: %s is a %s goal, not an odometer goal\n", goalSlug, goal.GoalType)
+		return 1
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	if _, err := client.CreateDatapoint(context.Background(), goalSlug, timestamp, "0", odometerResetComment, ""); err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to record odometer reset: %s\n", redactError(err))
+		return 1
+	}
+	fmt.Fprintf(stdout, "Recorded an odometer reset for %s. Enter readings from the reset odometer from now on.\n", goalSlug)
+
+	// Signal any running TUI instances to refresh so they pick up the new
+	// datapoint. Don't fail the command if flag creation fails.
+	if err := createRefreshFlag(); err != nil {
+		fmt.Fprintf(stderr, "Warning: Could not create refresh flag: %s\n", redactError(err))
+	}
+	return 0
+}
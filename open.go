@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+const openUsage = "Usage: buzz open [--qr] [goalslug]"
+
+// openRequest holds the parsed `buzz open` arguments.
+type openRequest struct {
+	goalSlug string // "" for the dashboard
+	qr       bool
+}
+
+// handleOpenCommand opens the user's Beeminder dashboard, or a goal's page,
+// in the browser: a shorter `buzz view <goalslug> --web`.
+func handleOpenCommand() {
+	req, code, done := parseOpenArgs(os.Args[2:], os.Stdout, os.Stderr)
+	if done {
+		os.Exit(code)
+	}
+
+	config, _, ok := loadConfigAndClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+
+	if err := openBrowser(config, req.goalSlug, req.qr, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Failed to open browser: %s\n", redactError(err))
+		os.Exit(1)
+	}
+}
+
+// parseOpenArgs parses `buzz open` arguments, returning the request, a process
+// exit code, and done=true when the caller should stop (help shown, or a
+// usage error).
+func parseOpenArgs(args []string, stdout, stderr io.Writer) (openRequest, int, bool) {
+	openFlags := flag.NewFlagSet("open", flag.ContinueOnError)
+	// Silence the flag package's own output; we print our own usage.
+	openFlags.SetOutput(io.Discard)
+	qr := openFlags.Bool("qr", false, "Print the URL as a QR code instead")
+
+	// Re-parse after the slug, as view does, so --qr can follow it.
+	var positional []string
+	for remaining := args; ; {
+		if err := openFlags.Parse(remaining); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				fmt.Fprintln(stdout, openUsage)
+				return openRequest{}, 0, true
+			}
+			fmt.Fprintf(stderr, "Error parsing flags: %s\n", err)
+			fmt.Fprintln(stderr, openUsage)
+			return openRequest{}, 2, true
+		}
+		if openFlags.NArg() == 0 {
+			break
+		}
+		positional = append(positional, openFlags.Arg(0))
+		remaining = openFlags.Args()[1:]
+	}
+
+	if len(positional) > 1 {
+		fmt.Fprintf(stderr, "Error: Too many arguments: %v\n", positional[1:])
+		fmt.Fprintln(stderr, openUsage)
+		return openRequest{}, 1, true
+	}
+	req := openRequest{qr: *qr}
+	if len(positional) == 1 {
+		req.goalSlug = positional[0]
+	}
+	return req, 0, false
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseOpenArgs(t *testing.T) {
+	for _, tt := range []struct {
+		args     []string
+		want     openRequest
+		wantCode int
+		wantDone bool
+	}{
+		{nil, openRequest{}, 0, false},
+		{[]string{"reading"}, openRequest{goalSlug: "reading"}, 0, false},
+		{[]string{"reading", "--qr"}, openRequest{goalSlug: "reading", qr: true}, 0, false},
+		{[]string{"--qr"}, openRequest{qr: true}, 0, false},
+		{[]string{"reading", "writing"}, openRequest{}, 1, true},
+		{[]string{"--web"}, openRequest{}, 2, true},
+		{[]string{"--help"}, openRequest{}, 0, true},
+	} {
+		req, code, done := parseOpenArgs(tt.args, &bytes.Buffer{}, &bytes.Buffer{})
+		if req != tt.want || code != tt.wantCode || done != tt.wantDone {
+			t.Errorf("parseOpenArgs(%v) = %+v, %d, %v", tt.args, req, code, done)
+		}
+	}
+}
+
+func TestGoalPageURL(t *testing.T) {
+	config := &Config{Username: "alice"}
+	if got, want := goalPageURL(config, ""), "https://www.beeminder.com/alice"; got != want {
+		t.Errorf("dashboard URL = %q, want %q", got, want)
+	}
+	if got, want := goalPageURL(config, "reading"), "https://www.beeminder.com/alice/reading"; got != want {
+		t.Errorf("goal URL = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The command palette (:) lists the Browse screen's actions by name, for
+// finding one without remembering its key. Typing narrows the list, fuzzily
+// like search; Enter runs the selected action as if its key were pressed.
+
+// paletteAction is one action in the command palette.
+type paletteAction struct {
+	name string                           // what the palette shows and matches against
+	key  string                           // the key that runs it from Browse, shown beside the name
+	run  func(model) (tea.Model, tea.Cmd) // runs it, from Browse
+}
+
+// paletteActions lists the palette's actions, in the order it shows them.
+var paletteActions = []paletteAction{
+	{name: "Charge yourself", key: "$", run: func(m model) (tea.Model, tea.Cmd) {
+		m.appModel.openCharge()
+		return m, nil
+	}},
+	{name: "Create a goal", key: "n", run: handleCreateGoal},
+	{name: "Search goals", key: "/", run: handleEnterSearch},
+	{name: "Jump to a goal", key: "'", run: handleEnterJump},
+	{name: "Next saved filter", key: "f", run: handleCycleFilter},
+	{name: "Refresh goals", key: "r", run: handleRefresh},
+	{name: "Toggle auto-refresh", key: "t", run: handleToggleRefresh},
+	{name: "Undo the latest change", key: "u", run: handleUndo},
+	{name: "Quit", key: "q", run: func(m model) (tea.Model, tea.Cmd) { return m, tea.Quit }},
+}
+
+// paletteState is the palette's query and selection while modePalette is
+// active.
+type paletteState struct {
+	query  string // typed so far
+	cursor int    // index into matchingPaletteActions(query)
+}
+
+// matchingPaletteActions returns the actions whose names fuzzily match query.
+func matchingPaletteActions(query string) []paletteAction {
+	var matches []paletteAction
+	for _, a := range paletteActions {
+		if fuzzyMatch(query, a.name) {
+			matches = append(matches, a)
+		}
+	}
+	return matches
+}
+
+// handlePaletteKey handles keys in the command palette. Enter closes it and
+// runs the selected action; Escape closes it without running anything.
+func handlePaletteKey(m model, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := &m.appModel.palette
+	matches := matchingPaletteActions(p.query)
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		m.appModel.closePalette()
+		return m, nil
+	case "enter":
+		if len(matches) == 0 {
+			return m, nil
+		}
+		action := matches[p.cursor]
+		m.appModel.closePalette()
+		return action.run(m)
+	case "up", "ctrl+p":
+		if p.cursor > 0 {
+			p.cursor--
+		}
+		return m, nil
+	case "down", "ctrl+n":
+		if p.cursor < len(matches)-1 {
+			p.cursor++
+		}
+		return m, nil
+	case "backspace":
+		if p.query != "" {
+			runes := []rune(p.query)
+			p.query = string(runes[:len(runes)-1])
+			p.cursor = 0
+		}
+		return m, nil
+	}
+	if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+		p.query += string(msg.Runes)
+		p.cursor = 0
+	}
+	return m, nil
+}
+
+// RenderPaletteModal renders the command palette: the query, then the
+// matching actions with their keys, the selected one highlighted.
+func RenderPaletteModal(width, height int, query string, actions []paletteAction, cursor int) string {
+	modalStyle := CreateModalStyle()
+	modalWidth := modalWidthFor(width)
+
+	var list strings.Builder
+	for i, a := range actions {
+		line := fmt.Sprintf("%-24s %s", a.name, a.key)
+		if i == cursor {
+			line = lipgloss.NewStyle().Background(lipgloss.Color("4")).Render(line)
+		}
+		list.WriteString("\n" + line)
+	}
+	if len(actions) == 0 {
+		list.WriteString("\nNo matching commands")
+	}
+
+	content := fmt.Sprintf("Commands\n\n: %s_\n%s\n\n↑/↓: Select • Enter: Run • Esc: Cancel", query, list.String())
+	styledContent := modalStyle.Width(modalWidth).Render(content)
+	return placeModal(styledContent, width, height, height/4)
+}
@@ -0,0 +1,252 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"time"
+)
+
+const planUsage = `Usage: buzz plan [--blocks] [--ics] [--start <time>] [--block <duration>]
+  List today's bare minimums in deadline order. With --blocks, propose a
+  time-blocked schedule for them; --ics writes it as an iCalendar file.`
+
+// defaultPlanBlock is how long --blocks sets aside for a goal whose units
+// aren't a measure of time.
+const defaultPlanBlock = "30m"
+
+// planSlot is the granularity of a time-blocked plan: blocks start on it and
+// last a whole number of it.
+const planSlot = 5 * time.Minute
+
+// planRequest holds the parsed `buzz plan` flags.
+type planRequest struct {
+	blocks bool          // propose times, not just an order
+	ics    bool          // write the blocks as iCalendar
+	start  time.Duration // time of day to start the first block, after midnight (0 for now)
+	block  time.Duration // length of a block for goals not measured in time
+}
+
+// planBlock is a goal's slot in a time-blocked plan.
+type planBlock struct {
+	goal       Goal
+	start, end time.Time
+	late       bool // the block ends after the goal's deadline
+}
+
+// handlePlanCommand plans today's bare minimums.
+func handlePlanCommand() {
+	req, code, done := parsePlanArgs(os.Args[2:], os.Stdout, os.Stderr)
+	if done {
+		os.Exit(code)
+	}
+
+	client, ok := loadClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+	os.Exit(runPlanCommand(context.Background(), client, req, goalFilter, time.Now(), os.Stdout, os.Stderr))
+}
+
+// parsePlanArgs parses `buzz plan` arguments, returning the request, a process
+// exit code, and done=true when the caller should stop (help shown, or a
+// usage error).
+func parsePlanArgs(args []string, stdout, stderr io.Writer) (planRequest, int, bool) {
+	planFlags := flag.NewFlagSet("plan", flag.ContinueOnError)
+	// Silence the flag package's own output; we print our own usage.
+	planFlags.SetOutput(io.Discard)
+	blocks := planFlags.Bool("blocks", false, "Propose a time-blocked schedule")
+	ics := planFlags.Bool("ics", false, "Write the schedule as iCalendar")
+	start := planFlags.String("start", "", "Time to start the first block")
+	block := planFlags.String("block", defaultPlanBlock, "Block length for goals not measured in time")
+	if err := planFlags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stdout, planUsage)
+			return planRequest{}, 0, true
+		}
+		fmt.Fprintf(stderr, "Error parsing flags: %s\n", err)
+		fmt.Fprintln(stderr, planUsage)
+		return planRequest{}, 2, true
+	}
+	if planFlags.NArg() > 0 {
+		fmt.Fprintf(stderr, "Error: unexpected argument %q\n", planFlags.Arg(0))
+		fmt.Fprintln(stderr, planUsage)
+		return planRequest{}, 1, true
+	}
+
+	req := planRequest{blocks: *blocks || *ics, ics: *ics}
+	length, ok := ParseDuration(*block)
+	if !ok || length < planSlot {
+		fmt.Fprintf(stderr, "Error: invalid --block %q (want a duration of at least 5m, e.g. 30m or 1h)\n", *block)
+		return planRequest{}, 1, true
+	}
+	req.block = length
+	if *start != "" {
+		if req.start, ok = parseTimeOfDay(*start); !ok {
+			fmt.Fprintf(stderr, "Error: invalid --start %q (expected e.g. \"9:00 AM\" or \"09:00\")\n", *start)
+			return planRequest{}, 1, true
+		}
+	}
+	return req, 0, false
+}
+
+// parseTimeOfDay parses a clock time such as "9:00 AM" or "09:00" as the time
+// since midnight.
+func parseTimeOfDay(s string) (time.Duration, bool) {
+	for _, layout := range []string{"3:04 PM", "3:04PM", "15:04"} {
+		if t, err := time.Parse(layout, strings.ToUpper(strings.TrimSpace(s))); err == nil {
+			return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, true
+		}
+	}
+	return 0, false
+}
+
+// startOfPlan is when the first block can start: startAt (time since
+// midnight) today, but never before now, rounded up to the next planSlot.
+func startOfPlan(startAt time.Duration, now time.Time) time.Time {
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).Add(startAt)
+	if start.Before(now) {
+		start = now
+	}
+	rounded := start.Truncate(planSlot)
+	if rounded.Before(start) {
+		rounded = rounded.Add(planSlot)
+	}
+	return rounded
+}
+
+// plannedGoals returns the goals due today with work left to do, soonest
+// deadline first.
+func plannedGoals(goals []Goal, now time.Time) []Goal {
+	var planned []Goal
+	for _, g := range goals {
+		if _, _, ok := bareminAmount(g); ok && isDueTodayFilterAt(g, now) {
+			planned = append(planned, g)
+		}
+	}
+	SortGoals(planned)
+	return planned
+}
+
+// blockLength is how long to set aside for g: its bare minimum when g is
+// measured in time, and otherwise fallback. It is rounded up to a whole
+// planSlot.
+func blockLength(g Goal, fallback time.Duration) time.Duration {
+	amount, inHours, _ := bareminAmount(g)
+	length := fallback
+	switch {
+	case inHours:
+		length = time.Duration(amount * float64(time.Hour))
+	case isTimeUnits(g.Gunits):
+		perHour := timeUnitsPerHour[strings.ToLower(strings.TrimSpace(g.Gunits))]
+		length = time.Duration(amount / perHour * float64(time.Hour))
+	}
+	slots := math.Ceil(float64(length) / float64(planSlot))
+	return time.Duration(math.Max(slots, 1)) * planSlot
+}
+
+// planBlocks lays the goals out back to back from start, in the order given.
+func planBlocks(goals []Goal, start time.Time, fallback time.Duration) []planBlock {
+	blocks := make([]planBlock, len(goals))
+	at := start
+	for i, g := range goals {
+		end := at.Add(blockLength(g, fallback))
+		blocks[i] = planBlock{goal: g, start: at, end: end, late: end.Unix() > g.Losedate}
+		at = end
+	}
+	return blocks
+}
+
+// planAmount is what a goal needs today, e.g. "+40 pushups".
+func planAmount(g Goal) string {
+	return strings.TrimSpace(stripTimeWindowSuffix(g.Baremin) + " " + g.Gunits)
+}
+
+// renderPlan lists the goals, or their blocks when blocks is non-nil, one per
+// line.
+func renderPlan(goals []Goal, blocks []planBlock, now time.Time) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Plan for %s:\n", formatDate(now))
+	if blocks == nil {
+		for _, g := range goals {
+			fmt.Fprintf(&sb, "  [ ] %s  %s  by %s\n", g.Slug, planAmount(g), FormatAbsoluteDeadlineAt(g.Losedate, now))
+		}
+		return sb.String()
+	}
+
+	layout := clockLayout()
+	width := 0
+	for _, b := range blocks {
+		width = max(width, len(b.goal.Slug))
+	}
+	for _, b := range blocks {
+		line := fmt.Sprintf("  %8s–%-8s  %-*s  %s  (due %s)", b.start.Format(layout), b.end.Format(layout), width, b.goal.Slug, planAmount(b.goal), FormatAbsoluteDeadlineAt(b.goal.Losedate, now))
+		if b.late {
+			line = UrgencyOverdue.TextStyle().Render(line + "  ends after the deadline")
+		}
+		sb.WriteString(line + "\n")
+	}
+	return sb.String()
+}
+
+// icsText escapes s for an iCalendar text value.
+func icsText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+// renderPlanICS writes the blocks as an iCalendar file with one event each.
+func renderPlanICS(blocks []planBlock, now time.Time) string {
+	const stamp = "20060102T150405Z"
+	lines := []string{"BEGIN:VCALENDAR", "VERSION:2.0", "PRODID:-//buzz//plan//EN", "CALSCALE:GREGORIAN"}
+	for _, b := range blocks {
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			fmt.Sprintf("UID:buzz-plan-%s-%s@buzz", b.goal.Slug, b.start.Format("20060102")),
+			"DTSTAMP:"+now.UTC().Format(stamp),
+			"DTSTART:"+b.start.UTC().Format(stamp),
+			"DTEND:"+b.end.UTC().Format(stamp),
+			"SUMMARY:"+icsText(b.goal.Slug+": "+planAmount(b.goal)),
+			"DESCRIPTION:"+icsText("Beeminder deadline: "+time.Unix(b.goal.Losedate, 0).In(now.Location()).Format(clockLayout())),
+			"END:VEVENT",
+		)
+	}
+	lines = append(lines, "END:VCALENDAR")
+	return strings.Join(lines, "\r\n") + "\r\n"
+}
+
+// runPlanCommand fetches the goals and prints today's plan. It returns the
+// process exit code.
+func runPlanCommand(ctx context.Context, client Client, req planRequest, keep func(Goal) bool, now time.Time, out, errOut io.Writer) int {
+	goals, err := client.FetchGoals(ctx)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: Failed to fetch goals: %s\n", redactError(err))
+		return 1
+	}
+	planned := plannedGoals(keepGoals(goals, keep), now)
+
+	if !req.blocks {
+		if len(planned) == 0 {
+			fmt.Fprintln(out, "Nothing left to do today.")
+			return 0
+		}
+		fmt.Fprint(out, renderPlan(planned, nil, now))
+		return 0
+	}
+
+	blocks := planBlocks(planned, startOfPlan(req.start, now), req.block)
+	if req.ics {
+		fmt.Fprint(out, renderPlanICS(blocks, now))
+		return 0
+	}
+	if len(blocks) == 0 {
+		fmt.Fprintln(out, "Nothing left to do today.")
+		return 0
+	}
+	fmt.Fprint(out, renderPlan(planned, blocks, now))
+	return 0
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParsePlanArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		want     planRequest
+		wantCode int
+		wantDone bool
+	}{
+		{"defaults", nil, planRequest{block: 30 * time.Minute}, 0, false},
+		{"blocks", []string{"--blocks", "--start", "9:00 AM", "--block", "20m"}, planRequest{blocks: true, start: 9 * time.Hour, block: 20 * time.Minute}, 0, false},
+		{"ics implies blocks", []string{"--ics", "--start=13:30"}, planRequest{blocks: true, ics: true, start: 13*time.Hour + 30*time.Minute, block: 30 * time.Minute}, 0, false},
+		{"bad start", []string{"--start", "soon"}, planRequest{}, 1, true},
+		{"short block", []string{"--block", "1m"}, planRequest{}, 1, true},
+		{"extra", []string{"today"}, planRequest{}, 1, true},
+		{"help", []string{"--help"}, planRequest{}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, code, done := parsePlanArgs(tt.args, &bytes.Buffer{}, &bytes.Buffer{})
+			if got != tt.want || code != tt.wantCode || done != tt.wantDone {
+				t.Errorf("parsePlanArgs(%v) = %+v, %d, %v; want %+v, %d, %v", tt.args, got, code, done, tt.want, tt.wantCode, tt.wantDone)
+			}
+		})
+	}
+}
+
+func TestStartOfPlan(t *testing.T) {
+	now := time.Date(2024, 1, 15, 8, 52, 10, 0, time.Local)
+	if got := startOfPlan(0, now); !got.Equal(time.Date(2024, 1, 15, 8, 55, 0, 0, time.Local)) {
+		t.Errorf("startOfPlan(now) = %v, want the next 5 minutes", got)
+	}
+	if got := startOfPlan(9*time.Hour, now); !got.Equal(time.Date(2024, 1, 15, 9, 0, 0, 0, time.Local)) {
+		t.Errorf("startOfPlan(9:00) = %v", got)
+	}
+}
+
+func TestPlanBlocks(t *testing.T) {
+	now := time.Date(2024, 1, 15, 9, 0, 0, 0, time.Local)
+	at := func(h, m int) int64 { return time.Date(2024, 1, 15, h, m, 0, 0, time.Local).Unix() }
+	goals := []Goal{
+		{Slug: "write", Baremin: "+1:30 within 1 day", Gunits: "hours", Losedate: at(23, 0)},
+		{Slug: "pushups", Baremin: "+40 in 1 hour", Gunits: "pushups", Losedate: at(9, 20)},
+		{Slug: "read", Baremin: "+20 within 1 day", Gunits: "minutes", Losedate: at(23, 0)},
+		{Slug: "beer", Baremin: "+2", GoalType: "drinker", Losedate: at(23, 0)},
+		{Slug: "done", Baremin: "0 today", Losedate: at(23, 0)},
+		{Slug: "later", Baremin: "+1", Losedate: at(23, 0) + 86400},
+	}
+	planned := plannedGoals(goals, now)
+	blocks := planBlocks(planned, now, 30*time.Minute)
+	var got []string
+	for _, b := range blocks {
+		got = append(got, b.goal.Slug+" "+b.start.Format("15:04")+"-"+b.end.Format("15:04"))
+	}
+	want := "pushups 09:00-09:30,read 09:30-09:50,write 09:50-11:20"
+	if strings.Join(got, ",") != want {
+		t.Errorf("blocks = %v, want %s", got, want)
+	}
+	if !blocks[0].late || blocks[2].late {
+		t.Errorf("late = %v, %v; want only pushups late", blocks[0].late, blocks[2].late)
+	}
+}
+
+func TestRunPlanCommand(t *testing.T) {
+	now := time.Date(2024, 1, 15, 9, 0, 0, 0, time.Local)
+	client := &FakeClient{FetchGoalsFunc: func() ([]Goal, error) {
+		return []Goal{{Slug: "pushups", Baremin: "+40 in 5 hours", Gunits: "pushups", Losedate: now.Add(5 * time.Hour).Unix()}}, nil
+	}}
+	var out, errb bytes.Buffer
+	code := runPlanCommand(context.Background(), client, planRequest{}, nil, now, &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 0, "[ ] pushups  +40 pushups  by", "")
+
+	out.Reset()
+	code = runPlanCommand(context.Background(), client, planRequest{blocks: true, ics: true, block: 30 * time.Minute}, nil, now, &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 0, "BEGIN:VEVENT\r\n", "")
+	for _, want := range []string{
+		"DTSTART:" + now.UTC().Format("20060102T150405Z"),
+		"DTEND:" + now.Add(30*time.Minute).UTC().Format("20060102T150405Z"),
+		`SUMMARY:pushups: +40 pushups`,
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("ics missing %q:\n%s", want, out.String())
+		}
+	}
+
+	client.FetchGoalsFunc = func() ([]Goal, error) { return nil, nil }
+	out.Reset()
+	code = runPlanCommand(context.Background(), client, planRequest{blocks: true}, nil, now, &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 0, "Nothing left to do today.", "")
+}
+
+func TestICSText(t *testing.T) {
+	if got := icsText("a, b; c\\d\ne"); got != `a\, b\; c\\d\ne` {
+		t.Errorf("icsText = %q", got)
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/muesli/termenv"
+)
+
+// enableTerminalANSI turns on ANSI escape handling in the Windows console,
+// which the classic console host (conhost) leaves off by default: without it
+// the colors, clearScreen, and the TUI's cursor movement print as raw escape
+// codes. Windows Terminal already has it on, and elsewhere this does nothing.
+// It's left on at exit, as every terminal buzz runs in can handle it.
+func enableTerminalANSI() {
+	_, _ = termenv.EnableVirtualTerminalProcessing(termenv.NewOutput(os.Stdout))
+}
+
+// expandHome expands a leading "~" in a path from the config to the home
+// directory. Windows paths may also be written "~\buzz\hooks.star".
+func expandHome(path string) string {
+	rest, ok := strings.CutPrefix(path, "~/")
+	if !ok && runtime.GOOS == "windows" {
+		rest, ok = strings.CutPrefix(path, `~\`)
+	}
+	if !ok {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, rest)
+}
+
+// browserCommand returns the command line that opens url: the config's
+// open_command if set, else the first program in $BROWSER that's installed,
+// else the platform's opener. custom reports that the user chose the command
+// (either of the first two), so it may be a text browser that needs the
+// terminal. ok is false when there's no command at all.
+func browserCommand(config *Config, url string, lookPath func(string) (string, error)) (argv []string, custom, ok bool) {
+	if config != nil && strings.TrimSpace(config.OpenCommand) != "" {
+		return expandOpenCommand(config.OpenCommand, url), true, true
+	}
+	// $BROWSER is a list of commands to try in turn, like $PATH.
+	for _, entry := range filepath.SplitList(os.Getenv("BROWSER")) {
+		if argv := expandOpenCommand(entry, url); len(argv) > 0 {
+			if _, err := lookPath(argv[0]); err == nil {
+				return argv, true, true
+			}
+		}
+	}
+	switch runtime.GOOS {
+	case "darwin":
+		return []string{"open", url}, false, true
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return []string{"xdg-open", url}, false, true
+	case "windows":
+		return []string{"rundll32", "url.dll,FileProtocolHandler", url}, false, true
+	}
+	return nil, false, false
+}
+
+// expandOpenCommand splits an open command template on spaces and puts url
+// in place of "{url}" (or $BROWSER's "%s"), appending it when the template
+// has no placeholder.
+func expandOpenCommand(template, url string) []string {
+	fields := strings.Fields(template)
+	placed := false
+	for i, f := range fields {
+		if strings.Contains(f, "{url}") || strings.Contains(f, "%s") {
+			fields[i] = strings.NewReplacer("{url}", url, "%s", url).Replace(f)
+			placed = true
+		}
+	}
+	if len(fields) > 0 && !placed {
+		fields = append(fields, url)
+	}
+	return fields
+}
+
+// noDisplay reports whether there's likely no screen for a browser to open
+// on: buzz is running over SSH, or on a Unix system with neither an X nor a
+// Wayland display.
+func noDisplay() bool {
+	if os.Getenv("SSH_TTY") != "" || os.Getenv("SSH_CONNECTION") != "" {
+		return true
+	}
+	switch runtime.GOOS {
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == ""
+	}
+	return false
+}
+
+// printsURLs reports whether goal pages are printed rather than opened, as
+// the config's open_mode says: "print" always, "browser" never, and by
+// default when there's no display (see noDisplay) — unless the user chose
+// the browser command (custom; see browserCommand), which may well be a text
+// browser that works over SSH.
+func printsURLs(config *Config, custom bool) bool {
+	mode := ""
+	if config != nil {
+		mode = config.OpenMode
+	}
+	switch mode {
+	case "print":
+		return true
+	case "browser":
+		return false
+	}
+	return !custom && noDisplay()
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestExpandHome(t *testing.T) {
+	home := t.TempDir()
+	setHome(t, home)
+
+	if got := expandHome("~/buzz/hooks.star"); got != filepath.Join(home, "buzz", "hooks.star") {
+		t.Errorf("expandHome(~/buzz/hooks.star) = %q", got)
+	}
+	for _, path := range []string{"/etc/hooks.star", "hooks.star", "~other/hooks.star"} {
+		if got := expandHome(path); got != path {
+			t.Errorf("expandHome(%q) = %q, want it unchanged", path, got)
+		}
+	}
+}
+
+func TestBrowserCommand(t *testing.T) {
+	const url = "https://www.beeminder.com/alice/read"
+	installed := func(names ...string) func(string) (string, error) {
+		return func(name string) (string, error) {
+			for _, n := range names {
+				if n == name {
+					return "/usr/bin/" + name, nil
+				}
+			}
+			return "", errors.New("not found")
+		}
+	}
+	sep := string(filepath.ListSeparator)
+
+	tests := []struct {
+		name       string
+		config     *Config
+		browser    string
+		lookPath   func(string) (string, error)
+		wantArgv   []string
+		wantCustom bool
+	}{
+		{"open_command with placeholder", &Config{OpenCommand: "firejail --net=none lynx {url}"}, "w3m", installed("w3m"),
+			[]string{"firejail", "--net=none", "lynx", url}, true},
+		{"open_command without placeholder", &Config{OpenCommand: "lynx"}, "", installed(),
+			[]string{"lynx", url}, true},
+		{"first installed $BROWSER", &Config{}, "chromium %s" + sep + "w3m", installed("w3m"),
+			[]string{"w3m", url}, true},
+		{"$BROWSER's %s", nil, "chromium --new-window %s", installed("chromium"),
+			[]string{"chromium", "--new-window", url}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BROWSER", tt.browser)
+			argv, custom, ok := browserCommand(tt.config, url, tt.lookPath)
+			if !ok || custom != tt.wantCustom || !reflect.DeepEqual(argv, tt.wantArgv) {
+				t.Errorf("browserCommand = %q, custom %v, ok %v; want %q, custom %v", argv, custom, ok, tt.wantArgv, tt.wantCustom)
+			}
+		})
+	}
+
+	t.Run("platform opener when $BROWSER isn't installed", func(t *testing.T) {
+		t.Setenv("BROWSER", "nonesuch")
+		argv, custom, ok := browserCommand(&Config{}, url, installed())
+		if runtime.GOOS == "linux" && (!ok || custom || !reflect.DeepEqual(argv, []string{"xdg-open", url})) {
+			t.Errorf("browserCommand = %q, custom %v, ok %v; want xdg-open", argv, custom, ok)
+		}
+	})
+}
+
+func TestPrintsURLs(t *testing.T) {
+	t.Setenv("SSH_TTY", "/dev/pts/3")
+	t.Setenv("SSH_CONNECTION", "")
+	if !printsURLs(&Config{}, false) {
+		t.Error("over SSH, URLs aren't printed")
+	}
+	if printsURLs(nil, true) {
+		t.Error("a browser the user chose is skipped over SSH")
+	}
+	if printsURLs(&Config{OpenMode: "browser"}, false) {
+		t.Error(`open_mode "browser" still prints`)
+	}
+
+	t.Setenv("SSH_TTY", "")
+	t.Setenv("DISPLAY", ":0")
+	if printsURLs(&Config{}, false) {
+		t.Error("with a display, URLs are printed")
+	}
+	if !printsURLs(&Config{OpenMode: "print"}, true) {
+		t.Error(`open_mode "print" still opens`)
+	}
+}
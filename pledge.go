@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// `buzz pledge` shows where a goal is on Beeminder's pledge schedule, and with
+// --cap sets how far up it the pledge may climb. Each derailment raises the
+// pledge to the next step until it reaches the cap; a cap can only be one of
+// the steps.
+
+const pledgeUsage = `Usage: buzz pledge <goalslug> [--cap <amount>]
+  Show the goal's pledge, its cap, and the steps between. --cap sets the cap,
+  which must be one of the steps: $5, $10, $30, $90, $270, $810, or $2430.`
+
+// pledgeSteps is Beeminder's pledge schedule, in dollars.
+var pledgeSteps = []float64{5, 10, 30, 90, 270, 810, 2430}
+
+// pledgeRequest holds the parsed `buzz pledge` arguments.
+type pledgeRequest struct {
+	goalSlug  string
+	pledgeCap float64 // 0 to only show the pledge
+}
+
+// handlePledgeCommand shows a goal's pledge schedule, or sets its cap.
+func handlePledgeCommand() {
+	req, code, done := parsePledgeArgs(os.Args[2:], os.Stdout, os.Stderr)
+	if done {
+		os.Exit(code)
+	}
+
+	client, ok := loadClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+
+	code = runPledgeCommand(context.Background(), client, req, time.Now(), os.Stdout, os.Stderr)
+	if code == 0 {
+		fmt.Print(getUpdateMessage())
+	}
+	os.Exit(code)
+}
+
+// parsePledgeArgs parses `buzz pledge` arguments, returning the request, a
+// process exit code, and done=true when the caller should stop (help shown,
+// or a usage error). Flags may come before or after the goal.
+func parsePledgeArgs(args []string, stdout, stderr io.Writer) (pledgeRequest, int, bool) {
+	pledgeFlags := flag.NewFlagSet("pledge", flag.ContinueOnError)
+	// Silence the flag package's own output; we print our own usage.
+	pledgeFlags.SetOutput(io.Discard)
+	capFlag := pledgeFlags.String("cap", "", "Most the pledge rises to, in dollars")
+
+	// Re-parse after each positional, as dial does, so flags can follow the
+	// goal: `buzz pledge reading --cap 30`.
+	var positional []string
+	for remaining := args; ; {
+		if err := pledgeFlags.Parse(remaining); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				fmt.Fprintln(stdout, pledgeUsage)
+				return pledgeRequest{}, 0, true
+			}
+			fmt.Fprintf(stderr, "Error parsing flags: %s\n", err)
+			fmt.Fprintln(stderr, pledgeUsage)
+			return pledgeRequest{}, 2, true
+		}
+		if pledgeFlags.NArg() == 0 {
+			break
+		}
+		positional = append(positional, pledgeFlags.Arg(0))
+		remaining = pledgeFlags.Args()[1:]
+	}
+	if len(positional) != 1 {
+		fmt.Fprintln(stderr, "Error: buzz pledge takes one goal")
+		fmt.Fprintln(stderr, pledgeUsage)
+		return pledgeRequest{}, 1, true
+	}
+
+	req := pledgeRequest{goalSlug: positional[0]}
+	if *capFlag != "" {
+		c, err := strconv.ParseFloat(strings.TrimPrefix(*capFlag, "$"), 64)
+		if err != nil || !slices.Contains(pledgeSteps, c) {
+			fmt.Fprintf(stderr, "Error: invalid --cap %q (want one of %s)\n", *capFlag, formatPledgeSteps(pledgeSteps))
+			return pledgeRequest{}, 1, true
+		}
+		req.pledgeCap = c
+	}
+	return req, 0, false
+}
+
+// formatPledgeSteps lists amounts as "$5, $10, $30".
+func formatPledgeSteps(amounts []float64) string {
+	parts := make([]string, len(amounts))
+	for i, a := range amounts {
+		parts[i] = "$" + formatPledge(a)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// pledgeCap returns the goal's pledge cap, with ok=false when it has none.
+func pledgeCap(g Goal) (float64, bool) {
+	if g.PledgeCap == nil || *g.PledgeCap <= 0 {
+		return 0, false
+	}
+	return *g.PledgeCap, true
+}
+
+// nextPledge returns what the goal's pledge becomes after its next
+// derailment: the next step up, held at the cap.
+func nextPledge(g Goal) float64 {
+	next := g.Pledge
+	for _, step := range pledgeSteps {
+		if step > g.Pledge {
+			next = step
+			break
+		}
+	}
+	if c, ok := pledgeCap(g); ok && next > c {
+		return math.Max(c, g.Pledge)
+	}
+	return next
+}
+
+// renderPledgeSchedule lays out the goal's pledge, cap, and the steps up to
+// the cap, with the current pledge in brackets.
+func renderPledgeSchedule(g Goal, now time.Time) string {
+	c, capped := pledgeCap(g)
+	var steps []string
+	for _, step := range pledgeSteps {
+		if capped && step > c {
+			break
+		}
+		if step == g.Pledge {
+			steps = append(steps, "[$"+formatPledge(step)+"]")
+		} else {
+			steps = append(steps, "$"+formatPledge(step))
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Pledge:    $%s\n", formatPledge(g.Pledge))
+	if capped {
+		fmt.Fprintf(&sb, "Cap:       $%s\n", formatPledge(c))
+	} else {
+		sb.WriteString("Cap:       none\n")
+	}
+	fmt.Fprintf(&sb, "Schedule:  %s\n", strings.Join(steps, " → "))
+	if next := nextPledge(g); next > g.Pledge {
+		fmt.Fprintf(&sb, "Next:      $%s after a derailment\n", formatPledge(next))
+	} else {
+		sb.WriteString("Next:      stays the same after a derailment\n")
+	}
+	if at, ok := stepdownAt(g); ok {
+		fmt.Fprintf(&sb, "Stepdown:  %s\n", FormatAbsoluteDeadlineAt(at, now))
+	}
+	return sb.String()
+}
+
+// runPledgeCommand prints the goal's pledge schedule, first setting its cap
+// when req asks to. It returns the process exit code.
+func runPledgeCommand(ctx context.Context, client Client, req pledgeRequest, now time.Time, out, errOut io.Writer) int {
+	if req.pledgeCap == 0 {
+		goal, err := client.FetchGoal(ctx, req.goalSlug)
+		if err != nil {
+			fmt.Fprintf(errOut, "Error: Failed to fetch goal: %s\n", redactError(err))
+			return 1
+		}
+		fmt.Fprint(out, renderPledgeSchedule(*goal, now))
+		return 0
+	}
+
+	goal, err := client.UpdateGoalPledgeCap(ctx, req.goalSlug, req.pledgeCap)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: Failed to set pledge cap: %s\n", redactError(err))
+		return 1
+	}
+	fmt.Fprintf(out, "Set %s's pledge cap to $%s.\n", goal.Slug, formatPledge(req.pledgeCap))
+	if goal.Pledge > req.pledgeCap {
+		// A cap doesn't lower the pledge already at stake.
+		fmt.Fprintf(out, "The pledge stays at $%s until you lower it with `buzz stepdown %s`.\n", formatPledge(goal.Pledge), goal.Slug)
+	}
+	fmt.Fprint(out, renderPledgeSchedule(*goal, now))
+	return 0
+}
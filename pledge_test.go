@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParsePledgeArgs(t *testing.T) {
+	for _, tt := range []struct {
+		args     []string
+		want     pledgeRequest
+		wantCode int
+		wantDone bool
+	}{
+		{[]string{"reading"}, pledgeRequest{goalSlug: "reading"}, 0, false},
+		{[]string{"reading", "--cap", "30"}, pledgeRequest{goalSlug: "reading", pledgeCap: 30}, 0, false},
+		{[]string{"--cap", "$270", "reading"}, pledgeRequest{goalSlug: "reading", pledgeCap: 270}, 0, false},
+		{[]string{"reading", "--cap", "25"}, pledgeRequest{}, 1, true},
+		{[]string{"reading", "--cap", "lots"}, pledgeRequest{}, 1, true},
+		{[]string{}, pledgeRequest{}, 1, true},
+		{[]string{"reading", "writing"}, pledgeRequest{}, 1, true},
+		{[]string{"reading", "--yes"}, pledgeRequest{}, 2, true},
+		{[]string{"--help"}, pledgeRequest{}, 0, true},
+	} {
+		req, code, done := parsePledgeArgs(tt.args, &bytes.Buffer{}, &bytes.Buffer{})
+		if req != tt.want || code != tt.wantCode || done != tt.wantDone {
+			t.Errorf("parsePledgeArgs(%v) = %+v, %d, %v", tt.args, req, code, done)
+		}
+	}
+}
+
+func TestNextPledge(t *testing.T) {
+	capAt := func(c float64) *float64 { return &c }
+	for _, tt := range []struct {
+		goal Goal
+		want float64
+	}{
+		{Goal{Pledge: 5}, 10},
+		{Goal{Pledge: 10, PledgeCap: capAt(30)}, 30},
+		{Goal{Pledge: 30, PledgeCap: capAt(30)}, 30},
+		{Goal{Pledge: 90, PledgeCap: capAt(30)}, 90},
+		{Goal{Pledge: 0}, 5},
+		{Goal{Pledge: 2430}, 2430},
+	} {
+		if got := nextPledge(tt.goal); got != tt.want {
+			t.Errorf("nextPledge(pledge %v) = %v, want %v", tt.goal.Pledge, got, tt.want)
+		}
+	}
+}
+
+func TestRunPledgeCommand(t *testing.T) {
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.Local)
+	at := time.Date(2024, 1, 17, 23, 59, 0, 0, time.Local).Unix()
+	capAt := func(c float64) *float64 { return &c }
+	goal := &Goal{Slug: "reading", Pledge: 10, PledgeCap: capAt(90)}
+	var capped []float64
+	client := &FakeClient{
+		FetchGoalFunc: func(slug string) (*Goal, error) { return goal, nil },
+		UpdateGoalPledgeCapFunc: func(slug string, pledgeCap float64) (*Goal, error) {
+			capped = append(capped, pledgeCap)
+			g := *goal
+			g.PledgeCap = &pledgeCap
+			return &g, nil
+		},
+	}
+	run := func(req pledgeRequest) (int, string, string) {
+		var out, errOut bytes.Buffer
+		code := runPledgeCommand(context.Background(), client, req, now, &out, &errOut)
+		return code, out.String(), errOut.String()
+	}
+
+	code, out, errOut := run(pledgeRequest{goalSlug: "reading"})
+	checkResult(t, code, out, errOut, 0, "Pledge:    $10\nCap:       $90\nSchedule:  $5 → [$10] → $30 → $90\nNext:      $30 after a derailment\n", "")
+
+	goal = &Goal{Slug: "reading", Pledge: 30, Contract: &Contract{Amount: 30, StepdownAt: &at}}
+	code, out, errOut = run(pledgeRequest{goalSlug: "reading"})
+	checkResult(t, code, out, errOut, 0, "Cap:       none\n", "")
+	checkResult(t, code, out, errOut, 0, "Stepdown:  Jan 17 11:59 PM\n", "")
+	if len(capped) != 0 {
+		t.Errorf("showing the pledge set the cap: %v", capped)
+	}
+
+	code, out, errOut = run(pledgeRequest{goalSlug: "reading", pledgeCap: 10})
+	checkResult(t, code, out, errOut, 0, "Set reading's pledge cap to $10.\nThe pledge stays at $30 until you lower it with `buzz stepdown reading`.\n", "")
+	checkResult(t, code, out, errOut, 0, "Next:      stays the same after a derailment\n", "")
+	if len(capped) != 1 || capped[0] != 10 {
+		t.Errorf("capped = %v, want [10]", capped)
+	}
+
+	client.UpdateGoalPledgeCapFunc = nil
+	code, out, errOut = run(pledgeRequest{goalSlug: "reading", pledgeCap: 30})
+	checkResult(t, code, out, errOut, 1, "", "Error: Failed to set pledge cap: FakeClient method not configured for this test\n")
+}
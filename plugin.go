@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+)
+
+// Plugins work like git's external commands: `buzz foo args...` runs
+// `buzz-foo args...` from PATH when foo isn't a built-in command. The plugin
+// inherits the terminal and gets the user's account details and buzz's global
+// options through BUZZ_* environment variables, so it can call the Beeminder
+// API without parsing ~/.buzzrc itself. Any buzz-* on PATH runs, so only the
+// plugins listed in trusted_plugins get the auth token, and the others don't
+// inherit $BUZZ_PASSPHRASE either, which would let them unlock an encrypted
+// ~/.buzzrc and read it from there.
+
+// pluginPrefix is prepended to an unknown command's name to find its plugin.
+const pluginPrefix = "buzz-"
+
+// findPlugin returns the path of the plugin executable for a command name, if
+// one is on PATH. Names that look like flags or paths never match.
+func findPlugin(name string) (string, bool) {
+	if name == "" || strings.HasPrefix(name, "-") || strings.ContainsAny(name, `/\`) {
+		return "", false
+	}
+	path, err := exec.LookPath(pluginPrefix + name)
+	if err != nil {
+		return "", false
+	}
+	return path, true
+}
+
+// pluginEnv returns the environment a plugin runs with: buzz's own, less
+// $BUZZ_PASSPHRASE and any $BUZZ_AUTH_TOKEN unless the plugin is trusted, plus
+//
+//	BUZZ_VERSION     this buzz's version
+//	BUZZ_CONFIG      path to ~/.buzzrc
+//	BUZZ_USERNAME    Beeminder username      } when buzz is logged in
+//	BUZZ_BASE_URL    Beeminder API base URL  }
+//	BUZZ_AUTH_TOKEN  Beeminder auth token    } ...and trusted_plugins has name
+//	BUZZ_FORMAT      the global --format (table, json, jsonl, or csv)
+//	BUZZ_NO_COLOR    "1" with --no-color
+//
+// config may be nil when buzz isn't logged in, or its config is locked, in
+// which case no plugin counts as trusted.
+func pluginEnv(config *Config, name string, noColor bool) []string {
+	trusted := config != nil && slices.Contains(config.TrustedPlugins, name)
+	env := os.Environ()
+	if !trusted {
+		env = slices.DeleteFunc(env, func(kv string) bool {
+			key, _, _ := strings.Cut(kv, "=")
+			return key == configPassphraseEnv || key == "BUZZ_AUTH_TOKEN"
+		})
+	}
+	env = append(env,
+		"BUZZ_VERSION="+version,
+		"BUZZ_FORMAT="+outputFormat,
+	)
+	if path, err := getConfigPath(); err == nil {
+		env = append(env, "BUZZ_CONFIG="+path)
+	}
+	if config != nil {
+		env = append(env,
+			"BUZZ_USERNAME="+config.Username,
+			"BUZZ_BASE_URL="+getBaseURL(config),
+		)
+		if trusted {
+			env = append(env, "BUZZ_AUTH_TOKEN="+config.AuthToken)
+		}
+	}
+	if noColor {
+		env = append(env, "BUZZ_NO_COLOR=1")
+	}
+	return env
+}
+
+// runPlugin runs a plugin with args and env on the given streams, returning
+// its exit code.
+func runPlugin(path string, args, env []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	cmd := exec.Command(path, args...)
+	cmd.Env = env
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = stdin, stdout, stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() >= 0 {
+			return exitErr.ExitCode()
+		}
+		fmt.Fprintf(stderr, "Error: Failed to run plugin %s: %s\n", path, err)
+		return 1
+	}
+	return 0
+}
+
+// handlePluginCommand runs the plugin at path for `buzz <name> args...`,
+// exiting with its exit code. A missing or unreadable config just means the
+// plugin gets no account details. An encrypted config is only unlocked when
+// $BUZZ_PASSPHRASE can do it without a prompt; otherwise the plugin runs as
+// an untrusted one rather than asking for the passphrase on every run.
+func handlePluginCommand(name, path string, args []string, noColor bool) {
+	var config *Config
+	if ConfigExists() {
+		load := loadUnlockedConfig
+		if _, ok := os.LookupEnv(configPassphraseEnv); ok {
+			load = LoadConfig
+		}
+		config, _ = load()
+	}
+	os.Exit(runPlugin(path, args, pluginEnv(config, name, noColor), os.Stdin, os.Stdout, os.Stderr))
+}
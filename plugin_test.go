@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// writePlugin puts an executable shell script named buzz-<name> in a fresh
+// directory on PATH.
+func writePlugin(t *testing.T, name, script string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin scripts need a POSIX shell")
+	}
+	dir := t.TempDir()
+	path := filepath.Join(dir, pluginPrefix+name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return path
+}
+
+func TestFindPlugin(t *testing.T) {
+	want := writePlugin(t, "hello", "exit 0\n")
+
+	if got, ok := findPlugin("hello"); !ok || got != want {
+		t.Errorf("findPlugin(hello) = %q, %v; want %q", got, ok, want)
+	}
+	for _, name := range []string{"missing", "", "-x", "../hello", `a\b`} {
+		if got, ok := findPlugin(name); ok {
+			t.Errorf("findPlugin(%q) = %q, want no plugin", name, got)
+		}
+	}
+}
+
+func TestRunPlugin(t *testing.T) {
+	t.Run("passes arguments, environment, and exit code", func(t *testing.T) {
+		path := writePlugin(t, "echo", `echo "$@" "$BUZZ_USERNAME" "$BUZZ_FORMAT" "$BUZZ_NO_COLOR"; read line; echo "$line" >&2; exit 3`+"\n")
+		config := &Config{Username: "alice", AuthToken: "tok"}
+		var out, errb bytes.Buffer
+		code := runPlugin(path, []string{"a", "b"}, pluginEnv(config, "echo", true), strings.NewReader("piped\n"), &out, &errb)
+		checkResult(t, code, out.String(), errb.String(), 3, "a b alice table 1", "piped")
+	})
+
+	t.Run("not executable", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "buzz-broken")
+		if err := os.WriteFile(path, []byte("not a program"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		var out, errb bytes.Buffer
+		code := runPlugin(path, nil, nil, strings.NewReader(""), &out, &errb)
+		checkResult(t, code, out.String(), errb.String(), 1, "", "Failed to run plugin")
+	})
+}
+
+func TestPluginEnv(t *testing.T) {
+	setHome(t, t.TempDir())
+	has := func(env []string, kv string) bool {
+		for _, e := range env {
+			if e == kv {
+				return true
+			}
+		}
+		return false
+	}
+
+	config := &Config{Username: "alice", AuthToken: "tok", TrustedPlugins: []string{"sync"}}
+	env := pluginEnv(config, "sync", false)
+	for _, kv := range []string{"BUZZ_USERNAME=alice", "BUZZ_AUTH_TOKEN=tok", "BUZZ_BASE_URL=https://www.beeminder.com", "BUZZ_VERSION=" + version} {
+		if !has(env, kv) {
+			t.Errorf("env lacks %s", kv)
+		}
+	}
+	if has(env, "BUZZ_NO_COLOR=1") {
+		t.Error("BUZZ_NO_COLOR set without --no-color")
+	}
+
+	// Only trusted plugins get the token.
+	env = pluginEnv(config, "other", false)
+	if has(env, "BUZZ_AUTH_TOKEN=tok") || !has(env, "BUZZ_USERNAME=alice") {
+		t.Errorf("untrusted plugin env = %q, want the username but no token", env)
+	}
+
+	env = pluginEnv(nil, "sync", false)
+	for _, e := range env {
+		if strings.HasPrefix(e, "BUZZ_AUTH_TOKEN=") {
+			t.Errorf("logged-out env has %s", e)
+		}
+	}
+}
+
+func TestPluginEnvWithholdsSecretsFromUntrustedPlugins(t *testing.T) {
+	setHome(t, t.TempDir())
+	t.Setenv(configPassphraseEnv, "hunter2")
+	t.Setenv("BUZZ_AUTH_TOKEN", "inherited")
+	secret := func(env []string) []string {
+		var found []string
+		for _, e := range env {
+			if strings.HasPrefix(e, configPassphraseEnv+"=") || strings.HasPrefix(e, "BUZZ_AUTH_TOKEN=") {
+				found = append(found, e)
+			}
+		}
+		return found
+	}
+
+	config := &Config{Username: "alice", AuthToken: "tok", TrustedPlugins: []string{"sync"}}
+	if got := secret(pluginEnv(config, "other", false)); len(got) != 0 {
+		t.Errorf("untrusted plugin env has %q", got)
+	}
+	if got := secret(pluginEnv(nil, "sync", false)); len(got) != 0 {
+		t.Errorf("logged-out plugin env has %q", got)
+	}
+	got := secret(pluginEnv(config, "sync", false))
+	if !slices.Contains(got, configPassphraseEnv+"=hunter2") || got[len(got)-1] != "BUZZ_AUTH_TOKEN=tok" {
+		t.Errorf("trusted plugin env secrets = %q, want the passphrase and its own token last", got)
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const pomUsage = `Usage: buzz pom [--units hours|minutes|count] [--comment <text>] <goalslug> [duration]
+  duration   Length of the session, e.g. 25m, 50m, 1h (default: 25m)
+  --units    What to log when the session completes: the duration in decimal
+             hours (default), in minutes, or a count of 1. Defaults to
+             "pom_units" in ~/.buzzrc.
+  --comment  Datapoint comment (default: "<duration> pomodoro")
+Press Ctrl+C to abandon the session without logging anything.`
+
+// defaultPomDuration is the classic pomodoro length.
+const defaultPomDuration = 25 * time.Minute
+
+// pomRequest is a parsed, validated `buzz pom` invocation.
+type pomRequest struct {
+	goalSlug string
+	duration time.Duration
+	units    string // hours, minutes, or count; "" to use the config (hours if unset)
+	comment  string
+}
+
+// handlePomCommand runs a pomodoro timer and logs the session to a goal when
+// it completes.
+func handlePomCommand() {
+	req, code, done := parsePomArgs(os.Args[2:], os.Stdout, os.Stderr)
+	if done {
+		os.Exit(code)
+	}
+
+	client, ok := loadClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+	if req.units == "" {
+		// loadClient just loaded the config successfully.
+		if config, err := LoadConfig(); err == nil {
+			req.units = config.PomUnits
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code = runPomCommand(ctx, req, runPomTimer, notifyPomDone, client, os.Stdout, os.Stderr)
+	stop()
+	if code == 0 {
+		fmt.Print(getUpdateMessage())
+	}
+	os.Exit(code)
+}
+
+// parsePomArgs parses and validates `buzz pom` arguments, returning the
+// request, a process exit code, and done=true when the caller should stop
+// (help shown, or a parse/validation error).
+func parsePomArgs(args []string, stdout, stderr io.Writer) (pomRequest, int, bool) {
+	pomFlags := flag.NewFlagSet("pom", flag.ContinueOnError)
+	// Silence the flag package's own output; we print our own usage.
+	pomFlags.SetOutput(io.Discard)
+	units := pomFlags.String("units", "", "What to log: hours, minutes, or count")
+	comment := pomFlags.String("comment", "", "Datapoint comment")
+	if err := pomFlags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stdout, pomUsage)
+			return pomRequest{}, 0, true
+		}
+		fmt.Fprintf(stderr, "Error parsing flags: %s\n", redactError(err))
+		fmt.Fprintln(stderr, pomUsage)
+		return pomRequest{}, 2, true
+	}
+
+	rest := pomFlags.Args()
+	if len(rest) < 1 || len(rest) > 2 {
+		fmt.Fprintln(stderr, "Error: Missing required arguments")
+		fmt.Fprintln(stderr, pomUsage)
+		return pomRequest{}, 1, true
+	}
+
+	req := pomRequest{goalSlug: rest[0], duration: defaultPomDuration, units: *units, comment: *comment}
+	if len(rest) == 2 {
+		d, err := time.ParseDuration(rest[1])
+		if err != nil || d <= 0 {
+			fmt.Fprintf(stderr, "Error: Invalid duration %q (expected e.g. 25m or 1h)\n", rest[1])
+			return pomRequest{}, 1, true
+		}
+		req.duration = d
+	}
+	if req.units != "" && pomValue(req.duration, req.units) == "" {
+		fmt.Fprintf(stderr, "Error: Invalid --units %q (expected hours, minutes, or count)\n", req.units)
+		return pomRequest{}, 1, true
+	}
+	return req, 0, false
+}
+
+// pomValue returns the datapoint value for a completed session of d in the
+// given units ("" meaning hours), or "" for unknown units.
+func pomValue(d time.Duration, units string) string {
+	switch units {
+	case "", "hours":
+		return strconv.FormatFloat(d.Hours(), 'f', -1, 64)
+	case "minutes":
+		return strconv.FormatFloat(d.Minutes(), 'f', -1, 64)
+	case "count":
+		return "1"
+	}
+	return ""
+}
+
+// pomDurationLabel formats d the way it would be typed, e.g. "25m" or
+// "1h30m" rather than time.Duration's "25m0s".
+func pomDurationLabel(d time.Duration) string {
+	s := d.Round(time.Second).String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// pomTimer counts down d, reporting progress to out, and returns true if the
+// session ran to completion or false if ctx was cancelled first.
+type pomTimer func(ctx context.Context, d time.Duration, goalSlug string, out io.Writer) bool
+
+// runPomCommand runs the session with timer and, if it completes, calls notify
+// and logs the session to the goal. It returns the process exit code;
+// abandoning the session is not an error.
+func runPomCommand(ctx context.Context, req pomRequest, timer pomTimer, notify func(goalSlug string, out io.Writer), client Client, stdout, stderr io.Writer) int {
+	value := pomValue(req.duration, req.units)
+	if value == "" {
+		fmt.Fprintf(stderr, "Error: Invalid pom_units %q in ~/.buzzrc (expected hours, minutes, or count)\n", req.units)
+		return 1
+	}
+	comment := req.comment
+	if comment == "" {
+		comment = fmt.Sprintf("%s pomodoro", pomDurationLabel(req.duration))
+	}
+
+	if !timer(ctx, req.duration, req.goalSlug, stdout) {
+		fmt.Fprintln(stdout, "Pomodoro abandoned; nothing was logged.")
+		return 0
+	}
+	notify(req.goalSlug, stdout)
+
+	// The session is over, so a cancel from here on shouldn't lose it.
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	if _, err := client.CreateDatapoint(context.Background(), req.goalSlug, timestamp, value, comment, ""); err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to log pomodoro: %s\n", redactError(err))
+		return 1
+	}
+	fmt.Fprintf(stdout, "Logged %s to %s (\"%s\")\n", value, req.goalSlug, comment)
+
+	// Signal any running TUI instances to refresh so they pick up the new
+	// datapoint. Don't fail the command if flag creation fails.
+	if err := createRefreshFlag(); err != nil {
+		fmt.Fprintf(stderr, "Warning: Could not create refresh flag: %s\n", redactError(err))
+	}
+	return 0
+}
+
+// runPomTimer is the real pomTimer: a once-a-second countdown redrawn in place.
+func runPomTimer(ctx context.Context, d time.Duration, goalSlug string, out io.Writer) bool {
+	end := time.Now().Add(d)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		remaining := time.Until(end).Round(time.Second)
+		if remaining < 0 {
+			remaining = 0
+		}
+		fmt.Fprintf(out, "\r🍅 %s  %02d:%02d remaining (Ctrl+C to abandon) ", goalSlug, int(remaining.Minutes()), int(remaining.Seconds())%60)
+		if remaining == 0 {
+			fmt.Fprintln(out)
+			return true
+		}
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(out)
+			return false
+		case <-ticker.C:
+		}
+	}
+}
+
+// notifyPomDone rings the terminal bell and, where the platform has a
+// notifier, shows a desktop notification. Failures are ignored: the bell and
+// the log message below it are enough.
+func notifyPomDone(goalSlug string, out io.Writer) {
+	fmt.Fprintf(out, "\a🍅 Pomodoro for %s complete!\n", goalSlug)
+
+	message := fmt.Sprintf("Pomodoro for %s complete", goalSlug)
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("osascript", "-e", fmt.Sprintf("display notification %q with title \"buzz\"", message))
+	case "linux":
+		cmd = exec.Command("notify-send", "buzz", message)
+	default:
+		return
+	}
+	_ = cmd.Start()
+}
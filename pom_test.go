@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParsePomArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		wantDone bool
+		want     pomRequest
+	}{
+		{"default duration", []string{"focus"}, 0, false, pomRequest{goalSlug: "focus", duration: 25 * time.Minute}},
+		{"custom duration and units", []string{"--units", "count", "focus", "50m"}, 0, false, pomRequest{goalSlug: "focus", duration: 50 * time.Minute, units: "count"}},
+		{"comment", []string{"--comment", "essay", "focus"}, 0, false, pomRequest{goalSlug: "focus", duration: 25 * time.Minute, comment: "essay"}},
+		{"help", []string{"-h"}, 0, true, pomRequest{}},
+		{"missing goal", nil, 1, true, pomRequest{}},
+		{"bad duration", []string{"focus", "soon"}, 1, true, pomRequest{}},
+		{"negative duration", []string{"focus", "-5m"}, 1, true, pomRequest{}},
+		{"bad units", []string{"--units", "seconds", "focus"}, 1, true, pomRequest{}},
+		{"unknown flag", []string{"--nope", "focus"}, 2, true, pomRequest{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, code, done := parsePomArgs(tt.args, &bytes.Buffer{}, &bytes.Buffer{})
+			if code != tt.wantCode || done != tt.wantDone || req != tt.want {
+				t.Errorf("got (%+v, %d, %v), want (%+v, %d, %v)", req, code, done, tt.want, tt.wantCode, tt.wantDone)
+			}
+		})
+	}
+}
+
+func TestPomValue(t *testing.T) {
+	tests := []struct {
+		units string
+		want  string
+	}{
+		{"", "0.5"},
+		{"hours", "0.5"},
+		{"minutes", "30"},
+		{"count", "1"},
+		{"seconds", ""},
+	}
+	for _, tt := range tests {
+		if got := pomValue(30*time.Minute, tt.units); got != tt.want {
+			t.Errorf("pomValue(30m, %q) = %q, want %q", tt.units, got, tt.want)
+		}
+	}
+}
+
+func TestRunPomCommand(t *testing.T) {
+	completes := func(context.Context, time.Duration, string, io.Writer) bool { return true }
+	abandoned := func(context.Context, time.Duration, string, io.Writer) bool { return false }
+	noNotify := func(string, io.Writer) {}
+
+	t.Run("completed session is logged", func(t *testing.T) {
+		setHome(t, t.TempDir()) // contain createRefreshFlag's file write
+		var gotSlug, gotValue, gotComment string
+		client := &FakeClient{CreateDatapointFunc: func(slug, _, value, comment, _ string) (*Datapoint, error) {
+			gotSlug, gotValue, gotComment = slug, value, comment
+			return &Datapoint{}, nil
+		}}
+		notified := false
+		var out, errb bytes.Buffer
+		code := runPomCommand(context.Background(), pomRequest{goalSlug: "focus", duration: 25 * time.Minute}, completes,
+			func(string, io.Writer) { notified = true }, client, &out, &errb)
+		if code != 0 || !notified || gotSlug != "focus" || gotComment != "25m pomodoro" {
+			t.Errorf("code=%d notified=%v slug=%q comment=%q err=%q", code, notified, gotSlug, gotComment, errb.String())
+		}
+		if !strings.HasPrefix(gotValue, "0.41666") {
+			t.Errorf("value = %q, want 25 minutes in hours", gotValue)
+		}
+	})
+
+	t.Run("abandoned session logs nothing", func(t *testing.T) {
+		client := &FakeClient{} // CreateDatapoint unset → would error if called
+		var out bytes.Buffer
+		code := runPomCommand(context.Background(), pomRequest{goalSlug: "focus", duration: time.Minute}, abandoned, noNotify, client, &out, &bytes.Buffer{})
+		if code != 0 || !strings.Contains(out.String(), "nothing was logged") {
+			t.Errorf("code=%d out=%q", code, out.String())
+		}
+	})
+
+	t.Run("invalid configured units", func(t *testing.T) {
+		var errb bytes.Buffer
+		code := runPomCommand(context.Background(), pomRequest{goalSlug: "focus", duration: time.Minute, units: "pages"}, completes, noNotify, &FakeClient{}, &bytes.Buffer{}, &errb)
+		if code != 1 || !strings.Contains(errb.String(), "pom_units") {
+			t.Errorf("code=%d err=%q", code, errb.String())
+		}
+	})
+
+	t.Run("log error", func(t *testing.T) {
+		client := &FakeClient{CreateDatapointFunc: func(string, string, string, string, string) (*Datapoint, error) {
+			return nil, errors.New("boom")
+		}}
+		var errb bytes.Buffer
+		code := runPomCommand(context.Background(), pomRequest{goalSlug: "focus", duration: time.Minute}, completes, noNotify, client, &bytes.Buffer{}, &errb)
+		if code != 1 || !strings.Contains(errb.String(), "Failed to log pomodoro") {
+			t.Errorf("code=%d err=%q", code, errb.String())
+		}
+	})
+}
+
+func TestRunPomTimerCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if runPomTimer(ctx, time.Hour, "focus", io.Discard) {
+		t.Error("runPomTimer() = true after cancel, want false")
+	}
+}
+
+func TestPomDurationLabel(t *testing.T) {
+	tests := map[time.Duration]string{
+		25 * time.Minute:                "25m",
+		time.Hour:                       "1h",
+		90 * time.Minute:                "1h30m",
+		45 * time.Second:                "45s",
+		25*time.Minute + 30*time.Second: "25m30s",
+	}
+	for d, want := range tests {
+		if got := pomDurationLabel(d); got != want {
+			t.Errorf("pomDurationLabel(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestRenderQR(t *testing.T) {
+	code, err := renderQR("https://www.beeminder.com/alice/read")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(code, "\n"), "\n")
+	// A version 3 code is 29 modules square, plus the quiet zone on each side.
+	width := 29 + 2*qrQuietZone
+	if len(lines) != (width+1)/2 {
+		t.Errorf("%d lines, want %d", len(lines), (width+1)/2)
+	}
+	for i, line := range lines {
+		if n := utf8.RuneCountInString(line); n != width {
+			t.Fatalf("line %d is %d wide, want %d", i, n, width)
+		}
+	}
+	// The quiet zone's top rows are all light.
+	if lines[0] != strings.Repeat("█", width) {
+		t.Errorf("top row = %q, want the quiet zone", lines[0])
+	}
+}
+
+func TestPrintGoalURL(t *testing.T) {
+	var b strings.Builder
+	if err := printGoalURL(&b, "https://www.beeminder.com/alice/read", false); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != "https://www.beeminder.com/alice/read\n" {
+		t.Errorf("output = %q", b.String())
+	}
+
+	b.Reset()
+	if err := printGoalURL(&b, "https://www.beeminder.com/alice/read", true); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(b.String(), "https://www.beeminder.com/alice/read\n█") {
+		t.Errorf("output = %q, want the URL then the QR code", b.String())
+	}
+}
@@ -1,109 +1,170 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
+const ratchetUsage = `Usage: buzz ratchet [-y|--yes] [--dry-run] <goalslug> <days>
+       buzz ratchet [-y|--yes] [--dry-run] <goalslug> --days <days>
+  <days> is the number of days of safety buffer to leave on the goal`
+
+// ratchetRequest holds the parsed `buzz ratchet` arguments.
+type ratchetRequest struct {
+	goalSlug    string
+	days        int
+	skipConfirm bool
+	dryRun      bool // only show what the ratchet would do
+}
+
 // handleRatchetCommand removes safety buffer from a goal, leaving it with at
 // most the specified number of days of buffer. The Beeminder ratchet endpoint
 // only ever tightens a goal: requests that would add buffer are ignored by the
 // server, so a goal already at or below the target is left unchanged.
 func handleRatchetCommand() {
-	ratchetFlags := flag.NewFlagSet("ratchet", flag.ContinueOnError)
-	ratchetFlags.Usage = func() {
-		fmt.Fprintln(os.Stderr, "Usage: buzz ratchet [-y|--yes] <goalslug> <days>")
-		fmt.Fprintln(os.Stderr, "  <days> is the number of days of safety buffer to leave on the goal")
+	req, code, done := parseRatchetArgs(os.Args[2:], os.Stdout, os.Stderr)
+	if done {
+		os.Exit(code)
+	}
+
+	client, ok := loadClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+
+	code = runRatchetCommand(context.Background(), client, req, time.Now(), os.Stdin, os.Stdout, os.Stderr)
+	if code == 0 && !req.dryRun {
+		fmt.Print(getUpdateMessage())
 	}
+	os.Exit(code)
+}
+
+// parseRatchetArgs parses `buzz ratchet` arguments, returning the request, a
+// process exit code, and done=true when the caller should stop (help shown,
+// or a usage error). Flags may come before or after the goal.
+func parseRatchetArgs(args []string, stdout, stderr io.Writer) (ratchetRequest, int, bool) {
+	ratchetFlags := flag.NewFlagSet("ratchet", flag.ContinueOnError)
+	// Silence the flag package's own output; we print our own usage.
+	ratchetFlags.SetOutput(io.Discard)
+	days := ratchetFlags.String("days", "", "Days of safety buffer to leave")
+	dryRun := dryRunFlag(ratchetFlags, "Show the new deadline without ratcheting")
 	yes := ratchetFlags.Bool("yes", false, "Skip the confirmation prompt")
 	yesShort := ratchetFlags.Bool("y", false, "Skip the confirmation prompt (shorthand)")
-	if err := ratchetFlags.Parse(os.Args[2:]); err != nil {
-		if errors.Is(err, flag.ErrHelp) {
-			ratchetFlags.Usage()
-			return
+
+	// Re-parse after each positional, as dial does, so flags can follow the
+	// goal: `buzz ratchet reading --days 2`.
+	var positional []string
+	for remaining := args; ; {
+		if err := ratchetFlags.Parse(remaining); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				fmt.Fprintln(stdout, ratchetUsage)
+				return ratchetRequest{}, 0, true
+			}
+			fmt.Fprintf(stderr, "Error parsing flags: %s\n", err)
+			fmt.Fprintln(stderr, ratchetUsage)
+			return ratchetRequest{}, 2, true
+		}
+		if ratchetFlags.NArg() == 0 {
+			break
 		}
-		fmt.Fprintf(os.Stderr, "Error parsing flags: %s\n", err)
-		ratchetFlags.Usage()
-		os.Exit(2)
+		positional = append(positional, ratchetFlags.Arg(0))
+		remaining = ratchetFlags.Args()[1:]
 	}
 
-	args := ratchetFlags.Args()
-	if len(args) != 2 {
-		if len(args) < 2 {
-			fmt.Fprintln(os.Stderr, "Error: Missing required arguments")
+	if *days != "" {
+		positional = append(positional, *days)
+		if len(positional) > 2 {
+			fmt.Fprintln(stderr, "Error: Give the number of days either as an argument or with --days, not both")
+			fmt.Fprintln(stderr, ratchetUsage)
+			return ratchetRequest{}, 1, true
+		}
+	}
+	if len(positional) != 2 {
+		if len(positional) < 2 {
+			fmt.Fprintln(stderr, "Error: Missing required arguments")
 		} else {
-			fmt.Fprintf(os.Stderr, "Error: Too many arguments: %v\n", args[2:])
+			fmt.Fprintf(stderr, "Error: Too many arguments: %v\n", positional[2:])
 		}
-		ratchetFlags.Usage()
-		os.Exit(1)
+		fmt.Fprintln(stderr, ratchetUsage)
+		return ratchetRequest{}, 1, true
 	}
 
-	goalSlug := args[0]
-	days, err := strconv.Atoi(args[1])
+	n, err := strconv.Atoi(positional[1])
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: Invalid number of days %q: must be a whole number\n", args[1])
-		os.Exit(1)
+		fmt.Fprintf(stderr, "Error: Invalid number of days %q: must be a whole number\n", positional[1])
+		return ratchetRequest{}, 1, true
 	}
-	if days < 0 {
-		fmt.Fprintln(os.Stderr, "Error: Number of days must not be negative")
-		os.Exit(1)
-	}
-
-	skipConfirm := *yes || *yesShort
-
-	if !ConfigExists() {
-		fmt.Fprintln(os.Stderr, "Error: No configuration found. Please run 'buzz auth login' to authenticate.")
-		os.Exit(1)
+	if n < 0 {
+		fmt.Fprintln(stderr, "Error: Number of days must not be negative")
+		return ratchetRequest{}, 1, true
 	}
+	return ratchetRequest{goalSlug: positional[0], days: n, skipConfirm: *yes || *yesShort, dryRun: *dryRun}, 0, false
+}
 
-	config, err := LoadConfig()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: Failed to load config: %s\n", redactError(err))
-		os.Exit(1)
+// ratchetedLosedate estimates the goal's losedate once ratcheted to days of
+// buffer: each day of buffer removed brings it a day closer. A goal already
+// at or below days keeps its losedate.
+func ratchetedLosedate(g Goal, days int) int64 {
+	if g.Safebuf <= days {
+		return g.Losedate
 	}
+	return time.Unix(g.Losedate, 0).AddDate(0, 0, days-g.Safebuf).Unix()
+}
 
-	client := NewHTTPClient(config)
-
-	if !skipConfirm {
-		// Fetch the current goal only when we need to show the confirmation
-		// prompt, so the --yes path doesn't pay for an extra API call that
+// runRatchetCommand ratchets the goal, after showing what it will do and
+// confirming on stdin unless skipConfirm is set. With dryRun it only shows
+// what it would do. It returns the process exit code.
+func runRatchetCommand(ctx context.Context, client Client, req ratchetRequest, now time.Time, stdin io.Reader, out, errOut io.Writer) int {
+	if !req.skipConfirm || req.dryRun {
+		// Fetch the current goal only when we need to show what the ratchet
+		// will do, so the --yes path doesn't pay for an extra API call that
 		// can fail before the ratchet itself runs.
-		currentGoal, err := client.FetchGoal(context.Background(), goalSlug)
+		current, err := client.FetchGoal(ctx, req.goalSlug)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: Failed to fetch goal: %s\n", redactError(err))
-			os.Exit(1)
+			fmt.Fprintf(errOut, "Error: Failed to fetch goal: %s\n", redactError(err))
+			return 1
 		}
-		if currentGoal.Safebuf <= days {
-			fmt.Printf("%s already has %d days of safety buffer, which is at or below %d days. No buffer will be removed. Continue anyway? [y/N] ", goalSlug, currentGoal.Safebuf, days)
-		} else {
-			fmt.Printf("Ratchet %s from %d to at most %d days of safety buffer? This removes buffer and cannot add it back. [y/N] ", goalSlug, currentGoal.Safebuf, days)
+
+		if req.dryRun {
+			if current.Safebuf <= req.days {
+				fmt.Fprintf(out, "%s already has %d days of safety buffer, which is at or below %d days, so ratcheting would leave it due %s.\n",
+					req.goalSlug, current.Safebuf, req.days, FormatAbsoluteDeadlineAt(current.Losedate, now))
+			} else {
+				fmt.Fprintf(out, "Ratcheting %s from %d to %d days of safety buffer would move its deadline from %s to %s.\n",
+					req.goalSlug, current.Safebuf, req.days, FormatAbsoluteDeadlineAt(current.Losedate, now), FormatAbsoluteDeadlineAt(ratchetedLosedate(*current, req.days), now))
+			}
+			return 0
 		}
-		var response string
-		if _, err := fmt.Scanln(&response); err != nil {
-			// EOF or read error in non-interactive contexts — treat as "no"
-			// so we never remove buffer without an explicit affirmative.
-			fmt.Println("Cancelled.")
-			return
+
+		if current.Safebuf <= req.days {
+			fmt.Fprintf(out, "%s already has %d days of safety buffer, which is at or below %d days. No buffer will be removed. Continue anyway? [y/N] ", req.goalSlug, current.Safebuf, req.days)
+		} else {
+			fmt.Fprintf(out, "Ratchet %s from %d to at most %d days of safety buffer? This removes buffer and cannot add it back. [y/N] ", req.goalSlug, current.Safebuf, req.days)
 		}
-		response = strings.TrimSpace(strings.ToLower(response))
-		if response != "y" && response != "yes" {
-			fmt.Println("Cancelled.")
-			return
+		// Only an explicit "y" or "yes" removes buffer: a read error, or EOF
+		// before any answer, is a "no". As with buzz dial, a piped "y"
+		// without a newline still confirms.
+		line, err := bufio.NewReader(stdin).ReadString('\n')
+		answer := strings.ToLower(strings.TrimSpace(line))
+		if err != nil && !errors.Is(err, io.EOF) || answer != "y" && answer != "yes" {
+			fmt.Fprintln(out, "Cancelled.")
+			return 0
 		}
 	}
 
-	goal, err := client.RatchetGoal(context.Background(), goalSlug, days)
+	goal, err := client.RatchetGoal(ctx, req.goalSlug, req.days)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: Failed to ratchet goal: %s\n", redactError(err))
-		os.Exit(1)
+		fmt.Fprintf(errOut, "Error: Failed to ratchet goal: %s\n", redactError(err))
+		return 1
 	}
-
-	fmt.Printf("Ratcheted %s to %d days of safety buffer.\n", goal.Slug, goal.Safebuf)
-
-	fmt.Print(getUpdateMessage())
+	fmt.Fprintf(out, "Ratcheted %s to %d days of safety buffer.\n", goal.Slug, goal.Safebuf)
+	return 0
 }
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRatchetArgs(t *testing.T) {
+	for _, tt := range []struct {
+		args     []string
+		want     ratchetRequest
+		wantCode int
+		wantDone bool
+	}{
+		{[]string{"reading", "2"}, ratchetRequest{goalSlug: "reading", days: 2}, 0, false},
+		{[]string{"reading", "--days", "2", "--dry-run"}, ratchetRequest{goalSlug: "reading", days: 2, dryRun: true}, 0, false},
+		{[]string{"-y", "reading", "0"}, ratchetRequest{goalSlug: "reading", skipConfirm: true}, 0, false},
+		{[]string{"reading"}, ratchetRequest{}, 1, true},
+		{[]string{"reading", "2", "--days", "3"}, ratchetRequest{}, 1, true},
+		{[]string{"reading", "2", "3"}, ratchetRequest{}, 1, true},
+		{[]string{"reading", "two"}, ratchetRequest{}, 1, true},
+		{[]string{"reading", "--days=-1"}, ratchetRequest{}, 1, true},
+		{[]string{"--nope"}, ratchetRequest{}, 2, true},
+		{[]string{"--help"}, ratchetRequest{}, 0, true},
+	} {
+		req, code, done := parseRatchetArgs(tt.args, &bytes.Buffer{}, &bytes.Buffer{})
+		if req != tt.want || code != tt.wantCode || done != tt.wantDone {
+			t.Errorf("parseRatchetArgs(%v) = %+v, %d, %v", tt.args, req, code, done)
+		}
+	}
+}
+
+func TestRunRatchetCommand(t *testing.T) {
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.Local)
+	losedate := time.Date(2024, 1, 15, 23, 59, 0, 0, time.Local).Unix()
+	var ratcheted []int
+	client := &FakeClient{
+		FetchGoalFunc: func(slug string) (*Goal, error) {
+			return &Goal{Slug: slug, Safebuf: 5, Losedate: losedate}, nil
+		},
+		RatchetGoalFunc: func(slug string, days int) (*Goal, error) {
+			ratcheted = append(ratcheted, days)
+			return &Goal{Slug: slug, Safebuf: days}, nil
+		},
+	}
+	run := func(req ratchetRequest, stdin string) (int, string, string) {
+		var out, errOut bytes.Buffer
+		code := runRatchetCommand(context.Background(), client, req, now, strings.NewReader(stdin), &out, &errOut)
+		return code, out.String(), errOut.String()
+	}
+
+	code, out, errOut := run(ratchetRequest{goalSlug: "reading", days: 2, dryRun: true, skipConfirm: true}, "")
+	checkResult(t, code, out, errOut, 0, "Ratcheting reading from 5 to 2 days of safety buffer would move its deadline from Jan 15 11:59 PM to Jan 12 11:59 PM.\n", "")
+	code, out, errOut = run(ratchetRequest{goalSlug: "reading", days: 7, dryRun: true}, "")
+	checkResult(t, code, out, errOut, 0, "reading already has 5 days of safety buffer, which is at or below 7 days, so ratcheting would leave it due Jan 15 11:59 PM.\n", "")
+	if len(ratcheted) != 0 {
+		t.Errorf("dry run ratcheted %v", ratcheted)
+	}
+
+	code, out, errOut = run(ratchetRequest{goalSlug: "reading", days: 2}, "n\n")
+	checkResult(t, code, out, errOut, 0, "", "")
+	if !strings.HasSuffix(out, "Cancelled.\n") || len(ratcheted) != 0 {
+		t.Errorf("declined ratchet: out %q, ratcheted %v", out, ratcheted)
+	}
+
+	code, out, errOut = run(ratchetRequest{goalSlug: "reading", days: 2}, "y\n")
+	checkResult(t, code, out, errOut, 0, "", "")
+	if !strings.HasPrefix(out, "Ratchet reading from 5 to at most 2 days") || !strings.HasSuffix(out, "Ratcheted reading to 2 days of safety buffer.\n") {
+		t.Errorf("confirmed ratchet: out %q", out)
+	}
+
+	// EOF with no answer declines; a "y" without a newline confirms.
+	ratcheted = nil
+	code, out, errOut = run(ratchetRequest{goalSlug: "reading", days: 2}, "")
+	checkResult(t, code, out, errOut, 0, "", "")
+	if !strings.HasSuffix(out, "Cancelled.\n") || len(ratcheted) != 0 {
+		t.Errorf("ratchet at EOF: out %q, ratcheted %v", out, ratcheted)
+	}
+	code, out, errOut = run(ratchetRequest{goalSlug: "reading", days: 2}, "y")
+	checkResult(t, code, out, errOut, 0, "", "")
+	if !slices.Equal(ratcheted, []int{2}) {
+		t.Errorf("ratchet on a piped y: out %q, ratcheted %v", out, ratcheted)
+	}
+
+	code, out, errOut = run(ratchetRequest{goalSlug: "reading", days: 0, skipConfirm: true}, "")
+	checkResult(t, code, out, errOut, 0, "Ratcheted reading to 0 days of safety buffer.\n", "")
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+const renameUsage = `Usage: buzz rename [--yes|-y] <old-slug> <new-slug>`
+
+// renameRequest is a parsed, validated `buzz rename` invocation.
+type renameRequest struct {
+	oldSlug     string
+	newSlug     string
+	skipConfirm bool
+}
+
+// handleRenameCommand changes a goal's slug and carries local state keyed by
+// the old slug over to the new one.
+func handleRenameCommand() {
+	req, code, done := parseRenameArgs(os.Args[2:], os.Stdout, os.Stderr)
+	if done {
+		os.Exit(code)
+	}
+
+	client, ok := loadClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+
+	code = runRenameCommand(req, os.Stdin, client, os.Stdout, os.Stderr)
+	if code == 0 {
+		fmt.Print(getUpdateMessage())
+	}
+	os.Exit(code)
+}
+
+// parseRenameArgs parses and validates `buzz rename` arguments, returning the
+// request, a process exit code, and done=true when the caller should stop
+// (help shown, or a parse/validation error).
+func parseRenameArgs(args []string, stdout, stderr io.Writer) (renameRequest, int, bool) {
+	renameFlags := flag.NewFlagSet("rename", flag.ContinueOnError)
+	// Silence the flag package's own output; we print our own usage.
+	renameFlags.SetOutput(io.Discard)
+	yes := renameFlags.Bool("yes", false, "Skip confirmation prompt")
+	yesShort := renameFlags.Bool("y", false, "Skip confirmation prompt (shorthand)")
+	if err := renameFlags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stdout, renameUsage)
+			return renameRequest{}, 0, true
+		}
+		fmt.Fprintf(stderr, "Error parsing flags: %s\n", redactError(err))
+		fmt.Fprintln(stderr, renameUsage)
+		return renameRequest{}, 2, true
+	}
+
+	rest := renameFlags.Args()
+	if len(rest) != 2 {
+		fmt.Fprintln(stderr, "Error: Missing required arguments")
+		fmt.Fprintln(stderr, renameUsage)
+		return renameRequest{}, 1, true
+	}
+	if rest[0] == rest[1] {
+		fmt.Fprintf(stderr, "Error: %s is already the goal's slug\n", rest[0])
+		return renameRequest{}, 1, true
+	}
+
+	return renameRequest{
+		oldSlug:     rest[0],
+		newSlug:     rest[1],
+		skipConfirm: *yes || *yesShort,
+	}, 0, false
+}
+
+// runRenameCommand renames the goal, prompting for confirmation on stdin unless
+// skipConfirm is set, then updates local state. It returns the process exit
+// code; a failure to update local state after a successful rename is reported
+// but doesn't fail the command, since the rename itself went through.
+func runRenameCommand(req renameRequest, stdin io.Reader, client Client, stdout, stderr io.Writer) int {
+	if !req.skipConfirm {
+		// Renaming changes the goal's URL, which breaks bookmarks and any
+		// integrations that post to the old slug, so ask first.
+		fmt.Fprintf(stdout, "Rename %s to %s? Links and integrations using the old slug will stop working. [y/N] ", req.oldSlug, req.newSlug)
+		line, err := bufio.NewReader(stdin).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			fmt.Fprintln(stdout, "Cancelled.")
+			return 0
+		}
+		response := strings.TrimSpace(strings.ToLower(line))
+		if response != "y" && response != "yes" {
+			fmt.Fprintln(stdout, "Cancelled.")
+			return 0
+		}
+	}
+
+	goal, err := client.RenameGoal(context.Background(), req.oldSlug, req.newSlug)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to rename goal: %s\n", redactError(err))
+		return 1
+	}
+	fmt.Fprintf(stdout, "Renamed %s to %s\n", req.oldSlug, goal.Slug)
+	renameLocalState(req.oldSlug, goal.Slug, stdout, stderr)
+	return 0
+}
+
+// renameLocalState carries what buzz keeps on disk about oldSlug over to
+// newSlug: saved filters and groups in ~/.buzzrc, the slug cache, delta-mode
+// word counts, and the add `buzz undo` would remove. Failures are warnings,
+// since the rename itself went through.
+func renameLocalState(oldSlug, newSlug string, stdout, stderr io.Writer) {
+	if ConfigExists() {
+		renameSlugInConfigFile(oldSlug, newSlug, stdout, stderr)
+	}
+	if err := renameSlugInSlugCache(oldSlug, newSlug); err != nil {
+		fmt.Fprintf(stderr, "Warning: failed to update the slug cache: %v\n", err)
+	}
+	if err := renameSlugInWordcountState(oldSlug, newSlug); err != nil {
+		fmt.Fprintf(stderr, "Warning: failed to update word count state, the next delta-mode count starts over: %v\n", err)
+	}
+	if err := renameSlugInLastAdds(oldSlug, newSlug); err != nil {
+		fmt.Fprintf(stderr, "Warning: failed to update the last add, `buzz undo %s` won't find it: %v\n", newSlug, err)
+	}
+}
+
+// renameSlugInConfigFile updates ~/.buzzrc's saved filters and groups, and
+// says which changed.
+func renameSlugInConfigFile(oldSlug, newSlug string, stdout, stderr io.Writer) {
+	var filters, groups []string
+	err := updateConfig(func(config *Config) error {
+		filters, groups = renameSlugInConfig(config, oldSlug, newSlug)
+		if len(filters) == 0 && len(groups) == 0 {
+			return errUnchanged
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "Warning: failed to update config, saved filters and groups were not updated: %v\n", err)
+		return
+	}
+	if len(filters) > 0 {
+		fmt.Fprintf(stdout, "Updated saved filters: %s\n", strings.Join(filters, ", "))
+	}
+	if len(groups) > 0 {
+		fmt.Fprintf(stdout, "Updated groups: %s\n", strings.Join(groups, ", "))
+	}
+}
+
+// renameSlugInConfig points config's saved filters at newSlug wherever they
+// compare against oldSlug, and puts newSlug in place of oldSlug in its
+// groups. It returns the names of the filters and of the groups it changed,
+// each in sorted order.
+func renameSlugInConfig(config *Config, oldSlug, newSlug string) (filters, groups []string) {
+	for _, name := range filterNames(config) {
+		if expr, ok := renameFilterSlug(config.Filters[name], oldSlug, newSlug); ok {
+			config.Filters[name] = expr
+			filters = append(filters, name)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(config.Groups)) {
+		members := config.Groups[name]
+		i := slices.Index(members, oldSlug)
+		if i < 0 {
+			continue
+		}
+		if slices.Contains(members, newSlug) {
+			config.Groups[name] = slices.Delete(members, i, i+1)
+		} else {
+			members[i] = newSlug
+		}
+		groups = append(groups, name)
+	}
+	return filters, groups
+}
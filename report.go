@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+)
+
+const reportUsage = `Usage: buzz report --unused [--no-prompt]
+  --unused     List goals that look unused: candidates for archiving
+  --no-prompt  Just list them, without asking about each one`
+
+// Thresholds for `buzz report --unused`. A goal is a candidate when at least
+// unusedMinSignals of these hold.
+const (
+	// unusedMaxMonthlyRate: the bright line climbs less than this many goal
+	// units a month (or not at all).
+	unusedMaxMonthlyRate = 1.0
+	// unusedMinSafebuf: more safety buffer than this many days, far beyond
+	// the 7 that turns a goal gray.
+	unusedMinSafebuf = 30
+	// unusedStaleAfter: no datapoint for this long on a goal without autodata.
+	unusedStaleAfter = 90 * 24 * time.Hour
+	unusedMinSignals = 2
+)
+
+// unusedGoal is a goal `buzz report --unused` suggests archiving, with why.
+type unusedGoal struct {
+	goal    Goal
+	reasons []string
+}
+
+// unusedReasons lists the signs that a goal is no longer really in use.
+func unusedReasons(g Goal, now time.Time) []string {
+	var reasons []string
+	if g.Rate == nil || *g.Rate == 0 {
+		reasons = append(reasons, "flat bright red line")
+	} else if isKnownRunits(g.Runits) {
+		if perMonth := ratePerDay(*g.Rate, g.Runits) * 30; perMonth > -unusedMaxMonthlyRate && perMonth < unusedMaxMonthlyRate {
+			reasons = append(reasons, fmt.Sprintf("rate of %s", formatRate(*g.Rate, g.Runits, g.Gunits)))
+		}
+	}
+	if g.Safebuf > unusedMinSafebuf {
+		reasons = append(reasons, fmt.Sprintf("%d days of buffer", g.Safebuf))
+	}
+	if !isAutodataGoal(g) {
+		if g.Lastday == 0 {
+			reasons = append(reasons, "no datapoints")
+		} else if age := now.Sub(time.Unix(g.Lastday, 0)); age > unusedStaleAfter {
+			reasons = append(reasons, fmt.Sprintf("no datapoints in %d days", int(age/(24*time.Hour))))
+		}
+	}
+	return reasons
+}
+
+// findUnusedGoals returns the goals showing at least unusedMinSignals signs of
+// disuse, in slug order.
+func findUnusedGoals(goals []Goal, now time.Time) []unusedGoal {
+	sorted := append([]Goal(nil), goals...)
+	SortGoalsBySlug(sorted)
+	var unused []unusedGoal
+	for _, g := range sorted {
+		if reasons := unusedReasons(g, now); len(reasons) >= unusedMinSignals {
+			unused = append(unused, unusedGoal{goal: g, reasons: reasons})
+		}
+	}
+	return unused
+}
+
+// handleReportCommand prints a report about the user's goals.
+func handleReportCommand() {
+	prompt, code, done := parseReportArgs(os.Args[2:], os.Stdout, os.Stderr)
+	if done {
+		os.Exit(code)
+	}
+	client, ok := loadClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+	// loadClient just loaded the config successfully.
+	config, _ := LoadConfig()
+
+	// Only ask about each goal when someone is at the terminal to answer.
+	var stdin io.Reader
+	if prompt && outputFormat == "table" && term.IsTerminal(os.Stdin.Fd()) && term.IsTerminal(os.Stdout.Fd()) {
+		stdin = os.Stdin
+	}
+	archive := func(slug string) error { return openBrowser(config, slug, false, os.Stdout) }
+
+	code = runUnusedReport(context.Background(), client, goalFilter, outputFormat, time.Now(), stdin, archive, os.Stdout, os.Stderr)
+	if code == 0 && outputFormat == "table" {
+		fmt.Print(getUpdateMessage())
+	}
+	os.Exit(code)
+}
+
+// parseReportArgs parses `buzz report` arguments, returning whether to prompt
+// about each goal, a process exit code, and done=true when the caller should
+// stop (help shown, or a parse/validation error).
+func parseReportArgs(args []string, stdout, stderr io.Writer) (bool, int, bool) {
+	reportFlags := flag.NewFlagSet("report", flag.ContinueOnError)
+	// Silence the flag package's own output; we print our own usage.
+	reportFlags.SetOutput(io.Discard)
+	unused := reportFlags.Bool("unused", false, "List goals that look unused")
+	noPrompt := reportFlags.Bool("no-prompt", false, "Don't ask about each goal")
+	if err := reportFlags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stdout, reportUsage)
+			return false, 0, true
+		}
+		fmt.Fprintf(stderr, "Error parsing flags: %s\n", err)
+		fmt.Fprintln(stderr, reportUsage)
+		return false, 2, true
+	}
+	if reportFlags.NArg() > 0 {
+		fmt.Fprintf(stderr, "Error: unexpected argument %q\n", reportFlags.Arg(0))
+		fmt.Fprintln(stderr, reportUsage)
+		return false, 1, true
+	}
+	if !*unused {
+		fmt.Fprintln(stderr, "Error: Choose a report: --unused")
+		fmt.Fprintln(stderr, reportUsage)
+		return false, 1, true
+	}
+	return !*noPrompt, 0, false
+}
+
+// runUnusedReport lists the goals that look unused. With stdin non-nil it then
+// asks about each one, calling archive for those the user says yes to.
+// Beeminder's API can't archive goals, so archive opens the goal's page, where
+// archiving is a click away. It returns the process exit code.
+func runUnusedReport(ctx context.Context, client Client, keep func(Goal) bool, format string, now time.Time, stdin io.Reader, archive func(slug string) error, out, errOut io.Writer) int {
+	goals, err := client.FetchGoals(ctx)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: Failed to fetch goals: %s\n", redactError(err))
+		return 1
+	}
+	unused := findUnusedGoals(keepGoals(goals, keep), now)
+
+	reasonsFor := make(map[string]string, len(unused))
+	candidates := make([]Goal, len(unused))
+	for i, u := range unused {
+		candidates[i] = u.goal
+		reasonsFor[u.goal.Slug] = strings.Join(u.reasons, ", ")
+	}
+	table := Table{
+		Columns: []Column{
+			{Header: "Slug", Cell: func(g Goal) string { return g.Slug }},
+			{Header: "Reasons", Cell: func(g Goal) string { return reasonsFor[g.Slug] }},
+		},
+	}
+
+	// Machine-readable formats: emit just the goals, like the list commands.
+	if format == "jsonl" {
+		if err := writeJSONL(out, candidates); err != nil {
+			fmt.Fprintf(errOut, "Error: %s\n", redactError(err))
+			return 1
+		}
+		return 0
+	}
+	if format != "table" {
+		rendered, err := table.RenderAs(format, candidates)
+		if err != nil {
+			fmt.Fprintf(errOut, "Error: %s\n", redactError(err))
+			return 1
+		}
+		fmt.Fprint(out, rendered)
+		return 0
+	}
+
+	if len(candidates) == 0 {
+		fmt.Fprintln(out, "No unused goals found.")
+		return 0
+	}
+	fmt.Fprintf(out, "Goals that look unused (candidates for archiving): %d\n\n", len(candidates))
+	fmt.Fprint(out, table.Render(candidates))
+	if stdin == nil {
+		return 0
+	}
+
+	fmt.Fprintln(out, "\nBeeminder's API can't archive goals, so yes opens the goal on beeminder.com to archive it there.")
+	reader := bufio.NewReader(stdin)
+	for _, g := range candidates {
+		fmt.Fprintf(out, "Archive %s now? [y/N/q] ", g.Slug)
+		line, err := reader.ReadString('\n')
+		answer := strings.ToLower(strings.TrimSpace(line))
+		if answer == "q" || (err != nil && answer == "") {
+			if err != nil {
+				fmt.Fprintln(out)
+			}
+			return 0
+		}
+		if answer != "y" && answer != "yes" {
+			continue
+		}
+		if err := archive(g.Slug); err != nil {
+			fmt.Fprintf(errOut, "Error: Failed to open %s: %s\n", g.Slug, err)
+		}
+	}
+	return 0
+}
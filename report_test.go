@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func reportTestGoals(now time.Time) []Goal {
+	tiny, normal := 0.1, 5.0
+	old := now.AddDate(0, -6, 0).Unix()
+	recent := now.AddDate(0, 0, -1).Unix()
+	return []Goal{
+		// Flat, 60 days of buffer, and untouched for half a year.
+		{Slug: "guitar", Rate: nil, Safebuf: 60, Lastday: old},
+		// A trickle of a rate and lots of buffer, but still logged.
+		{Slug: "letters", Rate: &tiny, Runits: "m", Gunits: "letters", Safebuf: 45, Lastday: recent},
+		// Busy.
+		{Slug: "pushups", Rate: &normal, Runits: "d", Safebuf: 2, Lastday: recent},
+		// Autodata is exempt from the staleness signal, leaving only one.
+		{Slug: "steps", Rate: &normal, Runits: "d", Safebuf: 40, Lastday: old, Autodata: "fitbit"},
+	}
+}
+
+func TestFindUnusedGoals(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	unused := findUnusedGoals(reportTestGoals(now), now)
+	if len(unused) != 2 || unused[0].goal.Slug != "guitar" || unused[1].goal.Slug != "letters" {
+		t.Fatalf("unused = %+v, want guitar and letters", unused)
+	}
+	if got := strings.Join(unused[0].reasons, ", "); got != "flat bright red line, 60 days of buffer, no datapoints in 181 days" {
+		t.Errorf("guitar reasons = %q", got)
+	}
+	if got := strings.Join(unused[1].reasons, ", "); got != "rate of 0.1 letters / month, 45 days of buffer" {
+		t.Errorf("letters reasons = %q", got)
+	}
+}
+
+func TestParseReportArgs(t *testing.T) {
+	var out, errb bytes.Buffer
+	if prompt, code, done := parseReportArgs([]string{"--unused"}, &out, &errb); !prompt || code != 0 || done {
+		t.Errorf("--unused: prompt=%v code=%d done=%v", prompt, code, done)
+	}
+	if prompt, _, done := parseReportArgs([]string{"--unused", "--no-prompt"}, &out, &errb); prompt || done {
+		t.Errorf("--no-prompt: prompt=%v done=%v", prompt, done)
+	}
+	if _, code, done := parseReportArgs(nil, &out, &errb); !done || code != 1 || !strings.Contains(errb.String(), "Choose a report") {
+		t.Errorf("no report: code=%d done=%v stderr=%q", code, done, errb.String())
+	}
+}
+
+func TestRunUnusedReport(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	client := &FakeClient{FetchGoalsFunc: func() ([]Goal, error) { return reportTestGoals(now), nil }}
+
+	t.Run("asks about each goal", func(t *testing.T) {
+		var opened []string
+		archive := func(slug string) error { opened = append(opened, slug); return nil }
+		var out, errb bytes.Buffer
+		code := runUnusedReport(context.Background(), client, nil, "table", now, strings.NewReader("y\nn\n"), archive, &out, &errb)
+		checkResult(t, code, out.String(), errb.String(), 0, "candidates for archiving): 2", "")
+		if strings.Join(opened, " ") != "guitar" {
+			t.Errorf("opened %v, want [guitar]", opened)
+		}
+		if !strings.Contains(out.String(), "Archive letters now? [y/N/q]") {
+			t.Errorf("no prompt for letters:\n%s", out.String())
+		}
+	})
+
+	t.Run("q stops asking", func(t *testing.T) {
+		var opened []string
+		archive := func(slug string) error { opened = append(opened, slug); return nil }
+		var out, errb bytes.Buffer
+		code := runUnusedReport(context.Background(), client, nil, "table", now, strings.NewReader("q\ny\n"), archive, &out, &errb)
+		checkResult(t, code, out.String(), errb.String(), 0, "Archive guitar now?", "")
+		if len(opened) != 0 || strings.Contains(out.String(), "Archive letters") {
+			t.Errorf("kept going after q: opened %v\n%s", opened, out.String())
+		}
+	})
+
+	t.Run("no prompt without a terminal", func(t *testing.T) {
+		var out, errb bytes.Buffer
+		code := runUnusedReport(context.Background(), client, nil, "table", now, nil, nil, &out, &errb)
+		checkResult(t, code, out.String(), errb.String(), 0, "guitar", "")
+		if strings.Contains(out.String(), "now?") {
+			t.Errorf("prompted without stdin:\n%s", out.String())
+		}
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var out, errb bytes.Buffer
+		code := runUnusedReport(context.Background(), client, nil, "csv", now, nil, nil, &out, &errb)
+		checkResult(t, code, out.String(), errb.String(), 0, "Slug,Reasons\nguitar,", "")
+	})
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// weekStart returns midnight on the Monday of the week offset weeks from the
+// one containing now: 0 for this week, -1 for last week, and so on.
+func weekStart(now time.Time, offset int) time.Time {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	back := (int(day.Weekday()) + 6) % 7 // days since Monday
+	return day.AddDate(0, 0, 7*offset-back)
+}
+
+// weekGoal is one goal's line in a weekly report.
+type weekGoal struct {
+	slug       string
+	datapoints int
+	total      float64
+}
+
+// weekSummary is a week of datapoints: how many were entered each day, and
+// per goal.
+type weekSummary struct {
+	start time.Time
+	days  [7]int     // datapoints per day, Monday first
+	goals []weekGoal // most datapoints first, then by slug
+}
+
+// summarizeWeek summarizes the feed entries whose daystamps fall in the week
+// beginning start.
+func summarizeWeek(entries []activityEntry, start time.Time) weekSummary {
+	w := weekSummary{start: start}
+	daystamps := make(map[string]int, 7)
+	for i := range 7 {
+		daystamps[start.AddDate(0, 0, i).Format("20060102")] = i
+	}
+	byGoal := map[string]*weekGoal{}
+	for _, e := range entries {
+		i, ok := daystamps[e.dp.Daystamp]
+		if !ok {
+			continue
+		}
+		w.days[i]++
+		g := byGoal[e.slug]
+		if g == nil {
+			g = &weekGoal{slug: e.slug}
+			byGoal[e.slug] = g
+		}
+		g.datapoints++
+		g.total += e.dp.Value
+	}
+	for _, g := range byGoal {
+		w.goals = append(w.goals, *g)
+	}
+	sort.Slice(w.goals, func(i, j int) bool {
+		if w.goals[i].datapoints != w.goals[j].datapoints {
+			return w.goals[i].datapoints > w.goals[j].datapoints
+		}
+		return w.goals[i].slug < w.goals[j].slug
+	})
+	return w
+}
+
+// renderWeekSummary draws a week's datapoints per day as bars, scaled like
+// `buzz buffer`, then each goal's datapoint count and total.
+func renderWeekSummary(w weekSummary) string {
+	total, largest := 0, 0
+	for _, n := range w.days {
+		total += n
+		largest = max(largest, n)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Datapoints by day (%d in all):\n", total)
+	bar := lipgloss.NewStyle().Foreground(lipgloss.Color("39"))
+	for i, n := range w.days {
+		length := 0
+		if largest > 0 {
+			length = n * bufferBarWidth / largest
+		}
+		if n > 0 && length == 0 {
+			length = 1
+		}
+		fmt.Fprintf(&sb, "  %s  %s %d\n", w.start.AddDate(0, 0, i).Format("Mon Jan 2"), bar.Render(strings.Repeat("█", length)), n)
+	}
+
+	if len(w.goals) > 0 {
+		slugWidth := 0
+		for _, g := range w.goals {
+			slugWidth = max(slugWidth, len(g.slug))
+		}
+		sb.WriteString("\nBy goal:\n")
+		for _, g := range w.goals {
+			fmt.Fprintf(&sb, "  %-*s  %3d  total %.6g\n", slugWidth, g.slug, g.datapoints, g.total)
+		}
+	}
+	return sb.String()
+}
+
+// reportsLoadedMsg carries the datapoints fetched for the Reports tab, from
+// since onwards.
+type reportsLoadedMsg struct {
+	entries []activityEntry
+	since   time.Time
+	err     error
+}
+
+// loadReportsCmd fetches the datapoints from since onwards across all goals.
+func loadReportsCmd(ctx context.Context, client Client, since time.Time) tea.Cmd {
+	return func() tea.Msg {
+		entries, err := fetchActivity(ctx, client, since)
+		return reportsLoadedMsg{entries: entries, since: since, err: err}
+	}
+}
+
+// reportsModel is the Reports tab: a weekly report of datapoints, navigable
+// by week, above the safety-buffer histogram of the goals the grid shows.
+type reportsModel struct {
+	ctx     context.Context
+	client  Client
+	goals   []Goal          // the grid's goals, for the buffer histogram
+	entries []activityEntry // datapoints from since onwards
+	since   time.Time       // how far back entries reaches
+	week    int             // the week shown: 0 for this week, -1 for last week, ...
+	loading bool            // a fetch is in flight
+	loaded  bool            // entries holds a fetched feed
+	err     error           // the last fetch failed
+	offset  int             // first visible line of the report
+	width   int
+	height  int
+}
+
+// refresh starts a fetch of the datapoints back to the start of the week
+// shown, unless one is already in flight.
+func (m *reportsModel) refresh() tea.Cmd {
+	if m.loading {
+		return nil
+	}
+	m.loading = true
+	return loadReportsCmd(m.ctx, m.client, weekStart(time.Now(), m.week))
+}
+
+// showWeek moves to another week, fetching further back if the datapoints
+// loaded so far don't reach it.
+func (m *reportsModel) showWeek(week int) tea.Cmd {
+	m.week = min(0, week)
+	m.offset = 0
+	if m.loaded && !weekStart(time.Now(), m.week).Before(m.since) {
+		return nil
+	}
+	return m.refresh()
+}
+
+// rows is how many report lines fit under the header and above the help line.
+func (m reportsModel) rows() int {
+	return max(1, m.height-3)
+}
+
+// scroll moves the view by delta lines, clamped to the report.
+func (m *reportsModel) scroll(delta int) {
+	lines := strings.Count(m.report(), "\n") + 1
+	m.offset = max(0, min(m.offset+delta, lines-m.rows()))
+}
+
+// report renders the body of the tab: the week shown, the buffer histogram,
+// and the goal groups' rollups.
+func (m reportsModel) report() string {
+	week := summarizeWeek(m.entries, weekStart(time.Now(), m.week))
+	report := renderWeekSummary(week) + "\n" + renderBufferHistogram(bufferHistogram(m.goals))
+	if rollups := renderGroupRollups(groupRollups(m.goals, time.Now())); rollups != "" {
+		report += "\n" + rollups
+	}
+	return report
+}
+
+func (m reportsModel) Update(msg tea.Msg) (reportsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.scroll(0)
+
+	case reportsLoadedMsg:
+		m.loading = false
+		// A failed refetch keeps showing the last good report with the error.
+		m.err = msg.err
+		if msg.err == nil {
+			m.entries = msg.entries
+			m.since = msg.since
+			m.loaded = true
+			// The user may have gone back further while this was in flight.
+			if weekStart(time.Now(), m.week).Before(m.since) {
+				return m, m.refresh()
+			}
+		}
+
+	case tea.MouseMsg:
+		switch msg.Button {
+		case tea.MouseButtonWheelUp:
+			m.scroll(-3)
+		case tea.MouseButtonWheelDown:
+			m.scroll(3)
+		}
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "left", "h", "p":
+			return m, m.showWeek(m.week - 1)
+		case "right", "l", "n":
+			return m, m.showWeek(m.week + 1)
+		case "t":
+			return m, m.showWeek(0)
+		case "up", "k":
+			m.scroll(-1)
+		case "down", "j":
+			m.scroll(1)
+		case "pgup":
+			m.scroll(-m.rows())
+		case "pgdown", "d":
+			m.scroll(m.rows())
+		case "r":
+			return m, m.refresh()
+		}
+	}
+	return m, nil
+}
+
+func (m reportsModel) View() string {
+	start := weekStart(time.Now(), m.week)
+	when := "this week"
+	switch {
+	case m.week == -1:
+		when = "last week"
+	case m.week < -1:
+		when = fmt.Sprintf("%d weeks ago", -m.week)
+	}
+	header := lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("Week of %s – %s", start.Format("Mon Jan 2"), start.AddDate(0, 0, 6).Format("Mon Jan 2, 2006"))) +
+		"  " + when
+	help := lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(
+		"Week: ← → (or h l, or p n)  |  This week: t  |  Scroll: ↑ ↓ PgUp PgDn  |  Refresh: r  |  Goals: Esc  |  Quit: q")
+
+	var body string
+	switch {
+	case m.err != nil && !m.loaded:
+		body = UrgencyOverdue.TextStyle().Render(fmt.Sprintf("Failed to load datapoints: %s", redactError(m.err)))
+	case !m.loaded || (m.loading && start.Before(m.since)):
+		body = "Loading datapoints..."
+	default:
+		lines := strings.Split(strings.TrimRight(m.report(), "\n"), "\n")
+		body = strings.Join(lines[min(m.offset, len(lines)):min(len(lines), m.offset+m.rows())], "\n")
+	}
+	if m.err != nil && m.loaded {
+		help = UrgencyOverdue.TextStyle().Render(fmt.Sprintf("Refresh failed: %s", redactError(m.err))) + "  " + help
+	}
+
+	// Pad the body so the help line stays at the bottom.
+	if pad := m.rows() - lipgloss.Height(body); pad > 0 {
+		body += strings.Repeat("\n", pad)
+	}
+	return header + "\n" + body + "\n" + help
+}
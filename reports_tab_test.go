@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestWeekStart(t *testing.T) {
+	tests := []struct {
+		now    time.Time
+		offset int
+		want   string
+	}{
+		{time.Date(2026, 10, 16, 15, 4, 0, 0, time.UTC), 0, "2026-10-12"}, // Friday
+		{time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC), 0, "2026-10-12"},  // Monday
+		{time.Date(2026, 10, 18, 23, 0, 0, 0, time.UTC), 0, "2026-10-12"}, // Sunday
+		{time.Date(2026, 10, 16, 15, 4, 0, 0, time.UTC), -1, "2026-10-05"},
+		{time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC), -1, "2025-12-22"},
+	}
+	for _, tt := range tests {
+		if got := weekStart(tt.now, tt.offset).Format("2006-01-02"); got != tt.want {
+			t.Errorf("weekStart(%v, %d) = %s, want %s", tt.now, tt.offset, got, tt.want)
+		}
+	}
+}
+
+func TestSummarizeWeek(t *testing.T) {
+	start := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+	w := summarizeWeek([]activityEntry{
+		{slug: "write", dp: Datapoint{Daystamp: "20261012", Value: 500}},
+		{slug: "read", dp: Datapoint{Daystamp: "20261014", Value: 10}},
+		{slug: "write", dp: Datapoint{Daystamp: "20261014", Value: 250}},
+		{slug: "read", dp: Datapoint{Daystamp: "20261018", Value: 5}},
+		{slug: "read", dp: Datapoint{Daystamp: "20261011", Value: 99}}, // the week before
+	}, start)
+
+	if w.days != [7]int{1, 0, 2, 0, 0, 0, 1} {
+		t.Errorf("days = %v", w.days)
+	}
+	want := []weekGoal{{slug: "read", datapoints: 2, total: 15}, {slug: "write", datapoints: 2, total: 750}}
+	if len(w.goals) != len(want) || w.goals[0] != want[0] || w.goals[1] != want[1] {
+		t.Errorf("goals = %+v, want %+v", w.goals, want)
+	}
+
+	report := renderWeekSummary(w)
+	for _, line := range []string{"Datapoints by day (4 in all):", "Mon Oct 12", "Sun Oct 18", "read     2  total 15", "write    2  total 750"} {
+		if !strings.Contains(report, line) {
+			t.Errorf("report lacks %q:\n%s", line, report)
+		}
+	}
+}
+
+func TestReportsTab(t *testing.T) {
+	now := time.Now()
+	today := now.Format("20060102")
+	var since []time.Time
+	client := &FakeClient{
+		FetchDatapointsSinceFunc: func(s time.Time) ([]Goal, error) {
+			since = append(since, s)
+			return []Goal{{Slug: "read", Datapoints: []Datapoint{{Timestamp: now.Unix(), Daystamp: today, Value: 12}}}}, nil
+		},
+	}
+	m := model{state: "app", appModel: appModel{
+		ctx:    context.Background(),
+		client: client,
+		config: &Config{Username: "u"},
+		goals:  []Goal{{Slug: "read", Safebuf: 0}, {Slug: "write", Safebuf: 9}},
+		width:  100,
+		height: 40,
+	}}
+
+	tm, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("4")})
+	m = mustModel(t, tm)
+	if m.appModel.tab != tabReports || cmd == nil {
+		t.Fatalf("tab = %v, cmd = %v", m.appModel.tab, cmd)
+	}
+	m = mustModel(t, mustTeaModel(m.Update(cmd())))
+	view := m.View()
+	for _, want := range []string{"this week", "Datapoints by day (1 in all):", "Safety buffer across 2 goals:"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view lacks %q:\n%s", want, view)
+		}
+	}
+
+	// Going back a week fetches from its start; coming back doesn't refetch.
+	tm, cmd = m.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	m = mustModel(t, tm)
+	if cmd == nil {
+		t.Fatal("going back a week should fetch its datapoints")
+	}
+	m = mustModel(t, mustTeaModel(m.Update(cmd())))
+	if len(since) != 2 || !since[1].Equal(weekStart(now, -1)) {
+		t.Errorf("fetched since %v, want last week's start last", since)
+	}
+	if view := m.View(); !strings.Contains(view, "last week") || !strings.Contains(view, "Datapoints by day (0 in all):") {
+		t.Errorf("last week's view:\n%s", view)
+	}
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRight}); cmd != nil {
+		t.Error("moving forward a week should use the datapoints already loaded")
+	}
+}
@@ -3,11 +3,12 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"math"
 	"net/url"
 	"os"
 	"os/exec"
-	"runtime"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -18,50 +19,31 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
-// handleReviewCommand launches an interactive review of all goals
+// handleReviewCommand opens the TUI on its Review tab, which steps through
+// every goal in slug order; the other tabs are a key away.
 func handleReviewCommand() {
 	// Load config
 	if !ConfigExists() {
 		fmt.Fprintln(os.Stderr, "Error: No configuration found. Please run 'buzz auth login' to authenticate.")
 		os.Exit(1)
 	}
-
-	config, err := LoadConfig()
-	if err != nil {
+	if _, err := LoadConfig(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: Failed to load config: %s\n", redactError(err))
 		os.Exit(1)
 	}
 
-	client := NewHTTPClient(config)
-
-	// Fetch just the goal list (one request) so the TUI opens immediately. Each
-	// goal's datapoints and road are loaded lazily on demand as the user views
-	// it (see fetchGoalDetailsCmd), instead of fetching every goal up front —
-	// which took ~50s for accounts with many goals.
-	goals, err := client.FetchGoals(context.Background())
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: Failed to fetch goals: %s\n", redactError(err))
-		os.Exit(1)
-	}
-
-	if len(goals) == 0 {
-		fmt.Println("No goals found.")
-		return
-	}
-
-	// Sort goals alphabetically by slug as specified
-	SortGoalsBySlug(goals)
-
-	// Long-lived context cancelled when the TUI exits, so in-flight lazy detail
-	// fetches don't outlive the program (per the client.go context contract).
+	// Long-lived context cancelled when the TUI exits, so in-flight fetches
+	// don't outlive the program (per the client.go context contract).
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// Launch the interactive review TUI
-	model := initialReviewModel(goals, config)
-	model.client = client // use the client built above; the constructor's default is discarded
-	model.ctx = ctx
-	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion())
+	// The goal list loads first (one request) so the TUI opens immediately.
+	// Each goal's datapoints and road are loaded lazily on demand as the user
+	// views it (see fetchGoalDetailsCmd), instead of fetching every goal up
+	// front — which took ~50s for accounts with many goals.
+	model := initialModel(ctx)
+	model.appModel.tab = tabReview
+	p := tea.NewProgram(model, tea.WithAltScreen(), tea.WithMouseCellMotion(), tea.WithReportFocus())
 	if _, err := p.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %s\n", redactError(err))
 		os.Exit(1)
@@ -81,7 +63,9 @@ type reviewModel struct {
 	width    int                 // terminal width
 	height   int                 // terminal height
 	err      string              // error message to display
+	notice   string              // other message to display, like a goal URL printed instead of opened
 	viewport viewport.Model      // scrollable pane for the goal content (keeps tall goals reachable on short terminals)
+	chart    chartWindow         // the chart's timeframe and zoom, kept from goal to goal
 	ready    bool                // viewport has been sized by a WindowSizeMsg
 }
 
@@ -97,7 +81,7 @@ func initialReviewModel(goals []Goal, config *Config) reviewModel {
 		details:  make(map[string]*Goal),
 		inFlight: make(map[string]struct{}),
 		ctx:      context.Background(), // overridden with a cancellable ctx by handleReviewCommand
-		client:   NewHTTPClient(config),
+		client:   journaling(recordingSlugs(NewHTTPClient(config), config.Username)),
 		config:   config,
 		current:  0,
 		loading:  len(goals) > 0,
@@ -115,6 +99,12 @@ type goalDetailsMsg struct {
 	err  error
 }
 
+// browserClosedMsg reports that a browser the review handed the terminal to
+// (see goalPageCommand) has exited.
+type browserClosedMsg struct {
+	err error
+}
+
 // fetchGoalDetailsCmd fetches one goal's full details (datapoints + road) in the
 // background so the TUI opens immediately and navigation stays responsive. The
 // context lets the fetch be cancelled when the user quits. The fetch goes through
@@ -123,6 +113,7 @@ type goalDetailsMsg struct {
 func fetchGoalDetailsCmd(ctx context.Context, client Client, slug string) tea.Cmd {
 	return func() tea.Msg {
 		goal, err := client.FetchGoalWithDatapoints(ctx, slug)
+		goal.prepareChart()
 		return goalDetailsMsg{slug: slug, goal: goal, err: err}
 	}
 }
@@ -149,6 +140,30 @@ func (m *reviewModel) ensureDetails() tea.Cmd {
 	return fetchGoalDetailsCmd(m.ctx, m.client, slug)
 }
 
+// setGoals replaces the goals under review, staying on the current goal when
+// it's still among them, and returns a command to fetch its details if needed.
+func (m *reviewModel) setGoals(goals []Goal) tea.Cmd {
+	slug := ""
+	if m.current < len(m.goals) {
+		slug = m.goals[m.current].Slug
+	}
+	m.goals = goals
+	m.current = 0
+	for i, g := range goals {
+		if g.Slug == slug {
+			m.current = i
+		}
+		// Details fetched before the goal last changed (a datapoint added,
+		// say) are stale; drop them so they're fetched afresh.
+		if d, ok := m.details[g.Slug]; ok && goalVersion(*d) != goalVersion(g) {
+			delete(m.details, g.Slug)
+		}
+	}
+	cmd := m.ensureDetails()
+	m.refreshContent()
+	return cmd
+}
+
 func (m reviewModel) Init() tea.Cmd {
 	// The constructor already marked goals[0] in-flight; just dispatch its fetch.
 	if len(m.goals) == 0 {
@@ -199,12 +214,17 @@ func (m reviewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if isCurrent {
 			m.loading = false
 			m.err = ""
+			m.notice = ""
 			// Datapoints/chart just arrived for the goal on screen; re-flow the
 			// content pane so they appear (keeping the current scroll position).
 			m.refreshContent()
 		}
 		return m, nil
 
+	case browserClosedMsg:
+		m.setBrowserErr(msg.err)
+		return m, nil
+
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c", "q", "esc":
@@ -216,6 +236,7 @@ func (m reviewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.current++
 			}
 			m.err = ""
+			m.notice = ""
 			cmd := m.ensureDetails()
 			// New goal: re-flow and jump back to the top of the pane.
 			m.refreshContent()
@@ -228,21 +249,44 @@ func (m reviewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.current--
 			}
 			m.err = ""
+			m.notice = ""
 			cmd := m.ensureDetails()
 			m.refreshContent()
 			m.viewport.GotoTop()
 			return m, cmd
 
+		case "t":
+			// Next chart timeframe: the goal's own window, 7/30/90 days, all
+			i := slices.Index(chartTimeframes, m.chart.days)
+			m.chart.days = chartTimeframes[(i+1)%len(chartTimeframes)]
+			m.refreshContent()
+			return m, nil
+
+		case "z":
+			// Zoom the chart's Y axis to the datapoints, or back out
+			m.chart.zoomY = !m.chart.zoomY
+			m.refreshContent()
+			return m, nil
+
 		case "o", "enter":
 			// Open current goal in browser
 			if m.current < len(m.goals) {
 				goal := m.goals[m.current]
-				if err := openBrowser(m.config, goal.Slug); err != nil {
-					m.err = fmt.Sprintf("Failed to open browser: %v", err)
-				} else {
-					m.err = "" // Clear any previous error
+				cmd, custom, err := goalPageCommand(m.config, goal.Slug)
+				if err != nil || printsURLs(m.config, custom) {
+					// No display to open a browser on (say, over SSH), so
+					// show the URL to copy instead.
+					m.err = ""
+					m.notice = "Goal page: " + goalPageURL(m.config, goal.Slug)
+					m.refreshContent()
+					return m, nil
 				}
-				m.refreshContent()
+				if custom {
+					// The user's browser may be a text browser, so hand it
+					// the terminal until it exits.
+					return m, tea.ExecProcess(cmd, func(err error) tea.Msg { return browserClosedMsg{err: err} })
+				}
+				m.setBrowserErr(cmd.Start())
 			}
 			return m, nil
 		}
@@ -306,6 +350,8 @@ func (m reviewModel) contentView() string {
 		goal.Initday = d.Initday
 		goal.Kyoom = d.Kyoom
 		goal.Yaw = d.Yaw
+		goal.series = d.series
+		goal.warnings = d.warnings
 	}
 
 	// Create the goal details view
@@ -356,8 +402,11 @@ func (m reviewModel) contentView() string {
 
 	// Progress chart (datapoints vs. bright red line). Empty when the goal has
 	// no datapoints or none inside the charted window.
-	if chart := renderGoalChart(goal, m.width); chart != "" {
+	if chart := renderGoalChartWindow(goal, m.width, m.chart); chart != "" {
 		view += chart
+	} else if len(goal.Datapoints) > 0 && m.chart.days > 0 {
+		view += lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Padding(0, 2).
+			Render(fmt.Sprintf("\nNo datapoints in the last %d days (t for a longer timeframe).", m.chart.days)) + "\n"
 	}
 
 	// Loading indicator while this goal's datapoints/chart are being fetched.
@@ -368,6 +417,16 @@ func (m reviewModel) contentView() string {
 		view += loadingStyle.Render("Loading datapoints…") + "\n"
 	}
 
+	if m.notice != "" {
+		noticeStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color("241")).
+			Padding(0, 2)
+		if m.width > 0 {
+			noticeStyle = noticeStyle.Width(m.width)
+		}
+		view += noticeStyle.Render(m.notice) + "\n"
+	}
+
 	// Error message section (if any). Errors are free-form (e.g. a full API URL
 	// in a fetch failure), so wrap to the terminal width instead of letting the
 	// line overflow and get cut off. Width includes the horizontal padding.
@@ -392,7 +451,7 @@ func (m reviewModel) helpView() string {
 		Foreground(lipgloss.Color("241")).
 		Padding(1, 2)
 
-	help := "Navigation: ← → (or h l, or j k, or p n)  |  Scroll: ↑ ↓ PgUp PgDn  |  Open in browser: o or Enter  |  Quit: q or Esc"
+	help := "Navigation: ← → (or h l, or j k, or p n)  |  Scroll: ↑ ↓ PgUp PgDn  |  Chart: t timeframe, z zoom  |  Open in browser: o or Enter  |  Goals: Esc  |  Quit: q"
 	// Reserve the indicator's slot whether or not the percentage is shown, so the
 	// help bar keeps a constant width as the user moves between goals that do and
 	// don't overflow (a varying width could shift terminal wrapping on narrow
@@ -405,26 +464,68 @@ func (m reviewModel) helpView() string {
 	return helpStyle.Render(help)
 }
 
-// openBrowser opens the goal page in the default browser
-func openBrowser(config *Config, goalSlug string) error {
-	baseURL := getBaseURL(config)
-	goalURL := fmt.Sprintf("%s/%s/%s", baseURL, url.PathEscape(config.Username), url.PathEscape(goalSlug))
-
-	var cmd *exec.Cmd
-	switch runtime.GOOS {
-	case "darwin":
-		cmd = exec.Command("open", goalURL)
-	case "linux":
-		cmd = exec.Command("xdg-open", goalURL)
-	case "windows":
-		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", goalURL)
-	default:
-		return fmt.Errorf("unsupported platform")
+// setBrowserErr shows why opening the browser failed, or clears the error.
+func (m *reviewModel) setBrowserErr(err error) {
+	if err != nil {
+		m.err = fmt.Sprintf("Failed to open browser: %v", err)
+	} else {
+		m.err = "" // Clear any previous error
 	}
+	m.refreshContent()
+}
 
+// openBrowser opens the goal page, or with goalSlug "" the user's dashboard,
+// in the browser. A browser the user chose
+// (open_command or $BROWSER) runs in the terminal until it exits, so a text
+// browser works; the platform's opener hands off to the desktop and returns.
+// Where there's no browser to open (see printsURLs), or showQR asks for a QR
+// code to scan with a phone, the URL is printed to stdout instead.
+func openBrowser(config *Config, goalSlug string, showQR bool, stdout io.Writer) error {
+	cmd, custom, err := goalPageCommand(config, goalSlug)
+	if err != nil || showQR || printsURLs(config, custom) {
+		return printGoalURL(stdout, goalPageURL(config, goalSlug), showQR)
+	}
+	if custom {
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+		return cmd.Run()
+	}
 	return cmd.Start()
 }
 
+// goalPageCommand returns the command that opens the goal's page, and whether
+// it's one the user chose (see browserCommand).
+func goalPageCommand(config *Config, goalSlug string) (*exec.Cmd, bool, error) {
+	argv, custom, ok := browserCommand(config, goalPageURL(config, goalSlug), exec.LookPath)
+	if !ok {
+		return nil, false, fmt.Errorf("unsupported platform")
+	}
+	return exec.Command(argv[0], argv[1:]...), custom, nil
+}
+
+// goalPageURL returns the URL of the goal's page on Beeminder, or of the
+// user's dashboard when goalSlug is "".
+func goalPageURL(config *Config, goalSlug string) string {
+	if goalSlug == "" {
+		return fmt.Sprintf("%s/%s", getBaseURL(config), url.PathEscape(config.Username))
+	}
+	return fmt.Sprintf("%s/%s/%s", getBaseURL(config), url.PathEscape(config.Username), url.PathEscape(goalSlug))
+}
+
+// printGoalURL prints a goal page's URL, followed by a QR code of it when
+// showQR is set.
+func printGoalURL(w io.Writer, goalURL string, showQR bool) error {
+	fmt.Fprintln(w, goalURL)
+	if !showQR {
+		return nil
+	}
+	code, err := renderQR(goalURL)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(w, code)
+	return nil
+}
+
 // formatRate formats the rate with the appropriate time unit and goal units
 func formatRate(rate float64, runits, gunits string) string {
 	unitName := ""
@@ -539,16 +640,16 @@ func formatGoalDetails(goal *Goal, config *Config, now time.Time) string {
 
 	// Display deadline (formatted timestamp) with same color coding
 	deadlineTime := time.Unix(goal.Losedate, 0)
-	deadlineStr := deadlineTime.Format("Mon Jan 2, 2006 at 3:04 PM MST")
+	deadlineStr := deadlineTime.Format("Mon Jan 2, 2006 at " + clockLayout() + " MST")
 	coloredDeadline := style.Render(deadlineStr)
 	details += fmt.Sprintf("Deadline:    %s\n", coloredDeadline)
 
 	// Display due time (time of day)
 	details += fmt.Sprintf("Due time:    %s\n", formatDueTime(goal.Deadline))
 
-	pledgeDisplay := fmt.Sprintf("$%.2f", goal.Pledge)
+	pledgeDisplay := formatMoney(goal.Pledge, 2)
 	if goal.PledgeCap != nil && *goal.PledgeCap > 0 && *goal.PledgeCap != goal.Pledge {
-		pledgeDisplay = fmt.Sprintf("$%.2f / $%.2f", goal.Pledge, *goal.PledgeCap)
+		pledgeDisplay = formatMoney(goal.Pledge, 2) + " / " + formatMoney(*goal.PledgeCap, 2)
 	}
 	details += fmt.Sprintf("Pledge:      %s\n", pledgeDisplay)
 
@@ -564,7 +665,10 @@ func formatGoalDetails(goal *Goal, config *Config, now time.Time) string {
 
 	// Display autodata only if not empty
 	if goal.Autodata != "" {
-		details += fmt.Sprintf("Autodata:    %s\n", goal.Autodata)
+		details += fmt.Sprintf("Autodata:    %s\n", autodataSourceName(*goal))
+		if warning := autodataWarning(*goal, now); warning != "" {
+			details += fmt.Sprintf("             %s\n", UrgencyOverdue.TextStyle().Render("⚠ "+warning))
+		}
 	}
 
 	// Display fine print if it exists
@@ -572,6 +676,15 @@ func formatGoalDetails(goal *Goal, config *Config, now time.Time) string {
 		details += fmt.Sprintf("Fine print:  %s\n", goal.Fineprint)
 	}
 
+	// Display what couldn't be read from the goal as Beeminder sent it
+	for i, warning := range goalWarnings(*goal) {
+		label := ""
+		if i == 0 {
+			label = "Warnings:"
+		}
+		details += fmt.Sprintf("%-13s%s\n", label, UrgencyOverdue.TextStyle().Render("⚠ "+warning))
+	}
+
 	// Display the next-seven-days "amount due" forecast, when available.
 	details += formatSevenDayForecastAt(goal, now)
 
@@ -198,6 +198,24 @@ func TestReviewModelNavigationForward(t *testing.T) {
 	}
 }
 
+func TestReviewModelPrintsGoalURL(t *testing.T) {
+	goals := []Goal{{Slug: "goal1", Title: "First Goal"}, {Slug: "goal2", Title: "Second Goal"}}
+	config := &Config{Username: "testuser", AuthToken: "testtoken", OpenMode: "print"}
+	m := initialReviewModel(goals, config)
+
+	updatedModel, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("o")})
+	m = updatedModel.(reviewModel)
+	if cmd != nil || m.notice != "Goal page: https://www.beeminder.com/testuser/goal1" {
+		t.Errorf("notice = %q, want the goal's URL", m.notice)
+	}
+
+	// The URL is for the goal it was shown on, so it goes with that goal.
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if m = updatedModel.(reviewModel); m.notice != "" {
+		t.Errorf("notice = %q after moving on, want none", m.notice)
+	}
+}
+
 func TestReviewModelNavigationBackward(t *testing.T) {
 	goals := []Goal{
 		{Slug: "goal1", Title: "First Goal"},
@@ -1760,3 +1778,40 @@ func TestReviewModelNoScrollIndicatorWhenContentFits(t *testing.T) {
 		t.Errorf("expected no scroll indicator when content fits, got: %s", m.helpView())
 	}
 }
+
+func TestReviewModelChartKeys(t *testing.T) {
+	old := time.Now().AddDate(0, 0, -20).Unix()
+	goals := []Goal{{Slug: "a"}, {Slug: "b"}}
+	m := initialReviewModel(goals, &Config{Username: "u"})
+	m.loading = false
+	m.details["a"] = &Goal{Slug: "a", Datapoints: []Datapoint{{Timestamp: old, Value: 1}}}
+
+	press := func(key string) {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = updated.(reviewModel)
+	}
+	press("t")
+	if m.chart.days != 7 {
+		t.Fatalf("t: chart.days = %d, want 7", m.chart.days)
+	}
+	if view := m.View(); !strings.Contains(view, "No datapoints in the last 7 days") {
+		t.Errorf("view lacks the empty-timeframe note:\n%s", view)
+	}
+	press("z")
+	press("t")
+	press("t")
+	press("t")
+	if m.chart != (chartWindow{days: chartAllDays, zoomY: true}) {
+		t.Errorf("chart = %+v, want all data, zoomed", m.chart)
+	}
+	press("t")
+	if m.chart.days != 0 {
+		t.Errorf("t should wrap to the goal's own window, got %d", m.chart.days)
+	}
+
+	// The window is kept from goal to goal.
+	updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if m = updated.(reviewModel); m.current != 1 || !m.chart.zoomY {
+		t.Errorf("after moving on: current = %d, chart = %+v", m.current, m.chart)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import "time"
+
+// Day rollover. The TUI can run for days, but Beeminder's goal data only
+// changes when it's fetched, so at midnight (or a goal's deadline, when its
+// Beeminder day ends) the grid would keep yesterday's framing until the next
+// 5-minute refresh. Instead the 1s refresh-flag poller checks for a rollover,
+// refetches the goals at once, moves an open datapoint form's date along, and
+// badges the goals that became due.
+
+// newlyDueMarker badges goals in the grid that became due since the TUI last
+// looked, until they're opened or stop being due. Two terminal cells wide,
+// like the other markers.
+const newlyDueMarker = "⏰"
+
+// nextRollover returns the first moment after now that the day rolls over:
+// local midnight, or a goal's deadline, where its Beeminder day ends.
+func nextRollover(goals []Goal, now time.Time) time.Time {
+	next := startOfDay(now, now.Location()).AddDate(0, 0, 1)
+	for _, g := range goals {
+		// todayDaystampFor shifts now by the deadline; its day ends a day on.
+		offset := time.Duration(g.Deadline) * time.Second
+		end := startOfDay(now.Add(-offset), now.Location()).AddDate(0, 0, 1).Add(offset)
+		if end.After(now) && end.Before(next) {
+			next = end
+		}
+	}
+	return next
+}
+
+// dueTodaySlugs returns the goals that are due today as of now.
+func dueTodaySlugs(goals []Goal, now time.Time) map[string]bool {
+	due := make(map[string]bool)
+	for _, g := range goals {
+		if isDueTodayFilterAt(g, now) {
+			due[g.Slug] = true
+		}
+	}
+	return due
+}
+
+// trackDue records which goals are due as of now, badging those that weren't
+// before (see newlyDueMarker). The initial load badges nothing.
+func (m *appModel) trackDue(now time.Time) {
+	due := dueTodaySlugs(m.goals, now)
+	if m.dueToday != nil {
+		if m.newlyDue == nil {
+			m.newlyDue = make(map[string]bool)
+		}
+		for slug := range due {
+			if !m.dueToday[slug] {
+				m.newlyDue[slug] = true
+			}
+		}
+	}
+	for slug := range m.newlyDue {
+		if !due[slug] {
+			delete(m.newlyDue, slug)
+		}
+	}
+	m.dueToday = due
+	m.rolloverAt = nextRollover(m.goals, now)
+}
+
+// checkRollover reports whether the day has rolled over for any goal since
+// the goals were last loaded, and so they should be refetched. It moves an
+// open datapoint form still set to the old day on to the new one.
+func (m *appModel) checkRollover(now time.Time) bool {
+	if m.rolloverAt.IsZero() || now.Before(m.rolloverAt) {
+		return false
+	}
+	// The form defaults to the goal's Beeminder day, which rolls over at its
+	// deadline rather than at midnight.
+	day := func(t time.Time) string { return t.Format("2006-01-02") }
+	if m.modalGoal != nil {
+		goal := *m.modalGoal
+		day = func(t time.Time) string { return goalDayAt(goal, t).Format("2006-01-02") }
+	}
+	m.datapoint.rollDate(day(m.rolloverAt.Add(-time.Second)), day(now))
+	m.rolloverAt = nextRollover(m.goals, now)
+	return true
+}
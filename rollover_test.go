@@ -0,0 +1,99 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextRollover(t *testing.T) {
+	now := time.Date(2024, 1, 8, 22, 0, 0, 0, time.Local)
+	midnight := time.Date(2024, 1, 9, 0, 0, 0, 0, time.Local)
+	for _, tt := range []struct {
+		name  string
+		goals []Goal
+		want  time.Time
+	}{
+		{"no goals", nil, midnight},
+		{"midnight deadline", []Goal{{Deadline: 0}}, midnight},
+		{"11pm deadline", []Goal{{Deadline: -3600}}, midnight.Add(-time.Hour)},
+		{"3am deadline comes after midnight", []Goal{{Deadline: 3 * 3600}}, midnight},
+		{"9pm deadline has passed for today", []Goal{{Deadline: -3 * 3600}}, midnight},
+		{"earliest wins", []Goal{{Deadline: -1800}, {Deadline: -3600}}, midnight.Add(-time.Hour)},
+	} {
+		if got := nextRollover(tt.goals, now); !got.Equal(tt.want) {
+			t.Errorf("%s: nextRollover = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTrackDue(t *testing.T) {
+	now := time.Date(2024, 1, 8, 12, 0, 0, 0, time.Local)
+	dueTonight := now.Add(10 * time.Hour).Unix()
+	dueLater := now.AddDate(0, 0, 3).Unix()
+	m := &appModel{}
+
+	m.goals = []Goal{{Slug: "a", Losedate: dueTonight}, {Slug: "b", Losedate: dueLater}}
+	m.trackDue(now)
+	if len(m.newlyDue) != 0 {
+		t.Errorf("initial load badged %v", m.newlyDue)
+	}
+	if want := time.Date(2024, 1, 9, 0, 0, 0, 0, time.Local); !m.rolloverAt.Equal(want) {
+		t.Errorf("rolloverAt = %v, want %v", m.rolloverAt, want)
+	}
+
+	m.goals = []Goal{{Slug: "a", Losedate: dueTonight}, {Slug: "b", Losedate: dueTonight}}
+	m.trackDue(now)
+	if !m.newlyDue["b"] || m.newlyDue["a"] {
+		t.Errorf("newlyDue = %v, want just b", m.newlyDue)
+	}
+
+	m.openGoalDetail(&m.goals[1])
+	if m.newlyDue["b"] {
+		t.Error("opening b should clear its badge")
+	}
+}
+
+func TestCheckRollover(t *testing.T) {
+	now := time.Date(2024, 1, 8, 23, 59, 0, 0, time.Local)
+	m := &appModel{datapoint: newDatapointForm("1", "")}
+	m.datapoint.fields[dpDate].value = "2024-01-08"
+	m.trackDue(now)
+
+	if m.checkRollover(now.Add(30 * time.Second)) {
+		t.Error("checkRollover before midnight = true")
+	}
+	after := now.Add(2 * time.Minute)
+	if !m.checkRollover(after) {
+		t.Fatal("checkRollover after midnight = false")
+	}
+	if got := m.datapoint.date(); got != "2024-01-09" {
+		t.Errorf("form date = %q, want 2024-01-09", got)
+	}
+	if m.checkRollover(after) {
+		t.Error("checkRollover twice for one midnight = true")
+	}
+
+	// A date the user picked is left alone.
+	m.datapoint.fields[dpDate].value = "2024-01-05"
+	if !m.checkRollover(after.AddDate(0, 0, 1)) || m.datapoint.date() != "2024-01-05" {
+		t.Errorf("form date = %q, want the user's 2024-01-05", m.datapoint.date())
+	}
+}
+
+func TestCheckRolloverGoalDay(t *testing.T) {
+	// With a 3am deadline, the form's day rolls over at 3am, not midnight.
+	goal := Goal{Slug: "late", Deadline: 3 * 3600}
+	now := time.Date(2024, 1, 9, 1, 0, 0, 0, time.Local)
+	m := &appModel{goals: []Goal{goal}, modalGoal: &goal, datapoint: newDatapointForm("1", "")}
+	m.datapoint.fields[dpDate].value = goalDayAt(goal, now).Format("2006-01-02")
+	if got := m.datapoint.date(); got != "2024-01-08" {
+		t.Fatalf("form date at 1am = %q, want 2024-01-08", got)
+	}
+	m.trackDue(now)
+	if !m.checkRollover(now.Add(2*time.Hour + time.Minute)) {
+		t.Fatal("checkRollover after 3am = false")
+	}
+	if got := m.datapoint.date(); got != "2024-01-09" {
+		t.Errorf("form date after 3am = %q, want 2024-01-09", got)
+	}
+}
@@ -9,6 +9,7 @@
 // It implements just the endpoints buzz hits for the demo:
 //
 //	GET  /api/v1/users/{user}.json                          → account (timezone)
+//	GET  /api/v1/users/me.json                              → the same account (buzz whoami)
 //	GET  /api/v1/users/{user}/goals.json                    → goal list (TUI dashboard, buzz list, buzz today)
 //	GET  /api/v1/users/{user}/goals/{slug}.json             → one goal w/ datapoints (buzz view)
 //	POST /api/v1/users/{user}/goals/{slug}/datapoints.json  → acknowledge a datapoint (buzz add)
@@ -109,8 +110,12 @@ func handle(w http.ResponseWriter, r *http.Request, user string, now time.Time)
 	prefix := "/api/v1/users/" + user
 
 	switch {
-	case path == prefix+".json":
-		writeJSON(w, map[string]any{"username": user, "timezone": "America/New_York"})
+	case path == prefix+".json" || path == "/api/v1/users/me.json":
+		slugs := make([]string, 0, len(demoGoals))
+		for _, g := range demoGoals {
+			slugs = append(slugs, g.Slug)
+		}
+		writeJSON(w, map[string]any{"username": user, "timezone": "America/New_York", "urgency_load": 9, "goals": slugs})
 
 	case r.Method == http.MethodPost && strings.HasPrefix(path, prefix+"/goals/") && strings.HasSuffix(path, "/datapoints.json"):
 		// Acknowledge a new datapoint without persisting it. `buzz add` runs
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const secretUsage = `Usage: buzz secret [--data-public on|off] <goalslug> [on|off]
+  on|off                Make the goal secret, or visible to others
+  --data-public on|off  Make the goal's datapoints public, or private
+  At least one of on|off and --data-public is required.`
+
+// secretRequest is a parsed, validated `buzz secret` invocation. A nil
+// setting is left unchanged.
+type secretRequest struct {
+	goalSlug   string
+	secret     *bool
+	dataPublic *bool
+}
+
+// handleSecretCommand changes whether a goal is secret and whether its data
+// is public.
+func handleSecretCommand() {
+	req, code, done := parseSecretArgs(os.Args[2:], os.Stdout, os.Stderr)
+	if done {
+		os.Exit(code)
+	}
+
+	client, ok := loadClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+
+	code = runSecretCommand(req, client, os.Stdout, os.Stderr)
+	if code == 0 {
+		fmt.Print(getUpdateMessage())
+	}
+	os.Exit(code)
+}
+
+// parseSecretArgs parses and validates `buzz secret` arguments, returning the
+// request, a process exit code, and done=true when the caller should stop
+// (help shown, or a parse/validation error).
+func parseSecretArgs(args []string, stdout, stderr io.Writer) (secretRequest, int, bool) {
+	secretFlags := flag.NewFlagSet("secret", flag.ContinueOnError)
+	// Silence the flag package's own output; we print our own usage.
+	secretFlags.SetOutput(io.Discard)
+	dataPublic := secretFlags.String("data-public", "", "Make the goal's datapoints public (on) or private (off)")
+	if err := secretFlags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stdout, secretUsage)
+			return secretRequest{}, 0, true
+		}
+		fmt.Fprintf(stderr, "Error parsing flags: %s\n", redactError(err))
+		fmt.Fprintln(stderr, secretUsage)
+		return secretRequest{}, 2, true
+	}
+
+	rest := secretFlags.Args()
+	if len(rest) == 0 || len(rest) > 2 || len(rest) == 1 && *dataPublic == "" {
+		fmt.Fprintln(stderr, "Error: Missing required arguments")
+		fmt.Fprintln(stderr, secretUsage)
+		return secretRequest{}, 1, true
+	}
+
+	req := secretRequest{goalSlug: rest[0]}
+	if len(rest) == 2 {
+		on, err := parseOnOff(rest[1])
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %s\n", err)
+			return secretRequest{}, 1, true
+		}
+		req.secret = &on
+	}
+	if *dataPublic != "" {
+		on, err := parseOnOff(*dataPublic)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: --data-public: %s\n", err)
+			return secretRequest{}, 1, true
+		}
+		req.dataPublic = &on
+	}
+	return req, 0, false
+}
+
+// runSecretCommand applies the visibility change and returns the process exit
+// code.
+func runSecretCommand(req secretRequest, client Client, stdout, stderr io.Writer) int {
+	goal, err := client.UpdateGoalVisibility(context.Background(), req.goalSlug, req.secret, req.dataPublic)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to update goal visibility: %s\n", redactError(err))
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "Updated %s: %s\n", goal.Slug, describeVisibility(*goal))
+	return 0
+}
+
+// describeVisibility summarizes who can see a goal and its data.
+func describeVisibility(goal Goal) string {
+	var parts []string
+	if goal.Secret {
+		parts = append(parts, secretMarker+" secret")
+	} else {
+		parts = append(parts, "public")
+	}
+	if goal.DataPublic {
+		parts = append(parts, "data public")
+	} else {
+		parts = append(parts, "data private")
+	}
+	return strings.Join(parts, ", ")
+}
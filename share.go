@@ -0,0 +1,259 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+const shareUsage = `Usage: buzz share [--week] [--redact-amounts] [--goals <slug,...>] [--html]
+  Write a summary of how your goals went, as markdown (or HTML), to send to a
+  coach or accountability partner.`
+
+// shareRequest holds the parsed `buzz share` flags.
+type shareRequest struct {
+	week          bool     // this week since Monday, rather than the last 7 days
+	redactAmounts bool     // leave out the pledges
+	goals         []string // only these goals; nil for all but secret ones
+	html          bool     // write HTML rather than markdown
+}
+
+// shareGoal is one goal's line in the digest.
+type shareGoal struct {
+	goal       Goal
+	daysLogged int // days in the period with a datapoint
+	datapoints int
+	total      float64
+}
+
+// shareDigest is the summary `buzz share` writes.
+type shareDigest struct {
+	username   string
+	start, end time.Time // the period, end exclusive
+	days       int       // days in the period so far
+	goals      []shareGoal
+}
+
+// handleShareCommand writes a shareable summary of the goals.
+func handleShareCommand() {
+	req, code, done := parseShareArgs(os.Args[2:], os.Stdout, os.Stderr)
+	if done {
+		os.Exit(code)
+	}
+
+	config, client, ok := loadConfigAndClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+	os.Exit(runShareCommand(context.Background(), client, config.Username, req, goalFilter, time.Now(), os.Stdout, os.Stderr))
+}
+
+// parseShareArgs parses `buzz share` arguments, returning the request, a
+// process exit code, and done=true when the caller should stop (help shown,
+// or a usage error).
+func parseShareArgs(args []string, stdout, stderr io.Writer) (shareRequest, int, bool) {
+	shareFlags := flag.NewFlagSet("share", flag.ContinueOnError)
+	// Silence the flag package's own output; we print our own usage.
+	shareFlags.SetOutput(io.Discard)
+	week := shareFlags.Bool("week", false, "This week since Monday")
+	redact := shareFlags.Bool("redact-amounts", false, "Leave out pledge amounts")
+	goals := shareFlags.String("goals", "", "Comma-separated goals to include")
+	asHTML := shareFlags.Bool("html", false, "Write HTML")
+	if err := shareFlags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stdout, shareUsage)
+			return shareRequest{}, 0, true
+		}
+		fmt.Fprintf(stderr, "Error parsing flags: %s\n", err)
+		fmt.Fprintln(stderr, shareUsage)
+		return shareRequest{}, 2, true
+	}
+	if shareFlags.NArg() > 0 {
+		fmt.Fprintf(stderr, "Error: unexpected argument %q\n", shareFlags.Arg(0))
+		fmt.Fprintln(stderr, shareUsage)
+		return shareRequest{}, 1, true
+	}
+
+	req := shareRequest{week: *week, redactAmounts: *redact, html: *asHTML}
+	for _, slug := range strings.Split(*goals, ",") {
+		if slug = strings.TrimSpace(slug); slug != "" {
+			req.goals = append(req.goals, slug)
+		}
+	}
+	return req, 0, false
+}
+
+// sharePeriod is the period a digest covers: this week since Monday with
+// week set, otherwise the last 7 days including today. end is exclusive.
+func sharePeriod(week bool, now time.Time) (start, end time.Time) {
+	end = time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, now.Location())
+	if week {
+		return weekStart(now, 0), end
+	}
+	return end.AddDate(0, 0, -7), end
+}
+
+// buildShareDigest summarizes the goals' datapoints between start and end.
+// With allow set, only those goals are included, secret or not; otherwise
+// every goal but the secret ones.
+func buildShareDigest(goals []Goal, allow []string, start, end time.Time) shareDigest {
+	d := shareDigest{start: start, end: end, days: int(end.Sub(start).Hours()/24 + 0.5)}
+	for _, g := range goals {
+		if allow != nil && !slices.Contains(allow, g.Slug) || allow == nil && g.Secret {
+			continue
+		}
+		sg := shareGoal{goal: g}
+		days := map[string]bool{}
+		for _, dp := range g.Datapoints {
+			day, err := time.ParseInLocation("20060102", dp.Daystamp, start.Location())
+			if err != nil || day.Before(start) || !day.Before(end) {
+				continue
+			}
+			days[dp.Daystamp] = true
+			sg.datapoints++
+			sg.total += dp.Value
+		}
+		sg.daysLogged = len(days)
+		d.goals = append(d.goals, sg)
+	}
+	slices.SortFunc(d.goals, func(a, b shareGoal) int { return strings.Compare(a.goal.Slug, b.goal.Slug) })
+	return d
+}
+
+// onTrack reports whether g isn't due today, so isn't at risk of derailing.
+func (sg shareGoal) onTrack() bool {
+	return IsEndValueReached(sg.goal) || UrgencyFor(sg.goal.Safebuf) != UrgencyOverdue
+}
+
+// status describes the goal's safety buffer for the digest.
+func (sg shareGoal) status() string {
+	switch {
+	case IsEndValueReached(sg.goal):
+		return "complete"
+	case !sg.onTrack():
+		return "due today"
+	case sg.goal.Safebuf == 1:
+		return "1 day of buffer"
+	default:
+		return fmt.Sprintf("%d days of buffer", sg.goal.Safebuf)
+	}
+}
+
+// rows returns the digest's table: a header row, then a row per goal.
+func (d shareDigest) rows(redactAmounts bool) [][]string {
+	header := []string{"Goal", "Days logged", "Datapoints", "Total", "Status"}
+	if !redactAmounts {
+		header = append(header, "Pledge")
+	}
+	rows := [][]string{header}
+	for _, sg := range d.goals {
+		total := formatValue(sg.total)
+		if sg.goal.Gunits != "" {
+			total += " " + sg.goal.Gunits
+		}
+		row := []string{sg.goal.Slug, fmt.Sprintf("%d/%d", sg.daysLogged, d.days), fmt.Sprint(sg.datapoints), total, sg.status()}
+		if !redactAmounts {
+			row = append(row, "$"+formatPledge(sg.goal.Pledge))
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// title heads the digest, e.g. "alice's goals: Mon Jan 15 – Sun Jan 21, 2024".
+func (d shareDigest) title() string {
+	who := "My"
+	if d.username != "" {
+		who = d.username + "'s"
+	}
+	last := d.end.AddDate(0, 0, -1)
+	return fmt.Sprintf("%s goals: %s – %s", who, d.start.Format("Mon Jan 2"), last.Format("Mon Jan 2, 2006"))
+}
+
+// summary is the digest's closing line, e.g. "4 of 5 goals on track."
+func (d shareDigest) summary() string {
+	onTrack := 0
+	for _, sg := range d.goals {
+		if sg.onTrack() {
+			onTrack++
+		}
+	}
+	return fmt.Sprintf("%d of %d goals on track.", onTrack, len(d.goals))
+}
+
+// renderShareMarkdown writes the digest as a markdown table.
+func renderShareMarkdown(d shareDigest, redactAmounts bool) string {
+	escape := strings.NewReplacer("|", `\|`)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n", d.title())
+	for i, row := range d.rows(redactAmounts) {
+		for j := range row {
+			row[j] = escape.Replace(row[j])
+		}
+		sb.WriteString("| " + strings.Join(row, " | ") + " |\n")
+		if i == 0 {
+			sb.WriteString(strings.Repeat("| --- ", len(row)) + "|\n")
+		}
+	}
+	fmt.Fprintf(&sb, "\n**%s**\n", d.summary())
+	return sb.String()
+}
+
+// renderShareHTML writes the digest as a standalone HTML page.
+func renderShareHTML(d shareDigest, redactAmounts bool) string {
+	var sb strings.Builder
+	title := html.EscapeString(d.title())
+	fmt.Fprintf(&sb, "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n</head>\n<body>\n<h1>%s</h1>\n<table>\n", title, title)
+	for i, row := range d.rows(redactAmounts) {
+		cell := "td"
+		if i == 0 {
+			cell = "th"
+		}
+		sb.WriteString("<tr>")
+		for _, c := range row {
+			fmt.Fprintf(&sb, "<%s>%s</%s>", cell, html.EscapeString(c), cell)
+		}
+		sb.WriteString("</tr>\n")
+	}
+	fmt.Fprintf(&sb, "</table>\n<p><strong>%s</strong></p>\n</body>\n</html>\n", html.EscapeString(d.summary()))
+	return sb.String()
+}
+
+// runShareCommand fetches the period's datapoints and writes the digest. It
+// returns the process exit code.
+func runShareCommand(ctx context.Context, client Client, username string, req shareRequest, keep func(Goal) bool, now time.Time, out, errOut io.Writer) int {
+	start, end := sharePeriod(req.week, now)
+	goals, err := client.FetchDatapointsSince(ctx, start)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: Failed to fetch datapoints: %s\n", redactError(err))
+		return 1
+	}
+	goals = keepGoals(goals, keep)
+
+	for _, slug := range req.goals {
+		if !slices.ContainsFunc(goals, func(g Goal) bool { return g.Slug == slug }) {
+			fmt.Fprintf(errOut, "Error: no goal named %q\n", slug)
+			return 1
+		}
+	}
+	d := buildShareDigest(goals, req.goals, start, end)
+	d.username = username
+	if len(d.goals) == 0 {
+		fmt.Fprintln(errOut, "Error: No goals to share.")
+		return 1
+	}
+
+	if req.html {
+		fmt.Fprint(out, renderShareHTML(d, req.redactAmounts))
+	} else {
+		fmt.Fprint(out, renderShareMarkdown(d, req.redactAmounts))
+	}
+	return 0
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseShareArgs(t *testing.T) {
+	req, code, done := parseShareArgs([]string{"--week", "--redact-amounts", "--goals", "read, gym,"}, &bytes.Buffer{}, &bytes.Buffer{})
+	want := shareRequest{week: true, redactAmounts: true, goals: []string{"read", "gym"}}
+	if code != 0 || done || !reflect.DeepEqual(req, want) {
+		t.Errorf("parseShareArgs = %+v, %d, %v, want %+v", req, code, done, want)
+	}
+	if _, code, done := parseShareArgs([]string{"extra"}, &bytes.Buffer{}, &bytes.Buffer{}); code != 1 || !done {
+		t.Errorf("extra argument: code %d, done %v", code, done)
+	}
+	if _, code, done := parseShareArgs([]string{"--nope"}, &bytes.Buffer{}, &bytes.Buffer{}); code != 2 || !done {
+		t.Errorf("unknown flag: code %d, done %v", code, done)
+	}
+}
+
+func TestSharePeriod(t *testing.T) {
+	now := time.Date(2024, 1, 17, 9, 0, 0, 0, time.Local) // a Wednesday
+	start, end := sharePeriod(false, now)
+	if !start.Equal(time.Date(2024, 1, 11, 0, 0, 0, 0, time.Local)) || !end.Equal(time.Date(2024, 1, 18, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("last 7 days = %v to %v", start, end)
+	}
+	if start, _ = sharePeriod(true, now); !start.Equal(time.Date(2024, 1, 15, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("this week starts %v, want Monday", start)
+	}
+}
+
+func TestRunShareCommand(t *testing.T) {
+	now := time.Date(2024, 1, 17, 9, 0, 0, 0, time.Local)
+	goals := []Goal{
+		{Slug: "read", Gunits: "pages", Safebuf: 3, Pledge: 30, Datapoints: []Datapoint{
+			{Daystamp: "20240110", Value: 99}, // before the week
+			{Daystamp: "20240115", Value: 10},
+			{Daystamp: "20240115", Value: 5},
+			{Daystamp: "20240117", Value: 20},
+		}},
+		{Slug: "gym", Safebuf: 0, Pledge: 5},
+		{Slug: "diary", Secret: true, Safebuf: 5},
+	}
+	var gotSince time.Time
+	client := &FakeClient{FetchDatapointsSinceFunc: func(since time.Time) ([]Goal, error) {
+		gotSince = since
+		return goals, nil
+	}}
+
+	var out, errOut bytes.Buffer
+	code := runShareCommand(context.Background(), client, "alice", shareRequest{week: true}, nil, now, &out, &errOut)
+	checkResult(t, code, out.String(), errOut.String(), 0, "", "")
+	if !gotSince.Equal(time.Date(2024, 1, 15, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("fetched since %v, want Monday", gotSince)
+	}
+	md := out.String()
+	for _, want := range []string{
+		"# alice's goals: Mon Jan 15 – Wed Jan 17, 2024\n",
+		"| Goal | Days logged | Datapoints | Total | Status | Pledge |\n",
+		"| gym | 0/3 | 0 | 0 | due today | $5 |\n",
+		"| read | 2/3 | 3 | 35 pages | 3 days of buffer | $30 |\n",
+		"**1 of 2 goals on track.**",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "diary") {
+		t.Errorf("secret goal shared:\n%s", md)
+	}
+
+	out.Reset()
+	code = runShareCommand(context.Background(), client, "alice", shareRequest{week: true, redactAmounts: true, goals: []string{"diary"}, html: true}, nil, now, &out, &errOut)
+	checkResult(t, code, out.String(), errOut.String(), 0, "", "")
+	page := out.String()
+	if !strings.Contains(page, "<td>diary</td>") || strings.Contains(page, "read") || strings.Contains(page, "$") || strings.Contains(page, "Pledge") {
+		t.Errorf("HTML should list only the allowlisted goal, without pledges:\n%s", page)
+	}
+	if !strings.Contains(page, "alice&#39;s goals") {
+		t.Errorf("HTML should escape the title:\n%s", page)
+	}
+
+	out.Reset()
+	errOut.Reset()
+	code = runShareCommand(context.Background(), client, "", shareRequest{goals: []string{"nope"}}, nil, now, &out, &errOut)
+	checkResult(t, code, out.String(), errOut.String(), 1, "", "Error: no goal named \"nope\"\n")
+
+	errOut.Reset()
+	client.FetchDatapointsSinceFunc = func(time.Time) ([]Goal, error) { return nil, errors.New("boom") }
+	code = runShareCommand(context.Background(), client, "", shareRequest{}, nil, now, &out, &errOut)
+	checkResult(t, code, "", errOut.String(), 1, "", "Error: Failed to fetch datapoints: boom\n")
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const shortCircuitUsage = "Usage: buzz shortcircuit [-y|--yes] <goalslug>"
+
+// shortCircuitRequest holds the parsed `buzz shortcircuit` arguments.
+type shortCircuitRequest struct {
+	goalSlug    string
+	skipConfirm bool
+}
+
+// handleShortCircuitCommand charges a goal's current pledge right away, as if
+// it had derailed. Because it costs real money, the user must type the goal's
+// slug to confirm.
+func handleShortCircuitCommand() {
+	req, code, done := parseShortCircuitArgs(os.Args[2:], os.Stdout, os.Stderr)
+	if done {
+		os.Exit(code)
+	}
+
+	config, client, ok := loadConfigAndClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+
+	code = runShortCircuitCommand(context.Background(), client, req, config.Username, os.Stdin, os.Stdout, os.Stderr)
+	if code == 0 {
+		fmt.Print(getUpdateMessage())
+	}
+	os.Exit(code)
+}
+
+// parseShortCircuitArgs parses `buzz shortcircuit` arguments, returning the
+// request, a process exit code, and done=true when the caller should stop
+// (help shown, or a usage error).
+func parseShortCircuitArgs(args []string, stdout, stderr io.Writer) (shortCircuitRequest, int, bool) {
+	scFlags := flag.NewFlagSet("shortcircuit", flag.ContinueOnError)
+	// Silence the flag package's own output; we print our own usage.
+	scFlags.SetOutput(io.Discard)
+	yes := scFlags.Bool("yes", false, "Skip the confirmation prompt")
+	yesShort := scFlags.Bool("y", false, "Skip the confirmation prompt (shorthand)")
+	if err := scFlags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stdout, shortCircuitUsage)
+			return shortCircuitRequest{}, 0, true
+		}
+		fmt.Fprintf(stderr, "Error parsing flags: %s\n", err)
+		fmt.Fprintln(stderr, shortCircuitUsage)
+		return shortCircuitRequest{}, 2, true
+	}
+
+	rest := scFlags.Args()
+	if len(rest) != 1 {
+		if len(rest) == 0 {
+			fmt.Fprintln(stderr, "Error: Missing required argument")
+		} else {
+			fmt.Fprintf(stderr, "Error: Too many arguments: %v\n", rest[1:])
+		}
+		fmt.Fprintln(stderr, shortCircuitUsage)
+		return shortCircuitRequest{}, 1, true
+	}
+	return shortCircuitRequest{goalSlug: rest[0], skipConfirm: *yes || *yesShort}, 0, false
+}
+
+// runShortCircuitCommand short-circuits the goal, after showing the pledge it
+// will charge and having the user type the slug on stdin unless skipConfirm is
+// set. username is who the charge is for. It returns the process exit code.
+func runShortCircuitCommand(ctx context.Context, client Client, req shortCircuitRequest, username string, stdin io.Reader, out, errOut io.Writer) int {
+	// The API returns the goal with its pledge already raised, so fetch the
+	// pledge it's about to charge first.
+	current, err := client.FetchGoal(ctx, req.goalSlug)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: Failed to fetch goal: %s\n", redactError(err))
+		return 1
+	}
+
+	if !req.skipConfirm {
+		fmt.Fprintf(out, "Short-circuit %s? This charges its $%s pledge now and raises the pledge. Type the goal's slug to confirm: ", req.goalSlug, formatPledge(current.Pledge))
+		// Anything but the exact slug, including EOF or a read error in
+		// non-interactive contexts, is a "no": a stray "y" must not cost money.
+		line, err := bufio.NewReader(stdin).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) || strings.TrimSpace(line) != req.goalSlug {
+			fmt.Fprintln(out, "Cancelled.")
+			return 0
+		}
+	}
+
+	goal, err := client.ShortCircuitGoal(ctx, req.goalSlug)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: Failed to short-circuit goal: %s\n", redactError(err))
+		return 1
+	}
+
+	ch := Charge{Amount: current.Pledge, Note: fmt.Sprintf("Short-circuited %s", goal.Slug), Username: username}
+	fmt.Fprintf(out, "Charged $%.2f with note: %q for %s\n", ch.Amount, ch.Note, ch.Username)
+	fmt.Fprintf(out, "%s's pledge is now $%s.\n", goal.Slug, formatPledge(goal.Pledge))
+	return 0
+}
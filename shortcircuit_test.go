@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestParseShortCircuitArgs(t *testing.T) {
+	for _, tt := range []struct {
+		args     []string
+		want     shortCircuitRequest
+		wantCode int
+		wantDone bool
+	}{
+		{[]string{"reading"}, shortCircuitRequest{goalSlug: "reading"}, 0, false},
+		{[]string{"--yes", "reading"}, shortCircuitRequest{goalSlug: "reading", skipConfirm: true}, 0, false},
+		{[]string{"-y", "reading"}, shortCircuitRequest{goalSlug: "reading", skipConfirm: true}, 0, false},
+		{[]string{}, shortCircuitRequest{}, 1, true},
+		{[]string{"reading", "writing"}, shortCircuitRequest{}, 1, true},
+		{[]string{"--nope", "reading"}, shortCircuitRequest{}, 2, true},
+		{[]string{"--help"}, shortCircuitRequest{}, 0, true},
+	} {
+		req, code, done := parseShortCircuitArgs(tt.args, &bytes.Buffer{}, &bytes.Buffer{})
+		if req != tt.want || code != tt.wantCode || done != tt.wantDone {
+			t.Errorf("parseShortCircuitArgs(%v) = %+v, %d, %v", tt.args, req, code, done)
+		}
+	}
+}
+
+func TestRunShortCircuitCommand(t *testing.T) {
+	var shorted []string
+	client := &FakeClient{
+		FetchGoalFunc: func(slug string) (*Goal, error) {
+			return &Goal{Slug: slug, Pledge: 5}, nil
+		},
+		ShortCircuitGoalFunc: func(slug string) (*Goal, error) {
+			shorted = append(shorted, slug)
+			return &Goal{Slug: slug, Pledge: 10}, nil
+		},
+	}
+	run := func(req shortCircuitRequest, stdin string) (int, string, string) {
+		var out, errOut bytes.Buffer
+		code := runShortCircuitCommand(context.Background(), client, req, "alice", strings.NewReader(stdin), &out, &errOut)
+		return code, out.String(), errOut.String()
+	}
+
+	// Only the exact slug confirms; "y" is not enough.
+	for _, stdin := range []string{"", "y\n", "yes\n", "Reading\n"} {
+		code, out, errOut := run(shortCircuitRequest{goalSlug: "reading"}, stdin)
+		checkResult(t, code, out, errOut, 0, "", "")
+		if !strings.HasSuffix(out, "Cancelled.\n") {
+			t.Errorf("answer %q: out %q, want cancelled", stdin, out)
+		}
+	}
+	if len(shorted) != 0 {
+		t.Fatalf("unconfirmed short-circuit ran: %v", shorted)
+	}
+
+	code, out, errOut := run(shortCircuitRequest{goalSlug: "reading"}, "reading\n")
+	checkResult(t, code, out, errOut, 0, "", "")
+	if !strings.HasPrefix(out, "Short-circuit reading? This charges its $5 pledge now") ||
+		!strings.HasSuffix(out, "Charged $5.00 with note: \"Short-circuited reading\" for alice\nreading's pledge is now $10.\n") {
+		t.Errorf("confirmed short-circuit: out %q", out)
+	}
+
+	code, out, errOut = run(shortCircuitRequest{goalSlug: "reading", skipConfirm: true}, "")
+	checkResult(t, code, out, errOut, 0, "Charged $5.00 with note: \"Short-circuited reading\" for alice\nreading's pledge is now $10.\n", "")
+	if len(shorted) != 2 {
+		t.Errorf("shorted = %v, want two calls", shorted)
+	}
+
+	client.ShortCircuitGoalFunc = func(slug string) (*Goal, error) { return nil, errFakeNotConfigured }
+	code, out, errOut = run(shortCircuitRequest{goalSlug: "reading", skipConfirm: true}, "")
+	checkResult(t, code, out, errOut, 1, "", "")
+	if !strings.HasPrefix(errOut, "Error: Failed to short-circuit goal:") {
+		t.Errorf("errOut = %q", errOut)
+	}
+}
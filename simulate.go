@@ -0,0 +1,234 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"time"
+)
+
+const simulateUsage = `Usage: buzz simulate <goalslug> <value>
+  Show what adding <value> today would do to the goal, without adding it.
+  <value> may be a number, a time like 1:30, or an expression like 3*12+5.`
+
+// maxSimulatedBuffer caps how far ahead simulateAdd looks for the day a goal
+// would next be due.
+const maxSimulatedBuffer = 3650
+
+// simulation is the local estimate of what adding a datapoint would do.
+type simulation struct {
+	valueBefore, valueAfter   float64 // the goal's current value
+	bufferBefore, bufferAfter int     // safety buffer in days
+	// dueDay is the day the goal would next be due after the add, dueAfter
+	// that day's deadline, and neededAfter how much it would need by then
+	// (do-more goals only).
+	dueDay, dueAfter time.Time
+	neededAfter      float64
+}
+
+// simulateAdd works out what adding value on day would do to goal, from its
+// bright red line and datapoints (so goal must have been fetched with
+// datapoints). Nothing is submitted.
+//
+// The local model is the bright line plus buzz's own day aggregation, which
+// can disagree with Beeminder in the details (an initial value, odometer
+// resets, a goal's road editor history). To keep that from showing, the
+// result is anchored to what Beeminder reports: the current value is Curval
+// and the buffer is Safebuf, each moved by the difference the add makes in
+// the local model.
+func simulateAdd(goal Goal, value float64, day, now time.Time) (simulation, error) {
+	r, err := parseRoad(goal.Roadall, goal.Runits)
+	if err != nil {
+		return simulation{}, err
+	}
+	if len(r) == 0 {
+		return simulation{}, errors.New("the goal has no bright red line to simulate against")
+	}
+
+	loc := now.Location()
+	timestamp := now.Unix()
+	if !startOfDay(day, loc).Equal(startOfDay(now, loc)) {
+		timestamp = startOfDay(day, loc).Add(12 * time.Hour).Unix()
+	}
+	added := append(append([]Datapoint(nil), goal.Datapoints...), Datapoint{
+		Timestamp: timestamp,
+		Daystamp:  day.In(loc).Format("20060102"),
+		Value:     value,
+	})
+
+	localBefore := simulatedValue(goal, goal.Datapoints, loc)
+	localAfter := simulatedValue(goal, added, loc)
+	sim := simulation{valueBefore: localBefore, valueAfter: localAfter}
+	if goal.Curval != nil {
+		sim.valueBefore = *goal.Curval
+		sim.valueAfter = *goal.Curval + (localAfter - localBefore)
+	}
+
+	bufBefore := simulatedBuffer(goal, r, localBefore, now)
+	bufAfter := simulatedBuffer(goal, r, localAfter, now)
+	sim.bufferBefore = goal.Safebuf
+	sim.bufferAfter = max(goal.Safebuf+bufAfter-bufBefore, 0)
+
+	sim.dueDay = startOfDay(now, loc).AddDate(0, 0, sim.bufferAfter)
+	sim.dueAfter = dayDeadline(goal, sim.dueDay)
+	if goal.Yaw >= 0 {
+		sim.neededAfter = math.Max(r.valueAt(sim.dueAfter)-localAfter, 0)
+	}
+	return sim, nil
+}
+
+// simulatedValue is the goal's current value from datapoints: the running
+// total for a cumulative goal, otherwise the latest day's aggregate.
+func simulatedValue(goal Goal, datapoints []Datapoint, loc *time.Location) float64 {
+	days := aggregateByDay(goal, datapoints, loc)
+	if len(days) == 0 {
+		return 0
+	}
+	if !goal.Kyoom {
+		return days[len(days)-1].value
+	}
+	total := 0.0
+	for _, d := range days {
+		total += d.value
+	}
+	return total
+}
+
+// simulatedBuffer counts the days, starting today, that value stays on the
+// good side of the bright red line at each day's deadline.
+func simulatedBuffer(goal Goal, r road, value float64, now time.Time) int {
+	const epsilon = 1e-9
+	today := startOfDay(now, now.Location())
+	for days := 0; days < maxSimulatedBuffer; days++ {
+		line := r.valueAt(dayDeadline(goal, today.AddDate(0, 0, days)))
+		if goal.Yaw < 0 && value > line+epsilon || goal.Yaw >= 0 && value < line-epsilon {
+			return days
+		}
+	}
+	return maxSimulatedBuffer
+}
+
+// dayDeadline is the instant the Beeminder day starting at local midnight day
+// ends for the goal: the next midnight, moved by the goal's deadline offset.
+func dayDeadline(goal Goal, day time.Time) time.Time {
+	return day.AddDate(0, 0, 1).Add(time.Duration(goal.Deadline) * time.Second)
+}
+
+// describe summarizes a simulation for the goal in a few lines.
+func (s simulation) describe(goal Goal, now time.Time) string {
+	days := func(n int) string {
+		if n >= maxSimulatedBuffer {
+			return "years"
+		}
+		if n == 1 {
+			return "1 day"
+		}
+		return fmt.Sprintf("%d days", n)
+	}
+	out := fmt.Sprintf("Safety buffer: %s → %s\n", days(s.bufferBefore), days(s.bufferAfter))
+	out += fmt.Sprintf("Value: %s → %s%s\n", formatSimulatedNumber(s.valueBefore), formatSimulatedNumber(s.valueAfter), unitsSuffix(goal.Gunits))
+	if goal.Yaw >= 0 && s.bufferAfter < maxSimulatedBuffer && s.neededAfter > 0 {
+		out += fmt.Sprintf("Then due %s (%s): +%s needed\n", s.dueDay.Format("Mon Jan 2"), FormatDueDateAt(s.dueAfter.Unix(), now), formatSimulatedNumber(s.neededAfter))
+	}
+	return out
+}
+
+// formatSimulatedNumber rounds a simulated value for display.
+func formatSimulatedNumber(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+// handleSimulateCommand shows what adding a value would do, without adding it.
+func handleSimulateCommand() {
+	goalSlug, rawValue, code, done := parseSimulateArgs(os.Args[2:], os.Stdout, os.Stderr)
+	if done {
+		os.Exit(code)
+	}
+
+	client, ok := loadClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+
+	code = runSimulateCommand(context.Background(), goalSlug, rawValue, client, time.Now(), os.Stdout, os.Stderr)
+	if code == 0 {
+		fmt.Print(getUpdateMessage())
+	}
+	os.Exit(code)
+}
+
+// parseSimulateArgs parses `buzz simulate` arguments, returning the goal slug
+// and raw value, a process exit code, and done=true when the caller should
+// stop (help shown, or a parse error).
+func parseSimulateArgs(args []string, stdout, stderr io.Writer) (string, string, int, bool) {
+	simulateFlags := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	// Silence the flag package's own output; we print our own usage.
+	simulateFlags.SetOutput(io.Discard)
+	if err := simulateFlags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stdout, simulateUsage)
+			return "", "", 0, true
+		}
+		fmt.Fprintf(stderr, "Error parsing flags: %s\n", err)
+		fmt.Fprintln(stderr, simulateUsage)
+		return "", "", 2, true
+	}
+	if simulateFlags.NArg() != 2 {
+		fmt.Fprintln(stderr, "Error: buzz simulate takes a goal and a value")
+		fmt.Fprintln(stderr, simulateUsage)
+		return "", "", 1, true
+	}
+	return simulateFlags.Arg(0), simulateFlags.Arg(1), 0, false
+}
+
+// runSimulateCommand fetches the goal and prints the simulated effect of
+// adding rawValue today. It returns the process exit code.
+func runSimulateCommand(ctx context.Context, goalSlug, rawValue string, client Client, now time.Time, stdout, stderr io.Writer) int {
+	goal, err := client.FetchGoalWithDatapoints(ctx, goalSlug)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %s\n", redactError(err))
+		return 1
+	}
+	value, err := parseAddValue(rawValue, goal.Gunits)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %s\n", err)
+		return 1
+	}
+	v, _ := strconv.ParseFloat(value, 64)
+
+	sim, err := simulateAdd(*goal, v, now, now)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Can't simulate %s: %s\n", goal.Slug, err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "Adding %s%s to %s today:\n", value, unitsSuffix(goal.Gunits), goal.Slug)
+	fmt.Fprint(stdout, sim.describe(*goal, now))
+	fmt.Fprintln(stdout, "(Simulated; nothing was added.)")
+	return 0
+}
+
+// previewDatapoint is the TUI form's `?` preview: the simulated effect of
+// adding the form's entry to goal, or why it can't be shown.
+func previewDatapoint(goal *Goal, d *datapointForm, now time.Time) string {
+	if goal == nil {
+		return ""
+	}
+	if errMsg := d.validate(); errMsg != "" {
+		return "Can't preview: " + errMsg
+	}
+	if goal.Datapoints == nil {
+		return "Can't preview yet: the goal's datapoints are still loading"
+	}
+	day, _ := time.ParseInLocation("2006-01-02", d.date(), now.Location())
+	value, _ := strconv.ParseFloat(d.submitValue(), 64)
+	sim, err := simulateAdd(*goal, value, day, now)
+	if err != nil {
+		return "Can't preview: " + err.Error()
+	}
+	return "If added: " + sim.describe(*goal, now)
+}
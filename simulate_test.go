@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// simulateTestGoal is a do-more goal whose bright line climbs 1/day from 0 on
+// March 1, with 10 logged so far: on March 10 it's safe through today only.
+func simulateTestGoal() Goal {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.Local)
+	t0, v0 := float64(start.Unix()), 0.0
+	t1, rate := float64(start.AddDate(0, 0, 100).Unix()), 1.0
+	curval := 10.0
+	return Goal{
+		Slug:    "reading",
+		Gunits:  "pages",
+		Runits:  "d",
+		Yaw:     1,
+		Kyoom:   true,
+		Safebuf: 1,
+		Curval:  &curval,
+		Roadall: [][]*float64{{&t0, &v0, nil}, {&t1, nil, &rate}},
+		Datapoints: []Datapoint{
+			{Timestamp: start.AddDate(0, 0, 4).Unix(), Daystamp: "20260305", Value: 6},
+			{Timestamp: start.AddDate(0, 0, 6).Unix(), Daystamp: "20260307", Value: 4},
+		},
+	}
+}
+
+func TestSimulateAdd(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+
+	t.Run("do more", func(t *testing.T) {
+		sim, err := simulateAdd(simulateTestGoal(), 3, now, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sim.bufferBefore != 1 || sim.bufferAfter != 4 || sim.valueBefore != 10 || sim.valueAfter != 13 {
+			t.Errorf("sim = %+v", sim)
+		}
+		wantDue := time.Date(2026, 3, 15, 0, 0, 0, 0, time.Local)
+		if !sim.dueAfter.Equal(wantDue) || math.Abs(sim.neededAfter-1) > 1e-9 {
+			t.Errorf("due %v needing %v, want %v needing 1", sim.dueAfter, sim.neededAfter, wantDue)
+		}
+	})
+
+	t.Run("anchored to Beeminder's numbers", func(t *testing.T) {
+		goal := simulateTestGoal()
+		curval := 110.0 // an initial value buzz's datapoints don't show
+		goal.Curval = &curval
+		goal.Safebuf = 2
+		sim, err := simulateAdd(goal, 3, now, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sim.valueAfter != 113 || sim.bufferAfter != 5 {
+			t.Errorf("sim = %+v, want value 113 and buffer 5", sim)
+		}
+	})
+
+	t.Run("do less", func(t *testing.T) {
+		goal := simulateTestGoal()
+		goal.Yaw = -1
+		goal.Safebuf = 0
+		sim, err := simulateAdd(goal, 3, now, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sim.bufferAfter != 0 {
+			t.Errorf("bufferAfter = %d, want 0", sim.bufferAfter)
+		}
+	})
+
+	t.Run("no road", func(t *testing.T) {
+		goal := simulateTestGoal()
+		goal.Roadall = nil
+		if _, err := simulateAdd(goal, 3, now, now); err == nil {
+			t.Error("want an error without a bright red line")
+		}
+	})
+}
+
+func TestRunSimulateCommand(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	client := &FakeClient{
+		FetchGoalWithDatapointsFunc: func(slug string) (*Goal, error) {
+			goal := simulateTestGoal()
+			return &goal, nil
+		},
+	}
+
+	var out, errb bytes.Buffer
+	code := runSimulateCommand(context.Background(), "reading", "1+2", client, now, &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 0, "Safety buffer: 1 day → 4 days", "")
+	for _, want := range []string{"Value: 10 → 13 pages", "Then due Sat Mar 14", "+1 needed", "nothing was added"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output lacks %q:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	errb.Reset()
+	code = runSimulateCommand(context.Background(), "reading", "lots", client, now, &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 1, "", "value must be a valid number")
+}
+
+func TestRunAddDryRun(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	client := &FakeClient{
+		FetchGoalWithDatapointsFunc: func(slug string) (*Goal, error) {
+			goal := simulateTestGoal()
+			return &goal, nil
+		},
+		// Any submission fails with errFakeNotConfigured.
+	}
+
+	var out, errb bytes.Buffer
+	req := addRequest{goalSlug: "reading", value: "3", comment: "ch 4", requestid: "r1", dryRun: true}
+	code := runAddDryRun(context.Background(), req, client, now, &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 0, `Dry run: Would add 3 pages to reading on 2026-03-10, comment="ch 4", requestid="r1"`, "")
+	for _, want := range []string{"Safety buffer: 1 day → 4 days", "nothing was added"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output lacks %q:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	req.daystamp = "20260309"
+	runAddDryRun(context.Background(), req, client, now, &out, &errb)
+	if !strings.Contains(out.String(), "on 2026-03-09") {
+		t.Errorf("output = %q, want the daystamp's date", out.String())
+	}
+}
+
+func TestPreviewDatapoint(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	goal := simulateTestGoal()
+	form := newDatapointForm("3", goal.Gunits)
+	form.fields[dpDate].value = "2026-03-10"
+
+	if got := previewDatapoint(&goal, &form, now); !strings.Contains(got, "Safety buffer: 1 day → 4 days") {
+		t.Errorf("preview = %q", got)
+	}
+
+	loading := goal
+	loading.Datapoints = nil
+	if got := previewDatapoint(&loading, &form, now); !strings.Contains(got, "still loading") {
+		t.Errorf("preview = %q", got)
+	}
+
+	form.fields[dpValue].value = "-"
+	if got := previewDatapoint(&goal, &form, now); !strings.HasPrefix(got, "Can't preview: ") {
+		t.Errorf("preview = %q", got)
+	}
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// SlugCache is a lightweight list of the user's goal slugs, kept on disk for
+// shell completion and fuzzy slug matching, which need the slugs quickly and
+// without a network round trip. It is rewritten whenever buzz fetches the goal
+// list (see slugRecordingClient), so it stays about as current as buzz's last
+// look at the account.
+type SlugCache struct {
+	Username  string    `json:"username"`
+	Slugs     []string  `json:"slugs"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// getSlugCachePath returns the path to the slug cache file
+func getSlugCachePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".buzz_slug_cache"), nil
+}
+
+// loadSlugCache loads the slug cache from disk. A missing file is not an
+// error; it returns an empty cache.
+func loadSlugCache() (*SlugCache, error) {
+	cachePath, err := getSlugCachePath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(cachePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &SlugCache{}, nil
+		}
+		return nil, err
+	}
+
+	var cache SlugCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return nil, err
+	}
+	return &cache, nil
+}
+
+// saveSlugCache records goals' slugs as username's, sorted.
+func saveSlugCache(username string, goals []Goal) error {
+	cachePath, err := getSlugCachePath()
+	if err != nil {
+		return err
+	}
+
+	cache := SlugCache{Username: username, Slugs: make([]string, len(goals)), UpdatedAt: time.Now()}
+	for i, g := range goals {
+		cache.Slugs[i] = g.Slug
+	}
+	slices.Sort(cache.Slugs)
+
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(cachePath, data, 0600)
+}
+
+// renameSlugInSlugCache replaces oldSlug with newSlug in the slug cache, so
+// completion offers a renamed goal before the next goal list refreshes it.
+func renameSlugInSlugCache(oldSlug, newSlug string) error {
+	cachePath, err := getSlugCachePath()
+	if err != nil {
+		return err
+	}
+	return updateFileAtomic(cachePath, 0600, func(data []byte) ([]byte, error) {
+		var cache SlugCache
+		if data == nil || json.Unmarshal(data, &cache) != nil {
+			return nil, errUnchanged
+		}
+		i := slices.Index(cache.Slugs, oldSlug)
+		if i < 0 {
+			return nil, errUnchanged
+		}
+		cache.Slugs[i] = newSlug
+		slices.Sort(cache.Slugs)
+		return json.MarshalIndent(cache, "", "  ")
+	})
+}
+
+// clearSlugCache deletes the slug cache. A missing cache is already clear.
+func clearSlugCache() error {
+	cachePath, err := getSlugCachePath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(cachePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// slugRecordingClient is a Client that refreshes the slug cache with every
+// goal list it fetches successfully. Failing to write the cache never fails
+// the fetch: the cache is a convenience.
+type slugRecordingClient struct {
+	Client
+	username string
+}
+
+// recordingSlugs wraps client so its goal lists update username's slug cache.
+func recordingSlugs(client Client, username string) *slugRecordingClient {
+	return &slugRecordingClient{Client: client, username: username}
+}
+
+func (c *slugRecordingClient) FetchGoals(ctx context.Context) ([]Goal, error) {
+	goals, err := c.Client.FetchGoals(ctx)
+	if err == nil {
+		_ = saveSlugCache(c.username, goals)
+	}
+	return goals, err
+}
+
+func (c *slugRecordingClient) FetchGoalSummaries(ctx context.Context) ([]Goal, error) {
+	goals, err := c.Client.FetchGoalSummaries(ctx)
+	if err == nil {
+		_ = saveSlugCache(c.username, goals)
+	}
+	return goals, err
+}
+
+// StreamGoals records the slugs of the active goals once they've all been
+// streamed.
+func (c *slugRecordingClient) StreamGoals(ctx context.Context, archived bool, fn func(Goal) error) error {
+	var seen []Goal
+	err := c.Client.StreamGoals(ctx, archived, func(g Goal) error {
+		seen = append(seen, Goal{Slug: g.Slug})
+		return fn(g)
+	})
+	if err == nil && !archived {
+		_ = saveSlugCache(c.username, seen)
+	}
+	return err
+}
+
+// cachedSlugs returns username's cached goal slugs, refreshing the cache
+// through client when it's empty or another account's. With a nil client
+// it only reads the cache, whoever's it is.
+func cachedSlugs(ctx context.Context, client Client, username string) ([]string, error) {
+	cache, err := loadSlugCache()
+	if err == nil && (cache.Username == username || client == nil) && len(cache.Slugs) > 0 {
+		return cache.Slugs, nil
+	}
+	if client == nil {
+		return nil, errors.New("the slug cache is empty; run `buzz cache refresh` to fill it")
+	}
+	goals, err := client.FetchGoalSummaries(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := saveSlugCache(username, goals); err != nil {
+		return nil, err
+	}
+	cache, err = loadSlugCache()
+	if err != nil {
+		return nil, err
+	}
+	return cache.Slugs, nil
+}
+
+// printCacheHelp prints usage for the `buzz cache` command group.
+func printCacheHelp(w io.Writer) {
+	fmt.Fprintln(w, "buzz cache - Manage the goal slug cache used for shell completion")
+	fmt.Fprintln(w, "")
+	fmt.Fprintln(w, "USAGE:")
+	fmt.Fprintln(w, "  buzz cache refresh                Refetch your goals and rewrite the slug cache")
+	fmt.Fprintln(w, "  buzz cache clear                  Delete the slug cache")
+	fmt.Fprintln(w, "  buzz cache help                   Show this help message")
+}
+
+// handleCacheCommand dispatches `buzz cache <subcommand>`.
+func handleCacheCommand() {
+	if len(os.Args) >= 3 && os.Args[2] == "refresh" {
+		config, client, ok := loadConfigAndClient(os.Stderr)
+		if !ok {
+			os.Exit(1)
+		}
+		os.Exit(runCacheCommand(os.Args[2:], client, config.Username, os.Stdout, os.Stderr))
+	}
+	os.Exit(runCacheCommand(os.Args[2:], nil, "", os.Stdout, os.Stderr))
+}
+
+// runCacheCommand is the testable core of `buzz cache`. Only refresh uses
+// client and username.
+func runCacheCommand(args []string, client Client, username string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		printCacheHelp(stderr)
+		return 1
+	}
+	switch args[0] {
+	case "refresh":
+		goals, err := client.FetchGoalSummaries(context.Background())
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %s\n", redactError(err))
+			return 1
+		}
+		if err := saveSlugCache(username, goals); err != nil {
+			fmt.Fprintf(stderr, "Error: Failed to save the slug cache: %s\n", err)
+			return 1
+		}
+		fmt.Fprintf(stdout, "Cached %d goal slugs.\n", len(goals))
+		return 0
+	case "clear":
+		if err := clearSlugCache(); err != nil {
+			fmt.Fprintf(stderr, "Error: Failed to clear the slug cache: %s\n", err)
+			return 1
+		}
+		fmt.Fprintln(stdout, "Cleared the slug cache.")
+		return 0
+	case "help", "-h", "--help":
+		printCacheHelp(stdout)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "Unknown cache subcommand: %s\n", args[0])
+		printCacheHelp(stderr)
+		return 1
+	}
+}
+
+// handleCompletionCommand prints completion candidates for shell scripts.
+func handleCompletionCommand() {
+	if len(os.Args) < 3 || os.Args[2] != "--goals" {
+		os.Exit(runCompletionCommand(os.Args[2:], nil, "", os.Stdout, os.Stderr))
+	}
+	// Completion runs while the shell offers candidates, with no one to type
+	// a passphrase, so an encrypted config that's locked gets the cache as is.
+	if _, err := loadUnlockedConfig(); errors.Is(err, errConfigLocked) {
+		os.Exit(runCompletionCommand(os.Args[2:], nil, "", os.Stdout, os.Stderr))
+	}
+	config, client, ok := loadConfigAndClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+	os.Exit(runCompletionCommand(os.Args[2:], client, config.Username, os.Stdout, os.Stderr))
+}
+
+// runCompletionCommand is the testable core of `buzz completion`. --goals
+// prints the goal slugs one per line from the slug cache, filling the cache
+// first if it's empty, for a shell completion function to offer. A query after
+// --goals keeps only the slugs it fuzzy-matches, as the TUI's search does.
+func runCompletionCommand(args []string, client Client, username string, stdout, stderr io.Writer) int {
+	const usage = "Usage: buzz completion --goals [query]"
+	if len(args) == 1 && (args[0] == "-h" || args[0] == "--help") {
+		fmt.Fprintln(stdout, usage)
+		return 0
+	}
+	if len(args) < 1 || len(args) > 2 || args[0] != "--goals" {
+		fmt.Fprintln(stderr, usage)
+		return 1
+	}
+	slugs, err := cachedSlugs(context.Background(), client, username)
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %s\n", redactError(err))
+		return 1
+	}
+	for _, slug := range slugs {
+		if len(args) == 1 || fuzzyMatch(args[1], slug) {
+			fmt.Fprintln(stdout, slug)
+		}
+	}
+	return 0
+}
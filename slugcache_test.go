@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestSlugRecordingClient(t *testing.T) {
+	setHome(t, t.TempDir())
+
+	failing := recordingSlugs(&FakeClient{FetchGoalsFunc: func() ([]Goal, error) { return nil, errors.New("boom") }}, "alice")
+	if _, err := failing.FetchGoals(context.Background()); err == nil {
+		t.Fatal("FetchGoals error was swallowed")
+	}
+	if cache, err := loadSlugCache(); err != nil || len(cache.Slugs) != 0 {
+		t.Fatalf("failed fetch wrote the cache: %+v, %v", cache, err)
+	}
+
+	client := recordingSlugs(&FakeClient{FetchGoalsFunc: func() ([]Goal, error) {
+		return []Goal{{Slug: "write"}, {Slug: "exercise"}}, nil
+	}}, "alice")
+	if _, err := client.FetchGoals(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	cache, err := loadSlugCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cache.Username != "alice" || !reflect.DeepEqual(cache.Slugs, []string{"exercise", "write"}) {
+		t.Errorf("cache = %+v, want alice's sorted slugs", cache)
+	}
+}
+
+func TestRunCacheCommand(t *testing.T) {
+	setHome(t, t.TempDir())
+	client := &FakeClient{FetchGoalsFunc: func() ([]Goal, error) { return []Goal{{Slug: "read"}}, nil }}
+
+	var out, errb bytes.Buffer
+	code := runCacheCommand([]string{"refresh"}, client, "alice", &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 0, "Cached 1 goal slugs.", "")
+	if cache, _ := loadSlugCache(); !reflect.DeepEqual(cache.Slugs, []string{"read"}) {
+		t.Errorf("slugs after refresh = %v, want [read]", cache.Slugs)
+	}
+
+	out.Reset()
+	code = runCacheCommand([]string{"clear"}, nil, "", &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 0, "Cleared the slug cache.", "")
+	if cache, _ := loadSlugCache(); len(cache.Slugs) != 0 {
+		t.Errorf("slugs after clear = %v, want none", cache.Slugs)
+	}
+	code = runCacheCommand([]string{"clear"}, nil, "", &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 0, "", "")
+
+	errb.Reset()
+	code = runCacheCommand([]string{"nope"}, nil, "", &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 1, "", "Unknown cache subcommand: nope")
+}
+
+func TestRunCompletionCommand(t *testing.T) {
+	setHome(t, t.TempDir())
+	fetches := 0
+	client := &FakeClient{FetchGoalsFunc: func() ([]Goal, error) {
+		fetches++
+		return []Goal{{Slug: "workout"}, {Slug: "reading"}, {Slug: "water"}}, nil
+	}}
+
+	var out, errb bytes.Buffer
+	code := runCompletionCommand([]string{"--goals"}, client, "alice", &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 0, "", "")
+	if out.String() != "reading\nwater\nworkout\n" {
+		t.Errorf("slugs = %q", out.String())
+	}
+
+	// The cache filled by the first call serves the next without a fetch.
+	out.Reset()
+	code = runCompletionCommand([]string{"--goals", "wkt"}, client, "alice", &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 0, "", "")
+	if out.String() != "workout\n" || fetches != 1 {
+		t.Errorf("fuzzy slugs = %q after %d fetches, want workout after 1", out.String(), fetches)
+	}
+
+	// Another account's cache isn't used.
+	out.Reset()
+	runCompletionCommand([]string{"--goals"}, client, "bob", &out, &errb)
+	if fetches != 2 {
+		t.Errorf("fetches = %d, want a refetch for another user", fetches)
+	}
+
+	// Without a client (a locked config) the cache is all there is.
+	out.Reset()
+	code = runCompletionCommand([]string{"--goals"}, nil, "", &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 0, "", "")
+	if out.String() != "reading\nwater\nworkout\n" {
+		t.Errorf("cached slugs = %q", out.String())
+	}
+
+	code = runCompletionCommand(nil, client, "alice", &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 1, "", "Usage: buzz completion --goals")
+
+	setHome(t, t.TempDir())
+	code = runCompletionCommand([]string{"--goals"}, nil, "", &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 1, "", "buzz cache refresh")
+}
+
+func TestRenameSlugInSlugCache(t *testing.T) {
+	setHome(t, t.TempDir())
+	if err := renameSlugInSlugCache("run", "jog"); err != nil {
+		t.Fatalf("rename without a cache: %v", err)
+	}
+	path, _ := getSlugCachePath()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("rename created a slug cache")
+	}
+
+	if err := saveSlugCache("alice", []Goal{{Slug: "run"}, {Slug: "write"}, {Slug: "abs"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := renameSlugInSlugCache("run", "jog"); err != nil {
+		t.Fatal(err)
+	}
+	cache, err := loadSlugCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cache.Username != "alice" || !reflect.DeepEqual(cache.Slugs, []string{"abs", "jog", "write"}) {
+		t.Errorf("cache = %+v, want jog in place of run", cache)
+	}
+}
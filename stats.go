@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const statsUsage = "Usage: buzz stats [--self]"
+
+// accountStats is the account-wide summary `buzz stats` prints.
+type accountStats struct {
+	Goals    int            `json:"goals"`
+	ByColor  map[string]int `json:"by_color"` // goals per urgency colour, "red" to "gray"
+	Pledged  float64        `json:"pledged"`  // sum of the goals' current pledges
+	Manual   int            `json:"manual"`   // goals without autodata
+	Autodata int            `json:"autodata"`
+	Due24h   int            `json:"due_24h"` // goals due in the next 24 hours, not counting ones complete
+}
+
+// summarizeGoals computes the account summary for goals at now.
+func summarizeGoals(goals []Goal, now time.Time) accountStats {
+	s := accountStats{Goals: len(goals), ByColor: map[string]int{}}
+	for _, color := range urgencyColors {
+		s.ByColor[color] = 0
+	}
+	for _, g := range goals {
+		s.ByColor[UrgencyFor(g.Safebuf).String()]++
+		s.Pledged += g.Pledge
+		if g.Autodata == "" {
+			s.Manual++
+		} else {
+			s.Autodata++
+		}
+		if !IsEndValueReached(g) && IsDueWithinAt(g.Losedate, 24*time.Hour, now) {
+			s.Due24h++
+		}
+	}
+	return s
+}
+
+// renderStats lays the summary out as labelled lines, with the colour counts
+// drawn in their colours.
+func renderStats(s accountStats) string {
+	colors := make([]string, len(urgencyColors))
+	for i, color := range urgencyColors {
+		colors[i] = Urgency(i).TextStyle().Render(fmt.Sprintf("%d %s", s.ByColor[color], color))
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Goals:        %d\n", s.Goals)
+	fmt.Fprintf(&sb, "By buffer:    %s\n", strings.Join(colors, ", "))
+	fmt.Fprintf(&sb, "Pledged:      $%s\n", formatPledge(s.Pledged))
+	fmt.Fprintf(&sb, "Manual:       %d\n", s.Manual)
+	fmt.Fprintf(&sb, "Autodata:     %d\n", s.Autodata)
+	fmt.Fprintf(&sb, "Due in 24h:   %d\n", s.Due24h)
+	return sb.String()
+}
+
+// handleStatsCommand prints an account-wide summary of the goals, or with
+// --self the local usage statistics.
+func handleStatsCommand() {
+	statsFlags := flag.NewFlagSet("stats", flag.ContinueOnError)
+	// Silence the flag package's own output; we print our own usage.
+	statsFlags.SetOutput(io.Discard)
+	self := statsFlags.Bool("self", false, "Show how often you use each buzz command")
+	if err := statsFlags.Parse(os.Args[2:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fmt.Println(statsUsage)
+			return
+		}
+		fmt.Fprintf(os.Stderr, "Error parsing flags: %s\n", err)
+		fmt.Fprintln(os.Stderr, statsUsage)
+		os.Exit(2)
+	}
+	if statsFlags.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "Error: unexpected argument %q\n", statsFlags.Arg(0))
+		fmt.Fprintln(os.Stderr, statsUsage)
+		os.Exit(1)
+	}
+
+	// --self is about buzz rather than the account, so needs no login.
+	if *self {
+		os.Exit(runSelfStats(outputFormat, os.Stdout, os.Stderr))
+	}
+
+	client, ok := loadClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+
+	code := runStatsCommand(context.Background(), client, goalFilter, outputFormat, time.Now(), os.Stdout, os.Stderr)
+	if code == 0 && outputFormat == "table" {
+		fmt.Print(getUpdateMessage())
+	}
+	os.Exit(code)
+}
+
+// runStatsCommand fetches the goals and prints their summary in the given
+// format. keep, when non-nil, is the global --filter. It returns the process
+// exit code.
+func runStatsCommand(ctx context.Context, client Client, keep func(Goal) bool, format string, now time.Time, out, errOut io.Writer) int {
+	goals, err := client.FetchGoals(ctx)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: Failed to fetch goals: %s\n", redactError(err))
+		return 1
+	}
+	stats := summarizeGoals(keepGoals(goals, keep), now)
+
+	switch format {
+	case "jsonl":
+		err = writeJSONL(out, []accountStats{stats})
+	case "json":
+		var b []byte
+		if b, err = json.MarshalIndent(stats, "", "  "); err == nil {
+			fmt.Fprintln(out, string(b))
+		}
+	case "csv":
+		headers := []string{"goals"}
+		row := []string{strconv.Itoa(stats.Goals)}
+		for _, color := range urgencyColors {
+			headers = append(headers, color)
+			row = append(row, strconv.Itoa(stats.ByColor[color]))
+		}
+		headers = append(headers, "pledged", "manual", "autodata", "due_24h")
+		row = append(row, strconv.FormatFloat(stats.Pledged, 'f', 2, 64), strconv.Itoa(stats.Manual), strconv.Itoa(stats.Autodata), strconv.Itoa(stats.Due24h))
+		var rendered string
+		if rendered, err = encodeCSV(headers, [][]string{row}); err == nil {
+			fmt.Fprint(out, rendered)
+		}
+	default:
+		fmt.Fprint(out, renderStats(stats))
+	}
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: %s\n", redactError(err))
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestSummarizeGoals(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	goalval, curval := 10.0, 10.0
+	goals := []Goal{
+		{Slug: "a", Safebuf: 0, Pledge: 5, Losedate: now.Add(3 * time.Hour).Unix()},
+		{Slug: "b", Safebuf: 1, Pledge: 30, Autodata: "fitbit", Losedate: now.Add(20 * time.Hour).Unix()},
+		{Slug: "c", Safebuf: 9, Pledge: 10, Losedate: now.Add(200 * time.Hour).Unix()},
+		{Slug: "done", Safebuf: 0, Pledge: 0, Losedate: now.Add(time.Hour).Unix(), Goalval: &goalval, Curval: &curval, Dir: 1},
+	}
+	s := summarizeGoals(goals, now)
+	if s.Goals != 4 || s.Pledged != 45 || s.Manual != 3 || s.Autodata != 1 || s.Due24h != 2 {
+		t.Errorf("stats = %+v", s)
+	}
+	if s.ByColor["red"] != 2 || s.ByColor["orange"] != 1 || s.ByColor["gray"] != 1 || s.ByColor["blue"] != 0 {
+		t.Errorf("by color = %v", s.ByColor)
+	}
+}
+
+func TestRunStatsCommand(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	client := &FakeClient{FetchGoalsFunc: func() ([]Goal, error) {
+		return []Goal{{Slug: "a", Pledge: 5, Safebuf: 3, Losedate: now.Add(72 * time.Hour).Unix()}}, nil
+	}}
+	var out, errb bytes.Buffer
+	code := runStatsCommand(context.Background(), client, nil, "table", now, &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 0, "Pledged:      $5", "")
+
+	out.Reset()
+	code = runStatsCommand(context.Background(), client, nil, "json", now, &out, &errb)
+	var s accountStats
+	if code != 0 || json.Unmarshal(out.Bytes(), &s) != nil || s.ByColor["green"] != 1 || s.Due24h != 0 {
+		t.Errorf("json = %d, %q", code, out.String())
+	}
+
+	out.Reset()
+	code = runStatsCommand(context.Background(), client, nil, "csv", now, &out, &errb)
+	checkResult(t, code, out.String(), errb.String(), 0, "goals,red,orange,blue,green,gray,pledged,manual,autodata,due_24h\n1,0,0,0,1,0,5.00,1,0,0\n", "")
+
+	client.FetchGoalsFunc = nil
+	code = runStatsCommand(context.Background(), client, nil, "table", now, &out, &errb)
+	checkResult(t, code, "", errb.String(), 1, "", "Failed to fetch goals")
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+const stepdownUsage = "Usage: buzz stepdown <goalslug>"
+
+// handleStepdownCommand schedules a decrease of a goal's pledge. Beeminder
+// only lowers it past the akrasia horizon, so this reports when.
+func handleStepdownCommand() {
+	slug, code, done := parseStepdownArgs(os.Args[2:], os.Stdout, os.Stderr)
+	if done {
+		os.Exit(code)
+	}
+
+	client, ok := loadClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+
+	code = runStepdownCommand(context.Background(), client, slug, time.Now(), os.Stdout, os.Stderr)
+	if code == 0 {
+		fmt.Print(getUpdateMessage())
+	}
+	os.Exit(code)
+}
+
+// parseStepdownArgs parses `buzz stepdown` arguments, returning the goal slug,
+// a process exit code, and done=true when the caller should stop (help shown,
+// or a usage error).
+func parseStepdownArgs(args []string, stdout, stderr io.Writer) (string, int, bool) {
+	stepdownFlags := flag.NewFlagSet("stepdown", flag.ContinueOnError)
+	// Silence the flag package's own output; we print our own usage.
+	stepdownFlags.SetOutput(io.Discard)
+	if err := stepdownFlags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stdout, stepdownUsage)
+			return "", 0, true
+		}
+		fmt.Fprintf(stderr, "Error parsing flags: %s\n", err)
+		fmt.Fprintln(stderr, stepdownUsage)
+		return "", 2, true
+	}
+
+	rest := stepdownFlags.Args()
+	if len(rest) != 1 {
+		if len(rest) == 0 {
+			fmt.Fprintln(stderr, "Error: Missing required argument")
+		} else {
+			fmt.Fprintf(stderr, "Error: Too many arguments: %v\n", rest[1:])
+		}
+		fmt.Fprintln(stderr, stepdownUsage)
+		return "", 1, true
+	}
+	return rest[0], 0, false
+}
+
+// stepdownAt returns when the goal's scheduled stepdown takes effect, with
+// ok=false when none is scheduled.
+func stepdownAt(g Goal) (int64, bool) {
+	if g.Contract == nil || g.Contract.StepdownAt == nil || *g.Contract.StepdownAt == 0 {
+		return 0, false
+	}
+	return *g.Contract.StepdownAt, true
+}
+
+// runStepdownCommand schedules a stepdown of the goal's pledge and prints when
+// it takes effect. A goal that already has one scheduled is left alone. It
+// returns the process exit code.
+func runStepdownCommand(ctx context.Context, client Client, goalSlug string, now time.Time, out, errOut io.Writer) int {
+	current, err := client.FetchGoal(ctx, goalSlug)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: Failed to fetch goal: %s\n", redactError(err))
+		return 1
+	}
+	if at, ok := stepdownAt(*current); ok {
+		fmt.Fprintf(out, "%s's $%s pledge already has a stepdown scheduled for %s.\n", goalSlug, formatPledge(current.Pledge), FormatAbsoluteDeadlineAt(at, now))
+		return 0
+	}
+
+	goal, err := client.StepDownGoal(ctx, goalSlug)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: Failed to step down pledge: %s\n", redactError(err))
+		return 1
+	}
+
+	// Beeminder should say when; if it doesn't, the akrasia horizon is the
+	// earliest it can be.
+	when := "in a week, at the akrasia horizon"
+	if at, ok := stepdownAt(*goal); ok {
+		when = "on " + FormatAbsoluteDeadlineAt(at, now)
+	}
+	fmt.Fprintf(out, "Scheduled a stepdown of %s's $%s pledge. The decrease takes effect %s.\n", goal.Slug, formatPledge(current.Pledge), when)
+	return 0
+}
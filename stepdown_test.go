@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParseStepdownArgs(t *testing.T) {
+	for _, tt := range []struct {
+		args     []string
+		want     string
+		wantCode int
+		wantDone bool
+	}{
+		{[]string{"reading"}, "reading", 0, false},
+		{[]string{}, "", 1, true},
+		{[]string{"reading", "writing"}, "", 1, true},
+		{[]string{"--yes", "reading"}, "", 2, true},
+		{[]string{"--help"}, "", 0, true},
+	} {
+		slug, code, done := parseStepdownArgs(tt.args, &bytes.Buffer{}, &bytes.Buffer{})
+		if slug != tt.want || code != tt.wantCode || done != tt.wantDone {
+			t.Errorf("parseStepdownArgs(%v) = %q, %d, %v", tt.args, slug, code, done)
+		}
+	}
+}
+
+func TestRunStepdownCommand(t *testing.T) {
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.Local)
+	at := time.Date(2024, 1, 17, 23, 59, 0, 0, time.Local).Unix()
+	var current Goal
+	var stepped []string
+	stepdownResult := &Goal{Slug: "reading", Pledge: 10, Contract: &Contract{Amount: 10, StepdownAt: &at}}
+	client := &FakeClient{
+		FetchGoalFunc: func(slug string) (*Goal, error) { return &current, nil },
+		StepDownGoalFunc: func(slug string) (*Goal, error) {
+			stepped = append(stepped, slug)
+			return stepdownResult, nil
+		},
+	}
+	run := func() (int, string, string) {
+		var out, errOut bytes.Buffer
+		code := runStepdownCommand(context.Background(), client, "reading", now, &out, &errOut)
+		return code, out.String(), errOut.String()
+	}
+
+	current = Goal{Slug: "reading", Pledge: 10, Contract: &Contract{Amount: 10}}
+	code, out, errOut := run()
+	checkResult(t, code, out, errOut, 0, "Scheduled a stepdown of reading's $10 pledge. The decrease takes effect on Jan 17 11:59 PM.\n", "")
+
+	stepdownResult = &Goal{Slug: "reading", Pledge: 10}
+	code, out, errOut = run()
+	checkResult(t, code, out, errOut, 0, "Scheduled a stepdown of reading's $10 pledge. The decrease takes effect in a week, at the akrasia horizon.\n", "")
+
+	current = Goal{Slug: "reading", Pledge: 10, Contract: &Contract{Amount: 10, StepdownAt: &at}}
+	code, out, errOut = run()
+	checkResult(t, code, out, errOut, 0, "reading's $10 pledge already has a stepdown scheduled for Jan 17 11:59 PM.\n", "")
+	if len(stepped) != 2 {
+		t.Errorf("stepped = %v, want two stepdowns", stepped)
+	}
+
+	current = Goal{Slug: "reading", Pledge: 10}
+	client.StepDownGoalFunc = func(slug string) (*Goal, error) { return nil, errFakeNotConfigured }
+	code, out, errOut = run()
+	checkResult(t, code, out, errOut, 1, "", "Error: Failed to step down pledge: FakeClient method not configured for this test\n")
+}
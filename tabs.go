@@ -0,0 +1,184 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// tab is one of the TUI's top-level screens, listed in the tab bar and
+// switched with Tab/Shift+Tab or its number key.
+type tab int
+
+const (
+	tabGoals    tab = iota // the goal grid, with its modals and forms
+	tabReview              // one goal at a time, with its details and chart
+	tabActivity            // recent datapoints across goals, newest first
+	tabReports             // a weekly report and the buffer histogram
+)
+
+// tabNames are the tab bar labels, indexed by tab.
+var tabNames = []string{"Goals", "Review", "Activity", "Reports"}
+
+// tabBarHeight is the rows the tab bar takes above a tab's own view. The
+// Goals tab draws it on its header line instead, so its layout is unchanged.
+const tabBarHeight = 1
+
+// renderTabBar draws the tab bar with active highlighted, each tab prefixed
+// with the number key that selects it.
+func renderTabBar(active tab) string {
+	activeStyle := lipgloss.NewStyle().Reverse(true).Bold(true)
+	inactiveStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	labels := make([]string, len(tabNames))
+	for i, name := range tabNames {
+		label := " " + strconv.Itoa(i+1) + " " + name + " "
+		if tab(i) == active {
+			labels[i] = activeStyle.Render(label)
+		} else {
+			labels[i] = inactiveStyle.Render(label)
+		}
+	}
+	return strings.Join(labels, " ")
+}
+
+// tabForKey returns the tab a number key selects.
+func tabForKey(key string) (tab, bool) {
+	n, err := strconv.Atoi(key)
+	if err != nil || n < 1 || n > len(tabNames) {
+		return 0, false
+	}
+	return tab(n - 1), true
+}
+
+// switchTab shows tab t, returning any command it needs (e.g. the Review
+// tab fetching the current goal's details).
+func (m *appModel) switchTab(t tab) tea.Cmd {
+	m.tab = t
+	switch t {
+	case tabReview:
+		return m.syncReview()
+	case tabActivity:
+		return m.startActivity()
+	case tabReports:
+		return m.syncReports()
+	}
+	return nil
+}
+
+// cycleTab moves to the next tab, or the previous one when reverse is true,
+// wrapping around.
+func (m *appModel) cycleTab(reverse bool) tea.Cmd {
+	n := tab(len(tabNames))
+	if reverse {
+		return m.switchTab((m.tab + n - 1) % n)
+	}
+	return m.switchTab((m.tab + 1) % n)
+}
+
+// syncReview points the Review tab at the goals the grid is showing (after
+// any saved filter or search), in slug order, starting it on first use.
+func (m *appModel) syncReview() tea.Cmd {
+	if !m.reviewStarted {
+		m.review = initialReviewModel(nil, m.config)
+		m.review.client = m.client
+		m.review.ctx = m.ctx
+		m.reviewStarted = true
+		m.resizeTabs()
+	}
+	goals := append([]Goal(nil), m.getDisplayGoals()...)
+	SortGoalsBySlug(goals)
+	return m.review.setGoals(goals)
+}
+
+// startActivity fetches the Activity tab's feed the first time it's shown;
+// after that it refreshes with the goals or on request.
+func (m *appModel) startActivity() tea.Cmd {
+	if m.activityStarted {
+		return nil
+	}
+	m.activity = activityModel{ctx: m.ctx, client: m.client}
+	m.activityStarted = true
+	m.resizeTabs()
+	return m.activity.refresh()
+}
+
+// syncReports hands the Reports tab the goals the grid is showing, for its
+// buffer histogram, fetching its datapoints the first time it's shown.
+func (m *appModel) syncReports() tea.Cmd {
+	m.reports.goals = m.getDisplayGoals()
+	if m.reportsStarted {
+		return nil
+	}
+	m.reports = reportsModel{ctx: m.ctx, client: m.client, goals: m.reports.goals}
+	m.reportsStarted = true
+	m.resizeTabs()
+	return m.reports.refresh()
+}
+
+// resizeTabs sizes the tabs other than Goals to the terminal, less the tab
+// bar.
+func (m *appModel) resizeTabs() {
+	if m.width == 0 {
+		return
+	}
+	size := tea.WindowSizeMsg{Width: m.width, Height: max(1, m.height-tabBarHeight)}
+	if m.reviewStarted {
+		updated, _ := m.review.Update(size)
+		m.review = updated.(reviewModel)
+	}
+	if m.activityStarted {
+		m.activity, _ = m.activity.Update(size)
+	}
+	if m.reportsStarted {
+		m.reports, _ = m.reports.Update(size)
+	}
+}
+
+// handleOtherTabKey handles a key press on a tab other than Goals:
+// Tab/Shift+Tab and the number keys switch tabs, Esc goes back to Goals, and
+// everything else belongs to the tab itself.
+func handleOtherTabKey(m model, msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch key := msg.String(); key {
+	case "tab", "shift+tab":
+		return m, m.appModel.cycleTab(key == "shift+tab")
+	case "esc":
+		return m, m.appModel.switchTab(tabGoals)
+	default:
+		if t, ok := tabForKey(key); ok {
+			return m, m.appModel.switchTab(t)
+		}
+	}
+	return m, m.appModel.updateTab(msg)
+}
+
+// updateTab passes a message to the active tab other than Goals.
+func (m *appModel) updateTab(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	switch {
+	case m.tab == tabReview && m.reviewStarted:
+		var updated tea.Model
+		updated, cmd = m.review.Update(msg)
+		m.review = updated.(reviewModel)
+	case m.tab == tabActivity && m.activityStarted:
+		m.activity, cmd = m.activity.Update(msg)
+	case m.tab == tabReports && m.reportsStarted:
+		m.reports, cmd = m.reports.Update(msg)
+	}
+	return cmd
+}
+
+// viewTab renders the active tab other than Goals under the tab bar.
+func (m *appModel) viewTab() string {
+	var body string
+	switch {
+	case m.tab == tabReview && m.reviewStarted:
+		body = m.review.View()
+	case m.tab == tabActivity && m.activityStarted:
+		body = m.activity.View()
+	case m.tab == tabReports && m.reportsStarted:
+		body = m.reports.View()
+	}
+	return renderTabBar(m.tab) + "\n" + body
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestTabSwitching(t *testing.T) {
+	var fetched []string
+	client := &FakeClient{
+		FetchGoalWithDatapointsFunc: func(slug string) (*Goal, error) {
+			fetched = append(fetched, slug)
+			return &Goal{Slug: slug}, nil
+		},
+	}
+	m := model{state: "app", appModel: appModel{
+		ctx:    context.Background(),
+		client: client,
+		config: &Config{Username: "u"},
+		goals:  []Goal{{Slug: "zeta"}, {Slug: "alpha"}},
+		width:  100,
+		height: 30,
+	}}
+
+	// 2 opens the Review tab on the first goal by slug, fetching its details.
+	tm, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2")})
+	m = mustModel(t, tm)
+	if m.appModel.tab != tabReview || !m.appModel.reviewStarted {
+		t.Fatalf("tab = %v, reviewStarted = %v", m.appModel.tab, m.appModel.reviewStarted)
+	}
+	if cmd == nil {
+		t.Fatal("opening the Review tab should fetch the current goal's details")
+	}
+	m = mustModel(t, mustTeaModel(m.Update(cmd())))
+	if strings.Join(fetched, ",") != "alpha" {
+		t.Errorf("fetched %v, want [alpha]", fetched)
+	}
+	view := m.View()
+	if !strings.Contains(view, "1 Goals") || !strings.Contains(view, "Goal: alpha") {
+		t.Errorf("review view lacks the tab bar or goal:\n%s", view)
+	}
+
+	// Keys on the Review tab go to the review.
+	m = mustModel(t, mustTeaModel(m.Update(tea.KeyMsg{Type: tea.KeyRight})))
+	if m.appModel.review.current != 1 {
+		t.Errorf("right arrow should move the review on, current = %d", m.appModel.review.current)
+	}
+
+	// Esc goes back to the grid rather than quitting.
+	tm, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	m = mustModel(t, tm)
+	if m.appModel.tab != tabGoals || cmd != nil {
+		t.Errorf("esc: tab = %v, cmd = %v", m.appModel.tab, cmd)
+	}
+	if view := m.View(); !strings.Contains(view, "Beeminder Goals - u") || !strings.Contains(view, "2 Review") {
+		t.Errorf("grid view lacks the tab bar:\n%s", view)
+	}
+
+	// Tab cycles back to Review, which stays on the goal it was showing.
+	m = mustModel(t, mustTeaModel(m.Update(tea.KeyMsg{Type: tea.KeyTab})))
+	if m.appModel.tab != tabReview || m.appModel.review.current != 1 {
+		t.Errorf("tab: tab = %v, review.current = %d", m.appModel.tab, m.appModel.review.current)
+	}
+}
+
+func TestTabKeysIgnoredWhileBusy(t *testing.T) {
+	// Number keys are search text while searching.
+	m := model{state: "app", appModel: appModel{searchActive: true, goals: []Goal{{Slug: "a"}}}}
+	m = mustModel(t, mustTeaModel(m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2")})))
+	if m.appModel.tab != tabGoals || m.appModel.searchQuery != "2" {
+		t.Errorf("tab = %v, searchQuery = %q", m.appModel.tab, m.appModel.searchQuery)
+	}
+
+	// And tab moves between fields in the create form.
+	m = model{state: "app", appModel: appModel{mode: modeCreateGoal, createGoal: newCreateGoalForm()}}
+	m = mustModel(t, mustTeaModel(m.Update(tea.KeyMsg{Type: tea.KeyTab})))
+	if m.appModel.tab != tabGoals || m.appModel.createGoal.focus != 1 {
+		t.Errorf("tab = %v, createGoal.focus = %d", m.appModel.tab, m.appModel.createGoal.focus)
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GoalTemplate is a named preset of goal settings, defined under "templates"
+// in ~/.buzzrc, that `buzz create --template` and the TUI's create form start
+// from. Unset fields keep the usual defaults, and explicit flags win.
+type GoalTemplate struct {
+	Type     string   `json:"type,omitempty"`     // Goal type name, label, or menu number
+	Units    string   `json:"units,omitempty"`    // Goal units
+	Rate     *float64 `json:"rate,omitempty"`     // Rate per the goal's rate units
+	Goalval  *float64 `json:"goalval,omitempty"`  // Goal value
+	Goaldate *int64   `json:"goaldate,omitempty"` // Goal date as an epoch timestamp
+	Deadline string   `json:"deadline,omitempty"` // Time of day, e.g. "11:00 PM" or "23:00"
+	Tags     []string `json:"tags,omitempty"`     // Tags set on the new goal
+}
+
+// templateNames returns the saved template names in sorted order.
+func templateNames(config *Config) []string {
+	names := make([]string, 0, len(config.Templates))
+	for name := range config.Templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupTemplate returns the saved template called name from config, checking
+// that its deadline parses so a typo fails before the goal is created.
+func lookupTemplate(config *Config, name string) (GoalTemplate, error) {
+	tmpl, ok := config.Templates[name]
+	if !ok {
+		if len(config.Templates) == 0 {
+			return GoalTemplate{}, fmt.Errorf("unknown template %q (no templates are defined in ~/.buzzrc)", name)
+		}
+		return GoalTemplate{}, fmt.Errorf("unknown template %q (defined: %s)", name, strings.Join(templateNames(config), ", "))
+	}
+	if _, _, err := tmpl.deadlineOffset(); err != nil {
+		return GoalTemplate{}, fmt.Errorf("template %q: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// deadlineOffset returns the template's deadline in seconds from midnight, and
+// whether it sets one at all.
+func (t GoalTemplate) deadlineOffset() (int, bool, error) {
+	if t.Deadline == "" {
+		return 0, false, nil
+	}
+	offset, err := parseTimeToDeadlineOffset(t.Deadline)
+	if err != nil {
+		return 0, false, err
+	}
+	return offset, true, nil
+}
+
+// hasTarget reports whether the template sets any of goaldate, goalval, and
+// rate. If it does, those it leaves unset are left blank rather than taking
+// their defaults, so the template decides which 2 of the 3 are given.
+func (t GoalTemplate) hasTarget() bool {
+	return t.Rate != nil || t.Goalval != nil || t.Goaldate != nil
+}
+
+// targetFields returns the template's goaldate, goalval, and rate as the
+// strings the create form and API take, "" for those it leaves unset.
+func (t GoalTemplate) targetFields() (goaldate, goalval, rate string) {
+	if t.Goaldate != nil {
+		goaldate = strconv.FormatInt(*t.Goaldate, 10)
+	}
+	if t.Goalval != nil {
+		goalval = strconv.FormatFloat(*t.Goalval, 'f', -1, 64)
+	}
+	if t.Rate != nil {
+		rate = strconv.FormatFloat(*t.Rate, 'f', -1, 64)
+	}
+	return goaldate, goalval, rate
+}
+
+// applyGoalTemplateSettings sets what a template configures beyond the create
+// call itself: the deadline and tags.
+func applyGoalTemplateSettings(ctx context.Context, client Client, slug string, tmpl GoalTemplate) error {
+	if offset, ok, err := tmpl.deadlineOffset(); err != nil {
+		return err
+	} else if ok {
+		if _, err := client.UpdateGoalDeadline(ctx, slug, offset); err != nil {
+			return fmt.Errorf("failed to set deadline: %w", err)
+		}
+	}
+	if len(tmpl.Tags) > 0 {
+		if _, err := client.UpdateGoalTags(ctx, slug, tmpl.Tags); err != nil {
+			return fmt.Errorf("failed to set tags: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLookupTemplate(t *testing.T) {
+	config := &Config{Templates: map[string]GoalTemplate{
+		"habit":  {Units: "sessions"},
+		"broken": {Deadline: "teatime"},
+	}}
+
+	if tmpl, err := lookupTemplate(config, "habit"); err != nil || tmpl.Units != "sessions" {
+		t.Errorf("lookupTemplate(habit) = %+v, %v", tmpl, err)
+	}
+	if _, err := lookupTemplate(config, "broken"); err == nil || !strings.Contains(err.Error(), `template "broken"`) {
+		t.Errorf("want a deadline error naming the template, got %v", err)
+	}
+	if _, err := lookupTemplate(config, "nope"); err == nil || !strings.Contains(err.Error(), "defined: broken, habit") {
+		t.Errorf("want an error listing the templates, got %v", err)
+	}
+	if _, err := lookupTemplate(&Config{}, "nope"); err == nil || !strings.Contains(err.Error(), "no templates") {
+		t.Errorf("want a no-templates error, got %v", err)
+	}
+}
+
+func TestCreateWithTemplate(t *testing.T) {
+	rate := 3.0
+	tmpl := GoalTemplate{Type: "Do More", Units: "sessions", Rate: &rate, Deadline: "11:00 PM", Tags: []string{"health", "daily"}}
+
+	req, code, done := parseCreateArgs(
+		[]string{"--template", "habit", "--goalval=100", "--units=minutes", "meditate", "Meditate daily"},
+		&bytes.Buffer{}, &bytes.Buffer{},
+	)
+	if done || code != 0 {
+		t.Fatalf("unexpected parse result: code=%d done=%v", code, done)
+	}
+	if req.template != "habit" || req.slug != "meditate" || req.title != "Meditate daily" {
+		t.Fatalf("unexpected fields: %+v", req)
+	}
+	req = req.withTemplate(tmpl)
+	// --units was passed, so it wins over the template's.
+	if req.goalType != "hustler" || req.gunits != "minutes" || req.rate != "3" || req.goalval != "100" {
+		t.Errorf("unexpected fields after template: %+v", req)
+	}
+	if !req.setDeadline || req.deadline != -3600 {
+		t.Errorf("template deadline not applied: set=%v val=%d", req.setDeadline, req.deadline)
+	}
+
+	var gotDeadline int
+	var gotTags []string
+	client := &FakeClient{
+		CreateGoalFunc: func(slug, _, _, _, _, _, _, _ string) (*Goal, error) { return &Goal{Slug: slug}, nil },
+		UpdateGoalDeadlineFunc: func(_ string, deadline int) (*Goal, error) {
+			gotDeadline = deadline
+			return &Goal{}, nil
+		},
+		UpdateGoalTagsFunc: func(_ string, tags []string) (*Goal, error) {
+			gotTags = tags
+			return &Goal{}, nil
+		},
+	}
+	var stdout, stderr bytes.Buffer
+	if c := doCreate(req, client, &stdout, &stderr); c != 0 {
+		t.Fatalf("expected exit 0, got %d (stderr: %s)", c, stderr.String())
+	}
+	if gotDeadline != -3600 || !slices.Equal(gotTags, []string{"health", "daily"}) {
+		t.Errorf("got deadline %d and tags %v", gotDeadline, gotTags)
+	}
+	if !strings.Contains(stdout.String(), "Set tags: health, daily") {
+		t.Errorf("missing tags message, got: %s", stdout.String())
+	}
+}
+
+func TestParseCreateArgsTemplatePositionals(t *testing.T) {
+	// Without --template, the slug and title may still be arguments.
+	var stderr bytes.Buffer
+	if req, code, done := parseCreateArgs([]string{"meditate", "Meditate", "--units=minutes"}, &bytes.Buffer{}, &stderr); done || code != 0 || req.slug != "meditate" || req.title != "Meditate" || req.gunits != "minutes" {
+		t.Errorf("positional slug without --template: %+v, code=%d done=%v", req, code, done)
+	}
+
+	// A third positional argument is one too many.
+	stderr.Reset()
+	_, code, done := parseCreateArgs([]string{"--template=habit", "a", "b", "c"}, &bytes.Buffer{}, &stderr)
+	if !done || code != 1 || !strings.Contains(stderr.String(), "unexpected argument(s): c") {
+		t.Errorf("extra positional: code=%d done=%v stderr=%s", code, done, stderr.String())
+	}
+}
+
+func TestApplyGoalTemplateSettings(t *testing.T) {
+	var calls []string
+	client := &FakeClient{
+		UpdateGoalDeadlineFunc: func(slug string, _ int) (*Goal, error) {
+			calls = append(calls, "deadline "+slug)
+			return &Goal{}, nil
+		},
+		UpdateGoalTagsFunc: func(slug string, _ []string) (*Goal, error) {
+			calls = append(calls, "tags "+slug)
+			return &Goal{}, nil
+		},
+	}
+
+	if err := applyGoalTemplateSettings(context.Background(), client, "g", GoalTemplate{}); err != nil || len(calls) != 0 {
+		t.Errorf("empty template: err %v, calls %v", err, calls)
+	}
+
+	if err := applyGoalTemplateSettings(context.Background(), client, "g", GoalTemplate{Deadline: "23:00", Tags: []string{"x"}}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(calls, ",") != "deadline g,tags g" {
+		t.Errorf("calls = %v", calls)
+	}
+
+	client.UpdateGoalTagsFunc = nil
+	if err := applyGoalTemplateSettings(context.Background(), client, "g", GoalTemplate{Tags: []string{"x"}}); err == nil || !strings.Contains(err.Error(), "failed to set tags") {
+		t.Errorf("want a tags error, got %v", err)
+	}
+}
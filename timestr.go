@@ -15,7 +15,7 @@ import (
 //     view baremin math.
 //   - The same HH:MM[:SS] format converted to decimal hours (isTimeFormat /
 //     timeToDecimalHours), used by `buzz add 1:30` to submit hour-valued
-//     datapoints.
+//     datapoints, or to minutes for goals measured in minutes (timeToUnits).
 //   - Wall-clock-of-day strings ("3:00 PM" or "15:00") and Beeminder's
 //     seconds-from-midnight deadline offset, converted by
 //     parseTimeToDeadlineOffset / formatDueTime, used by `buzz deadline`.
@@ -179,6 +179,35 @@ func timeToDecimalHours(timeStr string) (float64, bool) {
 	return decimalHours, true
 }
 
+// timeUnitsPerHour maps goal units that measure time to how many of them make
+// an hour. Units are matched case-insensitively.
+var timeUnitsPerHour = map[string]float64{
+	"hour": 1, "hours": 1, "hr": 1, "hrs": 1,
+	"minute": 60, "minutes": 60, "min": 60, "mins": 60,
+}
+
+// isTimeUnits reports whether a goal's units look like a measure of time, so
+// that a value typed as "1:30" makes sense for it.
+func isTimeUnits(gunits string) bool {
+	_, ok := timeUnitsPerHour[strings.ToLower(strings.TrimSpace(gunits))]
+	return ok
+}
+
+// timeToUnits converts a time string (HH:MM or HH:MM:SS) to the goal's
+// units: decimal hours, or minutes for a goal measured in minutes. Goals with
+// other units get decimal hours, as timeToDecimalHours. Returns false if the
+// format is invalid.
+func timeToUnits(timeStr, gunits string) (float64, bool) {
+	hours, ok := timeToDecimalHours(timeStr)
+	if !ok {
+		return 0, false
+	}
+	if perHour, ok := timeUnitsPerHour[strings.ToLower(strings.TrimSpace(gunits))]; ok {
+		return hours * perHour, true
+	}
+	return hours, true
+}
+
 // parseTimeToDeadlineOffset parses a time string (e.g., "3:00 PM", "15:00") into
 // a deadline offset in seconds from midnight, as used by the Beeminder API.
 func parseTimeToDeadlineOffset(timeStr string) (int, error) {
@@ -219,7 +248,7 @@ func parseTimeToDeadlineOffset(timeStr string) (int, error) {
 }
 
 // formatDueTime formats the deadline offset (seconds from midnight) as a time
-// string. Negative offset means before midnight, positive means after midnight.
+// string on the preferred clock (clockLayout). Negative offset means before midnight, positive means after midnight.
 //
 // The offset is normalized into the [0, 86400) range before formatting so a
 // second-precision negative input like -3599 (which is 59:59 before midnight,
@@ -229,5 +258,5 @@ func formatDueTime(deadlineOffset int) string {
 	const secondsPerDay = 24 * 60 * 60
 	normalized := ((deadlineOffset % secondsPerDay) + secondsPerDay) % secondsPerDay
 	t := time.Unix(int64(normalized), 0).UTC()
-	return t.Format("3:04 PM")
+	return t.Format(clockLayout())
 }
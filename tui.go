@@ -3,9 +3,11 @@ package main
 import (
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 // Bubble Tea lifecycle for the top-level `model` defined in model.go.
@@ -16,6 +18,10 @@ import (
 // navigationTimeout is the duration of inactivity before the cell highlight is auto-disabled
 const navigationTimeout = 3 * time.Second
 
+// focusRefreshThreshold is how long the terminal must have been in the
+// background before regaining focus triggers a refresh.
+const focusRefreshThreshold = time.Minute
+
 func (m model) Init() tea.Cmd {
 	if m.state == "auth" {
 		return m.authModel.Init()
@@ -25,6 +31,7 @@ func (m model) Init() tea.Cmd {
 		loadGoalsCmd(m.appModel.ctx, m.appModel.client),
 		refreshTickCmd(),
 		checkRefreshFlagCmd(),
+		whatsNewCmd(),
 	)
 }
 
@@ -39,7 +46,26 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.state == "app" {
 			displayGoals := m.appModel.getDisplayGoals()
 			updateScrollForCursor(&m, len(displayGoals))
+			m.appModel.resizeTabs()
+		}
+	}
+
+	// ctrl+z suspends from either state. Bubble Tea releases the terminal,
+	// stops the process with SIGTSTP, and sends ResumeMsg after SIGCONT.
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+z" {
+			m.suspended = true
+			return m, tea.Suspend
 		}
+	case tea.ResumeMsg:
+		// The terminal may have been resized and the data gone stale while
+		// we were stopped, so re-measure and catch up with one refresh.
+		m.suspended = false
+		if m.state == "app" {
+			return m, tea.Batch(tea.WindowSize(), loadGoalsCmd(m.appModel.ctx, m.appModel.client))
+		}
+		return m, tea.WindowSize()
 	}
 
 	if m.state == "auth" {
@@ -74,7 +100,17 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	// Handle app state
-	return m.updateApp(msg)
+	return withSelection(m.updateApp(msg))
+}
+
+// withSelection follows an app update with a fetch for the details pane, on a
+// wide terminal, when the grid's selection has moved.
+func withSelection(tm tea.Model, cmd tea.Cmd) (tea.Model, tea.Cmd) {
+	m, ok := tm.(model)
+	if !ok || m.state != "app" || m.appModel.tab != tabGoals {
+		return tm, cmd
+	}
+	return m, tea.Batch(cmd, m.appModel.followSelection())
 }
 
 func (m model) updateApp(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -83,16 +119,45 @@ func (m model) updateApp(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case goalsLoadedMsg:
 		// Goals have been loaded from the API
 		m.appModel.loading = false
+		if isUnauthorized(msg.err) {
+			m.appModel.authExpired = true
+			return m, nil
+		}
 		if msg.err != nil {
-			m.appModel.err = msg.err
+			// A failed background refresh behind a modal is dropped rather
+			// than replacing the form with an error screen; the next tick
+			// retries.
+			if m.appModel.mode == modeBrowse {
+				m.appModel.err = msg.err
+			}
 		} else {
-			m.appModel.goals = msg.goals
+			m.appModel.receiveGoals(msg.goals)
 			m.appModel.err = nil
+			// Refetch the details pane's goal too, for its new datapoints.
+			m.appModel.paneSlug = ""
+			// Keep an open Review tab's goal list and the Activity and
+			// Reports tabs current too.
+			var cmds []tea.Cmd
+			if m.appModel.tab == tabReview {
+				cmds = append(cmds, m.appModel.syncReview())
+			}
+			if m.appModel.activityStarted {
+				cmds = append(cmds, m.appModel.activity.refresh())
+			}
+			if m.appModel.reportsStarted {
+				m.appModel.reports.goals = m.appModel.getDisplayGoals()
+				cmds = append(cmds, m.appModel.reports.refresh())
+			}
+			return m, tea.Batch(cmds...)
 		}
 		return m, nil
 
 	case refreshTickMsg:
-		// Time to refresh data
+		// Time to refresh data. A tick that arrives while suspended only
+		// reschedules; ResumeMsg does the catch-up refresh.
+		if m.appModel.refreshActive && m.suspended {
+			return m, refreshTickCmd()
+		}
 		if m.appModel.refreshActive {
 			return m, tea.Batch(
 				loadGoalsCmd(m.appModel.ctx, m.appModel.client),
@@ -104,18 +169,65 @@ func (m model) updateApp(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case datapointSubmittedMsg:
 		// Datapoint submission completed
 		m.appModel.datapoint.submitting = false
+		if isUnauthorized(msg.err) {
+			m.appModel.authExpired = true
+			return m, nil
+		}
 		if msg.err != nil {
 			m.appModel.datapoint.err = fmt.Sprintf("Failed to submit: %v", msg.err)
 		} else {
 			// Success - exit input mode (back to goal detail) and refresh goals
-			// (without showing the full-app loading state)
+			// (without showing the full-app loading state), and the goal's
+			// details, which the add dropped from the cache, so the new
+			// datapoint shows
 			m.appModel.exitDatapointInput()
-			return m, loadGoalsCmd(m.appModel.ctx, m.appModel.client)
+			var bell tea.Cmd
+			if c := m.appModel.datapoint.celebration; c != "" {
+				m.appModel.showToast(c)
+				bell = ringBellCmd()
+			}
+			if m.appModel.modalGoal == nil {
+				return m, tea.Batch(loadGoalsCmd(m.appModel.ctx, m.appModel.client), bell)
+			}
+			return m, tea.Batch(
+				loadGoalsCmd(m.appModel.ctx, m.appModel.client),
+				loadGoalDetailsCmd(m.appModel.ctx, m.appModel.client, m.appModel.modalGoal.Slug),
+				bell,
+			)
+		}
+		return m, nil
+
+	case undoneMsg:
+		m.appModel.undoBusy = false
+		if isUnauthorized(msg.err) {
+			m.appModel.authExpired = true
+			return m, nil
+		}
+		switch {
+		case !msg.ok && msg.err == nil:
+			m.appModel.showToast("Nothing to undo")
+		case msg.err != nil:
+			m.appModel.showToast(fmt.Sprintf("Undo failed: %v", msg.err))
+		default:
+			m.appModel.undone[msg.entry.Datapoint.ID] = true
+			m.appModel.showToast(describeUndo(msg.entry))
+			// Refresh like a submission does, so the change shows
+			cmds := []tea.Cmd{loadGoalsCmd(m.appModel.ctx, m.appModel.client)}
+			if m.appModel.modalGoal != nil && m.appModel.modalGoal.Slug == msg.entry.Goal {
+				cmds = append(cmds, loadGoalDetailsCmd(m.appModel.ctx, m.appModel.client, msg.entry.Goal))
+			}
+			return m, tea.Batch(cmds...)
 		}
 		return m, nil
 
 	case goalDetailsLoadedMsg:
 		// Goal details with datapoints have been loaded
+		m.appModel.paneLoading = false
+		if msg.goal != nil {
+			if g := m.appModel.selectedGoal(); g != nil && g.Slug == msg.goal.Slug {
+				m.appModel.paneGoal = msg.goal
+			}
+		}
 		if msg.err != nil {
 			// Error loading goal details - continue with basic goal info
 			return m, nil
@@ -128,24 +240,76 @@ func (m model) updateApp(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case lastValueLoadedMsg:
+		// The last datapoint value for the datapoint form has been fetched
+		if m.appModel.mode == modeDatapointInput && m.appModel.modalGoal != nil && m.appModel.modalGoal.Slug == msg.slug {
+			m.appModel.datapoint.fillLastValue(msg.value, msg.err)
+		}
+		return m, nil
+
 	case goalCreatedMsg:
 		// Goal creation completed
 		m.appModel.createGoal.creating = false
-		if msg.err != nil {
+		if isUnauthorized(msg.err) {
+			m.appModel.authExpired = true
+			return m, nil
+		}
+		if msg.err != nil && msg.goal != nil {
+			// Created, but the template's follow-up settings failed; keep
+			// the form open so the error is seen, rather than retried.
+			m.appModel.createGoal.err = fmt.Sprintf("Goal %s created, but %v (Esc to close)", msg.goal.Slug, msg.err)
+		} else if msg.err != nil {
 			m.appModel.createGoal.err = fmt.Sprintf("Failed to create goal: %v", msg.err)
 		} else {
-			// Success - close the create form and refresh goals
+			// Success - close the create form, refresh goals, and go straight
+			// to adding the new goal's first datapoint, with its graph
+			// loading behind: a goal with no data is easily forgotten.
 			m.appModel.closeCreateGoal()
-			return m, loadGoalsCmd(m.appModel.ctx, m.appModel.client)
+			if msg.goal == nil {
+				return m, loadGoalsCmd(m.appModel.ctx, m.appModel.client)
+			}
+			m.appModel.startFirstDatapoint(msg.goal)
+			return m, tea.Batch(
+				loadGoalsCmd(m.appModel.ctx, m.appModel.client),
+				loadGoalDetailsCmd(m.appModel.ctx, m.appModel.client, msg.goal.Slug),
+			)
+		}
+		return m, nil
+
+	case chargeCreatedMsg:
+		// A charge, or its dry run, completed
+		if m.appModel.mode != modeCharge {
+			return m, nil
+		}
+		c := &m.appModel.charge
+		c.checking, c.charging = false, false
+		switch {
+		case isUnauthorized(msg.err):
+			m.appModel.authExpired = true
+		case msg.err != nil:
+			c.err = fmt.Sprintf("Failed to create charge: %s", redactError(msg.err))
+		case msg.dryrun:
+			c.preview = msg.charge
+		default:
+			c.preview = nil
+			c.done = msg.charge
 		}
 		return m, nil
 
 	case checkRefreshFlagMsg:
 		// Check if another process requested a refresh
+		if m.suspended {
+			return m, checkRefreshFlagCmd()
+		}
+		// Catch up as soon as the day rolls over, rather than showing
+		// yesterday's goals until the next refresh tick.
+		rolledOver := m.appModel.checkRollover(time.Now())
 		flagTimestamp := getRefreshFlagTimestamp()
-		if flagTimestamp > m.lastRefreshTimestamp {
+		if flagTimestamp > m.lastRefreshTimestamp || rolledOver {
 			// New refresh event detected - update our last processed timestamp
-			m.lastRefreshTimestamp = flagTimestamp
+			if flagTimestamp > m.lastRefreshTimestamp {
+				m.lastRefreshTimestamp = flagTimestamp
+			}
 			return m, tea.Batch(
 				loadGoalsCmd(m.appModel.ctx, m.appModel.client),
 				checkRefreshFlagCmd(), // Schedule next check
@@ -154,10 +318,51 @@ func (m model) updateApp(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// No new refresh event, but continue checking
 		return m, checkRefreshFlagCmd()
 
+	case goalDetailsMsg:
+		// The Review tab's lazy per-goal fetches land here even if another
+		// tab is now showing, so the review's cache stays warm.
+		if m.appModel.reviewStarted {
+			updated, _ := m.appModel.review.Update(msg)
+			m.appModel.review = updated.(reviewModel)
+		}
+		return m, nil
+
+	// Like goalDetailsMsg, these land whichever tab is showing.
+	case activityLoadedMsg:
+		m.appModel.activity, _ = m.appModel.activity.Update(msg)
+		return m, nil
+
+	case reportsLoadedMsg:
+		var cmd tea.Cmd
+		m.appModel.reports, cmd = m.appModel.reports.Update(msg)
+		return m, cmd
+
+	case whatsNewMsg:
+		m.appModel.openWhatsNew(msg.notes)
+		if m.appModel.mode == modeWhatsNew {
+			return m, recordRunVersionCmd()
+		}
+		return m, nil
+
+	case tea.BlurMsg:
+		m.appModel.blurredAt = time.Now()
+		return m, nil
+
+	case tea.FocusMsg:
+		// Returning to the window after a while (e.g. after lunch) reloads
+		// goals in the background so stale data isn't shown; quick
+		// alt-tabs don't cost an API call.
+		blurredAt := m.appModel.blurredAt
+		m.appModel.blurredAt = time.Time{}
+		if !blurredAt.IsZero() && time.Since(blurredAt) >= focusRefreshThreshold {
+			return m, loadGoalsCmd(m.appModel.ctx, m.appModel.client)
+		}
+		return m, nil
+
 	case navigationTimeoutMsg:
 		// Auto-disable highlight after inactivity
-		// Only disable if not in a goal modal or search
-		if !m.appModel.inGoalModal() && !m.appModel.searchActive {
+		// Only disable if not in a goal modal, search, or jump
+		if !m.appModel.inGoalModal() && !m.appModel.searchActive && !m.appModel.jumpActive {
 			// Check if enough time has elapsed since last navigation
 			elapsed := time.Since(m.appModel.lastNavigationTime)
 			if elapsed >= navigationTimeout {
@@ -170,8 +375,11 @@ func (m model) updateApp(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		return handleKeyPress(m, msg)
 
-	// Is it a mouse click?
+	// Is it a mouse click? (Other tabs take the mouse wheel to scroll.)
 	case tea.MouseMsg:
+		if m.appModel.tab != tabGoals {
+			return m, m.appModel.updateTab(msg)
+		}
 		// Only handle clicks when not in a modal
 		if m.appModel.mode == modeBrowse {
 			if msg.Action == tea.MouseActionRelease && msg.Button == tea.MouseButtonLeft {
@@ -194,6 +402,11 @@ func (m model) View() string {
 }
 
 func (m model) viewApp() string {
+	if m.appModel.authExpired {
+		return "Your Beeminder auth token is invalid or has expired.\n\n" +
+			"Press Enter to log in again, or q to quit.\n"
+	}
+
 	if m.appModel.loading {
 		return "Loading goals...\n\nPress q to quit.\n"
 	}
@@ -202,30 +415,89 @@ func (m model) viewApp() string {
 		return fmt.Sprintf("Error loading goals: %v\n\nPress q to quit.\n", m.appModel.err)
 	}
 
+	if m.appModel.tab != tabGoals {
+		return m.appModel.viewTab()
+	}
+
 	// Get the goals to display (filtered or all)
 	displayGoals := m.appModel.getDisplayGoals()
 
-	// Render the grid and footer
-	grid := RenderGrid(displayGoals, m.appModel.width, m.appModel.height, m.appModel.scrollRow, m.appModel.cursor, m.appModel.hasNavigated, m.appModel.config.Username, m.appModel.searchActive, m.appModel.searchQuery)
-	footer := RenderFooter(displayGoals, m.appModel.width, m.appModel.height, m.appModel.scrollRow, m.appModel.refreshActive)
+	// Render the grid and footer, beside the details pane on a wide terminal
+	layout := m.appModel.layout()
+	grid := RenderGrid(displayGoals, layout.gridWidth, m.appModel.height, m.appModel.scrollRow, m.appModel.cursor, m.appModel.hasNavigated, m.appModel.highlightedGoals(), m.appModel.newlyDue, m.appModel.cellTexts, m.appModel.config.Username, m.appModel.filterName, m.appModel.searchActive, m.appModel.searchQuery)
+	footer := RenderFooter(displayGoals, layout.gridWidth, m.appModel.height, m.appModel.scrollRow, m.appModel.refreshActive)
+
+	// The tab bar shares the grid's header line, so the layout is unchanged.
+	grid = renderTabBar(tabGoals) + "  " + grid
+	status := ""
+	if skew, ok := clockSkew(); ok {
+		if warning := clockSkewWarning(skew); warning != "" {
+			status = "\n" + UrgencyOverdue.TextStyle().Bold(true).Render("⚠ "+warning)
+		}
+	}
+	if m.appModel.jumpActive {
+		status += fmt.Sprintf("\nJump: '%s (Enter for details, Esc to stop)", m.appModel.jumpQuery)
+	}
+	toast := ""
+	if t := m.appModel.activeToast(); t != "" {
+		toast = "\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render(t)
+	}
+	baseView := grid + status + toast + footer
+
+	// Show the post-upgrade release notes if active
+	if m.appModel.mode == modeWhatsNew {
+		return RenderWhatsNewModal(m.appModel.width, m.appModel.height, m.appModel.whatsNew)
+	}
+
+	// Show the charge form if active
+	if m.appModel.mode == modeCharge {
+		c := &m.appModel.charge
+		return RenderChargeModal(m.appModel.width, m.appModel.height, c.amount(), c.note(), c.focus, c.err, c.checking, c.preview, c.charging, c.done)
+	}
 
-	baseView := grid + footer
+	// Show the command palette if active
+	if m.appModel.mode == modePalette {
+		p := &m.appModel.palette
+		return RenderPaletteModal(m.appModel.width, m.appModel.height, p.query, matchingPaletteActions(p.query), p.cursor)
+	}
 
 	// Show create goal modal if active
 	if m.appModel.mode == modeCreateGoal {
 		cg := &m.appModel.createGoal
 		modal := RenderCreateGoalModal(m.appModel.width, m.appModel.height, cg.slug(), cg.title(),
-			cg.goalType(), cg.gunits(), cg.goaldate(), cg.goalval(),
-			cg.rate(), cg.focus, cg.err, cg.creating)
+			cg.goalType(), cg.completion(), cg.gunits(), cg.goaldate(), cg.goalval(),
+			cg.rate(), cg.runits(), cg.template, m.appModel.config != nil && len(m.appModel.config.Templates) > 0,
+			cg.focus, cg.err, cg.fieldErrors(), cg.creating)
 		return modal
 	}
 
+	// On a wide terminal the goal detail lives in the pane, not a modal
+	if layout.split {
+		return besidePane(strings.TrimRight(baseView, "\n"), layout.gridWidth, m.appModel.viewDetailPane(layout.paneWidth))
+	}
+
 	// Show modal overlay if a goal detail is active
 	if m.appModel.inGoalModal() && m.appModel.modalGoal != nil {
 		dp := &m.appModel.datapoint
-		modal := RenderModal(m.appModel.modalGoal, m.appModel.width, m.appModel.height, dp.date(), dp.value(), dp.comment(), dp.focus, m.appModel.mode == modeDatapointInput, dp.err, dp.submitting)
-		return modal
+		modal := RenderModal(m.appModel.modalGoal, m.appModel.width, m.appModel.height, dp.date(), dp.value(), dp.comment(), dp.focus, m.appModel.mode == modeDatapointInput, dp.err, dp.fieldErrors(), dp.previewText(), dp.submitting)
+		return modal + toast
 	}
 
 	return baseView
 }
+
+// viewDetailPane renders the details pane: the open goal and its datapoint
+// form when there is one, otherwise the goal under the cursor.
+func (m *appModel) viewDetailPane(width int) string {
+	if m.inGoalModal() && m.modalGoal != nil {
+		goal := m.modalGoal
+		if len(goal.Datapoints) == 0 {
+			goal = m.paneGoalFor(goal)
+		}
+		dp := &m.datapoint
+		return RenderDetailPane(goal, width, m.height, dp.date(), dp.value(), dp.comment(), dp.focus, m.mode == modeDatapointInput, dp.err, dp.fieldErrors(), dp.previewText(), dp.submitting,
+			true, false, "Left/Right or h/l: Previous/Next goal • 'a': Add datapoint • 'u': Undo • ESC: Close")
+	}
+	return RenderDetailPane(m.paneGoalFor(m.selectedGoal()), width, m.height, "", "", "", 0, false, "", nil, "", false,
+		false, m.paneLoading, "Enter: Open this goal to add a datapoint")
+}
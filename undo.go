@@ -0,0 +1,291 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const undoUsage = "Usage: buzz undo [--yes|-y] [--force] <goalslug>"
+
+// getLastAddsPath returns the path to the file remembering buzz's adds.
+func getLastAddsPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".buzz_last_adds"), nil
+}
+
+// loadLastAdds loads the id of the datapoint `buzz add` last created on each
+// goal, by slug. A missing file is not an error; it returns an empty map.
+func loadLastAdds() (map[string]string, error) {
+	path, err := getLastAddsPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]string{}, nil
+		}
+		return nil, err
+	}
+	adds := map[string]string{}
+	if err := json.Unmarshal(data, &adds); err != nil {
+		return nil, err
+	}
+	return adds, nil
+}
+
+// setLastAdd records id as the datapoint last added to goalSlug, or forgets
+// the goal's last add when id is "".
+func setLastAdd(goalSlug, id string) error {
+	path, err := getLastAddsPath()
+	if err != nil {
+		return err
+	}
+	return updateFileAtomic(path, 0600, func(data []byte) ([]byte, error) {
+		adds := map[string]string{}
+		if data != nil && json.Unmarshal(data, &adds) != nil {
+			// An unreadable file only remembered adds; start it over.
+			adds = map[string]string{}
+		}
+		if id == "" {
+			delete(adds, goalSlug)
+		} else {
+			adds[goalSlug] = id
+		}
+		return json.MarshalIndent(adds, "", "  ")
+	})
+}
+
+// renameSlugInLastAdds moves the add remembered for oldSlug over to newSlug,
+// so `buzz undo` and the already-logged check still find it.
+func renameSlugInLastAdds(oldSlug, newSlug string) error {
+	path, err := getLastAddsPath()
+	if err != nil {
+		return err
+	}
+	return updateFileAtomic(path, 0600, func(data []byte) ([]byte, error) {
+		adds := map[string]string{}
+		if data == nil || json.Unmarshal(data, &adds) != nil {
+			return nil, errUnchanged
+		}
+		id, ok := adds[oldSlug]
+		if !ok {
+			return nil, errUnchanged
+		}
+		delete(adds, oldSlug)
+		adds[newSlug] = id
+		return json.MarshalIndent(adds, "", "  ")
+	})
+}
+
+// undoRequest is a parsed `buzz undo` invocation.
+type undoRequest struct {
+	goalSlug    string
+	skipConfirm bool
+	force       bool
+}
+
+// handleUndoCommand deletes the datapoint buzz add last added to a goal.
+func handleUndoCommand() {
+	req, code, done := parseUndoArgs(os.Args[2:], os.Stdout, os.Stderr)
+	if done {
+		os.Exit(code)
+	}
+	client, ok := loadClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+	os.Exit(runUndoCommand(context.Background(), client, req, os.Stdin, os.Stdout, os.Stderr))
+}
+
+// parseUndoArgs parses `buzz undo` arguments, returning the request, a
+// process exit code, and done=true when the caller should stop (help shown,
+// or a usage error).
+func parseUndoArgs(args []string, stdout, stderr io.Writer) (undoRequest, int, bool) {
+	fs := flag.NewFlagSet("undo", flag.ContinueOnError)
+	// Silence the flag package's own output; we print our own usage.
+	fs.SetOutput(io.Discard)
+	yes := fs.Bool("yes", false, "Skip confirmation prompt")
+	yesShort := fs.Bool("y", false, "Skip confirmation prompt (shorthand)")
+	force := fs.Bool("force", false, "Delete the latest datapoint even if buzz didn't add it")
+
+	// Flags may sit on either side of the slug, as with `buzz data`.
+	var positional []string
+	remaining := args
+	for len(remaining) > 0 {
+		if err := fs.Parse(remaining); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				fmt.Fprintln(stdout, undoUsage)
+				return undoRequest{}, 0, true
+			}
+			fmt.Fprintf(stderr, "Error parsing flags: %s\n", redactError(err))
+			fmt.Fprintln(stderr, undoUsage)
+			return undoRequest{}, 2, true
+		}
+		rest := fs.Args()
+		if len(rest) == 0 {
+			break
+		}
+		positional = append(positional, rest[0])
+		remaining = rest[1:]
+	}
+	if len(positional) != 1 {
+		if len(positional) == 0 {
+			fmt.Fprintln(stderr, "Error: Missing required argument")
+		} else {
+			fmt.Fprintf(stderr, "Error: Too many arguments: %v\n", positional[1:])
+		}
+		fmt.Fprintln(stderr, undoUsage)
+		return undoRequest{}, 1, true
+	}
+	return undoRequest{goalSlug: positional[0], skipConfirm: *yes || *yesShort, force: *force}, 0, false
+}
+
+// runUndoCommand finds the datapoint `buzz add` last created on the goal,
+// shows it, and deletes it once confirmed on stdin (or with skipConfirm), so
+// an undo can't take out a datapoint from the website or an integration by
+// mistake; force deletes the latest datapoint instead, whoever added it. It
+// returns the process exit code.
+func runUndoCommand(ctx context.Context, client Client, req undoRequest, stdin io.Reader, stdout, stderr io.Writer) int {
+	goalSlug := req.goalSlug
+
+	// Without --force, undo takes the datapoint `buzz add` last created, which
+	// needn't be the newest: `buzz add --date` can backdate it.
+	var target *Datapoint
+	which := "the datapoint buzz add last added to"
+	if req.force {
+		dps, err := client.FetchDatapoints(ctx, goalSlug, 1)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %s\n", redactError(err))
+			return 1
+		}
+		if len(dps) == 0 {
+			fmt.Fprintf(stdout, "No datapoints found for goal: %s\n", goalSlug)
+			return 0
+		}
+		target, which = &dps[0], "the latest datapoint on"
+	} else {
+		adds, err := loadLastAdds()
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: Failed to read the last adds: %s\n", err)
+			return 1
+		}
+		if adds[goalSlug] == "" {
+			fmt.Fprintf(stderr, "Error: buzz add hasn't added a datapoint to %s to undo; use --force to delete the latest one anyway\n", goalSlug)
+			return 1
+		}
+		if target, err = findDatapoint(ctx, client, goalSlug, adds[goalSlug]); err != nil {
+			fmt.Fprintf(stderr, "Error: %s\n", redactError(err))
+			return 1
+		}
+		if target == nil {
+			fmt.Fprintf(stderr, "Error: The datapoint buzz add last added to %s is gone; use --force to delete the latest one anyway\n", goalSlug)
+			return 1
+		}
+	}
+	shown := fmt.Sprintf("%s  %s  %q", datapointDate(*target), formatValue(target.Value), target.Comment)
+
+	if !req.skipConfirm {
+		fmt.Fprintf(stdout, "Delete %s %s, %s? [y/N] ", which, goalSlug, shown)
+		line, err := bufio.NewReader(stdin).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			fmt.Fprintln(stdout, "Cancelled.")
+			return 0
+		}
+		response := strings.TrimSpace(strings.ToLower(line))
+		if response != "y" && response != "yes" {
+			fmt.Fprintln(stdout, "Cancelled.")
+			return 0
+		}
+	}
+
+	if _, err := client.DeleteDatapoint(ctx, goalSlug, target.ID); err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to delete datapoint: %s\n", redactError(err))
+		return 1
+	}
+	fmt.Fprintf(stdout, "Deleted %s %s: %s\n", which, goalSlug, shown)
+
+	// The add is undone, so there's nothing left for another undo to take.
+	if err := setLastAdd(goalSlug, ""); err != nil {
+		fmt.Fprintf(stderr, "Warning: Could not update the last adds: %s\n", err)
+	}
+	if err := createRefreshFlag(); err != nil {
+		fmt.Fprintf(stderr, "Warning: Could not create refresh flag: %s\n", redactError(err))
+	}
+	return 0
+}
+
+// errFoundDatapoint stops findDatapoint's fetch once it has the datapoint.
+var errFoundDatapoint = errors.New("found the datapoint")
+
+// findDatapoint looks for the goal's datapoint id, newest first, fetching
+// only as far back as it has to. It returns nil when the goal has no such
+// datapoint.
+func findDatapoint(ctx context.Context, client Client, goalSlug, id string) (*Datapoint, error) {
+	var found *Datapoint
+	err := client.StreamDatapoints(ctx, goalSlug, func(dp Datapoint) error {
+		if dp.ID != id {
+			return nil
+		}
+		found = &dp
+		return errFoundDatapoint
+	})
+	if err != nil && !errors.Is(err, errFoundDatapoint) {
+		return nil, err
+	}
+	return found, nil
+}
+
+// lastUndoable returns the newest journal entry since since that can be
+// undone: a datapoint added or deleted, which wasn't itself an undo and isn't
+// in undone (by datapoint id). ok is false when there's none.
+func lastUndoable(entries []JournalEntry, since time.Time, undone map[string]bool) (entry JournalEntry, ok bool) {
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if e.Time.Before(since) {
+			break
+		}
+		if e.Undo || e.Datapoint == nil || e.Datapoint.ID == "" || undone[e.Datapoint.ID] {
+			continue
+		}
+		if e.Action == "add" || e.Action == "delete" {
+			return e, true
+		}
+	}
+	return JournalEntry{}, false
+}
+
+// undoJournalEntry reverses a change lastUndoable found: it deletes a datapoint
+// that was added, or adds back one that was deleted, at its original time.
+func undoJournalEntry(ctx context.Context, client Client, entry JournalEntry) error {
+	ctx = undoing(ctx)
+	dp := entry.Datapoint
+	if entry.Action == "add" {
+		_, err := client.DeleteDatapoint(ctx, entry.Goal, dp.ID)
+		return err
+	}
+	value := strconv.FormatFloat(dp.Value, 'f', -1, 64)
+	_, err := client.CreateDatapoint(ctx, entry.Goal, strconv.FormatInt(dp.Timestamp, 10), value, dp.Comment, "")
+	return err
+}
+
+// describeUndo says what undoing entry did, for the TUI's confirmation.
+func describeUndo(entry JournalEntry) string {
+	if entry.Action == "add" {
+		return fmt.Sprintf("Undid adding %s to %s", formatValue(entry.Datapoint.Value), entry.Goal)
+	}
+	return fmt.Sprintf("Restored %s to %s", formatValue(entry.Datapoint.Value), entry.Goal)
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestRunUndoCommand(t *testing.T) {
+	latest := Datapoint{ID: "new", Timestamp: 300, Daystamp: "20240115", Value: 50, Comment: "typo"}
+	// A `buzz add --date` backdates the add, so it isn't the newest.
+	backdated := Datapoint{ID: "old", Timestamp: 100, Daystamp: "20240110", Value: 7, Comment: "late"}
+	tests := []struct {
+		name             string
+		args             []string
+		lastAdd          string
+		stdin            string
+		wantDeleted      string
+		wantCode         int
+		wantOut, wantErr string
+	}{
+		{"nothing added", []string{"g"}, "", "y\n", "", 1, "", "hasn't added a datapoint to g to undo; use --force"},
+		{"add since deleted", []string{"g"}, "gone", "y\n", "", 1, "", "is gone; use --force"},
+		{"declined", []string{"g"}, "new", "n\n", "", 0, `Delete the datapoint buzz add last added to g, 2024-01-15  50  "typo"? [y/N] Cancelled.`, ""},
+		{"confirmed", []string{"g"}, "new", "y\n", "new", 0, "Deleted the datapoint buzz add last added to g", ""},
+		{"backdated add", []string{"g", "--yes"}, "old", "", "old", 0, `Deleted the datapoint buzz add last added to g: 2024-01-10  7  "late"`, ""},
+		{"--force and --yes", []string{"--force", "g", "-y"}, "old", "", "new", 0, "Deleted the latest datapoint on g", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setHome(t, t.TempDir())
+			if tt.lastAdd != "" {
+				if err := setLastAdd("g", tt.lastAdd); err != nil {
+					t.Fatal(err)
+				}
+			}
+			deleted := ""
+			client := &FakeClient{
+				FetchDatapointsFunc: func(string, int) ([]Datapoint, error) { return []Datapoint{latest}, nil },
+				FetchGoalWithDatapointsFunc: func(string) (*Goal, error) {
+					return &Goal{Datapoints: []Datapoint{backdated, latest}}, nil
+				},
+				DeleteDatapointFunc: func(slug, id string) (*Datapoint, error) {
+					deleted = id
+					return &latest, nil
+				},
+			}
+			var out, errb bytes.Buffer
+			req, code, done := parseUndoArgs(tt.args, &out, &errb)
+			if !done {
+				code = runUndoCommand(context.Background(), client, req, strings.NewReader(tt.stdin), &out, &errb)
+			}
+			checkResult(t, code, out.String(), errb.String(), tt.wantCode, tt.wantOut, tt.wantErr)
+			if deleted != tt.wantDeleted {
+				t.Errorf("deleted %q, want %q", deleted, tt.wantDeleted)
+			}
+			if adds, _ := loadLastAdds(); deleted != "" && adds["g"] != "" {
+				t.Errorf("the undone add is still remembered: %v", adds)
+			}
+		})
+	}
+}
+
+func TestParseUndoArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		want     undoRequest
+		wantCode int
+		wantDone bool
+		wantErr  string
+	}{
+		{"slug", []string{"g"}, undoRequest{goalSlug: "g"}, 0, false, ""},
+		{"flags either side", []string{"-y", "g", "--force"}, undoRequest{goalSlug: "g", skipConfirm: true, force: true}, 0, false, ""},
+		{"missing slug", nil, undoRequest{}, 1, true, "Error: Missing required argument\n" + undoUsage + "\n"},
+		{"too many", []string{"a", "b"}, undoRequest{}, 1, true, "Error: Too many arguments: [b]\n" + undoUsage + "\n"},
+		{"bad flag", []string{"--nope", "g"}, undoRequest{}, 2, true, "Error parsing flags: flag provided but not defined: -nope\n" + undoUsage + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errb bytes.Buffer
+			req, code, done := parseUndoArgs(tt.args, &out, &errb)
+			if req != tt.want || code != tt.wantCode || done != tt.wantDone || errb.String() != tt.wantErr {
+				t.Errorf("parseUndoArgs(%v) = %+v, %d, %v, stderr %q; want %+v, %d, %v, stderr %q",
+					tt.args, req, code, done, errb.String(), tt.want, tt.wantCode, tt.wantDone, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAddRemembersItsDatapoint(t *testing.T) {
+	setHome(t, t.TempDir())
+	client := &FakeClient{CreateDatapointWithDaystampFunc: func(slug, ts, day, value, comment, reqid string) (*Datapoint, error) {
+		return &Datapoint{ID: "dp1"}, nil
+	}}
+	var out, errb bytes.Buffer
+	if code := runAddCommand(addRequest{goalSlug: "g", value: "1"}, client, &out, &errb); code != 0 {
+		t.Fatalf("add failed: %s", errb.String())
+	}
+	if adds, err := loadLastAdds(); err != nil || adds["g"] != "dp1" {
+		t.Errorf("last adds = %v, %v, want g: dp1", adds, err)
+	}
+}
+
+func TestLastUndoable(t *testing.T) {
+	start := time.Date(2024, 1, 15, 9, 0, 0, 0, time.Local)
+	at := func(min int) time.Time { return start.Add(time.Duration(min) * time.Minute) }
+	entries := []JournalEntry{
+		{Time: at(-5), Action: "add", Goal: "old", Datapoint: &Datapoint{ID: "before"}},
+		{Time: at(1), Action: "add", Goal: "read", Datapoint: &Datapoint{ID: "a"}},
+		{Time: at(2), Action: "delete", Goal: "read", Datapoint: &Datapoint{ID: "b"}},
+		{Time: at(3), Action: "add", Goal: "read", Datapoint: &Datapoint{ID: "c"}},
+		{Time: at(4), Action: "delete", Goal: "read", Datapoint: &Datapoint{ID: "c"}, Undo: true},
+		{Time: at(5), Action: "charge", Detail: "$5.00"},
+	}
+
+	undone := map[string]bool{"c": true}
+	if e, ok := lastUndoable(entries, start, undone); !ok || e.Datapoint.ID != "b" {
+		t.Errorf("lastUndoable = %+v, %v; want the delete of b", e, ok)
+	}
+	undone["b"], undone["a"] = true, true
+	if e, ok := lastUndoable(entries, start, undone); ok {
+		t.Errorf("lastUndoable = %+v; want nothing since the TUI started", e)
+	}
+}
+
+func TestUndoJournalEntry(t *testing.T) {
+	var deleted, created string
+	client := &FakeClient{
+		DeleteDatapointFunc: func(slug, id string) (*Datapoint, error) {
+			deleted = slug + "/" + id
+			return &Datapoint{ID: id}, nil
+		},
+		CreateDatapointFunc: func(slug, timestamp, value, comment, _ string) (*Datapoint, error) {
+			created = strings.Join([]string{slug, timestamp, value, comment}, " ")
+			return &Datapoint{ID: "new"}, nil
+		},
+	}
+	ctx := context.Background()
+
+	add := JournalEntry{Action: "add", Goal: "read", Datapoint: &Datapoint{ID: "dp1", Value: 3}}
+	if err := undoJournalEntry(ctx, client, add); err != nil || deleted != "read/dp1" {
+		t.Errorf("undoing an add deleted %q, %v", deleted, err)
+	}
+	if got := describeUndo(add); got != "Undid adding 3 to read" {
+		t.Errorf("describeUndo = %q", got)
+	}
+
+	del := JournalEntry{Action: "delete", Goal: "read", Datapoint: &Datapoint{ID: "dp2", Timestamp: 1705312800, Value: 2.5, Comment: "ch 4"}}
+	if err := undoJournalEntry(ctx, client, del); err != nil || created != "read 1705312800 2.5 ch 4" {
+		t.Errorf("undoing a delete created %q, %v", created, err)
+	}
+	if got := describeUndo(del); got != "Restored 2.5 to read" {
+		t.Errorf("describeUndo = %q", got)
+	}
+}
+
+func TestTUIUndo(t *testing.T) {
+	setHome(t, t.TempDir())
+	var deleted string
+	client := journaling(&FakeClient{
+		CreateDatapointWithDaystampFunc: func(_, _, _, _, _, _ string) (*Datapoint, error) {
+			return &Datapoint{ID: "dp1", Value: 1}, nil
+		},
+		DeleteDatapointFunc: func(_, id string) (*Datapoint, error) {
+			deleted = id
+			return &Datapoint{ID: id, Value: 1}, nil
+		},
+	})
+	m := model{state: "app", appModel: appModel{
+		client:    client,
+		ctx:       context.Background(),
+		startedAt: time.Now().Add(-time.Minute),
+		undone:    map[string]bool{},
+	}}
+	undo := func(key string) {
+		t.Helper()
+		tm, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = mustModel(t, tm)
+		if cmd == nil {
+			t.Fatalf("%s returned no command", key)
+		}
+		msg, ok := cmd().(undoneMsg)
+		if !ok {
+			t.Fatalf("%s's command didn't undo", key)
+		}
+		m = mustModel(t, mustTeaModel(m.Update(msg)))
+	}
+
+	undo("U")
+	if got := m.appModel.activeToast(); got != "Nothing to undo" {
+		t.Errorf("toast = %q, want %q", got, "Nothing to undo")
+	}
+
+	client.CreateDatapointWithDaystamp(context.Background(), "read", "", "", "1", "", "")
+	undo("u")
+	if deleted != "dp1" || m.appModel.activeToast() != "Undid adding 1 to read" {
+		t.Errorf("deleted %q, toast %q; want the add undone", deleted, m.appModel.activeToast())
+	}
+
+	// The undo's own delete is journaled but isn't undone in turn.
+	deleted = ""
+	undo("U")
+	if deleted != "" || m.appModel.activeToast() != "Nothing to undo" {
+		t.Errorf("deleted %q, toast %q; want nothing left to undo", deleted, m.appModel.activeToast())
+	}
+}
+
+func TestRenameSlugInLastAdds(t *testing.T) {
+	setHome(t, t.TempDir())
+	if err := setLastAdd("run", "dp1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := setLastAdd("write", "dp2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := renameSlugInLastAdds("run", "jog"); err != nil {
+		t.Fatal(err)
+	}
+	adds, err := loadLastAdds()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"jog": "dp1", "write": "dp2"}; !reflect.DeepEqual(adds, want) {
+		t.Errorf("last adds = %v, want %v", adds, want)
+	}
+}
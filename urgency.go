@@ -108,3 +108,17 @@ func (u Urgency) HighlightedGridCellStyle() lipgloss.Style {
 		MarginRight(GridMarginRight).
 		MarginBottom(GridMarginBottom)
 }
+
+// ChangedGridCellStyle returns the cell style for a goal whose colour or delta
+// just changed on refresh: a double border in the urgency colour, the same
+// size as the other cell styles so the grid doesn't shift.
+func (u Urgency) ChangedGridCellStyle() lipgloss.Style {
+	return lipgloss.NewStyle().
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(u.Color()).
+		Foreground(u.Color()).
+		Bold(true).
+		Padding(PaddingVertical, PaddingHorizontal).
+		MarginRight(GridMarginRight).
+		MarginBottom(GridMarginBottom)
+}
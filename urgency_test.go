@@ -54,5 +54,9 @@ func TestUrgencyStyles(t *testing.T) {
 		if got := u.HighlightedGridCellStyle().GetForeground(); got != c {
 			t.Errorf("Urgency %v HighlightedGridCellStyle foreground = %v, want %v", u, got, c)
 		}
+		// ChangedGridCellStyle keeps the urgency colour for border and text.
+		if got := u.ChangedGridCellStyle().GetForeground(); got != c {
+			t.Errorf("Urgency %v ChangedGridCellStyle foreground = %v, want %v", u, got, c)
+		}
 	}
 }
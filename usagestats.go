@@ -0,0 +1,197 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Local usage statistics. buzz counts how often each command is run, and when
+// it last was, in ~/.buzz_usage, so `buzz stats --self` can show which
+// workflows get used. Nothing is ever sent anywhere: the file is plain JSON to
+// read, delete, or attach to a bug report by hand. Setting no_usage_stats in
+// ~/.buzzrc turns the counting off.
+
+// tuiUsageName is what launching the TUI is counted as.
+const tuiUsageName = "tui"
+
+// commandUsage is how often one command has been run, and when last.
+type commandUsage struct {
+	Count    int       `json:"count"`
+	LastUsed time.Time `json:"last_used"`
+}
+
+// getUsagePath returns the path to the local usage statistics file.
+func getUsagePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".buzz_usage"), nil
+}
+
+// loadCommandUsage loads the usage statistics, by command name. A missing
+// file is not an error; it returns an empty map.
+func loadCommandUsage() (map[string]commandUsage, error) {
+	path, err := getUsagePath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]commandUsage{}, nil
+		}
+		return nil, err
+	}
+	usage := map[string]commandUsage{}
+	if err := json.Unmarshal(data, &usage); err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+// uncountedCommands aren't counted: the shell runs completion on every Tab,
+// and help (which --help dispatches to) says nothing about a workflow.
+var uncountedCommands = []string{"completion", "help"}
+
+// pendingUsage is the run countCommandUsage couldn't count yet because the
+// config was encrypted and still locked; LoadConfig counts it once the command
+// has unlocked the config and no_usage_stats can be read.
+var pendingUsage *usageRow
+
+// countCommandUsage counts a run of the named command at now, unless it's one
+// of uncountedCommands or the config sets no_usage_stats. The config is read
+// without asking for a passphrase: while an encrypted one is locked it might
+// opt out, so the run is left pending until the command unlocks it, and a
+// command that never needs the credentials isn't counted. Failing to write the
+// counts is ignored: it mustn't stop the command.
+func countCommandUsage(name string, now time.Time) {
+	if slices.Contains(uncountedCommands, name) {
+		return
+	}
+	config, err := loadUnlockedConfig()
+	if errors.Is(err, errConfigLocked) {
+		pendingUsage = &usageRow{name, commandUsage{LastUsed: now}}
+		return
+	}
+	if err == nil && config.NoUsageStats {
+		return
+	}
+	_ = recordCommandUsage(name, now)
+}
+
+// countPendingUsage counts the run countCommandUsage left pending, now that
+// config has been unlocked, unless it sets no_usage_stats.
+func countPendingUsage(config *Config) {
+	pending := pendingUsage
+	if pending == nil {
+		return
+	}
+	pendingUsage = nil
+	if !config.NoUsageStats {
+		_ = recordCommandUsage(pending.Command, pending.LastUsed)
+	}
+}
+
+// recordCommandUsage counts a run of the named command at now.
+func recordCommandUsage(name string, now time.Time) error {
+	path, err := getUsagePath()
+	if err != nil {
+		return err
+	}
+	return updateFileAtomic(path, 0600, func(data []byte) ([]byte, error) {
+		usage := map[string]commandUsage{}
+		if data != nil && json.Unmarshal(data, &usage) != nil {
+			// An unreadable file only held counts; start it over.
+			usage = map[string]commandUsage{}
+		}
+		u := usage[name]
+		u.Count++
+		u.LastUsed = now
+		usage[name] = u
+		return json.MarshalIndent(usage, "", "  ")
+	})
+}
+
+// usageRow is one command's statistics, as `buzz stats --self` lists them.
+type usageRow struct {
+	Command string `json:"command"`
+	commandUsage
+}
+
+// sortedUsage lists the commands most-run first, breaking ties by name.
+func sortedUsage(usage map[string]commandUsage) []usageRow {
+	rows := make([]usageRow, 0, len(usage))
+	for name, u := range usage {
+		rows = append(rows, usageRow{name, u})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Count != rows[j].Count {
+			return rows[i].Count > rows[j].Count
+		}
+		return rows[i].Command < rows[j].Command
+	})
+	return rows
+}
+
+// runSelfStats prints the local usage statistics in the given format. It
+// returns the process exit code.
+func runSelfStats(format string, out, errOut io.Writer) int {
+	usage, err := loadCommandUsage()
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: Failed to read usage statistics: %s\n", err)
+		return 1
+	}
+	rows := sortedUsage(usage)
+
+	switch format {
+	case "jsonl":
+		err = writeJSONL(out, rows)
+	case "json":
+		var b []byte
+		if b, err = json.MarshalIndent(rows, "", "  "); err == nil {
+			fmt.Fprintln(out, string(b))
+		}
+	case "csv":
+		records := make([][]string, len(rows))
+		for i, r := range rows {
+			records[i] = []string{r.Command, strconv.Itoa(r.Count), r.LastUsed.Format(time.RFC3339)}
+		}
+		var rendered string
+		if rendered, err = encodeCSV([]string{"command", "count", "last_used"}, records); err == nil {
+			fmt.Fprint(out, rendered)
+		}
+	default:
+		if len(rows) == 0 {
+			fmt.Fprintln(out, "No commands recorded yet.")
+			return 0
+		}
+		cells := [][]string{{"Command", "Runs", "Last used"}}
+		for _, r := range rows {
+			cells = append(cells, []string{r.Command, strconv.Itoa(r.Count), formatDate(r.LastUsed.Local())})
+		}
+		widths := make([]int, 3)
+		for _, row := range cells {
+			for i, cell := range row {
+				widths[i] = max(widths[i], len(cell))
+			}
+		}
+		for _, row := range cells {
+			fmt.Fprintln(out, padRow(row, widths))
+		}
+		fmt.Fprintln(out, "\nCounted on this machine only; nothing is sent anywhere.")
+	}
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: %s\n", redactError(err))
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecordCommandUsage(t *testing.T) {
+	setHome(t, t.TempDir())
+	first := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	later := first.Add(time.Hour)
+	for _, run := range []struct {
+		name string
+		at   time.Time
+	}{{"add", first}, {"tui", first}, {"add", later}} {
+		if err := recordCommandUsage(run.name, run.at); err != nil {
+			t.Fatalf("recordCommandUsage(%s): %v", run.name, err)
+		}
+	}
+
+	usage, err := loadCommandUsage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := usage["add"]; got.Count != 2 || !got.LastUsed.Equal(later) {
+		t.Errorf("add = %+v, want 2 runs, last at %v", got, later)
+	}
+	rows := sortedUsage(usage)
+	if len(rows) != 2 || rows[0].Command != "add" || rows[1].Command != "tui" {
+		t.Errorf("sortedUsage = %+v", rows)
+	}
+}
+
+func TestCountCommandUsage(t *testing.T) {
+	dir := t.TempDir()
+	setHome(t, dir)
+	at := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	// Completion and help aren't counted.
+	for _, name := range []string{"completion", "help", "add"} {
+		countCommandUsage(name, at)
+	}
+	usage, _ := loadCommandUsage()
+	if len(usage) != 1 || usage["add"].Count != 1 {
+		t.Errorf("usage = %+v, want only add", usage)
+	}
+
+	// no_usage_stats turns counting off.
+	if err := SaveConfig(&Config{Username: "alice", AuthToken: "secret", NoUsageStats: true}); err != nil {
+		t.Fatal(err)
+	}
+	countCommandUsage("add", at)
+	if usage, _ := loadCommandUsage(); usage["add"].Count != 1 {
+		t.Errorf("add counted with no_usage_stats set: %+v", usage)
+	}
+
+	// A locked config might opt out, and reading it doesn't ask for the
+	// passphrase.
+	if err := SaveConfig(&Config{Username: "alice", AuthToken: "secret"}); err != nil {
+		t.Fatal(err)
+	}
+	useTestPassphrases(t, "hunter2", "hunter2")
+	if err := encryptConfigFile(filepath.Join(dir, ".buzzrc")); err != nil {
+		t.Fatal(err)
+	}
+	prompts := useTestPassphrases(t)
+	countCommandUsage("add", at)
+	if usage, _ := loadCommandUsage(); usage["add"].Count != 1 || len(*prompts) != 0 {
+		t.Errorf("usage = %+v, prompts = %q; want nothing counted or asked yet", usage, *prompts)
+	}
+
+	// Once the command unlocks the config, the run is counted.
+	useTestPassphrases(t, "hunter2")
+	if _, err := LoadConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if usage, _ := loadCommandUsage(); usage["add"].Count != 2 {
+		t.Errorf("usage = %+v, want add counted after the unlock", usage)
+	}
+	if _, err := LoadConfig(); err != nil {
+		t.Fatal(err)
+	}
+	if usage, _ := loadCommandUsage(); usage["add"].Count != 2 {
+		t.Errorf("usage = %+v, want the unlocked run counted once", usage)
+	}
+}
+
+func TestRunSelfStats(t *testing.T) {
+	setHome(t, t.TempDir())
+	var out, errOut bytes.Buffer
+	code := runSelfStats("table", &out, &errOut)
+	checkResult(t, code, out.String(), errOut.String(), 0, "No commands recorded yet.\n", "")
+
+	at := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	recordCommandUsage("add", at)
+	recordCommandUsage("add", at)
+	recordCommandUsage("stats", at)
+
+	out.Reset()
+	code = runSelfStats("table", &out, &errOut)
+	checkResult(t, code, out.String(), errOut.String(), 0,
+		"Command  Runs  Last used\nadd      2     2026-03-10\nstats    1     2026-03-10\n\nCounted on this machine only; nothing is sent anywhere.\n", "")
+
+	out.Reset()
+	code = runSelfStats("json", &out, &errOut)
+	var rows []map[string]any
+	if code != 0 || json.Unmarshal(out.Bytes(), &rows) != nil || len(rows) != 2 || rows[0]["command"] != "add" || rows[0]["count"] != 2.0 {
+		t.Errorf("json = %d, %q", code, out.String())
+	}
+
+	out.Reset()
+	code = runSelfStats("csv", &out, &errOut)
+	checkResult(t, code, out.String(), errOut.String(), 0, "command,count,last_used\nadd,2,"+at.Format(time.RFC3339)+"\n", "")
+}
@@ -2,10 +2,15 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
 	"regexp"
 	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
 )
 
 // Compile regex patterns once at package initialization for efficiency
@@ -63,15 +68,35 @@ func truncateString(s string, maxLen int) string {
 	return s[:maxLen-3] + "..."
 }
 
+// goalFirstLineWidth is the display width of a goal cell's first line.
+const goalFirstLineWidth = 16
+
+// secretMarker flags secret goals in the grid and `buzz view`. It is two
+// terminal cells wide.
+const secretMarker = "🔒"
+
 // formatGoalFirstLine formats the first line of a goal cell with slug and stakes
 // Format: "slug         $5" or "slug      $5/$10" (exactly 16 characters)
 func formatGoalFirstLine(slug string, pledge float64, pledgeCap *float64) string {
-	const width = 16
+	return fitGoalFirstLine(slug, pledge, pledgeCap, goalFirstLineWidth)
+}
 
+// formatMarkedGoalFirstLine is formatGoalFirstLine with badges (see
+// goalMarkers) before the slug, which is shortened to keep the line 16 cells
+// wide.
+func formatMarkedGoalFirstLine(markers, slug string, pledge float64, pledgeCap *float64) string {
+	if markers == "" {
+		return formatGoalFirstLine(slug, pledge, pledgeCap)
+	}
+	return markers + " " + fitGoalFirstLine(slug, pledge, pledgeCap, goalFirstLineWidth-lipgloss.Width(markers)-1)
+}
+
+// fitGoalFirstLine lays out slug and stakes in exactly width characters.
+func fitGoalFirstLine(slug string, pledge float64, pledgeCap *float64, width int) string {
 	// Format the pledge part (e.g., "$5", "$5/$10")
-	pledgeStr := fmt.Sprintf("$%.0f", pledge)
+	pledgeStr := formatMoney(pledge, 0)
 	if pledgeCap != nil && *pledgeCap > 0 && *pledgeCap != pledge {
-		pledgeStr = fmt.Sprintf("$%.0f/$%.0f", pledge, *pledgeCap)
+		pledgeStr = formatMoney(pledge, 0) + "/" + formatMoney(*pledgeCap, 0)
 	}
 
 	// Calculate space available for slug (need at least 1 space between slug and pledge)
@@ -174,21 +199,20 @@ func fuzzyMatch(pattern, text string) bool {
 		return true
 	}
 
-	// Convert to lowercase for case-insensitive matching
-	pattern = strings.ToLower(pattern)
-	text = strings.ToLower(text)
-
-	patternIdx := 0
+	// Compare rune by rune in lowercase rather than lowercasing both strings:
+	// filtering runs this over every goal on each keystroke, and this way it
+	// doesn't allocate.
 	for _, char := range text {
-		if patternIdx < len(pattern) && char == rune(pattern[patternIdx]) {
-			patternIdx++
-		}
-		if patternIdx == len(pattern) {
-			return true
+		want, size := utf8.DecodeRuneInString(pattern)
+		if unicode.ToLower(char) == unicode.ToLower(want) {
+			pattern = pattern[size:]
+			if pattern == "" {
+				return true
+			}
 		}
 	}
 
-	return patternIdx == len(pattern)
+	return false
 }
 
 // ensureRowVisible adjusts the scroll position to keep the selected row visible
@@ -234,11 +258,15 @@ func ensureRowVisible(selectedRow, firstRow, visibleRows, totalRows int) int {
 // updateScrollForCursor adjusts scrollRow to keep the cursor visible after navigation
 // This function should be called after cursor changes from arrow key navigation
 func updateScrollForCursor(m *model, displayLen int) {
-	layout := gridLayout(m.appModel.width, m.appModel.height, displayLen)
+	layout := gridLayout(m.appModel.layout().gridWidth, m.appModel.height, displayLen)
 	selRow := m.appModel.cursor / layout.cols
 	m.appModel.scrollRow = ensureRowVisible(selRow, m.appModel.scrollRow, layout.visibleRows, layout.totalRows)
 }
 
+// errStdinNotPiped is returned by readValueFromStdin when stdin is a
+// terminal rather than a pipe.
+var errStdinNotPiped = errors.New("stdin is not piped")
+
 // readValueFromStdin reads a value from stdin if it's being piped (non-interactive input)
 // Returns the trimmed value and nil on success, or empty string and error if stdin is not piped or read fails
 func readValueFromStdin() (string, error) {
@@ -250,7 +278,7 @@ func readValueFromStdin() (string, error) {
 
 	// Check if stdin is a character device (terminal) - if so, no piped input
 	if (stat.Mode() & os.ModeCharDevice) != 0 {
-		return "", fmt.Errorf("stdin is not piped")
+		return "", errStdinNotPiped
 	}
 
 	// Read the first line from stdin
@@ -274,7 +302,7 @@ func readValueFromStdin() (string, error) {
 // This is used to detect when users place flags after positional arguments
 // Returns the first detected flag string, or empty string if none found
 func detectMisplacedFlag(args []string) string {
-	knownFlags := []string{"--requestid", "--daystamp"}
+	knownFlags := []string{"--requestid", "--no-requestid", "--daystamp", "--date", "--timestamp", "--dry-run", "--dryrun", "--lb", "--kg"}
 	for _, arg := range args {
 		for _, flag := range knownFlags {
 			if strings.HasPrefix(arg, flag) {
@@ -284,3 +312,24 @@ func detectMisplacedFlag(args []string) string {
 	}
 	return ""
 }
+
+// goalSlugLabel returns the goal's slug, marked with secretMarker when the
+// goal is secret.
+func goalSlugLabel(goal *Goal) string {
+	if goal.Secret {
+		return goal.Slug + " " + secretMarker
+	}
+	return goal.Slug
+}
+
+// parseOnOff parses the on|off argument of the goal setting toggles
+// (case-insensitive).
+func parseOnOff(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "on":
+		return true, nil
+	case "off":
+		return false, nil
+	}
+	return false, fmt.Errorf("expected on or off, got %q", s)
+}
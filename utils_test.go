@@ -2,7 +2,10 @@ package main
 
 import (
 	"fmt"
+	"math"
 	"testing"
+
+	"github.com/charmbracelet/lipgloss"
 )
 
 // TestMin tests the min function
@@ -373,6 +376,40 @@ func TestTimeToDecimalHours(t *testing.T) {
 	}
 }
 
+func TestTimeToUnits(t *testing.T) {
+	tests := []struct {
+		input    string
+		gunits   string
+		expected float64
+		shouldOk bool
+	}{
+		{"1:30", "hours", 1.5, true},
+		{"1:30", "hrs", 1.5, true},
+		{"1:30", "minutes", 90, true},
+		{"0:05:30", "Mins", 5.5, true},
+		{"-0:15", "minutes", -15, true},
+		{"1:30", "", 1.5, true},
+		{"1:75", "minutes", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := timeToUnits(tt.input, tt.gunits)
+		if ok != tt.shouldOk || (ok && math.Abs(got-tt.expected) > 0.0001) {
+			t.Errorf("timeToUnits(%q, %q) = %v, %v; want %v, %v", tt.input, tt.gunits, got, ok, tt.expected, tt.shouldOk)
+		}
+	}
+}
+
+func TestIsTimeUnits(t *testing.T) {
+	for gunits, want := range map[string]bool{
+		"hours": true, "Hours": true, "hr": true, "minutes": true, "min": true,
+		"pages": false, "pushups": false, "": false, "h": false,
+	} {
+		if got := isTimeUnits(gunits); got != want {
+			t.Errorf("isTimeUnits(%q) = %v, want %v", gunits, got, want)
+		}
+	}
+}
+
 // TestReadValueFromStdin tests the readValueFromStdin function
 // Note: This test is limited because we can't easily mock os.Stdin in unit tests
 // The actual stdin piping behavior is tested via integration tests
@@ -605,3 +642,28 @@ func TestRedactError(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatMarkedGoalFirstLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		markers  string
+		slug     string
+		expected string
+	}{
+		{"no markers", "", "test", "test          $5"},
+		{"one marker", secretMarker, "test", secretMarker + " test       $5"},
+		{"one marker truncates", secretMarker, "a_very_long_slug", secretMarker + " a_very_... $5"},
+		{"two markers", autodataMarker + secretMarker, "test", autodataMarker + secretMarker + " test     $5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := formatMarkedGoalFirstLine(tt.markers, tt.slug, 5, nil)
+			if result != tt.expected {
+				t.Errorf("formatMarkedGoalFirstLine(%q, %q) = %q, want %q", tt.markers, tt.slug, result, tt.expected)
+			}
+			if w := lipgloss.Width(result); w != 16 {
+				t.Errorf("formatMarkedGoalFirstLine(%q, %q) width = %d, want 16", tt.markers, tt.slug, w)
+			}
+		})
+	}
+}
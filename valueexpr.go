@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// evalValueExpr evaluates a simple arithmetic expression such as "3*12+5" or
+// "(20+25)/60", so a value can be typed the way it was counted. It supports
+// + - * /, unary signs, parentheses, and decimal numbers.
+func evalValueExpr(s string) (float64, error) {
+	p := &exprParser{input: strings.ReplaceAll(s, " ", "")}
+	if p.input == "" {
+		return 0, fmt.Errorf("empty expression")
+	}
+	v, err := p.parseSum()
+	if err != nil {
+		return 0, err
+	}
+	if p.pos < len(p.input) {
+		return 0, fmt.Errorf("unexpected %q in expression", p.input[p.pos:])
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, fmt.Errorf("expression is not a finite number")
+	}
+	return v, nil
+}
+
+// exprParser is a recursive-descent parser over an expression with spaces
+// removed.
+type exprParser struct {
+	input string
+	pos   int
+}
+
+func (p *exprParser) peek() byte {
+	if p.pos < len(p.input) {
+		return p.input[p.pos]
+	}
+	return 0
+}
+
+// parseSum parses terms joined by + and -.
+func (p *exprParser) parseSum() (float64, error) {
+	v, err := p.parseProduct()
+	if err != nil {
+		return 0, err
+	}
+	for p.peek() == '+' || p.peek() == '-' {
+		op := p.peek()
+		p.pos++
+		rhs, err := p.parseProduct()
+		if err != nil {
+			return 0, err
+		}
+		if op == '+' {
+			v += rhs
+		} else {
+			v -= rhs
+		}
+	}
+	return v, nil
+}
+
+// parseProduct parses factors joined by * and /.
+func (p *exprParser) parseProduct() (float64, error) {
+	v, err := p.parseFactor()
+	if err != nil {
+		return 0, err
+	}
+	for p.peek() == '*' || p.peek() == '/' {
+		op := p.peek()
+		p.pos++
+		rhs, err := p.parseFactor()
+		if err != nil {
+			return 0, err
+		}
+		if op == '*' {
+			v *= rhs
+		} else {
+			if rhs == 0 {
+				return 0, fmt.Errorf("division by zero")
+			}
+			v /= rhs
+		}
+	}
+	return v, nil
+}
+
+// parseFactor parses a signed number or a parenthesized expression.
+func (p *exprParser) parseFactor() (float64, error) {
+	switch p.peek() {
+	case '+':
+		p.pos++
+		return p.parseFactor()
+	case '-':
+		p.pos++
+		v, err := p.parseFactor()
+		return -v, err
+	case '(':
+		p.pos++
+		v, err := p.parseSum()
+		if err != nil {
+			return 0, err
+		}
+		if p.peek() != ')' {
+			return 0, fmt.Errorf("missing closing parenthesis")
+		}
+		p.pos++
+		return v, nil
+	}
+
+	start := p.pos
+	for p.pos < len(p.input) && (p.input[p.pos] == '.' || (p.input[p.pos] >= '0' && p.input[p.pos] <= '9')) {
+		p.pos++
+	}
+	if start == p.pos {
+		if p.pos == len(p.input) {
+			return 0, fmt.Errorf("expression ends unexpectedly")
+		}
+		return 0, fmt.Errorf("unexpected %q in expression", p.input[p.pos:])
+	}
+	v, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q in expression", p.input[start:p.pos])
+	}
+	return v, nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import "testing"
+
+func TestEvalValueExpr(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    float64
+		wantErr bool
+	}{
+		{expr: "42", want: 42},
+		{expr: "1.5", want: 1.5},
+		{expr: "3*12+5", want: 41},
+		{expr: "3 * 12 + 5", want: 41},
+		{expr: "2+3*4", want: 14},
+		{expr: "(2+3)*4", want: 20},
+		{expr: "(20+25)/60", want: 0.75},
+		{expr: "10-4-3", want: 3},
+		{expr: "12/4/3", want: 1},
+		{expr: "-5+2", want: -3},
+		{expr: "-(2+3)", want: -5},
+		{expr: "+4", want: 4},
+		{expr: "", wantErr: true},
+		{expr: "abc", wantErr: true},
+		{expr: "1+", wantErr: true},
+		{expr: "(1+2", wantErr: true},
+		{expr: "1+2)", wantErr: true},
+		{expr: "1/0", wantErr: true},
+		{expr: "1..2", wantErr: true},
+		{expr: "2x3", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := evalValueExpr(tt.expr)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("evalValueExpr(%q) = %v, want error", tt.expr, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("evalValueExpr(%q) error: %v", tt.expr, err)
+			}
+			if got != tt.want {
+				t.Errorf("evalValueExpr(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
@@ -20,6 +20,7 @@ const (
 type Release struct {
 	TagName string `json:"tag_name"`
 	HTMLURL string `json:"html_url"`
+	Body    string `json:"body"`
 }
 
 // VersionCache stores the last update check
@@ -28,8 +29,24 @@ type VersionCache struct {
 	LatestVersion   string    `json:"latest_version"`
 	UpdateAvailable bool      `json:"update_available"`
 	CurrentVersion  string    `json:"current_version"`
+	LastNotified    time.Time `json:"last_notified,omitempty"` // when the update message was last shown (for weekly mode)
 }
 
+// Update-check modes, set via the update_check config field.
+const (
+	updateCheckDaily   = "daily"   // default: any command may show the message (GitHub is checked at most daily)
+	updateCheckWeekly  = "weekly"  // show the message at most once a week
+	updateCheckVersion = "version" // only show it in `buzz version`
+	updateCheckOff     = "off"     // never check
+)
+
+// weeklyNagInterval is how often the update message may be shown in weekly mode
+const weeklyNagInterval = 7 * 24 * time.Hour
+
+// noUpdateCheck is set by the global --no-update-check flag and overrides the
+// configured mode for this invocation.
+var noUpdateCheck bool
+
 // getVersionCachePath returns the path to the version cache file
 func getVersionCachePath() (string, error) {
 	homeDir, err := os.UserHomeDir()
@@ -74,7 +91,26 @@ func saveVersionCache(cache *VersionCache) error {
 		return err
 	}
 
-	return os.WriteFile(cachePath, data, 0600)
+	return writeFileAtomic(cachePath, data, 0600)
+}
+
+// updateVersionCache applies update to the version cache on disk (empty when
+// there's none yet) under the file's lock, so the weekly notice and the daily
+// check, run by different buzz processes, don't overwrite each other.
+func updateVersionCache(update func(*VersionCache)) error {
+	cachePath, err := getVersionCachePath()
+	if err != nil {
+		return err
+	}
+	return updateFileAtomic(cachePath, 0600, func(data []byte) ([]byte, error) {
+		var cache VersionCache
+		if data != nil && json.Unmarshal(data, &cache) != nil {
+			// An unreadable cache only saves a check; start it over.
+			cache = VersionCache{}
+		}
+		update(&cache)
+		return json.MarshalIndent(cache, "", "  ")
+	})
 }
 
 // fetchLatestVersion fetches the latest version from GitHub
@@ -199,14 +235,13 @@ func checkForUpdates() (bool, string, error) {
 	// Compare versions
 	updateAvailable := compareVersions(version, latestVersion)
 
-	// Save to cache
-	newCache := &VersionCache{
-		LastCheck:       time.Now(),
-		LatestVersion:   latestVersion,
-		UpdateAvailable: updateAvailable,
-		CurrentVersion:  version,
-	}
-	_ = saveVersionCache(newCache) // Ignore errors when saving cache
+	// Save to cache, keeping when the update message was last shown
+	_ = updateVersionCache(func(c *VersionCache) {
+		c.LastCheck = time.Now()
+		c.LatestVersion = latestVersion
+		c.UpdateAvailable = updateAvailable
+		c.CurrentVersion = version
+	}) // Ignore errors when saving cache
 
 	return updateAvailable, latestVersion, nil
 }
@@ -263,8 +298,59 @@ func getUpdateCommand(method InstallMethod) string {
 	}
 }
 
-// getUpdateMessage returns a message if an update is available
+// updateCheckMode returns the effective update-check mode: off when
+// --no-update-check was passed, otherwise the configured mode (daily when
+// unset, unrecognized, or when there is no config yet).
+func updateCheckMode() string {
+	if noUpdateCheck {
+		return updateCheckOff
+	}
+	// Not worth asking for the passphrase over: commands that need the
+	// credentials have unlocked an encrypted config by now.
+	config, err := loadUnlockedConfig()
+	if err != nil {
+		return updateCheckDaily
+	}
+	switch config.UpdateCheck {
+	case updateCheckWeekly, updateCheckVersion, updateCheckOff:
+		return config.UpdateCheck
+	default:
+		return updateCheckDaily
+	}
+}
+
+// getUpdateMessage returns a message if an update is available and the
+// update-check mode allows showing it after an ordinary command.
 func getUpdateMessage() string {
+	mode := updateCheckMode()
+	switch mode {
+	case updateCheckOff, updateCheckVersion:
+		return ""
+	case updateCheckWeekly:
+		if cache, err := loadVersionCache(); err == nil && cache != nil && time.Since(cache.LastNotified) < weeklyNagInterval {
+			return ""
+		}
+	}
+
+	msg := updateMessage()
+	if msg != "" && mode == updateCheckWeekly {
+		_ = updateVersionCache(func(c *VersionCache) { c.LastNotified = time.Now() }) // Ignore errors when saving cache
+	}
+	return msg
+}
+
+// getVersionUpdateMessage returns the update message for `buzz version`, which
+// shows it in every mode except off.
+func getVersionUpdateMessage() string {
+	if updateCheckMode() == updateCheckOff {
+		return ""
+	}
+	return updateMessage()
+}
+
+// updateMessage returns a message if an update is available, regardless of
+// the update-check mode.
+func updateMessage() string {
 	updateAvailable, latestVersion, err := checkForUpdates()
 	if err != nil {
 		// Silently ignore errors - don't disrupt user's workflow
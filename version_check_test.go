@@ -106,9 +106,7 @@ func TestVersionCacheSaveAndLoad(t *testing.T) {
 	defer os.RemoveAll(tmpDir)
 
 	// Override the cache path for testing
-	originalHomeDir := os.Getenv("HOME")
-	os.Setenv("HOME", tmpDir)
-	defer os.Setenv("HOME", originalHomeDir)
+	setHome(t, tmpDir)
 
 	// Create a test cache
 	testCache := &VersionCache{
@@ -165,9 +163,7 @@ func TestLoadVersionCacheNoFile(t *testing.T) {
 	defer os.RemoveAll(tmpDir)
 
 	// Override the cache path for testing
-	originalHomeDir := os.Getenv("HOME")
-	os.Setenv("HOME", tmpDir)
-	defer os.Setenv("HOME", originalHomeDir)
+	setHome(t, tmpDir)
 
 	// Try to load non-existent cache
 	cache, err := loadVersionCache()
@@ -189,9 +185,7 @@ func TestVersionCacheInvalidJSON(t *testing.T) {
 	defer os.RemoveAll(tmpDir)
 
 	// Override the cache path for testing
-	originalHomeDir := os.Getenv("HOME")
-	os.Setenv("HOME", tmpDir)
-	defer os.Setenv("HOME", originalHomeDir)
+	setHome(t, tmpDir)
 
 	// Write invalid JSON to cache file
 	cachePath := filepath.Join(tmpDir, ".buzz_version_cache")
@@ -216,9 +210,7 @@ func TestCheckForUpdatesWithFreshCache(t *testing.T) {
 	defer os.RemoveAll(tmpDir)
 
 	// Override the cache path for testing
-	originalHomeDir := os.Getenv("HOME")
-	os.Setenv("HOME", tmpDir)
-	defer os.Setenv("HOME", originalHomeDir)
+	setHome(t, tmpDir)
 
 	// Create a fresh cache
 	freshCache := &VersionCache{
@@ -257,9 +249,7 @@ func TestCheckForUpdatesWithStaleCache(t *testing.T) {
 	defer os.RemoveAll(tmpDir)
 
 	// Override the cache path for testing
-	originalHomeDir := os.Getenv("HOME")
-	os.Setenv("HOME", tmpDir)
-	defer os.Setenv("HOME", originalHomeDir)
+	setHome(t, tmpDir)
 
 	// Create a stale cache (more than 24 hours old)
 	staleCache := &VersionCache{
@@ -288,9 +278,7 @@ func TestGetUpdateMessageNoUpdate(t *testing.T) {
 	defer os.RemoveAll(tmpDir)
 
 	// Override the cache path for testing
-	originalHomeDir := os.Getenv("HOME")
-	os.Setenv("HOME", tmpDir)
-	defer os.Setenv("HOME", originalHomeDir)
+	setHome(t, tmpDir)
 
 	// Create a cache indicating no update
 	cache := &VersionCache{
@@ -321,9 +309,7 @@ func TestGetUpdateMessageWithUpdate(t *testing.T) {
 	defer os.RemoveAll(tmpDir)
 
 	// Override the cache path for testing
-	originalHomeDir := os.Getenv("HOME")
-	os.Setenv("HOME", tmpDir)
-	defer os.Setenv("HOME", originalHomeDir)
+	setHome(t, tmpDir)
 
 	// Create a cache indicating update is available
 	cache := &VersionCache{
@@ -391,9 +377,7 @@ func TestCheckForUpdatesAfterVersionUpgrade(t *testing.T) {
 	defer os.RemoveAll(tmpDir)
 
 	// Override the cache path for testing
-	originalHomeDir := os.Getenv("HOME")
-	os.Setenv("HOME", tmpDir)
-	defer os.Setenv("HOME", originalHomeDir)
+	setHome(t, tmpDir)
 
 	// Simulate cache created when user was on v0.32.0
 	// and update to v0.33.0 was available
@@ -476,9 +460,7 @@ func TestGetUpdateMessage(t *testing.T) {
 	defer os.RemoveAll(tmpDir)
 
 	// Override the cache path for testing
-	originalHomeDir := os.Getenv("HOME")
-	os.Setenv("HOME", tmpDir)
-	defer os.Setenv("HOME", originalHomeDir)
+	setHome(t, tmpDir)
 
 	// Create a cache indicating update is available
 	cache := &VersionCache{
@@ -591,3 +573,81 @@ func TestDetectInstallMethodFromPath(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateCheckModes(t *testing.T) {
+	// setup writes a config with the given update_check mode and a fresh cache
+	// reporting an available update, so no test here touches the network.
+	setup := func(t *testing.T, mode string) {
+		t.Helper()
+		setHome(t, t.TempDir())
+		if err := SaveConfig(&Config{Username: "u", AuthToken: "t", UpdateCheck: mode}); err != nil {
+			t.Fatalf("SaveConfig: %v", err)
+		}
+		if err := saveVersionCache(&VersionCache{
+			LastCheck:       time.Now(),
+			LatestVersion:   "v0.31.0",
+			UpdateAvailable: true,
+			CurrentVersion:  version,
+		}); err != nil {
+			t.Fatalf("saveVersionCache: %v", err)
+		}
+	}
+
+	t.Run("default shows the message every time", func(t *testing.T) {
+		setup(t, "")
+		if getUpdateMessage() == "" || getUpdateMessage() == "" {
+			t.Error("expected the update message on every call")
+		}
+	})
+
+	t.Run("off never shows it", func(t *testing.T) {
+		setup(t, updateCheckOff)
+		if msg := getUpdateMessage(); msg != "" {
+			t.Errorf("getUpdateMessage() = %q, want empty", msg)
+		}
+		if msg := getVersionUpdateMessage(); msg != "" {
+			t.Errorf("getVersionUpdateMessage() = %q, want empty", msg)
+		}
+	})
+
+	t.Run("version only shows it in buzz version", func(t *testing.T) {
+		setup(t, updateCheckVersion)
+		if msg := getUpdateMessage(); msg != "" {
+			t.Errorf("getUpdateMessage() = %q, want empty", msg)
+		}
+		if getVersionUpdateMessage() == "" {
+			t.Error("getVersionUpdateMessage() should still report the update")
+		}
+	})
+
+	t.Run("weekly shows it once per week", func(t *testing.T) {
+		setup(t, updateCheckWeekly)
+		if getUpdateMessage() == "" {
+			t.Fatal("first call should show the update message")
+		}
+		if msg := getUpdateMessage(); msg != "" {
+			t.Errorf("second call = %q, want empty within the week", msg)
+		}
+
+		cache, err := loadVersionCache()
+		if err != nil || cache == nil {
+			t.Fatalf("loadVersionCache: %v", err)
+		}
+		cache.LastNotified = time.Now().Add(-8 * 24 * time.Hour)
+		if err := saveVersionCache(cache); err != nil {
+			t.Fatalf("saveVersionCache: %v", err)
+		}
+		if getUpdateMessage() == "" {
+			t.Error("message should show again after a week")
+		}
+	})
+
+	t.Run("--no-update-check overrides the config", func(t *testing.T) {
+		setup(t, "")
+		noUpdateCheck = true
+		defer func() { noUpdateCheck = false }()
+		if msg := getUpdateMessage(); msg != "" {
+			t.Errorf("getUpdateMessage() = %q, want empty", msg)
+		}
+	})
+}
@@ -35,10 +35,11 @@ func handleViewCommand() {
 	// of being silently dropped.
 	viewFlags := flag.NewFlagSet("view", flag.ContinueOnError)
 	web := viewFlags.Bool("web", false, "Open the goal in the browser")
+	qrCode := viewFlags.Bool("qr", false, "Print the goal's URL as a QR code instead (with --web)")
 	jsonOutput := viewFlags.Bool("json", false, "Output goal data as JSON")
 	datapoints := viewFlags.Bool("datapoints", false, "Include datapoints in output (use with --json)")
 
-	const usage = "Usage: buzz view <goalslug> [--web] [--json] [--datapoints]"
+	const usage = "Usage: buzz view <goalslug> [--web [--qr]] [--json] [--datapoints]"
 	var positional []string
 	remaining := os.Args[2:]
 	for len(remaining) > 0 {
@@ -77,23 +78,14 @@ func handleViewCommand() {
 
 	goalSlug := positional[0]
 
-	// Load config
-	if !ConfigExists() {
-		fmt.Fprintln(os.Stderr, "Error: No configuration found. Please run 'buzz auth login' to authenticate.")
+	config, client, ok := loadConfigAndClient(os.Stderr)
+	if !ok {
 		os.Exit(1)
 	}
 
-	config, err := LoadConfig()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: Failed to load config: %s\n", redactError(err))
-		os.Exit(1)
-	}
-
-	client := NewHTTPClient(config)
-
 	// If --web flag is present, open in browser and exit
-	if webFlag {
-		if err := openBrowser(config, goalSlug); err != nil {
+	if webFlag || *qrCode {
+		if err := openBrowser(config, goalSlug, *qrCode, os.Stdout); err != nil {
 			fmt.Fprintf(os.Stderr, "Error: Failed to open browser: %s\n", redactError(err))
 			os.Exit(1)
 		}
@@ -131,7 +123,7 @@ func handleViewCommand() {
 	}
 
 	// Display goal information (human-readable format)
-	fmt.Printf("Goal: %s\n", goal.Slug)
+	fmt.Printf("Goal: %s\n", goalSlugLabel(goal))
 	fmt.Print(formatGoalDetails(goal, config, time.Now()))
 
 	// Progress chart, matching `buzz review`. Empty when the goal has no
@@ -0,0 +1,335 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// `buzz watch` is a non-interactive, full-screen wallboard for a spare monitor
+// or a Raspberry Pi: it rotates through a few pages (goals due today, the most
+// urgent goal's chart, yesterday's datapoints) on a timer and refetches at the
+// start of every rotation. The only key it handles is quit.
+
+const watchUsage = `Usage: buzz watch [--pages today,chart,activity] [--interval 30s]
+  --pages     Pages to rotate through, in order (default: today,chart,activity)
+  --interval  How long each page is shown (default: 30s, minimum: 5s)
+Press q or Ctrl+C to exit.`
+
+// watchPages are the available wallboard pages, in their default order.
+var watchPages = []string{"today", "chart", "activity"}
+
+const (
+	defaultWatchInterval = 30 * time.Second
+	// minWatchInterval keeps a typo like --interval 1ms from hammering the
+	// API, since data is refetched every rotation.
+	minWatchInterval = 5 * time.Second
+)
+
+// watchRequest is a parsed, validated `buzz watch` invocation.
+type watchRequest struct {
+	pages    []string
+	interval time.Duration
+}
+
+// handleWatchCommand runs the wallboard until the user quits.
+func handleWatchCommand() {
+	req, code, done := parseWatchArgs(os.Args[2:], os.Stdout, os.Stderr)
+	if done {
+		os.Exit(code)
+	}
+
+	client, ok := loadClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+
+	// Cancelled when the program exits so an in-flight fetch doesn't outlive it.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p := tea.NewProgram(newWatchModel(ctx, client, req), tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", redactError(err))
+		os.Exit(1)
+	}
+}
+
+// parseWatchArgs parses and validates `buzz watch` arguments, returning the
+// request, a process exit code, and done=true when the caller should stop
+// (help shown, or a parse/validation error).
+func parseWatchArgs(args []string, stdout, stderr io.Writer) (watchRequest, int, bool) {
+	watchFlags := flag.NewFlagSet("watch", flag.ContinueOnError)
+	// Silence the flag package's own output; we print our own usage.
+	watchFlags.SetOutput(io.Discard)
+	pages := watchFlags.String("pages", strings.Join(watchPages, ","), "Pages to rotate through")
+	interval := watchFlags.Duration("interval", defaultWatchInterval, "How long each page is shown")
+	if err := watchFlags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stdout, watchUsage)
+			return watchRequest{}, 0, true
+		}
+		fmt.Fprintf(stderr, "Error parsing flags: %s\n", redactError(err))
+		fmt.Fprintln(stderr, watchUsage)
+		return watchRequest{}, 2, true
+	}
+	if watchFlags.NArg() > 0 {
+		fmt.Fprintf(stderr, "Error: unexpected argument %q\n", watchFlags.Arg(0))
+		fmt.Fprintln(stderr, watchUsage)
+		return watchRequest{}, 1, true
+	}
+
+	if *interval < minWatchInterval {
+		fmt.Fprintf(stderr, "Error: --interval must be at least %s\n", minWatchInterval)
+		return watchRequest{}, 1, true
+	}
+
+	var selected []string
+	for _, page := range strings.Split(*pages, ",") {
+		page = strings.ToLower(strings.TrimSpace(page))
+		if page == "" {
+			continue
+		}
+		if !slices.Contains(watchPages, page) {
+			fmt.Fprintf(stderr, "Error: unknown page %q (want %s)\n", page, strings.Join(watchPages, ", "))
+			return watchRequest{}, 1, true
+		}
+		selected = append(selected, page)
+	}
+	if len(selected) == 0 {
+		fmt.Fprintln(stderr, "Error: --pages needs at least one page")
+		return watchRequest{}, 1, true
+	}
+
+	return watchRequest{pages: selected, interval: *interval}, 0, false
+}
+
+// watchModel is the wallboard's Bubble Tea model.
+type watchModel struct {
+	ctx      context.Context
+	client   Client
+	pages    []string
+	interval time.Duration
+	page     int // index into pages
+
+	goals     []Goal // active goals (after --filter), most urgent first
+	chartGoal *Goal  // the most urgent goal with its datapoints, for the chart page
+	activity  []Goal // goals carrying only yesterday's datapoints
+	loaded    bool   // at least one fetch has succeeded
+	err       error  // the latest fetch error, shown until a fetch succeeds
+
+	width  int
+	height int
+}
+
+func newWatchModel(ctx context.Context, client Client, req watchRequest) watchModel {
+	return watchModel{
+		ctx:      ctx,
+		client:   client,
+		pages:    req.pages,
+		interval: req.interval,
+	}
+}
+
+// watchDataMsg carries the result of a wallboard refetch.
+type watchDataMsg struct {
+	goals     []Goal
+	chartGoal *Goal
+	activity  []Goal
+	err       error
+}
+
+// watchTickMsg advances the wallboard to its next page.
+type watchTickMsg struct{}
+
+func watchTickCmd(interval time.Duration) tea.Cmd {
+	return tea.Tick(interval, func(time.Time) tea.Msg { return watchTickMsg{} })
+}
+
+// loadWatchDataCmd fetches what the selected pages need: the goal list
+// always, the most urgent goal's datapoints for the chart page, and
+// yesterday's datapoints for the activity page.
+func loadWatchDataCmd(ctx context.Context, client Client, pages []string) tea.Cmd {
+	return func() tea.Msg {
+		now := time.Now()
+		goals, err := client.FetchGoals(ctx)
+		if err != nil {
+			return watchDataMsg{err: err}
+		}
+		goals = keepGoals(goals, goalFilter)
+		SortGoals(goals)
+		msg := watchDataMsg{goals: goals}
+
+		if slices.Contains(pages, "chart") && len(goals) > 0 {
+			goal, err := client.FetchGoalWithDatapoints(ctx, goals[0].Slug)
+			if err != nil {
+				return watchDataMsg{err: err}
+			}
+			goal.prepareChart()
+			msg.chartGoal = goal
+		}
+
+		if slices.Contains(pages, "activity") {
+			// Two days back covers yesterday for any goal deadline offset.
+			recent, err := client.FetchDatapointsSince(ctx, now.Add(-48*time.Hour))
+			if err != nil {
+				return watchDataMsg{err: err}
+			}
+			msg.activity = yesterdaysActivity(keepGoals(recent, goalFilter), now)
+		}
+		return msg
+	}
+}
+
+// yesterdaysActivity keeps, for each goal, only the datapoints on the goal's
+// previous Beeminder day, dropping goals with none. Goals are sorted by slug.
+func yesterdaysActivity(goals []Goal, now time.Time) []Goal {
+	var activity []Goal
+	for _, g := range goals {
+		yesterday := yesterdayDaystampFor(g, now)
+		var dps []Datapoint
+		for _, dp := range g.Datapoints {
+			if dp.Daystamp == yesterday {
+				dps = append(dps, dp)
+			}
+		}
+		if len(dps) > 0 {
+			g.Datapoints = dps
+			activity = append(activity, g)
+		}
+	}
+	sort.Slice(activity, func(i, j int) bool { return activity[i].Slug < activity[j].Slug })
+	return activity
+}
+
+func (m watchModel) Init() tea.Cmd {
+	return tea.Batch(loadWatchDataCmd(m.ctx, m.client, m.pages), watchTickCmd(m.interval))
+}
+
+func (m watchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		}
+		return m, nil
+
+	case watchDataMsg:
+		// A failed refetch keeps showing the last good data with the error
+		// underneath; the next rotation retries.
+		m.err = msg.err
+		if msg.err == nil {
+			m.goals = msg.goals
+			m.chartGoal = msg.chartGoal
+			m.activity = msg.activity
+			m.loaded = true
+		}
+		return m, nil
+
+	case watchTickMsg:
+		m.page = (m.page + 1) % len(m.pages)
+		if m.page == 0 {
+			return m, tea.Batch(loadWatchDataCmd(m.ctx, m.client, m.pages), watchTickCmd(m.interval))
+		}
+		return m, watchTickCmd(m.interval)
+	}
+	return m, nil
+}
+
+func (m watchModel) View() string {
+	now := time.Now()
+	page := m.pages[m.page]
+
+	var title, body string
+	switch {
+	case !m.loaded && m.err == nil:
+		title, body = "buzz", "Loading goals..."
+	case !m.loaded:
+		title, body = "buzz", ""
+	default:
+		title, body = m.renderPage(page, now)
+	}
+
+	header := lipgloss.NewStyle().Bold(true).Render(title) + "  " + now.Format("Mon Jan 2 "+clockLayout())
+
+	// Page dots: ● for the current page, ○ for the rest.
+	dots := make([]string, len(m.pages))
+	for i := range m.pages {
+		dots[i] = "○"
+		if i == m.page {
+			dots[i] = "●"
+		}
+	}
+	footer := strings.Join(dots, " ") + "  q to quit"
+	if m.err != nil {
+		footer = UrgencyOverdue.TextStyle().Render(fmt.Sprintf("Refresh failed: %s", redactError(m.err))) + "\n" + footer
+	}
+
+	// Clip the body so the footer stays on screen; a wallboard can't scroll.
+	if m.height > 0 {
+		room := max(1, m.height-lipgloss.Height(header)-lipgloss.Height(footer)-2)
+		if lines := strings.Split(body, "\n"); len(lines) > room {
+			body = strings.Join(lines[:room], "\n")
+		}
+	}
+
+	return header + "\n\n" + strings.TrimRight(body, "\n") + "\n\n" + footer
+}
+
+// renderPage returns the title and body of one wallboard page.
+func (m watchModel) renderPage(page string, now time.Time) (title, body string) {
+	switch page {
+	case "chart":
+		if m.chartGoal == nil {
+			return "Most urgent goal", "No goals."
+		}
+		g := *m.chartGoal
+		summary := UrgencyFor(g.Safebuf).TextStyle().Render(fmt.Sprintf("%s  %s  due %s", g.Slug, g.Baremin, FormatDueDateAt(g.Losedate, now)))
+		chart := renderGoalChart(g, max(m.width, 40))
+		if chart == "" {
+			chart = "\nNo datapoints to chart yet.\n"
+		}
+		return "Most urgent goal", summary + "\n" + chart
+
+	case "activity":
+		if len(m.activity) == 0 {
+			return "Yesterday", "No datapoints yesterday."
+		}
+		var b strings.Builder
+		for _, g := range m.activity {
+			for _, dp := range g.Datapoints {
+				fmt.Fprintf(&b, "%-20s %10.6g  %s\n", g.Slug, dp.Value, dp.Comment)
+			}
+		}
+		return "Yesterday", b.String()
+
+	default: // "today"
+		var today []Goal
+		for _, g := range m.goals {
+			if isDueTodayFilterAt(g, now) {
+				today = append(today, g)
+			}
+		}
+		if len(today) == 0 {
+			return "Due today", "Nothing due today."
+		}
+		table := dueTable(func(g Goal) string { return g.Baremin }, func(g Goal) int64 { return g.Losedate })
+		return "Due today", table.Render(today)
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestParseWatchArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		wantDone bool
+		want     watchRequest
+	}{
+		{"defaults", nil, 0, false, watchRequest{pages: watchPages, interval: defaultWatchInterval}},
+		{"custom", []string{"--pages", "chart, today", "--interval", "1m"}, 0, false, watchRequest{pages: []string{"chart", "today"}, interval: time.Minute}},
+		{"help", []string{"-h"}, 0, true, watchRequest{}},
+		{"bad interval", []string{"--interval", "soon"}, 2, true, watchRequest{}},
+		{"interval too short", []string{"--interval", "1s"}, 1, true, watchRequest{}},
+		{"unknown page", []string{"--pages", "today,weather"}, 1, true, watchRequest{}},
+		{"no pages", []string{"--pages", ","}, 1, true, watchRequest{}},
+		{"stray argument", []string{"today"}, 1, true, watchRequest{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, code, done := parseWatchArgs(tt.args, &bytes.Buffer{}, &bytes.Buffer{})
+			if code != tt.wantCode || done != tt.wantDone || !reflect.DeepEqual(req, tt.want) {
+				t.Errorf("got (%+v, %d, %v), want (%+v, %d, %v)", req, code, done, tt.want, tt.wantCode, tt.wantDone)
+			}
+		})
+	}
+}
+
+func TestYesterdaysActivity(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+	goals := []Goal{
+		{Slug: "write", Datapoints: []Datapoint{{Daystamp: "20260309", Value: 500}, {Daystamp: "20260310", Value: 200}}},
+		{Slug: "idle", Datapoints: []Datapoint{{Daystamp: "20260310", Value: 1}}},
+		{Slug: "run", Datapoints: []Datapoint{{Daystamp: "20260309", Value: 5}}},
+	}
+	got := yesterdaysActivity(goals, now)
+	if len(got) != 2 || got[0].Slug != "run" || got[1].Slug != "write" {
+		t.Fatalf("yesterdaysActivity() = %+v, want run and write", got)
+	}
+	if len(got[1].Datapoints) != 1 || got[1].Datapoints[0].Value != 500 {
+		t.Errorf("write datapoints = %+v, want only yesterday's", got[1].Datapoints)
+	}
+}
+
+func TestWatchModel(t *testing.T) {
+	now := time.Now()
+	client := &FakeClient{
+		FetchGoalsFunc: func() ([]Goal, error) {
+			return []Goal{
+				{Slug: "later", Safebuf: 9, Losedate: now.Add(9 * 24 * time.Hour).Unix()},
+				{Slug: "urgent", Safebuf: 0, Baremin: "+1", Losedate: now.Add(time.Hour).Unix()},
+			}, nil
+		},
+		FetchGoalWithDatapointsFunc: func(slug string) (*Goal, error) { return &Goal{Slug: slug}, nil },
+		FetchDatapointsSinceFunc:    func(time.Time) ([]Goal, error) { return nil, nil },
+	}
+	m := newWatchModel(context.Background(), client, watchRequest{pages: watchPages, interval: time.Minute})
+
+	msg := loadWatchDataCmd(m.ctx, m.client, m.pages)()
+	data, ok := msg.(watchDataMsg)
+	if !ok || data.err != nil {
+		t.Fatalf("loadWatchDataCmd() = %#v", msg)
+	}
+	if data.chartGoal == nil || data.chartGoal.Slug != "urgent" {
+		t.Errorf("chartGoal = %+v, want the most urgent goal", data.chartGoal)
+	}
+
+	updated, _ := m.Update(data)
+	m = updated.(watchModel)
+	if view := m.View(); !strings.Contains(view, "Due today") || !strings.Contains(view, "urgent") || strings.Contains(view, "later") {
+		t.Errorf("today page:\n%s", view)
+	}
+
+	// Each tick advances a page; wrapping back to the first page refetches.
+	updated, _ = m.Update(watchTickMsg{})
+	m = updated.(watchModel)
+	if view := m.View(); !strings.Contains(view, "Most urgent goal") {
+		t.Errorf("chart page:\n%s", view)
+	}
+	updated, _ = m.Update(watchTickMsg{})
+	m = updated.(watchModel)
+	if view := m.View(); !strings.Contains(view, "No datapoints yesterday") {
+		t.Errorf("activity page:\n%s", view)
+	}
+	updated, _ = m.Update(watchTickMsg{})
+	m = updated.(watchModel)
+	if m.page != 0 {
+		t.Errorf("page = %d after a full rotation, want 0", m.page)
+	}
+
+	// A failed refetch keeps the last good data and shows the error.
+	updated, _ = m.Update(watchDataMsg{err: errFakeNotConfigured})
+	m = updated.(watchModel)
+	if view := m.View(); !strings.Contains(view, "urgent") || !strings.Contains(view, "Refresh failed") {
+		t.Errorf("after failed refetch:\n%s", view)
+	}
+
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")}); cmd == nil {
+		t.Error("q should quit")
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+const weekendsOffUsage = `Usage: buzz weekends-off <goalslug> on|off
+       buzz weekends-off --all on|off
+  --all  Apply to every do-more goal`
+
+// weekendsOffRequest is a parsed, validated `buzz weekends-off` invocation.
+type weekendsOffRequest struct {
+	goalSlug string // empty with all
+	all      bool
+	on       bool
+}
+
+// handleWeekendsOffCommand turns a goal's automatic weekend breaks on or off,
+// or every do-more goal's with --all.
+func handleWeekendsOffCommand() {
+	req, code, done := parseWeekendsOffArgs(os.Args[2:], os.Stdout, os.Stderr)
+	if done {
+		os.Exit(code)
+	}
+
+	client, ok := loadClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+
+	code = runWeekendsOffCommand(req, client, os.Stdout, os.Stderr)
+	if code == 0 {
+		fmt.Print(getUpdateMessage())
+	}
+	os.Exit(code)
+}
+
+// parseWeekendsOffArgs parses and validates `buzz weekends-off` arguments,
+// returning the request, a process exit code, and done=true when the caller
+// should stop (help shown, or a parse/validation error).
+func parseWeekendsOffArgs(args []string, stdout, stderr io.Writer) (weekendsOffRequest, int, bool) {
+	weekendsOffFlags := flag.NewFlagSet("weekends-off", flag.ContinueOnError)
+	// Silence the flag package's own output; we print our own usage.
+	weekendsOffFlags.SetOutput(io.Discard)
+	all := weekendsOffFlags.Bool("all", false, "Apply to every do-more goal")
+	if err := weekendsOffFlags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stdout, weekendsOffUsage)
+			return weekendsOffRequest{}, 0, true
+		}
+		fmt.Fprintf(stderr, "Error parsing flags: %s\n", redactError(err))
+		fmt.Fprintln(stderr, weekendsOffUsage)
+		return weekendsOffRequest{}, 2, true
+	}
+
+	rest := weekendsOffFlags.Args()
+	want := 2
+	if *all {
+		want = 1
+	}
+	if len(rest) != want {
+		if len(rest) < want {
+			fmt.Fprintln(stderr, "Error: Missing required arguments")
+		} else {
+			fmt.Fprintln(stderr, "Error: Too many arguments")
+		}
+		fmt.Fprintln(stderr, weekendsOffUsage)
+		return weekendsOffRequest{}, 1, true
+	}
+
+	req := weekendsOffRequest{all: *all}
+	if !*all {
+		req.goalSlug = rest[0]
+	}
+	on, err := parseOnOff(rest[len(rest)-1])
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %s\n", err)
+		return weekendsOffRequest{}, 1, true
+	}
+	req.on = on
+	return req, 0, false
+}
+
+// runWeekendsOffCommand applies the change and returns the process exit code.
+// With --all, do-more goals already in the requested state are skipped and a
+// failure on one goal doesn't stop the rest.
+func runWeekendsOffCommand(req weekendsOffRequest, client Client, stdout, stderr io.Writer) int {
+	state := "off"
+	if req.on {
+		state = "on"
+	}
+
+	if !req.all {
+		goal, err := client.UpdateGoalWeekendsOff(context.Background(), req.goalSlug, req.on)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: Failed to update weekends off: %s\n", redactError(err))
+			return 1
+		}
+		fmt.Fprintf(stdout, "Turned weekends off %s for %s\n", state, goal.Slug)
+		return 0
+	}
+
+	goals, err := client.FetchGoals(context.Background())
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: Failed to fetch goals: %s\n", redactError(err))
+		return 1
+	}
+
+	updated, failed := 0, 0
+	for _, g := range goals {
+		if !IsDoMoreGoal(g) || g.WeekendsOff == req.on {
+			continue
+		}
+		if _, err := client.UpdateGoalWeekendsOff(context.Background(), g.Slug, req.on); err != nil {
+			fmt.Fprintf(stderr, "Error: Failed to update weekends off for %s: %s\n", g.Slug, redactError(err))
+			failed++
+			continue
+		}
+		fmt.Fprintf(stdout, "Turned weekends off %s for %s\n", state, g.Slug)
+		updated++
+	}
+
+	if updated == 0 && failed == 0 {
+		fmt.Fprintf(stdout, "Weekends off is already %s for every do-more goal\n", state)
+	}
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
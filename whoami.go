@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const whoamiUsage = "Usage: buzz whoami"
+
+// handleWhoamiCommand prints the account the auth token belongs to. Its exit
+// code makes it a quick check, in scripts, that buzz is logged in.
+func handleWhoamiCommand() {
+	code, done := parseWhoamiArgs(os.Args[2:], os.Stdout, os.Stderr)
+	if done {
+		os.Exit(code)
+	}
+
+	client, ok := loadClient(os.Stderr)
+	if !ok {
+		os.Exit(1)
+	}
+
+	code = runWhoamiCommand(context.Background(), client, outputFormat, os.Stdout, os.Stderr)
+	if code == 0 && outputFormat == "table" {
+		fmt.Print(getUpdateMessage())
+	}
+	os.Exit(code)
+}
+
+// parseWhoamiArgs parses `buzz whoami` arguments, of which there are none,
+// returning a process exit code and done=true when the caller should stop
+// (help shown, or a usage error).
+func parseWhoamiArgs(args []string, stdout, stderr io.Writer) (int, bool) {
+	whoamiFlags := flag.NewFlagSet("whoami", flag.ContinueOnError)
+	// Silence the flag package's own output; we print our own usage.
+	whoamiFlags.SetOutput(io.Discard)
+	if err := whoamiFlags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(stdout, whoamiUsage)
+			return 0, true
+		}
+		fmt.Fprintf(stderr, "Error parsing flags: %s\n", err)
+		fmt.Fprintln(stderr, whoamiUsage)
+		return 2, true
+	}
+	if whoamiFlags.NArg() > 0 {
+		fmt.Fprintf(stderr, "Error: unexpected argument %q\n", whoamiFlags.Arg(0))
+		fmt.Fprintln(stderr, whoamiUsage)
+		return 1, true
+	}
+	return 0, false
+}
+
+// whoami is what `buzz whoami` prints about the account.
+type whoami struct {
+	Username    string `json:"username"`
+	Timezone    string `json:"timezone"`
+	UrgencyLoad int    `json:"urgency_load"`
+	Plan        string `json:"plan"`
+	Goals       int    `json:"goals"`
+}
+
+// newWhoami summarizes user. Beeminder leaves the subscription out on the
+// free plan.
+func newWhoami(user User) whoami {
+	plan := user.Subscription
+	if plan == "" {
+		plan = "free"
+	}
+	return whoami{
+		Username:    user.Username,
+		Timezone:    user.Timezone,
+		UrgencyLoad: user.UrgencyLoad,
+		Plan:        plan,
+		Goals:       len(user.Goals),
+	}
+}
+
+// runWhoamiCommand fetches the user and prints who they are in the given
+// format. It returns the process exit code.
+func runWhoamiCommand(ctx context.Context, client Client, format string, out, errOut io.Writer) int {
+	user, err := client.FetchUser(ctx)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: Failed to fetch user: %s\n", redactError(err))
+		return 1
+	}
+	w := newWhoami(*user)
+
+	switch format {
+	case "jsonl":
+		err = writeJSONL(out, []whoami{w})
+	case "json":
+		var b []byte
+		if b, err = json.MarshalIndent(w, "", "  "); err == nil {
+			fmt.Fprintln(out, string(b))
+		}
+	case "csv":
+		row := []string{w.Username, w.Timezone, strconv.Itoa(w.UrgencyLoad), w.Plan, strconv.Itoa(w.Goals)}
+		var rendered string
+		if rendered, err = encodeCSV([]string{"username", "timezone", "urgency_load", "plan", "goals"}, [][]string{row}); err == nil {
+			fmt.Fprint(out, rendered)
+		}
+	default:
+		timezone := w.Timezone
+		if timezone == "" {
+			timezone = "(not set)"
+		}
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "Username:      %s\n", w.Username)
+		fmt.Fprintf(&sb, "Timezone:      %s\n", timezone)
+		fmt.Fprintf(&sb, "Urgency load:  %d\n", w.UrgencyLoad)
+		fmt.Fprintf(&sb, "Plan:          %s\n", w.Plan)
+		fmt.Fprintf(&sb, "Goals:         %d\n", w.Goals)
+		if user.Deadbeat {
+			sb.WriteString("\nBeeminder has a failed payment on file for this account.\n")
+		}
+		fmt.Fprint(out, sb.String())
+	}
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: %s\n", redactError(err))
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestParseWhoamiArgs(t *testing.T) {
+	for _, tt := range []struct {
+		args     []string
+		wantCode int
+		wantDone bool
+	}{
+		{[]string{}, 0, false},
+		{[]string{"alice"}, 1, true},
+		{[]string{"--yes"}, 2, true},
+		{[]string{"--help"}, 0, true},
+	} {
+		code, done := parseWhoamiArgs(tt.args, &bytes.Buffer{}, &bytes.Buffer{})
+		if code != tt.wantCode || done != tt.wantDone {
+			t.Errorf("parseWhoamiArgs(%v) = %d, %v", tt.args, code, done)
+		}
+	}
+}
+
+func TestRunWhoamiCommand(t *testing.T) {
+	user := &User{Username: "alice", Timezone: "America/New_York", UrgencyLoad: 12, Subscription: "infinibee", Goals: []string{"reading", "writing"}}
+	client := &FakeClient{FetchUserFunc: func() (*User, error) { return user, nil }}
+	run := func(format string) (int, string, string) {
+		var out, errOut bytes.Buffer
+		code := runWhoamiCommand(context.Background(), client, format, &out, &errOut)
+		return code, out.String(), errOut.String()
+	}
+
+	code, out, errOut := run("table")
+	checkResult(t, code, out, errOut, 0, "Username:      alice\nTimezone:      America/New_York\nUrgency load:  12\nPlan:          infinibee\nGoals:         2\n", "")
+
+	code, out, errOut = run("csv")
+	checkResult(t, code, out, errOut, 0, "username,timezone,urgency_load,plan,goals\nalice,America/New_York,12,infinibee,2\n", "")
+
+	user = &User{Username: "bob", Deadbeat: true}
+	code, out, errOut = run("json")
+	checkResult(t, code, out, errOut, 0, `"plan": "free"`, "")
+	code, out, errOut = run("table")
+	checkResult(t, code, out, errOut, 0, "Timezone:      (not set)\n", "")
+	checkResult(t, code, out, errOut, 0, "failed payment", "")
+
+	client.FetchUserFunc = nil
+	code, out, errOut = run("table")
+	checkResult(t, code, out, errOut, 1, "", "Error: Failed to fetch user: FakeClient method not configured for this test\n")
+}